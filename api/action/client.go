@@ -49,6 +49,61 @@ func (c *Client) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	return results, err
 }
 
+// EnqueueOperation queues up the given Actions as a single operation,
+// returning the operation's id and the result of queueing each Action.
+// An Action whose receiver is a service is queued on each of its units.
+func (c *Client) EnqueueOperation(arg params.Actions) (params.OperationResult, error) {
+	result := params.OperationResult{}
+	err := c.facade.FacadeCall("EnqueueOperation", arg, &result)
+	return result, err
+}
+
+// Operations returns the Actions queued as part of each of the given
+// operations.
+func (c *Client) Operations(arg params.Operations) (params.OperationResults, error) {
+	results := params.OperationResults{}
+	err := c.facade.FacadeCall("Operations", arg, &results)
+	return results, err
+}
+
+// SetActionLimits sets the maximum number of units of each given
+// service that may run the given action at once.
+func (c *Client) SetActionLimits(arg params.ActionLimits) (params.ErrorResults, error) {
+	results := params.ErrorResults{}
+	err := c.facade.FacadeCall("SetActionLimits", arg, &results)
+	return results, err
+}
+
+// FilterActions returns the Actions in the environment's action history
+// that match the given filter, most recently enqueued first.
+func (c *Client) FilterActions(arg params.ActionFilter) (params.ActionResults, error) {
+	results := params.ActionResults{}
+	err := c.facade.FacadeCall("FilterActions", arg, &results)
+	return results, err
+}
+
+// AddSchedules adds schedules on which the given Actions are enqueued
+// repeatedly.
+func (c *Client) AddSchedules(arg params.ActionSchedules) (params.ActionScheduleResults, error) {
+	results := params.ActionScheduleResults{}
+	err := c.facade.FacadeCall("AddSchedules", arg, &results)
+	return results, err
+}
+
+// Schedules returns all of the environment's action schedules.
+func (c *Client) Schedules() (params.ActionScheduleResults, error) {
+	results := params.ActionScheduleResults{}
+	err := c.facade.FacadeCall("Schedules", nil, &results)
+	return results, err
+}
+
+// RemoveSchedules removes the action schedules with the given ids.
+func (c *Client) RemoveSchedules(arg params.ActionScheduleIds) (params.ErrorResults, error) {
+	results := params.ErrorResults{}
+	err := c.facade.FacadeCall("RemoveSchedules", arg, &results)
+	return results, err
+}
+
 // ListAll takes a list of Entities representing ActionReceivers and returns
 // all of the Actions that have been queued or run by each of those
 // Entities.
@@ -76,8 +131,10 @@ func (c *Client) ListCompleted(arg params.Entities) (params.ActionsByReceivers,
 	return results, err
 }
 
-// Cancel attempts to cancel a queued up Action from running.
-func (c *Client) Cancel(arg params.Actions) (params.ActionResults, error) {
+// Cancel cancels the given Actions. Actions which have not yet started
+// are cancelled immediately; running Actions are stopped by the unit
+// running them.
+func (c *Client) Cancel(arg params.Entities) (params.ActionResults, error) {
 	results := params.ActionResults{}
 	err := c.facade.FacadeCall("Cancel", arg, &results)
 	return results, err
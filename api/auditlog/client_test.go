@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/auditlog"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type auditLogSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&auditLogSuite{})
+
+func (s *auditLogSuite) TestList(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "AuditLog")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "List")
+			c.Check(a, jc.DeepEquals, params.AuditEventFilter{
+				User:  "user-bob",
+				Limit: 5,
+			})
+			result, ok := response.(*params.AuditEventResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Events = []params.AuditEvent{{
+				User:   "user-bob",
+				Facade: "Client",
+				Method: "FullStatus",
+			}}
+			return nil
+		})
+	client := auditlog.NewClient(apiCaller)
+	events, err := client.List(params.AuditEventFilter{User: "user-bob", Limit: 5})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(events, jc.DeepEquals, []params.AuditEvent{{
+		User:   "user-bob",
+		Facade: "Client",
+		Method: "FullStatus",
+	}})
+}
+
+func (s *auditLogSuite) TestListError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := auditlog.NewClient(apiCaller)
+	_, err := client.List(params.AuditEventFilter{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -10,10 +10,20 @@ import (
 )
 
 // Create sends a request to create a backup of juju's state.  It
-// returns the metadata associated with the resulting backup.
-func (c *Client) Create(notes string) (*params.BackupsMetadataResult, error) {
+// returns the metadata associated with the resulting backup. If
+// encryptionKey is not empty, it must hold the OpenPGP public key with
+// which the backup archive is encrypted. The contents of the databases
+// and collections in exclude are left out of the backup, as are those
+// in the environment's backup-exclude setting unless they are in
+// include.
+func (c *Client) Create(notes string, encryptionKey []byte, exclude, include []string) (*params.BackupsMetadataResult, error) {
 	var result params.BackupsMetadataResult
-	args := params.BackupsCreateArgs{Notes: notes}
+	args := params.BackupsCreateArgs{
+		Notes:         notes,
+		EncryptionKey: encryptionKey,
+		Exclude:       exclude,
+		Include:       include,
+	}
 	if err := c.facade.FacadeCall("Create", args, &result); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -27,6 +27,9 @@ func (s *createSuite) TestCreate(c *gc.C) {
 			c.Assert(paramsIn, gc.FitsTypeOf, params.BackupsCreateArgs{})
 			p := paramsIn.(params.BackupsCreateArgs)
 			c.Check(p.Notes, gc.Equals, "important")
+			c.Check(string(p.EncryptionKey), gc.Equals, "<public key>")
+			c.Check(p.Exclude, jc.DeepEquals, []string{"logs"})
+			c.Check(p.Include, jc.DeepEquals, []string{"juju.statuseshistory"})
 
 			if result, ok := resp.(*params.BackupsMetadataResult); ok {
 				*result = apiserverbackups.ResultFromMetadata(s.Meta)
@@ -39,7 +42,7 @@ func (s *createSuite) TestCreate(c *gc.C) {
 	)
 	defer cleanup()
 
-	result, err := s.client.Create("important")
+	result, err := s.client.Create("important", []byte("<public key>"), []string{"logs"}, []string{"juju.statuseshistory"})
 	c.Assert(err, jc.ErrorIsNil)
 
 	meta := backupstesting.UpdateNotes(s.Meta, "important")
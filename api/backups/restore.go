@@ -61,8 +61,10 @@ func prepareRestore(newClient ClientConnection) error {
 	return errors.Annotatef(err, "could not start restore process: %v", remoteError)
 }
 
-// RestoreReader restores the contents of backupFile as backup.
-func (c *Client) RestoreReader(r io.ReadSeeker, meta *params.BackupsMetadataResult, newClient ClientConnection) error {
+// RestoreReader restores the contents of backupFile as backup. If the
+// backup is encrypted, decryptionKey must hold the OpenPGP private key
+// with which it is decrypted.
+func (c *Client) RestoreReader(r io.ReadSeeker, meta *params.BackupsMetadataResult, decryptionKey []byte, newClient ClientConnection) error {
 	if err := prepareRestore(newClient); err != nil {
 		return errors.Trace(err)
 	}
@@ -75,16 +77,19 @@ func (c *Client) RestoreReader(r io.ReadSeeker, meta *params.BackupsMetadataResu
 		logger.Errorf("could not exit restoring status: %v", finishErr)
 		return errors.Annotatef(err, "cannot upload backup file")
 	}
-	return c.restore(backupId, newClient)
+	return c.restore(backupId, decryptionKey, time.Time{}, newClient)
 }
 
 // Restore performs restore using a backup id corresponding to a backup stored in the server.
-func (c *Client) Restore(backupId string, newClient ClientConnection) error {
+// If the backup is encrypted, decryptionKey must hold the OpenPGP private key with which
+// it is decrypted. If recoverTo is not zero, the database operations captured after the
+// backup was made are replayed up to that time.
+func (c *Client) Restore(backupId string, decryptionKey []byte, recoverTo time.Time, newClient ClientConnection) error {
 	if err := prepareRestore(newClient); err != nil {
 		return errors.Trace(err)
 	}
 	logger.Debugf("Server in 'about to restore' mode")
-	return c.restore(backupId, newClient)
+	return c.restore(backupId, decryptionKey, recoverTo, newClient)
 }
 
 func restoreAttempt(client *Client, closer closerFunc, restoreArgs params.RestoreArgs) (error, error) {
@@ -100,12 +105,14 @@ func restoreAttempt(client *Client, closer closerFunc, restoreArgs params.Restor
 // It takes backupId as the identifier for the remote backup file and a
 // client connection factory newClient (newClient should no longer be
 // necessary when lp:1399722 is sorted out).
-func (c *Client) restore(backupId string, newClient ClientConnection) error {
+func (c *Client) restore(backupId string, decryptionKey []byte, recoverTo time.Time, newClient ClientConnection) error {
 	var err, remoteError error
 
 	// Restore
 	restoreArgs := params.RestoreArgs{
-		BackupId: backupId,
+		BackupId:      backupId,
+		DecryptionKey: decryptionKey,
+		RecoverTo:     recoverTo,
 	}
 
 	for a := restoreStrategy.Start(); a.Next(); {
@@ -424,12 +424,22 @@ func (c *Client) EnvironmentUUID() string {
 
 // ShareEnvironment allows the given users access to the environment.
 func (c *Client) ShareEnvironment(users ...names.UserTag) error {
+	return c.ShareEnvironmentWithAccess("", users...)
+}
+
+// ShareEnvironmentWithAccess allows the given users access to the
+// environment at the given access level. Users the environment is
+// already shared with have their access level changed. If access is
+// empty, new users are given write access and existing users are left
+// unchanged.
+func (c *Client) ShareEnvironmentWithAccess(access params.EnvironmentAccess, users ...names.UserTag) error {
 	var args params.ModifyEnvironUsers
 	for _, user := range users {
 		if &user != nil {
 			args.Changes = append(args.Changes, params.ModifyEnvironUser{
 				UserTag: user.String(),
 				Action:  params.AddEnvUser,
+				Access:  access,
 			})
 		}
 	}
@@ -820,6 +830,53 @@ func (c *Client) AgentVersion() (version.Number, error) {
 	return result.Version, nil
 }
 
+// ExportBundle returns the YAML for a bundle describing the current
+// environment.
+func (c *Client) ExportBundle() (string, error) {
+	var result params.StringResult
+	if err := c.facade.FacadeCall("ExportBundle", nil, &result); err != nil {
+		return "", errors.Trace(err)
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
+// ListPayloads returns the payloads registered by the units in the
+// environment. If patterns are given, only payloads whose class or one
+// of whose labels matches a pattern are returned.
+func (c *Client) ListPayloads(patterns ...string) ([]params.Payload, error) {
+	args := params.PayloadListArgs{Patterns: patterns}
+	var result params.PayloadListResults
+	if err := c.facade.FacadeCall("ListPayloads", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Results, nil
+}
+
+// UploadResource uploads the data read from r as a new revision of the
+// named resource of the service, returning the resource as recorded by
+// the API server. The service's units will run the upgrade-charm hook
+// to make use of it.
+func (c *Client) UploadResource(service, name string, r io.ReadSeeker) (params.Resource, error) {
+	httpClient, err := c.st.HTTPClient()
+	if err != nil {
+		return params.Resource{}, errors.Trace(err)
+	}
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/services/%s/resources/%s", service, name), nil)
+	if err != nil {
+		return params.Resource{}, errors.Annotate(err, "cannot create upload request")
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	var resp params.Resource
+	if err := httpClient.Do(req, r, &resp); err != nil {
+		return params.Resource{}, errors.Trace(err)
+	}
+	return resp, nil
+}
+
 // websocketDialConfig is called instead of websocket.DialConfig so we can
 // override it in tests.
 var websocketDialConfig = func(config *websocket.Config) (base.Stream, error) {
@@ -874,6 +931,22 @@ type DebugLogParams struct {
 	// Replay tells the server to start at the start of the log file rather
 	// than the end. If replay is true, backlog is ignored.
 	Replay bool
+	// MessageRegex restricts the response to log messages matching this
+	// regular expression.
+	MessageRegex string
+	// StartTime restricts the response to log messages written at or
+	// after this time.
+	StartTime time.Time
+	// EndTime restricts the response to log messages written before this
+	// time. Once all such messages have been sent, the socket is closed.
+	EndTime time.Time
+	// Location restricts the response to log messages written from this
+	// "file:line" location, or from any line in this file if no line
+	// number is given.
+	Location string
+	// JSON tells the server to send each log message as a JSON-encoded
+	// params.LogMessage on a line of its own, rather than as text.
+	JSON bool
 }
 
 // WatchDebugLog returns a ReadCloser that the caller can read the log
@@ -911,6 +984,21 @@ func (c *Client) WatchDebugLog(args DebugLogParams) (io.ReadCloser, error) {
 	if args.Level != loggo.UNSPECIFIED {
 		attrs.Set("level", fmt.Sprint(args.Level))
 	}
+	if args.MessageRegex != "" {
+		attrs.Set("messageRegex", args.MessageRegex)
+	}
+	if !args.StartTime.IsZero() {
+		attrs.Set("startTime", args.StartTime.UTC().Format(time.RFC3339))
+	}
+	if !args.EndTime.IsZero() {
+		attrs.Set("endTime", args.EndTime.UTC().Format(time.RFC3339))
+	}
+	if args.Location != "" {
+		attrs.Set("location", args.Location)
+	}
+	if args.JSON {
+		attrs.Set("format", "json")
+	}
 
 	connection, err := c.st.ConnectStream("/log", attrs)
 	if err != nil {
@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/httprequest"
@@ -117,6 +118,23 @@ func (s *clientSuite) TestAddLocalCharm(c *gc.C) {
 	c.Assert(savedURL.String(), gc.Equals, curl.WithRevision(43).String())
 }
 
+func (s *clientSuite) TestUploadResource(c *gc.C) {
+	s.AddTestingService(c, "resources", s.AddTestingCharm(c, "resources"))
+	client := s.APIState.Client()
+
+	res, err := client.UploadResource("resources", "software", strings.NewReader("hello"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Service, gc.Equals, "resources")
+	c.Assert(res.Name, gc.Equals, "software")
+	c.Assert(res.Path, gc.Equals, "software.tgz")
+	c.Assert(res.Revision, gc.Equals, 1)
+	c.Assert(res.Size, gc.Equals, int64(5))
+
+	_, err = client.UploadResource("resources", "unknown", strings.NewReader("hello"))
+	c.Assert(err, gc.ErrorMatches, `resource "unknown" in charm "local:quantal/resources-1" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
 func (s *clientSuite) TestAddLocalCharmOtherEnvironment(c *gc.C) {
 	charmArchive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
 	curl := charm.MustParseURL(
@@ -314,6 +332,34 @@ func (s *clientSuite) TestShareEnvironmentThreeUsers(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `existing user`)
 }
 
+func (s *clientSuite) TestShareEnvironmentWithAccess(c *gc.C) {
+	client := s.APIState.Client()
+	userTag := names.NewUserTag("foo@bar")
+	var called bool
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, paramsIn interface{}, response interface{}) error {
+			called = true
+			c.Assert(request, gc.Equals, "ShareEnvironment")
+			c.Assert(paramsIn, jc.DeepEquals, params.ModifyEnvironUsers{
+				Changes: []params.ModifyEnvironUser{{
+					UserTag: userTag.String(),
+					Action:  params.AddEnvUser,
+					Access:  params.EnvironmentReadAccess,
+				}},
+			})
+			*(response.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := client.ShareEnvironmentWithAccess(params.EnvironmentReadAccess, userTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
 func (s *clientSuite) TestUnshareEnvironmentThreeUsers(c *gc.C) {
 	client := s.APIState.Client()
 	missingUser := s.Factory.MakeEnvUser(c, nil)
@@ -465,6 +511,32 @@ func (s *clientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 	})
 }
 
+func (s *clientSuite) TestWatchDebugLogFilterParamsEncoded(c *gc.C) {
+	s.PatchValue(api.WebsocketDialConfig, echoURL(c))
+
+	params := api.DebugLogParams{
+		MessageRegex: "^hello",
+		StartTime:    time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC),
+		EndTime:      time.Date(2015, 10, 1, 13, 0, 0, 0, time.UTC),
+		Location:     "code.go:42",
+		JSON:         true,
+	}
+
+	client := s.APIState.Client()
+	reader, err := client.WatchDebugLog(params)
+	c.Assert(err, jc.ErrorIsNil)
+
+	connectURL := connectURLFromReader(c, reader)
+	values := connectURL.Query()
+	c.Assert(values, jc.DeepEquals, url.Values{
+		"messageRegex": {"^hello"},
+		"startTime":    {"2015-10-01T12:00:00Z"},
+		"endTime":      {"2015-10-01T13:00:00Z"},
+		"location":     {"code.go:42"},
+		"format":       {"json"},
+	})
+}
+
 func (s *clientSuite) TestConnectStreamRootPath(c *gc.C) {
 	s.PatchValue(api.WebsocketDialConfig, echoURL(c))
 
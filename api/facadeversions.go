@@ -17,6 +17,7 @@ var facadeVersions = map[string]int{
 	"AllWatcher":                   0,
 	"AllEnvWatcher":                1,
 	"Annotations":                  1,
+	"AuditLog":                     1,
 	"Backups":                      0,
 	"Block":                        1,
 	"Charms":                       1,
@@ -51,7 +52,7 @@ var facadeVersions = map[string]int{
 	"RelationUnitsWatcher":         0,
 	"Resumer":                      1,
 	"Rsyslog":                      0,
-	"Service":                      1,
+	"Service":                      2,
 	"Storage":                      1,
 	"Spaces":                       1,
 	"Subnets":                      1,
@@ -60,7 +61,7 @@ var facadeVersions = map[string]int{
 	"StringsWatcher":               0,
 	"SystemManager":                1,
 	"Upgrader":                     0,
-	"Uniter":                       2,
+	"Uniter":                       3,
 	"UserManager":                  0,
 	"VolumeAttachmentsWatcher":     1,
 }
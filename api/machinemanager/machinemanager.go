@@ -5,6 +5,7 @@ package machinemanager
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
@@ -36,3 +37,32 @@ func (client *Client) AddMachines(machineParams []params.AddMachineParams) ([]pa
 	}
 	return results.Machines, err
 }
+
+// UpgradeSeriesPrepare starts upgrading the given machine to the given
+// series.
+func (client *Client) UpgradeSeriesPrepare(machine, series string) error {
+	args := params.UpgradeSeriesPrepareArgs{
+		Args: []params.UpgradeSeriesPrepareArg{{
+			Entity: params.Entity{Tag: names.NewMachineTag(machine).String()},
+			Series: series,
+		}},
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("UpgradeSeriesPrepare", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// UpgradeSeriesComplete records that the operating system of the given
+// machine has been upgraded.
+func (client *Client) UpgradeSeriesComplete(machine string) error {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewMachineTag(machine).String()}},
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("UpgradeSeriesComplete", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
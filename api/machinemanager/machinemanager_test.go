@@ -116,3 +116,40 @@ func (s *MachinemanagerSuite) TestAddMachinesResultCountInvalid(c *gc.C) {
 		c.Check(err, gc.ErrorMatches, fmt.Sprintf("expected 1 result, got %d", n))
 	}
 }
+
+func (s *MachinemanagerSuite) TestUpgradeSeriesPrepare(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(request, gc.Equals, "UpgradeSeriesPrepare")
+		c.Check(arg, gc.DeepEquals, params.UpgradeSeriesPrepareArgs{
+			Args: []params.UpgradeSeriesPrepareArg{{
+				Entity: params.Entity{Tag: "machine-1"},
+				Series: "xenial",
+			}},
+		})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		return nil
+	})
+	st := machinemanager.NewClient(apiCaller)
+	err := st.UpgradeSeriesPrepare("1", "xenial")
+	c.Check(err, gc.ErrorMatches, "boom")
+}
+
+func (s *MachinemanagerSuite) TestUpgradeSeriesComplete(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(request, gc.Equals, "UpgradeSeriesComplete")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-1"}},
+		})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		return nil
+	})
+	st := machinemanager.NewClient(apiCaller)
+	err := st.UpgradeSeriesComplete("1")
+	c.Check(err, jc.ErrorIsNil)
+}
@@ -108,3 +108,38 @@ func (m *Machine) Jobs() (*params.JobsResult, error) {
 	}
 	return &result, nil
 }
+
+// UpgradeSeriesStatus returns the status of the machine's series upgrade,
+// and the series the machine is being upgraded to.
+func (m *Machine) UpgradeSeriesStatus() (params.UpgradeSeriesStatus, string, error) {
+	var results params.UpgradeSeriesStatusResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: m.tag.String()}},
+	}
+	err := m.st.facade.FacadeCall("UpgradeSeriesStatus", args, &results)
+	if err != nil {
+		return "", "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", "", result.Error
+	}
+	return result.Status, result.ToSeries, nil
+}
+
+// StartUnitUpgradeSeriesCompletion records that the machine's agent
+// services have been updated for the series it is being upgraded to.
+func (m *Machine) StartUnitUpgradeSeriesCompletion() error {
+	var result params.ErrorResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: m.tag.String()}},
+	}
+	err := m.st.facade.FacadeCall("StartUnitUpgradeSeriesCompletion", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
@@ -204,3 +204,28 @@ func (s *machinerSuite) TestWatch(c *gc.C) {
 	statetesting.AssertStop(c, w)
 	wc.AssertClosed()
 }
+
+func (s *machinerSuite) TestUpgradeSeries(c *gc.C) {
+	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, series, err := machine.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, params.UpgradeSeriesNotStarted)
+	c.Assert(series, gc.Equals, "")
+
+	err = s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.CompleteUpgradeSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	status, series, err = machine.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, params.UpgradeSeriesCompleteStarted)
+	c.Assert(series, gc.Equals, "trusty")
+
+	err = machine.StartUnitUpgradeSeriesCompletion()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Series(), gc.Equals, "trusty")
+}
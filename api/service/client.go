@@ -63,7 +63,8 @@ func (c *Client) EnvironmentUUID() string {
 // requested networks that must be present on the machines where the
 // service is deployed. Another way to specify networks to include/exclude
 // is using constraints. Placement directives, if provided, specify the
-// machine on which the charm is deployed.
+// machine on which the charm is deployed. Endpoint bindings, if provided,
+// map the charm's relation endpoints to the names of spaces.
 func (c *Client) ServiceDeploy(
 	charmURL string,
 	serviceName string,
@@ -74,7 +75,11 @@ func (c *Client) ServiceDeploy(
 	placement []*instance.Placement,
 	networks []string,
 	storage map[string]storage.Constraints,
+	bindings map[string]string,
 ) error {
+	if len(bindings) > 0 && c.facade.BestAPIVersion() < 2 {
+		return errors.NotImplementedf("endpoint bindings (need Service V2+)")
+	}
 	args := params.ServicesDeploy{
 		Services: []params.ServiceDeploy{{
 			ServiceName:   serviceName,
@@ -86,6 +91,8 @@ func (c *Client) ServiceDeploy(
 			Placement:     placement,
 			Networks:      networks,
 			Storage:       storage,
+
+			EndpointBindings: bindings,
 		}},
 	}
 	var results params.ErrorResults
@@ -95,7 +95,27 @@ func (s *serviceSuite) TestSetServiceDeploy(c *gc.C) {
 		return nil
 	})
 	err := s.client.ServiceDeploy("charmURL", "serviceA", 2, "configYAML", constraints.MustParse("mem=4G"),
-		"machineSpec", nil, []string{"neta"}, map[string]storage.Constraints{"data": storage.Constraints{Pool: "pool"}})
+		"machineSpec", nil, []string{"neta"}, map[string]storage.Constraints{"data": storage.Constraints{Pool: "pool"}}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *serviceSuite) TestSetServiceDeployEndpointBindings(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "ServicesDeploy")
+		args, ok := a.(params.ServicesDeploy)
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(args.Services, gc.HasLen, 1)
+		c.Assert(args.Services[0].EndpointBindings, jc.DeepEquals, map[string]string{"db": "internal"})
+
+		result := response.(*params.ErrorResults)
+		result.Results = make([]params.ErrorResult, 1)
+		return nil
+	})
+	err := s.client.ServiceDeploy("charmURL", "serviceA", 1, "", constraints.Value{},
+		"", nil, nil, nil, map[string]string{"db": "internal"})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
@@ -82,6 +82,26 @@ func (c *Client) ListBlockedEnvironments() ([]params.EnvironmentBlockInfo, error
 	return result.Environments, err
 }
 
+// ExportEnvironment returns the serialized description of the
+// environment with the given UUID.
+func (c *Client) ExportEnvironment(uuid string) ([]byte, error) {
+	args := params.Entity{Tag: names.NewEnvironTag(uuid).String()}
+	var result params.SerializedEnvironment
+	if err := c.facade.FacadeCall("ExportEnvironment", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Bytes, nil
+}
+
+// ImportEnvironment creates a new environment in the system from a
+// serialized description, as returned by ExportEnvironment.
+func (c *Client) ImportEnvironment(bytes []byte) (params.Environment, error) {
+	args := params.SerializedEnvironment{Bytes: bytes}
+	var result params.Environment
+	err := c.facade.FacadeCall("ImportEnvironment", args, &result)
+	return result, errors.Trace(err)
+}
+
 // RemoveBlocks removes all the blocks in the system.
 func (c *Client) RemoveBlocks() error {
 	args := params.RemoveBlocksArgs{All: true}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
@@ -19,6 +20,7 @@ import (
 	"github.com/juju/juju/juju"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/description"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
@@ -116,6 +118,33 @@ func (s *systemManagerSuite) TestRemoveBlocks(c *gc.C) {
 	c.Assert(blocks, gc.HasLen, 0)
 }
 
+func (s *systemManagerSuite) TestExportImportEnvironment(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "foo"})
+	defer st.Close()
+
+	sysManager := s.OpenAPI(c)
+	bytes, err := sysManager.ExportEnvironment(st.EnvironUUID())
+	c.Assert(err, jc.ErrorIsNil)
+	desc, err := description.Deserialize(bytes)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(desc.UUID(), gc.Equals, st.EnvironUUID())
+
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	desc.Config["uuid"] = uuid.String()
+	desc.Config["name"] = "bar"
+	bytes, err = description.Serialize(desc)
+	c.Assert(err, jc.ErrorIsNil)
+
+	env, err := sysManager.ImportEnvironment(bytes)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env, jc.DeepEquals, params.Environment{
+		Name:     "bar",
+		UUID:     uuid.String(),
+		OwnerTag: s.AdminUserTag(c).String(),
+	})
+}
+
 func (s *systemManagerSuite) TestWatchAllEnvs(c *gc.C) {
 	// The WatchAllEnvs infrastructure is comprehensively tested
 	// else. This test just ensure that the API calls work end-to-end.
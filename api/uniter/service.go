@@ -115,6 +115,31 @@ func (s *Service) CharmURL() (*charm.URL, bool, error) {
 	return nil, false, fmt.Errorf("%q has no charm url set", s.tag)
 }
 
+// CharmModifiedVersion returns the number of times the service's charm
+// has been modified in ways that require its units to run the
+// upgrade-charm hook, such as by attaching resources.
+func (s *Service) CharmModifiedVersion() (int, error) {
+	if s.st.BestAPIVersion() < 3 {
+		return 0, errors.NotImplementedf("CharmModifiedVersion")
+	}
+	var results params.IntResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("CharmModifiedVersion", args, &results)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return 0, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.Result, nil
+}
+
 // OwnerTag returns the service's owner user tag.
 func (s *Service) OwnerTag() (names.UserTag, error) {
 	if s.st.BestAPIVersion() > 0 {
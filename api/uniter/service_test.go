@@ -121,6 +121,22 @@ func (s *serviceSuite) TestCharmURL(c *gc.C) {
 	c.Assert(force, jc.IsFalse)
 }
 
+func (s *serviceSuite) TestCharmModifiedVersion(c *gc.C) {
+	version, err := s.apiService.CharmModifiedVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, 0)
+
+	_, err = s.wordpressService.SetResource(state.Resource{
+		Name:        "software",
+		Path:        "software.tgz",
+		StoragePath: "resources/wordpress/software",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	version, err = s.apiService.CharmModifiedVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, 1)
+}
+
 func (s *serviceSuite) TestOwnerTagV0(c *gc.C) {
 	s.patchNewState(c, uniter.NewStateV0)
 
@@ -5,6 +5,8 @@ package uniter
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -757,3 +759,211 @@ func (u *Unit) AddStorage(constraints map[string][]params.StorageConstraints) er
 
 	return results.Combine()
 }
+
+// UpgradeSeriesStatus returns the progress of the unit through the series
+// upgrade of its machine, and the series the machine is being upgraded to.
+func (u *Unit) UpgradeSeriesStatus() (params.UpgradeSeriesStatus, string, error) {
+	if u.st.BestAPIVersion() < 3 {
+		return "", "", errors.NotImplementedf("UpgradeSeriesStatus")
+	}
+	var results params.UpgradeSeriesStatusResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("UpgradeSeriesUnitStatus", args, &results)
+	if err != nil {
+		return "", "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", "", result.Error
+	}
+	return result.Status, result.ToSeries, nil
+}
+
+// SetUpgradeSeriesStatus records the progress of the unit through the
+// series upgrade of its machine.
+func (u *Unit) SetUpgradeSeriesStatus(status params.UpgradeSeriesStatus) error {
+	if u.st.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("SetUpgradeSeriesStatus")
+	}
+	var result params.ErrorResults
+	args := params.SetUpgradeSeriesStatusArgs{
+		Args: []params.SetUpgradeSeriesStatusArg{
+			{Entity: params.Entity{Tag: u.tag.String()}, Status: status},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetUpgradeSeriesUnitStatus", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// WatchUpgradeSeriesNotifications returns a NotifyWatcher for observing
+// changes to the series upgrade of the unit's machine.
+func (u *Unit) WatchUpgradeSeriesNotifications() (watcher.NotifyWatcher, error) {
+	if u.st.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("WatchUpgradeSeriesNotifications")
+	}
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("WatchUpgradeSeriesNotifications", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewNotifyWatcher(u.st.facade.RawAPICaller(), result)
+	return w, nil
+}
+
+// NetworkInfo returns the network interfaces and addresses of the unit's
+// machine in the spaces bound to each of the named endpoints, by
+// endpoint name.
+func (u *Unit) NetworkInfo(bindings []string) (map[string]params.NetworkInfoResult, error) {
+	if u.st.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("NetworkInfo")
+	}
+	var results params.NetworkInfoResults
+	args := params.NetworkInfoParams{
+		Unit:     u.tag.String(),
+		Bindings: bindings,
+	}
+	err := u.st.facade.FacadeCall("NetworkInfo", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// SetWorkloadVersion records the version of the workload the unit's
+// charm is running.
+func (u *Unit) SetWorkloadVersion(version string) error {
+	if u.st.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("SetWorkloadVersion")
+	}
+	var result params.ErrorResults
+	args := params.EntityWorkloadVersions{
+		Entities: []params.EntityWorkloadVersion{
+			{Tag: u.tag.String(), WorkloadVersion: version},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetWorkloadVersion", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// RegisterPayload records that the unit is running the given payload.
+func (u *Unit) RegisterPayload(payload params.Payload) error {
+	if u.st.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("RegisterPayload")
+	}
+	var result params.ErrorResults
+	args := params.UnitPayloads{
+		Payloads: []params.UnitPayload{
+			{Tag: u.tag.String(), Payload: payload},
+		},
+	}
+	err := u.st.facade.FacadeCall("RegisterPayloads", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// UnregisterPayload removes the unit's payload with the given class and
+// id.
+func (u *Unit) UnregisterPayload(class, id string) error {
+	if u.st.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("UnregisterPayload")
+	}
+	var result params.ErrorResults
+	args := params.UnitPayloadStatuses{
+		Payloads: []params.UnitPayloadStatus{
+			{Tag: u.tag.String(), Class: class, ID: id},
+		},
+	}
+	err := u.st.facade.FacadeCall("UnregisterPayloads", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// SetPayloadStatus updates the status of the unit's payload with the
+// given class and id.
+func (u *Unit) SetPayloadStatus(class, id, status string) error {
+	if u.st.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("SetPayloadStatus")
+	}
+	var result params.ErrorResults
+	args := params.UnitPayloadStatuses{
+		Payloads: []params.UnitPayloadStatus{
+			{Tag: u.tag.String(), Class: class, ID: id, Status: status},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetPayloadStatuses", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// Resource returns the current revision of the named resource of the
+// unit's service.
+func (u *Unit) Resource(name string) (params.Resource, error) {
+	if u.st.BestAPIVersion() < 3 {
+		return params.Resource{}, errors.NotImplementedf("Resource")
+	}
+	var results params.ResourceResults
+	args := params.UnitResources{
+		Resources: []params.UnitResource{
+			{Tag: u.tag.String(), Name: name},
+		},
+	}
+	err := u.st.facade.FacadeCall("Resources", args, &results)
+	if err != nil {
+		return params.Resource{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.Resource{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.Resource{}, result.Error
+	}
+	return result.Resource, nil
+}
+
+// DownloadResource returns a reader for the data of the current
+// revision of the named resource of the unit's service. The caller is
+// responsible for closing it.
+func (u *Unit) DownloadResource(name string) (io.ReadCloser, error) {
+	httpClient, err := u.st.facade.RawAPICaller().HTTPClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	path := fmt.Sprintf("/services/%s/resources/%s", u.ServiceName(), name)
+	req, err := http.NewRequest("GET", path, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create download request")
+	}
+	var resp *http.Response
+	if err := httpClient.Do(req, nil, &resp); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return resp.Body, nil
+}
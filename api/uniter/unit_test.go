@@ -5,6 +5,8 @@ package uniter_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -22,6 +24,7 @@ import (
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	statestorage "github.com/juju/juju/state/storage"
 	statetesting "github.com/juju/juju/state/testing"
 	jujufactory "github.com/juju/juju/testing/factory"
 )
@@ -973,3 +976,123 @@ func (s *unitMetricBatchesSuite) TestSendMetricBatch(c *gc.C) {
 	c.Assert(batches[0].Metrics()[0].Key, gc.Equals, "pings")
 	c.Assert(batches[0].Metrics()[0].Value, gc.Equals, "5")
 }
+
+func (s *unitSuite) TestUpgradeSeriesStatus(c *gc.C) {
+	status, series, err := s.apiUnit.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, params.UpgradeSeriesNotStarted)
+	c.Assert(series, gc.Equals, "")
+
+	err = s.wordpressMachine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	status, series, err = s.apiUnit.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, params.UpgradeSeriesPrepareStarted)
+	c.Assert(series, gc.Equals, "trusty")
+
+	err = s.apiUnit.SetUpgradeSeriesStatus(params.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpressMachine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.wordpressMachine.UpgradeSeriesStatus(), gc.Equals, state.UpgradeSeriesPrepareCompleted)
+}
+
+func (s *unitSuite) TestNetworkInfo(c *gc.C) {
+	err := s.wordpressMachine.SetProviderAddresses(network.NewAddress("10.0.0.5"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.apiUnit.NetworkInfo([]string{"db", "foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results["db"].Error, gc.IsNil)
+	c.Assert(results["db"].Info, jc.DeepEquals, []params.NetworkInfo{{
+		Addresses: []params.InterfaceAddress{{Address: "10.0.0.5"}},
+	}})
+	c.Assert(results["foo"].Error, gc.ErrorMatches, `endpoint "foo" not found`)
+}
+
+func (s *unitSuite) TestWatchUpgradeSeriesNotifications(c *gc.C) {
+	w, err := s.apiUnit.WatchUpgradeSeriesNotifications()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	err = s.wordpressMachine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *unitSuite) TestSetWorkloadVersion(c *gc.C) {
+	c.Assert(s.wordpressUnit.WorkloadVersion(), gc.Equals, "")
+
+	err := s.apiUnit.SetWorkloadVersion("4.2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.wordpressUnit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.wordpressUnit.WorkloadVersion(), gc.Equals, "4.2")
+}
+
+func (s *unitSuite) TestPayloads(c *gc.C) {
+	err := s.apiUnit.RegisterPayload(params.Payload{
+		Class: "webapp",
+		Type:  "docker",
+		ID:    "abc123",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.apiUnit.SetPayloadStatus("webapp", "abc123", "stopping")
+	c.Assert(err, jc.ErrorIsNil)
+
+	payloads, err := s.wordpressUnit.Payloads()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payloads, gc.HasLen, 1)
+	c.Assert(payloads[0].Status, gc.Equals, "stopping")
+
+	err = s.apiUnit.UnregisterPayload("webapp", "abc123")
+	c.Assert(err, jc.ErrorIsNil)
+	payloads, err = s.wordpressUnit.Payloads()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payloads, gc.HasLen, 0)
+
+	err = s.apiUnit.UnregisterPayload("webapp", "abc123")
+	c.Assert(err, gc.ErrorMatches, `.*payload webapp/abc123 not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *unitSuite) TestResource(c *gc.C) {
+	stor := statestorage.NewStorage(s.State.EnvironUUID(), s.State.MongoSession())
+	err := stor.Put("resources/wordpress/software", strings.NewReader("hello"), 5)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.wordpressService.SetResource(state.Resource{
+		Name:        "software",
+		Path:        "software.tgz",
+		Fingerprint: "abc",
+		Size:        5,
+		StoragePath: "resources/wordpress/software",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	res, err := s.apiUnit.Resource("software")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Name, gc.Equals, "software")
+	c.Assert(res.Path, gc.Equals, "software.tgz")
+	c.Assert(res.Revision, gc.Equals, 1)
+	c.Assert(res.Fingerprint, gc.Equals, "abc")
+
+	reader, err := s.apiUnit.DownloadResource("software")
+	c.Assert(err, jc.ErrorIsNil)
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "hello")
+
+	_, err = s.apiUnit.Resource("missing")
+	c.Assert(err, gc.ErrorMatches, `resource "missing" of service "wordpress" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
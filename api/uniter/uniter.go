@@ -221,8 +221,9 @@ func (st *State) Action(tag names.ActionTag) (*Action, error) {
 		return nil, err
 	}
 	return &Action{
-		name:   result.Action.Action.Name,
-		params: result.Action.Action.Parameters,
+		name:    result.Action.Action.Name,
+		params:  result.Action.Action.Parameters,
+		timeout: result.Action.Action.Timeout,
 	}, nil
 }
 
@@ -279,6 +280,30 @@ func (st *State) ActionFinish(tag names.ActionTag, status string, results map[st
 	return nil
 }
 
+// ActionStatus returns the current status of an action. A running
+// action whose status is "cancelling" should be stopped.
+func (st *State) ActionStatus(tag names.ActionTag) (string, error) {
+	if st.facade.BestAPIVersion() < 3 {
+		return "", errors.NotImplementedf("ActionStatus() (need V3+)")
+	}
+	var results params.StringResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	err := st.facade.FacadeCall("ActionStatus", args, &results)
+	if err != nil {
+		return "", err
+	}
+	if len(results.Results) != 1 {
+		return "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
 // RelationById returns the existing relation with the given id.
 func (st *State) RelationById(id int) (*Relation, error) {
 	var results params.RelationResults
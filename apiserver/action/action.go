@@ -4,8 +4,10 @@
 package action
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
+	"github.com/juju/utils"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -42,6 +44,7 @@ func NewActionAPI(st *state.State, resources *common.Resources, authorizer commo
 // each ID.
 func (a *ActionAPI) Actions(arg params.Entities) (params.ActionResults, error) {
 	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Entities))}
+	limits := make(serviceActionLimits)
 	for i, entity := range arg.Entities {
 		currentResult := &response.Results[i]
 		tag, err := names.ParseTag(entity.Tag)
@@ -65,6 +68,7 @@ func (a *ActionAPI) Actions(arg params.Entities) (params.ActionResults, error) {
 			continue
 		}
 		response.Results[i] = makeActionResult(receiverTag, action)
+		response.Results[i].Limit = limits.limit(a.state, receiverTag, action.Name())
 	}
 	return response, nil
 }
@@ -97,7 +101,9 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 			currentResult.Error = common.ServerError(err)
 			continue
 		}
-		enqueued, err := receiver.AddAction(action.Name, action.Parameters)
+		enqueued, err := receiver.AddActionWithOptions(action.Name, action.Parameters, state.ActionOptions{
+			Timeout: action.Timeout,
+		})
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
 			continue
@@ -108,6 +114,159 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	return response, nil
 }
 
+// EnqueueOperation queues up the given Actions as a single operation,
+// returning the operation's id along with the result of enqueueing each
+// Action. An Action whose receiver is a service is enqueued on each of
+// the service's units.
+func (a *ActionAPI) EnqueueOperation(arg params.Actions) (params.OperationResult, error) {
+	if len(arg.Actions) == 0 {
+		return params.OperationResult{}, errors.New("no actions specified")
+	}
+	operation, err := utils.NewUUID()
+	if err != nil {
+		return params.OperationResult{}, errors.Trace(err)
+	}
+	response := params.OperationResult{Operation: operation.String()}
+	for _, action := range arg.Actions {
+		units, err := a.operationUnits(action.Receiver)
+		if err != nil {
+			response.Actions = append(response.Actions, params.ActionResult{
+				Action: &params.Action{
+					Receiver: action.Receiver,
+					Name:     action.Name,
+				},
+				Error: common.ServerError(err),
+			})
+			continue
+		}
+		for _, unit := range units {
+			enqueued, err := unit.AddActionWithOptions(action.Name, action.Parameters, state.ActionOptions{
+				Operation: response.Operation,
+				Timeout:   action.Timeout,
+			})
+			if err != nil {
+				response.Actions = append(response.Actions, params.ActionResult{
+					Action: &params.Action{
+						Receiver: unit.Tag().String(),
+						Name:     action.Name,
+					},
+					Error: common.ServerError(err),
+				})
+				continue
+			}
+			response.Actions = append(response.Actions, makeActionResult(unit.Tag(), enqueued))
+		}
+	}
+	return response, nil
+}
+
+// operationUnits returns the units on which an Action with the given
+// receiver should be enqueued as part of an operation.
+func (a *ActionAPI) operationUnits(receiver string) ([]*state.Unit, error) {
+	tag, err := names.ParseTag(receiver)
+	if err != nil {
+		return nil, common.ErrBadId
+	}
+	switch tag := tag.(type) {
+	case names.UnitTag:
+		unit, err := a.state.Unit(tag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []*state.Unit{unit}, nil
+	case names.ServiceTag:
+		service, err := a.state.Service(tag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		units, err := service.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(units) == 0 {
+			return nil, errors.Errorf("service %q has no units", tag.Id())
+		}
+		return units, nil
+	}
+	return nil, common.ErrBadId
+}
+
+// Operations returns the Actions enqueued as part of each of the given
+// operations.
+func (a *ActionAPI) Operations(arg params.Operations) (params.OperationResults, error) {
+	response := params.OperationResults{Results: make([]params.OperationResult, len(arg.Operations))}
+	limits := make(serviceActionLimits)
+	for i, operation := range arg.Operations {
+		currentResult := &response.Results[i]
+		currentResult.Operation = operation
+		actions, err := a.state.OperationActions(operation)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		currentResult.Actions = make([]params.ActionResult, len(actions))
+		for j, action := range actions {
+			receiverTag, err := names.ActionReceiverTag(action.Receiver())
+			if err != nil {
+				currentResult.Actions[j].Error = common.ServerError(err)
+				continue
+			}
+			currentResult.Actions[j] = makeActionResult(receiverTag, action)
+			currentResult.Actions[j].Limit = limits.limit(a.state, receiverTag, action.Name())
+		}
+	}
+	return response, nil
+}
+
+// FilterActions returns the Actions in the environment's action history
+// that match the given filter, most recently enqueued first.
+func (a *ActionAPI) FilterActions(arg params.ActionFilter) (params.ActionResults, error) {
+	filter := state.ActionFilter{
+		Names:  arg.Names,
+		Offset: arg.Offset,
+		Limit:  arg.Limit,
+	}
+	for _, unit := range arg.Units {
+		tag, err := names.ParseUnitTag(unit)
+		if err != nil {
+			return params.ActionResults{}, common.ErrBadId
+		}
+		filter.Units = append(filter.Units, tag.Id())
+	}
+	for _, service := range arg.Services {
+		tag, err := names.ParseServiceTag(service)
+		if err != nil {
+			return params.ActionResults{}, common.ErrBadId
+		}
+		filter.Services = append(filter.Services, tag.Id())
+	}
+	for _, status := range arg.Statuses {
+		filter.Statuses = append(filter.Statuses, state.ActionStatus(status))
+	}
+	if arg.After != nil {
+		filter.After = *arg.After
+	}
+	if arg.Before != nil {
+		filter.Before = *arg.Before
+	}
+	actions, err := a.state.FilterActions(filter)
+	if err != nil {
+		return params.ActionResults{}, errors.Trace(err)
+	}
+	response := params.ActionResults{Results: make([]params.ActionResult, len(actions))}
+	limits := make(serviceActionLimits)
+	for i, action := range actions {
+		receiverTag, err := names.ActionReceiverTag(action.Receiver())
+		if err != nil {
+			response.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		response.Results[i] = makeActionResult(receiverTag, action)
+		response.Results[i].Limit = limits.limit(a.state, receiverTag, action.Name())
+	}
+	return response, nil
+}
+
 // ListAll takes a list of Entities representing ActionReceivers and
 // returns all of the Actions that have been enqueued or run by each of
 // those Entities.
@@ -136,7 +295,9 @@ func (a *ActionAPI) ListCompleted(arg params.Entities) (params.ActionsByReceiver
 	return a.internalList(arg, completedActions)
 }
 
-// Cancel attempts to cancel enqueued Actions from running.
+// Cancel cancels the given Actions. Pending Actions are cancelled
+// immediately; running Actions are marked as cancelling, and are
+// stopped by their receivers.
 func (a *ActionAPI) Cancel(arg params.Entities) (params.ActionResults, error) {
 	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Entities))}
 	for i, entity := range arg.Entities {
@@ -156,7 +317,7 @@ func (a *ActionAPI) Cancel(arg params.Entities) (params.ActionResults, error) {
 			currentResult.Error = common.ServerError(err)
 			continue
 		}
-		result, err := action.Finish(state.ActionResults{Status: state.ActionCancelled, Message: "action cancelled via the API"})
+		result, err := action.Cancel()
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
 			continue
@@ -199,6 +360,124 @@ func (a *ActionAPI) ServicesCharmActions(args params.Entities) (params.ServicesC
 	return result, nil
 }
 
+// SetActionLimits sets the maximum number of units of each given
+// service that may run the given action at once.
+func (a *ActionAPI) SetActionLimits(args params.ActionLimits) (params.ErrorResults, error) {
+	result := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Limits))}
+	for i, limit := range args.Limits {
+		svcTag, err := names.ParseServiceTag(limit.ServiceTag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrBadId)
+			continue
+		}
+		svc, err := a.state.Service(svcTag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		err = svc.SetActionLimit(limit.Action, limit.Limit)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// AddSchedules adds schedules on which the given Actions are enqueued
+// repeatedly, by the action scheduler worker, on a unit or on each unit
+// of a service.
+func (a *ActionAPI) AddSchedules(args params.ActionSchedules) (params.ActionScheduleResults, error) {
+	result := params.ActionScheduleResults{Results: make([]params.ActionScheduleResult, len(args.Schedules))}
+	for i, arg := range args.Schedules {
+		receiver, err := names.ParseTag(arg.Receiver)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrBadId)
+			continue
+		}
+		schedule, err := a.state.AddActionSchedule(state.ActionScheduleArgs{
+			Receiver:   receiver,
+			Name:       arg.Name,
+			Parameters: arg.Parameters,
+			Schedule:   arg.Schedule,
+		})
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Schedule, err = makeActionSchedule(schedule)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// Schedules returns all of the environment's action schedules.
+func (a *ActionAPI) Schedules() (params.ActionScheduleResults, error) {
+	schedules, err := a.state.AllActionSchedules()
+	if err != nil {
+		return params.ActionScheduleResults{}, errors.Trace(err)
+	}
+	result := params.ActionScheduleResults{Results: make([]params.ActionScheduleResult, len(schedules))}
+	for i, schedule := range schedules {
+		result.Results[i].Schedule, err = makeActionSchedule(schedule)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// RemoveSchedules removes the action schedules with the given ids.
+// Actions they have already enqueued are unaffected.
+func (a *ActionAPI) RemoveSchedules(args params.ActionScheduleIds) (params.ErrorResults, error) {
+	result := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Ids))}
+	for i, id := range args.Ids {
+		err := a.state.RemoveActionSchedule(id)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func makeActionSchedule(schedule *state.ActionSchedule) (*params.ActionSchedule, error) {
+	receiver, err := schedule.Receiver()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &params.ActionSchedule{
+		Id:         schedule.Id(),
+		Receiver:   receiver.String(),
+		Name:       schedule.Name(),
+		Parameters: schedule.Parameters(),
+		Schedule:   schedule.Schedule(),
+		Created:    schedule.Created(),
+		LastRun:    schedule.LastRun(),
+		NextRun:    schedule.NextRun(),
+		MissedRuns: schedule.MissedRuns(),
+	}, nil
+}
+
+// serviceActionLimits holds the action limits of services, by service
+// name, as they are looked up while building a response.
+type serviceActionLimits map[string]map[string]int
+
+// limit returns the maximum number of units of the receiver's service
+// that may run the named action at once, or 0 if there is no limit.
+func (l serviceActionLimits) limit(st *state.State, receiver names.Tag, actionName string) int {
+	unitTag, ok := receiver.(names.UnitTag)
+	if !ok {
+		return 0
+	}
+	serviceName, err := names.UnitService(unitTag.Id())
+	if err != nil {
+		return 0
+	}
+	limits, ok := l[serviceName]
+	if !ok {
+		if svc, err := st.Service(serviceName); err == nil {
+			limits = svc.ActionLimits()
+		} else {
+			logger.Debugf("cannot get action limits for service %q: %v", serviceName, err)
+		}
+		l[serviceName] = limits
+	}
+	return limits[actionName]
+}
+
 // internalList takes a list of Entities representing ActionReceivers
 // and returns all of the Actions the extractorFn can get out of the
 // ActionReceiver.
@@ -313,12 +592,16 @@ func makeActionResult(actionReceiverTag names.Tag, action *state.Action) params.
 			Tag:        action.ActionTag().String(),
 			Name:       action.Name(),
 			Parameters: action.Parameters(),
+			Operation:  action.Operation(),
+			Timeout:    action.Timeout(),
 		},
-		Status:    string(action.Status()),
-		Message:   message,
-		Output:    output,
-		Enqueued:  action.Enqueued(),
-		Started:   action.Started(),
-		Completed: action.Completed(),
+		Status:     string(action.Status()),
+		Message:    message,
+		Output:     output,
+		Enqueued:   action.Enqueued(),
+		Started:    action.Started(),
+		Completed:  action.Completed(),
+		Schedule:   action.Schedule(),
+		MissedRuns: action.MissedRuns(),
 	}
 }
@@ -6,6 +6,7 @@ package action_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -217,6 +218,66 @@ func (s *actionSuite) TestEnqueue(c *gc.C) {
 	c.Assert(actions, gc.HasLen, 0)
 }
 
+func (s *actionSuite) TestEnqueueOperation(c *gc.C) {
+	factory := jujuFactory.NewFactory(s.State)
+	wordpressUnit2 := factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Service: s.wordpress,
+		Machine: s.machine1,
+	})
+
+	arg := params.Actions{
+		Actions: []params.Action{
+			// Every unit of the service.
+			{Receiver: s.wordpress.Tag().String(), Name: "fakeaction"},
+			// A single unit.
+			{Receiver: s.mysqlUnit.Tag().String(), Name: "fakeaction"},
+			// Not a unit or service.
+			{Receiver: s.machine0.Tag().String(), Name: "fakeaction"},
+			// Missing service.
+			{Receiver: names.NewServiceTag("missing").String(), Name: "fakeaction"},
+		},
+	}
+	res, err := s.action.EnqueueOperation(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Operation, gc.Not(gc.Equals), "")
+	c.Assert(res.Actions, gc.HasLen, 5)
+
+	var receivers, actionTags []string
+	for _, result := range res.Actions[:3] {
+		c.Check(result.Error, gc.IsNil)
+		c.Check(result.Action.Operation, gc.Equals, res.Operation)
+		c.Check(result.Status, gc.Equals, params.ActionPending)
+		receivers = append(receivers, result.Action.Receiver)
+		actionTags = append(actionTags, result.Action.Tag)
+	}
+	c.Check(receivers, jc.SameContents, []string{
+		s.wordpressUnit.Tag().String(),
+		wordpressUnit2.Tag().String(),
+		s.mysqlUnit.Tag().String(),
+	})
+	c.Check(res.Actions[3].Error, gc.DeepEquals, &params.Error{Message: "id not found", Code: "not found"})
+	c.Check(res.Actions[4].Error, gc.ErrorMatches, `service "missing" not found`)
+
+	results, err := s.action.Operations(params.Operations{
+		Operations: []string{res.Operation, "missing"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Operation, gc.Equals, res.Operation)
+	var operationTags []string
+	for _, result := range results.Results[0].Actions {
+		c.Check(result.Action.Operation, gc.Equals, res.Operation)
+		operationTags = append(operationTags, result.Action.Tag)
+	}
+	c.Assert(operationTags, jc.SameContents, actionTags)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `operation "missing" not found`)
+}
+
+func (s *actionSuite) TestEnqueueOperationNoActions(c *gc.C) {
+	_, err := s.action.EnqueueOperation(params.Actions{})
+	c.Assert(err, gc.ErrorMatches, "no actions specified")
+}
+
 type testCaseAction struct {
 	Name       string
 	Parameters map[string]interface{}
@@ -574,6 +635,175 @@ func (s *actionSuite) TestCancel(c *gc.C) {
 	c.Assert(myActions[1].Status, gc.Equals, params.ActionCancelled)
 }
 
+func (s *actionSuite) TestCancelRunning(c *gc.C) {
+	action, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.action.Cancel(params.Entities{
+		Entities: []params.Entity{{Tag: action.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Status, gc.Equals, params.ActionCancelling)
+
+	// Cancelling a finished action fails.
+	_, err = action.Finish(state.ActionResults{Status: state.ActionCancelled})
+	c.Assert(err, jc.ErrorIsNil)
+	results, err = s.action.Cancel(params.Entities{
+		Entities: []params.Entity{{Tag: action.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "cannot cancel action .*: action .* has already finished")
+}
+
+func (s *actionSuite) TestEnqueueWithTimeout(c *gc.C) {
+	results, err := s.action.Enqueue(params.Actions{
+		Actions: []params.Action{{
+			Receiver: s.wordpressUnit.Tag().String(),
+			Name:     "fakeaction",
+			Timeout:  time.Minute,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Action.Timeout, gc.Equals, time.Minute)
+
+	actions, err := s.wordpressUnit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Timeout(), gc.Equals, time.Minute)
+}
+
+func (s *actionSuite) TestSetActionLimits(c *gc.C) {
+	results, err := s.action.SetActionLimits(params.ActionLimits{
+		Limits: []params.ActionLimit{{
+			ServiceTag: s.wordpress.Tag().String(),
+			Action:     "fakeaction",
+			Limit:      1,
+		}, {
+			ServiceTag: s.wordpress.Tag().String(),
+			Action:     "missing",
+			Limit:      1,
+		}, {
+			ServiceTag: "service-missing",
+			Action:     "fakeaction",
+			Limit:      1,
+		}, {
+			ServiceTag: s.wordpressUnit.Tag().String(),
+			Action:     "fakeaction",
+			Limit:      1,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot set limit for action "missing" on service "wordpress": action "missing" not defined`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `service "missing" not found`)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, "id not found")
+
+	// The limit is reported along with the actions it applies to.
+	action, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	actions, err := s.action.Actions(params.Entities{
+		Entities: []params.Entity{{Tag: action.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions.Results, gc.HasLen, 1)
+	c.Assert(actions.Results[0].Limit, gc.Equals, 1)
+}
+
+func (s *actionSuite) TestFilterActions(c *gc.C) {
+	wordpressAction, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	mysqlAction, err := s.mysqlUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = mysqlAction.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.action.FilterActions(params.ActionFilter{
+		Services: []string{s.wordpress.Tag().String()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Action.Tag, gc.Equals, wordpressAction.Tag().String())
+	c.Assert(results.Results[0].Action.Receiver, gc.Equals, s.wordpressUnit.Tag().String())
+	c.Assert(results.Results[0].Status, gc.Equals, params.ActionPending)
+
+	results, err = s.action.FilterActions(params.ActionFilter{
+		Units:    []string{s.mysqlUnit.Tag().String()},
+		Statuses: []string{params.ActionCompleted},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Action.Tag, gc.Equals, mysqlAction.Tag().String())
+
+	results, err = s.action.FilterActions(params.ActionFilter{
+		Names: []string{"fakeaction"},
+		Limit: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+
+	_, err = s.action.FilterActions(params.ActionFilter{
+		Units: []string{s.mysql.Tag().String()},
+	})
+	c.Assert(err, gc.ErrorMatches, "id not found")
+}
+
+func (s *actionSuite) TestSchedules(c *gc.C) {
+	added, err := s.action.AddSchedules(params.ActionSchedules{
+		Schedules: []params.ActionSchedule{{
+			Receiver: s.wordpressUnit.Tag().String(),
+			Name:     "fakeaction",
+			Schedule: "@daily",
+		}, {
+			Receiver: s.wordpress.Tag().String(),
+			Name:     "fakeaction",
+			Schedule: "*/5 * * * *",
+		}, {
+			Receiver: s.wordpress.Tag().String(),
+			Name:     "missing",
+			Schedule: "@daily",
+		}, {
+			Receiver: "bad-tag",
+			Name:     "fakeaction",
+			Schedule: "@daily",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(added.Results, gc.HasLen, 4)
+	c.Assert(added.Results[0].Error, gc.IsNil)
+	c.Assert(added.Results[0].Schedule.Receiver, gc.Equals, s.wordpressUnit.Tag().String())
+	c.Assert(added.Results[0].Schedule.Schedule, gc.Equals, "@daily")
+	c.Assert(added.Results[1].Error, gc.IsNil)
+	c.Assert(added.Results[2].Error, gc.ErrorMatches, `cannot add schedule for action "missing": action "missing" not defined`)
+	c.Assert(added.Results[3].Error, gc.ErrorMatches, "id not found")
+
+	schedules, err := s.action.Schedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedules.Results, gc.HasLen, 2)
+	// Schedules are listed in the order in which they are next due to run.
+	c.Assert(schedules.Results[0].Schedule, jc.DeepEquals, added.Results[1].Schedule)
+	c.Assert(schedules.Results[1].Schedule, jc.DeepEquals, added.Results[0].Schedule)
+
+	removed, err := s.action.RemoveSchedules(params.ActionScheduleIds{
+		Ids: []string{added.Results[0].Schedule.Id, "999"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed.Results, gc.HasLen, 2)
+	c.Assert(removed.Results[0].Error, gc.IsNil)
+	c.Assert(removed.Results[1].Error, gc.ErrorMatches, `action schedule "999" not found`)
+
+	schedules, err = s.action.Schedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedules.Results, gc.HasLen, 1)
+	c.Assert(schedules.Results[0].Schedule.Id, gc.Equals, added.Results[1].Schedule.Id)
+}
+
 func (s *actionSuite) TestServicesCharmActions(c *gc.C) {
 	actionSchemas := map[string]map[string]interface{}{
 		"snapshot": {
@@ -135,6 +135,16 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 	// to serve to them.
 	a.loggedIn = true
 
+	// Users with read-only access may not change the environment.
+	if isReadOnlyEntity(entity) {
+		authedApi = newReadOnlyRoot(authedApi)
+	}
+
+	// Record every call a user makes in the audit trail.
+	if isUser {
+		authedApi = newAuditingRoot(authedApi, a.root.state, entity.Tag(), a.root.remoteAddr)
+	}
+
 	if agentPingerNeeded {
 		if err := startPingerIfAgent(a.root, entity); err != nil {
 			return fail, errors.Trace(err)
@@ -512,6 +512,28 @@ func (s *loginSuite) TestNonEnvironUserLoginFails(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
 }
 
+func (s *loginSuite) TestReadOnlyEnvironUserLogin(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "dummy-password", NoEnvUser: true})
+	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{
+		User:   user.UserTag().Canonical(),
+		Access: state.EnvironmentReadAccess,
+	})
+	info.Password = "dummy-password"
+	info.Tag = user.UserTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	_, err = st.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = st.Client().DestroyMachines("0")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(params.ErrCode(err), gc.Equals, params.CodeUnauthorized)
+}
+
 func (s *loginV0Suite) TestLoginReportsEnvironTag(c *gc.C) {
 	st, cleanup := s.setupServer(c)
 	defer cleanup()
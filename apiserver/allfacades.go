@@ -11,6 +11,7 @@ import (
 	_ "github.com/juju/juju/apiserver/addresser"
 	_ "github.com/juju/juju/apiserver/agent"
 	_ "github.com/juju/juju/apiserver/annotations"
+	_ "github.com/juju/juju/apiserver/auditlog"
 	_ "github.com/juju/juju/apiserver/backups"
 	_ "github.com/juju/juju/apiserver/block"
 	_ "github.com/juju/juju/apiserver/charmrevisionupdater"
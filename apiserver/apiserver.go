@@ -356,6 +356,12 @@ func (srv *Server) run(lis net.Listener) {
 			ctxt:    httpCtxt,
 			dataDir: srv.dataDir},
 	)
+	handleAll(mux, "/environment/:envuuid/services/:service/resources/:name",
+		&resourcesHandler{
+			ctxt:    httpCtxt,
+			dataDir: srv.dataDir,
+		},
+	)
 	// TODO: We can switch from handleAll to mux.Post/Get/etc for entries
 	// where we only want to support specific request methods. However, our
 	// tests currently assert that errors come back as application/json and
@@ -375,7 +381,8 @@ func (srv *Server) run(lis net.Listener) {
 	strictCtxt.stateServerEnvOnly = true
 	handleAll(mux, "/environment/:envuuid/backups",
 		&backupHandler{
-			ctxt: strictCtxt,
+			ctxt:    strictCtxt,
+			dataDir: srv.dataDir,
 		},
 	)
 	handleAll(mux, "/environment/:envuuid/api", http.HandlerFunc(srv.apiHandler))
@@ -465,7 +472,7 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 	}
 	conn := rpc.NewConn(codec, notifier)
 
-	h, err := srv.newAPIHandler(conn, reqNotifier, envUUID)
+	h, err := srv.newAPIHandler(conn, reqNotifier, envUUID, wsConn.Request().RemoteAddr)
 	if err != nil {
 		conn.Serve(&errRoot{err}, serverError)
 	} else {
@@ -483,7 +490,7 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 	return conn.Close()
 }
 
-func (srv *Server) newAPIHandler(conn *rpc.Conn, reqNotifier *requestNotifier, envUUID string) (*apiHandler, error) {
+func (srv *Server) newAPIHandler(conn *rpc.Conn, reqNotifier *requestNotifier, envUUID, remoteAddr string) (*apiHandler, error) {
 	// Note that we don't overwrite envUUID here because
 	// newAPIHandler treats an empty envUUID as signifying
 	// the API version used.
@@ -498,7 +505,7 @@ func (srv *Server) newAPIHandler(conn *rpc.Conn, reqNotifier *requestNotifier, e
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return newApiHandler(srv, st, conn, reqNotifier, envUUID)
+	return newApiHandler(srv, st, conn, reqNotifier, envUUID, remoteAddr)
 }
 
 func (srv *Server) mongoPinger() error {
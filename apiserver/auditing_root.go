@@ -0,0 +1,122 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/juju/names"
+
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// auditRecorder records audit events; it is implemented by
+// *state.State.
+type auditRecorder interface {
+	AddAuditEvent(audit.Event) error
+}
+
+// auditingRoot records an audit event for every API call made
+// through it, once the call has completed.
+type auditingRoot struct {
+	rpc.MethodFinder
+	recorder   auditRecorder
+	user       names.Tag
+	remoteAddr string
+}
+
+// newAuditingRoot returns a new auditingRoot which records the calls
+// made by the given user from the given address.
+func newAuditingRoot(finder rpc.MethodFinder, recorder auditRecorder, user names.Tag, remoteAddr string) *auditingRoot {
+	return &auditingRoot{
+		MethodFinder: finder,
+		recorder:     recorder,
+		user:         user,
+		remoteAddr:   remoteAddr,
+	}
+}
+
+// isAudited returns whether calls to the named facade should be
+// recorded in the audit trail. Pings and watcher calls carry no
+// information about changes made by a user, and are very frequent,
+// so they are not recorded.
+func isAudited(rootName string) bool {
+	if rootName == "Pinger" {
+		return false
+	}
+	return !strings.HasSuffix(rootName, "Watcher")
+}
+
+// FindMethod implements rpc.MethodFinder.
+func (r *auditingRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if !isAudited(rootName) {
+		return caller, nil
+	}
+	return &auditingCaller{
+		MethodCaller: caller,
+		root:         r,
+		facade:       rootName,
+		version:      version,
+		method:       methodName,
+	}, nil
+}
+
+// auditingCaller wraps a rpcreflect.MethodCaller, recording an audit
+// event for each call made.
+type auditingCaller struct {
+	rpcreflect.MethodCaller
+	root    *auditingRoot
+	facade  string
+	version int
+	method  string
+}
+
+// Call implements rpcreflect.MethodCaller.
+func (c *auditingCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	start := time.Now()
+	result, err := c.MethodCaller.Call(objId, arg)
+
+	event := audit.Event{
+		Time:          start,
+		User:          c.root.user.String(),
+		Facade:        c.facade,
+		Version:       c.version,
+		Method:        c.method,
+		SourceAddress: c.root.remoteAddr,
+	}
+	if arg.IsValid() {
+		event.Args, event.Entities = audit.SummariseArgs(arg.Interface())
+	}
+	if err != nil {
+		event.Error = err.Error()
+	} else if resultErr := bulkResultError(result); resultErr != nil {
+		event.Error = resultErr.Error()
+	}
+	if recordErr := c.root.recorder.AddAuditEvent(event); recordErr != nil {
+		logger.Errorf("cannot record audit event for %s.%s: %v", c.facade, c.method, recordErr)
+	}
+	return result, err
+}
+
+// bulkResultError returns the combined error held in a bulk call
+// result, such as params.ErrorResults, or nil if there is none.
+func bulkResultError(result reflect.Value) error {
+	if !result.IsValid() || !result.CanInterface() {
+		return nil
+	}
+	if combiner, ok := result.Interface().(interface {
+		Combine() error
+	}); ok {
+		return combiner.Combine()
+	}
+	return nil
+}
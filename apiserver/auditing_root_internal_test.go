@@ -0,0 +1,155 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/rpc/rpcreflect"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type auditingRootSuite struct {
+	coretesting.BaseSuite
+	recorder *fakeAuditRecorder
+	finder   *fakeMethodFinder
+	root     *auditingRoot
+}
+
+var _ = gc.Suite(&auditingRootSuite{})
+
+func (s *auditingRootSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.recorder = &fakeAuditRecorder{}
+	s.finder = &fakeMethodFinder{}
+	s.root = newAuditingRoot(s.finder, s.recorder, names.NewUserTag("bob"), "10.0.0.1:4321")
+}
+
+func (s *auditingRootSuite) call(c *gc.C, rootName, method string, arg interface{}) (reflect.Value, error) {
+	caller, err := s.root.FindMethod(rootName, 1, method)
+	c.Assert(err, jc.ErrorIsNil)
+	var argValue reflect.Value
+	if arg != nil {
+		argValue = reflect.ValueOf(arg)
+	}
+	return caller.Call("", argValue)
+}
+
+func (s *auditingRootSuite) TestCallRecorded(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{{Tag: "machine-1"}}}
+	_, err := s.call(c, "Client", "DestroyMachines", args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.recorder.events, gc.HasLen, 1)
+	event := s.recorder.events[0]
+	c.Assert(event.Time.IsZero(), jc.IsFalse)
+	event.Time = time.Time{}
+	c.Assert(event, jc.DeepEquals, audit.Event{
+		User:          "user-bob",
+		Facade:        "Client",
+		Version:       1,
+		Method:        "DestroyMachines",
+		Args:          `{"Entities":[{"Tag":"machine-1"}]}`,
+		Entities:      []string{"machine-1"},
+		SourceAddress: "10.0.0.1:4321",
+	})
+}
+
+func (s *auditingRootSuite) TestCallErrorRecorded(c *gc.C) {
+	s.finder.err = errors.New("boom")
+	_, err := s.call(c, "Client", "ServiceDeploy", nil)
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	c.Assert(s.recorder.events, gc.HasLen, 1)
+	c.Assert(s.recorder.events[0].Error, gc.Equals, "boom")
+	c.Assert(s.recorder.events[0].Args, gc.Equals, "")
+}
+
+func (s *auditingRootSuite) TestBulkResultErrorRecorded(c *gc.C) {
+	s.finder.result = params.ErrorResults{
+		Results: []params.ErrorResult{{
+			Error: &params.Error{Message: "unit not found"},
+		}},
+	}
+	_, err := s.call(c, "Client", "DestroyServiceUnits", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.recorder.events, gc.HasLen, 1)
+	c.Assert(s.recorder.events[0].Error, gc.Equals, "unit not found")
+}
+
+func (s *auditingRootSuite) TestRecorderErrorIgnored(c *gc.C) {
+	s.recorder.err = errors.New("mongo down")
+	_, err := s.call(c, "Client", "FullStatus", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *auditingRootSuite) TestPingsAndWatchersNotRecorded(c *gc.C) {
+	for _, rootName := range []string{"Pinger", "AllWatcher", "NotifyWatcher"} {
+		_, err := s.call(c, rootName, "Next", nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(s.recorder.events, gc.HasLen, 0)
+}
+
+func (s *auditingRootSuite) TestFindMethodError(c *gc.C) {
+	s.finder.findErr = errors.New("no such method")
+	caller, err := s.root.FindMethod("Client", 1, "Foo")
+	c.Assert(err, gc.ErrorMatches, "no such method")
+	c.Assert(caller, gc.IsNil)
+	c.Assert(s.recorder.events, gc.HasLen, 0)
+}
+
+type fakeAuditRecorder struct {
+	events []audit.Event
+	err    error
+}
+
+func (r *fakeAuditRecorder) AddAuditEvent(event audit.Event) error {
+	r.events = append(r.events, event)
+	return r.err
+}
+
+type fakeMethodFinder struct {
+	findErr error
+	result  interface{}
+	err     error
+}
+
+func (f *fakeMethodFinder) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	if f.findErr != nil {
+		return nil, f.findErr
+	}
+	return &fakeMethodCaller{f}, nil
+}
+
+type fakeMethodCaller struct {
+	finder *fakeMethodFinder
+}
+
+func (*fakeMethodCaller) ParamsType() reflect.Type {
+	return nil
+}
+
+func (*fakeMethodCaller) ResultType() reflect.Type {
+	return nil
+}
+
+func (f *fakeMethodCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	if f.finder.err != nil {
+		return reflect.Value{}, f.finder.err
+	}
+	if f.finder.result == nil {
+		return reflect.Value{}, nil
+	}
+	return reflect.ValueOf(f.finder.result), nil
+}
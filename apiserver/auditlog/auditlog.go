@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package auditlog provides access to the audit trail of API calls
+// made by users against an environment.
+package auditlog
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("AuditLog", 1, NewAPI)
+}
+
+// API implements the AuditLog facade.
+type API struct {
+	st         *state.State
+	authorizer common.Authorizer
+}
+
+// NewAPI returns a new AuditLog API facade.
+func NewAPI(st *state.State, _ *common.Resources, authorizer common.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		st:         st,
+		authorizer: authorizer,
+	}, nil
+}
+
+// checkCanRead returns an error if the authenticated user is neither
+// the owner of the environment nor a system administrator.
+func (api *API) checkCanRead() error {
+	apiUser, _ := api.authorizer.GetAuthTag().(names.UserTag)
+	isAdmin, err := api.st.IsSystemAdministrator(apiUser)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if isAdmin {
+		return nil
+	}
+	env, err := api.st.Environment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if env.Owner().Canonical() == apiUser.Canonical() {
+		return nil
+	}
+	return common.ErrPerm
+}
+
+// List returns the audit events recorded for the environment that
+// match the given filter, oldest first.
+func (api *API) List(args params.AuditEventFilter) (params.AuditEventResults, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.AuditEventResults{}, errors.Trace(err)
+	}
+	filter := state.AuditEventFilter{
+		User:   args.User,
+		Entity: args.Entity,
+		Limit:  args.Limit,
+	}
+	if args.After != nil {
+		filter.After = *args.After
+	}
+	if args.Before != nil {
+		filter.Before = *args.Before
+	}
+	events, err := api.st.AuditEvents(filter)
+	if err != nil {
+		return params.AuditEventResults{}, errors.Trace(err)
+	}
+	results := params.AuditEventResults{
+		Events: make([]params.AuditEvent, len(events)),
+	}
+	for i, event := range events {
+		results.Events[i] = params.AuditEvent{
+			Time:          event.Time,
+			User:          event.User,
+			Facade:        event.Facade,
+			Version:       event.Version,
+			Method:        event.Method,
+			Args:          event.Args,
+			Entities:      event.Entities,
+			Error:         event.Error,
+			SourceAddress: event.SourceAddress,
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/auditlog"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/audit"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type auditLogSuite struct {
+	jujutesting.JujuConnSuite
+	api *auditlog.API
+}
+
+var _ = gc.Suite(&auditLogSuite{})
+
+var epoch = time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *auditLogSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = auditlog.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i, method := range []string{"ServiceDeploy", "AddServiceUnits", "DestroyMachines"} {
+		err := s.State.AddAuditEvent(audit.Event{
+			Time:     epoch.Add(time.Duration(i) * time.Minute),
+			User:     s.AdminUserTag(c).String(),
+			Facade:   "Client",
+			Method:   method,
+			Entities: []string{"service-mysql"},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *auditLogSuite) TestNewAPIRequiresClient(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}
+	_, err := auditlog.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *auditLogSuite) TestList(c *gc.C) {
+	results, err := s.api.List(params.AuditEventFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Events, gc.HasLen, 3)
+	c.Assert(results.Events[0], jc.DeepEquals, params.AuditEvent{
+		Time:     epoch,
+		User:     s.AdminUserTag(c).String(),
+		Facade:   "Client",
+		Method:   "ServiceDeploy",
+		Entities: []string{"service-mysql"},
+	})
+}
+
+func (s *auditLogSuite) TestListFiltered(c *gc.C) {
+	after := epoch.Add(time.Minute)
+	results, err := s.api.List(params.AuditEventFilter{
+		Entity: "service-mysql",
+		After:  &after,
+		Limit:  1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Events, gc.HasLen, 1)
+	c.Assert(results.Events[0].Method, gc.Equals, "DestroyMachines")
+}
+
+func (s *auditLogSuite) TestListDeniedForOrdinaryUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: user.UserTag(),
+	}
+	api, err := auditlog.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.List(params.AuditEventFilter{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
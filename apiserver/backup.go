@@ -20,26 +20,38 @@ import (
 	"github.com/juju/juju/state/backups"
 )
 
-var newBackups = func(st *state.State) (backups.Backups, io.Closer) {
-	stor := backups.NewStorage(st)
-	return backups.NewBackups(stor), stor
+var newBackups = func(st *state.State, dataDir string) (backups.Backups, io.Closer, error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	stor, err := backups.OpenStorage(st, cfg, dataDir)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return backups.NewBackups(stor), stor, nil
 }
 
 // backupHandler handles backup requests.
 type backupHandler struct {
-	ctxt httpContext
+	ctxt    httpContext
+	dataDir string
 }
 
 func (h *backupHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	// Validate before authenticate because the authentication is dependent
 	// on the state connection that is determined during the validation.
-	st, _, err := h.ctxt.stateForRequestAuthenticatedUser(req)
+	st, _, err := h.ctxt.stateForRequestAuthenticatedWriteUser(req)
 	if err != nil {
 		h.sendError(resp, err)
 		return
 	}
 
-	backups, closer := newBackups(st)
+	backups, closer, err := newBackups(st, h.dataDir)
+	if err != nil {
+		h.sendError(resp, err)
+		return
+	}
 	defer closer.Close()
 
 	switch req.Method {
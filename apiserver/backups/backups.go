@@ -81,9 +81,16 @@ func extractResourceValue(resources *common.Resources, key string) (string, erro
 	return strRes.String(), nil
 }
 
-var newBackups = func(st *state.State) (backups.Backups, io.Closer) {
-	stor := backups.NewStorage(st)
-	return backups.NewBackups(stor), stor
+var newBackups = func(st *state.State, dataDir string) (backups.Backups, io.Closer, error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	stor, err := backups.OpenStorage(st, cfg, dataDir)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return backups.NewBackups(stor), stor, nil
 }
 
 // ResultFromMetadata updates the result with the information in the
@@ -105,6 +112,10 @@ func ResultFromMetadata(meta *backups.Metadata) params.BackupsMetadataResult {
 		result.Finished = *meta.Finished
 	}
 	result.Notes = meta.Notes
+	result.Scheduled = meta.Scheduled
+	result.Encrypted = meta.Encrypted
+	result.KeyFingerprint = meta.KeyFingerprint
+	result.Excluded = meta.Excluded
 
 	result.Environment = meta.Origin.Environment
 	result.Machine = meta.Origin.Machine
@@ -128,6 +139,10 @@ func MetadataFromResult(result params.BackupsMetadataResult) *backups.Metadata {
 	meta.Origin.Hostname = result.Hostname
 	meta.Origin.Version = result.Version
 	meta.Notes = result.Notes
+	meta.Scheduled = result.Scheduled
+	meta.Encrypted = result.Encrypted
+	meta.KeyFingerprint = result.KeyFingerprint
+	meta.Excluded = result.Excluded
 	meta.SetFileInfo(result.Size, result.Checksum, result.ChecksumFormat)
 	return meta
 }
@@ -6,6 +6,7 @@ package backups
 import (
 	"github.com/juju/errors"
 	"github.com/juju/replicaset"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state/backups"
@@ -16,7 +17,10 @@ var waitUntilReady = replicaset.WaitUntilReady
 // Create is the API method that requests juju to create a new backup
 // of its state.  It returns the metadata for that backup.
 func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataResult, err error) {
-	backupsMethods, closer := newBackups(a.st)
+	backupsMethods, closer, err := newBackups(a.st, a.paths.DataDir)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
 	defer closer.Close()
 
 	session := a.st.MongoSession().Copy()
@@ -40,10 +44,68 @@ func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataRes
 	}
 	meta.Notes = args.Notes
 
-	err = backupsMethods.Create(meta, a.paths, dbInfo)
+	key, err := a.encryptionKey(args.EncryptionKey)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+	meta.Excluded, err = a.excluded(args.Exclude, args.Include)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+
+	err = backupsMethods.Create(meta, a.paths, dbInfo, key)
 	if err != nil {
 		return p, errors.Trace(err)
 	}
 
 	return ResultFromMetadata(meta), nil
 }
+
+// encryptionKey returns the key with which a new backup is encrypted:
+// the given key, if any, or else the environment's default key, if it
+// has one.
+func (a *API) encryptionKey(data []byte) (*backups.Key, error) {
+	if len(data) == 0 {
+		cfg, err := a.st.EnvironConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		data = []byte(cfg.BackupEncryptionKey())
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	key, err := backups.ParseKey(data)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid encryption key")
+	}
+	if key.HasPrivate() {
+		return nil, errors.New("invalid encryption key: expected a public key, got a private key")
+	}
+	return key, nil
+}
+
+// excluded returns the databases and collections whose contents are
+// left out of a new backup: those in the environment's backup-exclude
+// setting, less any that are included, and any others that are
+// excluded.
+func (a *API) excluded(exclude, include []string) ([]string, error) {
+	cfg, err := a.st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	included := set.NewStrings(include...)
+	seen := set.NewStrings()
+	var excluded []string
+	for _, name := range append(cfg.BackupExclude(), exclude...) {
+		if included.Contains(name) || seen.Contains(name) {
+			continue
+		}
+		seen.Add(name)
+		excluded = append(excluded, name)
+	}
+	if err := backups.ValidateExcluded(excluded); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return excluded, nil
+}
@@ -52,3 +52,37 @@ func (s *backupsSuite) TestCreateError(c *gc.C) {
 
 	c.Check(err, gc.ErrorMatches, "failed!")
 }
+
+func (s *backupsSuite) TestCreateExcluded(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"backup-exclude": "logs,juju.statuseshistory",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	fake := s.setBackups(c, nil, "")
+	args := params.BackupsCreateArgs{
+		Exclude: []string{"juju.metrics", "logs"},
+		Include: []string{"juju.statuseshistory"},
+	}
+	result, err := s.api.Create(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(fake.MetaArg.Excluded, jc.DeepEquals, []string{"logs", "juju.metrics"})
+	c.Check(result.Excluded, jc.DeepEquals, []string{"logs", "juju.metrics"})
+}
+
+func (s *backupsSuite) TestCreateExcludedInvalid(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	fake := s.setBackups(c, nil, "")
+	args := params.BackupsCreateArgs{
+		Exclude: []string{"juju"},
+	}
+	_, err := s.api.Create(args)
+
+	c.Check(err, gc.ErrorMatches, `cannot exclude "juju": only .* may be excluded`)
+	c.Check(fake.Calls, gc.HasLen, 0)
+}
@@ -11,7 +11,10 @@ import (
 
 // Info provides the implementation of the API method.
 func (a *API) Info(args params.BackupsInfoArgs) (params.BackupsMetadataResult, error) {
-	backups, closer := newBackups(a.st)
+	backups, closer, err := newBackups(a.st, a.paths.DataDir)
+	if err != nil {
+		return params.BackupsMetadataResult{}, errors.Trace(err)
+	}
 	defer closer.Close()
 
 	meta, file, err := backups.Get(args.ID)
@@ -15,9 +15,19 @@ import (
 
 // Restore implements the server side of Backups.Restore.
 func (a *API) Restore(p params.RestoreArgs) error {
+	var key *backups.Key
+	if len(p.DecryptionKey) > 0 {
+		var err error
+		if key, err = backups.ParseKey(p.DecryptionKey); err != nil {
+			return errors.Annotate(err, "invalid decryption key")
+		}
+	}
 
 	// Get hold of a backup file Reader
-	backup, closer := newBackups(a.st)
+	backup, closer, err := newBackups(a.st, a.paths.DataDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	defer closer.Close()
 
 	// Obtain the address of current machine, where we will be performing restore.
@@ -61,6 +71,20 @@ func (a *API) Restore(p params.RestoreArgs) error {
 		NewInstId:      instanceId,
 		NewInstTag:     machine.Tag(),
 		NewInstSeries:  machine.Series(),
+		DecryptionKey:  key,
+		RecoverTo:      p.RecoverTo,
+	}
+	if !p.RecoverTo.IsZero() {
+		cfg, err := a.st.EnvironConfig()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		oplog, err := backups.OpenOplogStorage(a.st, cfg, a.paths.DataDir)
+		if err != nil {
+			return errors.Annotate(err, "cannot open oplog storage")
+		}
+		defer oplog.Close()
+		restoreArgs.Oplog = oplog
 	}
 	if err := backup.Restore(p.BackupId, restoreArgs); err != nil {
 		return errors.Annotate(err, "restore failed")
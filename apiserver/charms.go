@@ -57,7 +57,7 @@ func (h *charmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *charmsHandler) servePost(w http.ResponseWriter, r *http.Request) error {
-	st, _, err := h.ctxt.stateForRequestAuthenticatedUser(r)
+	st, _, err := h.ctxt.stateForRequestAuthenticatedWriteUser(r)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -412,7 +412,7 @@ func (h *charmsHandler) processGet(r *http.Request, st *state.State) (string, st
 	// Check if the charm archive is already in the cache.
 	if _, err := os.Stat(charmArchivePath); os.IsNotExist(err) {
 		// Download the charm archive and save it to the cache.
-		if err = h.downloadCharm(st, curl, charmArchivePath); err != nil {
+		if err = downloadCharm(st, curl, charmArchivePath); err != nil {
 			return "", "", errors.Annotate(err, "unable to retrieve and save the charm")
 		}
 	} else if err != nil {
@@ -423,7 +423,7 @@ func (h *charmsHandler) processGet(r *http.Request, st *state.State) (string, st
 
 // downloadCharm downloads the given charm name from the provider storage and
 // saves the corresponding zip archive to the given charmArchivePath.
-func (h *charmsHandler) downloadCharm(st *state.State, curl *charm.URL, charmArchivePath string) error {
+func downloadCharm(st *state.State, curl *charm.URL, charmArchivePath string) error {
 	storage := storage.NewStorage(st.EnvironUUID(), st.MongoSession())
 	ch, err := st.Charm(curl)
 	if err != nil {
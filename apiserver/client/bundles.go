@@ -4,14 +4,18 @@
 package client
 
 import (
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/juju/bundlechanges"
 	"github.com/juju/errors"
 	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/yaml.v1"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
 )
 
 // GetBundleChanges returns the list of changes required to deploy the given
@@ -49,3 +53,264 @@ func (c *Client) GetBundleChanges(args params.GetBundleChangesParams) (params.Ge
 	}
 	return results, nil
 }
+
+// ExportBundle returns the YAML for a bundle describing the services,
+// machines and relations in the environment. The result can be deployed
+// with "juju deploy", reproducing the environment elsewhere.
+func (c *Client) ExportBundle() (params.StringResult, error) {
+	var result params.StringResult
+	data, bindings, err := c.bundleData()
+	if err != nil {
+		return result, errors.Annotate(err, "cannot export bundle")
+	}
+	if err := data.Verify(func(s string) error {
+		_, err := constraints.Parse(s)
+		return err
+	}); err != nil {
+		// This should never happen, as the bundle is built from a valid
+		// environment.
+		return result, errors.Annotate(err, "cannot verify exported bundle")
+	}
+	out, err := yaml.Marshal(data)
+	if err != nil {
+		return result, errors.Annotate(err, "cannot marshal bundle")
+	}
+	if len(bindings) > 0 {
+		if out, err = addEndpointBindings(out, bindings); err != nil {
+			return result, errors.Annotate(err, "cannot marshal bundle")
+		}
+	}
+	result.Result = string(out)
+	return result, nil
+}
+
+// bundleData builds the bundle data for the current environment, along
+// with the bindings of the services' endpoints to spaces, keyed by service
+// name.
+func (c *Client) bundleData() (*charm.BundleData, map[string]map[string]string, error) {
+	st := c.api.stateAccessor
+	machines, err := st.AllMachines()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	machinesById := make(map[string]*state.Machine)
+	for _, m := range machines {
+		machinesById[m.Id()] = m
+	}
+	services, err := st.AllServices()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	data := &charm.BundleData{
+		Services: make(map[string]*charm.ServiceSpec),
+		Machines: make(map[string]*charm.MachineSpec),
+	}
+	bindings := make(map[string]map[string]string)
+	for _, service := range services {
+		spec, hosts, err := c.serviceSpec(service)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "service %q", service.Name())
+		}
+		data.Services[service.Name()] = spec
+		if serviceBindings := service.EndpointBindings(); len(serviceBindings) > 0 {
+			bindings[service.Name()] = serviceBindings
+		}
+		for _, id := range hosts {
+			if _, ok := data.Machines[id]; ok {
+				continue
+			}
+			m, ok := machinesById[id]
+			if !ok {
+				return nil, nil, errors.NotFoundf("machine %q", id)
+			}
+			machineSpec, err := c.machineSpec(m)
+			if err != nil {
+				return nil, nil, errors.Annotatef(err, "machine %q", id)
+			}
+			data.Machines[id] = machineSpec
+		}
+	}
+	relations, err := st.AllRelations()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	for _, rel := range relations {
+		eps := rel.Endpoints()
+		if len(eps) != 2 {
+			// Peer relations are established implicitly.
+			continue
+		}
+		data.Relations = append(data.Relations, []string{
+			eps[0].ServiceName + ":" + eps[0].Name,
+			eps[1].ServiceName + ":" + eps[1].Name,
+		})
+	}
+	if len(data.Machines) == 0 {
+		data.Machines = nil
+	}
+	renumberMachines(data)
+	return data, bindings, nil
+}
+
+// addEndpointBindings adds the services' endpoint bindings, keyed by
+// service name, to the YAML bundle content. Each service's bindings go in
+// its "bindings" section, which charm.BundleData does not describe.
+func addEndpointBindings(content []byte, bindings map[string]map[string]string) ([]byte, error) {
+	var doc map[interface{}]interface{}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, errors.Trace(err)
+	}
+	services, _ := doc["services"].(map[interface{}]interface{})
+	for name, serviceBindings := range bindings {
+		if spec, ok := services[name].(map[interface{}]interface{}); ok {
+			spec["bindings"] = serviceBindings
+		}
+	}
+	return yaml.Marshal(doc)
+}
+
+// renumberMachines numbers the machines in the bundle from 0, in the
+// order of their ids in the environment, and updates the services'
+// placement directives to match. A bundle's machine ids only identify
+// machines within it, and the environment's own ids, starting with
+// those of its state servers, mean nothing where the bundle is
+// deployed.
+func renumberMachines(data *charm.BundleData) {
+	ids := make([]int, 0, len(data.Machines))
+	for id := range data.Machines {
+		n, _ := strconv.Atoi(id)
+		ids = append(ids, n)
+	}
+	sort.Ints(ids)
+	newIds := make(map[string]string)
+	machines := make(map[string]*charm.MachineSpec)
+	for i, n := range ids {
+		id := strconv.Itoa(n)
+		newIds[id] = strconv.Itoa(i)
+		machines[newIds[id]] = data.Machines[id]
+	}
+	if len(machines) > 0 {
+		data.Machines = machines
+	}
+	for _, spec := range data.Services {
+		for i, to := range spec.To {
+			prefix, id := "", to
+			if parts := strings.SplitN(to, ":", 2); len(parts) == 2 {
+				prefix, id = parts[0]+":", parts[1]
+			}
+			if newId, ok := newIds[id]; ok {
+				spec.To[i] = prefix + newId
+			}
+		}
+	}
+}
+
+// serviceSpec returns the bundle description of the given service, along
+// with the ids of the top level machines hosting its units.
+func (c *Client) serviceSpec(service *state.Service) (*charm.ServiceSpec, []string, error) {
+	curl, _ := service.CharmURL()
+	spec := &charm.ServiceSpec{
+		Charm:  curl.String(),
+		Expose: service.IsExposed(),
+	}
+	ch, _, err := service.Charm()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	settings, err := service.ConfigSettings()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	options := ch.Config().Options
+	for name, value := range settings {
+		if option, ok := options[name]; ok && option.Default == value {
+			continue
+		}
+		if spec.Options == nil {
+			spec.Options = make(map[string]interface{})
+		}
+		spec.Options[name] = value
+	}
+	cons, err := service.Constraints()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	spec.Constraints = cons.String()
+	annotations, err := c.api.stateAccessor.Annotations(service)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if len(annotations) > 0 {
+		spec.Annotations = annotations
+	}
+	if !service.IsPrincipal() {
+		// Subordinate units follow their principals.
+		return spec, nil, nil
+	}
+
+	units, err := service.AllUnits()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	sort.Sort(unitsByNumber(units))
+	spec.NumUnits = len(units)
+	var hosts []string
+	placed := false
+	for _, unit := range units {
+		id, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			spec.To = append(spec.To, "new")
+			continue
+		} else if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		placed = true
+		// Nested containers cannot be expressed in a bundle, so
+		// containers are always placed on their top level host.
+		host := state.TopParentId(id)
+		hosts = append(hosts, host)
+		if ctype := state.ContainerTypeFromId(id); ctype != "" {
+			spec.To = append(spec.To, string(ctype)+":"+host)
+		} else {
+			spec.To = append(spec.To, host)
+		}
+	}
+	if !placed {
+		spec.To = nil
+	}
+	return spec, hosts, nil
+}
+
+// machineSpec returns the bundle description of the given machine.
+func (c *Client) machineSpec(m *state.Machine) (*charm.MachineSpec, error) {
+	cons, err := m.Constraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	annotations, err := c.api.stateAccessor.Annotations(m)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spec := &charm.MachineSpec{
+		Series:      m.Series(),
+		Constraints: cons.String(),
+	}
+	if len(annotations) > 0 {
+		spec.Annotations = annotations
+	}
+	return spec, nil
+}
+
+// unitsByNumber sorts units of a single service by their unit number.
+type unitsByNumber []*state.Unit
+
+func (u unitsByNumber) Len() int      { return len(u) }
+func (u unitsByNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u unitsByNumber) Less(i, j int) bool {
+	return unitNumber(u[i].Name()) < unitNumber(u[j].Name())
+}
+
+func unitNumber(name string) int {
+	n, _ := strconv.Atoi(name[strings.LastIndex(name, "/")+1:])
+	return n
+}
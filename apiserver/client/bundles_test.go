@@ -4,10 +4,17 @@
 package client_test
 
 import (
+	"strings"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/yaml.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
 )
 
 func (s *serverSuite) TestGetBundleChangesBundleContentError(c *gc.C) {
@@ -103,3 +110,123 @@ func (s *serverSuite) TestGetBundleChangesSuccess(c *gc.C) {
 	}})
 	c.Assert(r.Errors, gc.IsNil)
 }
+
+func (s *serverSuite) TestExportBundle(c *gc.C) {
+	wordpress := s.Factory.MakeService(c, &factory.ServiceParams{
+		Name:  "wordpress",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	mysql := s.Factory.MakeService(c, &factory.ServiceParams{
+		Name:  "mysql",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	err := wordpress.UpdateConfigSettings(charm.Settings{"blog-title": "Exported"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress.SetAnnotations(map[string]string{"gui-x": "10"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	machine := s.Factory.MakeMachine(c, &factory.MachineParams{Series: "quantal"})
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, machine.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Factory.MakeUnit(c, &factory.UnitParams{Service: wordpress, Machine: machine})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Service: mysql, Machine: container})
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.Factory.MakeRelation(c, &factory.RelationParams{Endpoints: eps})
+
+	result, err := s.client.ExportBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := charm.ReadBundleData(strings.NewReader(result.Result))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Relations, gc.HasLen, 1)
+	c.Assert(data.Relations[0], jc.SameContents, []string{"wordpress:db", "mysql:server"})
+	data.Relations = nil
+	wordpressURL, _ := wordpress.CharmURL()
+	mysqlURL, _ := mysql.CharmURL()
+	c.Assert(data, jc.DeepEquals, &charm.BundleData{
+		Services: map[string]*charm.ServiceSpec{
+			"wordpress": {
+				Charm:       wordpressURL.String(),
+				NumUnits:    1,
+				To:          []string{"0"},
+				Options:     map[string]interface{}{"blog-title": "Exported"},
+				Annotations: map[string]string{"gui-x": "10"},
+			},
+			"mysql": {
+				Charm:    mysqlURL.String(),
+				NumUnits: 1,
+				To:       []string{"lxc:0"},
+			},
+		},
+		Machines: map[string]*charm.MachineSpec{
+			"0": {Series: "quantal"},
+		},
+	})
+}
+
+func (s *serverSuite) TestExportBundleRenumbersMachines(c *gc.C) {
+	wordpress := s.Factory.MakeService(c, &factory.ServiceParams{
+		Name:  "wordpress",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	controller := s.Factory.MakeMachine(c, &factory.MachineParams{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobManageEnviron, state.JobHostUnits},
+	})
+	other := s.Factory.MakeMachine(c, &factory.MachineParams{Series: "trusty"})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Service: wordpress, Machine: other})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Service: wordpress, Machine: controller})
+
+	result, err := s.client.ExportBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := charm.ReadBundleData(strings.NewReader(result.Result))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Services["wordpress"].To, jc.DeepEquals, []string{"1", "0"})
+	c.Assert(data.Machines, jc.DeepEquals, map[string]*charm.MachineSpec{
+		"0": {Series: "quantal"},
+		"1": {Series: "trusty"},
+	})
+}
+
+func (s *serverSuite) TestExportBundleOmitsDefaultSettings(c *gc.C) {
+	wordpress := s.Factory.MakeService(c, &factory.ServiceParams{
+		Name:  "wordpress",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	err := wordpress.UpdateConfigSettings(charm.Settings{"blog-title": "My Title"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.client.ExportBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := charm.ReadBundleData(strings.NewReader(result.Result))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Services["wordpress"].Options, gc.HasLen, 0)
+	c.Assert(data.Machines, gc.HasLen, 0)
+}
+
+func (s *serverSuite) TestExportBundleEndpointBindings(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := s.Factory.MakeService(c, &factory.ServiceParams{
+		Name:  "wordpress",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	err = wordpress.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.client.ExportBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = charm.ReadBundleData(strings.NewReader(result.Result))
+	c.Assert(err, jc.ErrorIsNil)
+	var doc struct {
+		Services map[string]struct {
+			Bindings map[string]string `yaml:"bindings"`
+		} `yaml:"services"`
+	}
+	err = yaml.Unmarshal([]byte(result.Result), &doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc.Services["wordpress"].Bindings, jc.DeepEquals, map[string]string{"db": "internal"})
+}
@@ -813,7 +813,7 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 		}
 		switch arg.Action {
 		case params.AddEnvUser:
-			_, err := c.api.stateAccessor.AddEnvironmentUser(user, createdBy, "")
+			err := c.shareEnvironment(user, createdBy, arg.Access)
 			if err != nil {
 				err = errors.Annotate(err, "could not share environment")
 				result.Results[i].Error = common.ServerError(err)
@@ -831,6 +831,25 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 	return result, nil
 }
 
+// shareEnvironment gives the user the requested level of access to
+// the environment. Sharing the environment again with an existing
+// environment user changes their access level, if one is specified.
+func (c *Client) shareEnvironment(user, createdBy names.UserTag, access params.EnvironmentAccess) error {
+	stateAccess := state.EnvironmentWriteAccess
+	if access != "" {
+		stateAccess = state.EnvironmentAccess(access)
+	}
+	_, err := c.api.stateAccessor.AddEnvironmentUser(user, createdBy, "", stateAccess)
+	if !errors.IsAlreadyExists(err) || access == "" {
+		return errors.Trace(err)
+	}
+	envUser, err := c.api.stateAccessor.EnvironmentUser(user)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(envUser.SetAccess(stateAccess))
+}
+
 // EnvUserInfo returns information on all users in the environment.
 func (c *Client) EnvUserInfo() (params.EnvUserInfoResults, error) {
 	var results params.EnvUserInfoResults
@@ -860,6 +879,7 @@ func (c *Client) EnvUserInfo() (params.EnvUserInfoResults, error) {
 				CreatedBy:      user.CreatedBy(),
 				DateCreated:    user.DateCreated(),
 				LastConnection: lastConn,
+				Access:         params.EnvironmentAccess(user.Access()),
 			},
 		})
 	}
@@ -175,6 +175,7 @@ func (s *serverSuite) TestEnvUsersInfo(c *gc.C) {
 		r.info.CreatedBy = owner.UserName()
 		r.info.DateCreated = r.user.DateCreated()
 		r.info.LastConnection = lastConnPointer(c, r.user)
+		r.info.Access = params.EnvironmentWriteAccess
 		expected.Results = append(expected.Results, params.EnvUserInfoResult{Result: r.info})
 	}
 
@@ -334,6 +335,59 @@ func (s *serverSuite) TestShareEnvironmentAddUserTwice(c *gc.C) {
 	c.Assert(envUser.UserName(), gc.Equals, user.UserTag().Canonical())
 }
 
+func (s *serverSuite) TestShareEnvironmentReadAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.Tag().String(),
+			Action:  params.AddEnvUser,
+			Access:  params.EnvironmentReadAccess,
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.IsNil)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+}
+
+func (s *serverSuite) TestShareEnvironmentChangeAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.Tag().String(),
+			Action:  params.AddEnvUser,
+			Access:  params.EnvironmentReadAccess,
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.IsNil)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+}
+
+func (s *serverSuite) TestShareEnvironmentInvalidAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.Tag().String(),
+			Action:  params.AddEnvUser,
+			Access:  "superuser",
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `could not share environment: environment access "superuser" not valid`)
+
+	_, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *serverSuite) TestShareEnvironmentInvalidTags(c *gc.C) {
 	for _, testParam := range []struct {
 		tag      string
@@ -0,0 +1,59 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// ListPayloads returns the payloads registered by the units in the
+// environment. If patterns are given, only payloads whose class or one
+// of whose labels matches a pattern are returned.
+func (c *Client) ListPayloads(args params.PayloadListArgs) (params.PayloadListResults, error) {
+	var result params.PayloadListResults
+	payloads, err := c.api.stateAccessor.AllPayloads()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, payload := range payloads {
+		if !payloadMatches(payload, args.Patterns) {
+			continue
+		}
+		result.Results = append(result.Results, params.Payload{
+			Class:   payload.Class,
+			Type:    payload.Type,
+			ID:      payload.ID,
+			Status:  payload.Status,
+			Labels:  payload.Labels,
+			Unit:    payload.Unit,
+			Machine: payload.Machine,
+		})
+	}
+	return result, nil
+}
+
+// payloadMatches reports whether the payload's class or one of its
+// labels matches one of the patterns, ignoring case. All payloads
+// match when there are no patterns.
+func payloadMatches(payload state.Payload, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if strings.EqualFold(pattern, payload.Class) {
+			return true
+		}
+		for _, label := range payload.Labels {
+			if strings.EqualFold(pattern, label) {
+				return true
+			}
+		}
+	}
+	return false
+}
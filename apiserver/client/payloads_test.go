@@ -0,0 +1,72 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+func (s *serverSuite) TestListPayloads(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, nil)
+	err := unit.RegisterPayload(state.Payload{
+		Class:  "webapp",
+		Type:   "docker",
+		ID:     "abc123",
+		Labels: []string{"Frontend"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.RegisterPayload(state.Payload{
+		Class: "cache",
+		Type:  "docker",
+		ID:    "def456",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+
+	webapp := params.Payload{
+		Class:   "webapp",
+		Type:    "docker",
+		ID:      "abc123",
+		Status:  "running",
+		Labels:  []string{"Frontend"},
+		Unit:    unit.Name(),
+		Machine: machineId,
+	}
+	cache := params.Payload{
+		Class:   "cache",
+		Type:    "docker",
+		ID:      "def456",
+		Status:  "running",
+		Unit:    unit.Name(),
+		Machine: machineId,
+	}
+	for i, t := range []struct {
+		patterns []string
+		expected []params.Payload
+	}{
+		{nil, []params.Payload{cache, webapp}},
+		{[]string{"webapp"}, []params.Payload{webapp}},
+		{[]string{"frontend"}, []params.Payload{webapp}},
+		{[]string{"cache", "frontend"}, []params.Payload{cache, webapp}},
+		{[]string{"missing"}, nil},
+	} {
+		c.Logf("test %d: %v", i, t.patterns)
+		result, err := s.client.ListPayloads(params.PayloadListArgs{Patterns: t.patterns})
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(result.Results, jc.DeepEquals, t.expected)
+	}
+}
+
+func (s *serverSuite) TestListPayloadsEmpty(c *gc.C) {
+	s.Factory.MakeUnit(c, &factory.UnitParams{})
+	result, err := s.client.ListPayloads(params.PayloadListArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 0)
+}
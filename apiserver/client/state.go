@@ -10,6 +10,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/leadership"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/version"
@@ -56,11 +57,15 @@ type stateInterface interface {
 	Charm(*charm.URL) (*state.Charm, error)
 	LatestPlaceholderCharm(*charm.URL) (*state.Charm, error)
 	AddRelation(...state.Endpoint) (*state.Relation, error)
-	AddEnvironmentUser(user, createdBy names.UserTag, displayName string) (*state.EnvironmentUser, error)
+	AddEnvironmentUser(user, createdBy names.UserTag, displayName string, access state.EnvironmentAccess) (*state.EnvironmentUser, error)
+	EnvironmentUser(names.UserTag) (*state.EnvironmentUser, error)
 	RemoveEnvironmentUser(names.UserTag) error
 	Watch() *state.Multiwatcher
 	AbortCurrentUpgrade() error
 	APIHostPorts() ([][]network.HostPort, error)
+	BackupStatus() (state.StatusInfo, error)
+	LeadershipChecker() leadership.Checker
+	AllPayloads() ([]state.Payload, error)
 }
 
 type stateShim struct {
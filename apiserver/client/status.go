@@ -105,6 +105,10 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 
 	logger.Debugf("Services: %v", context.services)
 
+	// Workload versions are found before filtering, so that the
+	// service's version is shown even if its leader is filtered out.
+	context.workloadVersions = fetchWorkloadVersions(c.api.stateAccessor, context.units)
+
 	if len(args.Patterns) > 0 {
 		predicate := BuildPredicateFor(args.Patterns)
 
@@ -180,10 +184,15 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 	if err != nil {
 		return noStatus, errors.Annotate(err, "cannot determine if there is a new tools version available")
 	}
+	backupsError, err := c.backupsError()
+	if err != nil {
+		return noStatus, errors.Annotate(err, "cannot determine backup status")
+	}
 
 	return params.FullStatus{
 		EnvironmentName:  cfg.Name(),
 		AvailableVersion: newToolsVersion,
+		BackupsError:     backupsError,
 		Machines:         processMachines(context.machines),
 		Services:         context.processServices(),
 		Networks:         context.processNetworks(),
@@ -191,6 +200,21 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 	}, nil
 }
 
+// backupsError returns the message of the error with which the last
+// scheduled backup failed, if it did.
+func (c *Client) backupsError() (string, error) {
+	info, err := c.api.stateAccessor.BackupStatus()
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	if info.Status != state.StatusError {
+		return "", nil
+	}
+	return info.Message, nil
+}
+
 // newToolsVersionAvailable will return a string representing a tools
 // version only if the latest check is newer than current tools.
 func (c *Client) newToolsVersionAvailable() (string, error) {
@@ -242,6 +266,9 @@ type statusContext struct {
 	units        map[string]map[string]*state.Unit
 	networks     map[string]*state.Network
 	latestCharms map[charm.URL]string
+	// workloadVersions: service name -> workload version of the
+	// service's leader unit
+	workloadVersions map[string]string
 }
 
 // fetchMachines returns a map from top level machine id to machines, where machines[0] is the host
@@ -323,6 +350,30 @@ func fetchAllServicesAndUnits(
 	return svcMap, unitMap, latestCharms, nil
 }
 
+// fetchWorkloadVersions returns a map from service name to the workload
+// version reported by the service's leader unit. Services whose leader
+// has not reported a version are omitted.
+func fetchWorkloadVersions(st stateInterface, units map[string]map[string]*state.Unit) map[string]string {
+	checker := st.LeadershipChecker()
+	versions := make(map[string]string)
+	for serviceName, svcUnitMap := range units {
+		for unitName, unit := range svcUnitMap {
+			version := unit.WorkloadVersion()
+			if version == "" {
+				// Only units reporting a version need be checked
+				// for leadership.
+				continue
+			}
+			token := checker.LeadershipCheck(serviceName, unitName)
+			if err := token.Check(nil); err == nil {
+				versions[serviceName] = version
+				break
+			}
+		}
+	}
+	return versions
+}
+
 // fetchUnitMachineIds returns a set of IDs for machines that
 // the specified units reside on, and those machines' ancestors.
 func fetchUnitMachineIds(units map[string]map[string]*state.Unit) (set.Strings, error) {
@@ -433,6 +484,8 @@ func makeMachineStatus(machine *state.Machine) (status params.MachineStatus) {
 	status.Jobs = paramsJobsFromJobs(machine.Jobs())
 	status.WantsVote = machine.WantsVote()
 	status.HasVote = machine.HasVote()
+	status.UpgradeSeriesStatus = params.UpgradeSeriesStatus(machine.UpgradeSeriesStatus())
+	status.UpgradeSeriesTarget = machine.UpgradeSeriesTarget()
 	instid, err := machine.InstanceId()
 	if err == nil {
 		status.InstanceId = instid
@@ -568,6 +621,7 @@ func (context *statusContext) processService(service *state.Service) (status par
 	status.Charm = serviceCharmURL.String()
 	status.Exposed = service.IsExposed()
 	status.Life = processLife(service)
+	status.WorkloadVersion = context.workloadVersions[service.Name()]
 
 	latestCharm, ok := context.latestCharms[*serviceCharmURL.WithRevision(-1)]
 	if ok && latestCharm != serviceCharmURL.String() {
@@ -671,6 +725,7 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 	if serviceCharm != "" && curl != nil && curl.String() != serviceCharm {
 		result.Charm = curl.String()
 	}
+	result.WorkloadVersion = unit.WorkloadVersion()
 	processUnitAndAgentStatus(unit, &result)
 
 	if subUnits := unit.SubordinateNames(); len(subUnits) > 0 {
@@ -4,6 +4,8 @@
 package client_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -164,3 +166,24 @@ func (s *statusUnitTestSuite) TestMeterStatus(c *gc.C) {
 		}
 	}
 }
+
+func (s *statusUnitTestSuite) TestWorkloadVersion(c *gc.C) {
+	service := s.MakeService(c, nil)
+	leader := s.MakeUnit(c, &factory.UnitParams{Service: service})
+	other := s.MakeUnit(c, &factory.UnitParams{Service: service})
+	err := leader.SetWorkloadVersion("4.2")
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.SetWorkloadVersion("4.3")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.LeadershipClaimer().ClaimLeadership(service.Name(), leader.Name(), time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	status, err := client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	serviceStatus, ok := status.Services[service.Name()]
+	c.Assert(ok, jc.IsTrue)
+	c.Check(serviceStatus.WorkloadVersion, gc.Equals, "4.2")
+	c.Check(serviceStatus.Units[leader.Name()].WorkloadVersion, gc.Equals, "4.2")
+	c.Check(serviceStatus.Units[other.Name()].WorkloadVersion, gc.Equals, "4.3")
+}
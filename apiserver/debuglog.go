@@ -8,8 +8,10 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -65,9 +67,20 @@ func newDebugLogHandler(
 //   limit -> uint - show *at most* this many lines
 //   backlog -> uint
 //      - go back this many lines from the end before starting to filter
-//      - has no meaning if 'replay' is true
+//      - has no meaning if 'replay' is true, or if startTime or endTime is set
 //   level -> string one of [TRACE, DEBUG, INFO, WARNING, ERROR]
 //   replay -> string - one of [true, false], if true, start the file from the start
+//   messageRegex -> string - only show lines whose message matches this regular expression
+//   startTime -> string - RFC3339 time; only show lines logged at or after this time
+//   endTime -> string - RFC3339 time; only show lines logged before this time
+//      - once all such lines have been sent, the socket is closed
+//   location -> string - only show lines logged from this "file:line", or from
+//      any line in this file if no line number is given
+//   format -> string - one of [text, json], if json, each line is sent as a
+//      JSON-encoded params.LogMessage
+//
+// The messageRegex, startTime, endTime, location and format arguments
+// are only supported when logging to the database.
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server := websocket.Server{
 		Handler: func(conn *websocket.Conn) {
@@ -150,6 +163,21 @@ type debugLogParams struct {
 	excludeEntity []string
 	includeModule []string
 	excludeModule []string
+	messageRegex  string
+	startTime     time.Time
+	endTime       time.Time
+	location      string
+	jsonFormat    bool
+}
+
+// hasStructuredFilters returns whether any of the parameters that
+// require structured log records, rather than lines of text, are set.
+func (p *debugLogParams) hasStructuredFilters() bool {
+	return p.messageRegex != "" ||
+		!p.startTime.IsZero() ||
+		!p.endTime.IsZero() ||
+		p.location != "" ||
+		p.jsonFormat
 }
 
 func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
@@ -189,6 +217,44 @@ func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
 		params.filterLevel = level
 	}
 
+	if value := queryMap.Get("messageRegex"); value != "" {
+		if _, err := regexp.Compile(value); err != nil {
+			return nil, errors.Errorf("messageRegex value %q is not a valid regular expression: %v", value, err)
+		}
+		params.messageRegex = value
+	}
+
+	if value := queryMap.Get("startTime"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, errors.Errorf("startTime value %q is not a valid RFC3339 time", value)
+		}
+		params.startTime = t
+	}
+
+	if value := queryMap.Get("endTime"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, errors.Errorf("endTime value %q is not a valid RFC3339 time", value)
+		}
+		params.endTime = t
+	}
+
+	if !params.startTime.IsZero() && !params.endTime.IsZero() && !params.startTime.Before(params.endTime) {
+		return nil, errors.Errorf("startTime %q must be before endTime %q",
+			queryMap.Get("startTime"), queryMap.Get("endTime"))
+	}
+
+	params.location = queryMap.Get("location")
+
+	switch value := queryMap.Get("format"); value {
+	case "", "text":
+	case "json":
+		params.jsonFormat = true
+	default:
+		return nil, errors.Errorf("format value %q is not one of %q, %q", value, "text", "json")
+	}
+
 	params.includeEntity = queryMap["includeEntity"]
 	params.excludeEntity = queryMap["excludeEntity"]
 	params.includeModule = queryMap["includeModule"]
@@ -4,12 +4,14 @@
 package apiserver
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
 )
 
@@ -40,9 +42,11 @@ func handleDebugLogDBRequest(
 				return errors.Annotate(tailer.Err(), "tailer stopped")
 			}
 
-			line := formatLogRecord(rec)
-			_, err := socket.Write([]byte(line))
+			line, err := formatLogRecord(rec, reqParams.jsonFormat)
 			if err != nil {
+				return errors.Trace(err)
+			}
+			if _, err := socket.Write(line); err != nil {
 				return errors.Annotate(err, "sending failed")
 			}
 
@@ -64,14 +68,40 @@ func makeLogTailerParams(reqParams *debugLogParams) *state.LogTailerParams {
 		ExcludeEntity: reqParams.excludeEntity,
 		IncludeModule: reqParams.includeModule,
 		ExcludeModule: reqParams.excludeModule,
+		StartTime:     reqParams.startTime,
+		EndTime:       reqParams.endTime,
+		MessageRegex:  reqParams.messageRegex,
+		Location:      reqParams.location,
 	}
-	if reqParams.fromTheStart {
+	if reqParams.fromTheStart || !reqParams.startTime.IsZero() || !reqParams.endTime.IsZero() {
+		// A time window selects the lines to send by itself, so the
+		// backlog doesn't apply.
 		params.InitialLines = 0
 	}
 	return params
 }
 
-func formatLogRecord(r *state.LogRecord) string {
+// formatLogRecord returns the line to send to the client for the
+// given log record, either as text or as JSON.
+func formatLogRecord(r *state.LogRecord, jsonFormat bool) ([]byte, error) {
+	if !jsonFormat {
+		return []byte(formatLogRecordText(r)), nil
+	}
+	line, err := json.Marshal(&params.LogMessage{
+		Entity:   r.Entity,
+		Time:     r.Time.UTC(),
+		Level:    r.Level.String(),
+		Module:   r.Module,
+		Location: r.Location,
+		Message:  r.Message,
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot marshal log record")
+	}
+	return append(line, '\n'), nil
+}
+
+func formatLogRecordText(r *state.LogRecord) string {
 	return fmt.Sprintf("%s: %s %s %s %s %s\n",
 		r.Entity,
 		formatTime(r.Time),
@@ -36,16 +36,18 @@ func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 		includeModule: []string{"bar"},
 		excludeEntity: []string{"baz"},
 		excludeModule: []string{"qux"},
+		messageRegex:  "^hello",
+		location:      "code.go:42",
 	}
 
 	called := false
 	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
 		called = true
 
-		// Start time will be used once the client is extended to send
-		// time range arguments.
 		c.Assert(params.StartTime.IsZero(), jc.IsTrue)
-
+		c.Assert(params.EndTime.IsZero(), jc.IsTrue)
+		c.Assert(params.MessageRegex, gc.Equals, "^hello")
+		c.Assert(params.Location, gc.Equals, "code.go:42")
 		c.Assert(params.MinLevel, gc.Equals, loggo.INFO)
 		c.Assert(params.InitialLines, gc.Equals, 11)
 		c.Assert(params.IncludeEntity, jc.DeepEquals, []string{"foo"})
@@ -86,6 +88,31 @@ func (s *debugLogDBIntSuite) TestParamConversionReplay(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *debugLogDBIntSuite) TestParamConversionTimeWindow(c *gc.C) {
+	reqParams := &debugLogParams{
+		backlog:   10,
+		startTime: time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC),
+		endTime:   time.Date(2015, 10, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	called := false
+	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
+		called = true
+
+		c.Assert(params.StartTime, gc.Equals, time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC))
+		c.Assert(params.EndTime, gc.Equals, time.Date(2015, 10, 2, 0, 0, 0, 0, time.UTC))
+		c.Assert(params.InitialLines, gc.Equals, 0)
+
+		return newFakeLogTailer()
+	})
+
+	stop := make(chan struct{})
+	close(stop) // Stop the request immediately.
+	err := handleDebugLogDBRequest(nil, reqParams, s.sock, stop)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
 func (s *debugLogDBIntSuite) TestFullRequest(c *gc.C) {
 	// Set up a fake log tailer with a 2 log records ready to send.
 	tailer := newFakeLogTailer()
@@ -123,6 +150,33 @@ func (s *debugLogDBIntSuite) TestFullRequest(c *gc.C) {
 	s.assertStops(c, done, tailer)
 }
 
+func (s *debugLogDBIntSuite) TestFullRequestJSON(c *gc.C) {
+	tailer := newFakeLogTailer()
+	tailer.logsCh <- &state.LogRecord{
+		Time:     time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+		Entity:   "machine-99",
+		Module:   "some.where",
+		Location: "code.go:42",
+		Level:    loggo.INFO,
+		Message:  "stuff happened",
+	}
+	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
+		return tailer
+	})
+
+	stop := make(chan struct{})
+	done := s.runRequest(&debugLogParams{jsonFormat: true}, stop)
+
+	s.assertOutput(c, []string{
+		"ok",
+		`{"entity":"machine-99","time":"2015-06-19T15:34:37Z","level":"INFO",` +
+			`"module":"some.where","location":"code.go:42","message":"stuff happened"}` + "\n",
+	})
+
+	close(stop)
+	s.assertStops(c, done, tailer)
+}
+
 func (s *debugLogDBIntSuite) TestRequestStopsWhenTailerStops(c *gc.C) {
 	tailer := newFakeLogTailer()
 	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
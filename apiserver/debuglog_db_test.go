@@ -3,7 +3,19 @@
 
 package apiserver_test
 
-import gc "gopkg.in/check.v1"
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
 
 // debugLogDBSuite runs the common debuglog API tests when the db-log
 // feature flag is enabled. These tests are inherited from
@@ -19,5 +31,33 @@ func (s *debugLogDBSuite) SetUpSuite(c *gc.C) {
 	s.debugLogBaseSuite.SetUpSuite(c)
 }
 
+func (s *debugLogDBSuite) TestTimeWindowIgnoresBacklog(c *gc.C) {
+	// Write more lines into the window than the default backlog.
+	t0 := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	dbLogger := state.NewDbLogger(s.State, names.NewMachineTag("0"))
+	defer dbLogger.Close()
+	for i := 0; i < 15; i++ {
+		err := dbLogger.Log(t0.Add(time.Duration(i)*time.Second), "some.where", "foo.go:99", loggo.INFO, fmt.Sprint("line ", i))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	// One more line after the window.
+	err := dbLogger.Log(t0.Add(time.Minute), "some.where", "foo.go:99", loggo.INFO, "too late")
+	c.Assert(err, jc.ErrorIsNil)
+
+	reader := s.openWebsocket(c, url.Values{
+		"backlog":   {"10"},
+		"startTime": {t0.Format(time.RFC3339)},
+		"endTime":   {t0.Add(30 * time.Second).Format(time.RFC3339)},
+	})
+	errResult := readJSONErrorLine(c, reader)
+	c.Assert(errResult.Error, gc.IsNil)
+	for i := 0; i < 15; i++ {
+		line, err := reader.ReadString('\n')
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(strings.HasSuffix(line, fmt.Sprintf(" line %d\n", i)), jc.IsTrue, gc.Commentf("line %q", line))
+	}
+	s.assertWebsocketClosed(c, reader)
+}
+
 // See debuglog_db_internal_test.go for DB specific unit tests and the
 // featuretests package for an end-to-end integration test.
@@ -12,6 +12,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils/tailer"
@@ -35,6 +36,11 @@ func (h *debugLogFileHandler) handle(
 	socket debugLogSocket,
 	stop <-chan struct{},
 ) error {
+	if params.hasStructuredFilters() {
+		err := errors.NotSupportedf("filtering by message, time or location, and JSON output, when not logging to the database,")
+		socket.sendError(err)
+		return err
+	}
 	stream := newLogFileStream(params)
 
 	// Open log file.
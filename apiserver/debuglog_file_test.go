@@ -30,6 +30,13 @@ func (s *debugLogFileSuite) TestNoLogfile(c *gc.C) {
 	s.assertWebsocketClosed(c, reader)
 }
 
+func (s *debugLogFileSuite) TestStructuredFiltersNotSupported(c *gc.C) {
+	s.ensureLogFile(c)
+	reader := s.openWebsocket(c, url.Values{"format": {"json"}})
+	assertJSONError(c, reader, "filtering by message, time or location, and JSON output, when not logging to the database, not supported")
+	s.assertWebsocketClosed(c, reader)
+}
+
 func (s *debugLogFileSuite) assertLogReader(c *gc.C, reader *bufio.Reader) {
 	s.assertLogFollowing(c, reader)
 	s.writeLogLines(c, logLineCount)
@@ -28,6 +28,35 @@ func (s *debugLogBaseSuite) TestBadParams(c *gc.C) {
 	s.assertWebsocketClosed(c, reader)
 }
 
+func (s *debugLogBaseSuite) TestBadFilterParams(c *gc.C) {
+	for _, test := range []struct {
+		values   url.Values
+		expected string
+	}{{
+		values:   url.Values{"messageRegex": {"foo("}},
+		expected: `messageRegex value "foo\(" is not a valid regular expression: .*`,
+	}, {
+		values:   url.Values{"startTime": {"yesterday"}},
+		expected: `startTime value "yesterday" is not a valid RFC3339 time`,
+	}, {
+		values:   url.Values{"endTime": {"2015-13-01"}},
+		expected: `endTime value "2015-13-01" is not a valid RFC3339 time`,
+	}, {
+		values: url.Values{
+			"startTime": {"2015-10-02T00:00:00Z"},
+			"endTime":   {"2015-10-01T00:00:00Z"},
+		},
+		expected: `startTime "2015-10-02T00:00:00Z" must be before endTime "2015-10-01T00:00:00Z"`,
+	}, {
+		values:   url.Values{"format": {"xml"}},
+		expected: `format value "xml" is not one of "text", "json"`,
+	}} {
+		reader := s.openWebsocket(c, test.values)
+		assertJSONError(c, reader, test.expected)
+		s.assertWebsocketClosed(c, reader)
+	}
+}
+
 func (s *debugLogBaseSuite) TestWithHTTP(c *gc.C) {
 	uri := s.logURL(c, "http", nil).String()
 	s.sendRequest(c, httpRequestParams{
@@ -100,7 +100,7 @@ func TestingApiHandler(c *gc.C, srvSt, st *state.State) (*apiHandler, *common.Re
 		state: srvSt,
 		tag:   names.NewMachineTag("0"),
 	}
-	h, err := newApiHandler(srv, st, nil, nil, st.EnvironUUID())
+	h, err := newApiHandler(srv, st, nil, nil, st.EnvironUUID(), "")
 	c.Assert(err, jc.ErrorIsNil)
 	return h, h.getResources()
 }
@@ -112,6 +112,13 @@ func TestingUpgradingRoot(st *state.State) rpc.MethodFinder {
 	return newUpgradingRoot(r)
 }
 
+// TestingReadOnlyRoot returns a srvRoot limited to the calls that
+// users with read-only access to an environment may make.
+func TestingReadOnlyRoot(st *state.State) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newReadOnlyRoot(r)
+}
+
 // TestingRestrictedApiHandler returns a restricted srvRoot as if accessed
 // from the root of the API path with a recent (verison > 1) login.
 func TestingRestrictedApiHandler(st *state.State) rpc.MethodFinder {
@@ -95,6 +95,20 @@ func (ctxt *httpContext) stateForRequestAuthenticatedUser(r *http.Request) (*sta
 	}
 }
 
+// stateForRequestAuthenticatedWriteUser is like
+// stateForRequestAuthenticatedUser except that it also verifies that
+// the user is allowed to change the environment.
+func (ctxt *httpContext) stateForRequestAuthenticatedWriteUser(r *http.Request) (*state.State, state.Entity, error) {
+	st, entity, err := ctxt.stateForRequestAuthenticatedUser(r)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if isReadOnlyEntity(entity) {
+		return nil, nil, errors.Trace(common.ErrPerm)
+	}
+	return st, entity, nil
+}
+
 // stateForRequestAuthenticatedUser is like stateForRequestAuthenticated
 // except that it also verifies that the authenticated entity is a user.
 func (ctxt *httpContext) stateForRequestAuthenticatedAgent(r *http.Request) (*state.State, state.Entity, error) {
@@ -135,3 +135,64 @@ func (api *MachinerAPI) Jobs(args params.Entities) (params.JobsResults, error) {
 	}
 	return result, nil
 }
+
+// UpgradeSeriesStatus returns the status of the series upgrade of each
+// given machine, and the series it is being upgraded to.
+func (api *MachinerAPI) UpgradeSeriesStatus(args params.Entities) (params.UpgradeSeriesStatusResults, error) {
+	result := params.UpgradeSeriesStatusResults{
+		Results: make([]params.UpgradeSeriesStatusResult, len(args.Entities)),
+	}
+	canRead, err := api.getCanRead()
+	if err != nil {
+		return result, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canRead(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := api.getMachine(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Status = params.UpgradeSeriesStatus(machine.UpgradeSeriesStatus())
+		result.Results[i].ToSeries = machine.UpgradeSeriesTarget()
+	}
+	return result, nil
+}
+
+// StartUnitUpgradeSeriesCompletion records that each given machine's
+// agent services have been updated for the series it is being upgraded
+// to, so that its units can complete the series upgrade.
+func (api *MachinerAPI) StartUnitUpgradeSeriesCompletion(args params.Entities) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canModify, err := api.getCanModify()
+	if err != nil {
+		return results, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canModify(tag) {
+			var m *state.Machine
+			m, err = api.getMachine(tag)
+			if err == nil {
+				err = m.StartUnitUpgradeSeriesCompletion()
+			}
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
@@ -262,3 +262,45 @@ func (s *machinerSuite) TestWatch(c *gc.C) {
 	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
 	wc.AssertNoChange()
 }
+
+func (s *machinerSuite) TestUpgradeSeries(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "machine-1"},
+		{Tag: "machine-0"},
+		{Tag: "machine-42"},
+	}}
+	result, err := s.machiner.UpgradeSeriesStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.UpgradeSeriesStatusResults{
+		Results: []params.UpgradeSeriesStatusResult{
+			{Status: params.UpgradeSeriesNotStarted},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.machine1.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine1.CompleteUpgradeSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.machiner.UpgradeSeriesStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0], gc.DeepEquals, params.UpgradeSeriesStatusResult{
+		Status:   params.UpgradeSeriesCompleteStarted,
+		ToSeries: "trusty",
+	})
+
+	errResults, err := s.machiner.StartUnitUpgradeSeriesCompletion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errResults, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+	err = s.machine1.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine1.Series(), gc.Equals, "trusty")
+	c.Assert(s.machine1.UpgradeSeriesStatus(), gc.Equals, state.UpgradeSeriesNotStarted)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -157,3 +158,60 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 	}
 	return mm.st.AddMachineInsideNewMachine(template, template, p.ContainerType)
 }
+
+// UpgradeSeriesPrepare starts upgrading the series of each of the given
+// machines. The units on each machine run their pre-series-upgrade hooks,
+// after which the machine's operating system can be upgraded.
+func (mm *MachineManagerAPI) UpgradeSeriesPrepare(args params.UpgradeSeriesPrepareArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Args {
+		err := mm.upgradeSeriesPrepare(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) upgradeSeriesPrepare(arg params.UpgradeSeriesPrepareArg) error {
+	if arg.Series == "" {
+		return errors.New("series not specified")
+	}
+	m, err := mm.machine(arg.Entity.Tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return m.PrepareUpgradeSeries(arg.Series)
+}
+
+// UpgradeSeriesComplete records that the operating system of each of the
+// given machines has been upgraded. The machine agents update their
+// services and the machines' series, and the units run their
+// post-series-upgrade hooks.
+func (mm *MachineManagerAPI) UpgradeSeriesComplete(args params.Entities) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		m, err := mm.machine(entity.Tag)
+		if err == nil {
+			err = m.CompleteUpgradeSeries()
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) machine(tag string) (Machine, error) {
+	machineTag, err := names.ParseMachineTag(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return mm.st.Machine(machineTag.Id())
+}
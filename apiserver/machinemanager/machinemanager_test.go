@@ -4,8 +4,7 @@
 package machinemanager_test
 
 import (
-	"errors"
-
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -118,10 +117,68 @@ func (s *MachineManagerSuite) TestAddMachinesStateError(c *gc.C) {
 	c.Assert(s.st.calls, gc.Equals, 1)
 }
 
+func (s *MachineManagerSuite) TestUpgradeSeriesPrepare(c *gc.C) {
+	s.st.upgradeMachines = map[string]*mockMachine{"1": {}}
+	results, err := s.api.UpgradeSeriesPrepare(params.UpgradeSeriesPrepareArgs{
+		Args: []params.UpgradeSeriesPrepareArg{
+			{Entity: params.Entity{Tag: "machine-1"}, Series: "xenial"},
+			{Entity: params.Entity{Tag: "machine-1"}},
+			{Entity: params.Entity{Tag: "machine-2"}, Series: "xenial"},
+			{Entity: params.Entity{Tag: "unit-foo-0"}, Series: "xenial"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "series not specified"}},
+			{Error: &params.Error{Message: `machine 2 not found`, Code: params.CodeNotFound}},
+			{Error: &params.Error{Message: `"unit-foo-0" is not a valid machine tag`}},
+		},
+	})
+	c.Assert(s.st.upgradeMachines["1"].prepared, gc.Equals, "xenial")
+}
+
+func (s *MachineManagerSuite) TestUpgradeSeriesComplete(c *gc.C) {
+	s.st.upgradeMachines = map[string]*mockMachine{"1": {}}
+	results, err := s.api.UpgradeSeriesComplete(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-1"}, {Tag: "machine-2"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "machine 2 not found")
+	c.Assert(s.st.upgradeMachines["1"].completed, jc.IsTrue)
+}
+
 type mockState struct {
-	calls    int
-	machines []state.MachineTemplate
-	err      error
+	calls           int
+	machines        []state.MachineTemplate
+	upgradeMachines map[string]*mockMachine
+	err             error
+}
+
+func (st *mockState) Machine(id string) (machinemanager.Machine, error) {
+	m, ok := st.upgradeMachines[id]
+	if !ok {
+		return nil, errors.NotFoundf("machine %s", id)
+	}
+	return m, nil
+}
+
+type mockMachine struct {
+	prepared  string
+	completed bool
+}
+
+func (m *mockMachine) PrepareUpgradeSeries(series string) error {
+	m.prepared = series
+	return nil
+}
+
+func (m *mockMachine) CompleteUpgradeSeries() error {
+	m.completed = true
+	return nil
 }
 
 func (st *mockState) AddOneMachine(template state.MachineTemplate) (*state.Machine, error) {
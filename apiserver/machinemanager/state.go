@@ -9,6 +9,12 @@ import (
 	"github.com/juju/juju/state"
 )
 
+// Machine represents the state.Machine methods used in this package.
+type Machine interface {
+	PrepareUpgradeSeries(series string) error
+	CompleteUpgradeSeries() error
+}
+
 type stateInterface interface {
 	EnvironConfig() (*config.Config, error)
 	Environment() (*state.Environment, error)
@@ -16,6 +22,7 @@ type stateInterface interface {
 	AddOneMachine(template state.MachineTemplate) (*state.Machine, error)
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
 	AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error)
+	Machine(id string) (Machine, error)
 }
 
 type stateShim struct {
@@ -45,3 +52,11 @@ func (s stateShim) AddMachineInsideNewMachine(template, parentTemplate state.Mac
 func (s stateShim) AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error) {
 	return s.State.AddMachineInsideMachine(template, parentId, containerType)
 }
+
+func (s stateShim) Machine(id string) (Machine, error) {
+	m, err := s.State.Machine(id)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
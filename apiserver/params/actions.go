@@ -30,6 +30,15 @@ const (
 	// ActionRunning is the status of an Action that has been started but
 	// not completed yet.
 	ActionRunning string = "running"
+
+	// ActionCancelling is the status of an Action that was cancelled
+	// while running, and has yet to be stopped by its receiver.
+	ActionCancelling string = "cancelling"
+
+	// ActionWaiting is the status of an Action that has been queued
+	// up, but is waiting for other units of its service to finish
+	// running it, because of the service's limit for the action.
+	ActionWaiting string = "waiting"
 )
 
 // Actions is a slice of Action for bulk requests.
@@ -43,6 +52,8 @@ type Action struct {
 	Receiver   string                 `json:"receiver"`
 	Name       string                 `json:"name"`
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Operation  string                 `json:"operation,omitempty"`
+	Timeout    time.Duration          `json:"timeout,omitempty"`
 }
 
 // ActionResults is a slice of ActionResult for bulk requests.
@@ -59,7 +70,115 @@ type ActionResult struct {
 	Status    string                 `json:"status,omitempty"`
 	Message   string                 `json:"message,omitempty"`
 	Output    map[string]interface{} `json:"output,omitempty"`
-	Error     *Error                 `json:"error,omitempty"`
+	Limit     int                    `json:"limit,omitempty"`
+
+	// Schedule holds the id of the action schedule that enqueued
+	// the Action, if any, and MissedRuns the number of runs of the
+	// schedule missed immediately before it.
+	Schedule   string `json:"schedule,omitempty"`
+	MissedRuns int    `json:"missedruns,omitempty"`
+
+	Error *Error `json:"error,omitempty"`
+}
+
+// ActionLimits holds the limits to set on a number of services'
+// actions.
+type ActionLimits struct {
+	Limits []ActionLimit `json:"limits"`
+}
+
+// ActionLimit holds the maximum number of units of a service that may
+// run an action at once. A limit of 0 means there is no limit.
+type ActionLimit struct {
+	ServiceTag string `json:"servicetag"`
+	Action     string `json:"action"`
+	Limit      int    `json:"limit"`
+}
+
+// ActionFilter holds the criteria by which Actions are selected from
+// the environment's action history. Empty fields do not restrict the
+// Actions selected.
+type ActionFilter struct {
+	// Units and Services restrict the Actions to those queued on the
+	// units with the given tags, or on any unit of the services with
+	// the given tags.
+	Units    []string `json:"units,omitempty"`
+	Services []string `json:"services,omitempty"`
+
+	// Names restricts the Actions to those with the given names.
+	Names []string `json:"names,omitempty"`
+
+	// Statuses restricts the Actions to those with the given
+	// statuses.
+	Statuses []string `json:"statuses,omitempty"`
+
+	// After and Before, if set, restrict the Actions to those
+	// enqueued in the given time range.
+	After  *time.Time `json:"after,omitempty"`
+	Before *time.Time `json:"before,omitempty"`
+
+	// Offset skips the given number of the most recently enqueued
+	// matching Actions, and Limit, if non-zero, restricts the
+	// number of Actions returned.
+	Offset int `json:"offset,omitempty"`
+	Limit  int `json:"limit,omitempty"`
+}
+
+// ActionSchedules holds a number of action schedules.
+type ActionSchedules struct {
+	Schedules []ActionSchedule `json:"schedules"`
+}
+
+// ActionSchedule describes an Action that is enqueued repeatedly, on a
+// unit or on each unit of a service, according to a cron-like
+// schedule. Id, Created, LastRun, NextRun and MissedRuns are ignored
+// when adding a schedule.
+type ActionSchedule struct {
+	Id         string                 `json:"id,omitempty"`
+	Receiver   string                 `json:"receiver"`
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Schedule   string                 `json:"schedule"`
+	Created    time.Time              `json:"created,omitempty"`
+	LastRun    time.Time              `json:"lastrun,omitempty"`
+	NextRun    time.Time              `json:"nextrun,omitempty"`
+	MissedRuns int                    `json:"missedruns,omitempty"`
+}
+
+// ActionScheduleResults is a slice of ActionScheduleResult for bulk
+// requests.
+type ActionScheduleResults struct {
+	Results []ActionScheduleResult `json:"results,omitempty"`
+}
+
+// ActionScheduleResult holds an action schedule, or the error
+// encountered adding it.
+type ActionScheduleResult struct {
+	Schedule *ActionSchedule `json:"schedule,omitempty"`
+	Error    *Error          `json:"error,omitempty"`
+}
+
+// ActionScheduleIds holds the ids of a number of action schedules.
+type ActionScheduleIds struct {
+	Ids []string `json:"ids"`
+}
+
+// Operations holds the ids of a number of operations.
+type Operations struct {
+	Operations []string `json:"operations"`
+}
+
+// OperationResults is a slice of OperationResult for bulk requests.
+type OperationResults struct {
+	Results []OperationResult `json:"results,omitempty"`
+}
+
+// OperationResult holds the Actions that were enqueued together as a
+// single operation.
+type OperationResult struct {
+	Operation string         `json:"operation"`
+	Actions   []ActionResult `json:"actions,omitempty"`
+	Error     *Error         `json:"error,omitempty"`
 }
 
 // ActionsByReceivers wrap a slice of Actions for API calls.
@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// AuditEventFilter holds the parameters used to select the audit
+// events to return from AuditLog.List.
+type AuditEventFilter struct {
+	// User, if set, is the tag of the user whose calls to return.
+	User string `json:"user,omitempty"`
+
+	// Entity, if set, is the tag of an entity that must be
+	// referenced by the returned calls.
+	Entity string `json:"entity,omitempty"`
+
+	// After and Before, if set, restrict the events returned to
+	// the given time range.
+	After  *time.Time `json:"after,omitempty"`
+	Before *time.Time `json:"before,omitempty"`
+
+	// Limit, if non-zero, restricts the number of events returned
+	// to the most recent Limit events.
+	Limit int `json:"limit,omitempty"`
+}
+
+// AuditEvent describes a single API call made by a user.
+type AuditEvent struct {
+	Time          time.Time `json:"time"`
+	User          string    `json:"user"`
+	Facade        string    `json:"facade"`
+	Version       int       `json:"version"`
+	Method        string    `json:"method"`
+	Args          string    `json:"args,omitempty"`
+	Entities      []string  `json:"entities,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	SourceAddress string    `json:"source-address,omitempty"`
+}
+
+// AuditEventResults holds the audit events returned by
+// AuditLog.List, oldest first.
+type AuditEventResults struct {
+	Events []AuditEvent `json:"events"`
+}
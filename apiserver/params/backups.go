@@ -12,6 +12,18 @@ import (
 // BackupsCreateArgs holds the args for the API Create method.
 type BackupsCreateArgs struct {
 	Notes string
+	// EncryptionKey, if set, holds the OpenPGP public key with which
+	// the backup archive is encrypted. If it is not set, the key in
+	// the environment's backup-encryption-key setting, if any, is
+	// used instead.
+	EncryptionKey []byte
+	// Exclude holds the names of databases ("db") and collections
+	// ("db.collection") whose contents are left out of the backup, in
+	// addition to those in the environment's backup-exclude setting.
+	Exclude []string
+	// Include holds the names of databases and collections in the
+	// environment's backup-exclude setting that are backed up anyway.
+	Include []string
 }
 
 // BackupsInfoArgs holds the args for the API Info method.
@@ -59,9 +71,17 @@ type BackupsMetadataResult struct {
 	Size           int64
 	Stored         time.Time // May be zero...
 
-	Started     time.Time
-	Finished    time.Time // May be zero...
-	Notes       string
+	Started   time.Time
+	Finished  time.Time // May be zero...
+	Notes     string
+	Scheduled bool
+	Encrypted bool
+	// KeyFingerprint is the fingerprint of the key with which an
+	// encrypted backup was encrypted.
+	KeyFingerprint string
+	// Excluded holds the names of the databases and collections whose
+	// contents were left out of the backup.
+	Excluded    []string
 	Environment string
 	Machine     string
 	Hostname    string
@@ -72,4 +92,10 @@ type BackupsMetadataResult struct {
 type RestoreArgs struct {
 	// BackupId holds the id of the backup in server if any
 	BackupId string
+	// DecryptionKey holds the OpenPGP private key with which an
+	// encrypted backup is decrypted.
+	DecryptionKey []byte
+	// RecoverTo, if not zero, is the time up to which the database
+	// operations captured after the backup was made are replayed.
+	RecoverTo time.Time
 }
@@ -41,10 +41,22 @@ const (
 	RemoveEnvUser EnvironAction = "remove"
 )
 
+// EnvironmentAccess defines the level of access a user has to an
+// environment.
+type EnvironmentAccess string
+
+// Access levels that a user may be given to an environment.
+const (
+	EnvironmentReadAccess  EnvironmentAccess = "read"
+	EnvironmentWriteAccess EnvironmentAccess = "write"
+)
+
 // ModifyEnvironUser stores the parameters used for a Client.ShareEnvironment call.
+// When adding a user, an empty Access gives the user write access.
 type ModifyEnvironUser struct {
-	UserTag string        `json:"user-tag"`
-	Action  EnvironAction `json:"action"`
+	UserTag string            `json:"user-tag"`
+	Action  EnvironAction     `json:"action"`
+	Access  EnvironmentAccess `json:"access,omitempty"`
 }
 
 // SetEnvironAgentVersion contains the arguments for
@@ -55,11 +67,12 @@ type SetEnvironAgentVersion struct {
 
 // EnvUserInfo holds information on a user.
 type EnvUserInfo struct {
-	UserName       string     `json:"user"`
-	DisplayName    string     `json:"displayname"`
-	CreatedBy      string     `json:"createdby"`
-	DateCreated    time.Time  `json:"datecreated"`
-	LastConnection *time.Time `json:"lastconnection"`
+	UserName       string            `json:"user"`
+	DisplayName    string            `json:"displayname"`
+	CreatedBy      string            `json:"createdby"`
+	DateCreated    time.Time         `json:"datecreated"`
+	LastConnection *time.Time        `json:"lastconnection"`
+	Access         EnvironmentAccess `json:"access"`
 }
 
 // EnvUserInfoResult holds the result of an EnvUserInfo call.
@@ -75,6 +75,18 @@ type StringResults struct {
 	Results []StringResult
 }
 
+// IntResult holds an int or an error.
+type IntResult struct {
+	Error  *Error
+	Result int
+}
+
+// IntResults holds the bulk operation result of an API call
+// that returns an int or an error.
+type IntResults struct {
+	Results []IntResult
+}
+
 // EnvironmentResult holds the result of an API call returning a name and UUID
 // for an environment.
 type EnvironmentResult struct {
@@ -277,6 +289,18 @@ type EntitiesCharmURL struct {
 	Entities []EntityCharmURL
 }
 
+// EntityWorkloadVersion holds the workload version for an entity.
+type EntityWorkloadVersion struct {
+	Tag             string
+	WorkloadVersion string
+}
+
+// EntityWorkloadVersions holds the parameters for setting the workload
+// version for a set of entities.
+type EntityWorkloadVersions struct {
+	Entities []EntityWorkloadVersion
+}
+
 // BytesResult holds the result of an API call that returns a slice
 // of bytes.
 type BytesResult struct {
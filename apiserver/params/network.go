@@ -564,3 +564,38 @@ type Space struct {
 	Subnets []Subnet `json:"Subnets"`
 	Error   *Error   `json:"Error,omitempty"`
 }
+
+// NetworkInfoParams holds a unit tag and the names of the endpoints
+// whose network information is requested.
+type NetworkInfoParams struct {
+	Unit     string   `json:"Unit"`
+	Bindings []string `json:"Bindings"`
+}
+
+// InterfaceAddress holds an address of a network interface, along with
+// the CIDR of the subnet containing it.
+type InterfaceAddress struct {
+	Address string `json:"Address"`
+	CIDR    string `json:"CIDR,omitempty"`
+}
+
+// NetworkInfo describes a network interface of a unit's machine, with
+// the addresses it has in the space bound to an endpoint.
+type NetworkInfo struct {
+	MACAddress    string             `json:"MACAddress,omitempty"`
+	InterfaceName string             `json:"InterfaceName,omitempty"`
+	Addresses     []InterfaceAddress `json:"Addresses"`
+}
+
+// NetworkInfoResult holds the network information for one endpoint,
+// or an error.
+type NetworkInfoResult struct {
+	Info  []NetworkInfo `json:"Info"`
+	Error *Error        `json:"Error,omitempty"`
+}
+
+// NetworkInfoResults holds the network information for each requested
+// endpoint, by endpoint name.
+type NetworkInfoResults struct {
+	Results map[string]NetworkInfoResult `json:"Results"`
+}
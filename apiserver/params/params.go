@@ -181,6 +181,58 @@ type AddMachinesResult struct {
 	Error   *Error `json:"Error"`
 }
 
+// UpgradeSeriesPrepareArgs holds the parameters for starting series
+// upgrades of machines.
+type UpgradeSeriesPrepareArgs struct {
+	Args []UpgradeSeriesPrepareArg `json:"Args"`
+}
+
+// UpgradeSeriesPrepareArg holds the machine to upgrade and the series to
+// upgrade it to.
+type UpgradeSeriesPrepareArg struct {
+	Entity Entity `json:"Entity"`
+	Series string `json:"Series"`
+}
+
+// UpgradeSeriesStatus describes the progress of a machine series upgrade,
+// either for the machine as a whole or for one of its units.
+type UpgradeSeriesStatus string
+
+const (
+	UpgradeSeriesNotStarted       UpgradeSeriesStatus = ""
+	UpgradeSeriesPrepareStarted   UpgradeSeriesStatus = "prepare started"
+	UpgradeSeriesPrepareCompleted UpgradeSeriesStatus = "prepare completed"
+	UpgradeSeriesCompleteStarted  UpgradeSeriesStatus = "complete started"
+	UpgradeSeriesCompleted        UpgradeSeriesStatus = "completed"
+)
+
+// UpgradeSeriesStatusResults holds the series upgrade status of machines
+// or units.
+type UpgradeSeriesStatusResults struct {
+	Results []UpgradeSeriesStatusResult `json:"Results"`
+}
+
+// UpgradeSeriesStatusResult holds the series upgrade status of a machine
+// or unit, and the series the machine is being upgraded to. Status is
+// empty if no upgrade is in progress.
+type UpgradeSeriesStatusResult struct {
+	Status   UpgradeSeriesStatus `json:"Status"`
+	ToSeries string              `json:"ToSeries"`
+	Error    *Error              `json:"Error"`
+}
+
+// SetUpgradeSeriesStatusArgs holds the parameters for recording the
+// progress of units through series upgrades.
+type SetUpgradeSeriesStatusArgs struct {
+	Args []SetUpgradeSeriesStatusArg `json:"Args"`
+}
+
+// SetUpgradeSeriesStatusArg holds the series upgrade status of a unit.
+type SetUpgradeSeriesStatusArg struct {
+	Entity Entity              `json:"Entity"`
+	Status UpgradeSeriesStatus `json:"Status"`
+}
+
 // DestroyMachines holds parameters for the DestroyMachines call.
 type DestroyMachines struct {
 	MachineNames []string
@@ -204,6 +256,10 @@ type ServiceDeploy struct {
 	Placement     []*instance.Placement
 	Networks      []string
 	Storage       map[string]storage.Constraints
+
+	// EndpointBindings maps the service's relation endpoints to the
+	// names of the spaces they are bound to.
+	EndpointBindings map[string]string
 }
 
 // ServiceUpdate holds the parameters for making the ServiceUpdate call.
@@ -771,6 +827,18 @@ type LogRecord struct {
 	Message  string      `json:"x"`
 }
 
+// LogMessage is a single log message as sent by the debug-log API
+// endpoint when JSON output is requested. Each message is sent as a
+// single line of JSON.
+type LogMessage struct {
+	Entity   string    `json:"entity"`
+	Time     time.Time `json:"time"`
+	Level    string    `json:"level"`
+	Module   string    `json:"module"`
+	Location string    `json:"location"`
+	Message  string    `json:"message"`
+}
+
 // GetBundleChangesParams holds parameters for making GetBundleChanges calls.
 type GetBundleChangesParams struct {
 	// BundleDataYAML is the YAML-encoded charm bundle data
@@ -0,0 +1,56 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// Payload describes a workload payload, such as a container or a
+// long-running process, registered by a unit's charm.
+type Payload struct {
+	Class   string   `json:"class"`
+	Type    string   `json:"type"`
+	ID      string   `json:"id"`
+	Status  string   `json:"status"`
+	Labels  []string `json:"labels,omitempty"`
+	Unit    string   `json:"unit"`
+	Machine string   `json:"machine,omitempty"`
+}
+
+// UnitPayload holds a payload to be registered for the unit with the
+// given tag.
+type UnitPayload struct {
+	Tag     string  `json:"tag"`
+	Payload Payload `json:"payload"`
+}
+
+// UnitPayloads holds the arguments for registering payloads.
+type UnitPayloads struct {
+	Payloads []UnitPayload `json:"payloads"`
+}
+
+// UnitPayloadStatus identifies a payload of the unit with the given tag
+// by class and id, along with a status. The status is ignored when
+// unregistering payloads.
+type UnitPayloadStatus struct {
+	Tag    string `json:"tag"`
+	Class  string `json:"class"`
+	ID     string `json:"id"`
+	Status string `json:"status,omitempty"`
+}
+
+// UnitPayloadStatuses holds the arguments for unregistering payloads or
+// setting their statuses.
+type UnitPayloadStatuses struct {
+	Payloads []UnitPayloadStatus `json:"payloads"`
+}
+
+// PayloadListArgs holds the arguments for listing payloads. Only
+// payloads whose class or one of whose labels matches one of the
+// patterns are listed; with no patterns, all payloads are listed.
+type PayloadListArgs struct {
+	Patterns []string `json:"patterns"`
+}
+
+// PayloadListResults holds the payloads that were listed.
+type PayloadListResults struct {
+	Results []Payload `json:"results"`
+}
@@ -0,0 +1,46 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// Resource describes the current revision of a charm resource attached
+// to a service.
+type Resource struct {
+	Service     string    `json:"service"`
+	Name        string    `json:"name"`
+	Path        string    `json:"path"`
+	Revision    int       `json:"revision"`
+	Fingerprint string    `json:"fingerprint"`
+	Size        int64     `json:"size"`
+	Username    string    `json:"username,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// ResourceResult holds a resource or an error.
+type ResourceResult struct {
+	Error    *Error   `json:"error,omitempty"`
+	Resource Resource `json:"resource"`
+}
+
+// ResourceResults holds the bulk operation result of an API call that
+// returns resources.
+type ResourceResults struct {
+	Results []ResourceResult `json:"results"`
+}
+
+// UnitResource identifies a resource of the service of the unit with
+// the given tag.
+type UnitResource struct {
+	Tag  string `json:"tag"`
+	Name string `json:"name"`
+}
+
+// UnitResources holds the arguments for getting the resources of units'
+// services.
+type UnitResources struct {
+	Resources []UnitResource `json:"resources"`
+}
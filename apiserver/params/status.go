@@ -26,6 +26,7 @@ type StatusParams struct {
 type FullStatus struct {
 	EnvironmentName  string
 	AvailableVersion string
+	BackupsError     string
 	Machines         map[string]MachineStatus
 	Services         map[string]ServiceStatus
 	Networks         map[string]NetworkStatus
@@ -56,6 +57,11 @@ type MachineStatus struct {
 	Jobs          []multiwatcher.MachineJob
 	HasVote       bool
 	WantsVote     bool
+
+	// UpgradeSeriesStatus and UpgradeSeriesTarget describe the
+	// progress of the machine's series upgrade, if any.
+	UpgradeSeriesStatus UpgradeSeriesStatus
+	UpgradeSeriesTarget string
 }
 
 // ServiceStatus holds status info about a service.
@@ -71,6 +77,10 @@ type ServiceStatus struct {
 	Units         map[string]UnitStatus
 	MeterStatuses map[string]MeterStatus
 	Status        AgentStatus
+
+	// WorkloadVersion holds the workload version reported by the
+	// service's leader unit.
+	WorkloadVersion string
 }
 
 // MeterStatus represents the meter status of a unit.
@@ -100,6 +110,10 @@ type UnitStatus struct {
 	PublicAddress string
 	Charm         string
 	Subordinates  map[string]UnitStatus
+
+	// WorkloadVersion holds the workload version reported by the
+	// unit's charm.
+	WorkloadVersion string
 }
 
 // TODO(ericsnow) Rename to ServiceNetworksSepcification.
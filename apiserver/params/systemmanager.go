@@ -36,3 +36,10 @@ type EnvironmentBlockInfoList struct {
 type RemoveBlocksArgs struct {
 	All bool `json:"all"`
 }
+
+// SerializedEnvironment holds the description of an environment, as
+// written by the state/description package, that is exported from one
+// system and imported into another.
+type SerializedEnvironment struct {
+	Bytes []byte `json:"bytes"`
+}
@@ -1323,6 +1323,26 @@ func (p *ProvisionerAPI) createOrFetchStateSubnet(subnetInfo network.SubnetInfo)
 	return subnet, nil
 }
 
+// machineBoundSpaces returns the sorted names of the spaces that the
+// endpoints of the services of the machine's units are bound to.
+func machineBoundSpaces(m *state.Machine) ([]string, error) {
+	units, err := m.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spaces := set.NewStrings()
+	for _, unit := range units {
+		service, err := unit.Service()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, space := range service.EndpointBindings() {
+			spaces.Add(space)
+		}
+	}
+	return spaces.SortedValues(), nil
+}
+
 // machineTags returns machine-specific tags to set on the instance.
 func (p *ProvisionerAPI) machineTags(m *state.Machine, jobs []multiwatcher.MachineJob) (map[string]string, error) {
 	// Names of all units deployed to the machine.
@@ -1357,13 +1377,30 @@ func (p *ProvisionerAPI) machineTags(m *state.Machine, jobs []multiwatcher.Machi
 // machineSubnetsAndZones returns a map of subnet provider-specific id
 // to list of availability zone names for that subnet. The result can
 // be empty if there are no spaces constraints specified for the
-// machine, or there's an error fetching them.
+// machine and none of its units' endpoints are bound to spaces, or
+// there's an error fetching them.
 func (p *ProvisionerAPI) machineSubnetsAndZones(m *state.Machine) (map[string][]string, error) {
 	mcons, err := m.Constraints()
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot get machine constraints")
 	}
 	includeSpaces := mcons.IncludeSpaces()
+	source := "constraints"
+	if len(includeSpaces) < 1 {
+		includeSpaces, err = machineBoundSpaces(m)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot get endpoint bindings")
+		}
+		source = "endpoint bindings"
+		// An instance is started in a subnet of only one space,
+		// which cannot satisfy bindings to several.
+		if len(includeSpaces) > 1 {
+			return nil, errors.Errorf(
+				"endpoints of units on machine %q are bound to more than one space (%s); only one is supported",
+				m.Id(), strings.Join(includeSpaces, ", "),
+			)
+		}
+	}
 	if len(includeSpaces) < 1 {
 		// Nothing to do.
 		return nil, nil
@@ -1373,8 +1410,8 @@ func (p *ProvisionerAPI) machineSubnetsAndZones(m *state.Machine) (map[string][]
 	spaceName := includeSpaces[0]
 	if len(includeSpaces) > 1 {
 		logger.Debugf(
-			"using space %q from constraints for machine %q (ignoring remaining: %v)",
-			spaceName, m.Id(), includeSpaces[1:],
+			"using space %q from %s for machine %q (ignoring remaining: %v)",
+			spaceName, source, m.Id(), includeSpaces[1:],
 		)
 	}
 	space, err := p.st.Space(spaceName)
@@ -0,0 +1,220 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/resource"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/storage"
+)
+
+// resourcesHandler handles the upload and download of service resources
+// through HTTPS in the API server.
+type resourcesHandler struct {
+	ctxt    httpContext
+	dataDir string
+}
+
+func (h *resourcesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var err error
+	switch r.Method {
+	case "PUT":
+		err = h.servePut(w, r)
+	case "GET":
+		err = h.serveGet(w, r)
+	default:
+		err = errors.MethodNotAllowedf("unsupported method: %q", r.Method)
+	}
+	if err != nil {
+		logger.Errorf("returning error from %s %s: %s", r.Method, r.URL, errors.Details(err))
+		sendError(w, err)
+	}
+}
+
+// servePut stores the request body as a new revision of the service's
+// resource.
+func (h *resourcesHandler) servePut(w http.ResponseWriter, r *http.Request) error {
+	st, entity, err := h.ctxt.stateForRequestAuthenticatedWriteUser(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	query := r.URL.Query()
+	service, err := st.Service(query.Get(":service"))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	name := query.Get(":name")
+	meta, err := h.serviceResourceMeta(st, service, name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	old, err := service.Resource(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	res, err := h.storeResource(st, service, meta, r.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	res.Username = entity.Tag().Id()
+	stor := storage.NewStorage(st.EnvironUUID(), st.MongoSession())
+	stored, err := service.SetResource(res)
+	if err != nil {
+		if err := stor.Remove(res.StoragePath); err != nil {
+			logger.Errorf("cannot remove unused resource data: %v", err)
+		}
+		return errors.Trace(err)
+	}
+	if old.StoragePath != "" {
+		if err := stor.Remove(old.StoragePath); err != nil && !errors.IsNotFound(err) {
+			logger.Errorf("cannot remove old resource data: %v", err)
+		}
+	}
+	sendStatusAndJSON(w, http.StatusOK, resourceToParams(stored))
+	return nil
+}
+
+// storeResource writes the data read from body to environment storage,
+// returning the resource to record for it.
+func (h *resourcesHandler) storeResource(st *state.State, service *state.Service, meta resource.Meta, body io.Reader) (state.Resource, error) {
+	// The data must be read fully to know its size before it is put
+	// in storage, so it is spooled to a temporary file first.
+	tempFile, err := ioutil.TempFile("", "resource")
+	if err != nil {
+		return state.Resource{}, errors.Annotate(err, "cannot create temp file")
+	}
+	defer tempFile.Close()
+	defer os.Remove(tempFile.Name())
+	fingerprint, size, err := resource.NewFingerprint(io.TeeReader(body, tempFile))
+	if err != nil {
+		return state.Resource{}, errors.Annotate(err, "error processing resource upload")
+	}
+	if _, err := tempFile.Seek(0, 0); err != nil {
+		return state.Resource{}, errors.Annotate(err, "cannot rewind resource upload")
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return state.Resource{}, errors.Trace(err)
+	}
+	storagePath := fmt.Sprintf("resources/%s/%s-%s", service.Name(), meta.Name, uuid)
+	stor := storage.NewStorage(st.EnvironUUID(), st.MongoSession())
+	if err := stor.Put(storagePath, tempFile, size); err != nil {
+		return state.Resource{}, errors.Annotate(err, "cannot store resource")
+	}
+	return state.Resource{
+		Name:        meta.Name,
+		Path:        meta.Path,
+		Fingerprint: fingerprint,
+		Size:        size,
+		StoragePath: storagePath,
+	}, nil
+}
+
+// serveGet sends the data of the current revision of the service's
+// resource. Only users and the service's own units may fetch it.
+func (h *resourcesHandler) serveGet(w http.ResponseWriter, r *http.Request) error {
+	st, entity, err := h.ctxt.stateForRequestAuthenticated(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	query := r.URL.Query()
+	serviceName := query.Get(":service")
+	switch tag := entity.Tag().(type) {
+	case names.UserTag:
+	case names.UnitTag:
+		unitService, err := names.UnitService(tag.Id())
+		if err != nil || unitService != serviceName {
+			return errors.Trace(common.ErrPerm)
+		}
+	default:
+		return errors.Trace(common.ErrPerm)
+	}
+	service, err := st.Service(serviceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	res, err := service.Resource(query.Get(":name"))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	stor := storage.NewStorage(st.EnvironUUID(), st.MongoSession())
+	reader, size, err := stor.Get(res.StoragePath)
+	if err != nil {
+		return errors.Annotate(err, "cannot get resource data")
+	}
+	defer reader.Close()
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", fmt.Sprint(size))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, reader); err != nil {
+		// The headers have already been sent, so all we can do
+		// is log the failure.
+		logger.Errorf("error sending resource %q: %v", res.Name, err)
+	}
+	return nil
+}
+
+// serviceResourceMeta returns the metadata of the named resource, as
+// declared by the service's charm.
+func (h *resourcesHandler) serviceResourceMeta(st *state.State, service *state.Service, name string) (resource.Meta, error) {
+	ch, _, err := service.Charm()
+	if err != nil {
+		return resource.Meta{}, errors.Trace(err)
+	}
+	// The charm archive is kept in the same cache as charm downloads,
+	// so that each upload reads only the archive's metadata.
+	archivePath := filepath.Join(h.dataDir, "charm-get-cache", charm.Quote(ch.URL().String())+".zip")
+	if _, err := os.Stat(archivePath); os.IsNotExist(err) {
+		if err := downloadCharm(st, ch.URL(), archivePath); err != nil {
+			return resource.Meta{}, errors.Annotate(err, "unable to retrieve and save the charm")
+		}
+	} else if err != nil {
+		return resource.Meta{}, errors.Annotate(err, "cannot access the charms cache")
+	}
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return resource.Meta{}, errors.Annotate(err, "cannot open charm archive")
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return resource.Meta{}, errors.Annotate(err, "cannot stat charm archive")
+	}
+	metas, err := resource.ReadArchiveMeta(f, info.Size())
+	if err != nil {
+		return resource.Meta{}, errors.Trace(err)
+	}
+	meta, ok := metas[name]
+	if !ok {
+		return resource.Meta{}, errors.NotFoundf("resource %q in charm %q", name, ch.URL())
+	}
+	return meta, nil
+}
+
+func resourceToParams(res state.Resource) params.Resource {
+	return params.Resource{
+		Service:     res.Service,
+		Name:        res.Name,
+		Path:        res.Path,
+		Revision:    res.Revision,
+		Fingerprint: res.Fingerprint,
+		Size:        res.Size,
+		Username:    res.Username,
+		Timestamp:   res.Timestamp,
+	}
+}
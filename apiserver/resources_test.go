@@ -0,0 +1,179 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/resource"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/storage"
+	"github.com/juju/juju/testing/factory"
+)
+
+type resourcesSuite struct {
+	authHttpSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&resourcesSuite{})
+
+func (s *resourcesSuite) SetUpTest(c *gc.C) {
+	s.authHttpSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "resources", s.AddTestingCharm(c, "resources"))
+}
+
+func (s *resourcesSuite) resourceURI(c *gc.C, service, name string) string {
+	uri := s.baseURL(c)
+	uri.Path = fmt.Sprintf("/environment/%s/services/%s/resources/%s", s.envUUID, service, name)
+	return uri.String()
+}
+
+func (s *resourcesSuite) upload(c *gc.C, name, data string) *http.Response {
+	return s.authRequest(c, httpRequestParams{
+		method:      "PUT",
+		url:         s.resourceURI(c, "resources", name),
+		contentType: "application/octet-stream",
+		body:        strings.NewReader(data),
+	})
+}
+
+func (s *resourcesSuite) assertErrorResponse(c *gc.C, resp *http.Response, expCode int, expError string) {
+	body := assertResponse(c, resp, expCode, params.ContentTypeJSON)
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("body: %s", body))
+	c.Assert(result.Error, gc.NotNil)
+	c.Assert(result.Error, gc.ErrorMatches, expError)
+}
+
+func (s *resourcesSuite) assertUploadResponse(c *gc.C, resp *http.Response) params.Resource {
+	body := assertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	var result params.Resource
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("body: %s", body))
+	return result
+}
+
+func (s *resourcesSuite) TestPUTRequiresAuth(c *gc.C) {
+	resp := s.sendRequest(c, httpRequestParams{method: "PUT", url: s.resourceURI(c, "resources", "software")})
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "no credentials provided")
+}
+
+func (s *resourcesSuite) TestRequiresPUTorGET(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "POST", url: s.resourceURI(c, "resources", "software")})
+	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "POST"`)
+}
+
+func (s *resourcesSuite) TestUpload(c *gc.C) {
+	resp := s.upload(c, "software", "hello")
+	res := s.assertUploadResponse(c, resp)
+	fingerprint, _, err := resource.NewFingerprint(strings.NewReader("hello"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(res.Service, gc.Equals, "resources")
+	c.Check(res.Name, gc.Equals, "software")
+	c.Check(res.Path, gc.Equals, "software.tgz")
+	c.Check(res.Revision, gc.Equals, 1)
+	c.Check(res.Fingerprint, gc.Equals, fingerprint)
+	c.Check(res.Size, gc.Equals, int64(5))
+	c.Check(res.Username, gc.Equals, s.userTag.Id())
+
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.CharmModifiedVersion(), gc.Equals, 1)
+}
+
+func (s *resourcesSuite) TestUploadReplacesData(c *gc.C) {
+	s.assertUploadResponse(c, s.upload(c, "software", "hello"))
+	old, err := s.service.Resource("software")
+	c.Assert(err, jc.ErrorIsNil)
+
+	res := s.assertUploadResponse(c, s.upload(c, "software", "goodbye"))
+	c.Assert(res.Revision, gc.Equals, 2)
+	current, err := s.service.Resource("software")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(current.StoragePath, gc.Not(gc.Equals), old.StoragePath)
+
+	stor := storage.NewStorage(s.State.EnvironUUID(), s.State.MongoSession())
+	_, _, err = stor.Get(old.StoragePath)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *resourcesSuite) TestUploadUsesCharmCache(c *gc.C) {
+	s.assertUploadResponse(c, s.upload(c, "software", "hello"))
+	ch, _, err := s.service.Charm()
+	c.Assert(err, jc.ErrorIsNil)
+	archivePath := filepath.Join(s.DataDir(), "charm-get-cache", charm.Quote(ch.URL().String())+".zip")
+	_, err = os.Stat(archivePath)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Later uploads read the resource metadata from the cached archive.
+	stor := storage.NewStorage(s.State.EnvironUUID(), s.State.MongoSession())
+	err = stor.Remove(ch.StoragePath())
+	c.Assert(err, jc.ErrorIsNil)
+	res := s.assertUploadResponse(c, s.upload(c, "software", "goodbye"))
+	c.Assert(res.Revision, gc.Equals, 2)
+}
+
+func (s *resourcesSuite) TestUploadUnknownResource(c *gc.C) {
+	resp := s.upload(c, "unknown", "hello")
+	s.assertErrorResponse(c, resp, http.StatusNotFound, `resource "unknown" in charm "local:quantal/resources-1" not found`)
+}
+
+func (s *resourcesSuite) TestUploadUnknownService(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{
+		method: "PUT",
+		url:    s.resourceURI(c, "unknown", "software"),
+		body:   strings.NewReader("hello"),
+	})
+	s.assertErrorResponse(c, resp, http.StatusNotFound, `service "unknown" not found`)
+}
+
+func (s *resourcesSuite) TestDownload(c *gc.C) {
+	s.assertUploadResponse(c, s.upload(c, "software", "hello"))
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.resourceURI(c, "resources", "software")})
+	body := assertResponse(c, resp, http.StatusOK, "application/octet-stream")
+	c.Assert(string(body), gc.Equals, "hello")
+}
+
+func (s *resourcesSuite) TestDownloadAsUnit(c *gc.C) {
+	s.assertUploadResponse(c, s.upload(c, "software", "hello"))
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, Password: "password"})
+	resp := s.sendRequest(c, httpRequestParams{
+		tag:      unit.Tag().String(),
+		password: "password",
+		method:   "GET",
+		url:      s.resourceURI(c, "resources", "software"),
+	})
+	body := assertResponse(c, resp, http.StatusOK, "application/octet-stream")
+	c.Assert(string(body), gc.Equals, "hello")
+}
+
+func (s *resourcesSuite) TestDownloadAsOtherServiceUnit(c *gc.C) {
+	s.assertUploadResponse(c, s.upload(c, "software", "hello"))
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Password: "password"})
+	resp := s.sendRequest(c, httpRequestParams{
+		tag:      unit.Tag().String(),
+		password: "password",
+		method:   "GET",
+		url:      s.resourceURI(c, "resources", "software"),
+	})
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "permission denied")
+}
+
+func (s *resourcesSuite) TestDownloadNotAttached(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.resourceURI(c, "resources", "software")})
+	s.assertErrorResponse(c, resp, http.StatusNotFound, `resource "software" of service "resources" not found`)
+}
@@ -5,11 +5,13 @@ package apiserver
 
 import (
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -52,17 +54,20 @@ type apiHandler struct {
 	// path, logins processed with v2 or later will only offer the
 	// user manager and environment manager api endpoints from here.
 	envUUID string
+	// remoteAddr holds the network address of the client.
+	remoteAddr string
 }
 
 var _ = (*apiHandler)(nil)
 
 // newApiHandler returns a new apiHandler.
-func newApiHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, reqNotifier *requestNotifier, envUUID string) (*apiHandler, error) {
+func newApiHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, reqNotifier *requestNotifier, envUUID, remoteAddr string) (*apiHandler, error) {
 	r := &apiHandler{
 		state:            st,
 		resources:        common.NewResources(),
 		rpcConn:          rpcConn,
 		envUUID:          envUUID,
+		remoteAddr:       remoteAddr,
 		mongoUnavailable: &srv.mongoUnavailable,
 	}
 	if err := r.resources.RegisterNamed("machineID", common.StringResource(srv.tag.Id())); err != nil {
@@ -238,6 +243,97 @@ func (r *apiRoot) lookupMethod(rootName string, version int, methodName string)
 	return goType, objMethod, nil
 }
 
+// readOnlyCalls holds the API methods, keyed by facade name, that
+// users with read-only access to an environment may call. Methods on
+// watcher facades may always be called.
+var readOnlyCalls = map[string]set.Strings{
+	"Action": set.NewStrings(
+		"Actions",
+		"FilterActions",
+		"FindActionTagsByPrefix",
+		"ListAll",
+		"ListCompleted",
+		"ListPending",
+		"ListRunning",
+		"Operations",
+		"Schedules",
+		"ServicesCharmActions",
+	),
+	"Annotations": set.NewStrings("Get"),
+	"AuditLog":    set.NewStrings("List"),
+	"Block":       set.NewStrings("List"),
+	"Charms":      set.NewStrings("CharmInfo", "IsMetered", "List"),
+	"Client": set.NewStrings(
+		"APIHostPorts",
+		"AgentVersion",
+		"CharmInfo",
+		"EnvUserInfo",
+		"EnvironmentGet",
+		"EnvironmentInfo",
+		"ExportBundle",
+		"FindTools",
+		"FullStatus",
+		"GetAnnotations",
+		"GetEnvironmentConstraints",
+		"GetServiceConstraints",
+		"ListPayloads",
+		"PrivateAddress",
+		"PublicAddress",
+		"ServiceCharmRelations",
+		"ServiceGet",
+		"Status",
+		"UnitStatusHistory",
+		"WatchAll",
+	),
+	"KeyManager":  set.NewStrings("ListKeys"),
+	"Pinger":      set.NewStrings("Ping", "Stop"),
+	"Spaces":      set.NewStrings("ListSpaces"),
+	"Storage":     set.NewStrings("List", "ListFilesystems", "ListPools", "ListVolumes", "Show"),
+	"Subnets":     set.NewStrings("AllSpaces", "AllZones", "ListSubnets"),
+	"UserManager": set.NewStrings("UserInfo"),
+}
+
+// isReadOnlyCall returns whether the given API method leaves the
+// environment unchanged, and so may be called by users with read-only
+// access to it.
+func isReadOnlyCall(rootName, methodName string) bool {
+	if strings.HasSuffix(rootName, "Watcher") {
+		return true
+	}
+	return readOnlyCalls[rootName].Contains(methodName)
+}
+
+// isReadOnlyEntity returns whether the authenticated entity is an
+// environment user with read-only access to the environment.
+func isReadOnlyEntity(entity state.Entity) bool {
+	envUser, ok := entity.(*environmentUserEntity)
+	return ok && envUser.envUser.ReadOnly()
+}
+
+// readOnlyRoot restricts API calls to those that leave the
+// environment unchanged.
+type readOnlyRoot struct {
+	rpc.MethodFinder
+}
+
+// newReadOnlyRoot returns a new readOnlyRoot.
+func newReadOnlyRoot(finder rpc.MethodFinder) *readOnlyRoot {
+	return &readOnlyRoot{finder}
+}
+
+// FindMethod returns common.ErrPerm for any API call that may change
+// the environment.
+func (r *readOnlyRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if !isReadOnlyCall(rootName, methodName) {
+		return nil, common.ErrPerm
+	}
+	return caller, nil
+}
+
 // AnonRoot dispatches API calls to those available to an anonymous connection
 // which has not logged in.
 type anonRoot struct {
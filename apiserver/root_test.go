@@ -412,3 +412,62 @@ func (r *rootSuite) TestAuthOwner(c *gc.C) {
 
 	c.Check(authorized, jc.IsFalse)
 }
+
+type readOnlyRootSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&readOnlyRootSuite{})
+
+func (r *readOnlyRootSuite) TestReadOnlyMethods(c *gc.C) {
+	root := apiserver.TestingReadOnlyRoot(nil)
+
+	for _, call := range []struct {
+		facade  string
+		version int
+		method  string
+	}{
+		{"Client", 0, "ExportBundle"},
+		{"Client", 0, "FullStatus"},
+		{"Client", 0, "ListPayloads"},
+		{"Client", 0, "UnitStatusHistory"},
+		{"Client", 0, "WatchAll"},
+		{"AllWatcher", 0, "Next"},
+		{"NotifyWatcher", 0, "Stop"},
+		{"Pinger", 0, "Ping"},
+		{"Storage", 1, "List"},
+	} {
+		caller, err := root.FindMethod(call.facade, call.version, call.method)
+		c.Check(err, jc.ErrorIsNil, gc.Commentf("%s.%s", call.facade, call.method))
+		c.Check(caller, gc.NotNil)
+	}
+}
+
+func (r *readOnlyRootSuite) TestFindMutatingMethod(c *gc.C) {
+	root := apiserver.TestingReadOnlyRoot(nil)
+
+	for _, call := range []struct {
+		facade  string
+		version int
+		method  string
+	}{
+		{"Client", 0, "ServiceDeploy"},
+		{"Client", 0, "DestroyMachines"},
+		{"Client", 0, "ShareEnvironment"},
+		{"Service", 1, "ServicesDeploy"},
+		{"Service", 2, "ServicesDeploy"},
+		{"Storage", 1, "CreatePool"},
+	} {
+		caller, err := root.FindMethod(call.facade, call.version, call.method)
+		c.Check(err, gc.Equals, common.ErrPerm, gc.Commentf("%s.%s", call.facade, call.method))
+		c.Check(caller, gc.IsNil)
+	}
+}
+
+func (r *readOnlyRootSuite) TestFindNonExistentMethod(c *gc.C) {
+	root := apiserver.TestingReadOnlyRoot(nil)
+
+	caller, err := root.FindMethod("Foo", 0, "Bar")
+	c.Assert(err, gc.ErrorMatches, "unknown object type \"Foo\"")
+	c.Assert(caller, gc.IsNil)
+}
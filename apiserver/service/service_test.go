@@ -234,6 +234,34 @@ func (s *serviceSuite) TestClientServiceDeployWithStorage(c *gc.C) {
 	})
 }
 
+func (s *serviceSuite) TestClientServiceDeployWithEndpointBindings(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := s.UploadCharm(c, "trusty/wordpress-3", "wordpress")
+
+	args := params.ServiceDeploy{
+		ServiceName:      "service",
+		CharmUrl:         curl.String(),
+		EndpointBindings: map[string]string{"db": "internal"},
+	}
+	results, err := s.serviceApi.ServicesDeploy(params.ServicesDeploy{
+		Services: []params.ServiceDeploy{args}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	svc, err := s.State.Service("service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.EndpointBindings(), jc.DeepEquals, map[string]string{"db": "internal"})
+
+	args.ServiceName = "other"
+	args.EndpointBindings = map[string]string{"db": "missing"}
+	results, err = s.serviceApi.ServicesDeploy(params.ServicesDeploy{
+		Services: []params.ServiceDeploy{args}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), gc.ErrorMatches, `.*space "missing" not found`)
+}
+
 func (s *serviceSuite) TestClientServiceDeployWithInvalidStoragePool(c *gc.C) {
 	setupStoragePool(c, s.State)
 	curl, _ := s.UploadCharm(c, "utopic/storage-block-0", "storage-block")
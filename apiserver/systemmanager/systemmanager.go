@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/description"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.systemmanager")
@@ -30,6 +31,8 @@ type SystemManager interface {
 	AllEnvironments() (params.UserEnvironmentList, error)
 	DestroySystem(args params.DestroySystemArgs) error
 	EnvironmentConfig() (params.EnvironmentConfigResults, error)
+	ExportEnvironment(args params.Entity) (params.SerializedEnvironment, error)
+	ImportEnvironment(args params.SerializedEnvironment) (params.Environment, error)
 	ListBlockedEnvironments() (params.EnvironmentBlockInfoList, error)
 	RemoveBlocks(args params.RemoveBlocksArgs) error
 	WatchAllEnvs() (params.AllWatcherId, error)
@@ -252,6 +255,53 @@ func (s *SystemManagerAPI) EnvironmentConfig() (params.EnvironmentConfigResults,
 	return result, nil
 }
 
+// ExportEnvironment returns a description of the given environment and
+// everything in it, from which ImportEnvironment can recreate the
+// environment in another system.
+func (s *SystemManagerAPI) ExportEnvironment(args params.Entity) (params.SerializedEnvironment, error) {
+	result := params.SerializedEnvironment{}
+	tag, err := names.ParseEnvironTag(args.Tag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	st, err := s.state.ForEnviron(tag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	defer st.Close()
+
+	env, err := st.Export()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	bytes, err := description.Serialize(env)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Bytes = bytes
+	return result, nil
+}
+
+// ImportEnvironment creates a new environment in the system from the
+// given description, as returned by ExportEnvironment.
+func (s *SystemManagerAPI) ImportEnvironment(args params.SerializedEnvironment) (params.Environment, error) {
+	result := params.Environment{}
+	desc, err := description.Deserialize(args.Bytes)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	env, st, err := s.state.Import(desc)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	defer st.Close()
+
+	result.Name = env.Name()
+	result.UUID = env.UUID()
+	result.OwnerTag = env.Owner().String()
+	return result, nil
+}
+
 // RemoveBlocks removes all the blocks in the system.
 func (s *SystemManagerAPI) RemoveBlocks(args params.RemoveBlocksArgs) error {
 	if !args.All {
@@ -9,6 +9,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver"
@@ -18,6 +19,7 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/description"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
@@ -83,7 +85,7 @@ func (s *systemManagerSuite) TestAllEnvironments(c *gc.C) {
 	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{
 		Name: "user", Owner: remoteUserTag})
 	defer st.Close()
-	st.AddEnvironmentUser(admin.UserTag(), remoteUserTag, "Foo Bar")
+	st.AddEnvironmentUser(admin.UserTag(), remoteUserTag, "Foo Bar", state.EnvironmentWriteAccess)
 
 	s.Factory.MakeEnvironment(c, &factory.EnvParams{
 		Name: "no-access", Owner: remoteUserTag}).Close()
@@ -186,6 +188,52 @@ func (s *systemManagerSuite) TestRemoveBlocksNotAll(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "not supported")
 }
 
+func (s *systemManagerSuite) TestExportEnvironment(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{
+		Name: "test"})
+	defer st.Close()
+	factory.NewFactory(st).MakeMachine(c, nil)
+
+	result, err := s.systemManager.ExportEnvironment(params.Entity{Tag: st.EnvironTag().String()})
+	c.Assert(err, jc.ErrorIsNil)
+	env, err := description.Deserialize(result.Bytes)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Name(), gc.Equals, "test")
+	c.Assert(env.UUID(), gc.Equals, st.EnvironUUID())
+	c.Assert(env.Machines, gc.HasLen, 1)
+}
+
+func (s *systemManagerSuite) TestExportEnvironmentInvalidTag(c *gc.C) {
+	_, err := s.systemManager.ExportEnvironment(params.Entity{Tag: "machine-0"})
+	c.Assert(err, gc.ErrorMatches, `"machine-0" is not a valid environment tag`)
+}
+
+func (s *systemManagerSuite) TestImportEnvironment(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{
+		Name: "test"})
+	defer st.Close()
+	desc, err := st.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	desc.Config["uuid"] = uuid.String()
+	desc.Config["name"] = "imported"
+	bytes, err := description.Serialize(desc)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.systemManager.ImportEnvironment(params.SerializedEnvironment{Bytes: bytes})
+	c.Assert(err, jc.ErrorIsNil)
+	imported, err := s.State.GetEnvironment(names.NewEnvironTag(uuid.String()))
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkEnvironmentMatches(c, result, imported)
+	c.Assert(result.Name, gc.Equals, "imported")
+}
+
+func (s *systemManagerSuite) TestImportEnvironmentInvalid(c *gc.C) {
+	_, err := s.systemManager.ImportEnvironment(params.SerializedEnvironment{Bytes: []byte("version: 0")})
+	c.Assert(err, gc.ErrorMatches, "environment description version 0 not supported")
+}
+
 func (s *systemManagerSuite) TestWatchAllEnvs(c *gc.C) {
 	watcherId, err := s.systemManager.WatchAllEnvs()
 	c.Assert(err, jc.ErrorIsNil)
@@ -60,7 +60,7 @@ func (h *toolsDownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 func (h *toolsUploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Validate before authenticate because the authentication is dependent
 	// on the state connection that is determined during the validation.
-	st, _, err := h.ctxt.stateForRequestAuthenticatedUser(r)
+	st, _, err := h.ctxt.stateForRequestAuthenticatedWriteUser(r)
 	if err != nil {
 		sendError(w, err)
 		return
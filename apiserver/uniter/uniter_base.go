@@ -805,6 +805,7 @@ func (u *uniterBaseAPI) Actions(args params.Entities) (params.ActionsQueryResult
 		results.Results[i].Action.Action = &params.Action{
 			Name:       action.Name(),
 			Parameters: action.Parameters(),
+			Timeout:    action.Timeout(),
 		}
 	}
 
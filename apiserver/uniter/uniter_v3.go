@@ -0,0 +1,398 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The uniter package implements the API interface used by the uniter
+// worker. This file contains the API facade version 3.
+
+package uniter
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("Uniter", 3, NewUniterAPIV3)
+}
+
+// UniterAPIV3 implements the API version 3, used by the uniter worker.
+type UniterAPIV3 struct {
+	UniterAPIV2
+}
+
+// ActionStatus returns the status of each given Action. It is used by
+// the uniter to learn when a running Action has been cancelled.
+func (u *UniterAPIV3) ActionStatus(args params.Entities) (params.StringResults, error) {
+	nothing := params.StringResults{}
+
+	actionFn, err := u.authAndActionFromTagFn()
+	if err != nil {
+		return nothing, err
+	}
+
+	results := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		action, err := actionFn(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = string(action.Status())
+	}
+	return results, nil
+}
+
+// UpgradeSeriesUnitStatus returns the progress of each given unit through
+// the series upgrade of its machine.
+func (u *UniterAPIV3) UpgradeSeriesUnitStatus(args params.Entities) (params.UpgradeSeriesStatusResults, error) {
+	result := params.UpgradeSeriesStatusResults{
+		Results: make([]params.UpgradeSeriesStatusResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.UpgradeSeriesStatusResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := u.unitMachine(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		status, err := machine.UpgradeSeriesUnitStatus(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Status = params.UpgradeSeriesStatus(status)
+		result.Results[i].ToSeries = machine.UpgradeSeriesTarget()
+	}
+	return result, nil
+}
+
+// SetUpgradeSeriesUnitStatus records the progress of each given unit
+// through the series upgrade of its machine.
+func (u *UniterAPIV3) SetUpgradeSeriesUnitStatus(args params.SetUpgradeSeriesStatusArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		tag, err := names.ParseUnitTag(arg.Entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var machine *state.Machine
+			machine, err = u.unitMachine(tag)
+			if err == nil {
+				err = machine.SetUpgradeSeriesUnitStatus(tag.Id(), state.UpgradeSeriesStatus(arg.Status))
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// WatchUpgradeSeriesNotifications returns a NotifyWatcher for observing
+// changes to the series upgrade of each given unit's machine.
+func (u *UniterAPIV3) WatchUpgradeSeriesNotifications(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.NotifyWatchResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		watcherId := ""
+		if canAccess(tag) {
+			watcherId, err = u.watchOneUpgradeSeries(tag)
+		}
+		result.Results[i].NotifyWatcherId = watcherId
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// NetworkInfo returns the network interfaces and addresses of the given
+// unit's machine in the spaces bound to each of the named endpoints.
+func (u *UniterAPIV3) NetworkInfo(args params.NetworkInfoParams) (params.NetworkInfoResults, error) {
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.NetworkInfoResults{}, err
+	}
+	tag, err := names.ParseUnitTag(args.Unit)
+	if err != nil || !canAccess(tag) {
+		return params.NetworkInfoResults{}, common.ErrPerm
+	}
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return params.NetworkInfoResults{}, err
+	}
+	result := params.NetworkInfoResults{
+		Results: make(map[string]params.NetworkInfoResult, len(args.Bindings)),
+	}
+	for _, binding := range args.Bindings {
+		infos, err := unit.NetworkInfo(binding)
+		if err != nil {
+			result.Results[binding] = params.NetworkInfoResult{Error: common.ServerError(err)}
+			continue
+		}
+		var info []params.NetworkInfo
+		for _, stateInfo := range infos {
+			addrs := make([]params.InterfaceAddress, len(stateInfo.Addresses))
+			for i, addr := range stateInfo.Addresses {
+				addrs[i] = params.InterfaceAddress{Address: addr.Address, CIDR: addr.CIDR}
+			}
+			info = append(info, params.NetworkInfo{
+				MACAddress:    stateInfo.MACAddress,
+				InterfaceName: stateInfo.InterfaceName,
+				Addresses:     addrs,
+			})
+		}
+		result.Results[binding] = params.NetworkInfoResult{Info: info}
+	}
+	return result, nil
+}
+
+// SetWorkloadVersion records the workload version reported by the charm
+// of each given unit.
+func (u *UniterAPIV3) SetWorkloadVersion(args params.EntityWorkloadVersions) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.SetWorkloadVersion(entity.WorkloadVersion)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// RegisterPayloads records the payloads run by each given unit.
+func (u *UniterAPIV3) RegisterPayloads(args params.UnitPayloads) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Payloads)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Payloads {
+		unit, err := u.payloadUnit(canAccess, arg.Tag)
+		if err == nil {
+			err = unit.RegisterPayload(state.Payload{
+				Class:  arg.Payload.Class,
+				Type:   arg.Payload.Type,
+				ID:     arg.Payload.ID,
+				Status: arg.Payload.Status,
+				Labels: arg.Payload.Labels,
+			})
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// UnregisterPayloads removes the given payloads of each unit.
+func (u *UniterAPIV3) UnregisterPayloads(args params.UnitPayloadStatuses) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Payloads)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Payloads {
+		unit, err := u.payloadUnit(canAccess, arg.Tag)
+		if err == nil {
+			err = unit.UnregisterPayload(arg.Class, arg.ID)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetPayloadStatuses updates the statuses of the given payloads of each
+// unit.
+func (u *UniterAPIV3) SetPayloadStatuses(args params.UnitPayloadStatuses) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Payloads)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Payloads {
+		unit, err := u.payloadUnit(canAccess, arg.Tag)
+		if err == nil {
+			err = unit.SetPayloadStatus(arg.Class, arg.ID, arg.Status)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// CharmModifiedVersion returns, for each given service, the number of
+// times the service's charm has been modified in ways that require its
+// units to run the upgrade-charm hook, such as by attaching resources.
+func (u *UniterAPIV3) CharmModifiedVersion(args params.Entities) (params.IntResults, error) {
+	result := params.IntResults{
+		Results: make([]params.IntResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessService()
+	if err != nil {
+		return params.IntResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var service *state.Service
+			service, err = u.getService(tag)
+			if err == nil {
+				result.Results[i].Result = service.CharmModifiedVersion()
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// Resources returns the current revision of each given resource of the
+// unit's service.
+func (u *UniterAPIV3) Resources(args params.UnitResources) (params.ResourceResults, error) {
+	result := params.ResourceResults{
+		Results: make([]params.ResourceResult, len(args.Resources)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ResourceResults{}, err
+	}
+	for i, arg := range args.Resources {
+		res, err := u.oneResource(canAccess, arg)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Resource = params.Resource{
+			Service:     res.Service,
+			Name:        res.Name,
+			Path:        res.Path,
+			Revision:    res.Revision,
+			Fingerprint: res.Fingerprint,
+			Size:        res.Size,
+			Username:    res.Username,
+			Timestamp:   res.Timestamp,
+		}
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV3) oneResource(canAccess common.AuthFunc, arg params.UnitResource) (state.Resource, error) {
+	tag, err := names.ParseUnitTag(arg.Tag)
+	if err != nil || !canAccess(tag) {
+		return state.Resource{}, common.ErrPerm
+	}
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return state.Resource{}, err
+	}
+	service, err := unit.Service()
+	if err != nil {
+		return state.Resource{}, err
+	}
+	return service.Resource(arg.Name)
+}
+
+// payloadUnit returns the unit with the given tag, if it may be
+// accessed.
+func (u *UniterAPIV3) payloadUnit(canAccess common.AuthFunc, tagString string) (*state.Unit, error) {
+	tag, err := names.ParseUnitTag(tagString)
+	if err != nil || !canAccess(tag) {
+		return nil, common.ErrPerm
+	}
+	return u.getUnit(tag)
+}
+
+// unitMachine returns the machine the given unit is assigned to.
+func (u *UniterAPIV3) unitMachine(tag names.UnitTag) (*state.Machine, error) {
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return nil, err
+	}
+	machineId, err := unit.AssignedMachineId()
+	if err != nil {
+		return nil, err
+	}
+	return u.UniterAPIV1.st.Machine(machineId)
+}
+
+func (u *UniterAPIV3) watchOneUpgradeSeries(tag names.UnitTag) (string, error) {
+	machine, err := u.unitMachine(tag)
+	if err != nil {
+		return "", err
+	}
+	watch := machine.Watch()
+	// Consume the initial event.
+	if _, ok := <-watch.Changes(); ok {
+		return u.UniterAPIV1.resources.Register(watch), nil
+	}
+	return "", watcher.EnsureErr(watch)
+}
+
+// NewUniterAPIV3 creates a new instance of the Uniter API, version 3.
+func NewUniterAPIV3(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*UniterAPIV3, error) {
+	baseAPI, err := NewUniterAPIV2(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV3{
+		UniterAPIV2: *baseAPI,
+	}, nil
+}
@@ -0,0 +1,314 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/uniter"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type uniterV3Suite struct {
+	uniterBaseSuite
+	uniter *uniter.UniterAPIV3
+}
+
+var _ = gc.Suite(&uniterV3Suite{})
+
+func (s *uniterV3Suite) SetUpTest(c *gc.C) {
+	s.uniterBaseSuite.setUpTest(c)
+
+	uniterAPIV3, err := uniter.NewUniterAPIV3(
+		s.State,
+		s.resources,
+		s.authorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.uniter = uniterAPIV3
+}
+
+func (s *uniterV3Suite) TestActionStatus(c *gc.C) {
+	action, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action.Cancel()
+	c.Assert(err, jc.ErrorIsNil)
+	otherAction, err := s.mysqlUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.uniter.ActionStatus(params.Entities{Entities: []params.Entity{
+		{Tag: action.Tag().String()},
+		{Tag: otherAction.Tag().String()},
+		{Tag: "unit-wordpress-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Result: params.ActionCancelling},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: &params.Error{Message: `"unit-wordpress-0" is not a valid action tag`}},
+		},
+	})
+}
+
+func (s *uniterV3Suite) TestUpgradeSeriesUnitStatus(c *gc.C) {
+	mysqlUniter, err := uniter.NewUniterAPIV3(s.State, s.resources, apiservertesting.FakeAuthorizer{
+		Tag: s.mysqlUnit.Tag(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine1.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := mysqlUniter.UpgradeSeriesUnitStatus(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "service-mysql"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.UpgradeSeriesStatusResults{
+		Results: []params.UpgradeSeriesStatusResult{
+			{Status: "prepare started", ToSeries: "trusty"},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	errResults, err := mysqlUniter.SetUpgradeSeriesUnitStatus(params.SetUpgradeSeriesStatusArgs{
+		Args: []params.SetUpgradeSeriesStatusArg{
+			{Entity: params.Entity{Tag: "unit-mysql-0"}, Status: "prepare completed"},
+			{Entity: params.Entity{Tag: "unit-wordpress-0"}, Status: "prepare completed"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errResults, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+	err = s.machine1.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine1.UpgradeSeriesStatus(), gc.Equals, state.UpgradeSeriesPrepareCompleted)
+}
+
+func (s *uniterV3Suite) TestWatchUpgradeSeriesNotifications(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	result, err := s.uniter.WatchUpgradeSeriesNotifications(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{NotifyWatcherId: "1"},
+		},
+	})
+
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+}
+
+func (s *uniterV3Suite) TestSetWorkloadVersion(c *gc.C) {
+	result, err := s.uniter.SetWorkloadVersion(params.EntityWorkloadVersions{
+		Entities: []params.EntityWorkloadVersion{
+			{Tag: "unit-wordpress-0", WorkloadVersion: "4.2"},
+			{Tag: "unit-mysql-0", WorkloadVersion: "5.6"},
+			{Tag: "service-wordpress", WorkloadVersion: "4.2"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.wordpressUnit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.wordpressUnit.WorkloadVersion(), gc.Equals, "4.2")
+	err = s.mysqlUnit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysqlUnit.WorkloadVersion(), gc.Equals, "")
+}
+
+func (s *uniterV3Suite) TestPayloads(c *gc.C) {
+	result, err := s.uniter.RegisterPayloads(params.UnitPayloads{
+		Payloads: []params.UnitPayload{{
+			Tag:     "unit-wordpress-0",
+			Payload: params.Payload{Class: "webapp", Type: "docker", ID: "abc123", Labels: []string{"a"}},
+		}, {
+			Tag:     "unit-wordpress-0",
+			Payload: params.Payload{Class: "cache", Type: "docker", ID: "def456"},
+		}, {
+			Tag:     "unit-mysql-0",
+			Payload: params.Payload{Class: "db", Type: "docker", ID: "ghi789"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	result, err = s.uniter.SetPayloadStatuses(params.UnitPayloadStatuses{
+		Payloads: []params.UnitPayloadStatus{
+			{Tag: "unit-wordpress-0", Class: "webapp", ID: "abc123", Status: "stopping"},
+			{Tag: "unit-wordpress-0", Class: "webapp", ID: "missing", Status: "stopping"},
+			{Tag: "unit-mysql-0", Class: "db", ID: "ghi789", Status: "stopping"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(result.Results[2].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	result, err = s.uniter.UnregisterPayloads(params.UnitPayloadStatuses{
+		Payloads: []params.UnitPayloadStatus{
+			{Tag: "unit-wordpress-0", Class: "cache", ID: "def456"},
+			{Tag: "unit-mysql-0", Class: "db", ID: "ghi789"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	payloads, err := s.wordpressUnit.Payloads()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payloads, jc.DeepEquals, []state.Payload{{
+		Class:   "webapp",
+		Type:    "docker",
+		ID:      "abc123",
+		Status:  "stopping",
+		Labels:  []string{"a"},
+		Unit:    "wordpress/0",
+		Machine: s.machine0.Id(),
+	}})
+}
+
+func (s *uniterV3Suite) TestNetworkInfo(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", []string{"10.0.0.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine0.SetProviderAddresses(
+		network.NewAddress("192.168.1.5"),
+		network.NewAddress("10.0.0.5"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.uniter.NetworkInfo(params.NetworkInfoParams{
+		Unit:     "unit-wordpress-0",
+		Bindings: []string{"db", "url", "foo"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NetworkInfoResults{
+		Results: map[string]params.NetworkInfoResult{
+			"db": {Info: []params.NetworkInfo{{
+				Addresses: []params.InterfaceAddress{{Address: "10.0.0.5", CIDR: "10.0.0.0/24"}},
+			}}},
+			"url": {Info: []params.NetworkInfo{{
+				Addresses: []params.InterfaceAddress{{Address: "192.168.1.5"}},
+			}}},
+			"foo": {Error: &params.Error{
+				Message: `endpoint "foo" not found`,
+				Code:    params.CodeNotFound,
+			}},
+		},
+	})
+
+	_, err = s.uniter.NetworkInfo(params.NetworkInfoParams{
+		Unit:     "unit-mysql-0",
+		Bindings: []string{"server"},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *uniterV3Suite) TestCharmModifiedVersion(c *gc.C) {
+	_, err := s.wordpress.SetResource(state.Resource{
+		Name:        "software",
+		Path:        "software.tgz",
+		StoragePath: "resources/wordpress/software",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.uniter.CharmModifiedVersion(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "service-wordpress"},
+			{Tag: "service-mysql"},
+			{Tag: "unit-wordpress-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.IntResults{
+		Results: []params.IntResult{
+			{Result: 1},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterV3Suite) TestResources(c *gc.C) {
+	_, err := s.wordpress.SetResource(state.Resource{
+		Name:        "software",
+		Path:        "software.tgz",
+		Fingerprint: "abc",
+		Size:        3,
+		StoragePath: "resources/wordpress/software",
+		Username:    "bob",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	// The timestamp is compared as stored, rather than as set.
+	res, err := s.wordpress.Resource("software")
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.uniter.Resources(params.UnitResources{
+		Resources: []params.UnitResource{
+			{Tag: "unit-wordpress-0", Name: "software"},
+			{Tag: "unit-wordpress-0", Name: "missing"},
+			{Tag: "unit-mysql-0", Name: "software"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ResourceResults{
+		Results: []params.ResourceResult{
+			{Resource: params.Resource{
+				Service:     "wordpress",
+				Name:        "software",
+				Path:        "software.tgz",
+				Revision:    1,
+				Fingerprint: "abc",
+				Size:        3,
+				Username:    "bob",
+				Timestamp:   res.Timestamp,
+			}},
+			{Error: apiservertesting.NotFoundError(`resource "missing" of service "wordpress"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
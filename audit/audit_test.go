@@ -4,6 +4,8 @@
 package audit
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/juju/loggo"
@@ -56,3 +58,63 @@ func (*auditSuite) TestAuditWithEmptyTagWillPanic(c *gc.C) {
 	f := func() { Audit(&mockUser{}, "should never be written") }
 	c.Assert(f, gc.PanicMatches, "user tag cannot be blank")
 }
+
+func (*auditSuite) TestSummariseArgsRedactsSecrets(c *gc.C) {
+	args := map[string]interface{}{
+		"Name":     "bob",
+		"Password": "sekrit",
+		"Config": map[string]interface{}{
+			"admin-secret": "hush",
+		},
+	}
+	summary, entities := SummariseArgs(args)
+	c.Assert(summary, gc.Equals, `{"Config":{"admin-secret":"<redacted>"},"Name":"bob","Password":"<redacted>"}`)
+	c.Assert(entities, gc.HasLen, 0)
+}
+
+func (*auditSuite) TestSummariseArgsFindsEntities(c *gc.C) {
+	args := struct {
+		Entities []struct{ Tag string }
+		Unit     string
+	}{
+		Entities: []struct{ Tag string }{{"unit-mysql-0"}, {"machine-1"}, {"unit-mysql-0"}},
+		Unit:     "mysql/0",
+	}
+	summary, entities := SummariseArgs(args)
+	c.Assert(summary, gc.Equals, `{"Entities":[{"Tag":"unit-mysql-0"},{"Tag":"machine-1"},{"Tag":"unit-mysql-0"}],"Unit":"mysql/0"}`)
+	c.Assert(entities, jc.DeepEquals, []string{"unit-mysql-0", "machine-1"})
+}
+
+func (*auditSuite) TestSummariseArgsTruncates(c *gc.C) {
+	args := map[string]string{"Value": strings.Repeat("x", MaxArgsLength*2)}
+	summary, _ := SummariseArgs(args)
+	c.Assert(len(summary) <= MaxArgsLength, jc.IsTrue)
+	var result map[string]string
+	err := json.Unmarshal([]byte(summary), &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result["Value"], gc.Equals, strings.Repeat("x", maxValueLength)+"...")
+}
+
+func (*auditSuite) TestSummariseArgsTruncatesLists(c *gc.C) {
+	values := make([]string, MaxArgsLength)
+	for i := range values {
+		values[i] = "unit-mysql-0"
+	}
+	summary, _ := SummariseArgs(map[string][]string{"Values": values})
+	c.Assert(len(summary) <= MaxArgsLength, jc.IsTrue)
+	var result map[string][]string
+	err := json.Unmarshal([]byte(summary), &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(len(result["Values"]) < len(values), jc.IsTrue)
+	c.Assert(result["Values"][len(result["Values"])-1], gc.Equals, "...")
+}
+
+func (*auditSuite) TestSummariseArgsEmpty(c *gc.C) {
+	summary, entities := SummariseArgs(nil)
+	c.Assert(summary, gc.Equals, "")
+	c.Assert(entities, gc.IsNil)
+
+	summary, entities = SummariseArgs(struct{}{})
+	c.Assert(summary, gc.Equals, "")
+	c.Assert(entities, gc.IsNil)
+}
@@ -0,0 +1,197 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/juju/names"
+)
+
+// MaxArgsLength is the maximum length of the argument summary
+// recorded for an event. Longer summaries are shortened by
+// truncating the individual values within them.
+const MaxArgsLength = 1024
+
+// maxValueLength is the length to which individual string values
+// are first truncated when a summary is too long.
+const maxValueLength = 256
+
+// minValueLength is the shortest length to which string values will
+// be truncated before the summary is abandoned.
+const minValueLength = 8
+
+// truncated marks where a value or list has been shortened.
+const truncated = "..."
+
+// redacted replaces the values of arguments that look like secrets.
+const redacted = "<redacted>"
+
+// sensitiveKeys holds the lower-cased fragments of argument names
+// whose values must never be written to the audit trail.
+var sensitiveKeys = []string{
+	"password",
+	"secret",
+	"macaroon",
+	"credential",
+	"private-key",
+	"privatekey",
+}
+
+// Event describes a single API call made by a user, as recorded in
+// the audit trail.
+type Event struct {
+	// Time is when the call was made.
+	Time time.Time
+
+	// EnvUUID identifies the environment the call was made against.
+	EnvUUID string
+
+	// User holds the tag of the user that made the call.
+	User string
+
+	// Facade, Version and Method identify the API method called.
+	Facade  string
+	Version int
+	Method  string
+
+	// Args holds a summary of the call arguments, with any
+	// sensitive values redacted.
+	Args string
+
+	// Entities holds the tags of the entities referenced in the
+	// call arguments.
+	Entities []string
+
+	// Error holds the error returned by the call, if any.
+	Error string
+
+	// SourceAddress holds the network address of the client.
+	SourceAddress string
+}
+
+// SummariseArgs returns a JSON summary of the given API call
+// arguments, suitable for recording in an Event, along with the tags
+// of all the entities they reference. The values of any arguments
+// that look like secrets are redacted, and long values are truncated
+// so that the summary remains valid JSON of at most MaxArgsLength
+// bytes.
+func SummariseArgs(args interface{}) (string, []string) {
+	if args == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", nil
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return "", nil
+	}
+	var entities []string
+	seen := make(map[string]bool)
+	generic = scrub(generic, func(s string) {
+		if seen[s] {
+			return
+		}
+		if _, err := names.ParseTag(s); err == nil {
+			seen[s] = true
+			entities = append(entities, s)
+		}
+	})
+	data, err = json.Marshal(generic)
+	if err != nil {
+		return "", entities
+	}
+	// Shorten the values within the summary, rather than the
+	// summary itself, so that it always remains valid JSON.
+	for limit := maxValueLength; len(data) > MaxArgsLength; limit /= 2 {
+		if limit < minValueLength {
+			data, _ = json.Marshal(truncated)
+			break
+		}
+		data, err = json.Marshal(truncate(generic, limit))
+		if err != nil {
+			return "", entities
+		}
+	}
+	summary := string(data)
+	if summary == "{}" || summary == "null" {
+		summary = ""
+	}
+	return summary, entities
+}
+
+// truncate returns a copy of the given JSON value with all strings
+// longer than limit bytes, and all lists longer than limit/8 items,
+// cut short and marked as truncated.
+func truncate(value interface{}, limit int) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			result[k] = truncate(v, limit)
+		}
+		return result
+	case []interface{}:
+		maxItems := limit / 8
+		result := make([]interface{}, 0, len(value))
+		for i, v := range value {
+			if i == maxItems {
+				result = append(result, truncated)
+				break
+			}
+			result = append(result, truncate(v, limit))
+		}
+		return result
+	case string:
+		if len(value) <= limit {
+			return value
+		}
+		// Cut on a rune boundary so the result is still valid UTF-8.
+		end := 0
+		for i := range value {
+			if i > limit {
+				break
+			}
+			end = i
+		}
+		return value[:end] + truncated
+	}
+	return value
+}
+
+// scrub walks the given JSON value, redacting sensitive values and
+// calling found for every string encountered.
+func scrub(value interface{}, found func(string)) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			if isSensitive(k) {
+				value[k] = redacted
+				continue
+			}
+			value[k] = scrub(v, found)
+		}
+	case []interface{}:
+		for i, v := range value {
+			value[i] = scrub(v, found)
+		}
+	case string:
+		found(value)
+	}
+	return value
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, fragment := range sensitiveKeys {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -32,9 +32,12 @@ func NewSuperCommand() cmd.Command {
 			UsagePrefix: "juju",
 			Purpose:     actionPurpose,
 		})
+	actionCmd.Register(newCancelCommand())
 	actionCmd.Register(newDefinedCommand())
 	actionCmd.Register(newDoCommand())
 	actionCmd.Register(newFetchCommand())
+	actionCmd.Register(newLimitCommand())
+	actionCmd.Register(newScheduleSuperCommand())
 	actionCmd.Register(newStatusCommand())
 	return actionCmd
 }
@@ -49,6 +52,20 @@ type APIClient interface {
 	// Action.
 	Enqueue(params.Actions) (params.ActionResults, error)
 
+	// EnqueueOperation takes a list of Actions and queues them up as a
+	// single operation, returning the operation's id along with the
+	// params.Action for each queued Action. An Action whose receiver
+	// is a service is queued on each of the service's units.
+	EnqueueOperation(params.Actions) (params.OperationResult, error)
+
+	// Operations returns the Actions queued as part of each of the
+	// given operations.
+	Operations(params.Operations) (params.OperationResults, error)
+
+	// FilterActions returns the Actions in the environment's action
+	// history that match the given filter, most recently queued first.
+	FilterActions(params.ActionFilter) (params.ActionResults, error)
+
 	// ListAll takes a list of Tags representing ActionReceivers and returns
 	// all of the Actions that have been queued or run by each of those
 	// Entities.
@@ -64,8 +81,23 @@ type APIClient interface {
 	// Entities.
 	ListCompleted(params.Entities) (params.ActionsByReceivers, error)
 
-	// Cancel attempts to cancel a queued up Action from running.
-	Cancel(params.Actions) (params.ActionResults, error)
+	// Cancel cancels the given Actions, stopping them if they are
+	// already running.
+	Cancel(params.Entities) (params.ActionResults, error)
+
+	// SetActionLimits sets the maximum number of units of each given
+	// service that may run the given action at once.
+	SetActionLimits(params.ActionLimits) (params.ErrorResults, error)
+
+	// AddSchedules adds schedules on which the given Actions are
+	// queued repeatedly.
+	AddSchedules(params.ActionSchedules) (params.ActionScheduleResults, error)
+
+	// Schedules returns all of the environment's action schedules.
+	Schedules() (params.ActionScheduleResults, error)
+
+	// RemoveSchedules removes the action schedules with the given ids.
+	RemoveSchedules(params.ActionScheduleIds) (params.ErrorResults, error)
 
 	// ServiceCharmActions is a single query which uses ServicesCharmActions to
 	// get the charm.Actions for a single Service by tag.
@@ -44,10 +44,13 @@ func (s *ActionCommandSuite) TestHelp(c *gc.C) {
 
 func (s *ActionCommandSuite) checkHelpSubCommands(c *gc.C, ctx *cmd.Context) {
 	var expectedSubCommmands = [][]string{
+		{"cancel", "cancel pending or running actions"},
 		{"defined", "show actions defined for a service"},
 		{"do", "queue an action for execution"},
 		{"fetch", "show results of an action by ID"},
 		{"help", "show help on a command or other topic"},
+		{"limit", "limit how many units of a service may run an action at once"},
+		{"schedule", "manage action schedules"},
 		{"status", "show results of all actions filtered by optional ID prefix"},
 	}
 
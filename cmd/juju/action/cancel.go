@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func newCancelCommand() cmd.Command {
+	return envcmd.Wrap(&cancelCommand{})
+}
+
+// cancelCommand cancels pending or running Actions by ID.
+type cancelCommand struct {
+	ActionCommandBase
+	out          cmd.Output
+	requestedIds []string
+}
+
+const cancelDoc = `
+Cancel the Actions matching the given IDs or partial ID prefixes. Each
+prefix must match exactly one Action.
+
+Actions that have not yet started are cancelled immediately. Actions
+that are running are marked as "cancelling" until the unit running them
+stops the Action, at which point its status becomes "cancelled" and any
+output it had produced is kept.
+`
+
+// Set up the output.
+func (c *cancelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+func (c *cancelCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "cancel",
+		Args:    "<action ID | action ID prefix> [...]",
+		Purpose: "cancel pending or running actions",
+		Doc:     cancelDoc,
+	}
+}
+
+func (c *cancelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no action IDs specified")
+	}
+	c.requestedIds = args
+	return nil
+}
+
+func (c *cancelCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	entities := []params.Entity{}
+	for _, id := range c.requestedIds {
+		tag, err := getActionTagByPrefix(api, id)
+		if err != nil {
+			return err
+		}
+		entities = append(entities, params.Entity{Tag: tag.String()})
+	}
+
+	actions, err := api.Cancel(params.Entities{Entities: entities})
+	if err != nil {
+		return err
+	}
+	if len(actions.Results) != len(entities) {
+		return errors.Errorf("expected %d results, got %d", len(entities), len(actions.Results))
+	}
+	return c.out.Write(ctx, resultsToMap(actions.Results))
+}
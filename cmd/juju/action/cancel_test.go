@@ -0,0 +1,94 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type CancelSuite struct {
+	BaseActionSuite
+	subcommand cmd.Command
+}
+
+var _ = gc.Suite(&CancelSuite{})
+
+func (s *CancelSuite) SetUpTest(c *gc.C) {
+	s.BaseActionSuite.SetUpTest(c)
+	s.subcommand = action.NewCancelCommand()
+}
+
+func (s *CancelSuite) TestHelp(c *gc.C) {
+	s.checkHelp(c, s.subcommand)
+}
+
+func (s *CancelSuite) TestInit(c *gc.C) {
+	_, err := testing.RunCommand(c, action.NewCancelCommand(), "-e", "dummyenv")
+	c.Assert(err, gc.ErrorMatches, "no action IDs specified")
+}
+
+func (s *CancelSuite) TestRun(c *gc.C) {
+	prefix := "deadbeef"
+	fakeid := prefix + "-0000-4000-8000-feedfacebeef"
+	fakeid2 := prefix + "-0001-4000-8000-feedfacebeef"
+	faketag := "action-" + fakeid
+	faketag2 := "action-" + fakeid2
+
+	results := []params.ActionResult{{
+		Action: &params.Action{Tag: faketag, Receiver: "unit-mysql-0"},
+		Status: params.ActionCancelling,
+	}}
+
+	tests := []struct {
+		about       string
+		tags        params.FindTagsResults
+		results     []params.ActionResult
+		expectError string
+	}{{
+		about:       "no match",
+		tags:        tagsForIdPrefix(prefix),
+		expectError: `actions for identifier "deadbeef" not found`,
+	}, {
+		about:       "ambiguous prefix",
+		tags:        tagsForIdPrefix(prefix, faketag, faketag2),
+		expectError: `identifier "deadbeef" matched multiple actions .*`,
+	}, {
+		about:       "missing results",
+		tags:        tagsForIdPrefix(prefix, faketag),
+		expectError: "expected 1 results, got 0",
+	}, {
+		about:   "cancelled",
+		tags:    tagsForIdPrefix(prefix, faketag),
+		results: results,
+	}}
+
+	for i, test := range tests {
+		c.Logf("test %d: %s", i, test.about)
+		fakeClient := makeFakeClient(0, 5*time.Second, test.tags, test.results, "")
+		restore := s.patchAPIClient(fakeClient)
+
+		ctx, err := testing.RunCommand(c, action.NewCancelCommand(), "-e", "dummyenv", prefix)
+		restore()
+		if test.expectError != "" {
+			c.Check(err, gc.ErrorMatches, test.expectError)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(fakeClient.cancelledActions, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: faketag}},
+		})
+		buf, err := cmd.DefaultFormatters["yaml"](action.ActionResultsToMap(test.results))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, string(buf)+"\n")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -26,13 +27,17 @@ func newDoCommand() cmd.Command {
 }
 
 // doCommand enqueues an Action for running on the given unit with given
-// params
+// params, or on a number of units as a single operation.
 type doCommand struct {
 	ActionCommandBase
 	unitTag      names.UnitTag
+	receivers    []names.Tag
+	allUnits     bool
+	units        string
 	actionName   string
 	paramsYAML   cmd.FileVar
 	parseStrings bool
+	timeout      time.Duration
 	out          cmd.Output
 	args         [][]string
 }
@@ -41,6 +46,13 @@ const doDoc = `
 Queue an Action for execution on a given unit, with a given set of params.
 Displays the ID of the Action for use with 'juju kill', 'juju status', etc.
 
+If a service is given instead of a unit, or the --all-units flag is set,
+the Action is queued on every unit of the service. The --units flag queues
+the Action on each of a comma-separated list of units, in which case only
+the action name and params are given as arguments. Actions queued on more
+than one unit form a single operation; its ID may be passed to
+'juju action fetch --operation' to show the results from all units.
+
 Params are validated according to the charm for the unit's service.  The 
 valid params can be seen using "juju action defined <service> --schema".
 Params may be in a yaml file which is passed with the --params flag, or they
@@ -53,6 +65,10 @@ is a boolean true in YAML.
 If --params is passed, along with key.key...=value explicit arguments, the
 explicit arguments will override the parameter file.
 
+The --timeout flag sets how long the Action may run for before it is stopped
+and marked as failed, overriding the default of the action's "timeout"
+string parameter, if the charm's actions.yaml declares one.
+
 Examples:
 
 $ juju action do mysql/3 backup 
@@ -103,6 +119,17 @@ $ juju action do sleeper/0 pause time=1000
 $ juju action do sleeper/0 pause --string-args time=1000
 ...
 The value for the "time" param will be the string literal "1000".
+
+$ juju action do mysql backup
+operation: <operation ID>
+actions:
+- id: <ID>
+  status: pending
+  unit: mysql/0
+...
+
+$ juju action do --units mysql/0,mysql/2 backup
+...
 `
 
 // ActionNameRule describes the format an action name must match to be valid.
@@ -113,58 +140,99 @@ func (c *doCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.Var(&c.paramsYAML, "params", "path to yaml-formatted params file")
 	f.BoolVar(&c.parseStrings, "string-args", false, "use raw string values of CLI args")
+	f.BoolVar(&c.allUnits, "all-units", false, "queue the action on all units of the service")
+	f.StringVar(&c.units, "units", "", "queue the action on each of these comma-separated units")
+	f.DurationVar(&c.timeout, "timeout", 0, "stop the action if it runs for longer than this")
 }
 
 func (c *doCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "do",
-		Args:    "<unit> <action name> [key.key.key...=value]",
+		Args:    "<unit> | <service> <action name> [key.key.key...=value]",
 		Purpose: "queue an action for execution",
 		Doc:     doDoc,
 	}
 }
 
-// Init gets the unit tag, and checks for other correct args.
+// Init gets the unit tag, or the tags of the units or service for an
+// operation, and checks for other correct args.
 func (c *doCommand) Init(args []string) error {
+	if c.timeout < 0 {
+		return errors.Errorf("invalid timeout %v", c.timeout)
+	}
+	if c.units != "" {
+		if c.allUnits {
+			return errors.New("cannot specify both --units and --all-units")
+		}
+		for _, unitName := range strings.Split(c.units, ",") {
+			if !names.IsValidUnit(unitName) {
+				return errors.Errorf("invalid unit name %q", unitName)
+			}
+			c.receivers = append(c.receivers, names.NewUnitTag(unitName))
+		}
+		if len(args) == 0 {
+			return errors.New("no action specified")
+		}
+		return c.initAction(args[0], args[1:])
+	}
 	switch len(args) {
 	case 0:
 		return errors.New("no unit specified")
 	case 1:
 		return errors.New("no action specified")
-	default:
-		// Grab and verify the unit and action names.
-		unitName := args[0]
-		if !names.IsValidUnit(unitName) {
-			return errors.Errorf("invalid unit name %q", unitName)
-		}
-		ActionName := args[1]
-		if valid := ActionNameRule.MatchString(ActionName); !valid {
-			return fmt.Errorf("invalid action name %q", ActionName)
+	}
+	// Grab and verify the unit or service name.
+	switch receiver := args[0]; {
+	case names.IsValidUnit(receiver):
+		if c.allUnits {
+			return errors.Errorf("--all-units requires a service name, got unit %q", receiver)
 		}
-		c.unitTag = names.NewUnitTag(unitName)
-		c.actionName = ActionName
-		if len(args) == 2 {
-			return nil
+		c.unitTag = names.NewUnitTag(receiver)
+	case names.IsValidService(receiver):
+		c.receivers = []names.Tag{names.NewServiceTag(receiver)}
+	default:
+		return errors.Errorf("invalid unit or service name %q", receiver)
+	}
+	return c.initAction(args[1], args[2:])
+}
+
+// initAction verifies the action name, and parses any key-value args.
+func (c *doCommand) initAction(actionName string, args []string) error {
+	if valid := ActionNameRule.MatchString(actionName); !valid {
+		return fmt.Errorf("invalid action name %q", actionName)
+	}
+	c.actionName = actionName
+	parsed, err := parseActionArgs(args)
+	if err != nil {
+		return err
+	}
+	c.args = parsed
+	return nil
+}
+
+// parseActionArgs parses key.key.key...=value args into slices of the
+// form [key, key, key, ..., value].
+func parseActionArgs(args []string) ([][]string, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	parsed := make([][]string, 0)
+	for _, arg := range args {
+		thisArg := strings.SplitN(arg, "=", 2)
+		if len(thisArg) != 2 {
+			return nil, fmt.Errorf("argument %q must be of the form key...=value", arg)
 		}
-		// Parse CLI key-value args if they exist.
-		c.args = make([][]string, 0)
-		for _, arg := range args[2:] {
-			thisArg := strings.SplitN(arg, "=", 2)
-			if len(thisArg) != 2 {
-				return fmt.Errorf("argument %q must be of the form key...=value", arg)
-			}
-			keySlice := strings.Split(thisArg[0], ".")
-			// check each key for validity
-			for _, key := range keySlice {
-				if valid := keyRule.MatchString(key); !valid {
-					return fmt.Errorf("key %q must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens", key)
-				}
+		keySlice := strings.Split(thisArg[0], ".")
+		// check each key for validity
+		for _, key := range keySlice {
+			if valid := keyRule.MatchString(key); !valid {
+				return nil, fmt.Errorf("key %q must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens", key)
 			}
-			// c.args={..., [key, key, key, key, value]}
-			c.args = append(c.args, append(keySlice, thisArg[1]))
 		}
-		return nil
+		// parsed={..., [key, key, key, key, value]}
+		parsed = append(parsed, append(keySlice, thisArg[1]))
 	}
+	return parsed, nil
 }
 
 func (c *doCommand) Run(ctx *cmd.Context) error {
@@ -174,27 +242,75 @@ func (c *doCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	actionParams, err := buildActionParams(ctx, c.paramsYAML, c.args, c.parseStrings)
+	if err != nil {
+		return err
+	}
+
+	if len(c.receivers) > 0 {
+		return c.enqueueOperation(ctx, api, actionParams)
+	}
+
+	actionParam := params.Actions{
+		Actions: []params.Action{{
+			Receiver:   c.unitTag.String(),
+			Name:       c.actionName,
+			Parameters: actionParams,
+			Timeout:    c.timeout,
+		}},
+	}
+
+	results, err := api.Enqueue(actionParam)
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != 1 {
+		return errors.New("illegal number of results returned")
+	}
+
+	result := results.Results[0]
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.Action == nil {
+		return errors.New("action failed to enqueue")
+	}
+
+	tag, err := names.ParseActionTag(result.Action.Tag)
+	if err != nil {
+		return err
+	}
+
+	output := map[string]string{"Action queued with id": tag.Id()}
+	return c.out.Write(ctx, output)
+}
+
+// buildActionParams builds the params for an Action from the YAML
+// params file, if any, overridden by the parsed key-value args.
+func buildActionParams(ctx *cmd.Context, paramsYAML cmd.FileVar, args [][]string, parseStrings bool) (map[string]interface{}, error) {
 	actionParams := map[string]interface{}{}
 
-	if c.paramsYAML.Path != "" {
-		b, err := c.paramsYAML.Read(ctx)
+	if paramsYAML.Path != "" {
+		b, err := paramsYAML.Read(ctx)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		err = yaml.Unmarshal(b, &actionParams)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		conformantParams, err := common.ConformYAML(actionParams)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		betterParams, ok := conformantParams.(map[string]interface{})
 		if !ok {
-			return errors.New("params must contain a YAML map with string keys")
+			return nil, errors.New("params must contain a YAML map with string keys")
 		}
 
 		actionParams = betterParams
@@ -202,15 +318,15 @@ func (c *doCommand) Run(ctx *cmd.Context) error {
 
 	// If we had explicit args {..., [key, key, key, key, value], ...}
 	// then iterate and set params ..., key.key.key.key=value, ...
-	for _, argSlice := range c.args {
+	for _, argSlice := range args {
 		valueIndex := len(argSlice) - 1
 		keys := argSlice[:valueIndex]
 		value := argSlice[valueIndex]
 		cleansedValue := interface{}(value)
-		if !c.parseStrings {
+		if !parseStrings {
 			err := yaml.Unmarshal([]byte(value), &cleansedValue)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 		// Insert the value in the map.
@@ -219,45 +335,49 @@ func (c *doCommand) Run(ctx *cmd.Context) error {
 
 	conformantParams, err := common.ConformYAML(actionParams)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	typedConformantParams, ok := conformantParams.(map[string]interface{})
 	if !ok {
-		return errors.Errorf("params must be a map, got %T", typedConformantParams)
+		return nil, errors.Errorf("params must be a map, got %T", typedConformantParams)
 	}
 
-	actionParam := params.Actions{
-		Actions: []params.Action{{
-			Receiver:   c.unitTag.String(),
+	return actionParams, nil
+}
+
+// enqueueOperation queues the action on each of the command's receivers
+// as a single operation, and writes the operation's ID along with the
+// queued actions.
+func (c *doCommand) enqueueOperation(ctx *cmd.Context, api APIClient, actionParams map[string]interface{}) error {
+	actions := make([]params.Action, len(c.receivers))
+	for i, receiver := range c.receivers {
+		actions[i] = params.Action{
+			Receiver:   receiver.String(),
 			Name:       c.actionName,
 			Parameters: actionParams,
-		}},
+			Timeout:    c.timeout,
+		}
 	}
-
-	results, err := api.Enqueue(actionParam)
+	result, err := api.EnqueueOperation(params.Actions{Actions: actions})
 	if err != nil {
 		return err
 	}
-	if len(results.Results) != 1 {
-		return errors.New("illegal number of results returned")
-	}
-
-	result := results.Results[0]
-
 	if result.Error != nil {
 		return result.Error
 	}
-
-	if result.Action == nil {
-		return errors.New("action failed to enqueue")
+	if len(result.Actions) == 0 {
+		return errors.New("no actions were queued")
 	}
-
-	tag, err := names.ParseActionTag(result.Action.Tag)
-	if err != nil {
+	output := resultsToMap(result.Actions)
+	output["operation"] = result.Operation
+	if err := c.out.Write(ctx, output); err != nil {
 		return err
 	}
-
-	output := map[string]string{"Action queued with id": tag.Id()}
-	return c.out.Write(ctx, output)
+	for _, action := range result.Actions {
+		if action.Error == nil {
+			return nil
+		}
+	}
+	return cmd.ErrSilent
 }
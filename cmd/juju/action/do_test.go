@@ -7,8 +7,10 @@ import (
 	"bytes"
 	"errors"
 	"strings"
+	"time"
 	"unicode/utf8"
 
+	"github.com/juju/cmd"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -65,9 +67,11 @@ func (s *DoSuite) TestInit(c *gc.C) {
 		should               string
 		args                 []string
 		expectUnit           names.UnitTag
+		expectReceivers      []names.Tag
 		expectAction         string
 		expectParamsYamlPath string
 		expectParseStrings   bool
+		expectTimeout        time.Duration
 		expectKVArgs         [][]string
 		expectOutput         string
 		expectError          string
@@ -82,7 +86,52 @@ func (s *DoSuite) TestInit(c *gc.C) {
 	}, {
 		should:      "fail with invalid unit tag",
 		args:        []string{invalidUnitId, "valid-action-name"},
-		expectError: "invalid unit name \"something-strange-\"",
+		expectError: "invalid unit or service name \"something-strange-\"",
+	}, {
+		should:          "init with a service",
+		args:            []string{"mysql", "valid-action-name"},
+		expectReceivers: []names.Tag{names.NewServiceTag("mysql")},
+		expectAction:    "valid-action-name",
+	}, {
+		should:          "init with a service and --all-units",
+		args:            []string{"--all-units", "mysql", "valid-action-name"},
+		expectReceivers: []names.Tag{names.NewServiceTag("mysql")},
+		expectAction:    "valid-action-name",
+	}, {
+		should:      "fail with a unit and --all-units",
+		args:        []string{"--all-units", validUnitId, "valid-action-name"},
+		expectError: "--all-units requires a service name, got unit \"mysql/0\"",
+	}, {
+		should: "init with --units",
+		args:   []string{"--units", "mysql/0,mysql/2", "valid-action-name", "foo=bar"},
+		expectReceivers: []names.Tag{
+			names.NewUnitTag("mysql/0"),
+			names.NewUnitTag("mysql/2"),
+		},
+		expectAction: "valid-action-name",
+		expectKVArgs: [][]string{{"foo", "bar"}},
+	}, {
+		should:      "fail with --units and no action",
+		args:        []string{"--units", "mysql/0"},
+		expectError: "no action specified",
+	}, {
+		should:      "fail with invalid unit in --units",
+		args:        []string{"--units", "mysql/0,mysql", "valid-action-name"},
+		expectError: "invalid unit name \"mysql\"",
+	}, {
+		should:        "handle --timeout",
+		args:          []string{validUnitId, "valid-action-name", "--timeout", "90s"},
+		expectUnit:    names.NewUnitTag(validUnitId),
+		expectAction:  "valid-action-name",
+		expectTimeout: 90 * time.Second,
+	}, {
+		should:      "fail with a negative --timeout",
+		args:        []string{validUnitId, "valid-action-name", "--timeout", "-1s"},
+		expectError: "invalid timeout -1s",
+	}, {
+		should:      "fail with --units and --all-units",
+		args:        []string{"--units", "mysql/0", "--all-units", "valid-action-name"},
+		expectError: "cannot specify both --units and --all-units",
 	}, {
 		should:      "fail with invalid action name",
 		args:        []string{validUnitId, "BadName"},
@@ -172,11 +221,14 @@ func (s *DoSuite) TestInit(c *gc.C) {
 		args := append([]string{"-e", "dummyenv"}, t.args...)
 		err := testing.InitCommand(wrappedCommand, args)
 		if t.expectError == "" {
+			c.Check(err, jc.ErrorIsNil)
 			c.Check(command.UnitTag(), gc.Equals, t.expectUnit)
+			c.Check(command.Receivers(), jc.DeepEquals, t.expectReceivers)
 			c.Check(command.ActionName(), gc.Equals, t.expectAction)
 			c.Check(command.ParamsYAML().Path, gc.Equals, t.expectParamsYamlPath)
 			c.Check(command.Args(), jc.DeepEquals, t.expectKVArgs)
 			c.Check(command.ParseStrings(), gc.Equals, t.expectParseStrings)
+			c.Check(command.Timeout(), gc.Equals, t.expectTimeout)
 		} else {
 			c.Check(err, gc.ErrorMatches, t.expectError)
 		}
@@ -400,3 +452,65 @@ func (s *DoSuite) TestRun(c *gc.C) {
 		}()
 	}
 }
+
+func (s *DoSuite) TestRunOperation(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		operationResult: params.OperationResult{
+			Operation: "an-operation",
+			Actions: []params.ActionResult{{
+				Action: &params.Action{
+					Tag:      validActionTagString,
+					Receiver: "unit-mysql-0",
+				},
+				Status: params.ActionPending,
+			}, {
+				Action: &params.Action{
+					Receiver: "unit-mysql-1",
+				},
+				Error: common.ServerError(errors.New("database error")),
+			}},
+		},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewDoCommand()
+	ctx, err := testing.RunCommand(c, wrappedCommand, "-e", "dummyenv", "mysql", "some-action", "foo=bar")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.EnqueuedActions(), jc.DeepEquals, params.Actions{
+		Actions: []params.Action{{
+			Receiver:   "service-mysql",
+			Name:       "some-action",
+			Parameters: map[string]interface{}{"foo": "bar"},
+		}},
+	})
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+actions:
+- id: `+validActionId+`
+  status: pending
+  unit: mysql/0
+- error: database error
+  status: ""
+  unit: mysql/1
+operation: an-operation
+`[1:])
+}
+
+func (s *DoSuite) TestRunOperationAllFailed(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		operationResult: params.OperationResult{
+			Operation: "an-operation",
+			Actions: []params.ActionResult{{
+				Action: &params.Action{Receiver: "unit-mysql-0"},
+				Error:  common.ServerError(errors.New("database error")),
+			}},
+		},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewDoCommand()
+	_, err := testing.RunCommand(c, wrappedCommand, "-e", "dummyenv", "--units", "mysql/0", "some-action")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Check(fakeClient.EnqueuedActions().Actions[0].Receiver, gc.Equals, "unit-mysql-0")
+}
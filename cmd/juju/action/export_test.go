@@ -4,6 +4,8 @@
 package action
 
 import (
+	"time"
+
 	"github.com/juju/cmd"
 	"github.com/juju/names"
 
@@ -14,8 +16,15 @@ import (
 var (
 	NewActionAPIClient = &newAPIClient
 	AddValueToMap      = addValueToMap
+	NewCancelCommand   = newCancelCommand
 	NewFetchCommand    = newFetchCommand
+	NewLimitCommand    = newLimitCommand
 	NewStatusCommand   = newStatusCommand
+
+	NewScheduleSuperCommand  = newScheduleSuperCommand
+	NewScheduleAddCommand    = newScheduleAddCommand
+	NewScheduleListCommand   = newScheduleListCommand
+	NewScheduleRemoveCommand = newScheduleRemoveCommand
 )
 
 type DoCommand struct {
@@ -26,6 +35,10 @@ func (c *DoCommand) UnitTag() names.UnitTag {
 	return c.unitTag
 }
 
+func (c *DoCommand) Receivers() []names.Tag {
+	return c.receivers
+}
+
 func (c *DoCommand) ActionName() string {
 	return c.actionName
 }
@@ -38,6 +51,10 @@ func (c *DoCommand) ParamsYAML() cmd.FileVar {
 	return c.paramsYAML
 }
 
+func (c *DoCommand) Timeout() time.Duration {
+	return c.timeout
+}
+
 func (c *DoCommand) Args() [][]string {
 	return c.args
 }
@@ -4,11 +4,15 @@
 package action
 
 import (
+	"bytes"
+	"fmt"
 	"regexp"
+	"text/tabwriter"
 	"time"
 
 	"github.com/juju/cmd"
 	errors "github.com/juju/errors"
+	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
@@ -24,6 +28,7 @@ type fetchCommand struct {
 	ActionCommandBase
 	out         cmd.Output
 	requestedId string
+	operation   string
 	fullSchema  bool
 	wait        string
 }
@@ -37,18 +42,31 @@ to wait indefinitely.  If units are left off, seconds are assumed.
 The default behavior without --wait is to immediately check and return; if
 the results are "pending" then only the available information will be
 displayed.  This is also the behavior when any negative time is given.
+
+The --operation flag shows the results of all the actions queued by a
+single 'juju action do' on several units, given the operation ID it
+displayed.  With --wait, the command blocks until every action in the
+operation has finished.  The results may also be displayed as a table,
+with --format tabular.
 `
 
 // Set up the output.
 func (c *fetchCommand) SetFlags(f *gnuflag.FlagSet) {
-	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	formatters := map[string]cmd.Formatter{
+		"tabular": formatOperationTabular,
+	}
+	for name, formatter := range cmd.DefaultFormatters {
+		formatters[name] = formatter
+	}
+	c.out.AddFlags(f, "smart", formatters)
 	f.StringVar(&c.wait, "wait", "-1s", "wait for results")
+	f.StringVar(&c.operation, "operation", "", "show results of all actions in the operation with this ID")
 }
 
 func (c *fetchCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "fetch",
-		Args:    "<action ID>",
+		Args:    "<action ID> | --operation <operation ID>",
 		Purpose: "show results of an action by ID",
 		Doc:     fetchDoc,
 	}
@@ -56,6 +74,12 @@ func (c *fetchCommand) Info() *cmd.Info {
 
 // Init validates the action ID and any other options.
 func (c *fetchCommand) Init(args []string) error {
+	if c.operation != "" {
+		if len(args) > 0 {
+			return errors.New("cannot specify both an action ID and --operation")
+		}
+		return nil
+	}
 	switch len(args) {
 	case 0:
 		return errors.New("no action ID specified")
@@ -104,6 +128,14 @@ func (c *fetchCommand) Run(ctx *cmd.Context) error {
 		wait = time.NewTimer(waitDur)
 	}
 
+	if c.operation != "" {
+		result, err := operationTimerLoop(api, c.operation, wait, tick)
+		if err != nil {
+			return err
+		}
+		return c.out.Write(ctx, formatOperationResult(result))
+	}
+
 	result, err := timerLoop(api, c.requestedId, wait, tick)
 	if err != nil {
 		return err
@@ -131,9 +163,7 @@ func timerLoop(api APIClient, requestedId string, wait, tick *time.Timer) (param
 
 		// Whether or not we're waiting for a result, if a completed
 		// result arrives, we're done.
-		switch result.Status {
-		case params.ActionRunning, params.ActionPending:
-		default:
+		if isFinished(result) {
 			return result, nil
 		}
 
@@ -148,6 +178,68 @@ func timerLoop(api APIClient, requestedId string, wait, tick *time.Timer) (param
 	}
 }
 
+// operationTimerLoop behaves like timerLoop, but queries the results of
+// all the actions in the given operation until they have all finished.
+func operationTimerLoop(api APIClient, operation string, wait, tick *time.Timer) (params.OperationResult, error) {
+	for {
+		result, err := fetchOperationResult(api, operation)
+		if err != nil {
+			return result, err
+		}
+
+		finished := true
+		for _, actionResult := range result.Actions {
+			if !isFinished(actionResult) {
+				finished = false
+				break
+			}
+		}
+		if finished {
+			return result, nil
+		}
+
+		select {
+		case _ = <-wait.C:
+			return result, nil
+
+		case _ = <-tick.C:
+			tick.Reset(2 * time.Second)
+		}
+	}
+}
+
+// isFinished returns whether the given action has finished running.
+func isFinished(result params.ActionResult) bool {
+	switch result.Status {
+	case params.ActionRunning, params.ActionPending, params.ActionCancelling, params.ActionWaiting:
+		return false
+	}
+	return true
+}
+
+// fetchOperationResult queries the given API for the results of the
+// actions in the given operation.
+func fetchOperationResult(api APIClient, operation string) (params.OperationResult, error) {
+	none := params.OperationResult{}
+
+	operations, err := api.Operations(params.Operations{
+		Operations: []string{operation},
+	})
+	if err != nil {
+		return none, err
+	}
+	if len(operations.Results) != 1 {
+		return none, errors.Errorf("expected 1 result for operation %s, got %d", operation, len(operations.Results))
+	}
+
+	result := operations.Results[0]
+	if result.Error != nil {
+		return none, result.Error
+	}
+
+	return result, nil
+}
+
 // fetchResult queries the given API for the given Action ID prefix, and
 // makes sure the results are acceptable, returning an error if they are not.
 func fetchResult(api APIClient, requestedId string) (params.ActionResult, error) {
@@ -211,3 +303,68 @@ func formatActionResult(result params.ActionResult) map[string]interface{} {
 
 	return response
 }
+
+// formatOperationResult returns the results of each action in the
+// operation, formatted as by formatActionResult along with the unit and
+// ID of the action, in a map for cmd.Output to write.
+func formatOperationResult(result params.OperationResult) map[string]interface{} {
+	items := make([]map[string]interface{}, len(result.Actions))
+	for i, actionResult := range result.Actions {
+		item := formatActionResult(actionResult)
+		if actionResult.Action != nil {
+			if tag, err := names.ParseActionTag(actionResult.Action.Tag); err == nil {
+				item["id"] = tag.Id()
+			}
+			if tag, err := names.ParseUnitTag(actionResult.Action.Receiver); err == nil {
+				item["unit"] = tag.Id()
+			}
+		}
+		if actionResult.Error != nil {
+			item["error"] = actionResult.Error.Error()
+		}
+		items[i] = item
+	}
+	return map[string]interface{}{
+		"operation": result.Operation,
+		"results":   items,
+	}
+}
+
+// formatOperationTabular writes the results of an operation as a table,
+// with one row for each action.
+func formatOperationTabular(value interface{}) ([]byte, error) {
+	output, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", output, value)
+	}
+	items, ok := output["results"].([]map[string]interface{})
+	if !ok {
+		return nil, errors.New("tabular format is only supported with --operation")
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "UNIT\tID\tSTATUS\tMESSAGE\n")
+	for _, item := range items {
+		field := func(key string) interface{} {
+			if value, ok := item[key]; ok {
+				return value
+			}
+			return ""
+		}
+		message := field("message")
+		if errMessage, ok := item["error"]; ok {
+			message = errMessage
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\n", field("unit"), field("id"), field("status"), message)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
@@ -45,6 +46,13 @@ func (s *FetchSuite) TestInit(c *gc.C) {
 		should:      "fail with multiple args",
 		args:        []string{"12345", "54321"},
 		expectError: `unrecognized args: \["54321"\]`,
+	}, {
+		should:      "fail with an action ID and --operation",
+		args:        []string{"--operation", "an-operation", "12345"},
+		expectError: "cannot specify both an action ID and --operation",
+	}, {
+		should: "accept --operation with no action ID",
+		args:   []string{"--operation", "an-operation"},
 	}}
 
 	for i, t := range tests {
@@ -55,6 +63,8 @@ func (s *FetchSuite) TestInit(c *gc.C) {
 		err := testing.InitCommand(cmd, args)
 		if t.expectError != "" {
 			c.Check(err, gc.ErrorMatches, t.expectError)
+		} else {
+			c.Check(err, gc.IsNil)
 		}
 	}
 }
@@ -289,6 +299,87 @@ timing:
 	}
 }
 
+func (s *FetchSuite) TestRunOperation(c *gc.C) {
+	client := &fakeAPIClient{
+		operationResults: []params.OperationResult{{
+			Operation: "an-operation",
+			Actions: []params.ActionResult{{
+				Action: &params.Action{
+					Tag:      validActionTagString,
+					Receiver: "unit-mysql-0",
+				},
+				Status:    params.ActionCompleted,
+				Output:    map[string]interface{}{"foo": "bar"},
+				Enqueued:  time.Date(2015, time.February, 14, 8, 13, 0, 0, time.UTC),
+				Completed: time.Date(2015, time.February, 14, 8, 15, 30, 0, time.UTC),
+			}, {
+				Action: &params.Action{
+					Tag:      validActionTagString,
+					Receiver: "unit-mysql-1",
+				},
+				Status:  params.ActionFailed,
+				Message: "oh dear",
+			}},
+		}},
+	}
+	unpatch := s.BaseActionSuite.patchAPIClient(client)
+	defer unpatch()
+
+	ctx, err := testing.RunCommand(c, action.NewFetchCommand(), "-e", "dummyenv", "--operation", "an-operation")
+	c.Assert(err, gc.IsNil)
+	c.Check(client.fetchedOperations, jc.DeepEquals, params.Operations{
+		Operations: []string{"an-operation"},
+	})
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+operation: an-operation
+results:
+- id: `+validActionId+`
+  results:
+    foo: bar
+  status: completed
+  timing:
+    completed: 2015-02-14 08:15:30 +0000 UTC
+    enqueued: 2015-02-14 08:13:00 +0000 UTC
+  unit: mysql/0
+- id: `+validActionId+`
+  message: oh dear
+  status: failed
+  unit: mysql/1
+`[1:])
+
+	ctx, err = testing.RunCommand(c, action.NewFetchCommand(), "-e", "dummyenv", "--operation", "an-operation", "--format", "tabular")
+	c.Assert(err, gc.IsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+UNIT     ID                                    STATUS     MESSAGE
+mysql/0  `+validActionId+`  completed  
+mysql/1  `+validActionId+`  failed     oh dear
+`[1:])
+}
+
+func (s *FetchSuite) TestRunOperationError(c *gc.C) {
+	client := &fakeAPIClient{
+		operationResults: []params.OperationResult{{
+			Error: common.ServerError(errors.New(`operation "missing" not found`)),
+		}},
+	}
+	unpatch := s.BaseActionSuite.patchAPIClient(client)
+	defer unpatch()
+
+	_, err := testing.RunCommand(c, action.NewFetchCommand(), "-e", "dummyenv", "--operation", "missing")
+	c.Assert(err, gc.ErrorMatches, `operation "missing" not found`)
+}
+
+func (s *FetchSuite) TestTabularRequiresOperation(c *gc.C) {
+	client := makeFakeClient(0, 10*time.Second, tagsForIdPrefix(validActionId, validActionTagString), []params.ActionResult{{
+		Status: params.ActionCompleted,
+	}}, "")
+	unpatch := s.BaseActionSuite.patchAPIClient(client)
+	defer unpatch()
+
+	_, err := testing.RunCommand(c, action.NewFetchCommand(), "-e", "dummyenv", "--format", "tabular", validActionId)
+	c.Assert(err, gc.ErrorMatches, "tabular format is only supported with --operation")
+}
+
 func testRunHelper(c *gc.C, s *FetchSuite, client *fakeAPIClient, expectedErr, expectedOutput, wait, query string) {
 	unpatch := s.BaseActionSuite.patchAPIClient(client)
 	defer unpatch()
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"strconv"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func newLimitCommand() cmd.Command {
+	return envcmd.Wrap(&limitCommand{})
+}
+
+// limitCommand limits how many units of a service may run an Action at
+// once.
+type limitCommand struct {
+	ActionCommandBase
+	serviceTag names.ServiceTag
+	actionName string
+	limit      int
+}
+
+const limitDoc = `
+Limit how many units of a service may run the named Action at once. Actions
+queued on further units have the status "waiting" until one of the running
+Actions finishes. This allows maintenance tasks to be rolled out across a
+service a few units at a time, by queueing the Action on every unit.
+
+A limit of 0 removes the limit. The limit that applies to an Action is shown
+by 'juju action status'.
+
+Examples:
+
+$ juju action limit mysql backup 2
+$ juju action do mysql backup
+`
+
+func (c *limitCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "limit",
+		Args:    "<service name> <action name> <limit>",
+		Purpose: "limit how many units of a service may run an action at once",
+		Doc:     limitDoc,
+	}
+}
+
+func (c *limitCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no service name specified")
+	case 1:
+		return errors.New("no action name specified")
+	case 2:
+		return errors.New("no limit specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return errors.Errorf("invalid service name %q", args[0])
+	}
+	c.serviceTag = names.NewServiceTag(args[0])
+	if !ActionNameRule.MatchString(args[1]) {
+		return errors.Errorf("invalid action name %q", args[1])
+	}
+	c.actionName = args[1]
+	limit, err := strconv.Atoi(args[2])
+	if err != nil || limit < 0 {
+		return errors.Errorf("invalid limit %q", args[2])
+	}
+	c.limit = limit
+	return cmd.CheckEmpty(args[3:])
+}
+
+func (c *limitCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.SetActionLimits(params.ActionLimits{
+		Limits: []params.ActionLimit{{
+			ServiceTag: c.serviceTag.String(),
+			Action:     c.actionName,
+			Limit:      c.limit,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type LimitSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&LimitSuite{})
+
+func (s *LimitSuite) TestHelp(c *gc.C) {
+	s.checkHelp(c, action.NewLimitCommand())
+}
+
+func (s *LimitSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		expectError string
+	}{{
+		expectError: "no service name specified",
+	}, {
+		args:        []string{"mysql"},
+		expectError: "no action name specified",
+	}, {
+		args:        []string{"mysql", "backup"},
+		expectError: "no limit specified",
+	}, {
+		args:        []string{"mysql/0", "backup", "1"},
+		expectError: `invalid service name "mysql/0"`,
+	}, {
+		args:        []string{"mysql", "BadName", "1"},
+		expectError: `invalid action name "BadName"`,
+	}, {
+		args:        []string{"mysql", "backup", "-1"},
+		expectError: `invalid limit "-1"`,
+	}, {
+		args:        []string{"mysql", "backup", "1", "2"},
+		expectError: `unrecognized args: \["2"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		args := append([]string{"-e", "dummyenv"}, test.args...)
+		err := testing.InitCommand(action.NewLimitCommand(), args)
+		c.Check(err, gc.ErrorMatches, test.expectError)
+	}
+}
+
+func (s *LimitSuite) TestRun(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		errorResults: params.ErrorResults{Results: []params.ErrorResult{{}}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := testing.RunCommand(c, action.NewLimitCommand(), "-e", "dummyenv", "mysql", "backup", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeClient.actionLimits, jc.DeepEquals, params.ActionLimits{
+		Limits: []params.ActionLimit{{
+			ServiceTag: "service-mysql",
+			Action:     "backup",
+			Limit:      2,
+		}},
+	})
+}
+
+func (s *LimitSuite) TestRunError(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		errorResults: params.ErrorResults{Results: []params.ErrorResult{{
+			Error: &params.Error{Message: `action "backup" not defined`},
+		}}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := testing.RunCommand(c, action.NewLimitCommand(), "-e", "dummyenv", "mysql", "backup", "0")
+	c.Assert(err, gc.ErrorMatches, `action "backup" not defined`)
+}
@@ -132,6 +132,16 @@ type fakeAPIClient struct {
 	timeout            *time.Timer
 	actionResults      []params.ActionResult
 	enqueuedActions    params.Actions
+	operationResult    params.OperationResult
+	operationResults   []params.OperationResult
+	fetchedOperations  params.Operations
+	actionFilter       params.ActionFilter
+	cancelledActions   params.Entities
+	actionLimits       params.ActionLimits
+	addedSchedules     params.ActionSchedules
+	scheduleResults    []params.ActionScheduleResult
+	removedSchedules   params.ActionScheduleIds
+	errorResults       params.ErrorResults
 	actionsByReceivers []params.ActionsByReceiver
 	actionTagMatches   params.FindTagsResults
 	charmActions       *charm.Actions
@@ -155,6 +165,21 @@ func (c *fakeAPIClient) Enqueue(args params.Actions) (params.ActionResults, erro
 	return params.ActionResults{Results: c.actionResults}, c.apiErr
 }
 
+func (c *fakeAPIClient) EnqueueOperation(args params.Actions) (params.OperationResult, error) {
+	c.enqueuedActions = args
+	return c.operationResult, c.apiErr
+}
+
+func (c *fakeAPIClient) Operations(args params.Operations) (params.OperationResults, error) {
+	c.fetchedOperations = args
+	return params.OperationResults{Results: c.operationResults}, c.apiErr
+}
+
+func (c *fakeAPIClient) FilterActions(args params.ActionFilter) (params.ActionResults, error) {
+	c.actionFilter = args
+	return params.ActionResults{Results: c.actionResults}, c.apiErr
+}
+
 func (c *fakeAPIClient) ListAll(args params.Entities) (params.ActionsByReceivers, error) {
 	return params.ActionsByReceivers{
 		Actions: c.actionsByReceivers,
@@ -173,12 +198,32 @@ func (c *fakeAPIClient) ListCompleted(args params.Entities) (params.ActionsByRec
 	}, c.apiErr
 }
 
-func (c *fakeAPIClient) Cancel(args params.Actions) (params.ActionResults, error) {
+func (c *fakeAPIClient) Cancel(args params.Entities) (params.ActionResults, error) {
+	c.cancelledActions = args
 	return params.ActionResults{
 		Results: c.actionResults,
 	}, c.apiErr
 }
 
+func (c *fakeAPIClient) SetActionLimits(args params.ActionLimits) (params.ErrorResults, error) {
+	c.actionLimits = args
+	return c.errorResults, c.apiErr
+}
+
+func (c *fakeAPIClient) AddSchedules(args params.ActionSchedules) (params.ActionScheduleResults, error) {
+	c.addedSchedules = args
+	return params.ActionScheduleResults{Results: c.scheduleResults}, c.apiErr
+}
+
+func (c *fakeAPIClient) Schedules() (params.ActionScheduleResults, error) {
+	return params.ActionScheduleResults{Results: c.scheduleResults}, c.apiErr
+}
+
+func (c *fakeAPIClient) RemoveSchedules(args params.ActionScheduleIds) (params.ErrorResults, error) {
+	c.removedSchedules = args
+	return c.errorResults, c.apiErr
+}
+
 func (c *fakeAPIClient) ServiceCharmActions(params.Entity) (*charm.Actions, error) {
 	return c.charmActions, c.apiErr
 }
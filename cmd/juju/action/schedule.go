@@ -0,0 +1,318 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/utils/cron"
+)
+
+const scheduleCmdDoc = `
+"juju action schedule" manages action schedules, on which Actions are queued
+repeatedly on a unit, or on every unit of a service. Schedules are run by the
+state server, so no external cron job is needed.
+
+A schedule is given in the five-field format of cron, as minute, hour, day of
+month, month and day of week, and is evaluated in UTC; the descriptors
+@hourly, @daily, @weekly, @monthly and @yearly may also be used.
+
+If the state server was unable to queue an Action when a schedule fell due,
+that run is not made up later. Instead, a single Action is queued for the
+most recent run due, and the number of runs missed before it is recorded on
+both the Action and the schedule. 'juju action status' shows the schedule
+that queued each Action, and the runs missed.
+`
+
+const scheduleCmdPurpose = "manage action schedules"
+
+// newScheduleSuperCommand creates the action schedule super subcommand
+// and registers the subcommands that it supports.
+func newScheduleSuperCommand() cmd.Command {
+	schedulecmd := jujucmd.NewSubSuperCommand(cmd.SuperCommandParams{
+		Name:        "schedule",
+		Doc:         scheduleCmdDoc,
+		UsagePrefix: "juju action",
+		Purpose:     scheduleCmdPurpose,
+	})
+	schedulecmd.Register(newScheduleAddCommand())
+	schedulecmd.Register(newScheduleListCommand())
+	schedulecmd.Register(newScheduleRemoveCommand())
+	return schedulecmd
+}
+
+func newScheduleAddCommand() cmd.Command {
+	return envcmd.Wrap(&scheduleAddCommand{})
+}
+
+// scheduleAddCommand adds a schedule on which an Action is queued.
+type scheduleAddCommand struct {
+	ActionCommandBase
+	receiver     names.Tag
+	actionName   string
+	schedule     string
+	paramsYAML   cmd.FileVar
+	parseStrings bool
+	args         [][]string
+	out          cmd.Output
+}
+
+const scheduleAddDoc = `
+Add a schedule on which the named Action is queued repeatedly, on the given
+unit or on every unit of the given service. The schedule must be quoted if it
+contains spaces. Params are given as for 'juju action do', and are validated
+when the schedule is added. The ID of the new schedule is displayed.
+
+Examples:
+
+$ juju action schedule add mysql/0 backup "30 2 * * *" out=backup.tar.bz2
+id: 1
+
+$ juju action schedule add logstash rotate-logs @daily
+id: 2
+
+$ juju action schedule add mysql backup "0 */6 * * mon-fri" --params p.yml
+id: 3
+`
+
+func (c *scheduleAddCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.Var(&c.paramsYAML, "params", "path to yaml-formatted params file")
+	f.BoolVar(&c.parseStrings, "string-args", false, "use raw string values of CLI args")
+}
+
+func (c *scheduleAddCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add",
+		Args:    "<unit> | <service> <action name> <schedule> [key.key.key...=value]",
+		Purpose: "add a schedule on which an action is queued",
+		Doc:     scheduleAddDoc,
+	}
+}
+
+func (c *scheduleAddCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no unit or service specified")
+	case 1:
+		return errors.New("no action specified")
+	case 2:
+		return errors.New("no schedule specified")
+	}
+	switch receiver := args[0]; {
+	case names.IsValidUnit(receiver):
+		c.receiver = names.NewUnitTag(receiver)
+	case names.IsValidService(receiver):
+		c.receiver = names.NewServiceTag(receiver)
+	default:
+		return errors.Errorf("invalid unit or service name %q", receiver)
+	}
+	if !ActionNameRule.MatchString(args[1]) {
+		return errors.Errorf("invalid action name %q", args[1])
+	}
+	c.actionName = args[1]
+	if _, err := cron.Parse(args[2]); err != nil {
+		return err
+	}
+	c.schedule = args[2]
+	parsed, err := parseActionArgs(args[3:])
+	if err != nil {
+		return err
+	}
+	c.args = parsed
+	return nil
+}
+
+func (c *scheduleAddCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	actionParams, err := buildActionParams(ctx, c.paramsYAML, c.args, c.parseStrings)
+	if err != nil {
+		return err
+	}
+	results, err := api.AddSchedules(params.ActionSchedules{
+		Schedules: []params.ActionSchedule{{
+			Receiver:   c.receiver.String(),
+			Name:       c.actionName,
+			Parameters: actionParams,
+			Schedule:   c.schedule,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != 1 {
+		return errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.Schedule == nil {
+		return errors.New("action schedule was not added")
+	}
+	return c.out.Write(ctx, map[string]string{"id": result.Schedule.Id})
+}
+
+func newScheduleListCommand() cmd.Command {
+	return envcmd.Wrap(&scheduleListCommand{})
+}
+
+// scheduleListCommand lists the environment's action schedules.
+type scheduleListCommand struct {
+	ActionCommandBase
+	out cmd.Output
+}
+
+const scheduleListDoc = `
+List the environment's action schedules, by ID, in the order in which they
+are next due to run. The time each schedule last and next runs is shown, in
+UTC, along with the total number of its runs that were missed.
+`
+
+func (c *scheduleListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *scheduleListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list action schedules",
+		Doc:     scheduleListDoc,
+		Aliases: []string{"ls"},
+	}
+}
+
+func (c *scheduleListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *scheduleListCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.Schedules()
+	if err != nil {
+		return err
+	}
+	var schedules []params.ActionSchedule
+	for _, result := range results.Results {
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.Schedule != nil {
+			schedules = append(schedules, *result.Schedule)
+		}
+	}
+	if len(schedules) == 0 {
+		ctx.Infof("no action schedules to display")
+		return nil
+	}
+	return c.out.Write(ctx, formatScheduleInfo(schedules))
+}
+
+// ScheduleInfo defines the serialization behaviour of action schedules.
+type ScheduleInfo struct {
+	Unit       string                 `yaml:"unit,omitempty" json:"unit,omitempty"`
+	Service    string                 `yaml:"service,omitempty" json:"service,omitempty"`
+	Action     string                 `yaml:"action" json:"action"`
+	Params     map[string]interface{} `yaml:"params,omitempty" json:"params,omitempty"`
+	Schedule   string                 `yaml:"schedule" json:"schedule"`
+	LastRun    string                 `yaml:"last-run,omitempty" json:"last-run,omitempty"`
+	NextRun    string                 `yaml:"next-run" json:"next-run"`
+	MissedRuns int                    `yaml:"missed-runs,omitempty" json:"missed-runs,omitempty"`
+}
+
+func formatScheduleInfo(schedules []params.ActionSchedule) map[string]ScheduleInfo {
+	output := make(map[string]ScheduleInfo)
+	for _, schedule := range schedules {
+		info := ScheduleInfo{
+			Action:     schedule.Name,
+			Params:     schedule.Parameters,
+			Schedule:   schedule.Schedule,
+			NextRun:    formatScheduleTime(schedule.NextRun),
+			LastRun:    formatScheduleTime(schedule.LastRun),
+			MissedRuns: schedule.MissedRuns,
+		}
+		tag, err := names.ParseTag(schedule.Receiver)
+		switch {
+		case err != nil:
+			info.Unit = schedule.Receiver
+		case tag.Kind() == names.ServiceTagKind:
+			info.Service = tag.Id()
+		default:
+			info.Unit = tag.Id()
+		}
+		output[schedule.Id] = info
+	}
+	return output
+}
+
+func formatScheduleTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func newScheduleRemoveCommand() cmd.Command {
+	return envcmd.Wrap(&scheduleRemoveCommand{})
+}
+
+// scheduleRemoveCommand removes action schedules.
+type scheduleRemoveCommand struct {
+	ActionCommandBase
+	ids []string
+}
+
+const scheduleRemoveDoc = `
+Remove the action schedules with the given IDs. Actions already queued by the
+schedules are unaffected; use 'juju action cancel' to cancel them.
+`
+
+func (c *scheduleRemoveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<schedule ID> ...",
+		Purpose: "remove action schedules",
+		Doc:     scheduleRemoveDoc,
+	}
+}
+
+func (c *scheduleRemoveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no schedule ID specified")
+	}
+	c.ids = args
+	return nil
+}
+
+func (c *scheduleRemoveCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.RemoveSchedules(params.ActionScheduleIds{Ids: c.ids})
+	if err != nil {
+		return err
+	}
+	return results.Combine()
+}
@@ -0,0 +1,191 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type ScheduleSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&ScheduleSuite{})
+
+func (s *ScheduleSuite) TestHelp(c *gc.C) {
+	ctx, err := testing.RunCommand(c, action.NewScheduleSuperCommand(), "--help")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Matches, "(?s)usage: juju action schedule \\[options\\] <command> .+")
+
+	var names []string
+	commandHelp := strings.SplitAfter(testing.Stdout(ctx), "commands:")[1]
+	for _, line := range strings.Split(strings.TrimSpace(commandHelp), "\n") {
+		names = append(names, strings.TrimSpace(strings.Split(line, " - ")[0]))
+	}
+	c.Check(names, jc.DeepEquals, []string{"add", "help", "list", "remove"})
+}
+
+func (s *ScheduleSuite) TestAddInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		expectError string
+	}{{
+		expectError: "no unit or service specified",
+	}, {
+		args:        []string{"mysql"},
+		expectError: "no action specified",
+	}, {
+		args:        []string{"mysql", "backup"},
+		expectError: "no schedule specified",
+	}, {
+		args:        []string{"something-strange-", "backup", "@daily"},
+		expectError: `invalid unit or service name "something-strange-"`,
+	}, {
+		args:        []string{"mysql", "BadName", "@daily"},
+		expectError: `invalid action name "BadName"`,
+	}, {
+		args:        []string{"mysql", "backup", "0 25 * * *"},
+		expectError: `invalid schedule "0 25 \* \* \*": hour "25": value 25 out of range 0-23`,
+	}, {
+		args:        []string{"mysql", "backup", "@daily", "out"},
+		expectError: `argument "out" must be of the form key...=value`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		args := append([]string{"-e", "dummyenv"}, test.args...)
+		err := testing.InitCommand(action.NewScheduleAddCommand(), args)
+		c.Check(err, gc.ErrorMatches, test.expectError)
+	}
+}
+
+func (s *ScheduleSuite) TestAddRun(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		scheduleResults: []params.ActionScheduleResult{{
+			Schedule: &params.ActionSchedule{Id: "1"},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, action.NewScheduleAddCommand(),
+		"-e", "dummyenv", "mysql", "backup", "30 2 * * *", "out=backup.tar.bz2", "level=3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "id: 1\n")
+	c.Assert(fakeClient.addedSchedules, jc.DeepEquals, params.ActionSchedules{
+		Schedules: []params.ActionSchedule{{
+			Receiver: "service-mysql",
+			Name:     "backup",
+			Parameters: map[string]interface{}{
+				"out":   "backup.tar.bz2",
+				"level": 3,
+			},
+			Schedule: "30 2 * * *",
+		}},
+	})
+}
+
+func (s *ScheduleSuite) TestAddRunError(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		scheduleResults: []params.ActionScheduleResult{{
+			Error: &params.Error{Message: `action "backup" not defined`},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := testing.RunCommand(c, action.NewScheduleAddCommand(), "-e", "dummyenv", "mysql/0", "backup", "@daily")
+	c.Assert(err, gc.ErrorMatches, `action "backup" not defined`)
+	c.Assert(fakeClient.addedSchedules.Schedules[0].Receiver, gc.Equals, "unit-mysql-0")
+}
+
+func (s *ScheduleSuite) TestList(c *gc.C) {
+	nextRun := time.Date(2015, time.October, 1, 2, 30, 0, 0, time.UTC)
+	fakeClient := &fakeAPIClient{
+		scheduleResults: []params.ActionScheduleResult{{
+			Schedule: &params.ActionSchedule{
+				Id:         "1",
+				Receiver:   "service-mysql",
+				Name:       "backup",
+				Parameters: map[string]interface{}{"out": "backup.tar.bz2"},
+				Schedule:   "30 2 * * *",
+				LastRun:    nextRun.Add(-24 * time.Hour),
+				NextRun:    nextRun,
+				MissedRuns: 2,
+			},
+		}, {
+			Schedule: &params.ActionSchedule{
+				Id:       "2",
+				Receiver: "unit-logstash-0",
+				Name:     "rotate-logs",
+				Schedule: "@daily",
+				NextRun:  nextRun.Add(time.Hour),
+			},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, action.NewScheduleListCommand(), "-e", "dummyenv")
+	c.Assert(err, jc.ErrorIsNil)
+	var schedules map[string]action.ScheduleInfo
+	err = yaml.Unmarshal([]byte(testing.Stdout(ctx)), &schedules)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(schedules, jc.DeepEquals, map[string]action.ScheduleInfo{
+		"1": {
+			Service:    "mysql",
+			Action:     "backup",
+			Params:     map[string]interface{}{"out": "backup.tar.bz2"},
+			Schedule:   "30 2 * * *",
+			LastRun:    "2015-09-30T02:30:00Z",
+			NextRun:    "2015-10-01T02:30:00Z",
+			MissedRuns: 2,
+		},
+		"2": {
+			Unit:     "logstash/0",
+			Action:   "rotate-logs",
+			Schedule: "@daily",
+			NextRun:  "2015-10-01T03:30:00Z",
+		},
+	})
+}
+
+func (s *ScheduleSuite) TestListEmpty(c *gc.C) {
+	restore := s.patchAPIClient(&fakeAPIClient{})
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, action.NewScheduleListCommand(), "-e", "dummyenv")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "")
+	c.Check(testing.Stderr(ctx), gc.Equals, "no action schedules to display\n")
+}
+
+func (s *ScheduleSuite) TestRemoveInit(c *gc.C) {
+	err := testing.InitCommand(action.NewScheduleRemoveCommand(), []string{"-e", "dummyenv"})
+	c.Assert(err, gc.ErrorMatches, "no schedule ID specified")
+}
+
+func (s *ScheduleSuite) TestRemoveRun(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		errorResults: params.ErrorResults{Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: `action schedule "7" not found`}},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := testing.RunCommand(c, action.NewScheduleRemoveCommand(), "-e", "dummyenv", "1", "7")
+	c.Assert(err, gc.ErrorMatches, `action schedule "7" not found`)
+	c.Assert(fakeClient.removedSchedules, jc.DeepEquals, params.ActionScheduleIds{
+		Ids: []string{"1", "7"},
+	})
+}
@@ -4,6 +4,8 @@
 package action
 
 import (
+	"time"
+
 	"github.com/juju/cmd"
 	errors "github.com/juju/errors"
 	"github.com/juju/names"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/common"
 )
 
 func newStatusCommand() cmd.Command {
@@ -22,15 +25,68 @@ type statusCommand struct {
 	ActionCommandBase
 	out         cmd.Output
 	requestedId string
+
+	units    []string
+	services []string
+	names    []string
+	statuses []string
+	after    string
+	before   string
+	offset   int
+	limit    int
+
+	filter    params.ActionFilter
+	filtering bool
 }
 
 const statusDoc = `
 Show the status of Actions matching given ID, partial ID prefix, or all Actions if no ID is supplied.
+
+The timeout of each Action is shown if it has one, as is the maximum number of
+units of its service that may run it at once, if the service limits it. An
+Action that is held back by that limit has the status "waiting". An Action
+queued by an action schedule shows the schedule's ID, and how many runs of the
+schedule were missed immediately before it, if any; see 'juju action schedule'.
+
+Actions, including those that have finished, may instead be selected by the
+unit or service they were queued on, their name, their status and the time
+they were queued. Times may be given in RFC3339 format, or as a duration
+before now. The most recently queued Actions are shown first, and --offset
+and --limit may be used to page through long histories. Finished Actions are
+removed from the history according to the max-action-results-age and
+max-action-results-count environment settings.
+
+Examples:
+
+$ juju action status
+$ juju action status 7fa9
+$ juju action status --service mysql --name backup --status failed
+$ juju action status --unit mysql/0 --after 24h --limit 10
 `
 
+// validActionStatuses holds the Action statuses that may be given to
+// the --status flag.
+var validActionStatuses = []string{
+	params.ActionPending,
+	params.ActionWaiting,
+	params.ActionRunning,
+	params.ActionCancelling,
+	params.ActionCompleted,
+	params.ActionFailed,
+	params.ActionCancelled,
+}
+
 // Set up the output.
 func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.Var(cmd.NewAppendStringsValue(&c.units), "unit", "only show actions queued on these units")
+	f.Var(cmd.NewAppendStringsValue(&c.services), "service", "only show actions queued on units of these services")
+	f.Var(cmd.NewAppendStringsValue(&c.names), "name", "only show actions with these names")
+	f.Var(cmd.NewAppendStringsValue(&c.statuses), "status", "only show actions with these statuses")
+	f.StringVar(&c.after, "after", "", "only show actions queued at or after this time")
+	f.StringVar(&c.before, "before", "", "only show actions queued before this time")
+	f.IntVar(&c.offset, "offset", 0, "skip this many of the most recently queued actions")
+	f.IntVar(&c.limit, "limit", 0, "show at most this many actions (0 for all)")
 }
 
 func (c *statusCommand) Info() *cmd.Info {
@@ -46,13 +102,80 @@ func (c *statusCommand) Init(args []string) error {
 	switch len(args) {
 	case 0:
 		c.requestedId = ""
-		return nil
 	case 1:
 		c.requestedId = args[0]
-		return nil
 	default:
 		return cmd.CheckEmpty(args[1:])
 	}
+	if err := c.initFilter(); err != nil {
+		return err
+	}
+	if c.filtering && c.requestedId != "" {
+		return errors.New("cannot filter actions given by ID")
+	}
+	return nil
+}
+
+// initFilter checks the filter flags, and builds the filter used to
+// select Actions from the environment's action history.
+func (c *statusCommand) initFilter() error {
+	filter := params.ActionFilter{
+		Names:    c.names,
+		Statuses: c.statuses,
+		Offset:   c.offset,
+		Limit:    c.limit,
+	}
+	for _, unit := range c.units {
+		if !names.IsValidUnit(unit) {
+			return errors.Errorf("invalid unit name %q", unit)
+		}
+		filter.Units = append(filter.Units, names.NewUnitTag(unit).String())
+	}
+	for _, service := range c.services {
+		if !names.IsValidService(service) {
+			return errors.Errorf("invalid service name %q", service)
+		}
+		filter.Services = append(filter.Services, names.NewServiceTag(service).String())
+	}
+	for _, name := range c.names {
+		if !ActionNameRule.MatchString(name) {
+			return errors.Errorf("invalid action name %q", name)
+		}
+	}
+	for _, status := range c.statuses {
+		if !isValidActionStatus(status) {
+			return errors.Errorf("invalid status %q; expected one of %v", status, validActionStatuses)
+		}
+	}
+	if c.offset < 0 {
+		return errors.Errorf("invalid offset %d", c.offset)
+	}
+	if c.limit < 0 {
+		return errors.Errorf("invalid limit %d", c.limit)
+	}
+	now := time.Now()
+	var err error
+	if filter.After, err = common.ParseTimeOrDuration(c.after, now); err != nil {
+		return errors.Annotate(err, "invalid --after value")
+	}
+	if filter.Before, err = common.ParseTimeOrDuration(c.before, now); err != nil {
+		return errors.Annotate(err, "invalid --before value")
+	}
+	c.filter = filter
+	c.filtering = len(filter.Units) > 0 || len(filter.Services) > 0 ||
+		len(filter.Names) > 0 || len(filter.Statuses) > 0 ||
+		filter.After != nil || filter.Before != nil ||
+		filter.Offset > 0 || filter.Limit > 0
+	return nil
+}
+
+func isValidActionStatus(status string) bool {
+	for _, valid := range validActionStatuses {
+		if status == valid {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *statusCommand) Run(ctx *cmd.Context) error {
@@ -62,6 +185,17 @@ func (c *statusCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	if c.filtering {
+		actions, err := api.FilterActions(c.filter)
+		if err != nil {
+			return err
+		}
+		if len(actions.Results) < 1 {
+			return errors.Errorf("no actions found")
+		}
+		return c.out.Write(ctx, resultsToMap(actions.Results))
+	}
+
 	actionTags, err := getActionTagsByPrefix(api, c.requestedId)
 	if err != nil {
 		return err
@@ -120,6 +254,18 @@ func resultToMap(result params.ActionResult) map[string]interface{} {
 			item["unit"] = rtag.Id()
 		}
 
+		if result.Action.Timeout > 0 {
+			item["timeout"] = result.Action.Timeout.String()
+		}
+	}
+	if result.Limit > 0 {
+		item["limit"] = result.Limit
+	}
+	if result.Schedule != "" {
+		item["schedule"] = result.Schedule
+		if result.MissedRuns > 0 {
+			item["missed-runs"] = result.MissedRuns
+		}
 	}
 	item["status"] = result.Status
 	return item
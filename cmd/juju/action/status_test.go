@@ -70,6 +70,139 @@ func (s *StatusSuite) TestRun(c *gc.C) {
 	}
 }
 
+func (s *StatusSuite) TestRunShowsLimits(c *gc.C) {
+	results := []params.ActionResult{{
+		Action: &params.Action{
+			Tag:      validActionTagString,
+			Receiver: "unit-mysql-0",
+			Timeout:  10 * time.Minute,
+		},
+		Status: params.ActionWaiting,
+		Limit:  2,
+	}}
+	fakeClient := makeFakeClient(0, 5*time.Second, tagsForIdPrefix(validActionId, validActionTagString), results, "")
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, action.NewStatusCommand(), "-e", "dummyenv", validActionId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+actions:
+- id: `+validActionId+`
+  limit: 2
+  status: waiting
+  timeout: 10m0s
+  unit: mysql/0
+`[1:])
+}
+
+func (s *StatusSuite) TestRunShowsSchedule(c *gc.C) {
+	results := []params.ActionResult{{
+		Action: &params.Action{
+			Tag:      validActionTagString,
+			Receiver: "unit-mysql-0",
+		},
+		Status:     params.ActionPending,
+		Schedule:   "3",
+		MissedRuns: 2,
+	}}
+	fakeClient := makeFakeClient(0, 5*time.Second, tagsForIdPrefix(validActionId, validActionTagString), results, "")
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, action.NewStatusCommand(), "-e", "dummyenv", validActionId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+actions:
+- id: `+validActionId+`
+  missed-runs: 2
+  schedule: "3"
+  status: pending
+  unit: mysql/0
+`[1:])
+}
+
+func (s *StatusSuite) TestInitFilterErrors(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		expectError string
+	}{{
+		args:        []string{"deadbeef", "--status", "failed"},
+		expectError: "cannot filter actions given by ID",
+	}, {
+		args:        []string{"--unit", "mysql"},
+		expectError: `invalid unit name "mysql"`,
+	}, {
+		args:        []string{"--service", "mysql/0"},
+		expectError: `invalid service name "mysql/0"`,
+	}, {
+		args:        []string{"--name", "BadName"},
+		expectError: `invalid action name "BadName"`,
+	}, {
+		args:        []string{"--status", "lost"},
+		expectError: `invalid status "lost"; expected one of .*`,
+	}, {
+		args:        []string{"--limit", "-1"},
+		expectError: "invalid limit -1",
+	}, {
+		args:        []string{"--offset", "-1"},
+		expectError: "invalid offset -1",
+	}, {
+		args:        []string{"--after", "yesterday"},
+		expectError: `invalid --after value: expected RFC3339 time or duration, got "yesterday"`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		args := append([]string{"-e", "dummyenv"}, test.args...)
+		err := testing.InitCommand(action.NewStatusCommand(), args)
+		c.Check(err, gc.ErrorMatches, test.expectError)
+	}
+}
+
+func (s *StatusSuite) TestRunFilter(c *gc.C) {
+	results := []params.ActionResult{{
+		Action: &params.Action{
+			Tag:      validActionTagString,
+			Receiver: "unit-mysql-0",
+		},
+		Status: params.ActionFailed,
+	}}
+	fakeClient := &fakeAPIClient{actionResults: results}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, action.NewStatusCommand(), "-e", "dummyenv",
+		"--unit", "mysql/0", "--service", "wordpress", "--name", "backup",
+		"--status", "failed", "--after", "2015-10-01T00:00:00Z",
+		"--offset", "10", "--limit", "5",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	after := time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC)
+	c.Check(fakeClient.actionFilter, jc.DeepEquals, params.ActionFilter{
+		Units:    []string{"unit-mysql-0"},
+		Services: []string{"service-wordpress"},
+		Names:    []string{"backup"},
+		Statuses: []string{"failed"},
+		After:    &after,
+		Offset:   10,
+		Limit:    5,
+	})
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+actions:
+- id: `+validActionId+`
+  status: failed
+  unit: mysql/0
+`[1:])
+}
+
+func (s *StatusSuite) TestRunFilterNoResults(c *gc.C) {
+	fakeClient := &fakeAPIClient{}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := testing.RunCommand(c, action.NewStatusCommand(), "-e", "dummyenv", "--status", "failed")
+	c.Assert(err, gc.ErrorMatches, "no actions found")
+}
+
 func (s *StatusSuite) runTestCase(c *gc.C, tc statusTestCase) {
 	fakeClient := makeFakeClient(
 		0*time.Second, // No API delay
@@ -6,7 +6,10 @@ package backups
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -62,6 +65,7 @@ func NewSuperCommand() cmd.Command {
 	backupsCmd.Register(newUploadCommand())
 	backupsCmd.Register(newRemoveCommand())
 	backupsCmd.Register(newRestoreCommand())
+	backupsCmd.Register(newVerifyCommand())
 	return &backupsCmd
 }
 
@@ -70,7 +74,7 @@ func NewSuperCommand() cmd.Command {
 type APIClient interface {
 	io.Closer
 	// Create sends an RPC request to create a new backup.
-	Create(notes string) (*params.BackupsMetadataResult, error)
+	Create(notes string, encryptionKey []byte, exclude, include []string) (*params.BackupsMetadataResult, error)
 	// Info gets the backup's metadata.
 	Info(id string) (*params.BackupsMetadataResult, error)
 	// List gets all stored metadata.
@@ -82,9 +86,9 @@ type APIClient interface {
 	// Remove removes the stored backup.
 	Remove(id string) error
 	// Restore will restore a backup with the given id into the state server.
-	Restore(string, backups.ClientConnection) error
+	Restore(string, []byte, time.Time, backups.ClientConnection) error
 	// Restore will restore a backup file into the state server.
-	RestoreReader(io.ReadSeeker, *params.BackupsMetadataResult, backups.ClientConnection) error
+	RestoreReader(io.ReadSeeker, *params.BackupsMetadataResult, []byte, backups.ClientConnection) error
 }
 
 // CommandBase is the base type for backups sub-commands.
@@ -116,6 +120,10 @@ func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetad
 	fmt.Fprintf(ctx.Stdout, "started:         %v\n", result.Started)
 	fmt.Fprintf(ctx.Stdout, "finished:        %v\n", result.Finished)
 	fmt.Fprintf(ctx.Stdout, "notes:           %q\n", result.Notes)
+	fmt.Fprintf(ctx.Stdout, "scheduled:       %v\n", result.Scheduled)
+	fmt.Fprintf(ctx.Stdout, "encrypted:       %v\n", result.Encrypted)
+	fmt.Fprintf(ctx.Stdout, "key fingerprint: %q\n", result.KeyFingerprint)
+	fmt.Fprintf(ctx.Stdout, "excluded:        %q\n", strings.Join(result.Excluded, ","))
 
 	fmt.Fprintf(ctx.Stdout, "environment ID:  %q\n", result.Environment)
 	fmt.Fprintf(ctx.Stdout, "machine ID:      %q\n", result.Machine)
@@ -128,7 +136,41 @@ type readSeekCloser interface {
 	io.Closer
 }
 
-func getArchive(filename string) (rc readSeekCloser, metaResult *params.BackupsMetadataResult, err error) {
+// readKey reads the OpenPGP key in the named file, returning both its
+// raw and parsed forms.
+func readKey(filename string) ([]byte, *statebackups.Key, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	key, err := statebackups.ParseKey(data)
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "cannot read key from %q", filename)
+	}
+	return data, key, nil
+}
+
+// readDecryptionKey reads the OpenPGP private key in the named file,
+// returning both its raw and parsed forms. If no file is named, no key
+// is returned.
+func readDecryptionKey(filename string) ([]byte, *statebackups.Key, error) {
+	if filename == "" {
+		return nil, nil, nil
+	}
+	data, key, err := readKey(filename)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if !key.HasPrivate() {
+		return nil, nil, errors.Errorf("%q holds no private key; a private key is needed to decrypt a backup", filename)
+	}
+	return data, key, nil
+}
+
+// getArchive opens the named backup archive and reads its metadata. An
+// encrypted archive is decrypted with the given key to do so, but the
+// archive returned is left as it is.
+func getArchive(filename string, key *statebackups.Key) (rc readSeekCloser, metaResult *params.BackupsMetadataResult, err error) {
 	defer func() {
 		if err != nil && rc != nil {
 			rc.Close()
@@ -141,7 +183,7 @@ func getArchive(filename string) (rc readSeekCloser, metaResult *params.BackupsM
 	}
 
 	// Extract the metadata.
-	ad, err := statebackups.NewArchiveDataReader(archive)
+	ad, err := statebackups.NewArchiveDataReader(archive, key)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
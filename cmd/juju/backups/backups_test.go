@@ -21,6 +21,7 @@ var expectedSubCommmandNames = []string{
 	"remove",
 	"restore",
 	"upload",
+	"verify",
 }
 
 type backupsSuite struct {
@@ -41,6 +41,24 @@ Therefore, you should use the --download or --filename options, or use
 "juju backups download", to get a local copy of the backup archive.
 This local copy can then be used to restore an environment even if that
 environment was already destroyed or is otherwise unavailable.
+
+Backup archives hold the credentials of the state server.  To protect
+them, the archive may be encrypted with an OpenPGP public key, such as is
+exported by "gpg --export", given with the --encrypt-with option.  If
+it is not given, the key in the environment's backup-encryption-key
+setting, if any, is used.  Only the public key is sent to the state
+server; the matching private key, which must not be protected by a
+passphrase, is needed to restore the backup.
+
+The contents of some databases and collections, such as the logs, may
+be much larger than the rest of juju's state and not worth keeping.
+The --exclude option takes a comma-separated list of databases ("logs")
+and collections ("juju.statuseshistory") to leave out of the backup, in
+addition to those in the environment's backup-exclude setting; the
+--include option names any of those that should be backed up anyway.
+Only logs, logs.logs, juju.auditevents, juju.metrics and
+juju.statuseshistory may be left out.  Excluded collections are
+recreated empty when the backup is restored.
 `
 
 func newCreateCommand() cmd.Command {
@@ -58,6 +76,15 @@ type createCommand struct {
 	Filename string
 	// Notes is the custom message to associated with the new backup.
 	Notes string
+	// EncryptWith is the file holding the public key with which the
+	// backup archive is encrypted.
+	EncryptWith string
+	// Exclude holds the databases and collections whose contents are
+	// left out of the backup.
+	Exclude []string
+	// Include holds the databases and collections that are backed up
+	// even though the environment excludes them.
+	Include []string
 }
 
 // Info implements Command.Info.
@@ -75,6 +102,9 @@ func (c *createCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Quiet, "quiet", false, "do not print the metadata")
 	f.BoolVar(&c.NoDownload, "no-download", false, "do not download the archive")
 	f.StringVar(&c.Filename, "filename", notset, "download to this file")
+	f.StringVar(&c.EncryptWith, "encrypt-with", "", "encrypt the archive with the OpenPGP public key in this file")
+	f.Var(cmd.NewStringsValue(nil, &c.Exclude), "exclude", "leave out these databases and collections")
+	f.Var(cmd.NewStringsValue(nil, &c.Include), "include", "back up these databases and collections even if the environment excludes them")
 }
 
 // Init implements Command.Init.
@@ -97,13 +127,25 @@ func (c *createCommand) Init(args []string) error {
 
 // Run implements Command.Run.
 func (c *createCommand) Run(ctx *cmd.Context) error {
+	var encryptionKey []byte
+	if c.EncryptWith != "" {
+		data, key, err := readKey(c.EncryptWith)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if key.HasPrivate() {
+			return errors.Errorf("%q holds a private key; encrypt with the public key instead", c.EncryptWith)
+		}
+		encryptionKey = data
+	}
+
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return errors.Trace(err)
 	}
 	defer client.Close()
 
-	result, err := client.Create(c.Notes)
+	result, err := client.Create(c.Notes, encryptionKey, c.Exclude, c.Include)
 	if err != nil {
 		return errors.Trace(err)
 	}
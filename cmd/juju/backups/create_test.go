@@ -5,6 +5,8 @@ package backups_test
 
 import (
 	"bytes"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 
 	"github.com/juju/cmd"
@@ -143,3 +145,37 @@ func (s *createSuite) TestError(c *gc.C) {
 
 	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
 }
+
+func (s *createSuite) TestEncryptWith(c *gc.C) {
+	keyFile := filepath.Join(c.MkDir(), "backup.pub")
+	err := ioutil.WriteFile(keyFile, []byte(testing.OpenPGPPublicKey), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.setSuccess()
+	_, err = testing.RunCommand(c, s.wrappedCommand, "--no-download", "--encrypt-with", keyFile)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, "", "", "Create")
+	c.Check(string(client.keyArg), gc.Equals, testing.OpenPGPPublicKey)
+}
+
+func (s *createSuite) TestExclude(c *gc.C) {
+	client := s.setSuccess()
+	_, err := testing.RunCommand(c, s.wrappedCommand, "--no-download",
+		"--exclude", "logs,juju.metrics", "--include", "juju.statuseshistory")
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, "", "", "Create")
+	c.Check(client.exclude, jc.DeepEquals, []string{"logs", "juju.metrics"})
+	c.Check(client.include, jc.DeepEquals, []string{"juju.statuseshistory"})
+}
+
+func (s *createSuite) TestEncryptWithPrivateKey(c *gc.C) {
+	keyFile := filepath.Join(c.MkDir(), "backup.key")
+	err := ioutil.WriteFile(keyFile, []byte(testing.OpenPGPPrivateKey), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.setSuccess()
+	_, err = testing.RunCommand(c, s.wrappedCommand, "--no-download", "--encrypt-with", keyFile)
+	c.Check(err, gc.ErrorMatches, `".*backup.key" holds a private key; encrypt with the public key instead`)
+}
@@ -22,6 +22,9 @@ var (
 	NewUploadCommand  = newUploadCommand
 	NewRemoveCommand  = newRemoveCommand
 	NewRestoreCommand = newRestoreCommand
+	NewVerifyCommand  = newVerifyCommand
+
+	VerifyArchive = &verifyArchive
 )
 
 type CreateCommand struct {
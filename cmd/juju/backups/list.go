@@ -14,7 +14,9 @@ import (
 )
 
 const listDoc = `
-"list" provides the metadata associated with all backups.
+"list" provides the metadata associated with all backups. Backups made
+automatically, according to the environment's backup-interval setting, are
+shown as scheduled; those made with "juju backups create" are not.
 `
 
 func newListCommand() cmd.Command {
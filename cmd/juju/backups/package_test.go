@@ -10,6 +10,7 @@ import (
 	"os"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -33,6 +34,10 @@ stored:          0001-01-01 00:00:00 +0000 UTC
 started:         0001-01-01 00:00:00 +0000 UTC
 finished:        0001-01-01 00:00:00 +0000 UTC
 notes:           ""
+scheduled:       false
+encrypted:       false
+key fingerprint: ""
+excluded:        ""
 environment ID:  ""
 machine ID:      ""
 created on host: ""
@@ -144,10 +149,14 @@ type fakeAPIClient struct {
 	archive    io.ReadCloser
 	err        error
 
-	calls []string
-	args  []string
-	idArg string
-	notes string
+	calls  []string
+	args   []string
+	idArg  string
+	notes  string
+	keyArg []byte
+	// exclude and include hold the arguments to Create.
+	exclude []string
+	include []string
 }
 
 func (f *fakeAPIClient) Check(c *gc.C, id, notes string, calls ...string) {
@@ -156,10 +165,13 @@ func (f *fakeAPIClient) Check(c *gc.C, id, notes string, calls ...string) {
 	c.Check(f.notes, gc.Equals, notes)
 }
 
-func (c *fakeAPIClient) Create(notes string) (*params.BackupsMetadataResult, error) {
+func (c *fakeAPIClient) Create(notes string, encryptionKey []byte, exclude, include []string) (*params.BackupsMetadataResult, error) {
 	c.calls = append(c.calls, "Create")
-	c.args = append(c.args, "notes")
+	c.args = append(c.args, "notes", "encryptionKey", "exclude", "include")
 	c.notes = notes
+	c.keyArg = encryptionKey
+	c.exclude = exclude
+	c.include = include
 	if c.err != nil {
 		return nil, c.err
 	}
@@ -218,10 +230,10 @@ func (c *fakeAPIClient) Close() error {
 	return nil
 }
 
-func (c *fakeAPIClient) RestoreReader(io.ReadSeeker, *params.BackupsMetadataResult, apibackups.ClientConnection) error {
+func (c *fakeAPIClient) RestoreReader(io.ReadSeeker, *params.BackupsMetadataResult, []byte, apibackups.ClientConnection) error {
 	return nil
 }
 
-func (c *fakeAPIClient) Restore(string, apibackups.ClientConnection) error {
+func (c *fakeAPIClient) Restore(string, []byte, time.Time, apibackups.ClientConnection) error {
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -33,6 +34,9 @@ type restoreCommand struct {
 	filename    string
 	backupId    string
 	bootstrap   bool
+	decryptWith string
+	recoverTo   string
+	recoverTime time.Time
 }
 
 var restoreDoc = `
@@ -51,6 +55,18 @@ If the provided state cannot be restored, this command will fail with
 an appropriate message.  For instance, if the existing bootstrap
 instance is already running then the command will fail with a message
 to that effect.
+
+An encrypted backup is decrypted with the OpenPGP private key given with
+the --decrypt-with option, which must match the key with which it was
+encrypted and must not be protected by a passphrase.  The private key is
+sent to the state server for the duration of the restore.
+
+If the environment's backup-oplog-interval setting is enabled, the
+database operations made after each backup are captured and kept with
+it.  A backup restored by ID with the --to option is then brought
+forward to the state at the given time, in RFC 3339 form (for example
+"2015-06-01T10:30:00Z"), by replaying the operations made up to it.
+This can recover from a mistake made minutes after the last backup.
 `
 
 // Info returns the content for --help.
@@ -71,6 +87,8 @@ func (c *restoreCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.bootstrap, "b", false, "bootstrap a new state machine")
 	f.StringVar(&c.filename, "file", "", "provide a file to be used as the backup.")
 	f.StringVar(&c.backupId, "id", "", "provide the name of the backup to be restored.")
+	f.StringVar(&c.decryptWith, "decrypt-with", "", "decrypt the backup with the OpenPGP private key in this file")
+	f.StringVar(&c.recoverTo, "to", "", "recover the state at this time, after the backup was made")
 }
 
 // Init is where the preconditions for this commands can be checked.
@@ -84,6 +102,16 @@ func (c *restoreCommand) Init(args []string) error {
 	if c.backupId != "" && c.bootstrap {
 		return errors.Errorf("it is not possible to rebootstrap and restore from an id.")
 	}
+	if c.recoverTo != "" {
+		if c.backupId == "" {
+			return errors.Errorf("it is only possible to recover to a time when restoring from an id.")
+		}
+		t, err := time.Parse(time.RFC3339, c.recoverTo)
+		if err != nil {
+			return errors.Errorf("invalid time %q; expected RFC 3339 form, such as %q", c.recoverTo, "2015-06-01T10:30:00Z")
+		}
+		c.recoverTime = t.UTC()
+	}
 	var err error
 	if c.filename != "" {
 		c.filename, err = filepath.Abs(c.filename)
@@ -100,6 +128,10 @@ const restoreAPIIncompatibility = "server version not compatible for " +
 // runRestore will implement the actual calls to the different Client parts
 // of restore.
 func (c *restoreCommand) runRestore(ctx *cmd.Context) error {
+	keyData, key, err := readDecryptionKey(c.decryptWith)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	client, closer, err := c.newClient()
 	if err != nil {
 		return errors.Trace(err)
@@ -109,16 +141,16 @@ func (c *restoreCommand) runRestore(ctx *cmd.Context) error {
 	var rErr error
 	if c.filename != "" {
 		target = c.filename
-		archive, meta, err := getArchive(c.filename)
+		archive, meta, err := getArchive(c.filename, key)
 		if err != nil {
 			return errors.Trace(err)
 		}
 		defer archive.Close()
 
-		rErr = client.RestoreReader(archive, meta, c.newClient)
+		rErr = client.RestoreReader(archive, meta, keyData, c.newClient)
 	} else {
 		target = c.backupId
-		rErr = client.Restore(c.backupId, c.newClient)
+		rErr = client.Restore(c.backupId, keyData, c.recoverTime, c.newClient)
 	}
 	if params.IsCodeNotImplemented(rErr) {
 		return errors.Errorf(restoreAPIIncompatibility)
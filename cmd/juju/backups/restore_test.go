@@ -32,4 +32,10 @@ func (s *restoreSuite) TestRestoreArgs(c *gc.C) {
 
 	_, err = testing.RunCommand(c, s.command, "restore", "--id", "anid", "-b")
 	c.Assert(err, gc.ErrorMatches, "it is not possible to rebootstrap and restore from an id.")
+
+	_, err = testing.RunCommand(c, s.command, "restore", "--file", "afile", "--to", "2015-06-01T10:30:00Z")
+	c.Assert(err, gc.ErrorMatches, "it is only possible to recover to a time when restoring from an id.")
+
+	_, err = testing.RunCommand(c, s.command, "restore", "--id", "anid", "--to", "yesterday")
+	c.Assert(err, gc.ErrorMatches, `invalid time "yesterday"; expected RFC 3339 form, such as "2015-06-01T10:30:00Z"`)
 }
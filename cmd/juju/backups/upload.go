@@ -16,6 +16,10 @@ import (
 
 const uploadDoc = `
 "upload" sends a backup archive file to remote storage.
+
+The metadata of an encrypted archive can only be read with the OpenPGP
+private key with which it is decrypted, given with the --decrypt-with
+option; the archive is stored still encrypted.
 `
 
 func newUploadCommand() cmd.Command {
@@ -31,12 +35,16 @@ type uploadCommand struct {
 	ShowMeta bool
 	// Quiet indicates that the new backup ID should not be printed.
 	Quiet bool
+	// DecryptWith is the file holding the private key with which an
+	// encrypted archive is decrypted, to read its metadata.
+	DecryptWith string
 }
 
 // SetFlags implements Command.SetFlags.
 func (c *uploadCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.ShowMeta, "verbose", false, "show the uploaded metadata")
 	f.BoolVar(&c.Quiet, "quiet", false, "do not print the new backup ID")
+	f.StringVar(&c.DecryptWith, "decrypt-with", "", "read an encrypted archive with the OpenPGP private key in this file")
 }
 
 // Info implements Command.Info.
@@ -70,7 +78,11 @@ func (c *uploadCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
-	archive, meta, err := getArchive(c.Filename)
+	_, key, err := readDecryptionKey(c.DecryptWith)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	archive, meta, err := getArchive(c.Filename, key)
 	if err != nil {
 		return errors.Trace(err)
 	}
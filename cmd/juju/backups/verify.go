@@ -0,0 +1,149 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	statebackups "github.com/juju/juju/state/backups"
+)
+
+const verifyDoc = `
+"verify" checks that a backup could be restored, without restoring it. The
+backup is given either as the ID of a stored backup, which is downloaded,
+or as the name of a local archive file.
+
+The archive is unpacked locally and checked for the files that every
+backup must hold. Its database dump is then restored into a temporary
+mongod, started just for the purpose, and the environments found in it
+are listed with their numbers of machines and units. Verification needs
+mongod and mongorestore to be installed locally; nothing is restored to,
+or changed on, the state server.
+
+An encrypted archive is decrypted with the private key in the file given
+by --decrypt-with.
+`
+
+// verifyArchive is replaced in tests.
+var verifyArchive = statebackups.Verify
+
+func newVerifyCommand() cmd.Command {
+	return envcmd.Wrap(&verifyCommand{})
+}
+
+// verifyCommand is the sub-command for verifying that a backup could be
+// restored.
+type verifyCommand struct {
+	CommandBase
+	// Backup is the ID of the backup, or the name of the archive file,
+	// to verify.
+	Backup string
+	// DecryptWith is the file holding the key with which an encrypted
+	// archive is decrypted.
+	DecryptWith string
+}
+
+// Info implements Command.Info.
+func (c *verifyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "verify",
+		Args:    "<ID> | <filename>",
+		Purpose: "check that a backup could be restored",
+		Doc:     verifyDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *verifyCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.DecryptWith, "decrypt-with", "", "file holding the private key with which to decrypt the archive")
+}
+
+// Init implements Command.Init.
+func (c *verifyCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing ID or filename")
+	}
+	backup, args := args[0], args[1:]
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	c.Backup = backup
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *verifyCommand) Run(ctx *cmd.Context) error {
+	_, key, err := readDecryptionKey(c.DecryptWith)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	archive, err := c.open()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+
+	result, err := verifyArchive(archive, key)
+	if err != nil {
+		return errors.Annotatef(err, "backup %q is not restorable", c.Backup)
+	}
+
+	meta := result.Metadata
+	fmt.Fprintf(ctx.Stdout, "backup:          %q\n", c.Backup)
+	fmt.Fprintf(ctx.Stdout, "started:         %v\n", meta.Started)
+	fmt.Fprintf(ctx.Stdout, "environment ID:  %q\n", meta.Origin.Environment)
+	fmt.Fprintf(ctx.Stdout, "machine ID:      %q\n", meta.Origin.Machine)
+	fmt.Fprintf(ctx.Stdout, "created on host: %q\n", meta.Origin.Hostname)
+	fmt.Fprintf(ctx.Stdout, "juju version:    %v\n", meta.Origin.Version)
+	fmt.Fprintf(ctx.Stdout, "environments:\n")
+	for _, env := range result.Environments {
+		fmt.Fprintf(ctx.Stdout, "  %s (%s): %d machines, %d units\n", env.Name, env.UUID, env.Machines, env.Units)
+	}
+	return nil
+}
+
+// open opens the archive to verify: the named file, if it exists, or
+// otherwise the stored backup with that ID.
+func (c *verifyCommand) open() (io.ReadCloser, error) {
+	archive, err := os.Open(c.Backup)
+	if err == nil {
+		return archive, nil
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Trace(err)
+	}
+
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	download, err := client.Download(c.Backup)
+	if err != nil {
+		client.Close()
+		return nil, errors.Trace(err)
+	}
+	return &downloadedArchive{download, client}, nil
+}
+
+// downloadedArchive is an archive being downloaded with the client,
+// which is closed along with it.
+type downloadedArchive struct {
+	io.ReadCloser
+	client APIClient
+}
+
+// Close closes the archive and the client.
+func (a *downloadedArchive) Close() error {
+	archiveErr := a.ReadCloser.Close()
+	if err := a.client.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(archiveErr)
+}
@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/backups"
+	statebackups "github.com/juju/juju/state/backups"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+type verifySuite struct {
+	BaseBackupsSuite
+	subcommand cmd.Command
+	result     *statebackups.Verification
+	verified   string
+}
+
+var _ = gc.Suite(&verifySuite{})
+
+func (s *verifySuite) SetUpTest(c *gc.C) {
+	s.BaseBackupsSuite.SetUpTest(c)
+	s.subcommand = backups.NewVerifyCommand()
+
+	meta := statebackups.NewMetadata()
+	meta.Started = time.Date(2015, 6, 1, 10, 30, 0, 0, time.UTC)
+	meta.Origin.Environment = "49db53ac-a42f-4ab2-86e1-0c6fa0fec762"
+	meta.Origin.Machine = "0"
+	meta.Origin.Hostname = "main-host"
+	meta.Origin.Version = version.MustParse("1.25.0")
+	s.result = &statebackups.Verification{
+		Metadata: meta,
+		Environments: []statebackups.EnvironmentSummary{{
+			UUID:     "49db53ac-a42f-4ab2-86e1-0c6fa0fec762",
+			Name:     "admin",
+			Machines: 3,
+			Units:    5,
+		}},
+	}
+	s.PatchValue(backups.VerifyArchive, func(r io.Reader, key *statebackups.Key) (*statebackups.Verification, error) {
+		data, err := ioutil.ReadAll(r)
+		c.Assert(err, jc.ErrorIsNil)
+		s.verified = string(data)
+		c.Check(key, gc.IsNil)
+		return s.result, nil
+	})
+}
+
+func (s *verifySuite) expectedOutput(backup string) string {
+	return `
+backup:          "` + backup + `"
+started:         2015-06-01 10:30:00 +0000 UTC
+environment ID:  "49db53ac-a42f-4ab2-86e1-0c6fa0fec762"
+machine ID:      "0"
+created on host: "main-host"
+juju version:    1.25.0
+environments:
+  admin (49db53ac-a42f-4ab2-86e1-0c6fa0fec762): 3 machines, 5 units
+`[1:]
+}
+
+func (s *verifySuite) TestHelp(c *gc.C) {
+	s.checkHelp(c, s.subcommand)
+}
+
+func (s *verifySuite) TestMissingArgs(c *gc.C) {
+	_, err := testing.RunCommand(c, s.subcommand)
+	c.Check(err, gc.ErrorMatches, "missing ID or filename")
+}
+
+func (s *verifySuite) TestTooManyArgs(c *gc.C) {
+	_, err := testing.RunCommand(c, s.subcommand, "spam", "eggs")
+	c.Check(err, gc.ErrorMatches, `unrecognized args: \["eggs"\]`)
+}
+
+func (s *verifySuite) TestFile(c *gc.C) {
+	client := s.setSuccess()
+	filename := filepath.Join(c.MkDir(), "backup.tar.gz")
+	err := ioutil.WriteFile(filename, []byte("<local archive>"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := testing.RunCommand(c, s.subcommand, filename)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.verified, gc.Equals, "<local archive>")
+	c.Check(client.calls, gc.HasLen, 0)
+	s.checkStd(c, ctx, s.expectedOutput(filename), "")
+}
+
+func (s *verifySuite) TestDownload(c *gc.C) {
+	client := s.setDownload()
+	ctx, err := testing.RunCommand(c, s.subcommand, s.metaresult.ID)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(client.calls, jc.DeepEquals, []string{"Download"})
+	c.Check(client.idArg, gc.Equals, s.metaresult.ID)
+	c.Check(s.verified, gc.Equals, s.data)
+	s.checkStd(c, ctx, s.expectedOutput(s.metaresult.ID), "")
+}
+
+func (s *verifySuite) TestDownloadError(c *gc.C) {
+	s.setFailure("failed!")
+	_, err := testing.RunCommand(c, s.subcommand, s.metaresult.ID)
+	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
+}
+
+func (s *verifySuite) TestNotRestorable(c *gc.C) {
+	s.setDownload()
+	s.PatchValue(backups.VerifyArchive, func(io.Reader, *statebackups.Key) (*statebackups.Verification, error) {
+		return nil, errors.New("archive is missing /var/lib/juju/server.pem")
+	})
+	_, err := testing.RunCommand(c, s.subcommand, s.metaresult.ID)
+	c.Check(err, gc.ErrorMatches, `backup "spam" is not restorable: archive is missing /var/lib/juju/server.pem`)
+}
@@ -0,0 +1,119 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func newAttachCommand() cmd.Command {
+	return envcmd.Wrap(&attachCommand{})
+}
+
+const attachDoc = `
+Upload files as new revisions of resources declared in the metadata of a
+service's charm. The files are kept by the juju controller, and the
+service's units run the upgrade-charm hook so that their charms can
+fetch them with the resource-get hook tool.
+
+Examples:
+    juju attach wordpress software=./wordpress-4.3.tgz
+    juju attach wordpress software=./wordpress-4.3.tgz theme=./theme.zip
+`
+
+// attachCommand uploads resources for a service.
+type attachCommand struct {
+	envcmd.EnvCommandBase
+	serviceName string
+	resources   []resourceFile
+}
+
+// resourceFile associates a resource name with the path of the file
+// to upload for it.
+type resourceFile struct {
+	name string
+	path string
+}
+
+func (c *attachCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "attach",
+		Args:    "<service> <resource>=<path> [...]",
+		Purpose: "upload resources for a service",
+		Doc:     attachDoc,
+	}
+}
+
+func (c *attachCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return errors.Errorf("invalid service name %q", args[0])
+	}
+	c.serviceName = args[0]
+	if len(args) == 1 {
+		return errors.New("no resources specified")
+	}
+	for _, arg := range args[1:] {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return errors.Errorf("expected resource=path, got %q", arg)
+		}
+		c.resources = append(c.resources, resourceFile{name: parts[0], path: parts[1]})
+	}
+	return nil
+}
+
+// AttachAPI defines the API methods used by the attach command.
+type AttachAPI interface {
+	UploadResource(service, name string, r io.ReadSeeker) (params.Resource, error)
+	Close() error
+}
+
+var getAttachAPI = func(c *attachCommand) (AttachAPI, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return client, nil
+}
+
+func (c *attachCommand) Run(ctx *cmd.Context) error {
+	client, err := getAttachAPI(c)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	for _, resource := range c.resources {
+		if err := c.upload(ctx, client, resource); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (c *attachCommand) upload(ctx *cmd.Context, client AttachAPI, resource resourceFile) error {
+	f, err := os.Open(ctx.AbsPath(resource.path))
+	if err != nil {
+		return errors.Annotatef(err, "cannot read resource %q", resource.name)
+	}
+	defer f.Close()
+	res, err := client.UploadResource(c.serviceName, resource.name, f)
+	if err != nil {
+		return errors.Annotatef(err, "cannot upload resource %q", resource.name)
+	}
+	ctx.Infof("uploaded resource %q revision %d", res.Name, res.Revision)
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type AttachSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeAttachAPI
+}
+
+var _ = gc.Suite(&AttachSuite{})
+
+func (s *AttachSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeAttachAPI{uploads: make(map[string]string)}
+	s.PatchValue(&getAttachAPI, func(_ *attachCommand) (AttachAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *AttachSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no service specified",
+	}, {
+		args: []string{"wordpress/0"},
+		err:  `invalid service name "wordpress/0"`,
+	}, {
+		args: []string{"wordpress"},
+		err:  "no resources specified",
+	}, {
+		args: []string{"wordpress", "software"},
+		err:  `expected resource=path, got "software"`,
+	}, {
+		args: []string{"wordpress", "=foo.tgz"},
+		err:  `expected resource=path, got "=foo.tgz"`,
+	}} {
+		c.Logf("test %d: %q", i, test.args)
+		err := testing.InitCommand(&attachCommand{}, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *AttachSuite) TestAttach(c *gc.C) {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "software.tgz"), []byte("software"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(dir, "theme.zip"), []byte("theme"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := []string{"wordpress", "software=software.tgz", "theme=theme.zip"}
+	_, err = testing.RunCommandInDir(c, newAttachCommand(), args, dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.service, gc.Equals, "wordpress")
+	c.Assert(s.fake.uploads, jc.DeepEquals, map[string]string{
+		"software": "software",
+		"theme":    "theme",
+	})
+}
+
+func (s *AttachSuite) TestAttachMissingFile(c *gc.C) {
+	_, err := testing.RunCommand(c, newAttachCommand(), "wordpress", "software="+filepath.Join(c.MkDir(), "missing"))
+	c.Assert(err, gc.ErrorMatches, `cannot read resource "software": .*`)
+	c.Assert(s.fake.uploads, gc.HasLen, 0)
+}
+
+type fakeAttachAPI struct {
+	service string
+	uploads map[string]string
+}
+
+func (fake *fakeAttachAPI) UploadResource(service, name string, r io.ReadSeeker) (params.Resource, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return params.Resource{}, err
+	}
+	fake.service = service
+	fake.uploads[name] = string(data)
+	return params.Resource{Service: service, Name: name, Revision: 1}, nil
+}
+
+func (fake *fakeAttachAPI) Close() error {
+	return nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/auditlog"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/common"
+)
+
+func newAuditLogCommand() cmd.Command {
+	return envcmd.Wrap(&auditLogCommand{})
+}
+
+// defaultAuditEventCount is the default number of audit events to
+// display.
+const defaultAuditEventCount = 50
+
+const auditLogDoc = `
+Show the API calls made by users against the environment, oldest first.
+Each event records the user, the API method called, a summary of its
+arguments (with secrets redacted), its result and the client address.
+
+Events may be filtered by the user who made them, by an entity they refer
+to, and by time. Times may be given in RFC3339 format, or as a duration
+before now.
+
+Examples:
+    juju audit-log --user bob
+    juju audit-log --entity mysql/0 --after 2h
+    juju audit-log --after 2015-10-01T00:00:00Z --before 2015-10-02T00:00:00Z
+
+Only the environment owner and system administrators may read the audit log.
+`
+
+type auditLogCommand struct {
+	envcmd.EnvCommandBase
+	out     cmd.Output
+	isoTime bool
+
+	user   string
+	entity string
+	after  string
+	before string
+	limit  int
+
+	filter params.AuditEventFilter
+}
+
+func (c *auditLogCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "audit-log",
+		Purpose: "show the API calls made by users against the environment",
+		Doc:     auditLogDoc,
+	}
+}
+
+func (c *auditLogCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.user, "user", "", "only show calls made by this user")
+	f.StringVar(&c.entity, "entity", "", "only show calls referring to this machine, service, unit or tag")
+	f.StringVar(&c.after, "after", "", "only show calls made at or after this time")
+	f.StringVar(&c.before, "before", "", "only show calls made before this time")
+	f.IntVar(&c.limit, "n", defaultAuditEventCount, "show at most this many of the most recent calls (0 for all)")
+	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": c.formatTabular,
+	})
+}
+
+func (c *auditLogCommand) Init(args []string) error {
+	if err := cmd.CheckEmpty(args); err != nil {
+		return err
+	}
+	if c.limit < 0 {
+		return errors.Errorf("invalid number of events %d", c.limit)
+	}
+	c.filter = params.AuditEventFilter{Limit: c.limit}
+	if c.user != "" {
+		if !names.IsValidUser(c.user) {
+			return errors.NotValidf("user name %q", c.user)
+		}
+		c.filter.User = names.NewUserTag(c.user).String()
+	}
+	if c.entity != "" {
+		tag, err := parseAuditEntity(c.entity)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.filter.Entity = tag.String()
+	}
+	now := time.Now()
+	var err error
+	if c.filter.After, err = common.ParseTimeOrDuration(c.after, now); err != nil {
+		return errors.Annotate(err, "invalid --after value")
+	}
+	if c.filter.Before, err = common.ParseTimeOrDuration(c.before, now); err != nil {
+		return errors.Annotate(err, "invalid --before value")
+	}
+	return nil
+}
+
+// parseAuditEntity returns the tag of the entity with the given tag
+// or name.
+func parseAuditEntity(entity string) (names.Tag, error) {
+	if tag, err := names.ParseTag(entity); err == nil {
+		return tag, nil
+	}
+	switch {
+	case names.IsValidMachine(entity):
+		return names.NewMachineTag(entity), nil
+	case names.IsValidUnit(entity):
+		return names.NewUnitTag(entity), nil
+	case names.IsValidService(entity):
+		return names.NewServiceTag(entity), nil
+	}
+	return nil, errors.NotValidf("entity %q", entity)
+}
+
+// AuditLogAPI defines the API methods used by the audit-log command.
+type AuditLogAPI interface {
+	List(params.AuditEventFilter) ([]params.AuditEvent, error)
+	Close() error
+}
+
+var getAuditLogAPI = func(c *auditLogCommand) (AuditLogAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return auditlog.NewClient(root), nil
+}
+
+func (c *auditLogCommand) Run(ctx *cmd.Context) error {
+	client, err := getAuditLogAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	events, err := client.List(c.filter)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, events)
+}
+
+func (c *auditLogCommand) formatTabular(value interface{}) ([]byte, error) {
+	events, ok := value.([]params.AuditEvent)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", events, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "TIME\tUSER\tSOURCE\tMETHOD\tENTITIES\tERROR\n")
+	for _, event := range events {
+		user := event.User
+		if tag, err := names.ParseUserTag(user); err == nil {
+			user = tag.Canonical()
+		}
+		method := fmt.Sprintf("%s.%s", event.Facade, event.Method)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			common.FormatTime(&event.Time, c.isoTime),
+			user,
+			event.SourceAddress,
+			method,
+			strings.Join(event.Entities, ","),
+			strings.Replace(event.Error, "\n", "; ", -1),
+		)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type AuditLogSuite struct {
+	testing.FakeJujuHomeSuite
+}
+
+var _ = gc.Suite(&AuditLogSuite{})
+
+func (s *AuditLogSuite) TestArgParsing(c *gc.C) {
+	after := time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i, test := range []struct {
+		args     []string
+		expected params.AuditEventFilter
+		errMatch string
+	}{{
+		expected: params.AuditEventFilter{Limit: 50},
+	}, {
+		args:     []string{"--user", "bob", "-n", "0"},
+		expected: params.AuditEventFilter{User: "user-bob"},
+	}, {
+		args:     []string{"--entity", "mysql/0"},
+		expected: params.AuditEventFilter{Entity: "unit-mysql-0", Limit: 50},
+	}, {
+		args:     []string{"--entity", "1"},
+		expected: params.AuditEventFilter{Entity: "machine-1", Limit: 50},
+	}, {
+		args:     []string{"--entity", "mysql"},
+		expected: params.AuditEventFilter{Entity: "service-mysql", Limit: 50},
+	}, {
+		args:     []string{"--entity", "service-wordpress"},
+		expected: params.AuditEventFilter{Entity: "service-wordpress", Limit: 50},
+	}, {
+		args:     []string{"--after", "2015-10-01T00:00:00Z"},
+		expected: params.AuditEventFilter{After: &after, Limit: 50},
+	}, {
+		args:     []string{"--entity", "foo/bar"},
+		errMatch: `entity "foo/bar" not valid`,
+	}, {
+		args:     []string{"--before", "yesterday"},
+		errMatch: `invalid --before value: expected RFC3339 time or duration, got "yesterday"`,
+	}, {
+		args:     []string{"-n", "-1"},
+		errMatch: `invalid number of events -1`,
+	}, {
+		args:     []string{"extra"},
+		errMatch: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &auditLogCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(command.filter, jc.DeepEquals, test.expected)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *AuditLogSuite) TestRelativeTime(c *gc.C) {
+	command := &auditLogCommand{}
+	err := testing.InitCommand(envcmd.Wrap(command), []string{"--after", "2h"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(command.filter.After, gc.NotNil)
+	ago := time.Since(*command.filter.After)
+	c.Assert(ago >= 2*time.Hour, jc.IsTrue)
+	c.Assert(ago < 2*time.Hour+time.Minute, jc.IsTrue)
+}
+
+func (s *AuditLogSuite) TestTabularOutput(c *gc.C) {
+	fake := &fakeAuditLogAPI{
+		events: []params.AuditEvent{{
+			Time:          time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC),
+			User:          "user-bob@local",
+			Facade:        "Client",
+			Version:       1,
+			Method:        "DestroyMachines",
+			Entities:      []string{"machine-1", "machine-2"},
+			Error:         "machine 2 has units",
+			SourceAddress: "10.0.0.1:4321",
+		}},
+	}
+	s.PatchValue(&getAuditLogAPI, func(_ *auditLogCommand) (AuditLogAPI, error) {
+		return fake, nil
+	})
+	ctx, err := testing.RunCommand(c, newAuditLogCommand(), "--utc", "--user", "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fake.filter, jc.DeepEquals, params.AuditEventFilter{User: "user-bob", Limit: 50})
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"TIME                  USER       SOURCE         METHOD                  ENTITIES             ERROR\n"+
+		"2015-10-01 12:00:00Z  bob@local  10.0.0.1:4321  Client.DestroyMachines  machine-1,machine-2  machine 2 has units\n")
+}
+
+type fakeAuditLogAPI struct {
+	events []params.AuditEvent
+	filter params.AuditEventFilter
+}
+
+func (fake *fakeAuditLogAPI) List(filter params.AuditEventFilter) ([]params.AuditEvent, error) {
+	fake.filter = filter
+	return fake.events, nil
+}
+
+func (fake *fakeAuditLogAPI) Close() error {
+	return nil
+}
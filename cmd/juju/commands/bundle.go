@@ -10,12 +10,14 @@ import (
 	"time"
 
 	"github.com/juju/bundlechanges"
+	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/yaml.v1"
 
 	"github.com/juju/juju/api"
+	apiservice "github.com/juju/juju/api/service"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
@@ -31,10 +33,11 @@ type deploymentLogger interface {
 	Infof(string, ...interface{})
 }
 
-// deployBundle deploys the given bundle data using the given API client and
-// charm store client. The deployment is not transactional, and its progress is
-// notified using the given deployment logger.
-func deployBundle(data *charm.BundleData, client *api.Client, csclient *csClient, repoPath string, conf *config.Config, log deploymentLogger) error {
+// deployBundle deploys the given bundle data, binding the services'
+// endpoints as given, using the given API clients and charm store client.
+// The deployment is not transactional, and its progress is notified using
+// the given deployment logger.
+func deployBundle(data *charm.BundleData, bindings bundleBindings, client *api.Client, serviceClient *apiservice.Client, csclient *csClient, repoPath string, conf *config.Config, log deploymentLogger) error {
 	if err := data.Verify(func(s string) error {
 		_, err := constraints.Parse(s)
 		return err
@@ -70,11 +73,13 @@ func deployBundle(data *charm.BundleData, client *api.Client, csclient *csClient
 		changes:         changes,
 		results:         make(map[string]string, numChanges),
 		client:          client,
+		serviceClient:   serviceClient,
 		csclient:        csclient,
 		repoPath:        repoPath,
 		conf:            conf,
 		log:             log,
 		data:            data,
+		bindings:        bindings,
 		unitStatus:      unitStatus,
 		ignoredMachines: make(map[string]bool, len(data.Services)),
 		ignoredUnits:    make(map[string]bool, len(data.Services)),
@@ -106,6 +111,114 @@ func deployBundle(data *charm.BundleData, client *api.Client, csclient *csClient
 	return nil
 }
 
+// describeBundleChanges returns a description of each change required to
+// deploy the given bundle data, in the order the changes would be applied.
+// Nothing is deployed. Each description is prefixed with the change id, which
+// is used to refer to machines and units created by earlier changes.
+func describeBundleChanges(data *charm.BundleData) ([]string, error) {
+	if err := data.Verify(func(s string) error {
+		_, err := constraints.Parse(s)
+		return err
+	}); err != nil {
+		return nil, errors.Annotate(err, "cannot deploy bundle")
+	}
+	changes := bundlechanges.FromData(data)
+	// results maps change ids to the charms and services they add, so that
+	// those can be described by name.
+	results := make(map[string]string, len(changes))
+	ref := func(placeholder string) string {
+		if name, ok := results[placeholder[1:]]; ok {
+			return name
+		}
+		return placeholder[1:]
+	}
+	descriptions := make([]string, len(changes))
+	for i, change := range changes {
+		var parts []string
+		switch change := change.(type) {
+		case *bundlechanges.AddCharmChange:
+			results[change.Id()] = change.Params.Charm
+			parts = append(parts, "add-charm "+change.Params.Charm)
+		case *bundlechanges.AddMachineChange:
+			p := change.Params
+			where := "new machine"
+			if p.ContainerType != "" {
+				if p.ParentId != "" {
+					where = fmt.Sprintf("%s container in %s", p.ContainerType, ref(p.ParentId))
+				} else {
+					where = fmt.Sprintf("%s container in new machine", p.ContainerType)
+				}
+			}
+			parts = append(parts, "add-machine "+where)
+			if p.Series != "" {
+				parts = append(parts, "series "+p.Series)
+			}
+			if p.Constraints != "" {
+				parts = append(parts, "constraints "+p.Constraints)
+			}
+		case *bundlechanges.AddRelationChange:
+			ep1 := resolveRelation(change.Params.Endpoint1, results)
+			ep2 := resolveRelation(change.Params.Endpoint2, results)
+			parts = append(parts, fmt.Sprintf("add-relation %s %s", ep1, ep2))
+		case *bundlechanges.AddServiceChange:
+			p := change.Params
+			results[change.Id()] = p.Service
+			parts = append(parts, fmt.Sprintf("deploy %s using %s", p.Service, ref(p.Charm)))
+			if len(p.Options) > 0 {
+				parts = append(parts, "options "+formatSettings(p.Options))
+			}
+			if p.Constraints != "" {
+				parts = append(parts, "constraints "+p.Constraints)
+			}
+		case *bundlechanges.AddUnitChange:
+			to := "new machine"
+			if change.Params.To != "" {
+				to = ref(change.Params.To)
+			}
+			parts = append(parts, fmt.Sprintf("add-unit %s to %s", ref(change.Params.Service), to))
+		case *bundlechanges.SetAnnotationsChange:
+			p := change.Params
+			annotations := make(map[string]interface{}, len(p.Annotations))
+			for key, value := range p.Annotations {
+				annotations[key] = value
+			}
+			parts = append(parts, fmt.Sprintf("set-annotations %s %s %s", p.EntityType, ref(p.Id), formatSettings(annotations)))
+		default:
+			return nil, errors.Errorf("unknown change type: %T", change)
+		}
+		descriptions[i] = change.Id() + ": " + strings.Join(parts, ", ")
+	}
+	return descriptions, nil
+}
+
+// printBundleChanges writes the changes required to deploy the given bundle
+// data to the command's standard output.
+func printBundleChanges(ctx *cmd.Context, data *charm.BundleData) error {
+	descriptions, err := describeBundleChanges(data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, description := range descriptions {
+		fmt.Fprintln(ctx.Stdout, description)
+	}
+	return nil
+}
+
+// formatSettings returns the given settings as space separated key=value
+// pairs, sorted by key.
+func formatSettings(settings map[string]interface{}) string {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", key, settings[key])
+	}
+	return strings.Join(pairs, " ")
+}
+
 // bundleHandler provides helpers and the state required to deploy a bundle.
 type bundleHandler struct {
 	// changes holds the changes to be applied in order to deploy the bundle.
@@ -124,6 +237,9 @@ type bundleHandler struct {
 	results map[string]string
 	// client is used to interact with the environment.
 	client *api.Client
+	// serviceClient is used to deploy services whose endpoints are bound
+	// to spaces.
+	serviceClient *apiservice.Client
 	// csclient is used to retrieve charms from the charm store.
 	csclient *csClient
 	// repoPath is used to retrieve charms from a local repository.
@@ -135,6 +251,9 @@ type bundleHandler struct {
 	log deploymentLogger
 	// data is the original bundle data that we want to deploy.
 	data *charm.BundleData
+	// bindings maps service names to the bindings of their endpoints to
+	// spaces, as given in the bundle.
+	bindings bundleBindings
 	// unitStatus reflects the environment status and maps unit names to their
 	// corresponding machine identifiers. This is kept updated by both change
 	// handlers (addCharm, addService etc.) and by updateUnitStatus.
@@ -189,7 +308,13 @@ func (h *bundleHandler) addService(id string, p bundlechanges.AddServiceParams)
 	}
 	// Deploy the service.
 	numUnits, toMachineSpec := 0, ""
-	if err := h.client.ServiceDeploy(ch, p.Service, numUnits, configYAML, cons, toMachineSpec); err == nil {
+	bindings := h.bindings[p.Service]
+	if len(bindings) > 0 {
+		err = h.serviceClient.ServiceDeploy(ch, p.Service, numUnits, configYAML, cons, toMachineSpec, nil, nil, nil, bindings)
+	} else {
+		err = h.client.ServiceDeploy(ch, p.Service, numUnits, configYAML, cons, toMachineSpec)
+	}
+	if err == nil {
 		h.log.Infof("service %s deployed (charm: %s)", p.Service, ch)
 		return nil
 	} else if !isErrServiceExists(err) {
@@ -24,8 +24,8 @@ import (
 
 // runDeployCommand executes the deploy command in order to deploy the given
 // charm or bundle. The deployment output and error are returned.
-func runDeployCommand(c *gc.C, id string) (string, error) {
-	ctx, err := coretesting.RunCommand(c, newDeployCommand(), id)
+func runDeployCommand(c *gc.C, id string, args ...string) (string, error) {
+	ctx, err := coretesting.RunCommand(c, newDeployCommand(), append([]string{id}, args...)...)
 	return strings.Trim(coretesting.Stderr(ctx), "\n"), err
 }
 
@@ -355,6 +355,54 @@ deployment of bundle "local:bundle/example-0" completed`
 	})
 }
 
+func (s *deployRepoCharmStoreSuite) TestDeployBundleDryRun(c *gc.C) {
+	bundlePath := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(bundlePath, []byte(`
+        services:
+            wordpress:
+                charm: cs:trusty/wordpress-0
+                num_units: 1
+                options:
+                    blog-title: these are the voyages
+                annotations:
+                    gui-x: "10"
+            mysql:
+                charm: cs:trusty/mysql-2
+                num_units: 1
+                to:
+                    - lxc:1
+        machines:
+            1:
+                series: trusty
+        relations:
+            - ["wordpress:db", "mysql:server"]
+    `), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx, err := coretesting.RunCommand(c, newDeployCommand(), bundlePath, "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	expectedOutput := `
+addCharm-0: add-charm cs:trusty/mysql-2
+deploy-1: deploy mysql using cs:trusty/mysql-2
+addCharm-2: add-charm cs:trusty/wordpress-0
+deploy-3: deploy wordpress using cs:trusty/wordpress-0, options blog-title=these are the voyages
+setAnnotations-4: set-annotations service wordpress gui-x=10
+addMachines-5: add-machine new machine, series trusty
+addRelation-6: add-relation wordpress:db mysql:server
+addMachines-7: add-machine lxc container in addMachines-5
+addUnit-8: add-unit mysql to addMachines-7
+addUnit-9: add-unit wordpress to new machine`
+	c.Assert(strings.TrimSpace(coretesting.Stdout(ctx)), gc.Equals, strings.TrimSpace(expectedOutput))
+	// Nothing has been deployed.
+	s.assertServicesDeployed(c, map[string]serviceInfo{})
+	s.assertUnitsCreated(c, map[string]string{})
+}
+
+func (s *deployRepoCharmStoreSuite) TestDeployCharmDryRun(c *gc.C) {
+	testcharms.UploadCharm(c, s.client, "trusty/mysql-2", "mysql")
+	_, err := runDeployCommand(c, "trusty/mysql-2", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, "--dry-run is only supported when deploying bundles")
+}
+
 func (s *deployRepoCharmStoreSuite) TestDeployBundleLocalAndCharmStoreCharms(c *gc.C) {
 	testcharms.UploadCharm(c, s.client, "trusty/wordpress-42", "wordpress")
 	testcharms.Repo.ClonedDirPath(s.SeriesPath, "mysql")
@@ -474,6 +522,24 @@ deployment of bundle "local:bundle/example-0" completed`
 	})
 }
 
+func (s *deployRepoCharmStoreSuite) TestDeployBundleEndpointBindings(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	testcharms.UploadCharm(c, s.client, "trusty/wordpress-42", "wordpress")
+	_, err = s.deployBundleYAML(c, `
+        services:
+            wordpress:
+                charm: wordpress
+                bindings:
+                    db: internal
+    `)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCharmsUplodaded(c, "cs:trusty/wordpress-42")
+	service, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{"db": "internal"})
+}
+
 func (s *deployRepoCharmStoreSuite) TestDeployBundleServiceUpgrade(c *gc.C) {
 	testcharms.UploadCharm(c, s.client, "trusty/wordpress-42", "wordpress")
 	testcharms.UploadCharm(c, s.client, "vivid/upgrade-1", "upgrade1")
@@ -0,0 +1,311 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/yaml.v1"
+)
+
+// includeFilePrefix marks a bundle value to be replaced with the contents
+// of the named file.
+const includeFilePrefix = "include-file://"
+
+// envVarPattern matches ${NAME} references to environment variables in
+// bundle values, and the $${ escape for a literal ${.
+var envVarPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// envVarEscape is replaced with a literal ${ in bundle values.
+const envVarEscape = "$${"
+
+// removeRelationsKey names the overlay list of relations to remove from
+// the bundle.
+const removeRelationsKey = "remove-relations"
+
+// bundleBindings holds the bindings of the services' endpoints to spaces,
+// keyed by service name and then endpoint name. They are given in each
+// service's "bindings" section, which charm.BundleData does not describe.
+type bundleBindings map[string]map[string]string
+
+// loadBundle reads the local bundle YAML from r, applies the given overlay
+// files in order and returns the resulting bundle data and endpoint
+// bindings. References to files
+// and environment variables are resolved in the bundle and in each overlay
+// before they are merged, with relative file paths interpreted relative to
+// dir for the bundle and to the overlay's own directory for overlays.
+func loadBundle(ctx *cmd.Context, r io.Reader, dir string, overlays []string) (*charm.BundleData, bundleBindings, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	doc, err := readBundleDocument(content, dir)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return applyOverlays(ctx, doc, overlays)
+}
+
+// loadStoreBundle applies the given overlay files in order to the bundle
+// data fetched from a charm repository, and returns the resulting bundle
+// data and endpoint bindings. References are resolved in the overlays, as by loadBundle, but not
+// in the bundle itself: it was not written by the user, so its values must
+// not be able to read the user's files or environment.
+func loadStoreBundle(ctx *cmd.Context, data *charm.BundleData, overlays []string) (*charm.BundleData, bundleBindings, error) {
+	content, err := yaml.Marshal(data)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot marshal bundle")
+	}
+	doc, err := parseBundleDocument(content)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return applyOverlays(ctx, doc, overlays)
+}
+
+// applyOverlays merges the given overlay files in order into the bundle
+// document, and returns the resulting bundle data and endpoint bindings.
+func applyOverlays(ctx *cmd.Context, doc map[interface{}]interface{}, overlays []string) (*charm.BundleData, bundleBindings, error) {
+	for _, overlay := range overlays {
+		path := ctx.AbsPath(overlay)
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, nil, errors.Annotate(err, "cannot read overlay")
+		}
+		overlayDoc, err := readBundleDocument(content, filepath.Dir(path))
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "cannot read overlay %q", overlay)
+		}
+		mergeBundleDocument(doc, overlayDoc)
+	}
+	removeOrphanedRelations(doc)
+	merged, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot marshal bundle")
+	}
+	data, err := charm.ReadBundleData(bytes.NewReader(merged))
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	bindings, err := readBundleBindings(merged)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return data, bindings, nil
+}
+
+// readBundleBindings returns the endpoint bindings of the services in the
+// YAML bundle content.
+func readBundleBindings(content []byte) (bundleBindings, error) {
+	var doc struct {
+		Services map[string]struct {
+			Bindings map[string]string `yaml:"bindings"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal bundle bindings")
+	}
+	bindings := make(bundleBindings)
+	for name, spec := range doc.Services {
+		if len(spec.Bindings) > 0 {
+			bindings[name] = spec.Bindings
+		}
+	}
+	return bindings, nil
+}
+
+// parseBundleDocument parses the YAML bundle content.
+func parseBundleDocument(content []byte) (map[interface{}]interface{}, error) {
+	var doc map[interface{}]interface{}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal bundle data")
+	}
+	if doc == nil {
+		doc = make(map[interface{}]interface{})
+	}
+	return doc, nil
+}
+
+// readBundleDocument parses the YAML bundle content and resolves the file
+// and environment variable references in its values.
+func readBundleDocument(content []byte, dir string) (map[interface{}]interface{}, error) {
+	doc, err := parseBundleDocument(content)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := resolveBundleValues(doc, dir); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return doc, nil
+}
+
+// resolveBundleValues replaces "include-file://" values with the contents of
+// the named file, and ${NAME} references in string values with the value of
+// the NAME environment variable, which may be empty but must be set; $${
+// is replaced with a literal ${. Maps and lists are resolved in place.
+func resolveBundleValues(value interface{}, dir string) (interface{}, error) {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		for k, v := range value {
+			resolved, err := resolveBundleValues(v, dir)
+			if err != nil {
+				return nil, errors.Annotatef(err, "%v", k)
+			}
+			value[k] = resolved
+		}
+	case []interface{}:
+		for i, v := range value {
+			resolved, err := resolveBundleValues(v, dir)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			value[i] = resolved
+		}
+	case string:
+		if strings.HasPrefix(value, includeFilePrefix) {
+			path := value[len(includeFilePrefix):]
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, errors.Annotate(err, "cannot include file")
+			}
+			return string(content), nil
+		}
+		var missing []string
+		expanded := envVarPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			if ref == envVarEscape {
+				return "${"
+			}
+			name := envVarPattern.FindStringSubmatch(ref)[1]
+			v, ok := os.LookupEnv(name)
+			if !ok {
+				missing = append(missing, name)
+			}
+			return v
+		})
+		if len(missing) > 0 {
+			return nil, errors.Errorf("environment variable %q is not set", missing[0])
+		}
+		return expanded, nil
+	}
+	return value, nil
+}
+
+// mergeBundleDocument merges the overlay into the bundle document. Overlay
+// values replace bundle values, except that maps are merged key by key and
+// relations are added to the bundle ones. A null value removes the
+// corresponding key, so that an overlay can remove services, machines or
+// config options; relations listed under "remove-relations" are removed
+// from the bundle, before the overlay's own relations are added.
+func mergeBundleDocument(doc, overlay map[interface{}]interface{}) {
+	for key, value := range overlay {
+		if key == "relations" || key == removeRelationsKey {
+			continue
+		}
+		mergeKey(doc, key, value)
+	}
+	relations, _ := doc["relations"].([]interface{})
+	var kept []interface{}
+	for _, rel := range relations {
+		if !hasRelation(asList(overlay[removeRelationsKey]), rel) {
+			kept = append(kept, rel)
+		}
+	}
+	for _, rel := range asList(overlay["relations"]) {
+		if !hasRelation(kept, rel) {
+			kept = append(kept, rel)
+		}
+	}
+	if len(kept) == 0 {
+		delete(doc, "relations")
+		return
+	}
+	doc["relations"] = kept
+}
+
+// mergeKey sets the key in the map to the merged value.
+func mergeKey(m map[interface{}]interface{}, key, value interface{}) {
+	if value == nil {
+		delete(m, key)
+		return
+	}
+	overlay, ok := value.(map[interface{}]interface{})
+	base, baseOk := m[key].(map[interface{}]interface{})
+	if !ok || !baseOk {
+		m[key] = value
+		return
+	}
+	for k, v := range overlay {
+		mergeKey(base, k, v)
+	}
+}
+
+// removeOrphanedRelations removes the relations involving services that are
+// not in the bundle, for instance because they were removed by an overlay.
+func removeOrphanedRelations(doc map[interface{}]interface{}) {
+	relations, ok := doc["relations"].([]interface{})
+	if !ok {
+		return
+	}
+	services, _ := doc["services"].(map[interface{}]interface{})
+	var kept []interface{}
+	for _, rel := range relations {
+		orphaned := false
+		for _, ep := range asList(rel) {
+			service := strings.SplitN(fmt.Sprint(ep), ":", 2)[0]
+			if _, ok := services[service]; !ok {
+				orphaned = true
+			}
+		}
+		if !orphaned {
+			kept = append(kept, rel)
+		}
+	}
+	if len(kept) == 0 {
+		delete(doc, "relations")
+		return
+	}
+	doc["relations"] = kept
+}
+
+func asList(value interface{}) []interface{} {
+	list, _ := value.([]interface{})
+	return list
+}
+
+// hasRelation returns whether the list holds the relation, with its
+// endpoints in either order.
+func hasRelation(list []interface{}, rel interface{}) bool {
+	key := relationKey(rel)
+	for _, r := range list {
+		if relationKey(r) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// relationKey returns a string identifying the relation, whatever the order
+// of its endpoints.
+func relationKey(rel interface{}) string {
+	var eps []string
+	for _, ep := range asList(rel) {
+		eps = append(eps, fmt.Sprint(ep))
+	}
+	sort.Strings(eps)
+	return strings.Join(eps, " ")
+}
@@ -0,0 +1,296 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/testcharms"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type BundleOverlaySuite struct {
+	coretesting.BaseSuite
+	dir string
+}
+
+var _ = gc.Suite(&BundleOverlaySuite{})
+
+func (s *BundleOverlaySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+}
+
+const overlayBaseBundle = `
+services:
+    wordpress:
+        charm: cs:trusty/wordpress-0
+        num_units: 1
+        options:
+            blog-title: Staging
+            debug: true
+    mysql:
+        charm: cs:trusty/mysql-2
+        num_units: 1
+    varnish:
+        charm: cs:trusty/varnish-1
+        num_units: 1
+relations:
+    - ["wordpress:db", "mysql:server"]
+    - ["varnish:webcache", "wordpress:cache"]
+`
+
+// writeFile writes the content to the named file in the test directory
+// and returns its path.
+func (s *BundleOverlaySuite) writeFile(c *gc.C, name, content string) string {
+	path := filepath.Join(s.dir, name)
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *BundleOverlaySuite) TestLoadBundleNoOverlays(c *gc.C) {
+	data, _, err := loadBundle(coretesting.Context(c), strings.NewReader(overlayBaseBundle), s.dir, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	expected, err := charm.ReadBundleData(strings.NewReader(overlayBaseBundle))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, expected)
+}
+
+func (s *BundleOverlaySuite) TestLoadBundleOverlays(c *gc.C) {
+	prod := s.writeFile(c, "prod.yaml", `
+services:
+    wordpress:
+        num_units: 3
+        options:
+            blog-title: Production
+            debug:
+    varnish:
+    haproxy:
+        charm: cs:trusty/haproxy-4
+        num_units: 1
+relations:
+    - ["haproxy:reverseproxy", "wordpress:website"]
+    - ["wordpress:db", "mysql:server"]
+`)
+	bigger := s.writeFile(c, "bigger.yaml", `
+services:
+    mysql:
+        num_units: 2
+        constraints: mem=8G
+`)
+	data, _, err := loadBundle(coretesting.Context(c), strings.NewReader(overlayBaseBundle), s.dir, []string{prod, bigger})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, &charm.BundleData{
+		Services: map[string]*charm.ServiceSpec{
+			"wordpress": {
+				Charm:    "cs:trusty/wordpress-0",
+				NumUnits: 3,
+				Options:  map[string]interface{}{"blog-title": "Production"},
+			},
+			"mysql": {
+				Charm:       "cs:trusty/mysql-2",
+				NumUnits:    2,
+				Constraints: "mem=8G",
+			},
+			"haproxy": {
+				Charm:    "cs:trusty/haproxy-4",
+				NumUnits: 1,
+			},
+		},
+		Relations: [][]string{
+			{"wordpress:db", "mysql:server"},
+			{"haproxy:reverseproxy", "wordpress:website"},
+		},
+	})
+}
+
+func (s *BundleOverlaySuite) TestLoadBundleOverlayNotFound(c *gc.C) {
+	_, _, err := loadBundle(coretesting.Context(c), strings.NewReader(overlayBaseBundle), s.dir, []string{"missing.yaml"})
+	c.Assert(err, gc.ErrorMatches, "cannot read overlay: open .*missing.yaml: no such file or directory")
+}
+
+func (s *BundleOverlaySuite) TestLoadBundleResolvesValues(c *gc.C) {
+	s.PatchEnvironment("BLOG_TITLE", "Production")
+	s.writeFile(c, "key.pem", "secret key")
+	secrets := s.writeFile(c, "secrets.yaml", `
+services:
+    wordpress:
+        options:
+            ssl-key: include-file://key.pem
+`)
+	data, _, err := loadBundle(coretesting.Context(c), strings.NewReader(`
+services:
+    wordpress:
+        charm: cs:trusty/wordpress-0
+        num_units: 1
+        options:
+            blog-title: ${BLOG_TITLE} blog
+`), s.dir, []string{secrets})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Services["wordpress"].Options, jc.DeepEquals, map[string]interface{}{
+		"blog-title": "Production blog",
+		"ssl-key":    "secret key",
+	})
+}
+
+func (s *BundleOverlaySuite) TestLoadBundleRemoveRelations(c *gc.C) {
+	overlay := s.writeFile(c, "overlay.yaml", `
+remove-relations:
+    - ["wordpress:cache", "varnish:webcache"]
+relations:
+    - ["wordpress:db", "mysql:server"]
+`)
+	data, _, err := loadBundle(coretesting.Context(c), strings.NewReader(overlayBaseBundle), s.dir, []string{overlay})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Relations, jc.DeepEquals, [][]string{
+		{"wordpress:db", "mysql:server"},
+	})
+	c.Assert(data.Services, gc.HasLen, 3)
+}
+
+func (s *BundleOverlaySuite) TestLoadBundleBindings(c *gc.C) {
+	overlay := s.writeFile(c, "overlay.yaml", `
+services:
+    wordpress:
+        bindings:
+            website: public
+`)
+	_, bindings, err := loadBundle(coretesting.Context(c), strings.NewReader(`
+services:
+    wordpress:
+        charm: cs:trusty/wordpress-0
+        num_units: 1
+        bindings:
+            db: internal
+    mysql:
+        charm: cs:trusty/mysql-2
+        num_units: 1
+`), s.dir, []string{overlay})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bindings, jc.DeepEquals, bundleBindings{
+		"wordpress": {"db": "internal", "website": "public"},
+	})
+}
+
+func (s *BundleOverlaySuite) TestLoadBundleEmptyVariable(c *gc.C) {
+	s.PatchEnvironment("BLOG_SUFFIX", "")
+	data, _, err := loadBundle(coretesting.Context(c), strings.NewReader(`
+services:
+    wordpress:
+        charm: cs:trusty/wordpress-0
+        options:
+            blog-title: My blog${BLOG_SUFFIX}
+`), s.dir, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Services["wordpress"].Options["blog-title"], gc.Equals, "My blog")
+}
+
+func (s *BundleOverlaySuite) TestLoadBundleEscapedVariable(c *gc.C) {
+	s.PatchEnvironment("HOME", "/home/ubuntu")
+	data, _, err := loadBundle(coretesting.Context(c), strings.NewReader(`
+services:
+    wordpress:
+        charm: cs:trusty/wordpress-0
+        options:
+            blog-title: $${HOME} is ${HOME}, costs $$5
+`), s.dir, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Services["wordpress"].Options["blog-title"], gc.Equals, "${HOME} is /home/ubuntu, costs $$5")
+}
+
+func (s *BundleOverlaySuite) TestLoadBundleUnsetVariable(c *gc.C) {
+	s.PatchEnvironment("BLOG_TITLE", "")
+	err := os.Unsetenv("BLOG_TITLE")
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = loadBundle(coretesting.Context(c), strings.NewReader(`
+services:
+    wordpress:
+        charm: cs:trusty/wordpress-0
+        options:
+            blog-title: ${BLOG_TITLE}
+`), s.dir, nil)
+	c.Assert(err, gc.ErrorMatches, `services: wordpress: options: blog-title: environment variable "BLOG_TITLE" is not set`)
+}
+
+func (s *BundleOverlaySuite) TestLoadBundleIncludeFileNotFound(c *gc.C) {
+	_, _, err := loadBundle(coretesting.Context(c), strings.NewReader(`
+services:
+    wordpress:
+        charm: cs:trusty/wordpress-0
+        options:
+            ssl-key: include-file://missing.pem
+`), s.dir, nil)
+	c.Assert(err, gc.ErrorMatches, `services: wordpress: options: ssl-key: cannot include file: open .*missing.pem: no such file or directory`)
+}
+
+func (s *BundleOverlaySuite) TestLoadStoreBundleLeavesValues(c *gc.C) {
+	s.PatchEnvironment("AWS_SECRET_ACCESS_KEY", "secret")
+	s.writeFile(c, "key.pem", "secret key")
+	ctx := coretesting.ContextForDir(c, s.dir)
+	overlay := s.writeFile(c, "overlay.yaml", `
+services:
+    wordpress:
+        options:
+            debug: ${AWS_SECRET_ACCESS_KEY}
+`)
+	bundle := &charm.BundleData{
+		Services: map[string]*charm.ServiceSpec{
+			"wordpress": {
+				Charm:    "cs:trusty/wordpress-0",
+				NumUnits: 1,
+				Options: map[string]interface{}{
+					"blog-title": "${AWS_SECRET_ACCESS_KEY}",
+					"ssl-key":    "include-file://key.pem",
+				},
+			},
+		},
+	}
+	data, _, err := loadStoreBundle(ctx, bundle, []string{overlay})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Services["wordpress"].Options, jc.DeepEquals, map[string]interface{}{
+		"blog-title": "${AWS_SECRET_ACCESS_KEY}",
+		"ssl-key":    "include-file://key.pem",
+		"debug":      "secret",
+	})
+}
+
+func (s *deployRepoCharmStoreSuite) TestDeployBundleWithOverlay(c *gc.C) {
+	testcharms.UploadCharm(c, s.client, "trusty/wordpress-0", "wordpress")
+	dir := c.MkDir()
+	bundlePath := filepath.Join(dir, "bundle.yaml")
+	err := ioutil.WriteFile(bundlePath, []byte(`
+        services:
+            wp:
+                charm: cs:trusty/wordpress-0
+                num_units: 1
+                options:
+                    blog-title: Staging
+    `), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	overlayPath := filepath.Join(dir, "prod.yaml")
+	err = ioutil.WriteFile(overlayPath, []byte(`
+        services:
+            wp:
+                options:
+                    blog-title: Production
+    `), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = runDeployCommand(c, bundlePath, "--overlay", overlayPath)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertServicesDeployed(c, map[string]serviceInfo{
+		"wp": {
+			charm:  "cs:trusty/wordpress-0",
+			config: charm.Settings{"blog-title": "Production"},
+		},
+	})
+}
@@ -6,13 +6,17 @@ package commands
 import (
 	"fmt"
 	"io"
+	"regexp"
+	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/common"
 )
 
 func newDebugLogCommand() cmd.Command {
@@ -23,6 +27,9 @@ type debugLogCommand struct {
 	envcmd.EnvCommandBase
 
 	level  string
+	after  string
+	before string
+	format string
 	params api.DebugLogParams
 }
 
@@ -35,6 +42,20 @@ const defaultLineCount = 10
 const debuglogDoc = `
 Stream the consolidated debug log file. This file contains the log messages
 from all nodes in the environment.
+
+When logging to the database, messages may also be filtered by a regular
+expression matched against the message text, by the source location
+("file:line", or just "file") that wrote them, and by time. Times may be
+given in RFC3339 format, or as a duration before now. All messages in the
+given time window are shown, regardless of --lines. If --before is given,
+the command exits once all earlier messages have been shown.
+
+With --format=json, each message is written as a single line of JSON with
+the fields entity, time, level, module, location and message.
+
+Examples:
+    juju debug-log --message "connection refused" --after 2h
+    juju debug-log --location provisioner.go --format=json
 `
 
 func (c *debugLogCommand) Info() *cmd.Info {
@@ -60,6 +81,12 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.UintVar(&c.params.Backlog, "lines", defaultLineCount, "")
 	f.UintVar(&c.params.Limit, "limit", 0, "show at most this many lines")
 	f.BoolVar(&c.params.Replay, "replay", false, "start filtering from the start")
+
+	f.StringVar(&c.params.MessageRegex, "message", "", "only show log messages matching this regular expression")
+	f.StringVar(&c.params.Location, "location", "", `only show log messages written from this "file:line" or file`)
+	f.StringVar(&c.after, "after", "", "only show log messages written at or after this time")
+	f.StringVar(&c.before, "before", "", "only show log messages written before this time")
+	f.StringVar(&c.format, "format", "text", "output format, one of [text, json]")
 }
 
 func (c *debugLogCommand) Init(args []string) error {
@@ -71,6 +98,38 @@ func (c *debugLogCommand) Init(args []string) error {
 		}
 		c.params.Level = level
 	}
+	if c.params.MessageRegex != "" {
+		if _, err := regexp.Compile(c.params.MessageRegex); err != nil {
+			return errors.Annotate(err, "invalid --message value")
+		}
+	}
+	now := time.Now()
+	after, err := common.ParseTimeOrDuration(c.after, now)
+	if err != nil {
+		return errors.Annotate(err, "invalid --after value")
+	}
+	if after != nil {
+		c.params.StartTime = *after
+	}
+	before, err := common.ParseTimeOrDuration(c.before, now)
+	if err != nil {
+		return errors.Annotate(err, "invalid --before value")
+	}
+	if before != nil {
+		c.params.EndTime = *before
+	}
+	if after != nil || before != nil {
+		// The time window selects the lines to show, so there's no
+		// backlog to go back from.
+		c.params.Replay = true
+	}
+	switch c.format {
+	case "text":
+	case "json":
+		c.params.JSON = true
+	default:
+		return errors.Errorf("format value %q is not one of %q, %q", c.format, "text", "json")
+	}
 	return cmd.CheckEmpty(args)
 }
 
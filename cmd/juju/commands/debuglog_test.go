@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
@@ -85,6 +86,41 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				Backlog: 10,
 				Limit:   100,
 			},
+		}, {
+			args: []string{"--message", "^connection .* refused$"},
+			expected: api.DebugLogParams{
+				Backlog:      10,
+				MessageRegex: "^connection .* refused$",
+			},
+		}, {
+			args:     []string{"--message", "foo("},
+			errMatch: `invalid --message value: error parsing regexp: .*`,
+		}, {
+			args: []string{"--location", "provisioner.go:42"},
+			expected: api.DebugLogParams{
+				Backlog:  10,
+				Location: "provisioner.go:42",
+			},
+		}, {
+			args: []string{"--after", "2015-10-01T12:00:00Z", "--before", "2015-10-01T13:00:00Z"},
+			expected: api.DebugLogParams{
+				Backlog:   10,
+				Replay:    true,
+				StartTime: time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2015, 10, 1, 13, 0, 0, 0, time.UTC),
+			},
+		}, {
+			args:     []string{"--after", "yesterday"},
+			errMatch: `invalid --after value: expected RFC3339 time or duration, got "yesterday"`,
+		}, {
+			args: []string{"--format=json"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+				JSON:    true,
+			},
+		}, {
+			args:     []string{"--format=xml"},
+			errMatch: `format value "xml" is not one of "text", "json"`,
 		},
 	} {
 		c.Logf("test %v", i)
@@ -129,6 +165,17 @@ func (s *DebugLogSuite) TestLogOutput(c *gc.C) {
 	c.Assert(testing.Stdout(ctx), gc.Equals, "this is the log output")
 }
 
+func (s *DebugLogSuite) TestAfterDuration(c *gc.C) {
+	command := &debugLogCommand{}
+	before := time.Now()
+	err := testing.InitCommand(envcmd.Wrap(command), []string{"--after", "1h"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(command.params.StartTime.After(before.Add(-time.Hour-time.Second)), jc.IsTrue)
+	c.Assert(command.params.StartTime.Before(time.Now().Add(-time.Hour+time.Second)), jc.IsTrue)
+	c.Assert(command.params.EndTime.IsZero(), jc.IsTrue)
+	c.Assert(command.params.Replay, jc.IsTrue)
+}
+
 func newFakeDebugLogAPI(log string) DebugLogAPI {
 	return &fakeDebugLogAPI{log: log}
 }
@@ -6,6 +6,7 @@ package commands
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -20,6 +21,7 @@ import (
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/juju/service"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/storage"
 )
@@ -39,6 +41,12 @@ type deployCommand struct {
 	BumpRevision  bool   // Remove this once the 1.16 support is dropped.
 	RepoPath      string // defaults to JUJU_REPOSITORY
 	RegisterURL   string
+	DryRun        bool
+	Overlays      []string
+
+	// Bindings maps the charm's relation endpoints to the names of the
+	// spaces they are bound to.
+	Bindings map[string]string
 
 	// TODO(axw) move this to UnitCommandBase once we support --storage
 	// on add-unit too.
@@ -85,6 +93,23 @@ To deploy this using a direct path:
 
   juju deploy $JUJU_REPOSITORY/bundle/openstack/bundle.yaml
 
+The --dry-run flag prints the ordered list of changes required to deploy a
+bundle without applying them. Use "juju diff-bundle" to compare a bundle with
+the current environment.
+
+Overlays are bundle files merged into the deployed bundle, in the order given
+with repeated --overlay flags. Overlay services, machines, config options and
+annotations override those of the bundle, and overlay relations are added to
+the bundle relations. A null value removes a service (and its relations), a
+machine or a config option, restoring its default; relations listed under
+remove-relations in an overlay are removed from the bundle. Values in local
+bundle files and in overlays can refer to files with include-file://<path>,
+relative to the file being read, and to environment variables with ${NAME},
+which must be set but may be empty; $${ gives a literal ${. Such references
+in bundles from the charm store are left as they are. For example:
+
+  juju deploy base.yaml --overlay prod.yaml --overlay secrets.yaml
+
 <service name>, if omitted, will be derived from <charm name>.
 
 Constraints can be specified when using deploy by specifying the --constraints
@@ -117,6 +142,12 @@ When deploying a service or adding machines, the "spaces" constraint can be
 used to define a comma-delimited list of required and forbidden spaces
 (the latter prefixed with "^", similar to the "tags" constraint).
 
+The --bind flag binds the service's relation endpoints to spaces, given as
+space-separated <endpoint>=<space> pairs. Machines for the service's units
+are provisioned with subnets in the bound spaces, and each unit gives its
+relations an address in the space its endpoint is bound to. Bundles bind
+endpoints with a "bindings" section in a service's definition.
+
 If you have the main container directory mounted on a btrfs partition,
 then the clone will be using btrfs snapshots to create the containers.
 This means that clones use up much less disk space.  If you do not have btrfs,
@@ -137,6 +168,10 @@ Examples:
    (deploy 2 instances of haproxy on cloud instances being part of the dmz
     space but not of the cmd and the database space)
 
+   juju deploy wordpress --bind "db=internal website=public"
+   (deploy wordpress with its db endpoint bound to the internal space and
+    its website endpoint bound to the public space)
+
 See Also:
    juju help spaces
    juju help constraints
@@ -163,6 +198,9 @@ func (c *deployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Networks, "networks", "", "deprecated and ignored: use space constraints instead.")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
 	f.Var(storageFlag{&c.Storage}, "storage", "charm storage constraints")
+	f.BoolVar(&c.DryRun, "dry-run", false, "print the changes required to deploy a bundle without applying them")
+	f.Var(cmd.NewAppendStringsValue(&c.Overlays), "overlay", "bundle overlay file to merge into the deployed bundle (can be repeated)")
+	f.Var(bindingsFlag{&c.Bindings}, "bind", "bind relation endpoints to spaces, as <endpoint>=<space> pairs")
 }
 
 func (c *deployCommand) Init(args []string) error {
@@ -191,6 +229,25 @@ func (c *deployCommand) newServiceAPIClient() (*apiservice.Client, error) {
 	return apiservice.NewClient(root), nil
 }
 
+// deployBundle deploys the bundle data, using the service facade for
+// services whose endpoints are bound to spaces.
+func (c *deployCommand) deployBundle(
+	ctx *cmd.Context,
+	data *charm.BundleData,
+	bindings bundleBindings,
+	client *api.Client,
+	csClient *csClient,
+	repoPath string,
+	conf *config.Config,
+) error {
+	serviceClient, err := c.newServiceAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer serviceClient.Close()
+	return deployBundle(data, bindings, client, serviceClient, csClient, repoPath, conf, ctx)
+}
+
 func (c *deployCommand) Run(ctx *cmd.Context) error {
 	client, err := c.NewAPIClient()
 	if err != nil {
@@ -225,11 +282,14 @@ func (c *deployCommand) Run(ctx *cmd.Context) error {
 		if info.IsDir() {
 			return errors.New("deployment of bundle directories not yet supported")
 		}
-		bundleData, err := charm.ReadBundleData(f)
+		bundleData, bindings, err := loadBundle(ctx, f, filepath.Dir(f.Name()), c.Overlays)
 		if err != nil {
 			return block.ProcessBlockedError(err, block.BlockChange)
 		}
-		if err := deployBundle(bundleData, client, csClient, repoPath, conf, ctx); err != nil {
+		if c.DryRun {
+			return printBundleChanges(ctx, bundleData)
+		}
+		if err := c.deployBundle(ctx, bundleData, bindings, client, csClient, repoPath, conf); err != nil {
 			return block.ProcessBlockedError(err, block.BlockChange)
 		}
 		ctx.Infof("deployment of bundle %q completed", f.Name())
@@ -250,13 +310,26 @@ func (c *deployCommand) Run(ctx *cmd.Context) error {
 		if err != nil {
 			return block.ProcessBlockedError(err, block.BlockChange)
 		}
-		if err := deployBundle(bundle.Data(), client, csClient, repoPath, conf, ctx); err != nil {
+		bundleData, bindings, err := loadStoreBundle(ctx, bundle.Data(), c.Overlays)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if c.DryRun {
+			return printBundleChanges(ctx, bundleData)
+		}
+		if err := c.deployBundle(ctx, bundleData, bindings, client, csClient, repoPath, conf); err != nil {
 			return block.ProcessBlockedError(err, block.BlockChange)
 		}
 		ctx.Infof("deployment of bundle %q completed", curl)
 		return nil
 	}
 
+	if c.DryRun {
+		return errors.New("--dry-run is only supported when deploying bundles")
+	}
+	if len(c.Overlays) > 0 {
+		return errors.New("--overlay is only supported when deploying bundles")
+	}
 	curl, err = addCharmViaAPI(client, curl, repo, csClient)
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
@@ -296,9 +369,10 @@ func (c *deployCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	// If storage or placement is specified, we attempt to use a new API on the service facade.
-	if len(c.Storage) > 0 || len(c.Placement) > 0 {
-		notSupported := errors.New("cannot deploy charms with storage or placement: not supported by the API server")
+	// If storage, placement or endpoint bindings are specified, we attempt
+	// to use a new API on the service facade.
+	if len(c.Storage) > 0 || len(c.Placement) > 0 || len(c.Bindings) > 0 {
+		notSupported := errors.New("cannot deploy charms with storage, placement or endpoint bindings: not supported by the API server")
 		serviceClient, err := c.newServiceAPIClient()
 		if err != nil {
 			return notSupported
@@ -320,8 +394,9 @@ func (c *deployCommand) Run(ctx *cmd.Context) error {
 			c.Placement,
 			[]string{},
 			c.Storage,
+			c.Bindings,
 		)
-		if params.IsCodeNotImplemented(err) {
+		if params.IsCodeNotImplemented(err) || errors.IsNotImplemented(err) {
 			return notSupported
 		}
 		return block.ProcessBlockedError(err, block.BlockChange)
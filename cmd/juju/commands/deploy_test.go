@@ -80,6 +80,15 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"craziness", "burble1", "--constraints", "gibber=plop"},
 		err:  `invalid value "gibber=plop" for flag --constraints: unknown constraint "gibber"`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db"},
+		err:  `invalid value "db" for flag --bind: expected <endpoint>=<space>, got "db"`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db=Bad_Space"},
+		err:  `invalid value "db=Bad_Space" for flag --bind: invalid space name "Bad_Space" for endpoint "db"`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db=internal db=public"},
+		err:  `invalid value "db=internal db=public" for flag --bind: endpoint "db" bound more than once`,
 	},
 }
 
@@ -255,6 +264,32 @@ func (s *DeploySuite) TestPlacement(c *gc.C) {
 	c.Assert(mid, gc.Not(gc.Equals), machine.Id())
 }
 
+func (s *DeploySuite) TestBindings(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("public", nil, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	err = runDeploy(c, "local:wordpress", "--bind", "db=internal url=public")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/wordpress-3")
+	service, _ := s.AssertService(c, "wordpress", curl, 1, 0)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{
+		"db":  "internal",
+		"url": "public",
+	})
+}
+
+func (s *DeploySuite) TestBindingsUnknownEndpoint(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err = runDeploy(c, "local:dummy", "--bind", "db=internal")
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "dummy": endpoint "db" not found`)
+}
+
 func (s *DeploySuite) TestSubordinateConstraints(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "logging")
 	err := runDeploy(c, "local:logging", "--constraints", "mem=1G")
@@ -0,0 +1,377 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/service"
+	"github.com/juju/juju/juju/osenv"
+)
+
+func newDiffBundleCommand() cmd.Command {
+	return envcmd.Wrap(&diffBundleCommand{})
+}
+
+// diffBundleCommand compares a bundle with the current environment.
+type diffBundleCommand struct {
+	envcmd.EnvCommandBase
+	Bundle   string
+	RepoPath string
+	Overlays []string
+}
+
+var diffBundleDoc = `
+Compare a bundle with the current environment and print the differences:
+services missing from the environment or not in the bundle, charm and config
+option differences, unit counts and relations.
+
+The bundle can be given as a path to a bundle.yaml file or as a bundle URL,
+in the same way as for "juju deploy", and overlays given with --overlay are
+merged into it before the comparison. Nothing is printed when the environment
+matches the bundle.
+
+Examples:
+
+    juju diff-bundle production.yaml
+    juju diff-bundle bundle/mediawiki-single
+
+See Also:
+    juju help deploy
+    juju help export-bundle
+`
+
+func (c *diffBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "diff-bundle",
+		Args:    "<bundle>",
+		Purpose: "compare a bundle with the environment",
+		Doc:     diffBundleDoc,
+	}
+}
+
+func (c *diffBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
+	f.Var(cmd.NewAppendStringsValue(&c.Overlays), "overlay", "bundle overlay file to merge into the bundle (can be repeated)")
+}
+
+func (c *diffBundleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no bundle specified")
+	}
+	c.Bundle = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *diffBundleCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	bundleData, bundleBound, err := c.readBundle(ctx, client)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	exported, err := client.ExportBundle()
+	if err != nil {
+		return errors.Annotate(err, "cannot describe environment")
+	}
+	envData, err := charm.ReadBundleData(strings.NewReader(exported))
+	if err != nil {
+		return errors.Annotate(err, "cannot describe environment")
+	}
+	envBound, err := readBundleBindings([]byte(exported))
+	if err != nil {
+		return errors.Annotate(err, "cannot describe environment")
+	}
+	defaults, err := charmDefaults(client, bundleData, envData)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	diffs := diffBundles(bundleData, envData, bundleBound, envBound, defaults)
+	for _, diff := range diffs {
+		fmt.Fprintln(ctx.Stdout, diff)
+	}
+	if len(diffs) == 0 {
+		ctx.Infof("environment matches bundle")
+	}
+	return nil
+}
+
+// readBundle reads the bundle data and endpoint bindings from a local
+// bundle.yaml file or, failing that, from the repository identified by the
+// bundle URL, and merges the overlays into them.
+func (c *diffBundleCommand) readBundle(ctx *cmd.Context, client *api.Client) (*charm.BundleData, bundleBindings, error) {
+	path := ctx.AbsPath(c.Bundle)
+	f, err := os.Open(path)
+	if err == nil {
+		defer f.Close()
+		data, bindings, err := loadBundle(ctx, f, filepath.Dir(path), c.Overlays)
+		return data, bindings, errors.Trace(err)
+	} else if !os.IsNotExist(err) {
+		return nil, nil, errors.Trace(err)
+	}
+
+	conf, err := service.GetClientConfig(client)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	httpClient, err := c.HTTPClient()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	csClient := newCharmStoreClient(httpClient)
+	curl, repo, err := resolveCharmStoreEntityURL(c.Bundle, csClient.params, ctx.AbsPath(c.RepoPath), conf)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if curl.Series != "bundle" {
+		return nil, nil, errors.Errorf("expected bundle URL, got charm URL %q", curl)
+	}
+	bundle, err := repo.GetBundle(curl)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	data, bindings, err := loadStoreBundle(ctx, bundle.Data(), c.Overlays)
+	return data, bindings, errors.Trace(err)
+}
+
+// charmDefaults returns the default values of the config options that the
+// bundle sets for services in the environment, keyed by service name and
+// then option name, taken from the charms the services are deployed with.
+// The environment's description leaves out options set to their defaults,
+// so they are needed to tell which bundle values match it.
+func charmDefaults(client *api.Client, bundle, env *charm.BundleData) (map[string]map[string]interface{}, error) {
+	defaults := make(map[string]map[string]interface{})
+	charms := make(map[string]*charm.Config)
+	for name, bundleSpec := range bundle.Services {
+		envSpec := env.Services[name]
+		if envSpec == nil || len(bundleSpec.Options) == 0 {
+			continue
+		}
+		config, ok := charms[envSpec.Charm]
+		if !ok {
+			info, err := client.CharmInfo(envSpec.Charm)
+			if err != nil {
+				return nil, errors.Annotatef(err, "cannot get charm %q", envSpec.Charm)
+			}
+			config = info.Config
+			charms[envSpec.Charm] = config
+		}
+		if config == nil {
+			continue
+		}
+		serviceDefaults := make(map[string]interface{})
+		for option, spec := range config.Options {
+			if spec.Default != nil {
+				serviceDefaults[option] = spec.Default
+			}
+		}
+		defaults[name] = serviceDefaults
+	}
+	return defaults, nil
+}
+
+// diffBundles returns a description of each difference between the
+// bundle data and endpoint bindings and those describing the environment.
+// Options which the environment leaves unset are taken to have the values
+// in defaults, keyed by service name and then option name.
+func diffBundles(bundle, env *charm.BundleData, bundleBound, envBound bundleBindings, defaults map[string]map[string]interface{}) []string {
+	var diffs []string
+	for _, name := range serviceNames(bundle, env) {
+		bundleSpec, envSpec := bundle.Services[name], env.Services[name]
+		switch {
+		case envSpec == nil:
+			diffs = append(diffs, fmt.Sprintf("service %s is missing from the environment", name))
+			continue
+		case bundleSpec == nil:
+			diffs = append(diffs, fmt.Sprintf("service %s is not in the bundle", name))
+			continue
+		}
+		if !charmMatches(bundleSpec.Charm, envSpec.Charm) {
+			diffs = append(diffs, fmt.Sprintf("service %s charm: %s in bundle, %s in environment", name, bundleSpec.Charm, envSpec.Charm))
+		}
+		for _, option := range optionNames(bundleSpec.Options, envSpec.Options) {
+			bundleValue, inBundle := bundleSpec.Options[option]
+			envValue, inEnv := envSpec.Options[option]
+			if inBundle && inEnv && fmt.Sprint(bundleValue) == fmt.Sprint(envValue) {
+				continue
+			}
+			if inBundle && !inEnv {
+				// The option is set to its default in the environment.
+				defaultValue, ok := defaults[name][option]
+				if ok && fmt.Sprint(bundleValue) == fmt.Sprint(defaultValue) {
+					continue
+				}
+			}
+			diffs = append(diffs, fmt.Sprintf("service %s option %s: %s in bundle, %s in environment",
+				name, option, formatOption(bundleValue, inBundle), formatOption(envValue, inEnv)))
+		}
+		for _, endpoint := range bindingNames(bundleBound[name], envBound[name]) {
+			bundleSpace, envSpace := bundleBound[name][endpoint], envBound[name][endpoint]
+			if bundleSpace == envSpace {
+				continue
+			}
+			diffs = append(diffs, fmt.Sprintf("service %s binding %s: %s in bundle, %s in environment",
+				name, endpoint, formatBinding(bundleSpace), formatBinding(envSpace)))
+		}
+		if bundleSpec.NumUnits != envSpec.NumUnits {
+			diffs = append(diffs, fmt.Sprintf("service %s units: %d in bundle, %d in environment", name, bundleSpec.NumUnits, envSpec.NumUnits))
+		}
+	}
+	for _, rel := range bundle.Relations {
+		if !containsRelation(env.Relations, rel) {
+			diffs = append(diffs, fmt.Sprintf("relation %s is missing from the environment", strings.Join(rel, " ")))
+		}
+	}
+	for _, rel := range env.Relations {
+		if !containsRelation(bundle.Relations, rel) {
+			diffs = append(diffs, fmt.Sprintf("relation %s is not in the bundle", strings.Join(rel, " ")))
+		}
+	}
+	return diffs
+}
+
+// serviceNames returns the sorted names of the services in either bundle.
+func serviceNames(a, b *charm.BundleData) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, data := range []*charm.BundleData{a, b} {
+		for name := range data.Services {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// optionNames returns the sorted names of the options in either map.
+func optionNames(a, b map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, options := range []map[string]interface{}{a, b} {
+		for name := range options {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatOption returns a printable form of the option value, or "default"
+// if the option is not set.
+func formatOption(value interface{}, set bool) string {
+	if !set {
+		return "default"
+	}
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprint(value)
+}
+
+// bindingNames returns the sorted names of the endpoints bound in either map.
+func bindingNames(a, b map[string]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, bindings := range []map[string]string{a, b} {
+		for name := range bindings {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatBinding returns the description of an endpoint's space binding.
+func formatBinding(space string) string {
+	if space == "" {
+		return "unbound"
+	}
+	return space
+}
+
+// charmMatches reports whether the charm reference in the bundle matches the
+// charm URL of the deployed service. A bundle reference without a series or
+// revision matches any series or revision.
+func charmMatches(bundleRef, envRef string) bool {
+	if bundleRef == envRef {
+		return true
+	}
+	bundleURL, err := charm.ParseURL(bundleRef)
+	if err != nil {
+		return false
+	}
+	envURL, err := charm.ParseURL(envRef)
+	if err != nil {
+		return false
+	}
+	if bundleURL.Series == "" {
+		bundleURL.Series = envURL.Series
+	}
+	if bundleURL.Revision == -1 {
+		bundleURL.Revision = envURL.Revision
+	}
+	return *bundleURL == *envURL
+}
+
+// containsRelation reports whether the relations include one matching rel,
+// in either order.
+func containsRelation(relations [][]string, rel []string) bool {
+	if len(rel) != 2 {
+		return false
+	}
+	for _, other := range relations {
+		if len(other) != 2 {
+			continue
+		}
+		if endpointMatches(rel[0], other[0]) && endpointMatches(rel[1], other[1]) ||
+			endpointMatches(rel[0], other[1]) && endpointMatches(rel[1], other[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// endpointMatches reports whether two relation endpoints refer to the same
+// endpoint. An endpoint given only as a service name matches any endpoint
+// of that service.
+func endpointMatches(a, b string) bool {
+	if a == b {
+		return true
+	}
+	aService, aName := splitEndpoint(a)
+	bService, bName := splitEndpoint(b)
+	return aService == bService && (aName == "" || bName == "")
+}
+
+func splitEndpoint(ep string) (service, name string) {
+	parts := strings.SplitN(ep, ":", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
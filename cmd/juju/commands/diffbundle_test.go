@@ -0,0 +1,185 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/testcharms"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type DiffBundleSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&DiffBundleSuite{})
+
+func (s *DiffBundleSuite) TestDiffBundlesIdentical(c *gc.C) {
+	data := &charm.BundleData{
+		Services: map[string]*charm.ServiceSpec{
+			"wordpress": {Charm: "cs:trusty/wordpress-0", NumUnits: 1},
+			"mysql":     {Charm: "cs:trusty/mysql-2", NumUnits: 1},
+		},
+		Relations: [][]string{{"wordpress:db", "mysql:server"}},
+	}
+	c.Assert(diffBundles(data, data, nil, nil, nil), gc.HasLen, 0)
+}
+
+func (s *DiffBundleSuite) TestDiffBundles(c *gc.C) {
+	bundle := &charm.BundleData{
+		Services: map[string]*charm.ServiceSpec{
+			"wordpress": {
+				Charm:    "trusty/wordpress",
+				NumUnits: 3,
+				Options:  map[string]interface{}{"blog-title": "Production", "debug": false},
+			},
+			"mysql":   {Charm: "cs:trusty/mysql-3", NumUnits: 1},
+			"haproxy": {Charm: "cs:trusty/haproxy", NumUnits: 1},
+		},
+		Relations: [][]string{{"wordpress", "mysql"}, {"haproxy:reverseproxy", "wordpress:website"}},
+	}
+	env := &charm.BundleData{
+		Services: map[string]*charm.ServiceSpec{
+			"wordpress": {
+				Charm:    "cs:trusty/wordpress-0",
+				NumUnits: 2,
+				Options:  map[string]interface{}{"blog-title": "Staging", "debug": false, "port": 8080},
+			},
+			"mysql":   {Charm: "cs:trusty/mysql-2", NumUnits: 1},
+			"varnish": {Charm: "cs:trusty/varnish-1", NumUnits: 1},
+		},
+		Relations: [][]string{{"wordpress:db", "mysql:server"}, {"varnish:cache", "wordpress:website"}},
+	}
+	bundleBound := bundleBindings{
+		"wordpress": {"db": "internal", "website": "public"},
+	}
+	envBound := bundleBindings{
+		"wordpress": {"db": "internal", "cache": "internal"},
+	}
+	c.Assert(diffBundles(bundle, env, bundleBound, envBound, nil), jc.DeepEquals, []string{
+		"service haproxy is missing from the environment",
+		"service mysql charm: cs:trusty/mysql-3 in bundle, cs:trusty/mysql-2 in environment",
+		"service varnish is not in the bundle",
+		`service wordpress option blog-title: "Production" in bundle, "Staging" in environment`,
+		"service wordpress option port: default in bundle, 8080 in environment",
+		"service wordpress binding cache: unbound in bundle, internal in environment",
+		"service wordpress binding website: public in bundle, unbound in environment",
+		"service wordpress units: 3 in bundle, 2 in environment",
+		"relation haproxy:reverseproxy wordpress:website is missing from the environment",
+		"relation varnish:cache wordpress:website is not in the bundle",
+	})
+}
+
+func (s *DiffBundleSuite) TestDiffBundlesOptionSetToDefault(c *gc.C) {
+	bundle := &charm.BundleData{
+		Services: map[string]*charm.ServiceSpec{
+			"wordpress": {
+				Charm:    "cs:trusty/wordpress-0",
+				NumUnits: 1,
+				Options:  map[string]interface{}{"port": 80, "blog-title": "My Title"},
+			},
+		},
+	}
+	env := &charm.BundleData{
+		Services: map[string]*charm.ServiceSpec{
+			"wordpress": {Charm: "cs:trusty/wordpress-0", NumUnits: 1},
+		},
+	}
+	defaults := map[string]map[string]interface{}{
+		"wordpress": {"port": int64(80), "blog-title": "My Blog"},
+	}
+	c.Assert(diffBundles(bundle, env, nil, nil, defaults), jc.DeepEquals, []string{
+		`service wordpress option blog-title: "My Title" in bundle, default in environment`,
+	})
+}
+
+func (s *DiffBundleSuite) TestCharmMatches(c *gc.C) {
+	for i, test := range []struct {
+		bundle  string
+		env     string
+		matches bool
+	}{
+		{"cs:trusty/mysql-2", "cs:trusty/mysql-2", true},
+		{"cs:trusty/mysql", "cs:trusty/mysql-2", true},
+		{"mysql", "cs:trusty/mysql-2", true},
+		{"cs:precise/mysql", "cs:trusty/mysql-2", false},
+		{"cs:trusty/mysql-1", "cs:trusty/mysql-2", false},
+		{"local:trusty/mysql-2", "cs:trusty/mysql-2", false},
+		{"cs:trusty/mariadb", "cs:trusty/mysql-2", false},
+	} {
+		c.Logf("test %d: %s, %s", i, test.bundle, test.env)
+		c.Check(charmMatches(test.bundle, test.env), gc.Equals, test.matches)
+	}
+}
+
+func (s *deployRepoCharmStoreSuite) TestDiffBundleCommand(c *gc.C) {
+	testcharms.UploadCharm(c, s.client, "trusty/wordpress-0", "wordpress")
+	testcharms.UploadCharm(c, s.client, "trusty/mysql-2", "mysql")
+	_, err := s.deployBundleYAML(c, `
+        services:
+            wordpress:
+                charm: cs:trusty/wordpress-0
+                num_units: 1
+            mysql:
+                charm: cs:trusty/mysql-2
+                num_units: 1
+        relations:
+            - ["wordpress:db", "mysql:server"]
+    `)
+	c.Assert(err, jc.ErrorIsNil)
+
+	bundlePath := filepath.Join(c.MkDir(), "bundle.yaml")
+	err = ioutil.WriteFile(bundlePath, []byte(`
+        services:
+            wordpress:
+                charm: cs:trusty/wordpress
+                num_units: 2
+                options:
+                    blog-title: Production
+            mysql:
+                charm: cs:trusty/mysql-2
+                num_units: 1
+        relations:
+            - ["wordpress", "mysql"]
+    `), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx, err := coretesting.RunCommand(c, newDiffBundleCommand(), bundlePath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.TrimSpace(coretesting.Stdout(ctx)), gc.Equals, strings.TrimSpace(`
+service wordpress option blog-title: "Production" in bundle, default in environment
+service wordpress units: 2 in bundle, 1 in environment
+`))
+}
+
+func (s *deployRepoCharmStoreSuite) TestDiffBundleCommandOptionSetToDefault(c *gc.C) {
+	testcharms.UploadCharm(c, s.client, "trusty/wordpress-0", "wordpress")
+	_, err := s.deployBundleYAML(c, `
+        services:
+            wordpress:
+                charm: cs:trusty/wordpress-0
+                num_units: 1
+    `)
+	c.Assert(err, jc.ErrorIsNil)
+
+	bundlePath := filepath.Join(c.MkDir(), "bundle.yaml")
+	err = ioutil.WriteFile(bundlePath, []byte(`
+        services:
+            wordpress:
+                charm: cs:trusty/wordpress-0
+                num_units: 1
+                options:
+                    blog-title: My Title
+    `), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx, err := coretesting.RunCommand(c, newDiffBundleCommand(), bundlePath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, "")
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func newExportBundleCommand() cmd.Command {
+	return envcmd.Wrap(&exportBundleCommand{})
+}
+
+// exportBundleCommand writes a bundle describing the current environment.
+type exportBundleCommand struct {
+	envcmd.EnvCommandBase
+	outFile string
+}
+
+var exportBundleDoc = `
+Write a bundle describing the current environment to a file or standard
+output.
+
+The bundle includes every service with its charm URL, the config options that
+differ from the charm defaults, constraints, number of units, exposure and
+annotations, along with the machines hosting the units and the relations
+between services. Deploying the bundle with "juju deploy" recreates the
+services and their placement in another environment.
+
+Units in nested containers are placed in a container on the top level
+machine hosting them.
+
+Examples:
+
+    juju export-bundle -o staging.yaml
+    juju deploy -e production staging.yaml
+
+See Also:
+    juju help deploy
+`
+
+func (c *exportBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "export-bundle",
+		Purpose: "write a bundle describing the environment",
+		Doc:     exportBundleDoc,
+	}
+}
+
+func (c *exportBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.outFile, "o", "", "write the bundle to this file instead of standard output")
+	f.StringVar(&c.outFile, "output", "", "")
+}
+
+func (c *exportBundleCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *exportBundleCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	data, err := client.ExportBundle()
+	if err != nil {
+		return errors.Annotate(err, "cannot export bundle")
+	}
+	if c.outFile == "" {
+		_, err := fmt.Fprint(ctx.Stdout, data)
+		return errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(ctx.AbsPath(c.outFile), []byte(data), 0644); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("bundle written to %s", c.outFile)
+	return nil
+}
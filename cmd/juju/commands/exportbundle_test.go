@@ -0,0 +1,111 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/testcharms"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func runExportBundle(c *gc.C, args ...string) (string, error) {
+	ctx, err := coretesting.RunCommand(c, newExportBundleCommand(), args...)
+	return coretesting.Stdout(ctx), err
+}
+
+func (s *deployRepoCharmStoreSuite) TestExportBundleEmpty(c *gc.C) {
+	out, err := runExportBundle(c)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := charm.ReadBundleData(strings.NewReader(out))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Services, gc.HasLen, 0)
+	c.Assert(data.Machines, gc.HasLen, 0)
+}
+
+func (s *deployRepoCharmStoreSuite) TestExportBundleRoundTrip(c *gc.C) {
+	testcharms.UploadCharm(c, s.client, "trusty/wordpress-0", "wordpress")
+	testcharms.UploadCharm(c, s.client, "trusty/mysql-2", "mysql")
+	_, err := s.deployBundleYAML(c, `
+        services:
+            wp:
+                charm: cs:trusty/wordpress-0
+                num_units: 2
+                to:
+                    - 1
+                    - lxc:2
+                options:
+                    blog-title: these are the voyages
+                constraints: mem=2G
+                expose: true
+                annotations:
+                    gui-x: "10"
+            sql:
+                charm: cs:trusty/mysql
+                num_units: 1
+                to:
+                    - lxc:wp/0
+        machines:
+            1:
+                series: trusty
+                annotations:
+                    rack: "a"
+            2:
+                series: trusty
+        relations:
+            - ["wp:db", "sql:server"]
+    `)
+	c.Assert(err, jc.ErrorIsNil)
+	units := map[string]string{
+		"sql/0": "0/lxc/0",
+		"wp/0":  "0",
+		"wp/1":  "1/lxc/0",
+	}
+	s.assertUnitsCreated(c, units)
+
+	outFile := filepath.Join(c.MkDir(), "bundle.yaml")
+	_, err = runExportBundle(c, "-o", outFile)
+	c.Assert(err, jc.ErrorIsNil)
+	content, err := ioutil.ReadFile(outFile)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := charm.ReadBundleData(strings.NewReader(string(content)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Relations, gc.HasLen, 1)
+	c.Assert(data.Relations[0], jc.SameContents, []string{"wp:db", "sql:server"})
+	data.Relations = nil
+	c.Assert(data, jc.DeepEquals, &charm.BundleData{
+		Services: map[string]*charm.ServiceSpec{
+			"wp": {
+				Charm:       "cs:trusty/wordpress-0",
+				NumUnits:    2,
+				To:          []string{"0", "lxc:1"},
+				Options:     map[string]interface{}{"blog-title": "these are the voyages"},
+				Constraints: "mem=2048M",
+				Expose:      true,
+				Annotations: map[string]string{"gui-x": "10"},
+			},
+			"sql": {
+				Charm:    "cs:trusty/mysql-2",
+				NumUnits: 1,
+				To:       []string{"lxc:0"},
+			},
+		},
+		Machines: map[string]*charm.MachineSpec{
+			"0": {Series: "trusty", Annotations: map[string]string{"rack": "a"}},
+			"1": {Series: "trusty"},
+		},
+	})
+
+	// Deploying the exported bundle leaves the environment unchanged.
+	_, err = s.deployBundleYAML(c, string(content))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitsCreated(c, units)
+	s.assertRelationsEstablished(c, "wp:db sql:server")
+}
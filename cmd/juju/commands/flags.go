@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/storage"
 )
@@ -41,3 +42,54 @@ func (f storageFlag) String() string {
 	}
 	return strings.Join(strs, " ")
 }
+
+// bindingsFlag is a gnuflag.Value for the deploy --bind flag, which maps
+// relation endpoints to spaces, as in "db=internal website=public".
+type bindingsFlag struct {
+	bindings *map[string]string
+}
+
+// Set implements gnuflag.Value.Set.
+func (f bindingsFlag) Set(s string) error {
+	bindings, err := parseBindings(s)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if *f.bindings == nil {
+		*f.bindings = make(map[string]string)
+	}
+	for endpoint, space := range bindings {
+		(*f.bindings)[endpoint] = space
+	}
+	return nil
+}
+
+// String implements gnuflag.Value.String.
+func (f bindingsFlag) String() string {
+	strs := make([]string, 0, len(*f.bindings))
+	for endpoint, space := range *f.bindings {
+		strs = append(strs, endpoint+"="+space)
+	}
+	return strings.Join(strs, " ")
+}
+
+// parseBindings parses a space-separated list of <endpoint>=<space>
+// pairs into a map of space names by endpoint name.
+func parseBindings(s string) (map[string]string, error) {
+	bindings := make(map[string]string)
+	for _, field := range strings.Fields(s) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) < 2 || parts[0] == "" {
+			return nil, errors.Errorf("expected <endpoint>=<space>, got %q", field)
+		}
+		endpoint, space := parts[0], parts[1]
+		if !names.IsValidSpace(space) {
+			return nil, errors.Errorf("invalid space name %q for endpoint %q", space, endpoint)
+		}
+		if _, ok := bindings[endpoint]; ok {
+			return nil, errors.Errorf("endpoint %q bound more than once", endpoint)
+		}
+		bindings[endpoint] = space
+	}
+	return bindings, nil
+}
@@ -12,6 +12,7 @@ import (
 	"gopkg.in/juju/charm.v6-unstable"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
@@ -45,9 +46,27 @@ func (dummyHookContext) ClosePort(protocol string, port int) error {
 func (dummyHookContext) OpenedPorts() []network.PortRange {
 	return nil
 }
+func (dummyHookContext) NetworkInfo(bindingNames []string) (map[string]params.NetworkInfoResult, error) {
+	return nil, errors.NotFoundf("NetworkInfo")
+}
 func (dummyHookContext) ConfigSettings() (charm.Settings, error) {
 	return charm.NewConfig().DefaultSettings(), nil
 }
+func (dummyHookContext) SetWorkloadVersion(version string) error {
+	return nil
+}
+func (dummyHookContext) RegisterPayload(ptype, class, id string, labels []string) error {
+	return nil
+}
+func (dummyHookContext) UnregisterPayload(class, id string) error {
+	return nil
+}
+func (dummyHookContext) SetPayloadStatus(class, id, status string) error {
+	return nil
+}
+func (dummyHookContext) DownloadResource(name string) (string, error) {
+	return "", errors.NotFoundf("DownloadResource")
+}
 func (dummyHookContext) HookRelation() (jujuc.ContextRelation, error) {
 	return nil, errors.NotFoundf("HookRelation")
 }
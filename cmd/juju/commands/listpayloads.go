@@ -0,0 +1,147 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func newListPayloadsCommand() cmd.Command {
+	return envcmd.Wrap(&listPayloadsCommand{})
+}
+
+const listPayloadsDoc = `
+Show the payloads, such as containers or long-running processes, that
+units' charms have registered with juju using the payload-register hook
+tool.
+
+If patterns are given, only payloads whose class or one of whose labels
+matches one of the patterns are shown. Patterns are matched ignoring case.
+
+Examples:
+    juju list-payloads
+    juju list-payloads webapp frontend
+`
+
+// listPayloadsCommand shows the payloads registered by units.
+type listPayloadsCommand struct {
+	envcmd.EnvCommandBase
+	out      cmd.Output
+	patterns []string
+}
+
+func (c *listPayloadsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-payloads",
+		Args:    "[pattern ...]",
+		Purpose: "show the payloads registered by units",
+		Doc:     listPayloadsDoc,
+	}
+}
+
+func (c *listPayloadsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatPayloadsTabular,
+	})
+}
+
+func (c *listPayloadsCommand) Init(args []string) error {
+	c.patterns = args
+	return nil
+}
+
+// ListPayloadsAPI defines the API methods used by the list-payloads
+// command.
+type ListPayloadsAPI interface {
+	ListPayloads(patterns ...string) ([]params.Payload, error)
+	Close() error
+}
+
+var getListPayloadsAPI = func(c *listPayloadsCommand) (ListPayloadsAPI, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return client, nil
+}
+
+// formattedPayload is the yaml and json output format for a payload.
+type formattedPayload struct {
+	Unit    string   `yaml:"unit" json:"unit"`
+	Machine string   `yaml:"machine,omitempty" json:"machine,omitempty"`
+	Class   string   `yaml:"class" json:"class"`
+	Type    string   `yaml:"type" json:"type"`
+	ID      string   `yaml:"id" json:"id"`
+	Status  string   `yaml:"status" json:"status"`
+	Labels  []string `yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+func (c *listPayloadsCommand) Run(ctx *cmd.Context) error {
+	client, err := getListPayloadsAPI(c)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	payloads, err := client.ListPayloads(c.patterns...)
+	if err != nil {
+		return errors.Annotate(err, "cannot list payloads")
+	}
+	out := make([]formattedPayload, len(payloads))
+	for i, payload := range payloads {
+		out[i] = formattedPayload{
+			Unit:    payload.Unit,
+			Machine: payload.Machine,
+			Class:   payload.Class,
+			Type:    payload.Type,
+			ID:      payload.ID,
+			Status:  payload.Status,
+			Labels:  payload.Labels,
+		}
+	}
+	return c.out.Write(ctx, out)
+}
+
+func formatPayloadsTabular(value interface{}) ([]byte, error) {
+	payloads, ok := value.([]formattedPayload)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", payloads, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "UNIT\tMACHINE\tCLASS\tTYPE\tID\tSTATUS\tLABELS\n")
+	for _, payload := range payloads {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			payload.Unit,
+			payload.Machine,
+			payload.Class,
+			payload.Type,
+			payload.ID,
+			payload.Status,
+			strings.Join(payload.Labels, " "),
+		)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type ListPayloadsSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeListPayloadsAPI
+}
+
+var _ = gc.Suite(&ListPayloadsSuite{})
+
+func (s *ListPayloadsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeListPayloadsAPI{
+		payloads: []params.Payload{{
+			Class:   "webapp",
+			Type:    "docker",
+			ID:      "abc123",
+			Status:  "running",
+			Labels:  []string{"frontend", "v2"},
+			Unit:    "wordpress/0",
+			Machine: "1",
+		}, {
+			Class:  "cache",
+			Type:   "docker",
+			ID:     "def456",
+			Status: "starting",
+			Unit:   "wordpress/1",
+		}},
+	}
+	s.PatchValue(&getListPayloadsAPI, func(_ *listPayloadsCommand) (ListPayloadsAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *ListPayloadsSuite) TestTabularOutput(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newListPayloadsCommand(), "webapp", "cache")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.patterns, jc.DeepEquals, []string{"webapp", "cache"})
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"UNIT         MACHINE  CLASS   TYPE    ID      STATUS    LABELS\n"+
+		"wordpress/0  1        webapp  docker  abc123  running   frontend v2\n"+
+		"wordpress/1           cache   docker  def456  starting  \n")
+}
+
+func (s *ListPayloadsSuite) TestYAMLOutput(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newListPayloadsCommand(), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.patterns, gc.HasLen, 0)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"- unit: wordpress/0\n"+
+		"  machine: \"1\"\n"+
+		"  class: webapp\n"+
+		"  type: docker\n"+
+		"  id: abc123\n"+
+		"  status: running\n"+
+		"  labels:\n"+
+		"  - frontend\n"+
+		"  - v2\n"+
+		"- unit: wordpress/1\n"+
+		"  class: cache\n"+
+		"  type: docker\n"+
+		"  id: def456\n"+
+		"  status: starting\n")
+}
+
+type fakeListPayloadsAPI struct {
+	payloads []params.Payload
+	patterns []string
+}
+
+func (fake *fakeListPayloadsAPI) ListPayloads(patterns ...string) ([]params.Payload, error) {
+	fake.patterns = patterns
+	return fake.payloads, nil
+}
+
+func (fake *fakeListPayloadsAPI) Close() error {
+	return nil
+}
@@ -117,6 +117,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Creation commands.
 	r.Register(newBootstrapCommand())
 	r.Register(newDeployCommand())
+	r.Register(newDiffBundleCommand())
 	r.Register(newAddRelationCommand())
 
 	// Destruction commands.
@@ -131,6 +132,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(newEndpointCommand())
 	r.Register(newAPIInfoCommand())
 	r.Register(status.NewStatusHistoryCommand())
+	r.Register(newAuditLogCommand())
+	r.Register(newListPayloadsCommand())
 
 	// Error resolution and debugging commands.
 	r.Register(newRunCommand())
@@ -147,10 +150,12 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.RegisterDeprecated(common.NewSetConstraintsCommand(),
 		twoDotOhDeprecation("environment set-constraints or service set-constraints"))
 	r.Register(newExposeCommand())
+	r.Register(newExportBundleCommand())
 	r.Register(newSyncToolsCommand())
 	r.Register(newUnexposeCommand())
 	r.Register(newUpgradeJujuCommand())
 	r.Register(newUpgradeCharmCommand())
+	r.Register(newAttachCommand())
 
 	// Charm publishing commands.
 	r.Register(newPublishCommand())
@@ -176,6 +181,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.RegisterSuperAlias("remove-machine", "machine", "remove", twoDotOhDeprecation("machine remove"))
 	r.RegisterSuperAlias("destroy-machine", "machine", "remove", twoDotOhDeprecation("machine remove"))
 	r.RegisterSuperAlias("terminate-machine", "machine", "remove", twoDotOhDeprecation("machine remove"))
+	r.Register(machine.NewUpgradeSeriesCommand())
 
 	// Mangage environment
 	r.Register(environment.NewSuperCommand())
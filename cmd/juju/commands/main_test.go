@@ -201,6 +201,8 @@ var commandNames = []string{
 	"add-unit",
 	"api-endpoints",
 	"api-info",
+	"attach",
+	"audit-log",
 	"authorised-keys", // alias for authorized-keys
 	"authorized-keys",
 	"backups",
@@ -215,10 +217,12 @@ var commandNames = []string{
 	"destroy-relation",
 	"destroy-service",
 	"destroy-unit",
+	"diff-bundle",
 	"ensure-availability",
 	"env", // alias for switch
 	"environment",
 	"expose",
+	"export-bundle",
 	"generate-config", // alias for init
 	"get",
 	"get-constraints",
@@ -227,6 +231,7 @@ var commandNames = []string{
 	"help",
 	"help-tool",
 	"init",
+	"list-payloads",
 	"machine",
 	"publish",
 	"remove-machine",  // alias for destroy-machine
@@ -259,6 +264,7 @@ var commandNames = []string{
 	"unset-environment",
 	"upgrade-charm",
 	"upgrade-juju",
+	"upgrade-series",
 	"user",
 	"version",
 }
@@ -26,6 +26,23 @@ func FormatTime(t *time.Time, formatISO bool) string {
 	return t.Local().Format("02 Jan 2006 15:04:05Z07:00")
 }
 
+// ParseTimeOrDuration parses a time given either in RFC3339 format or as
+// a duration before now. It returns nil if value is empty.
+func ParseTimeOrDuration(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, errors.Errorf("expected RFC3339 time or duration, got %q", value)
+	}
+	t := now.Add(-d)
+	return &t, nil
+}
+
 // ConformYAML ensures all keys of any nested maps are strings.  This is
 // necessary because YAML unmarshals map[interface{}]interface{} in nested
 // maps, which cannot be serialized by bson. Also, handle []interface{}.
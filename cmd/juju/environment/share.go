@@ -9,7 +9,9 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -17,6 +19,12 @@ import (
 const shareEnvHelpDoc = `
 Share the current environment with another user.
 
+Users are given write access to the environment unless --access is
+specified. Users with read access may observe the environment, for
+example with "juju status" and "juju debug-log", but may not change
+it. Sharing the environment with a user who already has access to it
+changes their access level to the one given by --access.
+
 Examples:
  juju environment share joe
      Give local user "joe" access to the current environment
@@ -26,6 +34,9 @@ Examples:
 
  juju environment share sam --environment myenv
      Give local user "sam" access to the environment named "myenv"
+
+ juju environment share auditor --access read
+     Give local user "auditor" read-only access to the current environment
  `
 
 func newShareCommand() cmd.Command {
@@ -40,6 +51,9 @@ type shareCommand struct {
 
 	// Users to share the environment with.
 	Users []names.UserTag
+
+	// Access is the level of access to give the users.
+	Access params.EnvironmentAccess
 }
 
 // Info implements Command.Info.
@@ -52,11 +66,22 @@ func (c *shareCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *shareCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar((*string)(&c.Access), "access", "", `access level to give the users, "read" or "write"`)
+}
+
 func (c *shareCommand) Init(args []string) (err error) {
 	if len(args) == 0 {
 		return errors.New("no users specified")
 	}
 
+	switch c.Access {
+	case "", params.EnvironmentReadAccess, params.EnvironmentWriteAccess:
+	default:
+		return errors.Errorf("invalid access level %q, expected \"read\" or \"write\"", c.Access)
+	}
+
 	for _, arg := range args {
 		if !names.IsValidUser(arg) {
 			return errors.Errorf("invalid username: %q", arg)
@@ -77,7 +102,7 @@ func (c *shareCommand) getAPI() (ShareEnvironmentAPI, error) {
 // ShareEnvironmentAPI defines the API functions used by the environment share command.
 type ShareEnvironmentAPI interface {
 	Close() error
-	ShareEnvironment(...names.UserTag) error
+	ShareEnvironmentWithAccess(params.EnvironmentAccess, ...names.UserTag) error
 }
 
 func (c *shareCommand) Run(ctx *cmd.Context) error {
@@ -87,5 +112,5 @@ func (c *shareCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
-	return block.ProcessBlockedError(client.ShareEnvironment(c.Users...), block.BlockChange)
+	return block.ProcessBlockedError(client.ShareEnvironmentWithAccess(c.Access, c.Users...), block.BlockChange)
 }
@@ -40,6 +40,17 @@ func (s *shareSuite) TestInit(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid username: "not valid/0"`)
 }
 
+func (s *shareSuite) TestInitAccess(c *gc.C) {
+	wrappedCmd, shareCmd := environment.NewShareCommand(s.fake)
+	err := testing.InitCommand(wrappedCmd, []string{"--access", "read", "bob"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(shareCmd.Access, gc.Equals, params.EnvironmentReadAccess)
+
+	wrappedCmd, _ = environment.NewShareCommand(s.fake)
+	err = testing.InitCommand(wrappedCmd, []string{"--access", "admin", "bob"})
+	c.Assert(err, gc.ErrorMatches, `invalid access level "admin", expected "read" or "write"`)
+}
+
 func (s *shareSuite) TestPassesValues(c *gc.C) {
 	sam := names.NewUserTag("sam")
 	ralph := names.NewUserTag("ralph")
@@ -47,6 +58,14 @@ func (s *shareSuite) TestPassesValues(c *gc.C) {
 	_, err := s.run(c, "sam", "ralph")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(s.fake.addUsers, jc.DeepEquals, []names.UserTag{sam, ralph})
+	c.Assert(s.fake.access, gc.Equals, params.EnvironmentAccess(""))
+}
+
+func (s *shareSuite) TestPassesAccess(c *gc.C) {
+	_, err := s.run(c, "--access", "read", "sam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.addUsers, jc.DeepEquals, []names.UserTag{names.NewUserTag("sam")})
+	c.Assert(s.fake.access, gc.Equals, params.EnvironmentReadAccess)
 }
 
 func (s *shareSuite) TestBlockShare(c *gc.C) {
@@ -0,0 +1,130 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const (
+	upgradeSeriesPrepare  = "prepare"
+	upgradeSeriesComplete = "complete"
+)
+
+const upgradeSeriesDoc = `
+Upgrading the series of a machine is done in two steps.
+
+"prepare" stops the unit agents on the machine and runs the
+pre-series-upgrade hook of every unit. No units or containers can be
+added to the machine until the series upgrade is complete. The progress
+of the units is shown by "juju status".
+
+Once every unit is prepared, upgrade the machine's operating system (for
+example with do-release-upgrade) and then run "complete". The machine
+agent updates its service files for the new series, the machine and its
+units take on the new series, and every unit runs its post-series-upgrade
+hook.
+
+Examples:
+	# Prepare machine 3 for an upgrade to xenial
+	$ juju upgrade-series 3 prepare xenial
+
+	# Complete the series upgrade of machine 3 once the OS is upgraded
+	$ juju upgrade-series 3 complete
+`
+
+// NewUpgradeSeriesCommand returns a command that upgrades the series of
+// a machine.
+func NewUpgradeSeriesCommand() cmd.Command {
+	return envcmd.Wrap(&upgradeSeriesCommand{})
+}
+
+// upgradeSeriesCommand prepares and completes the upgrade of a machine's
+// series.
+type upgradeSeriesCommand struct {
+	envcmd.EnvCommandBase
+	api       UpgradeSeriesAPI
+	MachineId string
+	Action    string
+	Series    string
+}
+
+func (c *upgradeSeriesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "upgrade-series",
+		Args:    "<machine> prepare <series> | <machine> complete",
+		Purpose: "upgrade the series of a machine",
+		Doc:     upgradeSeriesDoc,
+	}
+}
+
+func (c *upgradeSeriesCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.New("no machine and action specified")
+	}
+	c.MachineId, c.Action, args = args[0], args[1], args[2:]
+	if !names.IsValidMachine(c.MachineId) {
+		return fmt.Errorf("invalid machine id %q", c.MachineId)
+	}
+	switch c.Action {
+	case upgradeSeriesPrepare:
+		if len(args) == 0 {
+			return errors.New("no series specified")
+		}
+		c.Series, args = args[0], args[1:]
+	case upgradeSeriesComplete:
+	default:
+		return fmt.Errorf("unknown action %q, expected %q or %q", c.Action, upgradeSeriesPrepare, upgradeSeriesComplete)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// UpgradeSeriesAPI defines the API methods used by the upgrade-series
+// command.
+type UpgradeSeriesAPI interface {
+	UpgradeSeriesPrepare(machine, series string) error
+	UpgradeSeriesComplete(machine string) error
+	Close() error
+}
+
+func (c *upgradeSeriesCommand) getUpgradeSeriesAPI() (UpgradeSeriesAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+func (c *upgradeSeriesCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getUpgradeSeriesAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	if c.Action == upgradeSeriesPrepare {
+		err = client.UpgradeSeriesPrepare(c.MachineId, c.Series)
+	} else {
+		err = client.UpgradeSeriesComplete(c.MachineId)
+	}
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	if c.Action == upgradeSeriesPrepare {
+		ctx.Infof("preparing machine %s for upgrade to %s", c.MachineId, c.Series)
+	} else {
+		ctx.Infof("completing series upgrade of machine %s", c.MachineId)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type UpgradeSeriesSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeUpgradeSeriesAPI
+}
+
+var _ = gc.Suite(&UpgradeSeriesSuite{})
+
+func (s *UpgradeSeriesSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeUpgradeSeriesAPI{}
+}
+
+func (s *UpgradeSeriesSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	upgradeSeries, _ := machine.NewUpgradeSeriesCommandForTest(s.fake)
+	return testing.RunCommand(c, upgradeSeries, args...)
+}
+
+func (s *UpgradeSeriesSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		machine     string
+		action      string
+		series      string
+		errorString string
+	}{{
+		errorString: "no machine and action specified",
+	}, {
+		args:        []string{"1"},
+		errorString: "no machine and action specified",
+	}, {
+		args:        []string{"lxc", "complete"},
+		errorString: `invalid machine id "lxc"`,
+	}, {
+		args:        []string{"1", "upgrade"},
+		errorString: `unknown action "upgrade", expected "prepare" or "complete"`,
+	}, {
+		args:        []string{"1", "prepare"},
+		errorString: "no series specified",
+	}, {
+		args:        []string{"1", "complete", "xenial"},
+		errorString: `unrecognized args: \["xenial"\]`,
+	}, {
+		args:    []string{"1", "prepare", "xenial"},
+		machine: "1",
+		action:  "prepare",
+		series:  "xenial",
+	}, {
+		args:    []string{"1/lxc/2", "complete"},
+		machine: "1/lxc/2",
+		action:  "complete",
+	}} {
+		c.Logf("test %d", i)
+		wrappedCommand, upgradeCmd := machine.NewUpgradeSeriesCommandForTest(s.fake)
+		err := testing.InitCommand(wrappedCommand, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(upgradeCmd.MachineId, gc.Equals, test.machine)
+			c.Check(upgradeCmd.Action, gc.Equals, test.action)
+			c.Check(upgradeCmd.Series, gc.Equals, test.series)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *UpgradeSeriesSuite) TestPrepare(c *gc.C) {
+	_, err := s.run(c, "1", "prepare", "xenial")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"prepare 1 xenial"})
+}
+
+func (s *UpgradeSeriesSuite) TestComplete(c *gc.C) {
+	_, err := s.run(c, "1", "complete")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"complete 1"})
+}
+
+func (s *UpgradeSeriesSuite) TestBlockedError(c *gc.C) {
+	s.fake.err = common.OperationBlockedError("TestBlockedError")
+	_, err := s.run(c, "1", "prepare", "xenial")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(c.GetTestLog(), gc.Matches, "(?s).*TestBlockedError.*")
+}
+
+type fakeUpgradeSeriesAPI struct {
+	calls []string
+	err   error
+}
+
+func (f *fakeUpgradeSeriesAPI) Close() error {
+	return nil
+}
+
+func (f *fakeUpgradeSeriesAPI) UpgradeSeriesPrepare(machine, series string) error {
+	f.calls = append(f.calls, "prepare "+machine+" "+series)
+	return f.err
+}
+
+func (f *fakeUpgradeSeriesAPI) UpgradeSeriesComplete(machine string) error {
+	f.calls = append(f.calls, "complete "+machine)
+	return f.err
+}
@@ -25,6 +25,7 @@ type errorStatus struct {
 
 type environmentStatus struct {
 	AvailableVersion string `json:"upgrade-available,omitempty" yaml:"upgrade-available,omitempty"`
+	BackupsError     string `json:"backups-error,omitempty" yaml:"backups-error,omitempty"`
 }
 
 type machineStatus struct {
@@ -41,6 +42,12 @@ type machineStatus struct {
 	Containers     map[string]machineStatus `json:"containers,omitempty" yaml:"containers,omitempty"`
 	Hardware       string                   `json:"hardware,omitempty" yaml:"hardware,omitempty"`
 	HAStatus       string                   `json:"state-server-member-status,omitempty" yaml:"state-server-member-status,omitempty"`
+	UpgradeSeries  *upgradeSeriesStatus     `json:"upgrade-series,omitempty" yaml:"upgrade-series,omitempty"`
+}
+
+type upgradeSeriesStatus struct {
+	ToSeries string                     `json:"to-series" yaml:"to-series"`
+	Status   params.UpgradeSeriesStatus `json:"status" yaml:"status"`
 }
 
 // A goyaml bug means we can't declare these types
@@ -63,16 +70,17 @@ func (s machineStatus) MarshalYAML() (interface{}, error) {
 }
 
 type serviceStatus struct {
-	Err           error                 `json:"-" yaml:",omitempty"`
-	Charm         string                `json:"charm" yaml:"charm"`
-	CanUpgradeTo  string                `json:"can-upgrade-to,omitempty" yaml:"can-upgrade-to,omitempty"`
-	Exposed       bool                  `json:"exposed" yaml:"exposed"`
-	Life          string                `json:"life,omitempty" yaml:"life,omitempty"`
-	StatusInfo    statusInfoContents    `json:"service-status,omitempty" yaml:"service-status"`
-	Relations     map[string][]string   `json:"relations,omitempty" yaml:"relations,omitempty"`
-	Networks      map[string][]string   `json:"networks,omitempty" yaml:"networks,omitempty"`
-	SubordinateTo []string              `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
-	Units         map[string]unitStatus `json:"units,omitempty" yaml:"units,omitempty"`
+	Err             error                 `json:"-" yaml:",omitempty"`
+	Charm           string                `json:"charm" yaml:"charm"`
+	WorkloadVersion string                `json:"workload-version,omitempty" yaml:"workload-version,omitempty"`
+	CanUpgradeTo    string                `json:"can-upgrade-to,omitempty" yaml:"can-upgrade-to,omitempty"`
+	Exposed         bool                  `json:"exposed" yaml:"exposed"`
+	Life            string                `json:"life,omitempty" yaml:"life,omitempty"`
+	StatusInfo      statusInfoContents    `json:"service-status,omitempty" yaml:"service-status"`
+	Relations       map[string][]string   `json:"relations,omitempty" yaml:"relations,omitempty"`
+	Networks        map[string][]string   `json:"networks,omitempty" yaml:"networks,omitempty"`
+	SubordinateTo   []string              `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
+	Units           map[string]unitStatus `json:"units,omitempty" yaml:"units,omitempty"`
 }
 
 type serviceStatusNoMarshal serviceStatus
@@ -111,11 +119,12 @@ type unitStatus struct {
 	AgentVersion   string        `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
 	Life           string        `json:"life,omitempty" yaml:"life,omitempty"`
 
-	Charm         string                `json:"upgrading-from,omitempty" yaml:"upgrading-from,omitempty"`
-	Machine       string                `json:"machine,omitempty" yaml:"machine,omitempty"`
-	OpenedPorts   []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
-	PublicAddress string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
-	Subordinates  map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
+	Charm           string                `json:"upgrading-from,omitempty" yaml:"upgrading-from,omitempty"`
+	WorkloadVersion string                `json:"workload-version,omitempty" yaml:"workload-version,omitempty"`
+	Machine         string                `json:"machine,omitempty" yaml:"machine,omitempty"`
+	OpenedPorts     []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
+	PublicAddress   string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
+	Subordinates    map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
 }
 
 type statusInfoContents struct {
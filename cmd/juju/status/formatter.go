@@ -40,9 +40,10 @@ func (sf *statusFormatter) format() formattedStatus {
 		Machines:    make(map[string]machineStatus),
 		Services:    make(map[string]serviceStatus),
 	}
-	if sf.status.AvailableVersion != "" {
+	if sf.status.AvailableVersion != "" || sf.status.BackupsError != "" {
 		out.EnvironmentStatus = &environmentStatus{
 			AvailableVersion: sf.status.AvailableVersion,
+			BackupsError:     sf.status.BackupsError,
 		}
 	}
 
@@ -110,21 +111,29 @@ func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineSt
 			break
 		}
 	}
+
+	if machine.UpgradeSeriesStatus != params.UpgradeSeriesNotStarted {
+		out.UpgradeSeries = &upgradeSeriesStatus{
+			ToSeries: machine.UpgradeSeriesTarget,
+			Status:   machine.UpgradeSeriesStatus,
+		}
+	}
 	return out
 }
 
 func (sf *statusFormatter) formatService(name string, service params.ServiceStatus) serviceStatus {
 	out := serviceStatus{
-		Err:           service.Err,
-		Charm:         service.Charm,
-		Exposed:       service.Exposed,
-		Life:          service.Life,
-		Relations:     service.Relations,
-		Networks:      make(map[string][]string),
-		CanUpgradeTo:  service.CanUpgradeTo,
-		SubordinateTo: service.SubordinateTo,
-		Units:         make(map[string]unitStatus),
-		StatusInfo:    sf.getServiceStatusInfo(service),
+		Err:             service.Err,
+		Charm:           service.Charm,
+		WorkloadVersion: service.WorkloadVersion,
+		Exposed:         service.Exposed,
+		Life:            service.Life,
+		Relations:       service.Relations,
+		Networks:        make(map[string][]string),
+		CanUpgradeTo:    service.CanUpgradeTo,
+		SubordinateTo:   service.SubordinateTo,
+		Units:           make(map[string]unitStatus),
+		StatusInfo:      sf.getServiceStatusInfo(service),
 	}
 	if len(service.Networks.Enabled) > 0 {
 		out.Networks["enabled"] = service.Networks.Enabled
@@ -174,6 +183,7 @@ func (sf *statusFormatter) formatUnit(info unitFormatInfo) unitStatus {
 		OpenedPorts:        info.unit.OpenedPorts,
 		PublicAddress:      info.unit.PublicAddress,
 		Charm:              info.unit.Charm,
+		WorkloadVersion:    info.unit.WorkloadVersion,
 		Subordinates:       make(map[string]unitStatus),
 	}
 
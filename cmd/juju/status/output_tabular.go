@@ -41,19 +41,23 @@ func FormatTabular(value interface{}) ([]byte, error) {
 			p("UPGRADE-AVAILABLE")
 			p(envStatus.AvailableVersion)
 		}
+		if envStatus.BackupsError != "" {
+			p("BACKUPS-ERROR")
+			p(envStatus.BackupsError)
+		}
 		p()
 		tw.Flush()
 	}
 
 	units := make(map[string]unitStatus)
 	p("[Services]")
-	p("NAME\tSTATUS\tEXPOSED\tCHARM")
+	p("NAME\tSTATUS\tEXPOSED\tVERSION\tCHARM")
 	for _, svcName := range common.SortStringsNaturally(stringKeysFromMap(fs.Services)) {
 		svc := fs.Services[svcName]
 		for un, u := range svc.Units {
 			units[un] = u
 		}
-		p(svcName, svc.StatusInfo.Current, fmt.Sprintf("%t", svc.Exposed), svc.Charm)
+		p(svcName, svc.StatusInfo.Current, fmt.Sprintf("%t", svc.Exposed), svc.WorkloadVersion, svc.Charm)
 	}
 	tw.Flush()
 
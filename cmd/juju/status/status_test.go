@@ -2312,6 +2312,116 @@ var statusTests = []testCase{
 			},
 		},
 	),
+	test( // 19
+		"scheduled backup failed",
+		setBackupStatus{state.StatusError, "cannot create backup: disk full"},
+		expect{
+			"backup failure should be shown in environment-status",
+			M{
+				"environment": "dummyenv",
+				"environment-status": M{
+					"backups-error": "cannot create backup: disk full",
+				},
+				"machines": M{},
+				"services": M{},
+			},
+		},
+	),
+	test( // 20
+		"scheduled backup succeeded",
+		setBackupStatus{state.StatusIdle, "last backup made at 2015-10-18 12:00:00"},
+		expect{
+			"successful backups are not shown",
+			M{
+				"environment": "dummyenv",
+				"machines":    M{},
+				"services":    M{},
+			},
+		},
+	),
+	test( // 21
+		"workload versions",
+		addMachine{machineId: "0", job: state.JobManageEnviron},
+		setAddresses{"0", network.NewAddresses("dummyenv-0.dns")},
+		startAliveMachine{"0"},
+		setMachineStatus{"0", state.StatusStarted, ""},
+
+		addMachine{machineId: "1", job: state.JobHostUnits},
+		setAddresses{"1", network.NewAddresses("dummyenv-1.dns")},
+		startAliveMachine{"1"},
+		setMachineStatus{"1", state.StatusStarted, ""},
+
+		addMachine{machineId: "2", job: state.JobHostUnits},
+		setAddresses{"2", network.NewAddresses("dummyenv-2.dns")},
+		startAliveMachine{"2"},
+		setMachineStatus{"2", state.StatusStarted, ""},
+
+		addCharm{"mysql"},
+		addService{name: "mysql", charm: "mysql"},
+		addAliveUnit{"mysql", "1"},
+		addAliveUnit{"mysql", "2"},
+		setAgentStatus{"mysql/0", state.StatusIdle, "", nil},
+		setUnitStatus{"mysql/0", state.StatusActive, "", nil},
+		setAgentStatus{"mysql/1", state.StatusIdle, "", nil},
+		setUnitStatus{"mysql/1", state.StatusActive, "", nil},
+
+		setUnitWorkloadVersion{"mysql/0", "5.6"},
+		setUnitWorkloadVersion{"mysql/1", "5.7"},
+		claimLeadership{"mysql", "mysql/1"},
+
+		expect{
+			"the service shows the leader's workload version",
+			M{
+				"environment": "dummyenv",
+				"machines": M{
+					"0": machine0,
+					"1": machine1,
+					"2": machine2,
+				},
+				"services": M{
+					"mysql": M{
+						"charm":            "cs:quantal/mysql-1",
+						"workload-version": "5.7",
+						"exposed":          false,
+						"service-status": M{
+							"current": "active",
+							"since":   "01 Apr 15 01:23+10:00",
+						},
+						"units": M{
+							"mysql/0": M{
+								"machine":          "1",
+								"agent-state":      "started",
+								"workload-version": "5.6",
+								"workload-status": M{
+									"current": "active",
+									"since":   "01 Apr 15 01:23+10:00",
+								},
+								"agent-status": M{
+									"current": "idle",
+									"since":   "01 Apr 15 01:23+10:00",
+								},
+								"public-address": "dummyenv-1.dns",
+							},
+							"mysql/1": M{
+								"machine":          "2",
+								"agent-state":      "started",
+								"workload-version": "5.7",
+								"workload-status": M{
+									"current": "active",
+									"since":   "01 Apr 15 01:23+10:00",
+								},
+								"agent-status": M{
+									"current": "idle",
+									"since":   "01 Apr 15 01:23+10:00",
+								},
+								"public-address": "dummyenv-2.dns",
+							},
+						},
+					},
+				},
+			},
+		},
+	),
 }
 
 // TODO(dfc) test failing components by destructively mutating the state under the hood
@@ -2448,6 +2558,28 @@ func (st setUnitTools) step(c *gc.C, ctx *context) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+type setUnitWorkloadVersion struct {
+	unitName string
+	version  string
+}
+
+func (wv setUnitWorkloadVersion) step(c *gc.C, ctx *context) {
+	u, err := ctx.st.Unit(wv.unitName)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.SetWorkloadVersion(wv.version)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type claimLeadership struct {
+	serviceName string
+	unitName    string
+}
+
+func (cl claimLeadership) step(c *gc.C, ctx *context) {
+	err := ctx.st.LeadershipClaimer().ClaimLeadership(cl.serviceName, cl.unitName, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type addCharm struct {
 	name string
 }
@@ -2823,6 +2955,16 @@ func (ua setToolsUpgradeAvailable) step(c *gc.C, ctx *context) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+type setBackupStatus struct {
+	status  state.Status
+	message string
+}
+
+func (bs setBackupStatus) step(c *gc.C, ctx *context) {
+	err := ctx.st.SetBackupStatus(bs.status, bs.message, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *StatusSuite) TestStatusAllFormats(c *gc.C) {
 	for i, t := range statusTests {
 		c.Logf("test %d: %s", i, t.summary)
@@ -3172,6 +3314,8 @@ func (s *StatusSuite) prepareTabularData(c *gc.C) *context {
 			state.StatusMaintenance,
 			"installing all the things", nil},
 		setUnitTools{"mysql/0", version.MustParseBinary("1.2.3-trusty-ppc")},
+		setUnitWorkloadVersion{"mysql/0", "5.7"},
+		claimLeadership{"mysql", "mysql/0"},
 		addService{name: "logging", charm: "logging"},
 		setServiceExposed{"logging", true},
 		relateServices{"wordpress", "mysql"},
@@ -3206,10 +3350,10 @@ UPGRADE-AVAILABLE
 %s
 
 [Services] 
-NAME       STATUS      EXPOSED CHARM                  
-logging                true    cs:quantal/logging-1   
-mysql      maintenance true    cs:quantal/mysql-1     
-wordpress  active      true    cs:quantal/wordpress-3 
+NAME       STATUS      EXPOSED VERSION CHARM                  
+logging                true            cs:quantal/logging-1   
+mysql      maintenance true    5.7     cs:quantal/mysql-1     
+wordpress  active      true            cs:quantal/wordpress-3 
 
 [Units]     
 ID          WORKLOAD-STATE AGENT-STATE VERSION MACHINE PORTS PUBLIC-ADDRESS MESSAGE                        
@@ -3272,8 +3416,8 @@ func (s *StatusSuite) TestFormatTabularHookActionName(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(string(out), gc.Equals, `
 [Services] 
-NAME       STATUS EXPOSED CHARM 
-foo               false         
+NAME       STATUS EXPOSED VERSION CHARM 
+foo               false                 
 
 [Units] 
 ID      WORKLOAD-STATE AGENT-STATE VERSION MACHINE PORTS PUBLIC-ADDRESS MESSAGE                           
@@ -3750,3 +3894,40 @@ func (s *StatusSuite) TestFormatProvisioningError(c *gc.C) {
 		Services: map[string]serviceStatus{},
 	})
 }
+
+func (s *StatusSuite) TestFormatUpgradeSeries(c *gc.C) {
+	status := &params.FullStatus{
+		Machines: map[string]params.MachineStatus{
+			"1": params.MachineStatus{
+				Agent: params.AgentStatus{
+					Status: "started",
+				},
+				InstanceId:          "controller-1",
+				Series:              "trusty",
+				Id:                  "1",
+				Jobs:                []multiwatcher.MachineJob{"JobHostUnits"},
+				UpgradeSeriesStatus: params.UpgradeSeriesPrepareCompleted,
+				UpgradeSeriesTarget: "xenial",
+			},
+		},
+	}
+	formatter := newStatusFormatter(status, 0, true)
+	formatted := formatter.format()
+
+	c.Check(formatted, jc.DeepEquals, formattedStatus{
+		Machines: map[string]machineStatus{
+			"1": machineStatus{
+				AgentState: "started",
+				InstanceId: "controller-1",
+				Series:     "trusty",
+				Id:         "1",
+				Containers: map[string]machineStatus{},
+				UpgradeSeries: &upgradeSeriesStatus{
+					ToSeries: "xenial",
+					Status:   params.UpgradeSeriesPrepareCompleted,
+				},
+			},
+		},
+		Services: map[string]serviceStatus{},
+	})
+}
@@ -114,3 +114,19 @@ func NewListBlocksCommand(api listBlocksAPI, apierr error) cmd.Command {
 		apierr: apierr,
 	})
 }
+
+// NewExportEnvironmentCommand returns an ExportEnvironmentCommand with the
+// systemmanager endpoint mocked out.
+func NewExportEnvironmentCommand(api exportEnvironmentAPI) cmd.Command {
+	return envcmd.WrapSystem(&exportEnvironmentCommand{
+		api: api,
+	})
+}
+
+// NewImportEnvironmentCommand returns an ImportEnvironmentCommand with the
+// systemmanager endpoint mocked out.
+func NewImportEnvironmentCommand(api importEnvironmentAPI) cmd.Command {
+	return envcmd.WrapSystem(&importEnvironmentCommand{
+		api: api,
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package system
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func newExportEnvironmentCommand() cmd.Command {
+	return envcmd.WrapSystem(&exportEnvironmentCommand{})
+}
+
+// exportEnvironmentCommand writes the description of an environment in
+// the system, from which the environment can be recreated in another
+// system by import-environment.
+type exportEnvironmentCommand struct {
+	envcmd.SysCommandBase
+	api exportEnvironmentAPI
+
+	envName string
+	outFile string
+}
+
+// exportEnvironmentAPI defines the methods on the system manager API
+// end point that the export-environment command calls.
+type exportEnvironmentAPI interface {
+	Close() error
+	AllEnvironments() ([]base.UserEnvironment, error)
+	ExportEnvironment(uuid string) ([]byte, error)
+}
+
+var exportEnvironmentDoc = `
+Write a description of an environment in the system, and everything in it, to
+a file or standard output.
+
+The description is a versioned YAML document covering the environment's
+machines, services, charms (including their archives), units, relations,
+settings, constraints, storage, annotations, users and status. It can be read
+by "juju system import-environment" to recreate the environment in another
+system. Every entity in the environment must be alive to be exported.
+
+The environment may be given by name or UUID. Only system administrators can
+export environments.
+
+Examples:
+
+    juju system export-environment prod -o prod.yaml
+
+See Also:
+    juju help system import-environment
+`
+
+// Info implements Command.Info
+func (c *exportEnvironmentCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "export-environment",
+		Args:    "<environment name or UUID>",
+		Purpose: "write a description of an environment in the system",
+		Doc:     exportEnvironmentDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *exportEnvironmentCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.outFile, "o", "", "write the description to this file instead of standard output")
+	f.StringVar(&c.outFile, "output", "", "")
+}
+
+// Init implements Command.Init.
+func (c *exportEnvironmentCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("environment name or UUID is required")
+	}
+	c.envName, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+func (c *exportEnvironmentCommand) getAPI() (exportEnvironmentAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewSystemManagerAPIClient()
+}
+
+// Run implements Command.Run
+func (c *exportEnvironmentCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	uuid, err := c.environmentUUID(client)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data, err := client.ExportEnvironment(uuid)
+	if err != nil {
+		return errors.Annotatef(err, "cannot export environment %q", c.envName)
+	}
+	if c.outFile == "" {
+		_, err := ctx.Stdout.Write(data)
+		return errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(ctx.AbsPath(c.outFile), data, 0600); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("environment %q exported to %s", c.envName, c.outFile)
+	return nil
+}
+
+// environmentUUID returns the UUID of the environment named on the
+// command line.
+func (c *exportEnvironmentCommand) environmentUUID(client exportEnvironmentAPI) (string, error) {
+	envs, err := client.AllEnvironments()
+	if err != nil {
+		return "", errors.Annotate(err, "cannot list environments")
+	}
+	var found []base.UserEnvironment
+	for _, env := range envs {
+		if env.UUID == c.envName {
+			return env.UUID, nil
+		}
+		if env.Name == c.envName {
+			found = append(found, env)
+		}
+	}
+	switch len(found) {
+	case 0:
+		if utils.IsValidUUIDString(c.envName) {
+			return c.envName, nil
+		}
+		return "", errors.NotFoundf("environment %q", c.envName)
+	case 1:
+		return found[0].UUID, nil
+	}
+	return "", errors.Errorf("more than one environment is named %q, use its UUID", c.envName)
+}
@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package system_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/system"
+	"github.com/juju/juju/testing"
+)
+
+type exportEnvironmentSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeExportEnvironmentAPI
+}
+
+var _ = gc.Suite(&exportEnvironmentSuite{})
+
+func (s *exportEnvironmentSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+
+	err := envcmd.WriteCurrentSystem("fake")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.api = &fakeExportEnvironmentAPI{
+		envs: []base.UserEnvironment{{
+			Name:  "prod",
+			UUID:  "e2d5a41c-5c4c-4b5f-8ae8-4f5a8d7c9a10",
+			Owner: "admin@local",
+		}, {
+			Name:  "test",
+			UUID:  "9b0ea60e-3a8a-4e0b-8d6f-2c4f1c0e7b21",
+			Owner: "admin@local",
+		}, {
+			Name:  "test",
+			UUID:  "1c3c1f5e-8b44-4d6e-9a52-7e0f7f6a3d08",
+			Owner: "bob@local",
+		}},
+	}
+}
+
+func (s *exportEnvironmentSuite) newCommand() cmd.Command {
+	return system.NewExportEnvironmentCommand(s.api)
+}
+
+func (s *exportEnvironmentSuite) TestExportByName(c *gc.C) {
+	ctx, err := testing.RunCommand(c, s.newCommand(), "prod")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.exported, gc.Equals, "e2d5a41c-5c4c-4b5f-8ae8-4f5a8d7c9a10")
+	c.Assert(testing.Stdout(ctx), gc.Equals, "<description>")
+}
+
+func (s *exportEnvironmentSuite) TestExportByUUID(c *gc.C) {
+	_, err := testing.RunCommand(c, s.newCommand(), "9b0ea60e-3a8a-4e0b-8d6f-2c4f1c0e7b21")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.exported, gc.Equals, "9b0ea60e-3a8a-4e0b-8d6f-2c4f1c0e7b21")
+}
+
+func (s *exportEnvironmentSuite) TestExportToFile(c *gc.C) {
+	filename := filepath.Join(c.MkDir(), "prod.yaml")
+	ctx, err := testing.RunCommand(c, s.newCommand(), "prod", "-o", filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	data, err := ioutil.ReadFile(filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "<description>")
+}
+
+func (s *exportEnvironmentSuite) TestExportAmbiguousName(c *gc.C) {
+	_, err := testing.RunCommand(c, s.newCommand(), "test")
+	c.Assert(err, gc.ErrorMatches, `more than one environment is named "test", use its UUID`)
+	c.Assert(s.api.exported, gc.Equals, "")
+}
+
+func (s *exportEnvironmentSuite) TestExportUnknownEnvironment(c *gc.C) {
+	_, err := testing.RunCommand(c, s.newCommand(), "staging")
+	c.Assert(err, gc.ErrorMatches, `environment "staging" not found`)
+}
+
+func (s *exportEnvironmentSuite) TestExportError(c *gc.C) {
+	s.api.err = common.ErrPerm
+	_, err := testing.RunCommand(c, s.newCommand(), "prod")
+	c.Assert(err, gc.ErrorMatches, `cannot export environment "prod": permission denied`)
+}
+
+func (s *exportEnvironmentSuite) TestMissingArg(c *gc.C) {
+	_, err := testing.RunCommand(c, s.newCommand())
+	c.Assert(err, gc.ErrorMatches, "environment name or UUID is required")
+}
+
+func (s *exportEnvironmentSuite) TestUnrecognizedArg(c *gc.C) {
+	_, err := testing.RunCommand(c, s.newCommand(), "prod", "whoops")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["whoops"\]`)
+}
+
+type fakeExportEnvironmentAPI struct {
+	envs     []base.UserEnvironment
+	exported string
+	err      error
+}
+
+func (f *fakeExportEnvironmentAPI) Close() error {
+	return nil
+}
+
+func (f *fakeExportEnvironmentAPI) AllEnvironments() ([]base.UserEnvironment, error) {
+	return f.envs, nil
+}
+
+func (f *fakeExportEnvironmentAPI) ExportEnvironment(uuid string) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.exported = uuid
+	return []byte("<description>"), nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package system
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+func newImportEnvironmentCommand() cmd.Command {
+	return envcmd.WrapSystem(&importEnvironmentCommand{})
+}
+
+// importEnvironmentCommand recreates an environment in the system from
+// the description written by export-environment.
+type importEnvironmentCommand struct {
+	envcmd.SysCommandBase
+	api importEnvironmentAPI
+
+	filename string
+}
+
+// importEnvironmentAPI defines the methods on the system manager API
+// end point that the import-environment command calls.
+type importEnvironmentAPI interface {
+	Close() error
+	ImportEnvironment(bytes []byte) (params.Environment, error)
+}
+
+var importEnvironmentDoc = `
+Recreate an environment in the system from the description written by
+"juju system export-environment", in YAML or JSON.
+
+The environment keeps its name, UUID, owner and contents. Machines keep their
+instance ids, nonces and agent passwords, so the agents running on them can be
+pointed at this system without redeploying anything. The environment's owner
+and any other local users must already exist in the system, and the
+environment must not exist already. Only system administrators can import
+environments.
+
+Examples:
+
+    juju system import-environment prod.yaml
+
+See Also:
+    juju help system export-environment
+`
+
+// Info implements Command.Info
+func (c *importEnvironmentCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "import-environment",
+		Args:    "<filename>",
+		Purpose: "recreate an exported environment in the system",
+		Doc:     importEnvironmentDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *importEnvironmentCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("filename is required")
+	}
+	c.filename, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+func (c *importEnvironmentCommand) getAPI() (importEnvironmentAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewSystemManagerAPIClient()
+}
+
+// Run implements Command.Run
+func (c *importEnvironmentCommand) Run(ctx *cmd.Context) error {
+	data, err := ioutil.ReadFile(ctx.AbsPath(c.filename))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	env, err := client.ImportEnvironment(data)
+	if err != nil {
+		return errors.Annotate(err, "cannot import environment")
+	}
+	owner, err := names.ParseUserTag(env.OwnerTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("imported environment %q (%s) owned by %s", env.Name, env.UUID, owner.Canonical())
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package system_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/system"
+	"github.com/juju/juju/testing"
+)
+
+type importEnvironmentSuite struct {
+	testing.FakeJujuHomeSuite
+	api      *fakeImportEnvironmentAPI
+	filename string
+}
+
+var _ = gc.Suite(&importEnvironmentSuite{})
+
+func (s *importEnvironmentSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+
+	err := envcmd.WriteCurrentSystem("fake")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.api = &fakeImportEnvironmentAPI{}
+	s.filename = filepath.Join(c.MkDir(), "prod.yaml")
+	err = ioutil.WriteFile(s.filename, []byte("<description>"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *importEnvironmentSuite) newCommand() cmd.Command {
+	return system.NewImportEnvironmentCommand(s.api)
+}
+
+func (s *importEnvironmentSuite) TestImport(c *gc.C) {
+	ctx, err := testing.RunCommand(c, s.newCommand(), s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.imported, gc.Equals, "<description>")
+	c.Assert(testing.Stderr(ctx), gc.Equals,
+		"imported environment \"prod\" (e2d5a41c-5c4c-4b5f-8ae8-4f5a8d7c9a10) owned by admin@local\n")
+}
+
+func (s *importEnvironmentSuite) TestImportError(c *gc.C) {
+	s.api.err = common.ErrPerm
+	_, err := testing.RunCommand(c, s.newCommand(), s.filename)
+	c.Assert(err, gc.ErrorMatches, "cannot import environment: permission denied")
+}
+
+func (s *importEnvironmentSuite) TestMissingFile(c *gc.C) {
+	_, err := testing.RunCommand(c, s.newCommand(), filepath.Join(c.MkDir(), "missing.yaml"))
+	c.Assert(err, gc.ErrorMatches, "open .*missing.yaml: no such file or directory")
+	c.Assert(s.api.imported, gc.Equals, "")
+}
+
+func (s *importEnvironmentSuite) TestMissingArg(c *gc.C) {
+	_, err := testing.RunCommand(c, s.newCommand())
+	c.Assert(err, gc.ErrorMatches, "filename is required")
+}
+
+func (s *importEnvironmentSuite) TestUnrecognizedArg(c *gc.C) {
+	_, err := testing.RunCommand(c, s.newCommand(), s.filename, "whoops")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["whoops"\]`)
+}
+
+type fakeImportEnvironmentAPI struct {
+	imported string
+	err      error
+}
+
+func (f *fakeImportEnvironmentAPI) Close() error {
+	return nil
+}
+
+func (f *fakeImportEnvironmentAPI) ImportEnvironment(bytes []byte) (params.Environment, error) {
+	if f.err != nil {
+		return params.Environment{}, f.err
+	}
+	f.imported = string(bytes)
+	return params.Environment{
+		Name:     "prod",
+		UUID:     "e2d5a41c-5c4c-4b5f-8ae8-4f5a8d7c9a10",
+		OwnerTag: "user-admin@local",
+	}, nil
+}
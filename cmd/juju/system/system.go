@@ -44,6 +44,8 @@ func NewSuperCommand() cmd.Command {
 	systemCmd.Register(newListBlocksCommand())
 	systemCmd.Register(newEnvironmentsCommand())
 	systemCmd.Register(newCreateEnvironmentCommand())
+	systemCmd.Register(newExportEnvironmentCommand())
+	systemCmd.Register(newImportEnvironmentCommand())
 	systemCmd.Register(newRemoveBlocksCommand())
 	systemCmd.Register(newUseEnvironmentCommand())
 
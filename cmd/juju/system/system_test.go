@@ -24,7 +24,9 @@ var expectedCommmandNames = []string{
 	"create-environment",
 	"destroy",
 	"environments",
+	"export-environment",
 	"help",
+	"import-environment",
 	"kill",
 	"list",
 	"list-blocks",
@@ -61,15 +61,20 @@ import (
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
 	"github.com/juju/juju/state/multiwatcher"
 	statestorage "github.com/juju/juju/state/storage"
 	"github.com/juju/juju/storage/looputil"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/actionpruner"
+	"github.com/juju/juju/worker/actionscheduler"
 	"github.com/juju/juju/worker/addresser"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/apicaller"
+	"github.com/juju/juju/worker/auditpruner"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/backupscheduler"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/charmrevisionworker"
 	"github.com/juju/juju/worker/cleaner"
@@ -82,12 +87,14 @@ import (
 	"github.com/juju/juju/worker/imagemetadataworker"
 	"github.com/juju/juju/worker/instancepoller"
 	"github.com/juju/juju/worker/localstorage"
+	"github.com/juju/juju/worker/logforwarder"
 	workerlogger "github.com/juju/juju/worker/logger"
 	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/machiner"
 	"github.com/juju/juju/worker/metricworker"
 	"github.com/juju/juju/worker/minunitsworker"
 	"github.com/juju/juju/worker/networker"
+	"github.com/juju/juju/worker/oplogcapture"
 	"github.com/juju/juju/worker/peergrouper"
 	"github.com/juju/juju/worker/provisioner"
 	"github.com/juju/juju/worker/proxyupdater"
@@ -1129,6 +1136,10 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 				return txnpruner.New(st, time.Hour*2), nil
 			})
 
+			a.startWorkerAfterUpgrade(singularRunner, "auditpruner", func() (worker.Worker, error) {
+				return auditpruner.New(st, auditpruner.NewAuditPruneParams()), nil
+			})
+
 		case state.JobManageStateDeprecated:
 			// Legacy environments may set this, but we ignore it.
 		default:
@@ -1212,6 +1223,27 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("minunitsworker", func() (worker.Worker, error) {
 		return minunitsworker.NewMinUnitsWorker(st), nil
 	})
+	singularRunner.StartWorker("actionpruner", func() (worker.Worker, error) {
+		return actionpruner.New(st, actionpruner.DefaultPruneInterval), nil
+	})
+	singularRunner.StartWorker("actionscheduler", func() (worker.Worker, error) {
+		return actionscheduler.New(st, actionscheduler.DefaultCheckInterval), nil
+	})
+	singularRunner.StartWorker("backupscheduler", func() (worker.Worker, error) {
+		paths := backups.Paths{
+			DataDir: agentConfig.DataDir(),
+			LogsDir: agentConfig.LogDir(),
+		}
+		return backupscheduler.New(st, paths, a.machineId, backupscheduler.DefaultCheckInterval), nil
+	})
+	singularRunner.StartWorker("oplogcapture", func() (worker.Worker, error) {
+		return oplogcapture.New(st, agentConfig.DataDir(), oplogcapture.DefaultCheckInterval), nil
+	})
+	if feature.IsDbLogEnabled() {
+		singularRunner.StartWorker("logforwarder", func() (worker.Worker, error) {
+			return logforwarder.New(st), nil
+		})
+	}
 
 	// Start workers that use an API connection.
 	singularRunner.StartWorker("environ-provisioner", func() (worker.Worker, error) {
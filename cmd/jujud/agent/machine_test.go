@@ -266,6 +266,10 @@ type MachineSuite struct {
 var perEnvSingularWorkers = []string{
 	"cleaner",
 	"minunitsworker",
+	"actionpruner",
+	"actionscheduler",
+	"backupscheduler",
+	"oplogcapture",
 	"addresserworker",
 	"environ-provisioner",
 	"charm-revision-updater",
@@ -818,6 +822,16 @@ func (s *MachineSuite) TestManageEnvironDoesntRunDbLogPrunerByDefault(c *gc.C) {
 	c.Assert(started.Contains("dblogpruner"), jc.IsFalse)
 }
 
+func (s *MachineSuite) TestManageEnvironRunsAuditPruner(c *gc.C) {
+	m, _, _ := s.primeAgent(c, state.JobManageEnviron)
+	a := s.newAgent(c, m)
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+
+	runner := s.singularRecord.nextRunner(c)
+	runner.waitForWorker(c, "auditpruner")
+}
+
 func (s *MachineSuite) TestManageEnvironRunsStatusHistoryPruner(c *gc.C) {
 	m, _, _ := s.primeAgent(c, state.JobManageEnviron)
 	a := s.newAgent(c, m)
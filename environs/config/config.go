@@ -6,9 +6,11 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -18,6 +20,8 @@ import (
 	"github.com/juju/schema"
 	"github.com/juju/utils"
 	"github.com/juju/utils/proxy"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/ssh"
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/charmrepo.v1"
 	"gopkg.in/juju/environschema.v1"
@@ -69,6 +73,18 @@ const (
 	// refresh addresses from the provider each time.
 	DefaultBootstrapSSHAddressesDelay int = 10
 
+	// DefaultMaxActionResultsAge is the default length of time for
+	// which finished actions are kept in the environment's history.
+	DefaultMaxActionResultsAge = 14 * 24 * time.Hour
+
+	// DefaultMaxActionResultsCount is the default maximum number of
+	// finished actions kept in the environment's history.
+	DefaultMaxActionResultsCount = 5000
+
+	// DefaultBackupKeepLast is the default number of the most recent
+	// scheduled backups that are kept.
+	DefaultBackupKeepLast = 7
+
 	// fallbackLtsSeries is the latest LTS series we'll use, if we fail to
 	// obtain this information from the system.
 	fallbackLtsSeries string = "trusty"
@@ -184,6 +200,89 @@ const (
 	// IdentityPublicKey sets the public key of the identity manager.
 	IdentityPublicKey = "identity-public-key"
 
+	// LogForwardAddressKey, when set to a "host:port" address,
+	// causes the environment's logs to be forwarded to a syslog or
+	// GELF receiver at that address.
+	LogForwardAddressKey = "log-forward-address"
+
+	// LogForwardFormatKey stores the format in which logs are
+	// forwarded; one of LogForwardSyslog or LogForwardGELF.
+	LogForwardFormatKey = "log-forward-format"
+
+	// LogForwardTLSKey stores whether forwarded logs are sent over
+	// a TLS connection.
+	LogForwardTLSKey = "log-forward-tls"
+
+	// LogForwardCACertKey optionally stores the PEM-encoded CA
+	// certificate used to verify the log forwarding receiver. If
+	// not set, the system's root CAs are used.
+	LogForwardCACertKey = "log-forward-ca-cert"
+
+	// MaxActionResultsAgeKey stores, as a duration, how long finished
+	// actions are kept in the environment's history. A duration of
+	// 0 keeps them regardless of age.
+	MaxActionResultsAgeKey = "max-action-results-age"
+
+	// MaxActionResultsCountKey stores the maximum number of finished
+	// actions kept in the environment's history; the oldest are
+	// removed first. A count of 0 means there is no limit.
+	MaxActionResultsCountKey = "max-action-results-count"
+
+	// BackupIntervalKey stores, as a duration, how often backups of
+	// the state server environment are made automatically. A
+	// duration of 0, the default, disables automatic backups.
+	BackupIntervalKey = "backup-interval"
+
+	// BackupOplogIntervalKey stores, as a duration, how often the
+	// database operations made since the latest backup are captured
+	// and kept with it, so that a restore can recover the state at a
+	// chosen time after the backup was made. A duration of 0, the
+	// default, disables capturing.
+	BackupOplogIntervalKey = "backup-oplog-interval"
+
+	// BackupKeepLastKey stores the number of the most recent
+	// automatic backups that are kept.
+	BackupKeepLastKey = "backup-keep-last"
+
+	// BackupKeepDailyKey stores the number of days, among the most
+	// recent days with automatic backups, for which the last backup
+	// of the day is kept.
+	BackupKeepDailyKey = "backup-keep-daily"
+
+	// BackupKeepWeeklyKey stores the number of weeks, among the most
+	// recent weeks with automatic backups, for which the last backup
+	// of the week is kept.
+	BackupKeepWeeklyKey = "backup-keep-weekly"
+
+	// BackupEncryptionKeyKey stores an ASCII-armored OpenPGP public
+	// key with which backup archives are encrypted, unless another
+	// key is given when the backup is made.
+	BackupEncryptionKeyKey = "backup-encryption-key"
+
+	// BackupStorageKey stores the URL of the place, away from the
+	// state server, where backup archives are kept: a file URL naming
+	// a local directory such as an NFS mount, an s3 URL naming a bucket
+	// in an S3-compatible object store, or an sftp URL. When it is
+	// empty, the default, archives are kept by the state server.
+	BackupStorageKey = "backup-storage"
+
+	// BackupStorageAccessKeyKey and BackupStorageSecretKeyKey store
+	// the credentials with which archives are kept in an S3-compatible
+	// object store.
+	BackupStorageAccessKeyKey = "backup-storage-access-key"
+	BackupStorageSecretKeyKey = "backup-storage-secret-key"
+
+	// BackupStorageHostKeyKey stores the public host keys, one per
+	// line, against which the host is verified when archives are kept
+	// over SFTP.
+	BackupStorageHostKeyKey = "backup-storage-host-key"
+
+	// BackupExcludeKey stores a comma-separated list of the databases
+	// ("logs") and collections ("juju.statuseshistory") whose contents
+	// are left out of backups by default. They are recreated empty
+	// when a backup is restored.
+	BackupExcludeKey = "backup-exclude"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -619,6 +718,62 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[LogForwardAddressKey].(string); ok && v != "" {
+		if _, _, err := net.SplitHostPort(v); err != nil {
+			return errors.Annotate(err, "invalid log forwarding address")
+		}
+	}
+	if v, ok := cfg.defined[LogForwardCACertKey].(string); ok && v != "" {
+		if _, err := cert.ParseCert(v); err != nil {
+			return errors.Annotate(err, "bad log forwarding CA certificate in configuration")
+		}
+	}
+	if v, ok := cfg.defined[MaxActionResultsAgeKey].(string); ok && v != "" {
+		if d, err := time.ParseDuration(v); err != nil || d < 0 {
+			return errors.Errorf("invalid %s %q", MaxActionResultsAgeKey, v)
+		}
+	}
+	if v, ok := cfg.defined[MaxActionResultsCountKey].(int); ok && v < 0 {
+		return errors.Errorf("invalid %s %d", MaxActionResultsCountKey, v)
+	}
+	if v, ok := cfg.defined[BackupIntervalKey].(string); ok && v != "" {
+		if d, err := time.ParseDuration(v); err != nil || d < 0 {
+			return errors.Errorf("invalid %s %q", BackupIntervalKey, v)
+		}
+	}
+	if v, ok := cfg.defined[BackupOplogIntervalKey].(string); ok && v != "" {
+		if d, err := time.ParseDuration(v); err != nil || d < 0 {
+			return errors.Errorf("invalid %s %q", BackupOplogIntervalKey, v)
+		}
+	}
+	for _, key := range []string{BackupKeepLastKey, BackupKeepDailyKey, BackupKeepWeeklyKey} {
+		if v, ok := cfg.defined[key].(int); ok && v < 0 {
+			return errors.Errorf("invalid %s %d", key, v)
+		}
+	}
+	if v, ok := cfg.defined[BackupEncryptionKeyKey].(string); ok && v != "" {
+		if err := validateBackupEncryptionKey(v); err != nil {
+			return errors.Annotatef(err, "invalid %s", BackupEncryptionKeyKey)
+		}
+	}
+	if v, ok := cfg.defined[BackupStorageKey].(string); ok && v != "" {
+		if err := cfg.validateBackupStorage(v); err != nil {
+			return errors.Annotatef(err, "invalid %s %q", BackupStorageKey, v)
+		}
+	}
+	if v, ok := cfg.defined[BackupExcludeKey].(string); ok && v != "" {
+		for _, name := range strings.Split(v, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") {
+				return errors.Errorf("invalid %s %q", BackupExcludeKey, v)
+			}
+			// The juju and admin databases are needed to restore a backup.
+			if name == "juju" || name == "admin" {
+				return errors.Errorf("invalid %s %q: cannot exclude the %q database", BackupExcludeKey, v, name)
+			}
+		}
+	}
+
 	caCert, caCertOK := cfg.CACert()
 	caKey, caKeyOK := cfg.CAPrivateKey()
 	if caCertOK || caKeyOK {
@@ -1265,6 +1420,228 @@ func (c *Config) Apply(attrs map[string]interface{}) (*Config, error) {
 	return New(NoDefaults, defined)
 }
 
+// Log forwarding formats.
+const (
+	// LogForwardSyslog forwards logs as RFC5424 syslog messages,
+	// framed as described in RFC6587.
+	LogForwardSyslog = "syslog"
+
+	// LogForwardGELF forwards logs as null-terminated GELF
+	// messages.
+	LogForwardGELF = "gelf"
+)
+
+// LogForwardAddress returns the "host:port" address to which the
+// environment's logs should be forwarded, or the empty string if log
+// forwarding is disabled.
+func (c *Config) LogForwardAddress() string {
+	return c.asString(LogForwardAddressKey)
+}
+
+// LogForwardFormat returns the format in which logs should be
+// forwarded. It defaults to LogForwardSyslog.
+func (c *Config) LogForwardFormat() string {
+	if v := c.asString(LogForwardFormatKey); v != "" {
+		return v
+	}
+	return LogForwardSyslog
+}
+
+// LogForwardTLS returns whether forwarded logs should be sent over a
+// TLS connection.
+func (c *Config) LogForwardTLS() bool {
+	v, _ := c.defined[LogForwardTLSKey].(bool)
+	return v
+}
+
+// LogForwardCACert returns the PEM-encoded CA certificate used to
+// verify the log forwarding receiver, and whether it was set.
+func (c *Config) LogForwardCACert() (string, bool) {
+	v := c.asString(LogForwardCACertKey)
+	return v, v != ""
+}
+
+// MaxActionResultsAge returns how long finished actions are kept in
+// the environment's history, or 0 if they are kept regardless of age.
+// It defaults to DefaultMaxActionResultsAge.
+func (c *Config) MaxActionResultsAge() time.Duration {
+	// Validate has already checked the duration.
+	if d, err := time.ParseDuration(c.asString(MaxActionResultsAgeKey)); err == nil {
+		return d
+	}
+	return DefaultMaxActionResultsAge
+}
+
+// MaxActionResultsCount returns the maximum number of finished actions
+// kept in the environment's history, or 0 if there is no limit. It
+// defaults to DefaultMaxActionResultsCount.
+func (c *Config) MaxActionResultsCount() int {
+	if v, ok := c.defined[MaxActionResultsCountKey].(int); ok {
+		return v
+	}
+	return DefaultMaxActionResultsCount
+}
+
+// BackupInterval returns how often backups of the state server
+// environment are made automatically, or 0 if they are not.
+func (c *Config) BackupInterval() time.Duration {
+	// Validate has already checked the duration.
+	if d, err := time.ParseDuration(c.asString(BackupIntervalKey)); err == nil {
+		return d
+	}
+	return 0
+}
+
+// BackupOplogInterval returns how often the database operations made
+// since the latest backup are captured, or 0 if they are not.
+func (c *Config) BackupOplogInterval() time.Duration {
+	// Validate has already checked the duration.
+	if d, err := time.ParseDuration(c.asString(BackupOplogIntervalKey)); err == nil {
+		return d
+	}
+	return 0
+}
+
+// BackupKeepLast returns the number of the most recent automatic
+// backups that are kept. It defaults to DefaultBackupKeepLast.
+func (c *Config) BackupKeepLast() int {
+	if v, ok := c.defined[BackupKeepLastKey].(int); ok {
+		return v
+	}
+	return DefaultBackupKeepLast
+}
+
+// BackupKeepDaily returns the number of days, among the most recent
+// days with automatic backups, for which the last backup of the day is
+// kept.
+func (c *Config) BackupKeepDaily() int {
+	v, _ := c.defined[BackupKeepDailyKey].(int)
+	return v
+}
+
+// BackupKeepWeekly returns the number of weeks, among the most recent
+// weeks with automatic backups, for which the last backup of the week
+// is kept.
+func (c *Config) BackupKeepWeekly() int {
+	v, _ := c.defined[BackupKeepWeeklyKey].(int)
+	return v
+}
+
+// BackupEncryptionKey returns the ASCII-armored OpenPGP public key with
+// which backup archives are encrypted by default, or "" if they are not.
+func (c *Config) BackupEncryptionKey() string {
+	return c.asString(BackupEncryptionKeyKey)
+}
+
+// validateBackupEncryptionKey checks that the given key is an
+// ASCII-armored OpenPGP public key. Private keys are rejected, so that
+// they are not stored in the environment.
+func validateBackupEncryptionKey(key string) error {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(entities) == 0 {
+		return errors.New("no key found")
+	}
+	if entities[0].PrivateKey != nil {
+		return errors.New("expected a public key, got a private key")
+	}
+	return nil
+}
+
+// BackupStorage returns the URL of the place, away from the state
+// server, where backup archives are kept, or "" if they are kept by the
+// state server.
+func (c *Config) BackupStorage() string {
+	return c.asString(BackupStorageKey)
+}
+
+// BackupStorageAccessKey returns the access key with which backup
+// archives are kept in an S3-compatible object store.
+func (c *Config) BackupStorageAccessKey() string {
+	return c.asString(BackupStorageAccessKeyKey)
+}
+
+// BackupStorageSecretKey returns the secret key with which backup
+// archives are kept in an S3-compatible object store.
+func (c *Config) BackupStorageSecretKey() string {
+	return c.asString(BackupStorageSecretKeyKey)
+}
+
+// BackupExclude returns the databases and collections whose contents
+// are left out of backups by default.
+func (c *Config) BackupExclude() []string {
+	v := c.asString(BackupExcludeKey)
+	if v == "" {
+		return nil
+	}
+	var names []string
+	for _, name := range strings.Split(v, ",") {
+		names = append(names, strings.TrimSpace(name))
+	}
+	return names
+}
+
+// BackupStorageHostKey returns the public host keys, one per line,
+// against which the host is verified when backup archives are kept over
+// SFTP.
+func (c *Config) BackupStorageHostKey() string {
+	return c.asString(BackupStorageHostKeyKey)
+}
+
+// validateBackupStorage checks that the given backup storage URL is one
+// that is supported, and that the settings it needs are present.
+func (c *Config) validateBackupStorage(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" || !path.IsAbs(u.Path) {
+			return errors.New("expected an absolute path, as in file:///srv/backups")
+		}
+	case "s3":
+		if u.Host == "" {
+			return errors.New("expected a bucket, as in s3://bucket/prefix")
+		}
+		if c.BackupStorageAccessKey() == "" || c.BackupStorageSecretKey() == "" {
+			return errors.Errorf("%s and %s must be set", BackupStorageAccessKeyKey, BackupStorageSecretKeyKey)
+		}
+	case "sftp":
+		if u.Host == "" {
+			return errors.New("expected a host, as in sftp://user@host/srv/backups")
+		}
+		if err := validateHostKeys(c.BackupStorageHostKey()); err != nil {
+			return errors.Annotatef(err, "invalid %s", BackupStorageHostKeyKey)
+		}
+	default:
+		return errors.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	return nil
+}
+
+// validateHostKeys checks that at least one public host key is given,
+// and that all of them can be parsed.
+func validateHostKeys(keys string) error {
+	found := false
+	for _, line := range strings.Split(keys, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line)); err != nil {
+			return errors.Errorf("cannot parse host key %q", line)
+		}
+		found = true
+	}
+	if !found {
+		return errors.New("no host key given")
+	}
+	return nil
+}
+
 // IdentityURL returns the url of the identity manager.
 func (c *Config) IdentityURL() string {
 	return c.asString(IdentityURL)
@@ -1336,6 +1713,23 @@ var alwaysOptional = schema.Defaults{
 	AllowLXCLoopMounts:           false,
 	ResourceTagsKey:              schema.Omit,
 	CloudImageBaseURL:            schema.Omit,
+	LogForwardAddressKey:         schema.Omit,
+	LogForwardFormatKey:          schema.Omit,
+	LogForwardTLSKey:             schema.Omit,
+	LogForwardCACertKey:          schema.Omit,
+	MaxActionResultsAgeKey:       schema.Omit,
+	MaxActionResultsCountKey:     schema.Omit,
+	BackupIntervalKey:            schema.Omit,
+	BackupOplogIntervalKey:       schema.Omit,
+	BackupKeepLastKey:            schema.Omit,
+	BackupKeepDailyKey:           schema.Omit,
+	BackupKeepWeeklyKey:          schema.Omit,
+	BackupEncryptionKeyKey:       schema.Omit,
+	BackupStorageKey:             schema.Omit,
+	BackupStorageAccessKeyKey:    schema.Omit,
+	BackupStorageSecretKeyKey:    schema.Omit,
+	BackupStorageHostKeyKey:      schema.Omit,
+	BackupExcludeKey:             schema.Omit,
 
 	// Storage related config.
 	// Environ providers will specify their own defaults.
@@ -1705,6 +2099,93 @@ var configSchema = environschema.Fields{
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	LogForwardAddressKey: {
+		Description: `The "host:port" address of a syslog or GELF receiver to which the environment's logs are forwarded, when logging to the database`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogForwardFormatKey: {
+		Description: "The format in which logs are forwarded",
+		Type:        environschema.Tstring,
+		Values:      []interface{}{LogForwardSyslog, LogForwardGELF},
+		Group:       environschema.EnvironGroup,
+	},
+	LogForwardTLSKey: {
+		Description: "Whether forwarded logs are sent over TLS",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	LogForwardCACertKey: {
+		Description: "The CA certificate used to verify the log forwarding receiver, in PEM format. If omitted, the system's root CAs are used.",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	MaxActionResultsAgeKey: {
+		Description: `How long finished actions are kept, as a duration such as "72h"; 0 keeps them regardless of age`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	MaxActionResultsCountKey: {
+		Description: "The maximum number of finished actions kept; 0 keeps them regardless of number",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupIntervalKey: {
+		Description: `How often backups of the state server environment are made automatically, as a duration such as "24h"; 0 disables automatic backups`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupOplogIntervalKey: {
+		Description: `How often the database operations made since the latest backup are captured, so that a restore can recover the state at a chosen time, as a duration such as "5m"; 0 disables capturing`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupKeepLastKey: {
+		Description: "The number of the most recent automatic backups that are kept",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupKeepDailyKey: {
+		Description: "The number of days, among the most recent days with automatic backups, for which the last backup of the day is kept",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupKeepWeeklyKey: {
+		Description: "The number of weeks, among the most recent weeks with automatic backups, for which the last backup of the week is kept",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupEncryptionKeyKey: {
+		Description: "An ASCII-armored OpenPGP public key with which backup archives are encrypted, unless another key is given when a backup is made",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupStorageKey: {
+		Description: "The URL of the place where backup archives are kept, away from the state server: file:///path, s3://bucket/prefix or sftp://user@host/path; if empty, archives are kept by the state server",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupStorageAccessKeyKey: {
+		Description: "The access key with which backup archives are kept in an S3-compatible object store",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupStorageSecretKeyKey: {
+		Description: "The secret key with which backup archives are kept in an S3-compatible object store",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+		Secret:      true,
+	},
+	BackupStorageHostKeyKey: {
+		Description: "The public host keys, one per line, against which the host is verified when backup archives are kept over SFTP",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupExcludeKey: {
+		Description: `A comma-separated list of the databases and collections, such as "logs,juju.statuseshistory", whose contents are left out of backups by default`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"default-series": {
 		Description: "The default series of Ubuntu to use for deploying charms",
 		Type:        environschema.Tstring,
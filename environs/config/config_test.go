@@ -25,6 +25,7 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/testing"
+	sshtesting "github.com/juju/juju/utils/ssh/testing"
 	"github.com/juju/juju/version"
 )
 
@@ -1026,6 +1027,242 @@ var configTests = []configTest{
 			"identity-url":        "https://test-identity",
 			"identity-public-key": "o/yOqSNWncMo1GURWuez/dGR30TscmmuIxgjztpoHEY=",
 		},
+	}, {
+		about:       "Valid log forwarding config",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"log-forward-address": "logs.example.com:6514",
+			"log-forward-format":  "gelf",
+			"log-forward-tls":     true,
+			"log-forward-ca-cert": caCert,
+		},
+	}, {
+		about:       "Invalid log forwarding address",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"log-forward-address": "logs.example.com",
+		},
+		err: `invalid log forwarding address: missing port in address logs.example.com`,
+	}, {
+		about:       "Invalid log forwarding format",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":               "my-type",
+			"name":               "my-name",
+			"log-forward-format": "xml",
+		},
+		err: `log-forward-format: expected one of \[syslog gelf], got "xml"`,
+	}, {
+		about:       "Invalid log forwarding CA cert",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"log-forward-ca-cert": invalidCACert,
+		},
+		err: `bad log forwarding CA certificate in configuration: .*`,
+	}, {
+		about:       "Valid action history config",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                     "my-type",
+			"name":                     "my-name",
+			"max-action-results-age":   "72h",
+			"max-action-results-count": 100,
+		},
+	}, {
+		about:       "Invalid action history age",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"max-action-results-age": "3 days",
+		},
+		err: `invalid max-action-results-age "3 days"`,
+	}, {
+		about:       "Invalid action history count",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                     "my-type",
+			"name":                     "my-name",
+			"max-action-results-count": -1,
+		},
+		err: `invalid max-action-results-count -1`,
+	}, {
+		about:       "Backup schedule",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":               "my-type",
+			"name":               "my-name",
+			"backup-interval":    "6h",
+			"backup-keep-last":   4,
+			"backup-keep-daily":  7,
+			"backup-keep-weekly": 4,
+		},
+	}, {
+		about:       "Invalid backup interval",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"backup-interval": "-1h",
+		},
+		err: `invalid backup-interval "-1h"`,
+	}, {
+		about:       "Backup oplog interval",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"backup-oplog-interval": "5m",
+		},
+	}, {
+		about:       "Invalid backup oplog interval",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"backup-oplog-interval": "often",
+		},
+		err: `invalid backup-oplog-interval "often"`,
+	}, {
+		about:       "Invalid backup retention",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"backup-keep-daily": -2,
+		},
+		err: `invalid backup-keep-daily -2`,
+	}, {
+		about:       "Backup encryption key",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"backup-encryption-key": testing.OpenPGPPublicKey,
+		},
+	}, {
+		about:       "Invalid backup encryption key",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"backup-encryption-key": "not a key",
+		},
+		err: `invalid backup-encryption-key: .*`,
+	}, {
+		about:       "Private backup encryption key",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"backup-encryption-key": testing.OpenPGPPrivateKey,
+		},
+		err: `invalid backup-encryption-key: expected a public key, got a private key`,
+	}, {
+		about:       "Backup storage in a local directory",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"backup-storage": "file:///srv/backups",
+		},
+	}, {
+		about:       "Backup storage in S3",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                      "my-type",
+			"name":                      "my-name",
+			"backup-storage":            "s3://juju-backups/prod?region=eu-west-1",
+			"backup-storage-access-key": "access",
+			"backup-storage-secret-key": "secret",
+		},
+	}, {
+		about:       "Backup storage in S3 without credentials",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"backup-storage": "s3://juju-backups",
+		},
+		err: `invalid backup-storage "s3://juju-backups": backup-storage-access-key and backup-storage-secret-key must be set`,
+	}, {
+		about:       "Backup storage over SFTP",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                    "my-type",
+			"name":                    "my-name",
+			"backup-storage":          "sftp://backup@nas.example.com:2222/srv/backups",
+			"backup-storage-host-key": sshtesting.ValidKeyOne.Key + "\n" + sshtesting.ValidKeyTwo.Key,
+		},
+	}, {
+		about:       "Backup storage over SFTP without a host key",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"backup-storage": "sftp://backup@nas.example.com/srv/backups",
+		},
+		err: `invalid backup-storage "sftp://backup@nas.example.com/srv/backups": invalid backup-storage-host-key: no host key given`,
+	}, {
+		about:       "Backup storage over SFTP with an invalid host key",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                    "my-type",
+			"name":                    "my-name",
+			"backup-storage":          "sftp://backup@nas.example.com/srv/backups",
+			"backup-storage-host-key": "ssh-rsa bad key",
+		},
+		err: `invalid backup-storage "sftp://backup@nas.example.com/srv/backups": invalid backup-storage-host-key: cannot parse host key "ssh-rsa bad key"`,
+	}, {
+		about:       "Backup storage with a relative path",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"backup-storage": "file://backups",
+		},
+		err: `invalid backup-storage "file://backups": expected an absolute path, as in file:///srv/backups`,
+	}, {
+		about:       "Backup storage with an unsupported scheme",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"backup-storage": "ftp://nas.example.com/backups",
+		},
+		err: `invalid backup-storage "ftp://nas.example.com/backups": unsupported scheme "ftp"`,
+	}, {
+		about:       "Backup exclusions",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"backup-exclude": "logs, juju.statuseshistory",
+		},
+	}, {
+		about:       "Backup exclusions with an empty name",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"backup-exclude": "logs,,juju.statuseshistory",
+		},
+		err: `invalid backup-exclude "logs,,juju.statuseshistory"`,
+	}, {
+		about:       "Backup exclusions with the juju database",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"backup-exclude": "logs,juju",
+		},
+		err: `invalid backup-exclude "logs,juju": cannot exclude the "juju" database`,
 	},
 }
 
@@ -1763,6 +2000,75 @@ func (s *ConfigSuite) TestCloudImageBaseURLSet(c *gc.C) {
 	c.Assert(config.CloudImageBaseURL(), gc.Equals, "http://local.foo/query")
 }
 
+func (s *ConfigSuite) TestLogForwardDefaults(c *gc.C) {
+	s.addJujuFiles(c)
+	config := newTestConfig(c, testing.Attrs{})
+	c.Assert(config.LogForwardAddress(), gc.Equals, "")
+	c.Assert(config.LogForwardFormat(), gc.Equals, "syslog")
+	c.Assert(config.LogForwardTLS(), jc.IsFalse)
+	_, ok := config.LogForwardCACert()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestMaxActionResults(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.MaxActionResultsAge(), gc.Equals, config.DefaultMaxActionResultsAge)
+	c.Assert(cfg.MaxActionResultsCount(), gc.Equals, config.DefaultMaxActionResultsCount)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"max-action-results-age":   "0",
+		"max-action-results-count": 0,
+	})
+	c.Assert(cfg.MaxActionResultsAge(), gc.Equals, time.Duration(0))
+	c.Assert(cfg.MaxActionResultsCount(), gc.Equals, 0)
+}
+
+func (s *ConfigSuite) TestBackupSchedule(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.BackupInterval(), gc.Equals, time.Duration(0))
+	c.Assert(cfg.BackupOplogInterval(), gc.Equals, time.Duration(0))
+	c.Assert(cfg.BackupKeepLast(), gc.Equals, config.DefaultBackupKeepLast)
+	c.Assert(cfg.BackupKeepDaily(), gc.Equals, 0)
+	c.Assert(cfg.BackupKeepWeekly(), gc.Equals, 0)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"backup-interval":       "24h",
+		"backup-oplog-interval": "5m",
+		"backup-keep-last":      0,
+		"backup-keep-daily":     7,
+		"backup-keep-weekly":    4,
+	})
+	c.Assert(cfg.BackupInterval(), gc.Equals, 24*time.Hour)
+	c.Assert(cfg.BackupOplogInterval(), gc.Equals, 5*time.Minute)
+	c.Assert(cfg.BackupKeepLast(), gc.Equals, 0)
+	c.Assert(cfg.BackupKeepDaily(), gc.Equals, 7)
+	c.Assert(cfg.BackupKeepWeekly(), gc.Equals, 4)
+}
+
+func (s *ConfigSuite) TestBackupEncryptionKey(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.BackupEncryptionKey(), gc.Equals, "")
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"backup-encryption-key": testing.OpenPGPPublicKey,
+	})
+	c.Assert(cfg.BackupEncryptionKey(), gc.Equals, testing.OpenPGPPublicKey)
+}
+
+func (s *ConfigSuite) TestBackupExclude(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.BackupExclude(), gc.HasLen, 0)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"backup-exclude": "logs, juju.statuseshistory",
+	})
+	c.Assert(cfg.BackupExclude(), jc.DeepEquals, []string{"logs", "juju.statuseshistory"})
+}
+
 func (s *ConfigSuite) TestProxyValuesWithFallback(c *gc.C) {
 	s.addJujuFiles(c)
 
@@ -91,12 +91,14 @@ func (s *apiEnvironmentSuite) TestEnvironmentUserInfo(c *gc.C) {
 			CreatedBy:      owner.UserName(),
 			DateCreated:    owner.DateCreated(),
 			LastConnection: lastConnPointer(c, owner),
+			Access:         params.EnvironmentWriteAccess,
 		}, {
 			UserName:       "bobjohns@ubuntuone",
 			DisplayName:    "Bob Johns",
 			CreatedBy:      owner.UserName(),
 			DateCreated:    envUser.DateCreated(),
 			LastConnection: lastConnPointer(c, envUser),
+			Access:         params.EnvironmentWriteAccess,
 		},
 	})
 }
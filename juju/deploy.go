@@ -38,6 +38,9 @@ type DeployServiceParams struct {
 	// TODO(dimitern): Drop this in a follow-up in favor of constraints.
 	Networks []string
 	Storage  map[string]storage.Constraints
+	// EndpointBindings maps the service's relation endpoints to the
+	// names of the spaces they are bound to.
+	EndpointBindings map[string]string
 }
 
 // DeployService takes a charm and various parameters and deploys it.
@@ -71,6 +74,12 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 		return nil, fmt.Errorf("use of --networks is deprecated. Please use spaces")
 	}
 
+	// The bindings are only set once the service has been added, so
+	// check them up front rather than leave a half-deployed service.
+	if err := validateEndpointBindings(st, args.Charm.Meta(), args.EndpointBindings); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// TODO(dimitern): In a follow-up drop Networks and use spaces
 	// constraints for this when possible.
 	service, err := st.AddService(
@@ -88,6 +97,11 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 			return nil, err
 		}
 	}
+	if len(args.EndpointBindings) > 0 {
+		if err := service.SetEndpointBindings(args.EndpointBindings); err != nil {
+			return nil, err
+		}
+	}
 	if args.Charm.Meta().Subordinate {
 		return service, nil
 	}
@@ -164,6 +178,37 @@ func addMachineForUnit(st *state.State, unit *state.Unit, placement *instance.Pl
 	return st.Machine(mid)
 }
 
+// validateEndpointBindings checks that each of the bindings names one of
+// the charm's relation endpoints and an existing space.
+func validateEndpointBindings(st *state.State, meta *charm.Meta, bindings map[string]string) error {
+	for endpoint, space := range bindings {
+		if !hasEndpoint(meta, endpoint) {
+			return errors.NotFoundf("endpoint %q", endpoint)
+		}
+		if space == "" {
+			continue
+		}
+		if _, err := st.Space(space); err != nil {
+			return errors.Annotatef(err, "cannot bind endpoint %q", endpoint)
+		}
+	}
+	return nil
+}
+
+// hasEndpoint returns whether the charm defines the named relation
+// endpoint, including the implicit juju-info endpoint.
+func hasEndpoint(meta *charm.Meta, name string) bool {
+	if name == "juju-info" {
+		return true
+	}
+	for _, relations := range []map[string]charm.Relation{meta.Provides, meta.Requires, meta.Peers} {
+		if _, ok := relations[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // AddUnits starts n units of the given service and allocates machines
 // to them as necessary.
 func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string) ([]*state.Unit, error) {
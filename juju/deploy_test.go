@@ -116,6 +116,32 @@ func (s *DeployLocalSuite) TestDeploySettingsError(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *DeployLocalSuite) TestDeployEndpointBindingsUnknownSpace(c *gc.C) {
+	_, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:      "bob",
+			Charm:            s.charm,
+			EndpointBindings: map[string]string{"juju-info": "missing"},
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot bind endpoint "juju-info": space "missing" not found`)
+	_, err = s.State.Service("bob")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *DeployLocalSuite) TestDeployEndpointBindingsUnknownEndpoint(c *gc.C) {
+	_, err := s.State.AddSpace("db", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:      "bob",
+			Charm:            s.charm,
+			EndpointBindings: map[string]string{"missing": "db"},
+		})
+	c.Assert(err, gc.ErrorMatches, `endpoint "missing" not found`)
+	_, err = s.State.Service("bob")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *DeployLocalSuite) TestDeployConstraints(c *gc.C) {
 	err := s.State.SetEnvironConstraints(constraints.MustParse("mem=2G"))
 	c.Assert(err, jc.ErrorIsNil)
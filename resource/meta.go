@@ -0,0 +1,62 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resource
+
+import (
+	"archive/zip"
+	"io"
+	"io/ioutil"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v2"
+)
+
+// ReadMeta returns the resources declared in the "resources" section
+// of the charm metadata read from r, keyed by name. A resource with no
+// type is a file.
+func ReadMeta(r io.Reader) (map[string]Meta, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var metadata struct {
+		Resources map[string]Meta `yaml:"resources"`
+	}
+	if err := goyaml.Unmarshal(data, &metadata); err != nil {
+		return nil, errors.Annotate(err, "cannot parse charm metadata")
+	}
+	resources := make(map[string]Meta, len(metadata.Resources))
+	for name, meta := range metadata.Resources {
+		meta.Name = name
+		if meta.Type == "" {
+			meta.Type = TypeFile
+		}
+		if err := meta.Validate(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		resources[name] = meta
+	}
+	return resources, nil
+}
+
+// ReadArchiveMeta returns the resources declared in the metadata of the
+// charm archive read from r.
+func ReadArchiveMeta(r io.ReaderAt, size int64) (map[string]Meta, error) {
+	zipr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read charm archive")
+	}
+	for _, f := range zipr.File {
+		if f.Name != "metadata.yaml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer rc.Close()
+		return ReadMeta(rc)
+	}
+	return nil, errors.NotFoundf("charm metadata")
+}
@@ -0,0 +1,94 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resource_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/resource"
+)
+
+type MetaSuite struct{}
+
+var _ = gc.Suite(&MetaSuite{})
+
+const metadataWithResources = `
+name: wordpress
+summary: blog
+description: a blog
+resources:
+  software:
+    type: file
+    filename: wordpress.tgz
+    description: the wordpress release
+  theme:
+    filename: theme.zip
+`
+
+func (s *MetaSuite) TestReadMeta(c *gc.C) {
+	resources, err := resource.ReadMeta(strings.NewReader(metadataWithResources))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, jc.DeepEquals, map[string]resource.Meta{
+		"software": {
+			Name:        "software",
+			Type:        "file",
+			Path:        "wordpress.tgz",
+			Description: "the wordpress release",
+		},
+		"theme": {
+			Name: "theme",
+			Type: "file",
+			Path: "theme.zip",
+		},
+	})
+}
+
+func (s *MetaSuite) TestReadMetaNoResources(c *gc.C) {
+	resources, err := resource.ReadMeta(strings.NewReader("name: wordpress\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 0)
+}
+
+func (s *MetaSuite) TestReadMetaInvalid(c *gc.C) {
+	_, err := resource.ReadMeta(strings.NewReader(`
+resources:
+  software:
+    type: file
+`))
+	c.Assert(err, gc.ErrorMatches, `resource "software" missing filename`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *MetaSuite) TestReadArchiveMeta(c *gc.C) {
+	var buf bytes.Buffer
+	zipw := zip.NewWriter(&buf)
+	w, err := zipw.Create("metadata.yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.Write([]byte(metadataWithResources))
+	c.Assert(err, jc.ErrorIsNil)
+	err = zipw.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	data := buf.Bytes()
+	resources, err := resource.ReadArchiveMeta(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 2)
+	c.Assert(resources["software"].Path, gc.Equals, "wordpress.tgz")
+}
+
+func (s *MetaSuite) TestReadArchiveMetaMissing(c *gc.C) {
+	var buf bytes.Buffer
+	err := zip.NewWriter(&buf).Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	data := buf.Bytes()
+	_, err = resource.ReadArchiveMeta(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, gc.ErrorMatches, "charm metadata not found")
+}
@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package resource defines charm resources: binary blobs declared in a
+// charm's metadata, uploaded by users and attached to services, which
+// units fetch from the controller instead of the network.
+package resource
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// TypeFile is the only resource type currently supported: a single
+// file that is written to disk on the unit.
+const TypeFile = "file"
+
+// Meta describes a resource declared in a charm's metadata.
+type Meta struct {
+	// Name identifies the resource within the charm.
+	Name string `yaml:"-"`
+
+	// Type is the kind of resource; only TypeFile is supported.
+	Type string `yaml:"type"`
+
+	// Path is the file name the resource is given on the unit.
+	Path string `yaml:"filename"`
+
+	// Description optionally describes the resource.
+	Description string `yaml:"description,omitempty"`
+}
+
+// Validate returns an error if the resource metadata is not valid.
+func (meta Meta) Validate() error {
+	if meta.Name == "" {
+		return errors.NewNotValid(nil, "missing resource name")
+	}
+	// The name is used as a directory on the unit.
+	if !isFileName(meta.Name) {
+		return errors.NotValidf("resource name %q", meta.Name)
+	}
+	if meta.Type != TypeFile {
+		return errors.NotValidf("resource %q type %q", meta.Name, meta.Type)
+	}
+	if meta.Path == "" {
+		return errors.NewNotValid(nil, fmt.Sprintf("resource %q missing filename", meta.Name))
+	}
+	// The filename is used on the unit, so it must not be a path.
+	if !isFileName(meta.Path) {
+		return errors.NotValidf("resource %q filename %q", meta.Name, meta.Path)
+	}
+	return nil
+}
+
+// isFileName returns whether name is a plain file name, which names a
+// file within a directory rather than a path elsewhere.
+func isFileName(name string) bool {
+	return name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// NewFingerprint returns the fingerprint of the data read from r, along
+// with its size. A fingerprint is the hex-encoded SHA-384 hash of the
+// data.
+func NewFingerprint(r io.Reader) (string, int64, error) {
+	hash := sha512.New384()
+	size, err := io.Copy(hash, r)
+	if err != nil {
+		return "", 0, errors.Trace(err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// FileFingerprint returns the fingerprint of the file at the given path.
+func FileFingerprint(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer f.Close()
+	fingerprint, _, err := NewFingerprint(f)
+	return fingerprint, errors.Trace(err)
+}
@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resource_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/resource"
+)
+
+type ResourceSuite struct{}
+
+var _ = gc.Suite(&ResourceSuite{})
+
+// helloFingerprint is the SHA-384 hash of "hello".
+const helloFingerprint = "59e1748777448c69de6b800d7a33bbfb9ff1b463e44354c3553bcdb9c666fa90125a3c79f90397bdf5f6a13de828684f"
+
+func (s *ResourceSuite) TestNewFingerprint(c *gc.C) {
+	fingerprint, size, err := resource.NewFingerprint(strings.NewReader("hello"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fingerprint, gc.Equals, helloFingerprint)
+	c.Assert(size, gc.Equals, int64(5))
+}
+
+func (s *ResourceSuite) TestFileFingerprint(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "hello.txt")
+	err := ioutil.WriteFile(path, []byte("hello"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fingerprint, err := resource.FileFingerprint(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fingerprint, gc.Equals, helloFingerprint)
+}
+
+func (s *ResourceSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		meta resource.Meta
+		err  string
+	}{{
+		meta: resource.Meta{Name: "software", Type: "file", Path: "software.tgz"},
+	}, {
+		meta: resource.Meta{Type: "file", Path: "software.tgz"},
+		err:  "missing resource name",
+	}, {
+		meta: resource.Meta{Name: "software", Type: "image", Path: "software.tgz"},
+		err:  `resource "software" type "image" not valid`,
+	}, {
+		meta: resource.Meta{Name: "software", Type: "file"},
+		err:  `resource "software" missing filename`,
+	}, {
+		meta: resource.Meta{Name: "software", Type: "file", Path: "../software.tgz"},
+		err:  `resource "software" filename "../software.tgz" not valid`,
+	}, {
+		meta: resource.Meta{Name: "software", Type: "file", Path: ".."},
+		err:  `resource "software" filename ".." not valid`,
+	}, {
+		meta: resource.Meta{Name: "..", Type: "file", Path: "software.tgz"},
+		err:  `resource name ".." not valid`,
+	}, {
+		meta: resource.Meta{Name: ".", Type: "file", Path: "software.tgz"},
+		err:  `resource name "." not valid`,
+	}, {
+		meta: resource.Meta{Name: "../software", Type: "file", Path: "software.tgz"},
+		err:  `resource name "../software" not valid`,
+	}} {
+		c.Logf("test %d: %#v", i, test.meta)
+		err := test.meta.Validate()
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
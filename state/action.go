@@ -9,6 +9,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
 	"github.com/juju/utils"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -39,6 +40,15 @@ const (
 
 	// ActionRunning indicates that the Action is currently running.
 	ActionRunning ActionStatus = "running"
+
+	// ActionCancelling indicates that the Action was cancelled while
+	// running, and the unit has yet to stop it.
+	ActionCancelling ActionStatus = "cancelling"
+
+	// ActionWaiting indicates that the Action was queued while as
+	// many of its service's units as its limit allows were already
+	// running it. It becomes pending as soon as one of them finishes.
+	ActionWaiting ActionStatus = "waiting"
 )
 const actionMarker string = "_a_"
 
@@ -59,6 +69,10 @@ type actionNotificationDoc struct {
 	// ActionID is the unique identifier for the Action this notification
 	// represents.
 	ActionID string `bson:"actionid"`
+
+	// Cancelled is set when the Action is cancelled while running.
+	// Setting it notifies watchers of the ActionReceiver's actions.
+	Cancelled bool `bson:"cancelled,omitempty"`
 }
 
 type actionDoc struct {
@@ -99,6 +113,40 @@ type actionDoc struct {
 
 	// Results are the structured results from the action.
 	Results map[string]interface{} `bson:"results"`
+
+	// Operation identifies the operation of which the action is a
+	// part, if it was enqueued along with others as a batch.
+	Operation string `bson:"operation,omitempty"`
+
+	// Timeout is the maximum time the action may run for before it
+	// is stopped and marked as failed. Zero means no timeout.
+	Timeout time.Duration `bson:"timeout,omitempty"`
+
+	// Schedule identifies the action schedule that enqueued the
+	// action, if any.
+	Schedule string `bson:"schedule,omitempty"`
+
+	// MissedRuns records how many runs of the action's schedule were
+	// missed immediately before the action was enqueued.
+	MissedRuns int `bson:"missed-runs,omitempty"`
+}
+
+// ActionOptions holds optional settings for an Action being queued.
+type ActionOptions struct {
+	// Operation identifies the operation of which the action is a
+	// part, if any.
+	Operation string
+
+	// Timeout is the maximum time the action may run for. If zero,
+	// the timeout given for the action in the charm's actions.yaml,
+	// if any, is used.
+	Timeout time.Duration
+
+	// Schedule identifies the action schedule enqueueing the
+	// action, if any, and MissedRuns the number of runs of the
+	// schedule missed immediately before this one.
+	Schedule   string
+	MissedRuns int
 }
 
 // Action represents an instruction to do some "action" and is expected
@@ -131,6 +179,30 @@ func (a *Action) Parameters() map[string]interface{} {
 	return a.doc.Parameters
 }
 
+// Operation returns the id of the operation of which the Action is a
+// part, or "" if the Action was enqueued on its own.
+func (a *Action) Operation() string {
+	return a.doc.Operation
+}
+
+// Timeout returns the maximum time the Action may run for, or zero if
+// it may run indefinitely.
+func (a *Action) Timeout() time.Duration {
+	return a.doc.Timeout
+}
+
+// Schedule returns the id of the action schedule that enqueued the
+// Action, or "" if it was not enqueued by a schedule.
+func (a *Action) Schedule() string {
+	return a.doc.Schedule
+}
+
+// MissedRuns returns the number of runs of the Action's schedule that
+// were missed immediately before the Action was enqueued.
+func (a *Action) MissedRuns() int {
+	return a.doc.MissedRuns
+}
+
 // Enqueued returns the time the action was added to state as a pending
 // Action.
 func (a *Action) Enqueued() time.Time {
@@ -208,37 +280,111 @@ func (a *Action) Finish(results ActionResults) (*Action, error) {
 	return a.removeAndLog(results.Status, results.Results, results.Message)
 }
 
+// Cancel cancels the action. A pending or waiting action is immediately
+// marked as cancelled; a running action is marked as cancelling, and its
+// receiver is notified so that it can stop the action and record the
+// outcome.
+func (a *Action) Cancel() (*Action, error) {
+	action := a
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			var err error
+			if action, err = a.st.Action(a.Id()); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		switch action.Status() {
+		case ActionPending, ActionWaiting:
+			return action.finishOps(ActionCancelled, nil, "action cancelled")
+		case ActionRunning:
+			return []txn.Op{{
+				C:      actionsC,
+				Id:     action.doc.DocId,
+				Assert: bson.D{{"status", ActionRunning}},
+				Update: bson.D{{"$set", bson.D{{"status", ActionCancelling}}}},
+			}, {
+				C:      actionNotificationsC,
+				Id:     a.st.docID(ensureActionMarker(action.Receiver()) + action.Id()),
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"cancelled", true}}}},
+			}}, nil
+		case ActionCancelling:
+			return nil, jujutxn.ErrNoOperations
+		}
+		return nil, errors.Errorf("action %s has already finished", action.Id())
+	}
+	if err := a.st.run(buildTxn); err != nil {
+		return nil, errors.Annotatef(err, "cannot cancel action %s", a.Id())
+	}
+	return a.st.Action(a.Id())
+}
+
 // removeAndLog takes the action off of the pending queue, and creates
 // an actionresult to capture the outcome of the action. It asserts that
 // the action is not already completed.
 func (a *Action) removeAndLog(finalStatus ActionStatus, results map[string]interface{}, message string) (*Action, error) {
-	err := a.st.runTransaction([]txn.Op{
-		{
-			C:  actionsC,
-			Id: a.doc.DocId,
-			Assert: bson.D{{"status", bson.D{
-				{"$nin", []interface{}{
-					ActionCompleted,
-					ActionCancelled,
-					ActionFailed,
-				}}}}},
-			Update: bson.D{{"$set", bson.D{
-				{"status", finalStatus},
-				{"message", message},
-				{"results", results},
-				{"completed", nowToTheSecond()},
-			}}},
-		}, {
-			C:      actionNotificationsC,
-			Id:     a.st.docID(ensureActionMarker(a.Receiver()) + a.Id()),
-			Remove: true,
-		}})
-	if err != nil {
+	action := a
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			var err error
+			if action, err = a.st.Action(a.Id()); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		switch action.Status() {
+		case ActionWaiting, ActionPending, ActionRunning, ActionCancelling:
+			return action.finishOps(finalStatus, results, message)
+		}
+		return nil, errors.Errorf("action %s has already finished", action.Id())
+	}
+	if err := a.st.run(buildTxn); err != nil {
 		return nil, err
 	}
 	return a.st.Action(a.Id())
 }
 
+// finishOps returns the operations needed to record the final state of
+// the action and remove its notification, asserting that the action's
+// status has not changed. If the action was pending or running, and its
+// service limits how many units may run it at once, the oldest action
+// waiting on it is passed on to its unit.
+func (a *Action) finishOps(finalStatus ActionStatus, results map[string]interface{}, message string) ([]txn.Op, error) {
+	ops := []txn.Op{{
+		C:      actionsC,
+		Id:     a.doc.DocId,
+		Assert: bson.D{{"status", a.doc.Status}},
+		Update: bson.D{{"$set", bson.D{
+			{"status", finalStatus},
+			{"message", message},
+			{"results", results},
+			{"completed", nowToTheSecond()},
+		}}},
+	}, {
+		C:      actionNotificationsC,
+		Id:     a.st.docID(ensureActionMarker(a.Receiver()) + a.Id()),
+		Remove: true,
+	}}
+	if a.doc.Status == ActionWaiting {
+		return ops, nil
+	}
+	limit, err := a.st.actionLimit(a.doc.Receiver, a.doc.Name)
+	if err != nil || limit == nil {
+		return ops, errors.Trace(err)
+	}
+	active := limit.active - 1
+	if active < limit.limit {
+		waiting, err := a.st.waitingServiceActions(limit.service, a.doc.Name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(waiting) > 0 {
+			ops = append(ops, a.st.releaseActionOps(waiting[0])...)
+			active++
+		}
+	}
+	return append(ops, limit.ops(active)...), nil
+}
+
 // newActionTagFromNotification converts an actionNotificationDoc into
 // an names.ActionTag
 func newActionTagFromNotification(doc actionNotificationDoc) names.ActionTag {
@@ -254,29 +400,39 @@ func newAction(st *State, adoc actionDoc) *Action {
 	}
 }
 
-// newActionDoc builds the actionDoc with the given name and parameters.
-func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameters map[string]interface{}) (actionDoc, actionNotificationDoc, error) {
-	prefix := ensureActionMarker(receiverTag.Id())
+// newActionDoc builds the actionDoc with the given name and parameters,
+// and the notification that passes it on to its receiver.
+func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameters map[string]interface{}, opts ActionOptions) (actionDoc, actionNotificationDoc, error) {
 	actionId, err := NewUUID()
 	if err != nil {
 		return actionDoc{}, actionNotificationDoc{}, err
 	}
 	actionLogger.Debugf("newActionDoc name: '%s', receiver: '%s', actionId: '%s'", actionName, receiverTag, actionId)
-	envuuid := st.EnvironUUID()
 	return actionDoc{
-			DocId:      st.docID(actionId.String()),
-			EnvUUID:    envuuid,
-			Receiver:   receiverTag.Id(),
-			Name:       actionName,
-			Parameters: parameters,
-			Enqueued:   nowToTheSecond(),
-			Status:     ActionPending,
-		}, actionNotificationDoc{
-			DocId:    st.docID(prefix + actionId.String()),
-			EnvUUID:  envuuid,
-			Receiver: receiverTag.Id(),
-			ActionID: actionId.String(),
-		}, nil
+		DocId:      st.docID(actionId.String()),
+		EnvUUID:    st.EnvironUUID(),
+		Receiver:   receiverTag.Id(),
+		Name:       actionName,
+		Parameters: parameters,
+		Enqueued:   nowToTheSecond(),
+		Status:     ActionPending,
+		Operation:  opts.Operation,
+		Timeout:    opts.Timeout,
+		Schedule:   opts.Schedule,
+		MissedRuns: opts.MissedRuns,
+	}, newActionNotificationDoc(st, receiverTag.Id(), actionId.String()), nil
+}
+
+// newActionNotificationDoc builds the actionNotificationDoc for the
+// action with the given id, queued for the given receiver.
+func newActionNotificationDoc(st *State, receiver, actionId string) actionNotificationDoc {
+	prefix := ensureActionMarker(receiver)
+	return actionNotificationDoc{
+		DocId:    st.docID(prefix + actionId),
+		EnvUUID:  st.EnvironUUID(),
+		Receiver: receiver,
+		ActionID: actionId,
+	}
 }
 
 var ensureActionMarker = ensureSuffixFn(actionMarker)
@@ -331,43 +487,78 @@ func (st *State) FindActionTagsByPrefix(prefix string) []names.ActionTag {
 
 // EnqueueAction
 func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}) (*Action, error) {
+	return st.enqueueAction(receiver, actionName, payload, ActionOptions{})
+}
+
+// EnqueueOperationAction enqueues an action as part of the operation
+// with the given id. The actions of an operation may be retrieved
+// together with OperationActions.
+func (st *State) EnqueueOperationAction(operation string, receiver names.Tag, actionName string, payload map[string]interface{}) (*Action, error) {
+	if operation == "" {
+		return nil, errors.New("operation id required")
+	}
+	return st.enqueueAction(receiver, actionName, payload, ActionOptions{Operation: operation})
+}
+
+func (st *State) enqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}, opts ActionOptions) (*Action, error) {
 	if len(actionName) == 0 {
 		return nil, errors.New("action name required")
 	}
+	if opts.Timeout < 0 {
+		return nil, errors.Errorf("invalid timeout %v", opts.Timeout)
+	}
 
 	receiverCollectionName, receiverId, err := st.tagToCollectionAndId(receiver)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	doc, ndoc, err := newActionDoc(st, receiver, actionName, payload)
+	doc, ndoc, err := newActionDoc(st, receiver, actionName, payload, opts)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	ops := []txn.Op{{
-		C:      receiverCollectionName,
-		Id:     receiverId,
-		Assert: notDeadDoc,
-	}, {
-		C:      actionsC,
-		Id:     doc.DocId,
-		Assert: txn.DocMissing,
-		Insert: doc,
-	}, {
-		C:      actionNotificationsC,
-		Id:     ndoc.DocId,
-		Assert: txn.DocMissing,
-		Insert: ndoc,
-	}}
-
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		if notDead, err := isNotDead(st, receiverCollectionName, receiverId); err != nil {
 			return nil, err
 		} else if !notDead {
 			return nil, ErrDead
-		} else if attempt != 0 {
-			return nil, errors.Errorf("unexpected attempt number '%d'", attempt)
+		}
+		ops := []txn.Op{{
+			C:      receiverCollectionName,
+			Id:     receiverId,
+			Assert: notDeadDoc,
+		}}
+
+		// If the receiver's service already has as many units
+		// running the action as it allows, the action waits
+		// for one of them to finish.
+		doc.Status = ActionPending
+		limit, err := st.actionLimit(doc.Receiver, actionName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if limit != nil {
+			active := limit.active + 1
+			if limit.active >= limit.limit {
+				doc.Status = ActionWaiting
+				active = limit.active
+			}
+			ops = append(ops, limit.ops(active)...)
+		}
+		ops = append(ops, txn.Op{
+			C:      actionsC,
+			Id:     doc.DocId,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		})
+		if doc.Status == ActionPending {
+			ops = append(ops, txn.Op{
+				C:      actionNotificationsC,
+				Id:     ndoc.DocId,
+				Assert: txn.DocMissing,
+				Insert: ndoc,
+			})
 		}
 		return ops, nil
 	}
@@ -377,6 +568,27 @@ func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload ma
 	return nil, err
 }
 
+// OperationActions returns the actions enqueued as part of the operation
+// with the given id, ordered by receiver.
+func (st *State) OperationActions(operation string) ([]*Action, error) {
+	actionsCollection, closer := st.getCollection(actionsC)
+	defer closer()
+
+	var docs []actionDoc
+	err := actionsCollection.Find(bson.D{{"operation", operation}}).Sort("receiver").All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get actions for operation %q", operation)
+	}
+	if len(docs) == 0 {
+		return nil, errors.NotFoundf("operation %q", operation)
+	}
+	actions := make([]*Action, len(docs))
+	for i, doc := range docs {
+		actions[i] = newAction(st, doc)
+	}
+	return actions, nil
+}
+
 // matchingActions finds actions that match ActionReceiver.
 func (st *State) matchingActions(ar ActionReceiver) ([]*Action, error) {
 	return st.matchingActionsByReceiverId(ar.Tag().Id())
@@ -418,16 +630,22 @@ func (st *State) matchingActionNotificationsByReceiverId(id string) ([]names.Act
 }
 
 // matchingActionsPending finds actions that match ActionReceiver and
-// that are pending.
+// that are pending or waiting.
 func (st *State) matchingActionsPending(ar ActionReceiver) ([]*Action, error) {
-	completed := bson.D{{"status", ActionPending}}
+	completed := bson.D{{"status", bson.D{{"$in", []ActionStatus{
+		ActionPending,
+		ActionWaiting,
+	}}}}}
 	return st.matchingActionsByReceiverAndStatus(ar.Tag(), completed)
 }
 
 // matchingActionsRunning finds actions that match ActionReceiver and
 // that are running.
 func (st *State) matchingActionsRunning(ar ActionReceiver) ([]*Action, error) {
-	completed := bson.D{{"status", ActionRunning}}
+	completed := bson.D{{"status", bson.D{{"$in", []ActionStatus{
+		ActionRunning,
+		ActionCancelling,
+	}}}}}
 	return st.matchingActionsByReceiverAndStatus(ar.Tag(), completed)
 }
 
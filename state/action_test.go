@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -301,6 +302,348 @@ func (s *ActionSuite) TestEnqueueActionRequiresName(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "action name required")
 }
 
+func (s *ActionSuite) TestOperationActions(c *gc.C) {
+	operation := "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+	action2, err := s.unit2.AddOperationAction(operation, "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action1, err := s.unit.AddOperationAction(operation, "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action1.Operation(), gc.Equals, operation)
+
+	actions, err := s.State.OperationActions(operation)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 2)
+	c.Assert(actions[0].Id(), gc.Equals, action1.Id())
+	c.Assert(actions[1].Id(), gc.Equals, action2.Id())
+
+	_, err = s.State.OperationActions("no-such-operation")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.unit.AddOperationAction("", "snapshot", nil)
+	c.Assert(err, gc.ErrorMatches, "no operation id given")
+}
+
+func (s *ActionSuite) TestFilterActions(c *gc.C) {
+	a1, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	a2, err := s.unit2.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = a2.Finish(state.ActionResults{Status: state.ActionFailed})
+	c.Assert(err, jc.ErrorIsNil)
+	otherService := s.AddTestingService(c, "other", s.charm)
+	otherUnit, err := otherService.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	a3, err := otherUnit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i, test := range []struct {
+		about  string
+		filter state.ActionFilter
+		ids    []string
+	}{{
+		about: "no filter",
+		ids:   []string{a1.Id(), a2.Id(), a3.Id()},
+	}, {
+		about:  "by unit",
+		filter: state.ActionFilter{Units: []string{s.unit.Name()}},
+		ids:    []string{a1.Id()},
+	}, {
+		about:  "by service",
+		filter: state.ActionFilter{Services: []string{s.service.Name()}},
+		ids:    []string{a1.Id(), a2.Id()},
+	}, {
+		about: "by unit or service",
+		filter: state.ActionFilter{
+			Units:    []string{otherUnit.Name()},
+			Services: []string{s.service.Name()},
+		},
+		ids: []string{a1.Id(), a2.Id(), a3.Id()},
+	}, {
+		about:  "by name",
+		filter: state.ActionFilter{Names: []string{"backup"}},
+	}, {
+		about:  "by status",
+		filter: state.ActionFilter{Statuses: []state.ActionStatus{state.ActionFailed}},
+		ids:    []string{a2.Id()},
+	}, {
+		about:  "after",
+		filter: state.ActionFilter{After: time.Now().Add(time.Hour)},
+	}, {
+		about:  "before",
+		filter: state.ActionFilter{Before: time.Now().Add(time.Hour)},
+		ids:    []string{a1.Id(), a2.Id(), a3.Id()},
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		actions, err := s.State.FilterActions(test.filter)
+		c.Assert(err, jc.ErrorIsNil)
+		ids := make([]string, len(actions))
+		for j, action := range actions {
+			ids[j] = action.Id()
+		}
+		c.Check(ids, jc.SameContents, test.ids)
+	}
+}
+
+func (s *ActionSuite) TestFilterActionsPages(c *gc.C) {
+	for i := 0; i < 3; i++ {
+		_, err := s.unit.AddAction("snapshot", nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	all, err := s.State.FilterActions(state.ActionFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 3)
+
+	page, err := s.State.FilterActions(state.ActionFilter{Limit: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(page, gc.DeepEquals, all[:2])
+
+	page, err = s.State.FilterActions(state.ActionFilter{Offset: 2, Limit: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(page, gc.DeepEquals, all[2:])
+
+	_, err = s.State.FilterActions(state.ActionFilter{Limit: -1})
+	c.Assert(err, gc.ErrorMatches, "offset and limit must not be negative")
+}
+
+func (s *ActionSuite) TestPruneActionsByTime(c *gc.C) {
+	pending, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 2; i++ {
+		action, err := s.unit.AddAction("snapshot", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = action.Finish(state.ActionResults{Status: state.ActionCompleted})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	err = state.PruneActions(s.State, time.Now().Add(-time.Hour), 0)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertActionCount(c, 3)
+
+	err = state.PruneActions(s.State, time.Now().Add(time.Hour), 0)
+	c.Assert(err, jc.ErrorIsNil)
+	actions, err := s.State.FilterActions(state.ActionFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Id(), gc.Equals, pending.Id())
+}
+
+func (s *ActionSuite) TestPruneActionsByCount(c *gc.C) {
+	_, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 4; i++ {
+		action, err := s.unit.AddAction("snapshot", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = action.Finish(state.ActionResults{Status: state.ActionCompleted})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	err = state.PruneActions(s.State, time.Time{}, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	finished, err := s.State.FilterActions(state.ActionFilter{
+		Statuses: []state.ActionStatus{state.ActionCompleted},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(finished, gc.HasLen, 2)
+	s.assertActionCount(c, 3)
+}
+
+func (s *ActionSuite) TestPruneActionsInBatches(c *gc.C) {
+	s.PatchValue(state.PruneActionsBatchSize, 2)
+	pending, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 5; i++ {
+		action, err := s.unit.AddAction("snapshot", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = action.Finish(state.ActionResults{Status: state.ActionCompleted})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	err = state.PruneActions(s.State, time.Now().Add(time.Hour), 0)
+	c.Assert(err, jc.ErrorIsNil)
+	actions, err := s.State.FilterActions(state.ActionFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Id(), gc.Equals, pending.Id())
+}
+
+func (s *ActionSuite) assertActionCount(c *gc.C, count int) {
+	actions, err := s.State.FilterActions(state.ActionFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, count)
+}
+
+func (s *ActionSuite) TestAddActionWithTimeout(c *gc.C) {
+	action, err := s.unit.AddActionWithOptions("snapshot", nil, state.ActionOptions{
+		Timeout: 10 * time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, 10*time.Minute)
+
+	action, err = s.State.Action(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, 10*time.Minute)
+
+	_, err = s.unit.AddActionWithOptions("snapshot", nil, state.ActionOptions{
+		Timeout: -time.Second,
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid timeout -1s")
+}
+
+func (s *ActionSuite) TestAddActionCharmTimeout(c *gc.C) {
+	ch := s.AddActionsCharm(c, "dummy", `
+backup:
+  description: Back up the database.
+  params:
+    timeout:
+      type: string
+      default: 1h
+snapshot:
+  description: Take a snapshot of the database.
+  params:
+    timeout:
+      type: string
+      default: never
+restore:
+  description: Restore the database.
+  params:
+    timeout:
+      type: integer
+      default: 30
+`, 2)
+	svc := s.AddTestingService(c, "timed", ch)
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	action, err := unit.AddAction("backup", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, time.Hour)
+
+	action, err = unit.AddActionWithOptions("backup", nil, state.ActionOptions{
+		Timeout: time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, time.Minute)
+
+	_, err = unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.ErrorMatches, `action "snapshot": invalid timeout "never"`)
+
+	// A timeout parameter that isn't a duration string is left to
+	// the charm.
+	action, err = unit.AddAction("restore", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, time.Duration(0))
+}
+
+func (s *ActionSuite) TestSetActionLimit(c *gc.C) {
+	c.Assert(s.service.ActionLimits(), gc.HasLen, 0)
+
+	err := s.service.SetActionLimit("snapshot", 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.ActionLimits(), jc.DeepEquals, map[string]int{"snapshot": 2})
+
+	err = s.service.SetActionLimit("snapshot", 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.ActionLimits(), gc.HasLen, 0)
+
+	err = s.service.SetActionLimit("snapshot", -1)
+	c.Assert(err, gc.ErrorMatches, `cannot set limit for action "snapshot" on service "dummy": cannot set a negative limit`)
+
+	err = s.service.SetActionLimit("missing", 1)
+	c.Assert(err, gc.ErrorMatches, `cannot set limit for action "missing" on service "dummy": action "missing" not defined`)
+}
+
+func (s *ActionSuite) TestActionLimit(c *gc.C) {
+	err := s.service.SetActionLimit("snapshot", 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	action1, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action1.Status(), gc.Equals, state.ActionPending)
+	action2, err := s.unit2.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action2.Status(), gc.Equals, state.ActionWaiting)
+	action3, err := s.unit2.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action3.Status(), gc.Equals, state.ActionWaiting)
+
+	// Waiting actions are not passed on to their units, but are
+	// listed as pending.
+	s.assertNotifications(c, s.unit2)
+	pending, err := s.unit2.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 2)
+
+	// Cancelling a waiting action does not free up a slot.
+	_, err = action3.Cancel()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNotifications(c, s.unit2)
+
+	// Once the running action finishes, the waiting one is passed on.
+	action1, err = action1.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action1.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	action2, err = s.State.Action(action2.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action2.Status(), gc.Equals, state.ActionPending)
+	s.assertNotifications(c, s.unit2, action2.Id())
+}
+
+func (s *ActionSuite) TestActionLimitLeavesServiceUnchanged(c *gc.C) {
+	err := s.service.SetActionLimit("snapshot", 1)
+	c.Assert(err, jc.ErrorIsNil)
+	w := s.service.Watch()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Running limited actions doesn't change the service.
+	action1, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action2, err := s.unit2.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action2.Status(), gc.Equals, state.ActionWaiting)
+	action1, err = action1.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action1.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	action2, err = s.State.Action(action2.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action2.Status(), gc.Equals, state.ActionPending)
+}
+
+func (s *ActionSuite) TestRaiseActionLimit(c *gc.C) {
+	err := s.service.SetActionLimit("snapshot", 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action2, err := s.unit2.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action2.Status(), gc.Equals, state.ActionWaiting)
+
+	err = s.service.SetActionLimit("snapshot", 0)
+	c.Assert(err, jc.ErrorIsNil)
+	action2, err = s.State.Action(action2.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action2.Status(), gc.Equals, state.ActionPending)
+	s.assertNotifications(c, s.unit2, action2.Id())
+}
+
+// assertNotifications checks that the given unit has been notified of
+// exactly the actions with the given ids.
+func (s *ActionSuite) assertNotifications(c *gc.C, unit *state.Unit, ids ...string) {
+	w := unit.WatchActionNotifications()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange(ids...)
+	wc.AssertNoChange()
+}
+
 func (s *ActionSuite) TestAddActionAcceptsDuplicateNames(c *gc.C) {
 	name := "snapshot"
 	params1 := map[string]interface{}{"outfile": "outfile.tar.bz2"}
@@ -463,6 +806,84 @@ func (s *ActionSuite) TestComplete(c *gc.C) {
 	c.Assert(len(actions), gc.Equals, 0)
 }
 
+func (s *ActionSuite) TestCancelPending(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	a, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := a.Cancel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Status(), gc.Equals, state.ActionCancelled)
+	_, message := result.Results()
+	c.Assert(message, gc.Equals, "action cancelled")
+
+	actions, err := unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 0)
+}
+
+func (s *ActionSuite) TestCancelRunning(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	a, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	a, err = a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := unit.WatchActionNotifications()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange(a.Id())
+	wc.AssertNoChange()
+
+	result, err := a.Cancel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Status(), gc.Equals, state.ActionCancelling)
+
+	// The unit is notified of the cancellation, and the action is
+	// still reported as running until the unit finishes it.
+	wc.AssertChange(a.Id())
+	wc.AssertNoChange()
+	running, err := unit.RunningActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running, gc.HasLen, 1)
+
+	// Cancelling again has no further effect.
+	result, err = a.Cancel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Status(), gc.Equals, state.ActionCancelling)
+
+	output := map[string]interface{}{"partial": "output"}
+	result, err = result.Finish(state.ActionResults{
+		Status:  state.ActionCancelled,
+		Results: output,
+		Message: "action cancelled",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Status(), gc.Equals, state.ActionCancelled)
+	results, _ := result.Results()
+	c.Assert(results, jc.DeepEquals, output)
+}
+
+func (s *ActionSuite) TestCancelFinished(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	a, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = a.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = a.Cancel()
+	c.Assert(err, gc.ErrorMatches, "cannot cancel action .*: action .* has already finished")
+}
+
 func (s *ActionSuite) TestFindActionTagsByPrefix(c *gc.C) {
 	prefix := "feedbeef"
 	uuidMock := uuidMockHelper{}
@@ -859,6 +1280,9 @@ var _ state.ActionReceiver = (*mockAR)(nil)
 func (r mockAR) AddAction(name string, payload map[string]interface{}) (*state.Action, error) {
 	return nil, nil
 }
+func (r mockAR) AddActionWithOptions(name string, payload map[string]interface{}, opts state.ActionOptions) (*state.Action, error) {
+	return nil, nil
+}
 func (r mockAR) CancelAction(*state.Action) (*state.Action, error) { return nil, nil }
 func (r mockAR) WatchActionNotifications() state.StringsWatcher    { return nil }
 func (r mockAR) Actions() ([]*state.Action, error)                 { return nil, nil }
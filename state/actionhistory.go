@@ -0,0 +1,198 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// finishedActionStatuses holds the statuses of actions that have
+// finished, and so form the environment's action history.
+var finishedActionStatuses = []ActionStatus{
+	ActionCompleted,
+	ActionCancelled,
+	ActionFailed,
+}
+
+// ActionFilter holds the criteria by which actions are selected by
+// FilterActions. Empty fields do not restrict the actions selected.
+type ActionFilter struct {
+	// Units and Services restrict the actions to those queued on
+	// the named units, or on any unit of the named services.
+	Units    []string
+	Services []string
+
+	// Names restricts the actions to those with the given names.
+	Names []string
+
+	// Statuses restricts the actions to those with the given
+	// statuses.
+	Statuses []ActionStatus
+
+	// After and Before restrict the actions to those enqueued in the
+	// given time range.
+	After  time.Time
+	Before time.Time
+
+	// Offset skips the given number of the most recently enqueued
+	// matching actions, and Limit restricts the number of actions
+	// returned; together they allow results to be paged through.
+	Offset int
+	Limit  int
+}
+
+// FilterActions returns the actions in the environment that match the
+// given filter, most recently enqueued first.
+func (st *State) FilterActions(filter ActionFilter) ([]*Action, error) {
+	if filter.Offset < 0 || filter.Limit < 0 {
+		return nil, errors.New("offset and limit must not be negative")
+	}
+	actions, closer := st.getCollection(actionsC)
+	defer closer()
+
+	sel := bson.D{}
+	var receivers []bson.D
+	if len(filter.Units) > 0 {
+		receivers = append(receivers, bson.D{{"receiver", bson.D{{"$in", filter.Units}}}})
+	}
+	for _, service := range filter.Services {
+		receivers = append(receivers, bson.D{{"receiver", bson.D{
+			{"$regex", "^" + regexp.QuoteMeta(service+"/")},
+		}}})
+	}
+	if len(receivers) > 0 {
+		sel = append(sel, bson.DocElem{"$or", receivers})
+	}
+	if len(filter.Names) > 0 {
+		sel = append(sel, bson.DocElem{"name", bson.D{{"$in", filter.Names}}})
+	}
+	if len(filter.Statuses) > 0 {
+		sel = append(sel, bson.DocElem{"status", bson.D{{"$in", filter.Statuses}}})
+	}
+	timeSel := bson.M{}
+	if !filter.After.IsZero() {
+		timeSel["$gte"] = filter.After.UTC()
+	}
+	if !filter.Before.IsZero() {
+		timeSel["$lt"] = filter.Before.UTC()
+	}
+	if len(timeSel) > 0 {
+		sel = append(sel, bson.DocElem{"enqueued", timeSel})
+	}
+
+	query := actions.Find(sel).Sort("-enqueued", "-_id").Skip(filter.Offset)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var docs []actionDoc
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get actions")
+	}
+	results := make([]*Action, len(docs))
+	for i, doc := range docs {
+		results[i] = newAction(st, doc)
+	}
+	return results, nil
+}
+
+// pruneActionsBatchSize is the maximum number of actions removed in
+// a single transaction when pruning the action history.
+var pruneActionsBatchSize = 100
+
+// PruneActions removes finished actions from the environment's action
+// history, in order to control its size. All actions that finished
+// before minTime are removed. Further removal is performed, oldest
+// first, if more than maxActions finished actions remain; a maxActions
+// of 0 means that there is no limit.
+func PruneActions(st *State, minTime time.Time, maxActions int) error {
+	actions, closer := st.getCollection(actionsC)
+	defer closer()
+
+	finished := bson.D{{"status", bson.D{{"$in", finishedActionStatuses}}}}
+	removed, err := removeFinishedActions(st, append(finished,
+		bson.DocElem{"completed", bson.D{{"$lt", minTime.UTC()}}},
+	))
+	if err != nil {
+		return errors.Annotate(err, "cannot prune actions by time")
+	}
+
+	if maxActions > 0 {
+		var oldest actionDoc
+		err := actions.Find(finished).
+			Sort("-completed", "-_id").
+			Skip(maxActions).
+			Select(bson.D{{"completed", 1}}).
+			One(&oldest)
+		if err != nil && err != mgo.ErrNotFound {
+			return errors.Annotate(err, "cannot find oldest action to keep")
+		}
+		if err == nil {
+			count, err := removeFinishedActions(st, append(finished,
+				bson.DocElem{"$or", []bson.D{
+					{{"completed", bson.D{{"$lt", oldest.Completed}}}},
+					{{"completed", oldest.Completed}, {"_id", bson.D{{"$lte", oldest.DocId}}}},
+				}},
+			))
+			removed += count
+			if err != nil {
+				return errors.Annotate(err, "cannot prune actions by count")
+			}
+		}
+	}
+	if removed > 0 {
+		logger.Debugf("pruned %d actions for environment %s", removed, st.EnvironUUID())
+	}
+	return nil
+}
+
+// removeFinishedActions removes the finished actions matching the
+// given selector, in batches, asserting that each is still finished.
+// It returns the number of actions removed.
+func removeFinishedActions(st *State, sel bson.D) (int, error) {
+	actions, closer := st.getCollection(actionsC)
+	defer closer()
+
+	removed := 0
+	for {
+		var batch int
+		buildTxn := func(attempt int) ([]txn.Op, error) {
+			var docs []actionDoc
+			err := actions.Find(sel).
+				Select(bson.D{{"_id", 1}}).
+				Limit(pruneActionsBatchSize).
+				All(&docs)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			batch = len(docs)
+			if batch == 0 {
+				return nil, jujutxn.ErrNoOperations
+			}
+			ops := make([]txn.Op, len(docs))
+			for i, doc := range docs {
+				ops[i] = txn.Op{
+					C:      actionsC,
+					Id:     doc.DocId,
+					Assert: bson.D{{"status", bson.D{{"$in", finishedActionStatuses}}}},
+					Remove: true,
+				}
+			}
+			return ops, nil
+		}
+		if err := st.run(buildTxn); err != nil {
+			return removed, errors.Trace(err)
+		}
+		if batch == 0 {
+			return removed, nil
+		}
+		removed += batch
+	}
+}
@@ -0,0 +1,322 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// activeActionStatuses holds the statuses of actions that count towards
+// a service's action limits.
+var activeActionStatuses = []ActionStatus{
+	ActionPending,
+	ActionRunning,
+	ActionCancelling,
+}
+
+// ActionLimits returns the maximum number of the service's units that
+// may run each limited action at once, by action name.
+func (s *Service) ActionLimits() map[string]int {
+	limits := make(map[string]int, len(s.doc.ActionLimits))
+	for name, limit := range s.doc.ActionLimits {
+		limits[name] = limit
+	}
+	return limits
+}
+
+// SetActionLimit sets the maximum number of the service's units that
+// may run the named action at once. Actions queued beyond the limit
+// have status ActionWaiting, and are passed on to their units as
+// earlier ones finish. A limit of 0 removes any limit.
+func (s *Service) SetActionLimit(name string, limit int) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set limit for action %q on service %q", name, s)
+	if limit < 0 {
+		return errors.New("cannot set a negative limit")
+	}
+	service := &Service{st: s.st, doc: s.doc}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := service.Refresh(); err != nil {
+				return nil, err
+			}
+		}
+		if service.doc.Life != Alive {
+			return nil, errors.New("service is no longer alive")
+		}
+		if limit == service.doc.ActionLimits[name] {
+			return nil, jujutxn.ErrNoOperations
+		}
+		ch, _, err := service.Charm()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var specs ActionSpecsByName
+		if actions := ch.Actions(); actions != nil {
+			specs = actions.ActionSpecs
+		}
+		if _, ok := specs[name]; !ok {
+			return nil, errors.Errorf("action %q not defined", name)
+		}
+		update := bson.D{{"$set", bson.D{{"actionlimits." + name, limit}}}}
+		if limit == 0 {
+			update = bson.D{{"$unset", bson.D{{"actionlimits." + name, nil}}}}
+		}
+		ops := []txn.Op{{
+			C:      servicesC,
+			Id:     service.doc.DocID,
+			Assert: bson.D{{"txn-revno", service.doc.TxnRevno}},
+			Update: update,
+		}}
+
+		// Pass on as many waiting actions as the new limit allows.
+		waiting, err := s.st.waitingServiceActions(service.doc.Name, name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		counter, err := s.st.actionLimitCounter(service.doc.Name, name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if limit > 0 {
+			active, err := s.st.countActiveServiceActions(service.doc.Name, name)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			free := limit - active
+			if free < 0 {
+				free = 0
+			}
+			if free < len(waiting) {
+				waiting = waiting[:free]
+			}
+			ops = append(ops, counter.setOp(active+len(waiting)))
+		} else {
+			ops = append(ops, counter.removeOp())
+		}
+		for _, doc := range waiting {
+			ops = append(ops, s.st.releaseActionOps(doc)...)
+		}
+		return ops, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return err
+	}
+	return s.Refresh()
+}
+
+// actionLimitCountDoc records how many actions with a given name are
+// pending or running on the units of a service that limits them. The
+// count is kept apart from the service document so that changes to
+// those actions can be serialised without touching the service.
+type actionLimitCountDoc struct {
+	DocId   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid"`
+	Service string `bson:"service"`
+	Action  string `bson:"action"`
+	Count   int    `bson:"count"`
+}
+
+// actionLimitCounter refers to the count document for an action limit,
+// as it was when read.
+type actionLimitCounter struct {
+	st      *State
+	service string
+	action  string
+	exists  bool
+	count   int
+}
+
+// actionLimitCountID returns the local id of the count document for
+// the named action limit of the service.
+func actionLimitCountID(serviceName, actionName string) string {
+	return serviceName + "#" + actionName
+}
+
+// actionLimitCounter returns the count document for the named action
+// limit of the service.
+func (st *State) actionLimitCounter(serviceName, actionName string) (*actionLimitCounter, error) {
+	counts, closer := st.getCollection(actionLimitCountsC)
+	defer closer()
+
+	counter := &actionLimitCounter{
+		st:      st,
+		service: serviceName,
+		action:  actionName,
+	}
+	var doc actionLimitCountDoc
+	err := counts.FindId(actionLimitCountID(serviceName, actionName)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return counter, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get count of %q actions on service %q", actionName, serviceName)
+	}
+	counter.exists = true
+	counter.count = doc.Count
+	return counter, nil
+}
+
+// setOp returns an operation that asserts that the count has not
+// changed since it was read, and then sets it to the given value.
+func (c *actionLimitCounter) setOp(count int) txn.Op {
+	id := c.st.docID(actionLimitCountID(c.service, c.action))
+	if !c.exists {
+		return txn.Op{
+			C:      actionLimitCountsC,
+			Id:     id,
+			Assert: txn.DocMissing,
+			Insert: &actionLimitCountDoc{
+				DocId:   id,
+				EnvUUID: c.st.EnvironUUID(),
+				Service: c.service,
+				Action:  c.action,
+				Count:   count,
+			},
+		}
+	}
+	return txn.Op{
+		C:      actionLimitCountsC,
+		Id:     id,
+		Assert: bson.D{{"count", c.count}},
+		Update: bson.D{{"$set", bson.D{{"count", count}}}},
+	}
+}
+
+// removeOp returns an operation that removes the count document.
+func (c *actionLimitCounter) removeOp() txn.Op {
+	return txn.Op{
+		C:      actionLimitCountsC,
+		Id:     c.st.docID(actionLimitCountID(c.service, c.action)),
+		Remove: true,
+	}
+}
+
+// serviceActionLimit holds the limit on how many units of a service
+// may run an action at once, along with the number of such actions
+// currently pending or running.
+type serviceActionLimit struct {
+	service string
+	action  string
+	limit   int
+	active  int
+	counter *actionLimitCounter
+}
+
+// ops returns the operations needed to serialise a change to the
+// service's active actions that leaves the given number of them
+// active. They assert that neither the limit nor the count of active
+// actions has changed, and then record the new count.
+func (l *serviceActionLimit) ops(active int) []txn.Op {
+	return []txn.Op{{
+		C:      servicesC,
+		Id:     l.counter.st.docID(l.service),
+		Assert: bson.D{{"actionlimits." + l.action, l.limit}},
+	}, l.counter.setOp(active)}
+}
+
+// actionLimit returns the limit on how many units of the service of the
+// given unit may run the named action at once, or nil if the receiver is
+// not a unit or there is no limit.
+func (st *State) actionLimit(receiver, actionName string) (*serviceActionLimit, error) {
+	if !names.IsValidUnit(receiver) {
+		return nil, nil
+	}
+	serviceName, err := names.UnitService(receiver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	services, closer := st.getCollection(servicesC)
+	defer closer()
+
+	var doc serviceDoc
+	err = services.FindId(serviceName).Select(bson.D{
+		{"actionlimits", 1},
+	}).One(&doc)
+	if err == mgo.ErrNotFound {
+		// The receiver's assertions take care of this.
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get service %q", serviceName)
+	}
+	limit := doc.ActionLimits[actionName]
+	if limit <= 0 {
+		return nil, nil
+	}
+	counter, err := st.actionLimitCounter(serviceName, actionName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	active, err := st.countActiveServiceActions(serviceName, actionName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &serviceActionLimit{
+		service: serviceName,
+		action:  actionName,
+		limit:   limit,
+		active:  active,
+		counter: counter,
+	}, nil
+}
+
+// serviceActionsSelector returns a selector for the actions with the
+// given name queued on units of the service, with the given statuses.
+func serviceActionsSelector(serviceName, actionName string, statuses ...ActionStatus) bson.D {
+	return bson.D{
+		{"receiver", bson.D{{"$regex", "^" + regexp.QuoteMeta(serviceName+"/")}}},
+		{"name", actionName},
+		{"status", bson.D{{"$in", statuses}}},
+	}
+}
+
+// countActiveServiceActions returns the number of actions with the
+// given name that are pending or running on units of the service.
+func (st *State) countActiveServiceActions(serviceName, actionName string) (int, error) {
+	actions, closer := st.getCollection(actionsC)
+	defer closer()
+
+	sel := serviceActionsSelector(serviceName, actionName, activeActionStatuses...)
+	count, err := actions.Find(sel).Count()
+	if err != nil {
+		return 0, errors.Annotatef(err, "cannot count %q actions on service %q", actionName, serviceName)
+	}
+	return count, nil
+}
+
+// waitingServiceActions returns the actions with the given name that are
+// waiting to be passed on to units of the service, oldest first.
+func (st *State) waitingServiceActions(serviceName, actionName string) ([]actionDoc, error) {
+	actions, closer := st.getCollection(actionsC)
+	defer closer()
+
+	var docs []actionDoc
+	sel := serviceActionsSelector(serviceName, actionName, ActionWaiting)
+	if err := actions.Find(sel).Sort("enqueued", "_id").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get waiting %q actions on service %q", actionName, serviceName)
+	}
+	return docs, nil
+}
+
+// releaseActionOps returns the operations needed to pass a waiting
+// action on to its receiver.
+func (st *State) releaseActionOps(doc actionDoc) []txn.Op {
+	ndoc := newActionNotificationDoc(st, doc.Receiver, st.localID(doc.DocId))
+	return []txn.Op{{
+		C:      actionsC,
+		Id:     doc.DocId,
+		Assert: bson.D{{"status", ActionWaiting}},
+		Update: bson.D{{"$set", bson.D{{"status", ActionPending}}}},
+	}, {
+		C:      actionNotificationsC,
+		Id:     ndoc.DocId,
+		Assert: txn.DocMissing,
+		Insert: ndoc,
+	}}
+}
@@ -0,0 +1,375 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/utils/cron"
+)
+
+// actionScheduleDoc records an action to be enqueued repeatedly, on a
+// unit or on each unit of a service, according to a cron-like
+// schedule.
+type actionScheduleDoc struct {
+	DocId   string `bson:"_id"`
+	Id      string `bson:"id"`
+	EnvUUID string `bson:"env-uuid"`
+
+	// Receiver holds the tag of the unit or service on which the
+	// action is enqueued.
+	Receiver   string                 `bson:"receiver"`
+	Name       string                 `bson:"name"`
+	Parameters map[string]interface{} `bson:"parameters"`
+	Schedule   string                 `bson:"schedule"`
+
+	Created time.Time `bson:"created"`
+	LastRun time.Time `bson:"last-run"`
+	NextRun time.Time `bson:"next-run"`
+
+	// MissedRuns holds the total number of runs of the schedule
+	// that were missed, because the schedule was not run in time.
+	MissedRuns int `bson:"missed-runs"`
+}
+
+// ActionScheduleArgs holds the arguments for adding an action schedule.
+type ActionScheduleArgs struct {
+	// Receiver is the unit, or the service on each of whose units,
+	// the action is enqueued.
+	Receiver names.Tag
+
+	// Name and Parameters are the name and parameters of the
+	// action enqueued.
+	Name       string
+	Parameters map[string]interface{}
+
+	// Schedule is a cron-like expression giving the times at
+	// which the action is enqueued; see package utils/cron.
+	Schedule string
+}
+
+// ActionSchedule represents an action that is enqueued repeatedly,
+// according to a schedule.
+type ActionSchedule struct {
+	st  *State
+	doc actionScheduleDoc
+}
+
+// Id returns the id of the schedule.
+func (s *ActionSchedule) Id() string {
+	return s.doc.Id
+}
+
+// Receiver returns the tag of the unit, or the service on each of
+// whose units, the action is enqueued.
+func (s *ActionSchedule) Receiver() (names.Tag, error) {
+	tag, err := names.ParseTag(s.doc.Receiver)
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid receiver for schedule %q", s.doc.Id)
+	}
+	return tag, nil
+}
+
+// Name returns the name of the action enqueued.
+func (s *ActionSchedule) Name() string {
+	return s.doc.Name
+}
+
+// Parameters returns the parameters of the action enqueued.
+func (s *ActionSchedule) Parameters() map[string]interface{} {
+	return s.doc.Parameters
+}
+
+// Schedule returns the expression giving the times at which the
+// action is enqueued.
+func (s *ActionSchedule) Schedule() string {
+	return s.doc.Schedule
+}
+
+// Created returns the time at which the schedule was added.
+func (s *ActionSchedule) Created() time.Time {
+	return s.doc.Created.UTC()
+}
+
+// LastRun returns the time at which the action was last enqueued, or
+// the zero time if it has never been.
+func (s *ActionSchedule) LastRun() time.Time {
+	return s.doc.LastRun.UTC()
+}
+
+// NextRun returns the time at which the action is next due to be
+// enqueued.
+func (s *ActionSchedule) NextRun() time.Time {
+	return s.doc.NextRun.UTC()
+}
+
+// MissedRuns returns the total number of runs of the schedule that
+// were missed.
+func (s *ActionSchedule) MissedRuns() int {
+	return s.doc.MissedRuns
+}
+
+// AddActionSchedule adds a schedule on which the given action is
+// enqueued repeatedly. Schedules are run by the action scheduler
+// worker, with Run.
+func (st *State) AddActionSchedule(args ActionScheduleArgs) (_ *ActionSchedule, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add schedule for action %q", args.Name)
+	schedule, err := cron.Parse(args.Schedule)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	now := nowToTheSecond()
+	next := schedule.Next(now)
+	if next.IsZero() {
+		return nil, errors.Errorf("schedule %q never fires", args.Schedule)
+	}
+
+	var receiverColl, receiverId string
+	var specs ActionSpecsByName
+	switch tag := args.Receiver.(type) {
+	case names.UnitTag:
+		unit, err := st.Unit(tag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if specs, err = unit.ActionSpecs(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		receiverColl, receiverId = unitsC, unit.doc.DocID
+	case names.ServiceTag:
+		service, err := st.Service(tag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ch, _, err := service.Charm()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if actions := ch.Actions(); actions != nil {
+			specs = actions.ActionSpecs
+		}
+		receiverColl, receiverId = servicesC, service.doc.DocID
+	default:
+		return nil, errors.NotValidf("action receiver %q", args.Receiver)
+	}
+	spec, ok := specs[args.Name]
+	if !ok {
+		return nil, errors.Errorf("action %q not defined", args.Name)
+	}
+	if err := spec.ValidateParams(args.Parameters); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	seq, err := st.sequence("actionschedule")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	id := strconv.Itoa(seq)
+	doc := actionScheduleDoc{
+		DocId:      st.docID(id),
+		Id:         id,
+		EnvUUID:    st.EnvironUUID(),
+		Receiver:   args.Receiver.String(),
+		Name:       args.Name,
+		Parameters: args.Parameters,
+		Schedule:   args.Schedule,
+		Created:    now,
+		NextRun:    next,
+	}
+	ops := []txn.Op{{
+		C:      receiverColl,
+		Id:     receiverId,
+		Assert: isAliveDoc,
+	}, {
+		C:      actionSchedulesC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.Errorf("%s is no longer alive", names.ReadableString(args.Receiver))
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ActionSchedule{st: st, doc: doc}, nil
+}
+
+// ActionSchedule returns the action schedule with the given id.
+func (st *State) ActionSchedule(id string) (*ActionSchedule, error) {
+	schedules, closer := st.getCollection(actionSchedulesC)
+	defer closer()
+
+	var doc actionScheduleDoc
+	err := schedules.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("action schedule %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get action schedule %q", id)
+	}
+	return &ActionSchedule{st: st, doc: doc}, nil
+}
+
+// AllActionSchedules returns all of the environment's action schedules,
+// in the order in which they are next due to run.
+func (st *State) AllActionSchedules() ([]*ActionSchedule, error) {
+	return st.actionSchedules(nil)
+}
+
+// DueActionSchedules returns the action schedules that are due to be
+// run at the given time, in the order in which they fell due.
+func (st *State) DueActionSchedules(now time.Time) ([]*ActionSchedule, error) {
+	return st.actionSchedules(bson.D{{"next-run", bson.D{{"$lte", now.UTC()}}}})
+}
+
+func (st *State) actionSchedules(sel bson.D) ([]*ActionSchedule, error) {
+	schedules, closer := st.getCollection(actionSchedulesC)
+	defer closer()
+
+	var docs []actionScheduleDoc
+	if err := schedules.Find(sel).Sort("next-run", "created", "_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get action schedules")
+	}
+	results := make([]*ActionSchedule, len(docs))
+	for i, doc := range docs {
+		results[i] = &ActionSchedule{st: st, doc: doc}
+	}
+	return results, nil
+}
+
+// RemoveActionSchedule removes the action schedule with the given id.
+// Actions it has already enqueued are unaffected.
+func (st *State) RemoveActionSchedule(id string) error {
+	ops := []txn.Op{{
+		C:      actionSchedulesC,
+		Id:     st.docID(id),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("action schedule %q", id)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot remove action schedule %q", id)
+	}
+	return nil
+}
+
+// Run enqueues the scheduled action, if it is due at the given time,
+// and records when it is next due.
+//
+// Runs that were missed, because the schedule was not run at the time
+// they fell due, are not made up: a single action is enqueued, for the
+// most recent run due, and the number of runs missed before it is
+// recorded, both on the action and on the schedule.
+//
+// If the schedule's receiver no longer exists, the schedule is removed,
+// and an error satisfying errors.IsNotFound is returned.
+func (s *ActionSchedule) Run(now time.Time) (_ []*Action, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot run action schedule %q", s.doc.Id)
+	schedule, err := cron.Parse(s.doc.Schedule)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	now = now.UTC()
+	due := s.NextRun()
+	if due.After(now) {
+		return nil, errors.Errorf("not due until %v", due)
+	}
+	missed := 0
+	for {
+		next := schedule.Next(due)
+		if next.IsZero() || next.After(now) {
+			break
+		}
+		due = next
+		missed++
+	}
+
+	receiver, err := s.Receiver()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := s.units(receiver)
+	if errors.IsNotFound(err) {
+		if err := s.st.RemoveActionSchedule(s.doc.Id); err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		return nil, err
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Record the run before enqueueing its actions, so that each run
+	// is only made once, even if the schedule is run concurrently.
+	ops := []txn.Op{{
+		C:      actionSchedulesC,
+		Id:     s.doc.DocId,
+		Assert: bson.D{{"next-run", s.doc.NextRun}},
+		Update: bson.D{
+			{"$set", bson.D{
+				{"last-run", now},
+				{"next-run", schedule.Next(now)},
+			}},
+			{"$inc", bson.D{{"missed-runs", missed}}},
+		},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.New("schedule has already been run, or removed")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	opts := ActionOptions{
+		Schedule:   s.doc.Id,
+		MissedRuns: missed,
+	}
+	if _, ok := receiver.(names.ServiceTag); ok {
+		operation, err := utils.NewUUID()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		opts.Operation = operation.String()
+	}
+	var actions []*Action
+	var errs []error
+	for _, unit := range units {
+		action, err := unit.AddActionWithOptions(s.doc.Name, s.doc.Parameters, opts)
+		if err != nil {
+			errs = append(errs, errors.Annotatef(err, "unit %q", unit.Name()))
+			continue
+		}
+		actions = append(actions, action)
+	}
+	if len(errs) > 0 {
+		return actions, errs[0]
+	}
+	return actions, nil
+}
+
+// units returns the units on which the scheduled action is enqueued,
+// given the schedule's receiver.
+func (s *ActionSchedule) units(receiver names.Tag) ([]*Unit, error) {
+	switch tag := receiver.(type) {
+	case names.UnitTag:
+		unit, err := s.st.Unit(tag.Id())
+		if err != nil {
+			return nil, err
+		}
+		return []*Unit{unit}, nil
+	case names.ServiceTag:
+		service, err := s.st.Service(tag.Id())
+		if err != nil {
+			return nil, err
+		}
+		return service.AllUnits()
+	}
+	return nil, errors.Errorf("unexpected receiver %q", s.doc.Receiver)
+}
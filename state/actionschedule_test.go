@@ -0,0 +1,268 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+)
+
+type ActionScheduleSuite struct {
+	ConnSuite
+	service *state.Service
+	unit    *state.Unit
+	unit2   *state.Unit
+}
+
+var _ = gc.Suite(&ActionScheduleSuite{})
+
+func (s *ActionScheduleSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	ch := s.AddTestingCharm(c, "dummy")
+	s.service = s.AddTestingService(c, "dummy", ch)
+	s.unit = s.addUnit(c)
+	s.unit2 = s.addUnit(c)
+}
+
+func (s *ActionScheduleSuite) addUnit(c *gc.C) *state.Unit {
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := s.service.CharmURL()
+	err = unit.SetCharmURL(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	return unit
+}
+
+func (s *ActionScheduleSuite) addSchedule(c *gc.C, receiver names.Tag, schedule string) *state.ActionSchedule {
+	sched, err := s.State.AddActionSchedule(state.ActionScheduleArgs{
+		Receiver:   receiver,
+		Name:       "snapshot",
+		Parameters: map[string]interface{}{"outfile": "out.bz2"},
+		Schedule:   schedule,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return sched
+}
+
+func (s *ActionScheduleSuite) TestAddActionSchedule(c *gc.C) {
+	before := time.Now().UTC()
+	sched := s.addSchedule(c, s.unit.UnitTag(), "@hourly")
+	receiver, err := sched.Receiver()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(receiver, gc.Equals, s.unit.UnitTag())
+	c.Assert(sched.Name(), gc.Equals, "snapshot")
+	c.Assert(sched.Parameters(), jc.DeepEquals, map[string]interface{}{"outfile": "out.bz2"})
+	c.Assert(sched.Schedule(), gc.Equals, "@hourly")
+	c.Assert(sched.LastRun().IsZero(), jc.IsTrue)
+	c.Assert(sched.NextRun().After(before), jc.IsTrue)
+	c.Assert(sched.NextRun().Minute(), gc.Equals, 0)
+	c.Assert(sched.MissedRuns(), gc.Equals, 0)
+
+	got, err := s.State.ActionSchedule(sched.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	receiver, err = got.Receiver()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(receiver, gc.Equals, s.unit.UnitTag())
+	c.Assert(got.NextRun(), gc.Equals, sched.NextRun())
+}
+
+func (s *ActionScheduleSuite) TestReceiverInvalid(c *gc.C) {
+	sched := s.addSchedule(c, s.unit.UnitTag(), "@hourly")
+	schedules := s.State.MongoSession().DB("juju").C("actionschedules")
+	err := schedules.UpdateId(s.State.EnvironUUID()+":"+sched.Id(), bson.D{
+		{"$set", bson.D{{"receiver", "bad"}}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	sched, err = s.State.ActionSchedule(sched.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = sched.Receiver()
+	c.Assert(err, gc.ErrorMatches, `invalid receiver for schedule ".*": "bad" is not a valid tag`)
+	_, err = sched.Run(time.Now().Add(2 * time.Hour))
+	c.Assert(err, gc.ErrorMatches, `invalid receiver for schedule ".*": "bad" is not a valid tag`)
+}
+
+func (s *ActionScheduleSuite) TestAddActionScheduleErrors(c *gc.C) {
+	for i, test := range []struct {
+		args        state.ActionScheduleArgs
+		expectedErr string
+	}{{
+		args: state.ActionScheduleArgs{
+			Receiver: s.unit.UnitTag(),
+			Name:     "snapshot",
+			Schedule: "0 0 * *",
+		},
+		expectedErr: `cannot add schedule for action "snapshot": expected 5 fields in schedule "0 0 \* \*", got 4`,
+	}, {
+		args: state.ActionScheduleArgs{
+			Receiver: s.unit.UnitTag(),
+			Name:     "snapshot",
+			Schedule: "0 0 30 2 *",
+		},
+		expectedErr: `cannot add schedule for action "snapshot": schedule "0 0 30 2 \*" never fires`,
+	}, {
+		args: state.ActionScheduleArgs{
+			Receiver: s.unit.UnitTag(),
+			Name:     "backup",
+			Schedule: "@daily",
+		},
+		expectedErr: `cannot add schedule for action "backup": action "backup" not defined`,
+	}, {
+		args: state.ActionScheduleArgs{
+			Receiver:   s.service.ServiceTag(),
+			Name:       "snapshot",
+			Parameters: map[string]interface{}{"outfile": 5},
+			Schedule:   "@daily",
+		},
+		expectedErr: `cannot add schedule for action "snapshot": validation failed: .*`,
+	}, {
+		args: state.ActionScheduleArgs{
+			Receiver: names.NewServiceTag("missing"),
+			Name:     "snapshot",
+			Schedule: "@daily",
+		},
+		expectedErr: `cannot add schedule for action "snapshot": service "missing" not found`,
+	}, {
+		args: state.ActionScheduleArgs{
+			Receiver: names.NewMachineTag("0"),
+			Name:     "snapshot",
+			Schedule: "@daily",
+		},
+		expectedErr: `cannot add schedule for action "snapshot": action receiver "machine-0" not valid`,
+	}} {
+		c.Logf("test %d: %s", i, test.expectedErr)
+		_, err := s.State.AddActionSchedule(test.args)
+		c.Check(err, gc.ErrorMatches, test.expectedErr)
+	}
+	schedules, err := s.State.AllActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedules, gc.HasLen, 0)
+}
+
+func (s *ActionScheduleSuite) TestRemoveActionSchedule(c *gc.C) {
+	sched := s.addSchedule(c, s.unit.UnitTag(), "@daily")
+	err := s.State.RemoveActionSchedule(sched.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ActionSchedule(sched.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveActionSchedule(sched.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionScheduleSuite) TestDueActionSchedules(c *gc.C) {
+	hourly := s.addSchedule(c, s.unit.UnitTag(), "@hourly")
+	daily := s.addSchedule(c, s.unit.UnitTag(), "@daily")
+
+	due, err := s.State.DueActionSchedules(hourly.NextRun().Add(-time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due, gc.HasLen, 0)
+
+	due, err = s.State.DueActionSchedules(hourly.NextRun())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due, gc.HasLen, 1)
+	c.Assert(due[0].Id(), gc.Equals, hourly.Id())
+
+	due, err = s.State.DueActionSchedules(daily.NextRun().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due, gc.HasLen, 2)
+}
+
+func (s *ActionScheduleSuite) TestRunUnitSchedule(c *gc.C) {
+	sched := s.addSchedule(c, s.unit.UnitTag(), "@hourly")
+	first := sched.NextRun()
+
+	_, err := sched.Run(first.Add(-time.Minute))
+	c.Assert(err, gc.ErrorMatches, `cannot run action schedule "\d+": not due until .*`)
+
+	actions, err := sched.Run(first.Add(10 * time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Receiver(), gc.Equals, s.unit.Name())
+	c.Assert(actions[0].Name(), gc.Equals, "snapshot")
+	c.Assert(actions[0].Schedule(), gc.Equals, sched.Id())
+	c.Assert(actions[0].MissedRuns(), gc.Equals, 0)
+	c.Assert(actions[0].Operation(), gc.Equals, "")
+
+	sched, err = s.State.ActionSchedule(sched.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sched.LastRun(), gc.Equals, first.Add(10*time.Minute))
+	c.Assert(sched.NextRun(), gc.Equals, first.Add(time.Hour))
+	c.Assert(sched.MissedRuns(), gc.Equals, 0)
+}
+
+func (s *ActionScheduleSuite) TestRunCountsMissedRuns(c *gc.C) {
+	sched := s.addSchedule(c, s.unit.UnitTag(), "@hourly")
+	first := sched.NextRun()
+
+	// The runs due at first, first+1h and first+2h are missed; only
+	// the one due at first+3h is made.
+	now := first.Add(3*time.Hour + 30*time.Minute)
+	actions, err := sched.Run(now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].MissedRuns(), gc.Equals, 3)
+
+	sched, err = s.State.ActionSchedule(sched.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sched.NextRun(), gc.Equals, first.Add(4*time.Hour))
+	c.Assert(sched.MissedRuns(), gc.Equals, 3)
+
+	actions, err = sched.Run(first.Add(4 * time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions[0].MissedRuns(), gc.Equals, 0)
+	sched, err = s.State.ActionSchedule(sched.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sched.MissedRuns(), gc.Equals, 3)
+}
+
+func (s *ActionScheduleSuite) TestRunOnlyOnce(c *gc.C) {
+	sched := s.addSchedule(c, s.unit.UnitTag(), "@hourly")
+	stale, err := s.State.ActionSchedule(sched.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = sched.Run(sched.NextRun())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = stale.Run(stale.NextRun())
+	c.Assert(err, gc.ErrorMatches, `cannot run action schedule "\d+": schedule has already been run, or removed`)
+
+	actions, err := s.unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+}
+
+func (s *ActionScheduleSuite) TestRunServiceSchedule(c *gc.C) {
+	sched := s.addSchedule(c, s.service.ServiceTag(), "*/15 * * * *")
+	actions, err := sched.Run(sched.NextRun())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 2)
+	c.Assert(actions[0].Receiver(), gc.Equals, s.unit.Name())
+	c.Assert(actions[1].Receiver(), gc.Equals, s.unit2.Name())
+	c.Assert(actions[0].Operation(), gc.Not(gc.Equals), "")
+	c.Assert(actions[1].Operation(), gc.Equals, actions[0].Operation())
+	for _, action := range actions {
+		c.Assert(action.Parameters(), jc.DeepEquals, map[string]interface{}{"outfile": "out.bz2"})
+		c.Assert(action.Schedule(), gc.Equals, sched.Id())
+	}
+}
+
+func (s *ActionScheduleSuite) TestRunRemovesScheduleForMissingReceiver(c *gc.C) {
+	sched := s.addSchedule(c, s.unit.UnitTag(), "@hourly")
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = sched.Run(sched.NextRun())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.ActionSchedule(sched.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -362,6 +362,9 @@ func (st *State) addMachineInsideMachineOps(template MachineTemplate, parentId s
 	if !parent.supportsContainerType(containerType) {
 		return nil, nil, errors.Errorf("machine %s cannot host %s containers", parentId, containerType)
 	}
+	if parent.doc.UpgradeSeries != nil {
+		return nil, nil, errUpgradingSeries(parent)
+	}
 	newId, err := st.newContainerId(parentId, containerType)
 	if err != nil {
 		return nil, nil, err
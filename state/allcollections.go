@@ -198,6 +198,20 @@ func allCollections() collectionSchema {
 		},
 		relationScopesC: {},
 
+		// This collection holds the payloads registered by units.
+		payloadsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "unitid"},
+			}},
+		},
+
+		// This collection holds the resources attached to services.
+		resourcesC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "service"},
+			}},
+		},
+
 		// -----
 
 		// These collections hold information associated with machines.
@@ -286,8 +300,24 @@ func allCollections() collectionSchema {
 		// -----
 
 		// These collections hold information associated with actions.
-		actionsC:             {},
+		actionsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "operation"},
+			}, {
+				Key: []string{"env-uuid", "name", "status"},
+			}, {
+				Key: []string{"env-uuid", "enqueued"},
+			}, {
+				Key: []string{"env-uuid", "status", "completed"},
+			}},
+		},
 		actionNotificationsC: {},
+		actionLimitCountsC:   {},
+		actionSchedulesC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "next-run"},
+			}},
+		},
 
 		// -----
 
@@ -324,6 +354,27 @@ func allCollections() collectionSchema {
 		// Raw-access collections
 		// ======================
 
+		// This collection holds the audit trail of API calls made by
+		// users. It is written on almost every call, and pruned in
+		// bulk, so it is not accessed via transactions.
+		auditEventsC: {
+			rawAccess: true,
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "time"},
+			}, {
+				Key: []string{"env-uuid", "user", "time"},
+			}, {
+				Key: []string{"env-uuid", "entities"},
+			}},
+		},
+
+		// This collection holds, for each log forwarding target, the
+		// position in the environment's logs up to which records have
+		// been forwarded. It is written for every record forwarded.
+		logForwardPositionsC: {
+			rawAccess: true,
+		},
+
 		// metrics; status-history; logs; ..?
 	}
 }
@@ -333,10 +384,13 @@ func allCollections() collectionSchema {
 // it in allCollections, above; and please keep this list sorted for easy
 // inspection.
 const (
+	actionLimitCountsC     = "actionlimitcounts"
 	actionNotificationsC   = "actionnotifications"
 	actionresultsC         = "actionresults"
 	actionsC               = "actions"
+	actionSchedulesC       = "actionschedules"
 	annotationsC           = "annotations"
+	auditEventsC           = "auditevents"
 	blockDevicesC          = "blockdevices"
 	blocksC                = "blocks"
 	charmsC                = "charms"
@@ -352,6 +406,7 @@ const (
 	ipaddressesC           = "ipaddresses"
 	leaseC                 = "lease"
 	leasesC                = "leases"
+	logForwardPositionsC   = "logforwardpositions"
 	machinesC              = "machines"
 	meterStatusC           = "meterStatus"
 	metricsC               = "metrics"
@@ -360,10 +415,12 @@ const (
 	networkInterfacesC     = "networkinterfaces"
 	networksC              = "networks"
 	openedPortsC           = "openedPorts"
+	payloadsC              = "payloads"
 	rebootC                = "reboot"
 	relationScopesC        = "relationscopes"
 	relationsC             = "relations"
 	requestedNetworksC     = "requestednetworks"
+	resourcesC             = "resources"
 	restoreInfoC           = "restoreInfo"
 	sequenceC              = "sequence"
 	servicesC              = "services"
@@ -0,0 +1,195 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/audit"
+)
+
+// auditEventDoc records a single API call made by a user against an
+// environment.
+type auditEventDoc struct {
+	Id            bson.ObjectId `bson:"_id"`
+	EnvUUID       string        `bson:"env-uuid"`
+	Time          time.Time     `bson:"time"`
+	User          string        `bson:"user"`
+	Facade        string        `bson:"facade"`
+	Version       int           `bson:"version"`
+	Method        string        `bson:"method"`
+	Args          string        `bson:"args,omitempty"`
+	Entities      []string      `bson:"entities,omitempty"`
+	Error         string        `bson:"error,omitempty"`
+	SourceAddress string        `bson:"source,omitempty"`
+}
+
+// AuditEventFilter specifies which audit events should be returned
+// by State.AuditEvents. Zero-valued fields are ignored.
+type AuditEventFilter struct {
+	// User restricts the events to those made by the user with
+	// this tag.
+	User string
+
+	// Entity restricts the events to those that refer to the
+	// entity with this tag.
+	Entity string
+
+	// After and Before restrict the events to those made in the
+	// given time range.
+	After  time.Time
+	Before time.Time
+
+	// Limit restricts the number of events returned to the most
+	// recent Limit events.
+	Limit int
+}
+
+// AddAuditEvent records the given event in the environment's audit
+// trail. The event's environment UUID is always set to that of the
+// environment.
+func (st *State) AddAuditEvent(event audit.Event) error {
+	if event.User == "" {
+		return errors.New("audit event user cannot be blank")
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	doc := &auditEventDoc{
+		Id:            bson.NewObjectId(),
+		EnvUUID:       st.EnvironUUID(),
+		Time:          event.Time.UTC(),
+		User:          event.User,
+		Facade:        event.Facade,
+		Version:       event.Version,
+		Method:        event.Method,
+		Args:          event.Args,
+		Entities:      event.Entities,
+		Error:         event.Error,
+		SourceAddress: event.SourceAddress,
+	}
+	events, closer := st.getCollection(auditEventsC)
+	defer closer()
+
+	// Audit events are written for almost every API call made by a
+	// user, so we don't wait for them to reach a majority of the
+	// replica set.
+	eventsW := events.Writeable()
+	session := eventsW.Underlying().Database.Session
+	session.SetSafe(&mgo.Safe{})
+	return errors.Trace(eventsW.Insert(doc))
+}
+
+// AuditEvents returns the audit events recorded for the environment
+// that match the given filter, oldest first.
+func (st *State) AuditEvents(filter AuditEventFilter) ([]audit.Event, error) {
+	events, closer := st.getCollection(auditEventsC)
+	defer closer()
+
+	sel := bson.D{}
+	if filter.User != "" {
+		sel = append(sel, bson.DocElem{"user", filter.User})
+	}
+	if filter.Entity != "" {
+		sel = append(sel, bson.DocElem{"entities", filter.Entity})
+	}
+	timeSel := bson.M{}
+	if !filter.After.IsZero() {
+		timeSel["$gte"] = filter.After.UTC()
+	}
+	if !filter.Before.IsZero() {
+		timeSel["$lt"] = filter.Before.UTC()
+	}
+	if len(timeSel) > 0 {
+		sel = append(sel, bson.DocElem{"time", timeSel})
+	}
+
+	query := events.Find(sel).Sort("-time", "-_id")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var docs []auditEventDoc
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get audit events")
+	}
+	results := make([]audit.Event, len(docs))
+	for i, doc := range docs {
+		// The query returns the newest events first so that the
+		// limit applies to them; reverse them here.
+		results[len(docs)-i-1] = audit.Event{
+			Time:          doc.Time.UTC(),
+			EnvUUID:       doc.EnvUUID,
+			User:          doc.User,
+			Facade:        doc.Facade,
+			Version:       doc.Version,
+			Method:        doc.Method,
+			Args:          doc.Args,
+			Entities:      doc.Entities,
+			Error:         doc.Error,
+			SourceAddress: doc.SourceAddress,
+		}
+	}
+	return results, nil
+}
+
+// PruneAuditEvents removes old audit events, across all
+// environments, in order to control the size of the audit trail. All
+// events older than minTime are removed. Further removal is
+// performed, oldest first, for any environment that has more than
+// maxEventsPerEnv events remaining.
+func PruneAuditEvents(st *State, minTime time.Time, maxEventsPerEnv int) error {
+	events, closer := st.getRawCollection(auditEventsC)
+	defer closer()
+
+	var envUUIDs []string
+	if err := events.Find(nil).Distinct("env-uuid", &envUUIDs); err != nil {
+		return errors.Annotate(err, "cannot get environments in audit trail")
+	}
+	for _, envUUID := range envUUIDs {
+		removeInfo, err := events.RemoveAll(bson.D{
+			{"env-uuid", envUUID},
+			{"time", bson.M{"$lt": minTime.UTC()}},
+		})
+		if err != nil {
+			return errors.Annotate(err, "cannot prune audit events by time")
+		}
+		removed := removeInfo.Removed
+
+		if maxEventsPerEnv > 0 {
+			// Find the newest event to remove. Events are ordered by
+			// time and then by id, so that those sharing its time are
+			// split exactly at the limit.
+			var newest auditEventDoc
+			err := events.Find(bson.D{{"env-uuid", envUUID}}).
+				Sort("-time", "-_id").
+				Skip(maxEventsPerEnv).
+				Select(bson.D{{"time", 1}, {"_id", 1}}).
+				One(&newest)
+			if err != nil && err != mgo.ErrNotFound {
+				return errors.Annotate(err, "cannot find newest audit event to remove")
+			}
+			if err == nil {
+				removeInfo, err := events.RemoveAll(bson.D{
+					{"env-uuid", envUUID},
+					{"$or", []bson.D{
+						{{"time", bson.M{"$lt": newest.Time}}},
+						{{"time", newest.Time}, {"_id", bson.M{"$lte": newest.Id}}},
+					}},
+				})
+				if err != nil {
+					return errors.Annotate(err, "cannot prune audit events by count")
+				}
+				removed += removeInfo.Removed
+			}
+		}
+		if removed > 0 {
+			logger.Debugf("pruned %d audit events for environment %s", removed, envUUID)
+		}
+	}
+	return nil
+}
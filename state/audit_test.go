@@ -0,0 +1,157 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/state"
+)
+
+type AuditSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&AuditSuite{})
+
+var auditEpoch = time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *AuditSuite) addEvent(c *gc.C, st *state.State, offset time.Duration, user, method string, entities ...string) {
+	err := st.AddAuditEvent(audit.Event{
+		Time:          auditEpoch.Add(offset),
+		User:          user,
+		Facade:        "Client",
+		Method:        method,
+		Entities:      entities,
+		SourceAddress: "10.0.0.1:1234",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AuditSuite) TestAddAuditEventRequiresUser(c *gc.C) {
+	err := s.State.AddAuditEvent(audit.Event{Facade: "Client", Method: "FullStatus"})
+	c.Assert(err, gc.ErrorMatches, "audit event user cannot be blank")
+}
+
+func (s *AuditSuite) TestAuditEvents(c *gc.C) {
+	s.addEvent(c, s.State, 0, "user-admin", "ServiceDeploy", "service-mysql")
+	s.addEvent(c, s.State, time.Minute, "user-bob", "DestroyMachines", "machine-1")
+
+	events, err := s.State.AuditEvents(state.AuditEventFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, jc.DeepEquals, []audit.Event{{
+		Time:          auditEpoch,
+		EnvUUID:       s.State.EnvironUUID(),
+		User:          "user-admin",
+		Facade:        "Client",
+		Method:        "ServiceDeploy",
+		Entities:      []string{"service-mysql"},
+		SourceAddress: "10.0.0.1:1234",
+	}, {
+		Time:          auditEpoch.Add(time.Minute),
+		EnvUUID:       s.State.EnvironUUID(),
+		User:          "user-bob",
+		Facade:        "Client",
+		Method:        "DestroyMachines",
+		Entities:      []string{"machine-1"},
+		SourceAddress: "10.0.0.1:1234",
+	}})
+}
+
+func (s *AuditSuite) TestAuditEventsFilters(c *gc.C) {
+	s.addEvent(c, s.State, 0, "user-admin", "ServiceDeploy", "service-mysql")
+	s.addEvent(c, s.State, time.Minute, "user-bob", "DestroyMachines", "machine-1")
+	s.addEvent(c, s.State, 2*time.Minute, "user-admin", "AddServiceUnits", "service-mysql")
+	s.addEvent(c, s.State, 3*time.Minute, "user-admin", "DestroyMachines", "machine-2")
+
+	methods := func(filter state.AuditEventFilter) []string {
+		events, err := s.State.AuditEvents(filter)
+		c.Assert(err, jc.ErrorIsNil)
+		var result []string
+		for _, event := range events {
+			result = append(result, event.Method)
+		}
+		return result
+	}
+	c.Check(methods(state.AuditEventFilter{User: "user-bob"}), jc.DeepEquals, []string{
+		"DestroyMachines",
+	})
+	c.Check(methods(state.AuditEventFilter{Entity: "service-mysql"}), jc.DeepEquals, []string{
+		"ServiceDeploy", "AddServiceUnits",
+	})
+	c.Check(methods(state.AuditEventFilter{
+		After:  auditEpoch.Add(time.Minute),
+		Before: auditEpoch.Add(3 * time.Minute),
+	}), jc.DeepEquals, []string{
+		"DestroyMachines", "AddServiceUnits",
+	})
+	c.Check(methods(state.AuditEventFilter{User: "user-admin", Limit: 2}), jc.DeepEquals, []string{
+		"AddServiceUnits", "DestroyMachines",
+	})
+}
+
+func (s *AuditSuite) TestAuditEventsAreEnvironmentSpecific(c *gc.C) {
+	otherSt := s.Factory.MakeEnvironment(c, nil)
+	defer otherSt.Close()
+
+	s.addEvent(c, s.State, 0, "user-admin", "ServiceDeploy")
+	s.addEvent(c, otherSt, 0, "user-admin", "DestroyMachines")
+
+	events, err := otherSt.AuditEvents(state.AuditEventFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 1)
+	c.Assert(events[0].Method, gc.Equals, "DestroyMachines")
+	c.Assert(events[0].EnvUUID, gc.Equals, otherSt.EnvironUUID())
+}
+
+func (s *AuditSuite) TestPruneAuditEventsByTime(c *gc.C) {
+	for i := 0; i < 5; i++ {
+		s.addEvent(c, s.State, time.Duration(i)*time.Hour, "user-admin", "FullStatus")
+	}
+
+	err := state.PruneAuditEvents(s.State, auditEpoch.Add(3*time.Hour), 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	events, err := s.State.AuditEvents(state.AuditEventFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 2)
+	c.Assert(events[0].Time, gc.Equals, auditEpoch.Add(3*time.Hour))
+}
+
+func (s *AuditSuite) TestPruneAuditEventsByCount(c *gc.C) {
+	otherSt := s.Factory.MakeEnvironment(c, nil)
+	defer otherSt.Close()
+
+	for i := 0; i < 5; i++ {
+		s.addEvent(c, s.State, time.Duration(i)*time.Hour, "user-admin", "FullStatus")
+		s.addEvent(c, otherSt, time.Duration(i)*time.Hour, "user-admin", "FullStatus")
+	}
+
+	err := state.PruneAuditEvents(s.State, auditEpoch, 3)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, st := range []*state.State{s.State, otherSt} {
+		events, err := st.AuditEvents(state.AuditEventFilter{})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(events, gc.HasLen, 3)
+		c.Assert(events[0].Time, gc.Equals, auditEpoch.Add(2*time.Hour))
+	}
+}
+
+func (s *AuditSuite) TestPruneAuditEventsByCountSharedTime(c *gc.C) {
+	for i := 0; i < 5; i++ {
+		s.addEvent(c, s.State, 0, "user-admin", "FullStatus")
+	}
+
+	err := state.PruneAuditEvents(s.State, auditEpoch, 3)
+	c.Assert(err, jc.ErrorIsNil)
+
+	events, err := s.State.AuditEvents(state.AuditEventFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 3)
+}
@@ -102,22 +102,34 @@ func newArchiveWorkspace() (*ArchiveWorkspace, error) {
 // unpacking the entire archive into a directory under the host's
 // "temporary" directory. For relatively large archives this could have
 // adverse effects on hosts with little disk space.
-func NewArchiveWorkspaceReader(archive io.Reader) (*ArchiveWorkspace, error) {
+//
+// If the archive is encrypted, it is decrypted with the given key,
+// which must then not be nil.
+func NewArchiveWorkspaceReader(archive io.Reader, key *Key) (*ArchiveWorkspace, error) {
 	ws, err := newArchiveWorkspace()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	err = unpackCompressedReader(ws.RootDir, archive)
+	err = unpackCompressedReader(ws.RootDir, archive, key)
 	return ws, errors.Trace(err)
 }
 
-func unpackCompressedReader(targetDir string, tarFile io.Reader) error {
-	tarFile, err := gzip.NewReader(tarFile)
+func unpackCompressedReader(targetDir string, archive io.Reader, key *Key) error {
+	archive, err := compressedReader(archive, key)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tarFile, err := gzip.NewReader(archive)
 	if err != nil {
 		return errors.Annotate(err, "while uncompressing archive file")
 	}
-	err = tar.UntarFiles(tarFile, targetDir)
-	return errors.Trace(err)
+	if err := tar.UntarFiles(tarFile, targetDir); err != nil {
+		return errors.Trace(err)
+	}
+	// Read the archive to the end, so that the integrity of an
+	// encrypted archive is checked.
+	_, err = io.Copy(ioutil.Discard, archive)
+	return errors.Annotate(err, "while reading archive file")
 }
 
 // Close cleans up the workspace dir.
@@ -194,7 +206,14 @@ func NewArchiveData(data []byte) *ArchiveData {
 // the provided reader. Note that the entire archive will be read into
 // memory and kept there. So for relatively large archives it will often
 // be more appropriate to use ArchiveWorkspace instead.
-func NewArchiveDataReader(r io.Reader) (*ArchiveData, error) {
+//
+// If the archive is encrypted, it is decrypted with the given key,
+// which must then not be nil.
+func NewArchiveDataReader(r io.Reader, key *Key) (*ArchiveData, error) {
+	r, err := compressedReader(r, key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	gzr, err := gzip.NewReader(r)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -205,6 +224,11 @@ func NewArchiveDataReader(r io.Reader) (*ArchiveData, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	// Read what remains, so that the integrity of an encrypted archive
+	// is checked.
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	return NewArchiveData(data), nil
 }
@@ -97,7 +97,7 @@ func (s *archiveDataSuite) TestNewArchiveData(c *gc.C) {
 }
 
 func (s *archiveDataSuite) TestNewArchiveDataReader(c *gc.C) {
-	ad, err := backups.NewArchiveDataReader(s.archiveFile)
+	ad, err := backups.NewArchiveDataReader(s.archiveFile, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	data := ad.NewBuffer().Bytes()
 
@@ -106,7 +106,7 @@ func (s *archiveDataSuite) TestNewArchiveDataReader(c *gc.C) {
 }
 
 func (s *archiveDataSuite) TestNewBuffer(c *gc.C) {
-	ad, err := backups.NewArchiveDataReader(s.archiveFile)
+	ad, err := backups.NewArchiveDataReader(s.archiveFile, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	buf := ad.NewBuffer()
 
@@ -114,7 +114,7 @@ func (s *archiveDataSuite) TestNewBuffer(c *gc.C) {
 }
 
 func (s *archiveDataSuite) TestNewBufferMultiple(c *gc.C) {
-	ad, err := backups.NewArchiveDataReader(s.archiveFile)
+	ad, err := backups.NewArchiveDataReader(s.archiveFile, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	buf1 := ad.NewBuffer()
@@ -125,7 +125,7 @@ func (s *archiveDataSuite) TestNewBufferMultiple(c *gc.C) {
 }
 
 func (s *archiveDataSuite) TestMetadata(c *gc.C) {
-	ad, err := backups.NewArchiveDataReader(s.archiveFile)
+	ad, err := backups.NewArchiveDataReader(s.archiveFile, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	meta, err := ad.Metadata()
@@ -135,7 +135,7 @@ func (s *archiveDataSuite) TestMetadata(c *gc.C) {
 }
 
 func (s *archiveDataSuite) TestVersionFound(c *gc.C) {
-	ad, err := backups.NewArchiveDataReader(s.archiveFile)
+	ad, err := backups.NewArchiveDataReader(s.archiveFile, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	version, err := ad.Version()
@@ -146,7 +146,7 @@ func (s *archiveDataSuite) TestVersionFound(c *gc.C) {
 
 func (s *archiveDataSuite) TestVersionNotFound(c *gc.C) {
 	archiveFile := s.newArchiveFile(c, nil)
-	ad, err := backups.NewArchiveDataReader(archiveFile)
+	ad, err := backups.NewArchiveDataReader(archiveFile, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	version, err := ad.Version()
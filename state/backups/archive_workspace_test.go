@@ -75,7 +75,7 @@ func (s *workspaceSuite) SetUpTest(c *gc.C) {
 }
 
 func (s *workspaceSuite) TestNewArchiveWorkspaceReader(c *gc.C) {
-	ws, err := backups.NewArchiveWorkspaceReader(s.archiveFile)
+	ws, err := backups.NewArchiveWorkspaceReader(s.archiveFile, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	defer ws.Close()
 
@@ -83,7 +83,7 @@ func (s *workspaceSuite) TestNewArchiveWorkspaceReader(c *gc.C) {
 }
 
 func (s *workspaceSuite) TestClose(c *gc.C) {
-	ws, err := backups.NewArchiveWorkspaceReader(s.archiveFile)
+	ws, err := backups.NewArchiveWorkspaceReader(s.archiveFile, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	err = ws.Close()
@@ -94,7 +94,7 @@ func (s *workspaceSuite) TestClose(c *gc.C) {
 }
 
 func (s *workspaceSuite) TestUnpackFilesBundle(c *gc.C) {
-	ws, err := backups.NewArchiveWorkspaceReader(s.archiveFile)
+	ws, err := backups.NewArchiveWorkspaceReader(s.archiveFile, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	defer ws.Close()
 
@@ -109,7 +109,7 @@ func (s *workspaceSuite) TestUnpackFilesBundle(c *gc.C) {
 }
 
 func (s *workspaceSuite) TestOpenBundledFile(c *gc.C) {
-	ws, err := backups.NewArchiveWorkspaceReader(s.archiveFile)
+	ws, err := backups.NewArchiveWorkspaceReader(s.archiveFile, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	defer ws.Close()
 
@@ -122,7 +122,7 @@ func (s *workspaceSuite) TestOpenBundledFile(c *gc.C) {
 }
 
 func (s *workspaceSuite) TestMetadata(c *gc.C) {
-	ws, err := backups.NewArchiveWorkspaceReader(s.archiveFile)
+	ws, err := backups.NewArchiveWorkspaceReader(s.archiveFile, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	defer ws.Close()
 
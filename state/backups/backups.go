@@ -83,8 +83,10 @@ func StoreArchive(stor filestorage.FileStorage, meta *Metadata, file io.Reader)
 // Backups is an abstraction around all juju backup-related functionality.
 type Backups interface {
 	// Create creates and stores a new juju backup archive. It updates
-	// the provided metadata.
-	Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error
+	// the provided metadata. If key is not nil, the archive is
+	// encrypted with it. The contents of the databases and collections
+	// listed in the metadata's Excluded field are left out.
+	Create(meta *Metadata, paths *Paths, dbInfo *DBInfo, key *Key) error
 
 	// Add stores the backup archive and returns its new ID.
 	Add(archive io.Reader, meta *Metadata) (string, error)
@@ -116,8 +118,12 @@ func NewBackups(stor filestorage.FileStorage) Backups {
 
 // Create creates and stores a new juju backup archive and updates the
 // provided metadata.
-func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
+func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo, key *Key) error {
 	meta.Started = time.Now().UTC()
+	if key != nil {
+		meta.Encrypted = true
+		meta.KeyFingerprint = key.Fingerprint()
+	}
 
 	// The metadata file will not contain the ID or the "finished" data.
 	// However, that information is not as critical. The alternatives
@@ -138,7 +144,10 @@ func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
 	if err != nil {
 		return errors.Annotate(err, "while preparing for DB dump")
 	}
-	args := createArgs{filesToBackUp, dumper, metadataFile}
+	if len(meta.Excluded) > 0 {
+		dumper = &excludingDumper{dumper, meta.Excluded}
+	}
+	args := createArgs{filesToBackUp, dumper, metadataFile, key}
 	result, err := runCreate(&args)
 	if err != nil {
 		return errors.Annotate(err, "while creating backup archive")
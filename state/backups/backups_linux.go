@@ -32,12 +32,31 @@ func (b *backups) Restore(backupId string, args RestoreArgs) error {
 
 	defer backupReader.Close()
 
-	workspace, err := NewArchiveWorkspaceReader(backupReader)
+	if meta.Encrypted {
+		if args.DecryptionKey == nil {
+			return errors.Errorf("backup %q is encrypted with key %s; a key is needed to restore it", backupId, meta.KeyFingerprint)
+		}
+		if fingerprint := args.DecryptionKey.Fingerprint(); fingerprint != meta.KeyFingerprint {
+			return errors.Errorf("backup %q is encrypted with key %s, not %s", backupId, meta.KeyFingerprint, fingerprint)
+		}
+	}
+	workspace, err := NewArchiveWorkspaceReader(backupReader, args.DecryptionKey)
 	if err != nil {
 		return errors.Annotate(err, "cannot unpack backup file")
 	}
 	defer workspace.Close()
 
+	// The captured operations are read before the database is
+	// replaced, since they are described in it.
+	if !args.RecoverTo.IsZero() {
+		if args.Oplog == nil {
+			return errors.New("cannot recover to a point in time: no oplog storage given")
+		}
+		if err := appendOplog(workspace.DBDumpDir, args.Oplog, meta, args.RecoverTo, args.DecryptionKey); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	// TODO(perrito666) Create a compatibility table of sorts.
 	version := meta.Origin.Version
 	backupMachine := names.NewMachineTag(meta.Origin.Machine)
@@ -102,6 +121,10 @@ func (b *backups) Restore(backupId string, args RestoreArgs) error {
 		return errors.Annotate(err, "cannot update mongo entries")
 	}
 
+	if err := resetExcluded(dialInfo, meta.Excluded); err != nil {
+		return errors.Trace(err)
+	}
+
 	// From here we work with the restored state server
 	mgoInfo, ok := agentConfig.MongoInfo()
 	if !ok {
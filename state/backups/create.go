@@ -31,6 +31,8 @@ type createArgs struct {
 	filesToBackUp  []string
 	db             DBDumper
 	metadataReader io.Reader
+	// key, if not nil, is the key with which the archive is encrypted.
+	key *Key
 }
 
 type createResult struct {
@@ -43,7 +45,7 @@ type createResult struct {
 // updates the metadata with the file info.
 func create(args *createArgs) (_ *createResult, err error) {
 	// Prepare the backup builder.
-	builder, err := newBuilder(args.filesToBackUp, args.db)
+	builder, err := newBuilder(args.filesToBackUp, args.db, args.key)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -96,6 +98,8 @@ type builder struct {
 	filesToBackUp []string
 	// db is the wrapper around the DB dump command and args.
 	db DBDumper
+	// key, if not nil, is the key with which the archive is encrypted.
+	key *Key
 	// checksum is the checksum of the archive file.
 	checksum string
 	// archiveFile is the backup archive file.
@@ -109,7 +113,7 @@ type builder struct {
 // directories which backup uses as its staging area while building the
 // archive.  It also creates the archive
 // (temp root, tarball root, DB dumpdir), along with any error.
-func newBuilder(filesToBackUp []string, db DBDumper) (b *builder, err error) {
+func newBuilder(filesToBackUp []string, db DBDumper, key *Key) (b *builder, err error) {
 	// Create the backups workspace root directory.
 	rootDir, err := ioutil.TempDir("", tempPrefix)
 	if err != nil {
@@ -123,6 +127,7 @@ func newBuilder(filesToBackUp []string, db DBDumper) (b *builder, err error) {
 		filename:      filepath.Join(rootDir, tempFilename),
 		filesToBackUp: filesToBackUp,
 		db:            db,
+		key:           key,
 	}
 	defer func() {
 		if err != nil {
@@ -312,9 +317,25 @@ func (b *builder) buildArchiveAndChecksum() error {
 	// than to the uncompressed contents of the tarball.  This is so
 	// that users can compare the published checksum against the
 	// checksum of the file without having to decompress it first.
+	// Likewise, the hash of an encrypted archive is that of the
+	// encrypted file.
 	hasher := hash.NewHashingWriter(b.archiveFile, sha1.New())
-	if err := b.buildArchive(hasher); err != nil {
-		return errors.Trace(err)
+	if b.key == nil {
+		if err := b.buildArchive(hasher); err != nil {
+			return errors.Trace(err)
+		}
+	} else {
+		encrypter, err := encryptingWriter(hasher, b.key)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := b.buildArchive(encrypter); err != nil {
+			encrypter.Close()
+			return errors.Trace(err)
+		}
+		if err := encrypter.Close(); err != nil {
+			return errors.Annotate(err, "while encrypting archive")
+		}
 	}
 
 	// Save the SHA1 checksum.
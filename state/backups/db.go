@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
@@ -267,3 +268,108 @@ func placeNewMongo(newMongoDumpPath string, ver version.Number) error {
 
 	return nil
 }
+
+// excludable maps the names of the databases ("db") and collections
+// ("db.collection") whose contents may be left out of a backup to the
+// collections that are recreated, empty, when the backup is restored.
+// Nothing else may be left out: juju cannot run without it.
+var excludable = map[string][]string{
+	"logs":                 {"logs.logs"},
+	"logs.logs":            {"logs.logs"},
+	"juju.auditevents":     {"juju.auditevents"},
+	"juju.metrics":         {"juju.metrics"},
+	"juju.statuseshistory": {"juju.statuseshistory"},
+}
+
+// ValidateExcluded checks that each of the names is that of a database
+// or collection whose contents may be left out of a backup.
+func ValidateExcluded(names []string) error {
+	for _, name := range names {
+		if _, ok := excludable[name]; !ok {
+			allowed := set.NewStrings()
+			for excludable := range excludable {
+				allowed.Add(excludable)
+			}
+			return errors.Errorf("cannot exclude %q: only %s may be excluded", name, strings.Join(allowed.SortedValues(), ", "))
+		}
+	}
+	return nil
+}
+
+// splitNamespace splits the given namespace into the names of its
+// database and collection, if any.
+func splitNamespace(ns string) (db, coll string) {
+	parts := strings.SplitN(ns, ".", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// isExcluded returns whether the given namespace, of a collection, is
+// in one of the excluded databases or collections.
+func isExcluded(ns string, excluded []string) bool {
+	db, _ := splitNamespace(ns)
+	for _, name := range excluded {
+		if name == ns || name == db {
+			return true
+		}
+	}
+	return false
+}
+
+// excludingDumper is a DBDumper which leaves the contents of the
+// excluded databases and collections out of the dump made by another.
+type excludingDumper struct {
+	DBDumper
+	excluded []string
+}
+
+// Dump implements DBDumper.
+func (d *excludingDumper) Dump(dumpDir string) error {
+	if err := d.DBDumper.Dump(dumpDir); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(stripExcluded(d.excluded, dumpDir))
+}
+
+// stripExcluded removes the excluded databases and collections from
+// the mongo dump files, along with the oplog entries made to them while
+// the dump was taken, so that they are not replayed either.
+func stripExcluded(excluded []string, dumpDir string) error {
+	for _, name := range excluded {
+		db, coll := splitNamespace(name)
+		if coll == "" {
+			if err := os.RemoveAll(filepath.Join(dumpDir, db)); err != nil {
+				return errors.Trace(err)
+			}
+			continue
+		}
+		for _, ext := range []string{".bson", ".metadata.json"} {
+			err := os.Remove(filepath.Join(dumpDir, db, coll+ext))
+			if err != nil && !os.IsNotExist(err) {
+				return errors.Trace(err)
+			}
+		}
+	}
+
+	oplogFile := filepath.Join(dumpDir, oplogFilename)
+	data, err := ioutil.ReadFile(oplogFile)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	var kept []byte
+	for len(data) > 0 {
+		var raw []byte
+		var entry mongo.OplogDoc
+		if raw, data, err = nextOplogEntry(data, &entry); err != nil {
+			return errors.Trace(err)
+		}
+		if !isExcluded(entry.Namespace, excluded) {
+			kept = append(kept, raw...)
+		}
+	}
+	return errors.Trace(ioutil.WriteFile(oplogFile, kept, 0600))
+}
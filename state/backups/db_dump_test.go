@@ -4,12 +4,15 @@
 package backups_test
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/set"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/state/backups"
 	"github.com/juju/juju/testing"
@@ -125,3 +128,72 @@ func (s *dumpSuite) TestDumpNothingIgnored(c *gc.C) {
 
 	s.checkDBs(c, "juju", "admin")
 }
+
+func (s *dumpSuite) prepCollection(c *gc.C, dbName, collName string) {
+	for _, ext := range []string{".bson", ".metadata.json"} {
+		err := ioutil.WriteFile(filepath.Join(s.dumpDir, dbName, collName+ext), nil, 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *dumpSuite) prepOplog(c *gc.C, namespaces ...string) {
+	var data []byte
+	for _, ns := range namespaces {
+		entry, err := bson.Marshal(bson.D{{"op", "i"}, {"ns", ns}})
+		c.Assert(err, jc.ErrorIsNil)
+		data = append(data, entry...)
+	}
+	err := ioutil.WriteFile(filepath.Join(s.dumpDir, "oplog.bson"), data, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *dumpSuite) TestStripExcluded(c *gc.C) {
+	s.prepDB(c, "juju")
+	s.prepCollection(c, "juju", "machines")
+	s.prepCollection(c, "juju", "statuseshistory")
+	s.prepDB(c, "logs")
+	s.prepCollection(c, "logs", "logs")
+	s.prepOplog(c, "juju.machines", "logs.logs", "juju.statuseshistory", "juju.units")
+
+	err := backups.StripExcluded([]string{"logs", "juju.statuseshistory"}, s.dumpDir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.checkDBs(c, "juju", "juju/machines.bson", "juju/machines.metadata.json")
+	s.checkStripped(c, "logs")
+	s.checkStripped(c, "juju/statuseshistory.bson")
+	s.checkStripped(c, "juju/statuseshistory.metadata.json")
+
+	data, err := ioutil.ReadFile(filepath.Join(s.dumpDir, "oplog.bson"))
+	c.Assert(err, jc.ErrorIsNil)
+	var namespaces []string
+	for len(data) > 0 {
+		var entry struct {
+			Namespace string `bson:"ns"`
+		}
+		size := int(data[0]) | int(data[1])<<8 | int(data[2])<<16 | int(data[3])<<24
+		err := bson.Unmarshal(data[:size], &entry)
+		c.Assert(err, jc.ErrorIsNil)
+		namespaces = append(namespaces, entry.Namespace)
+		data = data[size:]
+	}
+	c.Check(namespaces, jc.DeepEquals, []string{"juju.machines", "juju.units"})
+}
+
+func (s *dumpSuite) TestStripExcludedMissing(c *gc.C) {
+	s.prepDB(c, "juju")
+
+	err := backups.StripExcluded([]string{"logs", "juju.statuseshistory"}, s.dumpDir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.checkDBs(c, "juju")
+}
+
+func (s *dumpSuite) TestValidateExcluded(c *gc.C) {
+	err := backups.ValidateExcluded([]string{"logs", "juju.statuseshistory"})
+	c.Check(err, jc.ErrorIsNil)
+
+	for _, name := range []string{"", ".logs", "logs.", "juju", "admin", "juju.units", "blobstore"} {
+		err := backups.ValidateExcluded([]string{name})
+		c.Check(err, gc.ErrorMatches, fmt.Sprintf(`cannot exclude %q: only juju.auditevents, juju.metrics, juju.statuseshistory, logs, logs.logs may be excluded`, name))
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/juju/errors"
+	"golang.org/x/crypto/openpgp"
+	pgperrors "golang.org/x/crypto/openpgp/errors"
+)
+
+// Key is an OpenPGP key with which backup archives are encrypted or
+// decrypted. Archives are encrypted with the public part of the key,
+// so that the state server never needs to hold the private part; it
+// is only needed to decrypt the archive again, when it is restored.
+type Key struct {
+	entity *openpgp.Entity
+}
+
+// ParseKey parses an OpenPGP key, such as is exported by gpg, in either
+// ASCII-armored or binary form. If the data holds more than one key,
+// the first is used.
+func ParseKey(data []byte) (*Key, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if err != nil {
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot parse key")
+	}
+	if len(entities) == 0 {
+		return nil, errors.New("cannot parse key: no key found")
+	}
+	return &Key{entities[0]}, nil
+}
+
+// Fingerprint returns the fingerprint of the key's primary public key,
+// as a hexadecimal string.
+func (k *Key) Fingerprint() string {
+	return fmt.Sprintf("%X", k.entity.PrimaryKey.Fingerprint)
+}
+
+// HasPrivate returns whether the key includes its private part, and so
+// can be used to decrypt archives.
+func (k *Key) HasPrivate() bool {
+	return k.entity.PrivateKey != nil
+}
+
+// encryptingWriter returns a writer which encrypts what is written to
+// it with the given key, and writes the resulting OpenPGP message, with
+// its modification detection code, to w. The message is not complete
+// until the writer is closed.
+func encryptingWriter(w io.Writer, key *Key) (io.WriteCloser, error) {
+	hints := &openpgp.FileHints{IsBinary: true}
+	plaintext, err := openpgp.Encrypt(w, []*openpgp.Entity{key.entity}, nil, hints, nil)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot encrypt with key %s", key.Fingerprint())
+	}
+	return plaintext, nil
+}
+
+// decryptingReader returns a reader of the plaintext of the OpenPGP
+// message read from r, which must have been encrypted with the given
+// key. The integrity of the message is checked when the end of the
+// plaintext is reached, so the plaintext must be read in full to be
+// trusted.
+func decryptingReader(r io.Reader, key *Key) (io.Reader, error) {
+	if !key.HasPrivate() {
+		return nil, errors.Errorf("key %s has no private key", key.Fingerprint())
+	}
+	if key.entity.PrivateKey.Encrypted {
+		return nil, errors.Errorf("private key %s is protected by a passphrase", key.Fingerprint())
+	}
+	for _, subkey := range key.entity.Subkeys {
+		if subkey.PrivateKey != nil && subkey.PrivateKey.Encrypted {
+			return nil, errors.Errorf("private key %s is protected by a passphrase", key.Fingerprint())
+		}
+	}
+	md, err := openpgp.ReadMessage(r, openpgp.EntityList{key.entity}, nil, nil)
+	if err == pgperrors.ErrKeyIncorrect {
+		return nil, errors.Errorf("archive was not encrypted with key %s", key.Fingerprint())
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot decrypt archive")
+	}
+	return md.UnverifiedBody, nil
+}
+
+// gzipMagic holds the first bytes of every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// compressedReader returns a reader of the compressed (gzip) content of
+// the archive read from r. Archives that were not encrypted are read
+// as-is; encrypted archives are decrypted with the given key, which
+// must then not be nil.
+func compressedReader(r io.Reader, key *Key) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(gzipMagic))
+	if err != nil {
+		return nil, errors.Annotate(err, "while reading archive file")
+	}
+	if bytes.Equal(magic, gzipMagic) {
+		return br, nil
+	}
+	if key == nil {
+		return nil, errors.New("archive is encrypted; a key is needed to read it")
+	}
+	return decryptingReader(br, key)
+}
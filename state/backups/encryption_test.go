@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"io"
+
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	bt "github.com/juju/juju/state/backups/testing"
+	"github.com/juju/juju/testing"
+)
+
+type encryptionSuite struct {
+	jujutesting.IsolationSuite
+	archive []byte
+	meta    *backups.Metadata
+}
+
+var _ = gc.Suite(&encryptionSuite{})
+
+func (s *encryptionSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.meta = bt.NewMetadata()
+	files := []bt.File{{
+		Name:    "var/lib/juju/system-identity",
+		Content: "<an ssh key goes here>",
+	}}
+	dump := []bt.File{{
+		Name:    "oplog.bson",
+		Content: "<BSON data goes here>",
+	}}
+	archiveFile, err := bt.NewArchive(s.meta, files, dump)
+	c.Assert(err, jc.ErrorIsNil)
+	s.archive = archiveFile.Bytes()
+}
+
+func (s *encryptionSuite) parseKey(c *gc.C, data string) *backups.Key {
+	key, err := backups.ParseKey([]byte(data))
+	c.Assert(err, jc.ErrorIsNil)
+	return key
+}
+
+func (s *encryptionSuite) encrypt(c *gc.C, key *backups.Key) io.Reader {
+	var buf bytes.Buffer
+	w, err := backups.EncryptingWriter(&buf, key)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.Write(s.archive)
+	c.Assert(err, jc.ErrorIsNil)
+	err = w.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	return &buf
+}
+
+func (s *encryptionSuite) TestParseKey(c *gc.C) {
+	public := s.parseKey(c, testing.OpenPGPPublicKey)
+	c.Check(public.Fingerprint(), gc.Equals, testing.OpenPGPKeyFingerprint)
+	c.Check(public.HasPrivate(), jc.IsFalse)
+
+	private := s.parseKey(c, testing.OpenPGPPrivateKey)
+	c.Check(private.Fingerprint(), gc.Equals, testing.OpenPGPKeyFingerprint)
+	c.Check(private.HasPrivate(), jc.IsTrue)
+}
+
+func (s *encryptionSuite) TestParseKeyInvalid(c *gc.C) {
+	_, err := backups.ParseKey([]byte("not a key"))
+	c.Check(err, gc.ErrorMatches, "cannot parse key: .*")
+}
+
+func (s *encryptionSuite) TestEncryptedArchiveRoundTrip(c *gc.C) {
+	encrypted := s.encrypt(c, s.parseKey(c, testing.OpenPGPPublicKey))
+
+	ad, err := backups.NewArchiveDataReader(encrypted, s.parseKey(c, testing.OpenPGPPrivateKey))
+	c.Assert(err, jc.ErrorIsNil)
+	meta, err := ad.Metadata()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.ID(), gc.Equals, s.meta.ID())
+}
+
+func (s *encryptionSuite) TestEncryptedArchiveNoKey(c *gc.C) {
+	encrypted := s.encrypt(c, s.parseKey(c, testing.OpenPGPPublicKey))
+
+	_, err := backups.NewArchiveDataReader(encrypted, nil)
+	c.Check(err, gc.ErrorMatches, "archive is encrypted; a key is needed to read it")
+}
+
+func (s *encryptionSuite) TestEncryptedArchivePublicKey(c *gc.C) {
+	key := s.parseKey(c, testing.OpenPGPPublicKey)
+	encrypted := s.encrypt(c, key)
+
+	_, err := backups.NewArchiveDataReader(encrypted, key)
+	c.Check(err, gc.ErrorMatches, "key "+testing.OpenPGPKeyFingerprint+" has no private key")
+}
+
+func (s *encryptionSuite) TestEncryptedArchiveTampered(c *gc.C) {
+	var buf bytes.Buffer
+	_, err := buf.ReadFrom(s.encrypt(c, s.parseKey(c, testing.OpenPGPPublicKey)))
+	c.Assert(err, jc.ErrorIsNil)
+	data := buf.Bytes()
+	data[len(data)-10] ^= 0xff
+
+	_, err = backups.NewArchiveDataReader(bytes.NewReader(data), s.parseKey(c, testing.OpenPGPPrivateKey))
+	c.Check(err, gc.NotNil)
+}
+
+func (s *encryptionSuite) TestPlainArchiveWithKey(c *gc.C) {
+	ad, err := backups.NewArchiveDataReader(bytes.NewReader(s.archive), s.parseKey(c, testing.OpenPGPPrivateKey))
+	c.Assert(err, jc.ErrorIsNil)
+	meta, err := ad.Metadata()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.ID(), gc.Equals, s.meta.ID())
+}
@@ -17,8 +17,12 @@ import (
 )
 
 var (
-	Create        = create
-	FileTimestamp = fileTimestamp
+	Create                = create
+	FileTimestamp         = fileTimestamp
+	EncryptingWriter      = encryptingWriter
+	SummarizeEnvironments = summarizeEnvironments
+	AppendOplog           = appendOplog
+	StripExcluded         = stripExcluded
 
 	TestGetFilesToBackUp = &getFilesToBackUp
 	GetDBDumper          = &getDBDumper
@@ -28,6 +32,8 @@ var (
 	GetMongodumpPath     = &getMongodumpPath
 	RunCommand           = &runCommand
 	ReplaceableFolders   = &replaceableFolders
+	SummarizeDump        = &summarizeDump
+	GetOplog             = &getOplog
 )
 
 var _ filestorage.DocStorage = (*backupsDocStorage)(nil)
@@ -62,6 +62,18 @@ type Metadata struct {
 	Origin Origin
 	// Notes is an optional user-supplied annotation.
 	Notes string
+	// Scheduled records whether the backup was made automatically,
+	// according to the environment's backup schedule, rather than
+	// by hand.
+	Scheduled bool
+	// Encrypted records whether the archive is encrypted, in which
+	// case KeyFingerprint holds the fingerprint of the key with
+	// which it was encrypted.
+	Encrypted      bool
+	KeyFingerprint string
+	// Excluded lists the databases and collections, named "db" or
+	// "db.collection", whose contents were left out of the archive.
+	Excluded []string
 }
 
 // NewMetadata returns a new Metadata for a state backup archive.  Only
@@ -127,13 +139,17 @@ type flatMetadata struct {
 
 	// backup
 
-	Started     time.Time
-	Finished    time.Time
-	Notes       string
-	Environment string
-	Machine     string
-	Hostname    string
-	Version     version.Number
+	Started        time.Time
+	Finished       time.Time
+	Notes          string
+	Scheduled      bool     `json:",omitempty"`
+	Encrypted      bool     `json:",omitempty"`
+	KeyFingerprint string   `json:",omitempty"`
+	Excluded       []string `json:",omitempty"`
+	Environment    string
+	Machine        string
+	Hostname       string
+	Version        version.Number
 }
 
 // TODO(ericsnow) Move AsJSONBuffer to filestorage.Metadata.
@@ -147,12 +163,16 @@ func (m *Metadata) AsJSONBuffer() (io.Reader, error) {
 		ChecksumFormat: m.ChecksumFormat(),
 		Size:           m.Size(),
 
-		Started:     m.Started,
-		Notes:       m.Notes,
-		Environment: m.Origin.Environment,
-		Machine:     m.Origin.Machine,
-		Hostname:    m.Origin.Hostname,
-		Version:     m.Origin.Version,
+		Started:        m.Started,
+		Notes:          m.Notes,
+		Scheduled:      m.Scheduled,
+		Encrypted:      m.Encrypted,
+		KeyFingerprint: m.KeyFingerprint,
+		Excluded:       m.Excluded,
+		Environment:    m.Origin.Environment,
+		Machine:        m.Origin.Machine,
+		Hostname:       m.Origin.Hostname,
+		Version:        m.Origin.Version,
 	}
 
 	stored := m.Stored()
@@ -195,6 +215,10 @@ func NewMetadataJSONReader(in io.Reader) (*Metadata, error) {
 		meta.Finished = &flat.Finished
 	}
 	meta.Notes = flat.Notes
+	meta.Scheduled = flat.Scheduled
+	meta.Encrypted = flat.Encrypted
+	meta.KeyFingerprint = flat.KeyFingerprint
+	meta.Excluded = flat.Excluded
 	meta.Origin = Origin{
 		Environment: flat.Environment,
 		Machine:     flat.Machine,
@@ -91,6 +91,19 @@ func (s *metadataSuite) TestNewMetadataJSONReader(c *gc.C) {
 	c.Check(meta.Origin.Version.String(), gc.Equals, "1.21-alpha3")
 }
 
+func (s *metadataSuite) TestJSONExcluded(c *gc.C) {
+	meta := backups.NewMetadata()
+	meta.Excluded = []string{"logs", "juju.statuseshistory"}
+
+	buf, err := meta.AsJSONBuffer()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(buf.(*bytes.Buffer).String(), jc.Contains, `"Excluded":["logs","juju.statuseshistory"]`)
+
+	meta, err = backups.NewMetadataJSONReader(buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.Excluded, jc.DeepEquals, []string{"logs", "juju.statuseshistory"})
+}
+
 func (s *metadataSuite) TestBuildMetadata(c *gc.C) {
 	archive, err := os.Create(filepath.Join(c.MkDir(), "juju-backup.tgz"))
 	c.Assert(err, jc.ErrorIsNil)
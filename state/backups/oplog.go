@@ -0,0 +1,600 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/filestorage"
+	"github.com/juju/utils/hash"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/mongo"
+)
+
+// getOplog is replaced in tests.
+var getOplog = mongo.GetOplog
+
+// The operations made on the database after a backup was made are
+// captured from mongo's oplog, in segments, and kept with the backup.
+// Replaying them on top of the backup's database dump, which itself
+// ends with the oplog entries made while the dump was taken, recovers
+// the database as it was at a chosen time after the backup was made.
+
+const oplogSegmentsC = "oplog"
+
+// OplogSegment describes a segment of the oplog entries captured after
+// a backup was made. Each segment holds the entries later than From,
+// up to and including To; the segments of a backup follow on from one
+// another, in order of Seq.
+type OplogSegment struct {
+	ID       string              `bson:"_id"`
+	BackupID string              `bson:"backupid"`
+	Seq      int                 `bson:"seq"`
+	From     bson.MongoTimestamp `bson:"from"`
+	To       bson.MongoTimestamp `bson:"to"`
+	Entries  int                 `bson:"entries"`
+
+	// Size and Checksum describe the segment's data: its entries,
+	// compressed with gzip and, if KeyFingerprint is set, encrypted
+	// with the key with that fingerprint.
+	Size           int64  `bson:"size,minsize"`
+	Checksum       string `bson:"checksum"`
+	KeyFingerprint string `bson:"keyfingerprint,omitempty"`
+
+	// Location identifies the BackupStorage in which the data is
+	// kept, or is empty if it is kept in state.
+	Location string `bson:"location,omitempty"`
+}
+
+// name returns the name under which the segment's data is kept.
+func (seg *OplogSegment) name() string {
+	return fmt.Sprintf("%s.oplog.%04d.gz", seg.BackupID, seg.Seq)
+}
+
+// metadataName returns the name under which the segment's description
+// is kept, alongside its data, in a BackupStorage.
+func (seg *OplogSegment) metadataName() string {
+	return fmt.Sprintf("%s.oplog.%04d.json", seg.BackupID, seg.Seq)
+}
+
+// oplogTime returns the time, to the second, of the given oplog
+// timestamp.
+func oplogTime(ts bson.MongoTimestamp) time.Time {
+	return time.Unix(int64(ts)>>32, 0).UTC()
+}
+
+// OplogStorage keeps the oplog segments captured after each backup.
+// The segments are described in state, and their data is kept along
+// with the backup archives. Segments kept in a BackupStorage are also
+// described there, so that they are not lost along with the state
+// server that captured them.
+type OplogStorage struct {
+	dbWrap   *storageDBWrapper
+	target   BackupStorage
+	location string
+	state    filestorage.RawFileStorage
+}
+
+// OpenOplogStorage returns the OplogStorage for the environment's
+// backups. If the environment config names a BackupStorage, the data of
+// new segments is kept there, as by OpenStorage; otherwise it is kept
+// in state.
+func OpenOplogStorage(st DB, cfg *config.Config, dataDir string) (*OplogStorage, error) {
+	target, err := NewBackupStorage(cfg, dataDir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewOplogStorage(st, target, cfg.BackupStorage()), nil
+}
+
+// NewOplogStorage returns a new OplogStorage which keeps the data of
+// new segments in the given BackupStorage, identified by the location,
+// or in state if it is nil.
+func NewOplogStorage(st DB, target BackupStorage, location string) *OplogStorage {
+	envUUID := st.EnvironTag().Id()
+	db := st.MongoSession().DB(storageDBName)
+	dbWrap := newStorageDBWrapper(db, storageMetaName, envUUID)
+	if target == nil {
+		location = ""
+	}
+	return &OplogStorage{
+		dbWrap:   dbWrap,
+		target:   target,
+		location: location,
+		state:    newFileStorage(dbWrap, backupStorageRoot),
+	}
+}
+
+// Segments returns the segments captured after the identified backup,
+// in order. When a BackupStorage is in use, segments described only
+// there, such as those captured by a state server that has since been
+// lost, are included.
+func (s *OplogStorage) Segments(backupID string) ([]*OplogSegment, error) {
+	dbWrap := s.dbWrap.Copy()
+	defer dbWrap.Close()
+
+	var segments []*OplogSegment
+	query := dbWrap.db.C(oplogSegmentsC).Find(bson.D{{"backupid", backupID}})
+	if err := query.Sort("seq").All(&segments); err != nil {
+		return nil, errors.Annotatef(err, "cannot get oplog segments of backup %q", backupID)
+	}
+	if s.target == nil {
+		return segments, nil
+	}
+
+	known := make(map[int]*OplogSegment)
+	for _, seg := range segments {
+		known[seg.Seq] = seg
+	}
+	var all []*OplogSegment
+	for seq := 0; ; seq++ {
+		if seg, ok := known[seq]; ok {
+			all = append(all, seg)
+			delete(known, seq)
+			continue
+		}
+		seg, err := s.targetSegment(backupID, seq)
+		if errors.IsNotFound(err) {
+			if len(known) == 0 {
+				break
+			}
+			continue
+		} else if err != nil {
+			return nil, errors.Annotatef(err, "cannot get oplog segments of backup %q", backupID)
+		}
+		all = append(all, seg)
+	}
+	return all, nil
+}
+
+// targetSegment returns the segment with the given sequence number, as
+// described in the BackupStorage. If there is no such segment, an error
+// satisfying errors.IsNotFound is returned.
+func (s *OplogStorage) targetSegment(backupID string, seq int) (*OplogSegment, error) {
+	seg := &OplogSegment{BackupID: backupID, Seq: seq}
+	data, err := s.target.Get(seg.metadataName())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer data.Close()
+	if err := json.NewDecoder(data).Decode(seg); err != nil {
+		return nil, errors.Annotatef(err, "cannot read %q", seg.metadataName())
+	}
+	if seg.BackupID != backupID || seg.Seq != seq {
+		return nil, errors.Errorf("%q describes oplog segment %q", seg.metadataName(), seg.ID)
+	}
+	seg.Location = s.location
+	return seg, nil
+}
+
+// BackupIDs returns the IDs of the backups for which segments are kept.
+func (s *OplogStorage) BackupIDs() ([]string, error) {
+	dbWrap := s.dbWrap.Copy()
+	defer dbWrap.Close()
+
+	var ids []string
+	if err := dbWrap.db.C(oplogSegmentsC).Find(nil).Distinct("backupid", &ids); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ids, nil
+}
+
+// Add adds the segment, with the given data, which must match the
+// segment's size and checksum.
+func (s *OplogStorage) Add(seg *OplogSegment, data io.Reader) error {
+	seg.ID = fmt.Sprintf("%s.%d", seg.BackupID, seg.Seq)
+	seg.Location = s.location
+	stor, err := s.storage(seg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := stor.Put(seg.name(), data, seg.Size); err != nil {
+		return errors.Annotatef(err, "cannot store oplog segment %q", seg.ID)
+	}
+	if err := s.putMetadata(stor, seg); err != nil {
+		s.removeData(stor, seg)
+		return errors.Trace(err)
+	}
+
+	dbWrap := s.dbWrap.Copy()
+	defer dbWrap.Close()
+	ops := []txn.Op{{
+		C:      oplogSegmentsC,
+		Id:     seg.ID,
+		Assert: txn.DocMissing,
+		Insert: seg,
+	}}
+	if err := dbWrap.runTransaction(ops); err != nil {
+		s.removeData(stor, seg)
+		if errors.Cause(err) == txn.ErrAborted {
+			return errors.AlreadyExistsf("oplog segment %q", seg.ID)
+		}
+		return errors.Annotate(err, "while running transaction")
+	}
+	return nil
+}
+
+// putMetadata stores the segment's description alongside its data, if
+// the data is kept in a BackupStorage.
+func (s *OplogStorage) putMetadata(stor BackupStorage, seg *OplogSegment) error {
+	if seg.Location == "" {
+		return nil
+	}
+	data, err := json.Marshal(seg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := stor.Put(seg.metadataName(), bytes.NewReader(data), int64(len(data))); err != nil {
+		return errors.Annotatef(err, "cannot store oplog segment %q", seg.ID)
+	}
+	return nil
+}
+
+// removeData removes the segment's data, and its description if that
+// is kept alongside it, after it could not be added.
+func (s *OplogStorage) removeData(stor BackupStorage, seg *OplogSegment) {
+	names := []string{seg.name()}
+	if seg.Location != "" {
+		names = append(names, seg.metadataName())
+	}
+	for _, name := range names {
+		if err := stor.Remove(name); err != nil && !errors.IsNotFound(err) {
+			logger.Errorf("cannot remove data of oplog segment %q: %v", seg.ID, err)
+		}
+	}
+}
+
+// Open returns the data of the segment.
+func (s *OplogStorage) Open(seg *OplogSegment) (io.ReadCloser, error) {
+	stor, err := s.storage(seg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, err := stor.Get(seg.name())
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get oplog segment %q", seg.ID)
+	}
+	return data, nil
+}
+
+// RemoveAll removes all the segments captured after the identified
+// backup.
+func (s *OplogStorage) RemoveAll(backupID string) error {
+	segments, err := s.Segments(backupID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	dbWrap := s.dbWrap.Copy()
+	defer dbWrap.Close()
+	for _, seg := range segments {
+		stor, err := s.storage(seg)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := stor.Remove(seg.name()); err != nil && !errors.IsNotFound(err) {
+			return errors.Annotatef(err, "cannot remove oplog segment %q", seg.ID)
+		}
+		if seg.Location != "" {
+			if err := stor.Remove(seg.metadataName()); err != nil && !errors.IsNotFound(err) {
+				return errors.Annotatef(err, "cannot remove oplog segment %q", seg.ID)
+			}
+		}
+		ops := []txn.Op{{
+			C:      oplogSegmentsC,
+			Id:     seg.ID,
+			Remove: true,
+		}}
+		if err := dbWrap.runTransaction(ops); err != nil {
+			return errors.Annotate(err, "while running transaction")
+		}
+	}
+	return nil
+}
+
+// storage returns the storage in which the segment's data is kept.
+func (s *OplogStorage) storage(seg *OplogSegment) (BackupStorage, error) {
+	switch seg.Location {
+	case "":
+		return stateStorage{s.state}, nil
+	case s.location:
+		return s.target, nil
+	}
+	return nil, errors.Errorf("oplog segment %q is kept in %s, not in %s", seg.ID, seg.Location, s.location)
+}
+
+// Close closes the storage.
+func (s *OplogStorage) Close() error {
+	var err error
+	if s.target != nil {
+		err = s.target.Close()
+	}
+	if err := s.state.Close(); err != nil {
+		logger.Errorf("cannot close oplog storage: %v", err)
+	}
+	s.dbWrap.Close()
+	return errors.Trace(err)
+}
+
+// ignoredOplogEntry returns whether the entry is for one of the
+// databases that are not backed up, or for one of the databases or
+// collections excluded from the backup.
+func ignoredOplogEntry(entry *mongo.OplogDoc, excluded []string) bool {
+	db := strings.SplitN(entry.Namespace, ".", 2)[0]
+	return ignoredDatabases.Contains(db) || isExcluded(entry.Namespace, excluded)
+}
+
+// CaptureOplog captures, from the oplog read from the session, the
+// entries made since the last segment captured after the given backup,
+// or since the backup was started if there is none, and adds them to
+// storage as a new segment, which it returns. If the oplog has not
+// moved on, it returns nil. Entries for the databases that are not
+// backed up, and for those databases and collections excluded from the
+// backup, are left out, but a segment is still added when there are
+// only such entries, to record that nothing else happened.
+//
+// The segments of an encrypted backup are encrypted with the same key,
+// whose public part must be given; those of other backups are not
+// encrypted, and the key is ignored.
+func CaptureOplog(session *mgo.Session, stor *OplogStorage, meta *Metadata, key *Key) (*OplogSegment, error) {
+	if !meta.Encrypted {
+		key = nil
+	} else if key == nil || key.Fingerprint() != meta.KeyFingerprint {
+		return nil, errors.Errorf("backup %q is encrypted with key %s, which is needed to capture its oplog", meta.ID(), meta.KeyFingerprint)
+	}
+	segments, err := stor.Segments(meta.ID())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	seg := &OplogSegment{
+		BackupID: meta.ID(),
+		From:     mongo.NewMongoTimestamp(meta.Started),
+	}
+	if n := len(segments); n > 0 {
+		seg.Seq = segments[n-1].Seq + 1
+		seg.From = segments[n-1].To
+	}
+	if key != nil {
+		seg.KeyFingerprint = key.Fingerprint()
+	}
+
+	file, err := ioutil.TempFile("", "juju-backup-oplog")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	if err := writeOplogSegment(session, seg, file, key, meta.Excluded); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if seg.To == seg.From {
+		return nil, nil
+	}
+
+	if _, err := file.Seek(0, os.SEEK_SET); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := stor.Add(seg, file); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return seg, nil
+}
+
+// writeOplogSegment writes to w the entries in the oplog that are later
+// than seg.From, other than those for the excluded databases and
+// collections, compressed and, if a key is given, encrypted, and
+// records in seg what was written.
+func writeOplogSegment(session *mgo.Session, seg *OplogSegment, w io.Writer, key *Key, excluded []string) error {
+	oplog := getOplog(session)
+
+	// If the oplog no longer holds the entry that the segment follows
+	// on from, the entries after it have been lost.
+	var first mongo.OplogDoc
+	if err := oplog.Find(nil).Sort("$natural").One(&first); err != nil {
+		return errors.Annotate(err, "cannot read oplog")
+	}
+	if first.Timestamp > seg.From {
+		return errors.Errorf("oplog entries since %v are no longer available", oplogTime(seg.From))
+	}
+
+	counter := &countingWriter{w: w}
+	hasher := hash.NewHashingWriter(counter, sha1.New())
+	var out io.Writer = hasher
+	var encrypter io.WriteCloser
+	if key != nil {
+		var err error
+		if encrypter, err = encryptingWriter(hasher, key); err != nil {
+			return errors.Trace(err)
+		}
+		out = encrypter
+	}
+	gzw := gzip.NewWriter(out)
+
+	seg.To = seg.From
+	iter := oplog.Find(bson.D{{"ts", bson.D{{"$gt", seg.From}}}}).LogReplay().Iter()
+	var raw bson.Raw
+	for iter.Next(&raw) {
+		var entry mongo.OplogDoc
+		if err := raw.Unmarshal(&entry); err != nil {
+			iter.Close()
+			return errors.Annotate(err, "cannot read oplog entry")
+		}
+		seg.To = entry.Timestamp
+		if ignoredOplogEntry(&entry, excluded) {
+			continue
+		}
+		if _, err := gzw.Write(raw.Data); err != nil {
+			iter.Close()
+			return errors.Trace(err)
+		}
+		seg.Entries++
+	}
+	if err := iter.Close(); err != nil {
+		return errors.Annotate(err, "cannot read oplog")
+	}
+
+	if err := gzw.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if encrypter != nil {
+		if err := encrypter.Close(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	seg.Size = counter.n
+	seg.Checksum = hasher.Base64Sum()
+	return nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// oplogFilename is the name of the file in which mongodump --oplog
+// writes the oplog entries made during the dump, and from which
+// mongorestore --oplogReplay replays them.
+const oplogFilename = "oplog.bson"
+
+// appendOplog appends to the oplog file in the given database dump the
+// entries captured after the given backup, up to the end of the second
+// of the given time, so that they are replayed when the dump is
+// restored. The segments are read from storage and, if they are
+// encrypted, decrypted with the given key.
+func appendOplog(dumpDir string, stor *OplogStorage, meta *Metadata, until time.Time, key *Key) error {
+	if ver := meta.Origin.Version; ver.Major == 1 && ver.Minor < 22 {
+		return errors.Errorf("cannot recover to %v: backup %q was made without the oplog", until, meta.ID())
+	}
+	if !until.After(meta.Started) {
+		return errors.Errorf("cannot recover to %v: backup %q was started at %v", until, meta.ID(), meta.Started)
+	}
+	segments, err := stor.Segments(meta.ID())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(segments) == 0 {
+		return errors.Errorf("cannot recover to %v: no operations were captured after backup %q", until, meta.ID())
+	}
+	limit := mongo.NewMongoTimestamp(until.Add(time.Second))
+	last := segments[len(segments)-1].To
+	if oplogTime(last).Before(until.Truncate(time.Second)) {
+		return errors.Errorf("cannot recover to %v: operations since backup %q were captured only up to %v", until, meta.ID(), oplogTime(last))
+	}
+
+	file, err := os.OpenFile(filepath.Join(dumpDir, oplogFilename), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer file.Close()
+
+	from := mongo.NewMongoTimestamp(meta.Started)
+	for _, seg := range segments {
+		if seg.From != from {
+			return errors.Errorf("oplog segment %q does not follow on from the one before it", seg.ID)
+		}
+		from = seg.To
+		done, err := appendOplogSegment(file, stor, seg, limit, key)
+		if err != nil {
+			return errors.Annotatef(err, "cannot replay oplog segment %q", seg.ID)
+		}
+		if done {
+			break
+		}
+	}
+	return nil
+}
+
+// appendOplogSegment writes to w the entries in the segment that are
+// earlier than the limit. It returns whether the limit was reached.
+func appendOplogSegment(w io.Writer, stor *OplogStorage, seg *OplogSegment, limit bson.MongoTimestamp, key *Key) (bool, error) {
+	if seg.KeyFingerprint != "" {
+		if key == nil {
+			return false, errors.Errorf("segment is encrypted with key %s; a key is needed to read it", seg.KeyFingerprint)
+		}
+		if fingerprint := key.Fingerprint(); fingerprint != seg.KeyFingerprint {
+			return false, errors.Errorf("segment is encrypted with key %s, not %s", seg.KeyFingerprint, fingerprint)
+		}
+	}
+	data, err := stor.Open(seg)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	defer data.Close()
+
+	// The whole segment is read, even when the limit falls within it,
+	// so that its checksum can be checked.
+	hasher := hash.NewHashingWriter(ioutil.Discard, sha1.New())
+	compressed, err := compressedReader(io.TeeReader(data, hasher), key)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	gzr, err := gzip.NewReader(compressed)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	entries, err := ioutil.ReadAll(gzr)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if _, err := io.Copy(ioutil.Discard, compressed); err != nil {
+		return false, errors.Trace(err)
+	}
+	if sum := hasher.Base64Sum(); sum != seg.Checksum {
+		return false, errors.Errorf("checksum mismatch: expected %q, got %q", seg.Checksum, sum)
+	}
+
+	for len(entries) > 0 {
+		var raw []byte
+		var entry mongo.OplogDoc
+		if raw, entries, err = nextOplogEntry(entries, &entry); err != nil {
+			return false, errors.Trace(err)
+		}
+		if entry.Timestamp >= limit {
+			return true, nil
+		}
+		if _, err := w.Write(raw); err != nil {
+			return false, errors.Trace(err)
+		}
+	}
+	return seg.To >= limit, nil
+}
+
+// nextOplogEntry unmarshals into entry the first of the BSON-encoded
+// oplog entries in data, and returns its encoding and the rest of the
+// data.
+func nextOplogEntry(data []byte, entry *mongo.OplogDoc) (raw, rest []byte, err error) {
+	if len(data) < 4 {
+		return nil, nil, errors.New("truncated oplog entry")
+	}
+	size := int(binary.LittleEndian.Uint32(data))
+	if size < 5 || size > len(data) {
+		return nil, nil, errors.New("truncated oplog entry")
+	}
+	if err := bson.Unmarshal(data[:size], entry); err != nil {
+		return nil, nil, errors.Annotate(err, "cannot read oplog entry")
+	}
+	return data[:size], data[size:], nil
+}
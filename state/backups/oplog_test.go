@@ -0,0 +1,253 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+// oplogMetadata returns metadata for a backup started at the given time.
+func (s *storageSuite) oplogMetadata(started time.Time) *backups.Metadata {
+	meta := backups.NewMetadata()
+	meta.SetID("20150601-103000." + s.State.EnvironUUID())
+	meta.Started = started
+	meta.Origin.Environment = s.State.EnvironUUID()
+	meta.Origin.Version = version.MustParse("1.25.0")
+	return meta
+}
+
+// fakeOplog makes a capped collection, to stand in for the oplog, from
+// which CaptureOplog captures entries.
+func (s *storageSuite) fakeOplog(c *gc.C) *mgo.Collection {
+	oplog := s.Session.DB("foo").C("oplog.fake")
+	err := oplog.Create(&mgo.CollectionInfo{
+		Capped:   true,
+		MaxBytes: 1024 * 1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(backups.GetOplog, func(*mgo.Session) *mgo.Collection {
+		return oplog
+	})
+	return oplog
+}
+
+func (s *storageSuite) insertOplogEntry(c *gc.C, oplog *mgo.Collection, t time.Time, ns string) {
+	err := oplog.Insert(bson.D{
+		{"ts", mongo.NewMongoTimestamp(t)},
+		{"op", "i"},
+		{"ns", ns},
+		{"o", bson.D{{"_id", t.Unix()}}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// readOplogFile returns the times of the entries in the oplog file in
+// the given dump directory.
+func readOplogFile(c *gc.C, dumpDir string) []time.Time {
+	data, err := ioutil.ReadFile(filepath.Join(dumpDir, "oplog.bson"))
+	c.Assert(err, jc.ErrorIsNil)
+	var times []time.Time
+	for len(data) > 0 {
+		size := int(data[0]) | int(data[1])<<8 | int(data[2])<<16 | int(data[3])<<24
+		var entry mongo.OplogDoc
+		err := bson.Unmarshal(data[:size], &entry)
+		c.Assert(err, jc.ErrorIsNil)
+		times = append(times, time.Unix(int64(entry.Timestamp)>>32, 0).UTC())
+		data = data[size:]
+	}
+	return times
+}
+
+func (s *storageSuite) TestOplogStorage(c *gc.C) {
+	stor := backups.NewOplogStorage(s.State, nil, "")
+	defer stor.Close()
+
+	for _, seg := range []*backups.OplogSegment{
+		{BackupID: "spam", Seq: 1},
+		{BackupID: "spam", Seq: 0},
+		{BackupID: "eggs", Seq: 0},
+	} {
+		data := "<" + seg.BackupID + ">"
+		seg.Size = int64(len(data))
+		err := stor.Add(seg, bytes.NewBufferString(data))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	segments, err := stor.Segments("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(segments, gc.HasLen, 2)
+	c.Check(segments[0].Seq, gc.Equals, 0)
+	c.Check(segments[1].Seq, gc.Equals, 1)
+	data, err := stor.Open(segments[1])
+	c.Assert(err, jc.ErrorIsNil)
+	content, err := ioutil.ReadAll(data)
+	data.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(content), gc.Equals, "<spam>")
+
+	err = stor.RemoveAll("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	segments, err = stor.Segments("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(segments, gc.HasLen, 0)
+	ids, err := stor.BackupIDs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ids, jc.DeepEquals, []string{"eggs"})
+}
+
+func (s *storageSuite) TestOplogStorageTarget(c *gc.C) {
+	dir := c.MkDir()
+	location := "file://" + dir
+	stor := backups.NewOplogStorage(s.State, backups.NewLocalStorage(dir), location)
+	defer stor.Close()
+	for seq := 0; seq < 2; seq++ {
+		seg := &backups.OplogSegment{BackupID: "spam", Seq: seq, Entries: seq + 1, Size: 6}
+		err := stor.Add(seg, bytes.NewBufferString("<spam>"))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// The segments are found in the target even when state has lost
+	// track of them, as when restoring onto a new state server.
+	err := s.Session.DB("backups").C("oplog").DropCollection()
+	c.Assert(err, jc.ErrorIsNil)
+	segments, err := stor.Segments("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(segments, gc.HasLen, 2)
+	c.Check(segments[0].Seq, gc.Equals, 0)
+	c.Check(segments[1].Seq, gc.Equals, 1)
+	c.Check(segments[1].Entries, gc.Equals, 2)
+	c.Check(segments[1].Location, gc.Equals, location)
+	data, err := stor.Open(segments[1])
+	c.Assert(err, jc.ErrorIsNil)
+	content, err := ioutil.ReadAll(data)
+	data.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(content), gc.Equals, "<spam>")
+
+	err = stor.RemoveAll("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	files, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(files, gc.HasLen, 0)
+}
+
+func (s *storageSuite) TestCaptureAndReplayOplog(c *gc.C) {
+	started := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	meta := s.oplogMetadata(started)
+	oplog := s.fakeOplog(c)
+	s.insertOplogEntry(c, oplog, started.Add(-10*time.Second), "juju.machines")
+	s.insertOplogEntry(c, oplog, started.Add(10*time.Second), "juju.machines")
+	s.insertOplogEntry(c, oplog, started.Add(20*time.Second), "presence.presence.pings")
+	s.insertOplogEntry(c, oplog, started.Add(30*time.Second), "juju.units")
+
+	stor := backups.NewOplogStorage(s.State, nil, "")
+	defer stor.Close()
+	seg, err := backups.CaptureOplog(s.Session, stor, meta, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(seg.Seq, gc.Equals, 0)
+	c.Check(seg.Entries, gc.Equals, 2)
+	c.Check(seg.From, gc.Equals, mongo.NewMongoTimestamp(started))
+	c.Check(seg.To, gc.Equals, mongo.NewMongoTimestamp(started.Add(30*time.Second)))
+
+	// Nothing has happened since.
+	seg, err = backups.CaptureOplog(s.Session, stor, meta, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(seg, gc.IsNil)
+
+	s.insertOplogEntry(c, oplog, started.Add(40*time.Second), "juju.services")
+	seg, err = backups.CaptureOplog(s.Session, stor, meta, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(seg.Seq, gc.Equals, 1)
+	c.Check(seg.Entries, gc.Equals, 1)
+
+	dumpDir := c.MkDir()
+	err = backups.AppendOplog(dumpDir, stor, meta, started.Add(30*time.Second), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(readOplogFile(c, dumpDir), jc.DeepEquals, []time.Time{
+		started.Add(10 * time.Second),
+		started.Add(30 * time.Second),
+	})
+
+	err = backups.AppendOplog(c.MkDir(), stor, meta, started.Add(time.Minute), nil)
+	c.Check(err, gc.ErrorMatches, `cannot recover to .*: operations since backup ".*" were captured only up to .*`)
+}
+
+func (s *storageSuite) TestCaptureOplogExcluded(c *gc.C) {
+	started := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	meta := s.oplogMetadata(started)
+	meta.Excluded = []string{"juju.logs", "blobstore"}
+	oplog := s.fakeOplog(c)
+	s.insertOplogEntry(c, oplog, started.Add(-10*time.Second), "juju.machines")
+	s.insertOplogEntry(c, oplog, started.Add(10*time.Second), "juju.logs")
+	s.insertOplogEntry(c, oplog, started.Add(20*time.Second), "blobstore.blobs")
+	s.insertOplogEntry(c, oplog, started.Add(30*time.Second), "juju.units")
+
+	stor := backups.NewOplogStorage(s.State, nil, "")
+	defer stor.Close()
+	seg, err := backups.CaptureOplog(s.Session, stor, meta, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(seg.Entries, gc.Equals, 1)
+	c.Check(seg.To, gc.Equals, mongo.NewMongoTimestamp(started.Add(30*time.Second)))
+
+	dumpDir := c.MkDir()
+	err = backups.AppendOplog(dumpDir, stor, meta, started.Add(30*time.Second), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(readOplogFile(c, dumpDir), jc.DeepEquals, []time.Time{started.Add(30 * time.Second)})
+}
+
+func (s *storageSuite) TestCaptureOplogRolledOver(c *gc.C) {
+	started := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	meta := s.oplogMetadata(started)
+	oplog := s.fakeOplog(c)
+	s.insertOplogEntry(c, oplog, started.Add(10*time.Second), "juju.machines")
+
+	stor := backups.NewOplogStorage(s.State, nil, "")
+	defer stor.Close()
+	_, err := backups.CaptureOplog(s.Session, stor, meta, nil)
+	c.Check(err, gc.ErrorMatches, "oplog entries since .* are no longer available")
+}
+
+func (s *storageSuite) TestCaptureOplogEncrypted(c *gc.C) {
+	started := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	meta := s.oplogMetadata(started)
+	meta.Encrypted = true
+	meta.KeyFingerprint = testing.OpenPGPKeyFingerprint
+	oplog := s.fakeOplog(c)
+	s.insertOplogEntry(c, oplog, started.Add(-10*time.Second), "juju.machines")
+	s.insertOplogEntry(c, oplog, started.Add(10*time.Second), "juju.machines")
+
+	stor := backups.NewOplogStorage(s.State, nil, "")
+	defer stor.Close()
+	_, err := backups.CaptureOplog(s.Session, stor, meta, nil)
+	c.Check(err, gc.ErrorMatches, `backup ".*" is encrypted with key .*, which is needed to capture its oplog`)
+
+	public, err := backups.ParseKey([]byte(testing.OpenPGPPublicKey))
+	c.Assert(err, jc.ErrorIsNil)
+	seg, err := backups.CaptureOplog(s.Session, stor, meta, public)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(seg.KeyFingerprint, gc.Equals, testing.OpenPGPKeyFingerprint)
+
+	err = backups.AppendOplog(c.MkDir(), stor, meta, started.Add(10*time.Second), nil)
+	c.Check(err, gc.ErrorMatches, `cannot replay oplog segment ".*": segment is encrypted with key .*; a key is needed to read it`)
+
+	private, err := backups.ParseKey([]byte(testing.OpenPGPPrivateKey))
+	c.Assert(err, jc.ErrorIsNil)
+	dumpDir := c.MkDir()
+	err = backups.AppendOplog(dumpDir, stor, meta, started.Add(10*time.Second), private)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(readOplogFile(c, dumpDir), jc.DeepEquals, []time.Time{started.Add(10 * time.Second)})
+}
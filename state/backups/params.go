@@ -4,6 +4,8 @@
 package backups
 
 import (
+	"time"
+
 	"github.com/juju/names"
 
 	"github.com/juju/juju/instance"
@@ -16,4 +18,13 @@ type RestoreArgs struct {
 	NewInstId      instance.Id
 	NewInstTag     names.Tag
 	NewInstSeries  string
+	// DecryptionKey is the key with which an encrypted backup is
+	// decrypted.
+	DecryptionKey *Key
+	// RecoverTo, if not zero, is the time up to which the database
+	// operations captured after the backup was made are replayed.
+	RecoverTo time.Time
+	// Oplog holds the operations captured after the backup was made.
+	// It is needed when RecoverTo is set.
+	Oplog *OplogStorage
 }
@@ -98,6 +98,36 @@ func updateMongoEntries(newInstId instance.Id, newMachineId, oldMachineId string
 	return nil
 }
 
+// resetExcluded drops, from the restored mongo, the databases and
+// collections whose contents were left out of the backup, so that they
+// do not keep whatever the server held before the restore, and creates
+// their collections again, empty, with their indexes.
+func resetExcluded(dialInfo *mgo.DialInfo, excluded []string) error {
+	if len(excluded) == 0 {
+		return nil
+	}
+	session, err := mgo.DialWithInfo(dialInfo)
+	if err != nil {
+		return errors.Annotate(err, "cannot connect to mongo to reset excluded collections")
+	}
+	defer session.Close()
+	for _, name := range excluded {
+		collections, ok := excludable[name]
+		if !ok {
+			return errors.Errorf("cannot reset %q", name)
+		}
+		if db, coll := splitNamespace(name); coll == "" {
+			if err := session.DB(db).DropDatabase(); err != nil {
+				return errors.Annotatef(err, "cannot drop %q", name)
+			}
+		}
+		if err := state.ResetCollections(session, collections); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 // updateMachineAddresses will update the machine doc to the current addresses
 func updateMachineAddresses(machine *state.Machine, privateAddress, publicAddress string) error {
 	privateAddressAddress := network.Address{
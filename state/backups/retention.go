@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"sort"
+)
+
+// RetentionPolicy determines which scheduled backups are kept. A backup
+// is kept if any part of the policy keeps it.
+type RetentionPolicy struct {
+	// KeepLast is the number of the most recent backups kept.
+	KeepLast int
+
+	// KeepDaily is the number of days, among the most recent days on
+	// which backups were made, for which the last backup of the day
+	// is kept.
+	KeepDaily int
+
+	// KeepWeekly is the number of ISO weeks, among the most recent
+	// weeks in which backups were made, for which the last backup of
+	// the week is kept.
+	KeepWeekly int
+}
+
+// Expired returns the scheduled backups in the given list that the
+// policy does not keep, oldest first. Backups made by hand, and the
+// most recent scheduled backup, are never expired. Days and weeks are
+// reckoned in UTC, by the time at which each backup was started.
+func (p RetentionPolicy) Expired(metas []*Metadata) []*Metadata {
+	var scheduled []*Metadata
+	for _, meta := range metas {
+		if meta.Scheduled {
+			scheduled = append(scheduled, meta)
+		}
+	}
+	// Newest first.
+	sort.Sort(sort.Reverse(byStarted(scheduled)))
+
+	keep := make(map[*Metadata]bool)
+	for i, meta := range scheduled {
+		if i == 0 || i < p.KeepLast {
+			keep[meta] = true
+		}
+	}
+	keepPeriods(scheduled, p.KeepDaily, keep, func(meta *Metadata) interface{} {
+		year, month, day := meta.Started.UTC().Date()
+		return [3]int{year, int(month), day}
+	})
+	keepPeriods(scheduled, p.KeepWeekly, keep, func(meta *Metadata) interface{} {
+		year, week := meta.Started.UTC().ISOWeek()
+		return [2]int{year, week}
+	})
+
+	var expired []*Metadata
+	for i := len(scheduled) - 1; i >= 0; i-- {
+		if !keep[scheduled[i]] {
+			expired = append(expired, scheduled[i])
+		}
+	}
+	return expired
+}
+
+// keepPeriods marks as kept the newest backup in each of the count most
+// recent periods in which backups were made. The backups must be sorted
+// newest first.
+func keepPeriods(newestFirst []*Metadata, count int, keep map[*Metadata]bool, period func(*Metadata) interface{}) {
+	seen := make(map[interface{}]bool)
+	for _, meta := range newestFirst {
+		if len(seen) >= count {
+			return
+		}
+		key := period(meta)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keep[meta] = true
+	}
+}
+
+type byStarted []*Metadata
+
+func (b byStarted) Len() int           { return len(b) }
+func (b byStarted) Less(i, j int) bool { return b[i].Started.Before(b[j].Started) }
+func (b byStarted) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+)
+
+type retentionSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&retentionSuite{})
+
+// newBackups returns metadata for scheduled backups made every 12 hours,
+// newest first, starting at midday on Sunday 2015-10-18, along with a
+// backup made by hand.
+func (s *retentionSuite) newBackups(count int) (scheduled []*backups.Metadata, manual *backups.Metadata) {
+	start := time.Date(2015, time.October, 18, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		meta := backups.NewMetadata()
+		meta.SetID(start.Add(time.Duration(-12*i) * time.Hour).Format("20060102-1504"))
+		meta.Started = start.Add(time.Duration(-12*i) * time.Hour)
+		meta.Scheduled = true
+		scheduled = append(scheduled, meta)
+	}
+	manual = backups.NewMetadata()
+	manual.SetID("manual")
+	manual.Started = start.Add(-100 * 24 * time.Hour)
+	return scheduled, manual
+}
+
+func ids(metas []*backups.Metadata) []string {
+	var result []string
+	for _, meta := range metas {
+		result = append(result, meta.ID())
+	}
+	return result
+}
+
+func (s *retentionSuite) TestKeepLast(c *gc.C) {
+	scheduled, manual := s.newBackups(5)
+	policy := backups.RetentionPolicy{KeepLast: 2}
+	expired := policy.Expired(append(scheduled, manual))
+	c.Check(ids(expired), gc.DeepEquals, []string{
+		"20151016-1200",
+		"20151017-0000",
+		"20151017-1200",
+	})
+}
+
+func (s *retentionSuite) TestKeepsNewest(c *gc.C) {
+	scheduled, manual := s.newBackups(3)
+	expired := backups.RetentionPolicy{}.Expired(append(scheduled, manual))
+	c.Check(ids(expired), gc.DeepEquals, []string{
+		"20151017-1200",
+		"20151018-0000",
+	})
+}
+
+func (s *retentionSuite) TestKeepDaily(c *gc.C) {
+	scheduled, _ := s.newBackups(6)
+	policy := backups.RetentionPolicy{KeepDaily: 2}
+	expired := policy.Expired(scheduled)
+	// The last backups of the 18th and the 17th are kept.
+	c.Check(ids(expired), gc.DeepEquals, []string{
+		"20151016-0000",
+		"20151016-1200",
+		"20151017-0000",
+		"20151018-0000",
+	})
+}
+
+func (s *retentionSuite) TestKeepWeekly(c *gc.C) {
+	scheduled, _ := s.newBackups(20)
+	policy := backups.RetentionPolicy{KeepLast: 1, KeepWeekly: 2}
+	expired := policy.Expired(scheduled)
+	// The 18th is a Sunday, so the last backup of the previous ISO
+	// week is the one made at midday on the 11th.
+	c.Check(ids(expired), gc.HasLen, 18)
+	for _, meta := range expired {
+		c.Check(meta.ID(), gc.Not(gc.Equals), "20151011-1200")
+		c.Check(meta.ID(), gc.Not(gc.Equals), "20151018-1200")
+	}
+}
+
+func (s *retentionSuite) TestCombined(c *gc.C) {
+	scheduled, _ := s.newBackups(6)
+	policy := backups.RetentionPolicy{KeepLast: 2, KeepDaily: 3}
+	expired := policy.Expired(scheduled)
+	c.Check(ids(expired), gc.DeepEquals, []string{
+		"20151016-0000",
+		"20151017-0000",
+	})
+}
@@ -38,12 +38,17 @@ type storageMetaDoc struct {
 	ChecksumFormat string `bson:"checksumformat"`
 	Size           int64  `bson:"size,minsize"`
 	Stored         int64  `bson:"stored,minsize"`
+	Location       string `bson:"location,omitempty"`
 
 	// backup
 
-	Started  int64  `bson:"started,minsize"`
-	Finished int64  `bson:"finished,minsize"`
-	Notes    string `bson:"notes,omitempty"`
+	Started        int64    `bson:"started,minsize"`
+	Finished       int64    `bson:"finished,minsize"`
+	Notes          string   `bson:"notes,omitempty"`
+	Scheduled      bool     `bson:"scheduled,omitempty"`
+	Encrypted      bool     `bson:"encrypted,omitempty"`
+	KeyFingerprint string   `bson:"keyfingerprint,omitempty"`
+	Excluded       []string `bson:"excluded,omitempty"`
 
 	// origin
 
@@ -120,6 +125,10 @@ func docAsMetadata(doc *storageMetaDoc) *Metadata {
 	meta := NewMetadata()
 	meta.Started = metadocUnixToTime(doc.Started)
 	meta.Notes = doc.Notes
+	meta.Scheduled = doc.Scheduled
+	meta.Encrypted = doc.Encrypted
+	meta.KeyFingerprint = doc.KeyFingerprint
+	meta.Excluded = doc.Excluded
 
 	meta.Origin.Environment = doc.Environment
 	meta.Origin.Machine = doc.Machine
@@ -170,6 +179,10 @@ func newStorageMetaDoc(meta *Metadata) storageMetaDoc {
 		doc.Finished = metadocTimeToUnix(*meta.Finished)
 	}
 	doc.Notes = meta.Notes
+	doc.Scheduled = meta.Scheduled
+	doc.Encrypted = meta.Encrypted
+	doc.KeyFingerprint = meta.KeyFingerprint
+	doc.Excluded = meta.Excluded
 
 	doc.Environment = meta.Origin.Environment
 	doc.Machine = meta.Origin.Machine
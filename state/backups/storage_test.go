@@ -118,6 +118,18 @@ func (s *storageSuite) TestAddBackupMetadataSuccess(c *gc.C) {
 	s.checkMeta(c, meta, original, id)
 }
 
+func (s *storageSuite) TestAddBackupMetadataExcluded(c *gc.C) {
+	original := s.metadata(c)
+	original.Excluded = []string{"logs", "juju.statuseshistory"}
+	id, err := backups.AddBackupMetadata(s.State, original)
+	c.Assert(err, jc.ErrorIsNil)
+
+	meta, err := backups.GetBackupMetadata(s.State, id)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(meta.Excluded, jc.DeepEquals, []string{"logs", "juju.statuseshistory"})
+}
+
 func (s *storageSuite) TestAddBackupMetadataGeneratedID(c *gc.C) {
 	original := s.metadata(c)
 	original.SetID("spam")
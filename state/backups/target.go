@@ -0,0 +1,246 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"crypto/sha1"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/filestorage"
+	"github.com/juju/utils/hash"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/environs/config"
+)
+
+// BackupStorage is a place, away from the state server, in which backup
+// archives are kept, so that they are not lost along with it. Archives
+// are identified by name. Their metadata is still kept in state, but
+// each archive also holds its own.
+type BackupStorage interface {
+	// Put stores the archive, of the given size, under the given name.
+	Put(name string, archive io.Reader, size int64) error
+
+	// Get returns the named archive. If there is no such archive, an
+	// error satisfying errors.IsNotFound is returned.
+	Get(name string) (io.ReadCloser, error)
+
+	// Remove removes the named archive.
+	Remove(name string) error
+
+	io.Closer
+}
+
+// NewBackupStorage returns the BackupStorage named by the given
+// environment config's backup-storage setting, or nil if it is not set.
+// Archives kept over SFTP are accessed with the state server's system
+// identity, found in the given data directory.
+func NewBackupStorage(cfg *config.Config, dataDir string) (BackupStorage, error) {
+	rawURL := cfg.BackupStorage()
+	if rawURL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch u.Scheme {
+	case "file":
+		return NewLocalStorage(u.Path), nil
+	case "s3":
+		return newS3Storage(u, cfg.BackupStorageAccessKey(), cfg.BackupStorageSecretKey())
+	case "sftp":
+		identity := filepath.Join(dataDir, agent.SystemIdentity)
+		stor, err := newSFTPStorage(u, identity, cfg.BackupStorageHostKey())
+		return stor, errors.Trace(err)
+	}
+	return nil, errors.NotSupportedf("backup storage %q", rawURL)
+}
+
+// OpenStorage returns the FileStorage in which the environment's backups
+// are kept. If the environment config names a BackupStorage, archives
+// are added there, with their metadata kept in state; otherwise they
+// are kept in state too, as by NewStorage. Archives which were added to
+// state before the BackupStorage was set remain available.
+func OpenStorage(st DB, cfg *config.Config, dataDir string) (filestorage.FileStorage, error) {
+	target, err := NewBackupStorage(cfg, dataDir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if target == nil {
+		return NewStorage(st), nil
+	}
+	return NewTargetStorage(st, target, cfg.BackupStorage()), nil
+}
+
+// NewTargetStorage returns a new FileStorage which keeps backup archives
+// in the given BackupStorage, and their metadata in state. The location
+// identifies the BackupStorage, and is recorded with each archive added.
+func NewTargetStorage(st DB, target BackupStorage, location string) filestorage.FileStorage {
+	envUUID := st.EnvironTag().Id()
+	db := st.MongoSession().DB(storageDBName)
+	dbWrap := newStorageDBWrapper(db, storageMetaName, envUUID)
+	defer dbWrap.Close()
+
+	files := &targetFileStorage{
+		dbWrap:   dbWrap.Copy(),
+		target:   target,
+		location: location,
+		state:    newFileStorage(dbWrap, backupStorageRoot),
+	}
+	docs := newMetadataStorage(dbWrap)
+	return filestorage.NewFileStorage(docs, files)
+}
+
+// targetFileStorage is a filestorage.RawFileStorage which adds archives
+// to a BackupStorage, and verifies them once they are there. Archives
+// which were added to state are read from and removed from there.
+type targetFileStorage struct {
+	dbWrap   *storageDBWrapper
+	target   BackupStorage
+	location string
+	state    filestorage.RawFileStorage
+}
+
+// name returns the name of the identified archive in the BackupStorage.
+func (s *targetFileStorage) name(id string) string {
+	return id + ".tar.gz"
+}
+
+// storage returns the storage in which the identified archive is kept,
+// with the name it is kept under there.
+func (s *targetFileStorage) storage(id string) (BackupStorage, string, error) {
+	dbWrap := s.dbWrap.Copy()
+	defer dbWrap.Close()
+
+	doc, err := getStorageMetadata(dbWrap, id)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	switch doc.Location {
+	case "":
+		return stateStorage{s.state}, id, nil
+	case s.location:
+		return s.target, s.name(id), nil
+	}
+	return nil, "", errors.Errorf("backup %q is kept in %s, not in %s", id, doc.Location, s.location)
+}
+
+// File returns the identified file from storage.
+func (s *targetFileStorage) File(id string) (io.ReadCloser, error) {
+	stor, name, err := s.storage(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	file, err := stor.Get(name)
+	return file, errors.Trace(err)
+}
+
+// AddFile adds the file to the BackupStorage, and checks that what was
+// stored matches the checksum in the file's metadata. If it does not,
+// the stored file is removed again.
+func (s *targetFileStorage) AddFile(id string, file io.Reader, size int64) error {
+	dbWrap := s.dbWrap.Copy()
+	defer dbWrap.Close()
+
+	doc, err := getStorageMetadata(dbWrap, id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if doc.Checksum == "" || doc.ChecksumFormat != checksumFormat {
+		return errors.Errorf("cannot verify backup %q: no %s checksum", id, checksumFormat)
+	}
+
+	name := s.name(id)
+	if err := s.target.Put(name, file, size); err != nil {
+		return errors.Annotatef(err, "cannot add backup %q to %s", id, s.location)
+	}
+	if err := s.verify(name, doc.Checksum); err != nil {
+		if err := s.target.Remove(name); err != nil {
+			logger.Errorf("cannot remove unverified backup %q from %s: %v", id, s.location, err)
+		}
+		return errors.Annotatef(err, "cannot verify backup %q in %s", id, s.location)
+	}
+	return errors.Trace(setStorageLocation(dbWrap, id, s.location))
+}
+
+// verify checks that the named archive in the BackupStorage has the
+// given checksum.
+func (s *targetFileStorage) verify(name, checksum string) error {
+	archive, err := s.target.Get(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+
+	hasher := hash.NewHashingWriter(ioutil.Discard, sha1.New())
+	if _, err := io.Copy(hasher, archive); err != nil {
+		return errors.Trace(err)
+	}
+	if stored := hasher.Base64Sum(); stored != checksum {
+		return errors.Errorf("checksum mismatch: expected %q, got %q", checksum, stored)
+	}
+	return nil
+}
+
+// RemoveFile removes the identified file from storage.
+func (s *targetFileStorage) RemoveFile(id string) error {
+	stor, name, err := s.storage(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(stor.Remove(name))
+}
+
+// Close closes the storage.
+func (s *targetFileStorage) Close() error {
+	err := s.target.Close()
+	if err := s.state.Close(); err != nil {
+		logger.Errorf("cannot close backup storage: %v", err)
+	}
+	s.dbWrap.Close()
+	return errors.Trace(err)
+}
+
+// stateStorage adapts the state's raw file storage to BackupStorage, so
+// that archives added to state before a BackupStorage was set can be
+// treated alike.
+type stateStorage struct {
+	raw filestorage.RawFileStorage
+}
+
+func (s stateStorage) Put(name string, archive io.Reader, size int64) error {
+	return s.raw.AddFile(name, archive, size)
+}
+
+func (s stateStorage) Get(name string) (io.ReadCloser, error) {
+	return s.raw.File(name)
+}
+
+func (s stateStorage) Remove(name string) error {
+	return s.raw.RemoveFile(name)
+}
+
+func (s stateStorage) Close() error {
+	return nil
+}
+
+// setStorageLocation records, in the backup metadata associated with
+// "id", the location of the BackupStorage in which the archive is kept.
+func setStorageLocation(dbWrap *storageDBWrapper, id, location string) error {
+	op := dbWrap.txnOpUpdate(id, bson.DocElem{"location", location})
+	if err := dbWrap.runTransaction([]txn.Op{op}); err != nil {
+		if errors.Cause(err) == txn.ErrAborted {
+			return errors.NotFoundf("backup metadata %q", id)
+		}
+		return errors.Annotate(err, "while running transaction")
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+)
+
+// localStorage is a BackupStorage which keeps archives in a directory,
+// such as an NFS mount, on the state server's filesystem.
+type localStorage struct {
+	dir string
+}
+
+// NewLocalStorage returns a BackupStorage which keeps archives in the
+// given directory. The directory is created when the first archive is
+// added, if it does not already exist.
+func NewLocalStorage(dir string) BackupStorage {
+	return &localStorage{dir: dir}
+}
+
+// Put implements BackupStorage. The archive is written to a temporary
+// file first, so that a partly written archive is never seen under the
+// given name.
+func (s *localStorage) Put(name string, archive io.Reader, size int64) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return errors.Trace(err)
+	}
+	file, err := ioutil.TempFile(s.dir, "."+name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(file.Name())
+
+	written, err := io.Copy(file, archive)
+	if err := file.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if written != size {
+		return errors.Errorf("expected %d bytes, got %d", size, written)
+	}
+	return errors.Trace(os.Rename(file.Name(), s.path(name)))
+}
+
+// Get implements BackupStorage.
+func (s *localStorage) Get(name string) (io.ReadCloser, error) {
+	file, err := os.Open(s.path(name))
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("backup archive %q", name)
+	}
+	return file, errors.Trace(err)
+}
+
+// Remove implements BackupStorage.
+func (s *localStorage) Remove(name string) error {
+	err := os.Remove(s.path(name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// Close implements BackupStorage.
+func (s *localStorage) Close() error {
+	return nil
+}
+
+func (s *localStorage) path(name string) string {
+	return filepath.Join(s.dir, name)
+}
@@ -0,0 +1,130 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/s3"
+)
+
+// s3Storage is a BackupStorage which keeps archives in a bucket of an
+// S3-compatible object store.
+type s3Storage struct {
+	mu         sync.Mutex
+	madeBucket bool
+	bucket     *s3.Bucket
+	prefix     string
+}
+
+// newS3Storage returns a BackupStorage which keeps archives in the
+// bucket named by the host part of the given s3 URL, under the prefix
+// given by its path. The bucket is in the AWS region given by the
+// "region" query parameter, us-east-1 by default; for other object
+// stores, the "endpoint" query parameter gives the URL of the store.
+func newS3Storage(u *url.URL, accessKey, secretKey string) (BackupStorage, error) {
+	query := u.Query()
+	regionName := query.Get("region")
+	if regionName == "" {
+		regionName = "us-east-1"
+	}
+	region, ok := aws.Regions[regionName]
+	if endpoint := query.Get("endpoint"); endpoint != "" {
+		region = aws.Region{
+			Name:       regionName,
+			S3Endpoint: endpoint,
+		}
+	} else if !ok {
+		return nil, errors.NotValidf("region %q", regionName)
+	}
+
+	auth := aws.Auth{AccessKey: accessKey, SecretKey: secretKey}
+	bucket, err := s3.New(auth, region).Bucket(u.Host)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &s3Storage{
+		bucket: bucket,
+		prefix: strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+// makeBucket makes the bucket, if it has not already been made. This is
+// done only once, to avoid a round trip on every Put.
+func (s *s3Storage) makeBucket() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.madeBucket {
+		return nil
+	}
+	// PutBucket returns 200 if the bucket already exists at the
+	// original s3.amazonaws.com endpoint, and 409 with a known code
+	// at all others.
+	if err := s.bucket.PutBucket(s3.Private); err != nil && s3ErrorCode(err) != "BucketAlreadyOwnedByYou" {
+		return errors.Annotatef(err, "cannot make bucket %q", s.bucket.Name)
+	}
+	s.madeBucket = true
+	return nil
+}
+
+// Put implements BackupStorage.
+func (s *s3Storage) Put(name string, archive io.Reader, size int64) error {
+	if err := s.makeBucket(); err != nil {
+		return errors.Trace(err)
+	}
+	err := s.bucket.PutReader(s.key(name), archive, size, "application/octet-stream", s3.Private)
+	return errors.Trace(err)
+}
+
+// Get implements BackupStorage.
+func (s *s3Storage) Get(name string) (io.ReadCloser, error) {
+	archive, err := s.bucket.GetReader(s.key(name))
+	if s3ErrorStatusCode(err) == 404 {
+		return nil, errors.NotFoundf("backup archive %q", name)
+	}
+	return archive, errors.Trace(err)
+}
+
+// Remove implements BackupStorage.
+func (s *s3Storage) Remove(name string) error {
+	err := s.bucket.Del(s.key(name))
+	if s3ErrorStatusCode(err) == 404 {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// Close implements BackupStorage.
+func (s *s3Storage) Close() error {
+	return nil
+}
+
+// key returns the key of the named archive in the bucket.
+func (s *s3Storage) key(name string) string {
+	// Object keys are always separated by slashes.
+	return path.Join(s.prefix, name)
+}
+
+// s3ErrorStatusCode returns the HTTP status of the S3 request error,
+// if it is an error from an S3 operation, or 0 if it was not.
+func s3ErrorStatusCode(err error) int {
+	if err, _ := err.(*s3.Error); err != nil {
+		return err.StatusCode
+	}
+	return 0
+}
+
+// s3ErrorCode returns the text status code of the S3 error code.
+func s3ErrorCode(err error) string {
+	if err, _ := err.(*s3.Error); err != nil {
+		return err.Code
+	}
+	return ""
+}
@@ -0,0 +1,229 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// sftpStorage is a BackupStorage which keeps archives in a directory on
+// a remote host, accessed with the OpenSSH sftp client.
+type sftpStorage struct {
+	// host is the host to connect to, in the form [user@]host.
+	host string
+
+	// hostname is the name of the host, without the user.
+	hostname string
+
+	// port is the port to connect to, if not the default.
+	port string
+
+	// hostKeys holds the public keys, one per line, which the host
+	// must present. The connection fails if it presents any other.
+	hostKeys []string
+
+	// dir is the directory, on the remote host, in which archives
+	// are kept.
+	dir string
+
+	// identity is the path of the private key with which the
+	// connection is authenticated.
+	identity string
+}
+
+// newSFTPStorage returns a BackupStorage which keeps archives in the
+// directory on the remote host given by the sftp URL, authenticating
+// with the private key in the given identity file. The host must
+// present one of the given public host keys, one per line.
+func newSFTPStorage(u *url.URL, identity, hostKeys string) (BackupStorage, error) {
+	hostname := u.Host
+	port := ""
+	if i := strings.LastIndex(hostname, ":"); i >= 0 {
+		hostname, port = hostname[:i], hostname[i+1:]
+	}
+	host := hostname
+	if u.User != nil {
+		host = u.User.Username() + "@" + host
+	}
+	var keys []string
+	for _, line := range strings.Split(hostKeys, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			keys = append(keys, line)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.Errorf("no host key given for %s", hostname)
+	}
+	return &sftpStorage{
+		host:     host,
+		hostname: hostname,
+		port:     port,
+		hostKeys: keys,
+		dir:      u.Path,
+		identity: identity,
+	}, nil
+}
+
+// writeKnownHosts writes a known_hosts file holding only the storage's
+// host keys, and returns its path.
+func (s *sftpStorage) writeKnownHosts() (string, error) {
+	pattern := s.hostname
+	if s.port != "" {
+		pattern = fmt.Sprintf("[%s]:%s", s.hostname, s.port)
+	}
+	var lines []string
+	for _, key := range s.hostKeys {
+		lines = append(lines, pattern+" "+key)
+	}
+	file, err := ioutil.TempFile("", "juju-backup-known-hosts")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	_, err = file.WriteString(strings.Join(lines, "\n") + "\n")
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", errors.Trace(err)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", errors.Trace(err)
+	}
+	return file.Name(), nil
+}
+
+// run runs the given commands in a single sftp session. The session
+// fails as soon as one of them does, unless the command is prefixed
+// with "-".
+func (s *sftpStorage) run(commands ...string) error {
+	batch, err := ioutil.TempFile("", "juju-backup-sftp")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(batch.Name())
+	_, err = batch.WriteString(strings.Join(commands, "\n") + "\n")
+	if err := batch.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// The archives hold the state server's credentials, so the host
+	// is only trusted if it presents one of the configured keys.
+	knownHosts, err := s.writeKnownHosts()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(knownHosts)
+
+	args := []string{
+		"-b", batch.Name(),
+		"-i", s.identity,
+		"-o", "StrictHostKeyChecking yes",
+		"-o", "UserKnownHostsFile " + knownHosts,
+		"-o", "GlobalKnownHostsFile /dev/null",
+		"-o", "PasswordAuthentication no",
+	}
+	if s.port != "" {
+		args = append(args, "-P", s.port)
+	}
+	args = append(args, s.host)
+	return errors.Trace(runCommand("sftp", args...))
+}
+
+// Put implements BackupStorage. The archive is uploaded under a
+// temporary name first, so that a partly written archive is never seen
+// under the given name.
+func (s *sftpStorage) Put(name string, archive io.Reader, size int64) error {
+	file, err := ioutil.TempFile("", "juju-backup")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(file.Name())
+	written, err := io.Copy(file, archive)
+	if err := file.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if written != size {
+		return errors.Errorf("expected %d bytes, got %d", size, written)
+	}
+
+	partial := s.path("." + name + ".part")
+	err = s.run(
+		fmt.Sprintf("-mkdir %s", quote(s.dir)),
+		fmt.Sprintf("put %s %s", quote(file.Name()), quote(partial)),
+		fmt.Sprintf("rename %s %s", quote(partial), quote(s.path(name))),
+	)
+	return errors.Annotatef(err, "cannot put %q on %s", name, s.host)
+}
+
+// Get implements BackupStorage. The archive is downloaded to a temporary
+// file, which is removed when the returned reader is closed.
+func (s *sftpStorage) Get(name string) (io.ReadCloser, error) {
+	file, err := ioutil.TempFile("", "juju-backup")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	file.Close()
+
+	err = s.run(fmt.Sprintf("get %s %s", quote(s.path(name)), quote(file.Name())))
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, errors.Annotatef(err, "cannot get %q from %s", name, s.host)
+	}
+	archive, err := os.Open(file.Name())
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, errors.Trace(err)
+	}
+	return &tempFile{archive}, nil
+}
+
+// Remove implements BackupStorage.
+func (s *sftpStorage) Remove(name string) error {
+	err := s.run(fmt.Sprintf("rm %s", quote(s.path(name))))
+	return errors.Annotatef(err, "cannot remove %q from %s", name, s.host)
+}
+
+// Close implements BackupStorage.
+func (s *sftpStorage) Close() error {
+	return nil
+}
+
+// path returns the path of the named archive on the remote host.
+func (s *sftpStorage) path(name string) string {
+	// The remote host's paths are always separated by slashes.
+	return path.Join(s.dir, name)
+}
+
+// quote quotes the path for use in an sftp batch file.
+func quote(p string) string {
+	return `"` + strings.Replace(p, `"`, `\"`, -1) + `"`
+}
+
+// tempFile is a file which is removed when it is closed.
+type tempFile struct {
+	*os.File
+}
+
+// Close closes and removes the file.
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	if err := os.Remove(f.Name()); err != nil {
+		logger.Errorf("cannot remove %q: %v", f.Name(), err)
+	}
+	return errors.Trace(err)
+}
@@ -0,0 +1,239 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"gopkg.in/amz.v3/s3/s3test"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/testing"
+	sshtesting "github.com/juju/juju/utils/ssh/testing"
+)
+
+type targetSuite struct {
+	jujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&targetSuite{})
+
+func (s *targetSuite) checkStorage(c *gc.C, stor backups.BackupStorage) {
+	_, err := stor.Get("spam.tar.gz")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+
+	err = stor.Put("spam.tar.gz", bytes.NewBufferString("<archive>"), 9)
+	c.Assert(err, jc.ErrorIsNil)
+	archive, err := stor.Get("spam.tar.gz")
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadAll(archive)
+	archive.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "<archive>")
+
+	err = stor.Remove("spam.tar.gz")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = stor.Get("spam.tar.gz")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(stor.Close(), jc.ErrorIsNil)
+}
+
+func (s *targetSuite) TestNewBackupStorageNotSet(c *gc.C) {
+	stor, err := backups.NewBackupStorage(testing.EnvironConfig(c), "/var/lib/juju")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stor, gc.IsNil)
+}
+
+func (s *targetSuite) TestLocalStorage(c *gc.C) {
+	dir := filepath.Join(c.MkDir(), "backups")
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"backup-storage": "file://" + dir,
+	})
+	stor, err := backups.NewBackupStorage(cfg, "/var/lib/juju")
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStorage(c, stor)
+
+	// Nothing is left behind.
+	files, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(files, gc.HasLen, 0)
+}
+
+func (s *targetSuite) TestLocalStorageShortArchive(c *gc.C) {
+	dir := c.MkDir()
+	stor := backups.NewLocalStorage(dir)
+	err := stor.Put("spam.tar.gz", bytes.NewBufferString("<arch"), 9)
+	c.Check(err, gc.ErrorMatches, "expected 9 bytes, got 5")
+
+	_, err = os.Stat(filepath.Join(dir, "spam.tar.gz"))
+	c.Check(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *targetSuite) TestS3Storage(c *gc.C) {
+	srv, err := s3test.NewServer(&s3test.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer srv.Quit()
+
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"backup-storage":            "s3://juju-backups/prod?endpoint=" + srv.URL(),
+		"backup-storage-access-key": "access",
+		"backup-storage-secret-key": "secret",
+	})
+	stor, err := backups.NewBackupStorage(cfg, "/var/lib/juju")
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStorage(c, stor)
+}
+
+func (s *targetSuite) TestS3StorageUnknownRegion(c *gc.C) {
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"backup-storage":            "s3://juju-backups?region=nowhere",
+		"backup-storage-access-key": "access",
+		"backup-storage-secret-key": "secret",
+	})
+	_, err := backups.NewBackupStorage(cfg, "/var/lib/juju")
+	c.Check(err, gc.ErrorMatches, `region "nowhere" not valid`)
+}
+
+func (s *targetSuite) TestSFTPStoragePut(c *gc.C) {
+	var args []string
+	var batch, knownHosts string
+	s.PatchValue(backups.RunCommand, func(command string, cmdArgs ...string) error {
+		c.Check(command, gc.Equals, "sftp")
+		args = cmdArgs
+		data, err := ioutil.ReadFile(cmdArgs[1])
+		c.Assert(err, jc.ErrorIsNil)
+		batch = string(data)
+		c.Assert(cmdArgs[7], jc.HasPrefix, "UserKnownHostsFile ")
+		data, err = ioutil.ReadFile(strings.TrimPrefix(cmdArgs[7], "UserKnownHostsFile "))
+		c.Assert(err, jc.ErrorIsNil)
+		knownHosts = string(data)
+		return nil
+	})
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"backup-storage":          "sftp://backup@nas.example.com:2222/srv/backups",
+		"backup-storage-host-key": sshtesting.ValidKeyOne.Key + "\n" + sshtesting.ValidKeyTwo.Key,
+	})
+	stor, err := backups.NewBackupStorage(cfg, "/var/lib/juju")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = stor.Put("spam.tar.gz", bytes.NewBufferString("<archive>"), 9)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(args[2:], jc.DeepEquals, []string{
+		"-i", "/var/lib/juju/system-identity",
+		"-o", "StrictHostKeyChecking yes",
+		"-o", args[7],
+		"-o", "GlobalKnownHostsFile /dev/null",
+		"-o", "PasswordAuthentication no",
+		"-P", "2222",
+		"backup@nas.example.com",
+	})
+	c.Check(knownHosts, gc.Equals, ""+
+		"[nas.example.com]:2222 "+sshtesting.ValidKeyOne.Key+"\n"+
+		"[nas.example.com]:2222 "+sshtesting.ValidKeyTwo.Key+"\n")
+	lines := strings.Split(strings.TrimSpace(batch), "\n")
+	c.Assert(lines, gc.HasLen, 3)
+	c.Check(lines[0], gc.Equals, `-mkdir "/srv/backups"`)
+	c.Check(lines[1], gc.Matches, `put ".*" "/srv/backups/.spam.tar.gz.part"`)
+	c.Check(lines[2], gc.Equals, `rename "/srv/backups/.spam.tar.gz.part" "/srv/backups/spam.tar.gz"`)
+}
+
+func (s *targetSuite) TestSFTPStorageFailure(c *gc.C) {
+	s.PatchValue(backups.RunCommand, func(string, ...string) error {
+		return errors.New("Host key verification failed.")
+	})
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"backup-storage":          "sftp://nas.example.com/srv/backups",
+		"backup-storage-host-key": sshtesting.ValidKeyOne.Key,
+	})
+	stor, err := backups.NewBackupStorage(cfg, "/var/lib/juju")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = stor.Get("spam.tar.gz")
+	c.Check(err, gc.ErrorMatches, `cannot get "spam.tar.gz" from nas.example.com: Host key verification failed.`)
+}
+
+// archiveMetadata returns metadata for an archive of the given size,
+// with the SHA-1 checksum of the given data.
+func (s *storageSuite) archiveMetadata(c *gc.C, size int, data []byte) *backups.Metadata {
+	meta := backups.NewMetadata()
+	meta.Origin.Environment = s.State.EnvironUUID()
+	meta.Origin.Machine = "0"
+	meta.Origin.Hostname = "localhost"
+	sum := sha1.Sum(data)
+	err := meta.MarkComplete(int64(size), base64.StdEncoding.EncodeToString(sum[:]))
+	c.Assert(err, jc.ErrorIsNil)
+	return meta
+}
+
+func (s *storageSuite) TestTargetStorage(c *gc.C) {
+	dir := c.MkDir()
+	stor := backups.NewTargetStorage(s.State, backups.NewLocalStorage(dir), "file://"+dir)
+	defer stor.Close()
+
+	data := []byte("<archive>")
+	meta := s.archiveMetadata(c, len(data), data)
+	id, err := stor.Add(meta, bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+
+	stored, err := ioutil.ReadFile(filepath.Join(dir, id+".tar.gz"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stored, jc.DeepEquals, data)
+
+	_, archive, err := stor.Get(id)
+	c.Assert(err, jc.ErrorIsNil)
+	got, err := ioutil.ReadAll(archive)
+	archive.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(got, jc.DeepEquals, data)
+
+	err = stor.Remove(id)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(filepath.Join(dir, id+".tar.gz"))
+	c.Check(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *storageSuite) TestTargetStorageChecksumMismatch(c *gc.C) {
+	dir := c.MkDir()
+	stor := backups.NewTargetStorage(s.State, backups.NewLocalStorage(dir), "file://"+dir)
+	defer stor.Close()
+
+	data := []byte("<archive>")
+	meta := s.archiveMetadata(c, len(data), []byte("<something else>"))
+	_, err := stor.Add(meta, bytes.NewReader(data))
+	c.Check(err, gc.ErrorMatches, `.*cannot verify backup ".*" in file://.*: checksum mismatch: .*`)
+
+	files, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(files, gc.HasLen, 0)
+}
+
+func (s *storageSuite) TestTargetStorageReadsStateArchives(c *gc.C) {
+	data := []byte("<archive>")
+	meta := s.archiveMetadata(c, len(data), data)
+	stateStor := backups.NewStorage(s.State)
+	id, err := stateStor.Add(meta, bytes.NewReader(data))
+	stateStor.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	dir := c.MkDir()
+	stor := backups.NewTargetStorage(s.State, backups.NewLocalStorage(dir), "file://"+dir)
+	defer stor.Close()
+	_, archive, err := stor.Get(id)
+	c.Assert(err, jc.ErrorIsNil)
+	got, err := ioutil.ReadAll(archive)
+	archive.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(got, jc.DeepEquals, data)
+}
@@ -37,6 +37,8 @@ type FakeBackups struct {
 	DBInfoArg *backups.DBInfo
 	// MetaArg holds the backup metadata that was passed in.
 	MetaArg *backups.Metadata
+	// KeyArg holds the encryption or decryption key that was passed in.
+	KeyArg *backups.Key
 	// PrivateAddr Holds the address for the internal network of the machine.
 	PrivateAddr string
 	// InstanceId Is the id of the machine to be restored.
@@ -49,12 +51,13 @@ var _ backups.Backups = (*FakeBackups)(nil)
 
 // Create creates and stores a new juju backup archive and returns
 // its associated metadata.
-func (b *FakeBackups) Create(meta *backups.Metadata, paths *backups.Paths, dbInfo *backups.DBInfo) error {
+func (b *FakeBackups) Create(meta *backups.Metadata, paths *backups.Paths, dbInfo *backups.DBInfo, key *backups.Key) error {
 	b.Calls = append(b.Calls, "Create")
 
 	b.PathsArg = paths
 	b.DBInfoArg = dbInfo
 	b.MetaArg = meta
+	b.KeyArg = key
 
 	if b.Meta != nil {
 		*meta = *b.Meta
@@ -100,6 +103,7 @@ func (b *FakeBackups) Restore(bkpId string, args backups.RestoreArgs) error {
 	b.Calls = append(b.Calls, "Restore")
 	b.PrivateAddr = args.PrivateAddress
 	b.InstanceId = args.NewInstId
+	b.KeyArg = args.DecryptionKey
 	return errors.Trace(b.Error)
 }
 
@@ -0,0 +1,225 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/mongo"
+)
+
+// Verification describes a backup archive that was checked by Verify.
+type Verification struct {
+	// Metadata is the metadata found in the archive.
+	Metadata *Metadata
+
+	// Environments summarises each environment found in the archive's
+	// database dump.
+	Environments []EnvironmentSummary
+}
+
+// EnvironmentSummary summarises an environment found in the database
+// dump of a backup archive.
+type EnvironmentSummary struct {
+	UUID     string
+	Name     string
+	Machines int
+	Units    int
+}
+
+// Verify checks that the backup archive read from r could be restored.
+// The archive is unpacked, the state-related files it holds are checked
+// against those that every backup must hold, and its database dump is
+// restored into a temporary mongod, run just for the purpose, from
+// which the environments are summarised. No state server is involved.
+// If the archive is encrypted, it is decrypted with the given key,
+// which must then not be nil.
+func Verify(r io.Reader, key *Key) (*Verification, error) {
+	ws, err := NewArchiveWorkspaceReader(r, key)
+	if err != nil {
+		if ws != nil {
+			ws.Close()
+		}
+		return nil, errors.Annotate(err, "cannot unpack archive")
+	}
+	defer ws.Close()
+
+	meta, err := ws.Metadata()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read metadata")
+	}
+	if err := verifyFiles(ws, meta.Origin.Machine); err != nil {
+		return nil, errors.Trace(err)
+	}
+	envs, err := summarizeDump(ws.DBDumpDir, meta)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot restore database dump")
+	}
+	return &Verification{
+		Metadata:     meta,
+		Environments: envs,
+	}, nil
+}
+
+// verifyFiles checks that the archive's bundle of state-related files
+// holds all the files that are required for a restore. These are the
+// files that GetFilesToBackUp always includes in a backup, along with
+// the agent config of the machine the backup was made on.
+func verifyFiles(ws *ArchiveWorkspace, machine string) error {
+	root, err := ioutil.TempDir(ws.RootDir, "files")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := ws.UnpackFilesBundle(root); err != nil {
+		return errors.Annotate(err, "cannot unpack files bundle")
+	}
+
+	paths := &Paths{DataDir: dataDir, LogsDir: logsDir}
+	required, err := GetFilesToBackUp(root, paths, machine)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	required = append(required, filepath.Join(root, dataDir, agentsDir, "machine-"+machine, "agent.conf"))
+
+	var missing []string
+	for _, p := range required {
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			missing = append(missing, strings.TrimPrefix(p, root))
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("archive is missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// summarizeDump is replaced in tests.
+var summarizeDump = summarizeMongoDump
+
+// summarizeMongoDump restores the database dump in the given directory
+// into a temporary mongod, and summarises the environments in it.
+func summarizeMongoDump(dumpDir string, meta *Metadata) ([]EnvironmentSummary, error) {
+	mongod, err := mongo.Path()
+	if err != nil {
+		return nil, errors.Annotate(err, "mongod not available")
+	}
+	mongoRestore, err := restorePath()
+	if err != nil {
+		return nil, errors.Annotate(err, "mongorestore not available")
+	}
+
+	dbDir, err := ioutil.TempDir("", "juju-backup-verify")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer os.RemoveAll(dbDir)
+	port, err := freePort()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+
+	cmd := exec.Command(mongod,
+		"--dbpath", dbDir,
+		"--bind_ip", "127.0.0.1",
+		"--port", fmt.Sprint(port),
+		"--nojournal",
+		"--noprealloc",
+		"--smallfiles",
+		"--nohttpinterface",
+		"--nounixsocket",
+		"--quiet",
+	)
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Annotate(err, "cannot start mongod")
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	// Wait for mongod to accept connections before restoring into it.
+	session, err := mgo.DialWithTimeout(addr, time.Minute)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot connect to mongod")
+	}
+	defer session.Close()
+
+	args := []string{"--host", addr, "--drop"}
+	if ver := meta.Origin.Version; ver.Major > 1 || ver.Major == 1 && ver.Minor >= 22 {
+		args = append(args, "--oplogReplay")
+	}
+	if err := runCommand(mongoRestore, append(args, dumpDir)...); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return summarizeEnvironments(session)
+}
+
+// freePort returns a local TCP port which is not in use.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// summarizeEnvironments summarises the environments in the juju
+// database of the given session, in order of name.
+func summarizeEnvironments(session *mgo.Session) ([]EnvironmentSummary, error) {
+	db := session.DB("juju")
+	var envDocs []struct {
+		UUID string `bson:"_id"`
+		Name string `bson:"name"`
+	}
+	if err := db.C("environments").Find(nil).All(&envDocs); err != nil {
+		return nil, errors.Annotate(err, "cannot read environments")
+	}
+	if len(envDocs) == 0 {
+		return nil, errors.New("no environments found")
+	}
+
+	var envs []EnvironmentSummary
+	for _, doc := range envDocs {
+		sel := bson.D{{"env-uuid", doc.UUID}}
+		machines, err := db.C("machines").Find(sel).Count()
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot count machines")
+		}
+		units, err := db.C("units").Find(sel).Count()
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot count units")
+		}
+		envs = append(envs, EnvironmentSummary{
+			UUID:     doc.UUID,
+			Name:     doc.Name,
+			Machines: machines,
+			Units:    units,
+		})
+	}
+	sort.Sort(environmentsByName(envs))
+	return envs, nil
+}
+
+type environmentsByName []EnvironmentSummary
+
+func (e environmentsByName) Len() int           { return len(e) }
+func (e environmentsByName) Less(i, j int) bool { return e[i].Name < e[j].Name }
+func (e environmentsByName) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
@@ -0,0 +1,115 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	bt "github.com/juju/juju/state/backups/testing"
+)
+
+type verifySuite struct {
+	jujutesting.IsolationSuite
+	meta *backups.Metadata
+	dump []bt.File
+}
+
+var _ = gc.Suite(&verifySuite{})
+
+func (s *verifySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.meta = bt.NewMetadata()
+	s.dump = []bt.File{{
+		Name:  "juju",
+		IsDir: true,
+	}, {
+		Name:    "juju/machines.bson",
+		Content: "<BSON data goes here>",
+	}}
+}
+
+func (s *verifySuite) files(omit string) []bt.File {
+	var files []bt.File
+	for _, name := range []string{
+		"var/lib/juju/tools/1.25.0-trusty-amd64/jujud",
+		"var/lib/juju/system-identity",
+		"var/lib/juju/server.pem",
+		"var/lib/juju/shared-secret",
+		"var/lib/juju/agents/machine-0/agent.conf",
+		"var/log/juju/all-machines.log",
+	} {
+		if name != omit {
+			files = append(files, bt.File{Name: name, Content: "<" + name + ">"})
+		}
+	}
+	return files
+}
+
+func (s *verifySuite) TestVerify(c *gc.C) {
+	envs := []backups.EnvironmentSummary{{
+		UUID:     "49db53ac-a42f-4ab2-86e1-0c6fa0fec762",
+		Name:     "admin",
+		Machines: 3,
+		Units:    5,
+	}}
+	s.PatchValue(backups.SummarizeDump, func(dumpDir string, meta *backups.Metadata) ([]backups.EnvironmentSummary, error) {
+		data, err := ioutil.ReadFile(filepath.Join(dumpDir, "juju", "machines.bson"))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(data), gc.Equals, "<BSON data goes here>")
+		c.Check(meta.Origin.Machine, gc.Equals, "0")
+		return envs, nil
+	})
+	archive, err := bt.NewArchive(s.meta, s.files(""), s.dump)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := backups.Verify(archive, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Metadata.Origin.Hostname, gc.Equals, "main-host")
+	c.Check(result.Environments, jc.DeepEquals, envs)
+}
+
+func (s *verifySuite) TestVerifyMissingFile(c *gc.C) {
+	s.PatchValue(backups.SummarizeDump, func(string, *backups.Metadata) ([]backups.EnvironmentSummary, error) {
+		c.Fatalf("database dump should not be restored")
+		return nil, nil
+	})
+	archive, err := bt.NewArchive(s.meta, s.files("var/lib/juju/server.pem"), s.dump)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = backups.Verify(archive, nil)
+	c.Check(err, gc.ErrorMatches, "archive is missing /var/lib/juju/server.pem")
+}
+
+func (s *verifySuite) TestVerifyMissingTools(c *gc.C) {
+	s.PatchValue(backups.SummarizeDump, func(string, *backups.Metadata) ([]backups.EnvironmentSummary, error) {
+		return nil, nil
+	})
+	archive, err := bt.NewArchive(s.meta, s.files("var/lib/juju/tools/1.25.0-trusty-amd64/jujud"), s.dump)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = backups.Verify(archive, nil)
+	c.Check(err, gc.ErrorMatches, "archive is missing /var/lib/juju/tools")
+}
+
+func (s *storageSuite) TestSummarizeEnvironments(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+
+	envs, err := backups.SummarizeEnvironments(s.State.MongoSession())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(envs, jc.DeepEquals, []backups.EnvironmentSummary{{
+		UUID:     env.UUID(),
+		Name:     env.Name(),
+		Machines: 1,
+	}})
+}
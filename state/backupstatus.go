@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// backupStatusGlobalKey is the key for the status of the environment's
+// automatic backups.
+const backupStatusGlobalKey = environGlobalKey + "#backups"
+
+// BackupStatus returns the status of the environment's automatic
+// backups, as last recorded by SetBackupStatus. If no status has been
+// recorded, an error satisfying errors.IsNotFound is returned.
+func (st *State) BackupStatus() (StatusInfo, error) {
+	return getStatus(st, backupStatusGlobalKey, "backup status")
+}
+
+// SetBackupStatus records the status of the environment's automatic
+// backups: StatusError if the last attempt to make or prune backups
+// failed, or StatusIdle otherwise.
+func (st *State) SetBackupStatus(status Status, message string, data map[string]interface{}) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set backup status")
+	doc := statusDoc{
+		EnvUUID:    st.EnvironUUID(),
+		Status:     status,
+		StatusInfo: message,
+		StatusData: escapeKeys(data),
+		Updated:    time.Now().UnixNano(),
+	}
+	update := updateStatusSource(st, backupStatusGlobalKey, doc)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		_, err := st.readTxnRevno(statusesC, backupStatusGlobalKey)
+		if errors.Cause(err) == mgo.ErrNotFound {
+			return []txn.Op{createStatusOp(st, backupStatusGlobalKey, doc)}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return update(attempt)
+	}
+	return st.run(buildTxn)
+}
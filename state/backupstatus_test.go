@@ -0,0 +1,41 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type BackupStatusSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&BackupStatusSuite{})
+
+func (s *BackupStatusSuite) TestBackupStatusNotSet(c *gc.C) {
+	_, err := s.State.BackupStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *BackupStatusSuite) TestSetBackupStatus(c *gc.C) {
+	err := s.State.SetBackupStatus(state.StatusError, "backup failed", map[string]interface{}{"id": "x"})
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := s.State.BackupStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Status, gc.Equals, state.StatusError)
+	c.Assert(info.Message, gc.Equals, "backup failed")
+	c.Assert(info.Data, jc.DeepEquals, map[string]interface{}{"id": "x"})
+	c.Assert(info.Since, gc.NotNil)
+
+	err = s.State.SetBackupStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	info, err = s.State.BackupStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Status, gc.Equals, state.StatusIdle)
+	c.Assert(info.Message, gc.Equals, "")
+}
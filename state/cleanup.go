@@ -28,6 +28,7 @@ const (
 	cleanupAttachmentsForDyingStorage    cleanupKind = "storageAttachments"
 	cleanupAttachmentsForDyingVolume     cleanupKind = "volumeAttachments"
 	cleanupAttachmentsForDyingFilesystem cleanupKind = "filesystemAttachments"
+	cleanupRemovedResourceData           cleanupKind = "resourceData"
 )
 
 // cleanupDoc represents a potentially large set of documents that should be
@@ -99,6 +100,8 @@ func (st *State) Cleanup() (err error) {
 			err = st.cleanupAttachmentsForDyingVolume(doc.Prefix)
 		case cleanupAttachmentsForDyingFilesystem:
 			err = st.cleanupAttachmentsForDyingFilesystem(doc.Prefix)
+		case cleanupRemovedResourceData:
+			err = st.cleanupRemovedResourceData(doc.Prefix)
 		default:
 			err = fmt.Errorf("unknown cleanup kind %q", doc.Kind)
 		}
@@ -235,6 +238,9 @@ func (st *State) cleanupDyingUnit(name string) error {
 // cleanupRemovedUnit takes care of all the final cleanup required when
 // a unit is removed.
 func (st *State) cleanupRemovedUnit(unitId string) error {
+	if err := st.removeUnitPayloads(unitId); err != nil {
+		return err
+	}
 	actions, err := st.matchingActionsByReceiverId(unitId)
 	if err != nil {
 		return err
@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -101,17 +102,8 @@ func (schema collectionSchema) Load(db *mgo.Database, environUUID string) (Datab
 		return nil, errors.New("invalid environment UUID")
 	}
 	for name, info := range schema {
-		rawCollection := db.C(name)
-		if spec := info.explicitCreate; spec != nil {
-			if err := createCollection(rawCollection, spec); err != nil {
-				message := fmt.Sprintf("cannot create collection %q", name)
-				return nil, maybeUnauthorized(err, message)
-			}
-		}
-		for _, index := range info.indexes {
-			if err := rawCollection.EnsureIndex(index); err != nil {
-				return nil, maybeUnauthorized(err, "cannot create index")
-			}
+		if err := ensureCollection(db.C(name), info); err != nil {
+			return nil, err
 		}
 	}
 	return &database{
@@ -121,6 +113,68 @@ func (schema collectionSchema) Load(db *mgo.Database, environUUID string) (Datab
 	}, nil
 }
 
+// ensureCollection creates the collection, if it must be created
+// explicitly, and its indexes, as specified.
+func ensureCollection(raw *mgo.Collection, info collectionInfo) error {
+	if spec := info.explicitCreate; spec != nil {
+		if err := createCollection(raw, spec); err != nil {
+			message := fmt.Sprintf("cannot create collection %q", raw.Name)
+			return maybeUnauthorized(err, message)
+		}
+	}
+	for _, index := range info.indexes {
+		if err := raw.EnsureIndex(index); err != nil {
+			return maybeUnauthorized(err, "cannot create index")
+		}
+	}
+	return nil
+}
+
+// ResetCollections drops the named collections, each of which must be
+// the logs collection ("logs.logs") or one of the collections of the
+// juju database ("juju.machines"), and creates them again, empty, with
+// their indexes. It is used when restoring a backup from which their
+// contents were left out.
+func ResetCollections(session *mgo.Session, names []string) error {
+	schema := allCollections()
+	for _, name := range names {
+		parts := strings.SplitN(name, ".", 2)
+		if len(parts) != 2 {
+			return errors.NotValidf("collection name %q", name)
+		}
+		raw := session.DB(parts[0]).C(parts[1])
+		var ensure func() error
+		switch {
+		case parts[0] == logsDB && parts[1] == logsC:
+			ensure = func() error { return InitDbLogs(session) }
+		case parts[0] == jujuDB:
+			info, ok := schema[parts[1]]
+			if !ok {
+				return errors.NotFoundf("collection %q", name)
+			}
+			ensure = func() error { return ensureCollection(raw, info) }
+		default:
+			return errors.NotValidf("collection name %q", name)
+		}
+		if err := raw.DropCollection(); err != nil && !isNamespaceNotFound(err) {
+			return errors.Annotatef(err, "cannot drop collection %q", name)
+		}
+		if err := ensure(); err != nil {
+			return errors.Annotatef(err, "cannot recreate collection %q", name)
+		}
+	}
+	return nil
+}
+
+// isNamespaceNotFound returns whether the error is mongo's report that
+// a collection to be dropped does not exist.
+func isNamespaceNotFound(err error) bool {
+	if err, ok := errors.Cause(err).(*mgo.QueryError); ok {
+		return err.Code == 26
+	}
+	return false
+}
+
 // createCollection swallows collection-already-exists errors.
 func createCollection(raw *mgo.Collection, spec *mgo.CollectionInfo) error {
 	err := raw.Create(spec)
@@ -0,0 +1,466 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package description defines a portable, versioned description of an
+// environment, from which the environment can be recreated in another
+// state server. It knows nothing of how environments are stored; the
+// state package fills it in and reads it.
+package description
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v2"
+)
+
+// Version is the version of the description format written by
+// Serialize. It is incremented whenever the format changes in a way
+// that older readers cannot follow.
+const Version = 1
+
+// Environment describes an environment and everything in it.
+type Environment struct {
+	// Version is the version of the description format.
+	Version int `yaml:"version"`
+
+	// Owner is the canonical name of the user who owns the
+	// environment.
+	Owner string `yaml:"owner"`
+
+	// Config holds the environment's configuration, including its
+	// name and UUID.
+	Config map[string]interface{} `yaml:"config"`
+
+	Constraints string            `yaml:"constraints,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+
+	// Sequences holds the last numbers handed out for the names of
+	// new machines, units, relations and the like, so that the names
+	// of entities added after the import do not clash with those of
+	// entities that have been removed.
+	Sequences map[string]int `yaml:"sequences,omitempty"`
+
+	Users       []User            `yaml:"users"`
+	Machines    []Machine         `yaml:"machines,omitempty"`
+	Charms      []Charm           `yaml:"charms,omitempty"`
+	Services    []Service         `yaml:"services,omitempty"`
+	Relations   []Relation        `yaml:"relations,omitempty"`
+	Storage     []StorageInstance `yaml:"storage,omitempty"`
+	Volumes     []Volume          `yaml:"volumes,omitempty"`
+	Filesystems []Filesystem      `yaml:"filesystems,omitempty"`
+}
+
+// Name returns the name of the environment.
+func (e *Environment) Name() string {
+	name, _ := e.Config["name"].(string)
+	return name
+}
+
+// UUID returns the UUID of the environment.
+func (e *Environment) UUID() string {
+	uuid, _ := e.Config["uuid"].(string)
+	return uuid
+}
+
+// User describes a user's access to the environment.
+type User struct {
+	Name        string    `yaml:"name"`
+	DisplayName string    `yaml:"display-name,omitempty"`
+	CreatedBy   string    `yaml:"created-by"`
+	DateCreated time.Time `yaml:"date-created"`
+	Access      string    `yaml:"access"`
+}
+
+// Status describes the status of an entity.
+type Status struct {
+	Value   string                 `yaml:"value"`
+	Message string                 `yaml:"message,omitempty"`
+	Data    map[string]interface{} `yaml:"data,omitempty"`
+	Updated time.Time              `yaml:"updated"`
+}
+
+// Tools describes the agent tools an entity is running.
+type Tools struct {
+	Version string `yaml:"version"`
+	URL     string `yaml:"url,omitempty"`
+	SHA256  string `yaml:"sha256,omitempty"`
+	Size    int64  `yaml:"size,omitempty"`
+}
+
+// Address describes a network address of a machine.
+type Address struct {
+	Value       string `yaml:"value"`
+	Type        string `yaml:"type"`
+	NetworkName string `yaml:"network-name,omitempty"`
+	Scope       string `yaml:"scope,omitempty"`
+}
+
+// Machine describes a machine or container. A container's ID holds
+// that of the machine hosting it.
+type Machine struct {
+	Id            string   `yaml:"id"`
+	Nonce         string   `yaml:"nonce"`
+	Series        string   `yaml:"series"`
+	ContainerType string   `yaml:"container-type,omitempty"`
+	Jobs          []string `yaml:"jobs"`
+	PasswordHash  string   `yaml:"password-hash"`
+	Placement     string   `yaml:"placement,omitempty"`
+
+	// Instance describes the provider instance of a machine that has
+	// been provisioned.
+	Instance *Instance `yaml:"instance,omitempty"`
+	Tools    *Tools    `yaml:"tools,omitempty"`
+
+	ProviderAddresses       []Address `yaml:"provider-addresses,omitempty"`
+	MachineAddresses        []Address `yaml:"machine-addresses,omitempty"`
+	PreferredPublicAddress  *Address  `yaml:"preferred-public-address,omitempty"`
+	PreferredPrivateAddress *Address  `yaml:"preferred-private-address,omitempty"`
+
+	// SupportedContainers holds the types of container the machine
+	// can host, or nil if they are not yet known.
+	SupportedContainers *[]string `yaml:"supported-containers,omitempty"`
+
+	Constraints string            `yaml:"constraints,omitempty"`
+	Status      Status            `yaml:"status"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	OpenedPorts []OpenedPorts     `yaml:"opened-ports,omitempty"`
+}
+
+// Instance describes the provider instance of a machine.
+type Instance struct {
+	Id               string    `yaml:"id"`
+	Status           string    `yaml:"status,omitempty"`
+	Arch             *string   `yaml:"arch,omitempty"`
+	Mem              *uint64   `yaml:"mem,omitempty"`
+	RootDisk         *uint64   `yaml:"root-disk,omitempty"`
+	CpuCores         *uint64   `yaml:"cpu-cores,omitempty"`
+	CpuPower         *uint64   `yaml:"cpu-power,omitempty"`
+	Tags             *[]string `yaml:"tags,omitempty"`
+	AvailabilityZone *string   `yaml:"availability-zone,omitempty"`
+}
+
+// OpenedPorts describes the ports opened on a machine's network.
+type OpenedPorts struct {
+	Network string      `yaml:"network,omitempty"`
+	Ports   []PortRange `yaml:"ports"`
+}
+
+// PortRange describes a range of ports opened by a unit.
+type PortRange struct {
+	Unit     string `yaml:"unit"`
+	FromPort int    `yaml:"from-port"`
+	ToPort   int    `yaml:"to-port"`
+	Protocol string `yaml:"protocol"`
+}
+
+// Charm describes a charm used by the environment, with its archive.
+type Charm struct {
+	URL    string `yaml:"url"`
+	SHA256 string `yaml:"sha256"`
+
+	// Archive holds the base64-encoded charm archive.
+	Archive string `yaml:"archive"`
+}
+
+// Service describes a service and its units.
+type Service struct {
+	Name        string `yaml:"name"`
+	Series      string `yaml:"series"`
+	Subordinate bool   `yaml:"subordinate,omitempty"`
+	CharmURL    string `yaml:"charm-url"`
+	ForceCharm  bool   `yaml:"force-charm,omitempty"`
+	Exposed     bool   `yaml:"exposed,omitempty"`
+	MinUnits    int    `yaml:"min-units,omitempty"`
+	Owner       string `yaml:"owner"`
+
+	Settings           map[string]interface{}        `yaml:"settings,omitempty"`
+	LeadershipSettings map[string]interface{}        `yaml:"leadership-settings,omitempty"`
+	Constraints        string                        `yaml:"constraints,omitempty"`
+	StorageConstraints map[string]StorageConstraints `yaml:"storage-constraints,omitempty"`
+	ActionLimits       map[string]int                `yaml:"action-limits,omitempty"`
+
+	// MetricCredentials holds the base64-encoded credentials with
+	// which the service's metrics are sent.
+	MetricCredentials string `yaml:"metric-credentials,omitempty"`
+
+	// Status holds the status set for the service by its leader, or
+	// nil if none has been set and the status is derived from those
+	// of the units.
+	Status      *Status           `yaml:"status,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	Units       []Unit            `yaml:"units,omitempty"`
+}
+
+// StorageConstraints describes the storage given to a service's units.
+type StorageConstraints struct {
+	Pool  string `yaml:"pool"`
+	Size  uint64 `yaml:"size"`
+	Count uint64 `yaml:"count"`
+}
+
+// Unit describes a unit of a service.
+type Unit struct {
+	Name         string   `yaml:"name"`
+	Machine      string   `yaml:"machine,omitempty"`
+	Principal    string   `yaml:"principal,omitempty"`
+	Subordinates []string `yaml:"subordinates,omitempty"`
+	PasswordHash string   `yaml:"password-hash"`
+	CharmURL     string   `yaml:"charm-url,omitempty"`
+	Tools        *Tools   `yaml:"tools,omitempty"`
+
+	// WorkloadVersion holds the workload version reported by the
+	// unit's charm.
+	WorkloadVersion string `yaml:"workload-version,omitempty"`
+
+	// Constraints holds the constraints of a principal unit.
+	Constraints string `yaml:"constraints,omitempty"`
+
+	WorkloadStatus Status            `yaml:"workload-status"`
+	AgentStatus    Status            `yaml:"agent-status"`
+	MeterStatus    MeterStatus       `yaml:"meter-status"`
+	Annotations    map[string]string `yaml:"annotations,omitempty"`
+}
+
+// MeterStatus describes the meter status of a unit.
+type MeterStatus struct {
+	Code string `yaml:"code"`
+	Info string `yaml:"info,omitempty"`
+}
+
+// Relation describes a relation between services.
+type Relation struct {
+	Id        int        `yaml:"id"`
+	Key       string     `yaml:"key"`
+	Endpoints []Endpoint `yaml:"endpoints"`
+}
+
+// Endpoint describes one end of a relation.
+type Endpoint struct {
+	Service   string `yaml:"service"`
+	Name      string `yaml:"name"`
+	Interface string `yaml:"interface"`
+	Role      string `yaml:"role"`
+	Scope     string `yaml:"scope"`
+	Optional  bool   `yaml:"optional,omitempty"`
+	Limit     int    `yaml:"limit,omitempty"`
+
+	// UnitSettings holds the relation settings of each of the
+	// service's units in the relation's scope, by unit name.
+	UnitSettings map[string]map[string]interface{} `yaml:"unit-settings,omitempty"`
+}
+
+// StorageInstance describes a charm storage instance.
+type StorageInstance struct {
+	Id          string `yaml:"id"`
+	Kind        string `yaml:"kind"`
+	Owner       string `yaml:"owner"`
+	StorageName string `yaml:"storage-name"`
+	CharmURL    string `yaml:"charm-url,omitempty"`
+
+	// Attachments holds the names of the units the storage instance
+	// is attached to.
+	Attachments []string `yaml:"attachments,omitempty"`
+}
+
+// Volume describes a volume. A volume that has not yet been
+// provisioned has a Pool and Size to provision it with; one that has
+// been provisioned has Info.
+type Volume struct {
+	Id          string             `yaml:"id"`
+	Storage     string             `yaml:"storage,omitempty"`
+	Binding     string             `yaml:"binding,omitempty"`
+	Pool        string             `yaml:"pool,omitempty"`
+	Size        uint64             `yaml:"size,omitempty"`
+	Info        *VolumeInfo        `yaml:"info,omitempty"`
+	Status      *Status            `yaml:"status,omitempty"`
+	Attachments []VolumeAttachment `yaml:"attachments,omitempty"`
+}
+
+// VolumeInfo describes a provisioned volume.
+type VolumeInfo struct {
+	VolumeId   string `yaml:"volume-id"`
+	HardwareId string `yaml:"hardware-id,omitempty"`
+	Size       uint64 `yaml:"size"`
+	Pool       string `yaml:"pool"`
+	Persistent bool   `yaml:"persistent,omitempty"`
+}
+
+// VolumeAttachment describes the attachment of a volume to a machine.
+// An attachment that has been made has Info.
+type VolumeAttachment struct {
+	Machine  string                `yaml:"machine"`
+	ReadOnly bool                  `yaml:"read-only,omitempty"`
+	Info     *VolumeAttachmentInfo `yaml:"info,omitempty"`
+}
+
+// VolumeAttachmentInfo describes a volume attachment that has been
+// made.
+type VolumeAttachmentInfo struct {
+	DeviceName string `yaml:"device-name,omitempty"`
+	DeviceLink string `yaml:"device-link,omitempty"`
+	BusAddress string `yaml:"bus-address,omitempty"`
+	ReadOnly   bool   `yaml:"read-only,omitempty"`
+}
+
+// Filesystem describes a filesystem. A filesystem that has not yet
+// been provisioned has a Pool and Size to provision it with; one that
+// has been provisioned has Info.
+type Filesystem struct {
+	Id          string                 `yaml:"id"`
+	Storage     string                 `yaml:"storage,omitempty"`
+	Volume      string                 `yaml:"volume,omitempty"`
+	Binding     string                 `yaml:"binding,omitempty"`
+	Pool        string                 `yaml:"pool,omitempty"`
+	Size        uint64                 `yaml:"size,omitempty"`
+	Info        *FilesystemInfo        `yaml:"info,omitempty"`
+	Status      *Status                `yaml:"status,omitempty"`
+	Attachments []FilesystemAttachment `yaml:"attachments,omitempty"`
+}
+
+// FilesystemInfo describes a provisioned filesystem.
+type FilesystemInfo struct {
+	FilesystemId string `yaml:"filesystem-id,omitempty"`
+	Size         uint64 `yaml:"size"`
+	Pool         string `yaml:"pool"`
+}
+
+// FilesystemAttachment describes the attachment of a filesystem to a
+// machine. An attachment that has been made has Info.
+type FilesystemAttachment struct {
+	Machine  string                    `yaml:"machine"`
+	Location string                    `yaml:"location,omitempty"`
+	ReadOnly bool                      `yaml:"read-only,omitempty"`
+	Info     *FilesystemAttachmentInfo `yaml:"info,omitempty"`
+}
+
+// FilesystemAttachmentInfo describes a filesystem attachment that has
+// been made.
+type FilesystemAttachmentInfo struct {
+	MountPoint string `yaml:"mount-point,omitempty"`
+	ReadOnly   bool   `yaml:"read-only,omitempty"`
+}
+
+// Serialize returns the YAML form of the given environment
+// description, which is stamped with the current Version.
+func Serialize(env *Environment) ([]byte, error) {
+	env.Version = Version
+	data, err := goyaml.Marshal(env)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
+
+// Deserialize reads an environment description in YAML or JSON form,
+// as written by Serialize.
+func Deserialize(data []byte) (*Environment, error) {
+	var header struct {
+		Version int `yaml:"version"`
+	}
+	if err := goyaml.Unmarshal(data, &header); err != nil {
+		return nil, errors.Annotate(err, "cannot read environment description")
+	}
+	if header.Version != Version {
+		return nil, errors.NotSupportedf("environment description version %d", header.Version)
+	}
+	var env Environment
+	if err := goyaml.Unmarshal(data, &env); err != nil {
+		return nil, errors.Annotate(err, "cannot read environment description")
+	}
+	if err := env.conform(); err != nil {
+		return nil, errors.Annotate(err, "cannot read environment description")
+	}
+	return &env, nil
+}
+
+// conform converts the maps read from YAML, whose keys may be of any
+// type, to the string-keyed maps the rest of juju expects.
+func (e *Environment) conform() (err error) {
+	conform := func(m *map[string]interface{}) {
+		if err == nil && *m != nil {
+			*m, err = conformMap(*m)
+		}
+	}
+	conformStatus := func(status *Status) {
+		if status != nil {
+			conform(&status.Data)
+		}
+	}
+	conform(&e.Config)
+	for i := range e.Machines {
+		conformStatus(&e.Machines[i].Status)
+	}
+	for i := range e.Services {
+		service := &e.Services[i]
+		conform(&service.Settings)
+		conform(&service.LeadershipSettings)
+		conformStatus(service.Status)
+		for j := range service.Units {
+			conformStatus(&service.Units[j].WorkloadStatus)
+			conformStatus(&service.Units[j].AgentStatus)
+		}
+	}
+	for i := range e.Relations {
+		for _, ep := range e.Relations[i].Endpoints {
+			for unit, settings := range ep.UnitSettings {
+				conform(&settings)
+				ep.UnitSettings[unit] = settings
+			}
+		}
+	}
+	for i := range e.Volumes {
+		conformStatus(e.Volumes[i].Status)
+	}
+	for i := range e.Filesystems {
+		conformStatus(e.Filesystems[i].Status)
+	}
+	return err
+}
+
+// conformMap returns a copy of the given map in which all nested maps
+// have string keys.
+func conformMap(in map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+	for key, value := range in {
+		value, err := conformValue(value)
+		if err != nil {
+			return nil, errors.Annotatef(err, "%s", key)
+		}
+		out[key] = value
+	}
+	return out, nil
+}
+
+func conformValue(in interface{}) (interface{}, error) {
+	switch in := in.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{})
+		for key, value := range in {
+			name, ok := key.(string)
+			if !ok {
+				return nil, errors.Errorf("unexpected key %v", key)
+			}
+			value, err := conformValue(value)
+			if err != nil {
+				return nil, errors.Annotatef(err, "%s", name)
+			}
+			out[name] = value
+		}
+		return out, nil
+	case map[string]interface{}:
+		return conformMap(in)
+	case []interface{}:
+		out := make([]interface{}, len(in))
+		for i, value := range in {
+			value, err := conformValue(value)
+			if err != nil {
+				return nil, errors.Annotate(err, fmt.Sprint(i))
+			}
+			out[i] = value
+		}
+		return out, nil
+	}
+	return in, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package description_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/description"
+)
+
+type DescriptionSuite struct{}
+
+var _ = gc.Suite(&DescriptionSuite{})
+
+func (*DescriptionSuite) TestRoundTrip(c *gc.C) {
+	updated := time.Date(2015, 9, 1, 12, 0, 0, 0, time.UTC)
+	env := &description.Environment{
+		Owner: "admin@local",
+		Config: map[string]interface{}{
+			"name": "prod",
+			"uuid": "e2d5a41c-5c4c-4b5f-8ae8-4f5a8d7c9a10",
+		},
+		Sequences: map[string]int{"machine": 2},
+		Machines: []description.Machine{{
+			Id:       "0",
+			Nonce:    "machine-0:nonce",
+			Series:   "trusty",
+			Jobs:     []string{"JobHostUnits"},
+			Instance: &description.Instance{Id: "i-123"},
+			Status:   description.Status{Value: "started", Updated: updated},
+		}},
+		Services: []description.Service{{
+			Name:     "wordpress",
+			CharmURL: "cs:trusty/wordpress-3",
+			Settings: map[string]interface{}{
+				"blog-title": "Migrated",
+				"nested":     map[string]interface{}{"key": "value"},
+			},
+			Units: []description.Unit{{
+				Name:    "wordpress/0",
+				Machine: "0",
+				WorkloadStatus: description.Status{
+					Value:   "active",
+					Data:    map[string]interface{}{"list": []interface{}{"a"}},
+					Updated: updated,
+				},
+				AgentStatus: description.Status{Value: "idle", Updated: updated},
+			}},
+		}},
+	}
+
+	data, err := description.Serialize(env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Version, gc.Equals, description.Version)
+
+	read, err := description.Deserialize(data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read, jc.DeepEquals, env)
+	c.Assert(read.Name(), gc.Equals, "prod")
+	c.Assert(read.UUID(), gc.Equals, "e2d5a41c-5c4c-4b5f-8ae8-4f5a8d7c9a10")
+}
+
+func (*DescriptionSuite) TestDeserializeJSON(c *gc.C) {
+	read, err := description.Deserialize([]byte(`{
+		"version": 1,
+		"owner": "admin@local",
+		"config": {"name": "prod", "nested": {"key": "value"}}
+	}`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read.Owner, gc.Equals, "admin@local")
+	c.Assert(read.Config, jc.DeepEquals, map[string]interface{}{
+		"name":   "prod",
+		"nested": map[string]interface{}{"key": "value"},
+	})
+}
+
+func (*DescriptionSuite) TestDeserializeUnknownVersion(c *gc.C) {
+	_, err := description.Deserialize([]byte("version: 2\n"))
+	c.Assert(err, gc.ErrorMatches, "environment description version 2 not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -0,0 +1,200 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"net"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// EndpointBindings returns the names of the spaces the service's relation
+// endpoints are bound to, by endpoint name. Unbound endpoints are omitted.
+func (s *Service) EndpointBindings() map[string]string {
+	bindings := make(map[string]string, len(s.doc.EndpointBindings))
+	for endpoint, space := range s.doc.EndpointBindings {
+		bindings[endpoint] = space
+	}
+	return bindings
+}
+
+// EndpointBinding returns the name of the space the named relation
+// endpoint is bound to, or "" if it is not bound.
+func (s *Service) EndpointBinding(endpoint string) string {
+	return s.doc.EndpointBindings[endpoint]
+}
+
+// SetEndpointBindings binds the service's relation endpoints to the
+// given spaces. Each key must name one of the service's endpoints, and
+// each value an existing space; a value of "" removes the endpoint's
+// binding. Endpoints not mentioned keep their current bindings.
+func (s *Service) SetEndpointBindings(bindings map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set endpoint bindings for service %q", s)
+	for endpoint, space := range bindings {
+		if space != "" && !names.IsValidSpace(space) {
+			return errors.NotValidf("space name %q for endpoint %q", space, endpoint)
+		}
+	}
+	service := &Service{st: s.st, doc: s.doc}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := service.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if service.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		endpoints, err := service.Endpoints()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		known := make(map[string]bool, len(endpoints))
+		for _, ep := range endpoints {
+			known[ep.Name] = true
+		}
+		var set, unset bson.D
+		var ops []txn.Op
+		for _, endpoint := range sortedKeys(bindings) {
+			space := bindings[endpoint]
+			if !known[endpoint] {
+				return nil, errors.NotFoundf("endpoint %q", endpoint)
+			}
+			if space == service.doc.EndpointBindings[endpoint] {
+				continue
+			}
+			key := "endpointbindings." + endpoint
+			if space == "" {
+				unset = append(unset, bson.DocElem{key, nil})
+				continue
+			}
+			if _, err := s.st.Space(space); err != nil {
+				return nil, errors.Trace(err)
+			}
+			set = append(set, bson.DocElem{key, space})
+			ops = append(ops, txn.Op{
+				C:      spacesC,
+				Id:     s.st.docID(space),
+				Assert: isAliveDoc,
+			})
+		}
+		var update bson.D
+		if len(set) > 0 {
+			update = append(update, bson.DocElem{"$set", set})
+		}
+		if len(unset) > 0 {
+			update = append(update, bson.DocElem{"$unset", unset})
+		}
+		if len(update) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return append(ops, txn.Op{
+			C:      servicesC,
+			Id:     service.doc.DocID,
+			Assert: append(bson.D{{"charmurl", service.doc.CharmURL}}, isAliveDoc...),
+			Update: update,
+		}), nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return err
+	}
+	return s.Refresh()
+}
+
+// staleEndpointBindings returns the update clearing the service's
+// bindings for endpoints that the given charm does not define, or nil if
+// there are none.
+func (s *Service) staleEndpointBindings(meta *charm.Meta) bson.D {
+	var unset bson.D
+	for _, endpoint := range sortedKeys(s.doc.EndpointBindings) {
+		if endpoint == "juju-info" {
+			continue
+		}
+		_, provides := meta.Provides[endpoint]
+		_, requires := meta.Requires[endpoint]
+		_, peers := meta.Peers[endpoint]
+		if !provides && !requires && !peers {
+			unset = append(unset, bson.DocElem{"endpointbindings." + endpoint, nil})
+		}
+	}
+	if len(unset) == 0 {
+		return nil
+	}
+	return bson.D{{"$unset", unset}}
+}
+
+// sortedKeys returns the keys of the map in order, so that the
+// transaction operations built from it are deterministic.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// subnetAddress is an address of a machine, along with the CIDR of the
+// subnet containing it.
+type subnetAddress struct {
+	network.Address
+	CIDR string
+}
+
+// spaceAddresses returns the addresses of the machine that lie within
+// the subnets of the named space.
+func (m *Machine) spaceAddresses(spaceName string) ([]subnetAddress, error) {
+	space, err := m.st.Space(spaceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnets, err := space.Subnets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var ipNets []*net.IPNet
+	for _, subnet := range subnets {
+		_, ipNet, err := net.ParseCIDR(subnet.CIDR())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+	var addrs []subnetAddress
+	for _, addr := range m.Addresses() {
+		ip := net.ParseIP(addr.Value)
+		if ip == nil {
+			continue
+		}
+		for _, ipNet := range ipNets {
+			if ipNet.Contains(ip) {
+				addrs = append(addrs, subnetAddress{addr, ipNet.String()})
+				break
+			}
+		}
+	}
+	return addrs, nil
+}
+
+// spaceAddress returns an address of the machine that lies within one of
+// the subnets of the named space. It returns an error satisfying
+// errors.IsNotFound if the machine has no such address.
+func (m *Machine) spaceAddress(spaceName string) (network.Address, error) {
+	addrs, err := m.spaceAddresses(spaceName)
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	if len(addrs) == 0 {
+		return network.Address{}, errors.NotFoundf("address of machine %s in space %q", m, spaceName)
+	}
+	return addrs[0].Address, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type EndpointBindingsSuite struct {
+	ConnSuite
+	wordpress *state.Service
+}
+
+var _ = gc.Suite(&EndpointBindingsSuite{})
+
+func (s *EndpointBindingsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.addSpace(c, "internal", "10.0.0.0/24")
+	s.addSpace(c, "public", "192.168.1.0/24")
+}
+
+func (s *EndpointBindingsSuite) addSpace(c *gc.C, name, cidr string) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: cidr})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace(name, []string{cidr}, false)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *EndpointBindingsSuite) TestSetEndpointBindings(c *gc.C) {
+	c.Assert(s.wordpress.EndpointBindings(), gc.HasLen, 0)
+
+	err := s.wordpress.SetEndpointBindings(map[string]string{
+		"db":  "internal",
+		"url": "public",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	expected := map[string]string{"db": "internal", "url": "public"}
+	c.Assert(s.wordpress.EndpointBindings(), jc.DeepEquals, expected)
+	c.Assert(s.wordpress.EndpointBinding("db"), gc.Equals, "internal")
+	c.Assert(s.wordpress.EndpointBinding("cache"), gc.Equals, "")
+
+	service, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, expected)
+
+	// Unmentioned endpoints are unchanged, and empty spaces unbind.
+	err = s.wordpress.SetEndpointBindings(map[string]string{
+		"db":    "",
+		"cache": "internal",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.wordpress.EndpointBindings(), jc.DeepEquals, map[string]string{
+		"cache": "internal",
+		"url":   "public",
+	})
+}
+
+func (s *EndpointBindingsSuite) TestSetEndpointBindingsErrors(c *gc.C) {
+	err := s.wordpress.SetEndpointBindings(map[string]string{"foo": "internal"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "wordpress": endpoint "foo" not found`)
+
+	err = s.wordpress.SetEndpointBindings(map[string]string{"db": "missing"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "wordpress": space "missing" not found`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+
+	err = s.wordpress.SetEndpointBindings(map[string]string{"db": "Not Valid"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "wordpress": space name "Not Valid" for endpoint "db" not valid`)
+
+	c.Assert(s.wordpress.EndpointBindings(), gc.HasLen, 0)
+}
+
+func (s *EndpointBindingsSuite) TestSetCharmDropsStaleBindings(c *gc.C) {
+	err := s.wordpress.SetEndpointBindings(map[string]string{
+		"db":    "internal",
+		"cache": "internal",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddMetaCharm(c, "wordpress", `
+name: wordpress
+summary: "Blog engine"
+description: "A pretty popular blog engine"
+provides:
+  url:
+    interface: http
+requires:
+  db:
+    interface: mysql
+`, 2)
+	err = s.wordpress.SetCharm(ch, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.wordpress.EndpointBindings(), jc.DeepEquals, map[string]string{"db": "internal"})
+}
+
+func (s *EndpointBindingsSuite) TestRelationUnitPrivateAddress(c *gc.C) {
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProviderAddresses(
+		network.NewAddress("192.168.1.5"),
+		network.NewAddress("10.0.0.5"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err := ru.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "192.168.1.5")
+
+	err = s.wordpress.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err = rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err = ru.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "10.0.0.5")
+}
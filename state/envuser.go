@@ -25,12 +25,36 @@ type EnvironmentUser struct {
 }
 
 type envUserDoc struct {
-	ID          string    `bson:"_id"`
-	EnvUUID     string    `bson:"env-uuid"`
-	UserName    string    `bson:"user"`
-	DisplayName string    `bson:"displayname"`
-	CreatedBy   string    `bson:"createdby"`
-	DateCreated time.Time `bson:"datecreated"`
+	ID          string            `bson:"_id"`
+	EnvUUID     string            `bson:"env-uuid"`
+	UserName    string            `bson:"user"`
+	DisplayName string            `bson:"displayname"`
+	CreatedBy   string            `bson:"createdby"`
+	DateCreated time.Time         `bson:"datecreated"`
+	Access      EnvironmentAccess `bson:"access"`
+}
+
+// EnvironmentAccess defines the level of access an environment user
+// has to an environment.
+type EnvironmentAccess string
+
+const (
+	// EnvironmentReadAccess allows a user to observe an environment,
+	// but not to change it.
+	EnvironmentReadAccess EnvironmentAccess = "read"
+
+	// EnvironmentWriteAccess allows a user to make any change to an
+	// environment.
+	EnvironmentWriteAccess EnvironmentAccess = "write"
+)
+
+// Validate returns an error if the access level is not recognised.
+func (a EnvironmentAccess) Validate() error {
+	switch a {
+	case EnvironmentReadAccess, EnvironmentWriteAccess:
+		return nil
+	}
+	return errors.NotValidf("environment access %q", string(a))
 }
 
 // envUserLastConnectionDoc is updated by the apiserver whenever the user
@@ -80,6 +104,41 @@ func (e *EnvironmentUser) DateCreated() time.Time {
 	return e.doc.DateCreated.UTC()
 }
 
+// Access returns the level of access the environment user has to the
+// environment.
+func (e *EnvironmentUser) Access() EnvironmentAccess {
+	return e.doc.Access
+}
+
+// ReadOnly returns whether the environment user is only allowed to
+// observe the environment.
+func (e *EnvironmentUser) ReadOnly() bool {
+	return e.doc.Access == EnvironmentReadAccess
+}
+
+// SetAccess changes the level of access the environment user has to
+// the environment.
+func (e *EnvironmentUser) SetAccess(access EnvironmentAccess) error {
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      envUsersC,
+		Id:     envUserID(e.UserTag()),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"access", access}}}},
+	}}
+	err := e.st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("environment user %q", e.UserName())
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot set access for environment user %q", e.UserName())
+	}
+	e.doc.Access = access
+	return nil
+}
+
 // LastConnection returns when this EnvironmentUser last connected through the API
 // in UTC. The resulting time will be nil if the user has never logged in.
 func (e *EnvironmentUser) LastConnection() (time.Time, error) {
@@ -154,8 +213,13 @@ func (st *State) EnvironmentUser(user names.UserTag) (*EnvironmentUser, error) {
 	return envUser, nil
 }
 
-// AddEnvironmentUser adds a new user to the database.
-func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName string) (*EnvironmentUser, error) {
+// AddEnvironmentUser adds a new user to the database, with the given
+// level of access to the environment.
+func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName string, access EnvironmentAccess) (*EnvironmentUser, error) {
+	if err := access.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// Ensure local user exists in state before adding them as an environment user.
 	if user.IsLocal() {
 		localUser, err := st.User(user)
@@ -175,7 +239,7 @@ func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName s
 	}
 
 	envuuid := st.EnvironUUID()
-	op := createEnvUserOp(envuuid, user, createdBy, displayName)
+	op := createEnvUserOp(envuuid, user, createdBy, displayName, access)
 	err := st.runTransaction([]txn.Op{op})
 	if err == txn.ErrAborted {
 		err = errors.AlreadyExistsf("environment user %q", user.Canonical())
@@ -193,7 +257,7 @@ func envUserID(user names.UserTag) string {
 	return strings.ToLower(username)
 }
 
-func createEnvUserOp(envuuid string, user, createdBy names.UserTag, displayName string) txn.Op {
+func createEnvUserOp(envuuid string, user, createdBy names.UserTag, displayName string, access EnvironmentAccess) txn.Op {
 	creatorname := createdBy.Canonical()
 	doc := &envUserDoc{
 		ID:          envUserID(user),
@@ -202,6 +266,7 @@ func createEnvUserOp(envuuid string, user, createdBy names.UserTag, displayName
 		DisplayName: displayName,
 		CreatedBy:   creatorname,
 		DateCreated: nowToTheSecond(),
+		Access:      access,
 	}
 	return txn.Op{
 		C:      envUsersC,
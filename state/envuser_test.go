@@ -28,7 +28,7 @@ func (s *EnvUserSuite) TestAddEnvironmentUser(c *gc.C) {
 	now := state.NowToTheSecond()
 	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "validusername", NoEnvUser: true})
 	createdBy := s.Factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
-	envUser, err := s.State.AddEnvironmentUser(user.UserTag(), createdBy.UserTag(), "")
+	envUser, err := s.State.AddEnvironmentUser(user.UserTag(), createdBy.UserTag(), "", state.EnvironmentWriteAccess)
 	c.Assert(err, jc.ErrorIsNil)
 
 	c.Assert(envUser.ID(), gc.Equals, fmt.Sprintf("%s:validusername@local", s.envTag.Id()))
@@ -37,6 +37,8 @@ func (s *EnvUserSuite) TestAddEnvironmentUser(c *gc.C) {
 	c.Assert(envUser.DisplayName(), gc.Equals, user.DisplayName())
 	c.Assert(envUser.CreatedBy(), gc.Equals, "createdby@local")
 	c.Assert(envUser.DateCreated().Equal(now) || envUser.DateCreated().After(now), jc.IsTrue)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
+	c.Assert(envUser.ReadOnly(), jc.IsFalse)
 	when, err := envUser.LastConnection()
 	c.Assert(err, jc.Satisfies, state.IsNeverConnectedError)
 	c.Assert(when.IsZero(), jc.IsTrue)
@@ -49,6 +51,7 @@ func (s *EnvUserSuite) TestAddEnvironmentUser(c *gc.C) {
 	c.Assert(envUser.DisplayName(), gc.Equals, user.DisplayName())
 	c.Assert(envUser.CreatedBy(), gc.Equals, "createdby@local")
 	c.Assert(envUser.DateCreated().Equal(now) || envUser.DateCreated().After(now), jc.IsTrue)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
 	when, err = envUser.LastConnection()
 	c.Assert(err, jc.Satisfies, state.IsNeverConnectedError)
 	c.Assert(when.IsZero(), jc.IsTrue)
@@ -58,7 +61,7 @@ func (s *EnvUserSuite) TestCaseUserNameVsId(c *gc.C) {
 	env, err := s.State.Environment()
 	c.Assert(err, jc.ErrorIsNil)
 
-	user, err := s.State.AddEnvironmentUser(names.NewUserTag("Bob@RandomProvider"), env.Owner(), "")
+	user, err := s.State.AddEnvironmentUser(names.NewUserTag("Bob@RandomProvider"), env.Owner(), "", state.EnvironmentWriteAccess)
 	c.Assert(err, gc.IsNil)
 	c.Assert(user.UserName(), gc.Equals, "Bob@RandomProvider")
 	c.Assert(user.ID(), gc.Equals, state.DocID(s.State, "bob@randomprovider"))
@@ -69,7 +72,7 @@ func (s *EnvUserSuite) TestCaseSensitiveEnvUserErrors(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{User: "Bob@ubuntuone"})
 
-	_, err = s.State.AddEnvironmentUser(names.NewUserTag("boB@ubuntuone"), env.Owner(), "")
+	_, err = s.State.AddEnvironmentUser(names.NewUserTag("boB@ubuntuone"), env.Owner(), "", state.EnvironmentWriteAccess)
 	c.Assert(err, gc.ErrorMatches, `environment user "boB@ubuntuone" already exists`)
 	c.Assert(errors.IsAlreadyExists(err), jc.IsTrue)
 }
@@ -113,15 +116,69 @@ func (s *EnvUserSuite) TestAddEnvironmentDisplayName(c *gc.C) {
 	c.Assert(envUser.DisplayName(), gc.Equals, "Override user display name")
 }
 
+func (s *EnvUserSuite) TestAddReadOnlyEnvironmentUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "validusername", NoEnvUser: true})
+	envUser, err := s.State.AddEnvironmentUser(user.UserTag(), s.Owner, "", state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+	c.Assert(envUser.ReadOnly(), jc.IsTrue)
+
+	envUser, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.ReadOnly(), jc.IsTrue)
+}
+
+func (s *EnvUserSuite) TestAddEnvironmentUserInvalidAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "validusername", NoEnvUser: true})
+	_, err := s.State.AddEnvironmentUser(user.UserTag(), s.Owner, "", "admin")
+	c.Assert(err, gc.ErrorMatches, `environment access "admin" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *EnvUserSuite) TestSetAccess(c *gc.C) {
+	envUser := s.Factory.MakeEnvUser(c, nil)
+	c.Assert(envUser.ReadOnly(), jc.IsFalse)
+
+	err := envUser.SetAccess(state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+
+	envUser, err = s.State.EnvironmentUser(envUser.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.ReadOnly(), jc.IsTrue)
+
+	err = envUser.SetAccess(state.EnvironmentWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	envUser, err = s.State.EnvironmentUser(envUser.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.ReadOnly(), jc.IsFalse)
+}
+
+func (s *EnvUserSuite) TestSetAccessInvalid(c *gc.C) {
+	envUser := s.Factory.MakeEnvUser(c, nil)
+	err := envUser.SetAccess("")
+	c.Assert(err, gc.ErrorMatches, `environment access "" not valid`)
+}
+
+func (s *EnvUserSuite) TestSetAccessRemovedUser(c *gc.C) {
+	envUser := s.Factory.MakeEnvUser(c, nil)
+	err := s.State.RemoveEnvironmentUser(envUser.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = envUser.SetAccess(state.EnvironmentReadAccess)
+	c.Assert(err, gc.ErrorMatches, `cannot set access for environment user ".*": environment user ".*" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *EnvUserSuite) TestAddEnvironmentNoUserFails(c *gc.C) {
 	createdBy := s.Factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
-	_, err := s.State.AddEnvironmentUser(names.NewLocalUserTag("validusername"), createdBy.UserTag(), "")
+	_, err := s.State.AddEnvironmentUser(names.NewLocalUserTag("validusername"), createdBy.UserTag(), "", state.EnvironmentWriteAccess)
 	c.Assert(err, gc.ErrorMatches, `user "validusername" does not exist locally: user "validusername" not found`)
 }
 
 func (s *EnvUserSuite) TestAddEnvironmentNoCreatedByUserFails(c *gc.C) {
 	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "validusername"})
-	_, err := s.State.AddEnvironmentUser(user.UserTag(), names.NewLocalUserTag("createdby"), "")
+	_, err := s.State.AddEnvironmentUser(user.UserTag(), names.NewLocalUserTag("createdby"), "", state.EnvironmentWriteAccess)
 	c.Assert(err, gc.ErrorMatches, `createdBy user "createdby" does not exist locally: user "createdby" not found`)
 }
 
@@ -170,7 +227,7 @@ func (s *EnvUserSuite) TestUpdateLastConnectionTwoEnvUsers(c *gc.C) {
 	// Create a second environment and add the same user to this.
 	st2 := s.Factory.MakeEnvironment(c, nil)
 	defer st2.Close()
-	envUser2, err := st2.AddEnvironmentUser(user.UserTag(), createdBy.UserTag(), "ignored")
+	envUser2, err := st2.AddEnvironmentUser(user.UserTag(), createdBy.UserTag(), "ignored", state.EnvironmentWriteAccess)
 	c.Assert(err, jc.ErrorIsNil)
 
 	// Now we have two environment users with the same username. Ensure we get
@@ -259,7 +316,7 @@ func (s *EnvUserSuite) newEnvWithUser(c *gc.C, name string, user names.UserTag)
 	newEnv, err := envState.Environment()
 	c.Assert(err, jc.ErrorIsNil)
 
-	_, err = envState.AddEnvironmentUser(user, newEnv.Owner(), "")
+	_, err = envState.AddEnvironmentUser(user, newEnv.Owner(), "", state.EnvironmentWriteAccess)
 	c.Assert(err, jc.ErrorIsNil)
 	return newEnv
 }
@@ -52,6 +52,7 @@ var (
 	PickAddress            = &pickAddress
 	AddVolumeOps           = (*State).addVolumeOps
 	CombineMeterStatus     = combineMeterStatus
+	PruneActionsBatchSize  = &pruneActionsBatchSize
 )
 
 type (
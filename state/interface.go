@@ -138,6 +138,10 @@ type ActionReceiver interface {
 	// ActionReceiver.
 	AddAction(name string, payload map[string]interface{}) (*Action, error)
 
+	// AddActionWithOptions queues an action with the given name,
+	// payload and options for this ActionReceiver.
+	AddActionWithOptions(name string, payload map[string]interface{}, opts ActionOptions) (*Action, error)
+
 	// CancelAction removes a pending Action from the queue for this
 	// ActionReceiver and marks it as cancelled.
 	CancelAction(action *Action) (*Action, error)
@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// logForwardPositionDoc records how far through an environment's
+// logs forwarding to a single target has progressed.
+type logForwardPositionDoc struct {
+	DocID   string        `bson:"_id"`
+	EnvUUID string        `bson:"env-uuid"`
+	Target  string        `bson:"target"`
+	Id      bson.ObjectId `bson:"id"`
+}
+
+// LogForwardPosition identifies the most recent log record sent to a
+// log forwarding target. Log record ids increase as records are
+// written, so all records with ids up to and including Id have been
+// forwarded. Unlike record times, which are set by the agents that
+// log them, ids are not affected by clock skew or delayed writes.
+type LogForwardPosition struct {
+	Id bson.ObjectId
+}
+
+// NewLogForwardPosition returns a position before any log records
+// written from the given time onwards.
+func NewLogForwardPosition(t time.Time) *LogForwardPosition {
+	return &LogForwardPosition{Id: bson.NewObjectIdWithTime(t)}
+}
+
+// Advance updates the position to record that the given log record
+// has been forwarded.
+func (pos *LogForwardPosition) Advance(record *LogRecord) {
+	if record.Id > pos.Id {
+		pos.Id = record.Id
+	}
+}
+
+// LogForwardPosition returns the position up to which the
+// environment's logs have been forwarded to the named target. If
+// nothing has been forwarded to the target, an error satisfying
+// errors.IsNotFound is returned.
+func (st *State) LogForwardPosition(target string) (*LogForwardPosition, error) {
+	positions, closer := st.getCollection(logForwardPositionsC)
+	defer closer()
+
+	var doc logForwardPositionDoc
+	err := positions.FindId(st.docID(target)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("log forwarding position for %q", target)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get log forwarding position for %q", target)
+	}
+	return &LogForwardPosition{Id: doc.Id}, nil
+}
+
+// SetLogForwardPosition records the position up to which the
+// environment's logs have been forwarded to the named target.
+func (st *State) SetLogForwardPosition(target string, pos *LogForwardPosition) error {
+	positions, closer := st.getCollection(logForwardPositionsC)
+	defer closer()
+
+	// The position is written for every log record forwarded, so
+	// we don't wait for it to reach a majority of the replica set.
+	positionsW := positions.Writeable()
+	session := positionsW.Underlying().Database.Session
+	session.SetSafe(&mgo.Safe{})
+
+	doc := logForwardPositionDoc{
+		DocID:   st.docID(target),
+		EnvUUID: st.EnvironUUID(),
+		Target:  target,
+		Id:      pos.Id,
+	}
+	_, err := positionsW.UpsertId(doc.DocID, doc)
+	return errors.Annotatef(err, "cannot set log forwarding position for %q", target)
+}
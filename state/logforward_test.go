@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+)
+
+type LogForwardSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&LogForwardSuite{})
+
+func (s *LogForwardSuite) TestPositionNotFound(c *gc.C) {
+	_, err := s.State.LogForwardPosition("syslog://10.0.0.1:514")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `log forwarding position for "syslog://10.0.0.1:514" not found`)
+}
+
+func (s *LogForwardSuite) TestSetPosition(c *gc.C) {
+	ids := []bson.ObjectId{bson.NewObjectId(), bson.NewObjectId()}
+	err := s.State.SetLogForwardPosition("syslog://10.0.0.1:514", &state.LogForwardPosition{
+		Id: ids[0],
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	pos, err := s.State.LogForwardPosition("syslog://10.0.0.1:514")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pos, jc.DeepEquals, &state.LogForwardPosition{Id: ids[0]})
+
+	// Setting the position again replaces it.
+	err = s.State.SetLogForwardPosition("syslog://10.0.0.1:514", &state.LogForwardPosition{
+		Id: ids[1],
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	pos, err = s.State.LogForwardPosition("syslog://10.0.0.1:514")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pos, jc.DeepEquals, &state.LogForwardPosition{Id: ids[1]})
+
+	// Positions are recorded separately for each target.
+	_, err = s.State.LogForwardPosition("gelf://10.0.0.1:12201")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *LogForwardSuite) TestPositionsAreEnvironmentSpecific(c *gc.C) {
+	otherSt := s.Factory.MakeEnvironment(c, nil)
+	defer otherSt.Close()
+
+	err := s.State.SetLogForwardPosition("syslog://10.0.0.1:514", state.NewLogForwardPosition(time.Now()))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = otherSt.LogForwardPosition("syslog://10.0.0.1:514")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *LogForwardSuite) TestPositionAdvance(c *gc.C) {
+	t0 := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	pos := state.NewLogForwardPosition(t0)
+	first := &state.LogRecord{Id: bson.NewObjectId(), Time: t0.Add(time.Millisecond)}
+	second := &state.LogRecord{Id: bson.NewObjectId(), Time: t0}
+
+	// The position follows record ids, even when a later record
+	// has an earlier time.
+	pos.Advance(first)
+	c.Assert(pos.Id, gc.Equals, first.Id)
+	pos.Advance(second)
+	c.Assert(pos.Id, gc.Equals, second.Id)
+
+	// The position never moves backwards.
+	pos.Advance(first)
+	c.Assert(pos.Id, gc.Equals, second.Id)
+}
@@ -126,6 +126,7 @@ type LogTailer interface {
 // LogRecord defines a single Juju log message as returned by
 // LogTailer.
 type LogRecord struct {
+	Id       bson.ObjectId
 	Time     time.Time
 	Entity   string
 	Module   string
@@ -145,6 +146,31 @@ type LogTailerParams struct {
 	IncludeModule []string
 	ExcludeModule []string
 	Oplog         *mgo.Collection // For testing only
+
+	// EndTime, if set, restricts the logs returned to those
+	// recorded before it. The LogTailer stops once it has returned
+	// all such logs: when EndTime is recent, it waits for logs to
+	// be written until one recorded at or after EndTime appears.
+	EndTime time.Time
+
+	// MessageRegex, if set, restricts the logs returned to those
+	// with a message matching the regular expression. It uses Go's
+	// regexp syntax and is applied by the LogTailer itself rather
+	// than by MongoDB, whose regular expressions differ.
+	MessageRegex string
+
+	// AfterId, if set, restricts the logs returned to those with
+	// ids greater than it. Ids increase as logs are written, so this
+	// allows a consumer to resume where it left off regardless of
+	// the times recorded in the logs. Logs already written are then
+	// returned in id order rather than time order.
+	AfterId bson.ObjectId
+
+	// Location, if set, restricts the logs returned to those
+	// written from the given source location. It may be either a
+	// "filename:lineno" pair, or just a filename to match any line
+	// in that file.
+	Location string
 }
 
 // oplogOverlap is used to decide on the initial oplog timestamp to
@@ -195,6 +221,10 @@ type logTailer struct {
 	logCh     chan *LogRecord
 	lastTime  time.Time
 	recentIds *recentIdTracker
+
+	// messageRegex holds the compiled MessageRegex parameter, if
+	// one was given.
+	messageRegex *regexp.Regexp
 }
 
 // Logs implements the LogTailer interface.
@@ -219,10 +249,28 @@ func (t *logTailer) Err() error {
 }
 
 func (t *logTailer) loop() error {
+	if t.params.MessageRegex != "" {
+		messageRegex, err := regexp.Compile(t.params.MessageRegex)
+		if err != nil {
+			return errors.Annotate(err, "invalid message regex")
+		}
+		t.messageRegex = messageRegex
+	}
+
+	if t.params.AfterId != "" {
+		// Logs written after the id can't have been added to the
+		// oplog before it was generated.
+		t.lastTime = t.params.AfterId.Time()
+	}
 	err := t.processCollection()
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if t.endTimePassed() {
+		// All the requested logs have already been written, so
+		// there's no need to wait for more.
+		return nil
+	}
 
 	err = t.tailOplog()
 	return errors.Trace(err)
@@ -233,6 +281,9 @@ func (t *logTailer) processCollection() error {
 	sel := t.paramsToSelector(t.params, "")
 	query := t.logsColl.Find(sel)
 
+	if t.params.InitialLines > 0 && t.messageRegex != nil {
+		return t.processCollectionTail(query)
+	}
 	if t.params.InitialLines > 0 {
 		// This is a little racy but it's good enough.
 		count, err := query.Count()
@@ -244,26 +295,89 @@ func (t *logTailer) processCollection() error {
 		}
 	}
 
-	iter := query.Sort("t", "_id").Iter()
+	sort := []string{"t", "_id"}
+	if t.params.AfterId != "" {
+		sort = []string{"_id"}
+	}
+	iter := query.Sort(sort...).Iter()
 	doc := new(logDoc)
 	for iter.Next(doc) {
-		select {
-		case <-t.tomb.Dying():
-			return errors.Trace(tomb.ErrDying)
-		case t.logCh <- logDocToRecord(doc):
-			t.lastTime = doc.Time
-			t.recentIds.Add(doc.Id)
+		if !t.matchesMessage(doc) {
+			continue
+		}
+		if err := t.sendCollectionDoc(doc); err != nil {
+			iter.Close()
+			return errors.Trace(err)
 		}
 	}
 	return errors.Trace(iter.Close())
 }
 
+// processCollectionTail returns the last InitialLines logs matching
+// the message filter. The filter isn't applied by MongoDB, so the
+// matching logs can't be counted up front; instead they are found
+// by walking back from the newest log.
+func (t *logTailer) processCollectionTail(query *mgo.Query) error {
+	var docs []*logDoc
+	iter := query.Sort("-t", "-_id").Iter()
+	doc := new(logDoc)
+	for len(docs) < t.params.InitialLines && iter.Next(doc) {
+		if t.matchesMessage(doc) {
+			docs = append(docs, doc)
+			doc = new(logDoc)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	for i := len(docs) - 1; i >= 0; i-- {
+		if err := t.sendCollectionDoc(docs[i]); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (t *logTailer) sendCollectionDoc(doc *logDoc) error {
+	select {
+	case <-t.tomb.Dying():
+		return errors.Trace(tomb.ErrDying)
+	case t.logCh <- logDocToRecord(doc):
+		t.lastTime = doc.Time
+		if t.params.AfterId != "" {
+			t.lastTime = doc.Id.Time()
+		}
+		t.recentIds.Add(doc.Id)
+	}
+	return nil
+}
+
+// matchesMessage reports whether the given log's message matches the
+// MessageRegex parameter.
+func (t *logTailer) matchesMessage(doc *logDoc) bool {
+	return t.messageRegex == nil || t.messageRegex.MatchString(doc.Message)
+}
+
+// endTimePassed reports whether the EndTime parameter is far enough
+// in the past that no more logs recorded before it are expected.
+func (t *logTailer) endTimePassed() bool {
+	if t.params.EndTime.IsZero() {
+		return false
+	}
+	return time.Now().After(t.params.EndTime.Add(oplogOverlap))
+}
+
 func (t *logTailer) tailOplog() error {
 	recentIds := t.recentIds.AsSet()
 
-	newParams := t.params
-	newParams.StartTime = t.lastTime
-	oplogSel := append(t.paramsToSelector(newParams, "o."),
+	// The end time isn't included in the selector, so that the
+	// arrival of a log recorded at or after it can be seen.
+	newParams := *t.params
+	if newParams.AfterId == "" {
+		newParams.StartTime = t.lastTime
+	}
+	newParams.EndTime = time.Time{}
+	oplogSel := append(t.paramsToSelector(&newParams, "o."),
 		bson.DocElem{"ns", logsDB + "." + logsC},
 	)
 
@@ -279,11 +393,20 @@ func (t *logTailer) tailOplog() error {
 	logger.Tracef("LogTailer starting oplog tailing: recent id count=%d, lastTime=%s, minOplogTs=%s",
 		recentIds.Length(), t.lastTime, minOplogTs)
 
+	// Stop waiting for logs before the end time once it's unlikely
+	// any more will be written, even if no later log arrives.
+	var endTimeout <-chan time.Time
+	if endTime := t.params.EndTime; !endTime.IsZero() {
+		endTimeout = time.After(endTime.Add(oplogOverlap).Sub(time.Now()))
+	}
+
 	skipCount := 0
 	for {
 		select {
 		case <-t.tomb.Dying():
 			return errors.Trace(tomb.ErrDying)
+		case <-endTimeout:
+			return nil
 		case oplogDoc, ok := <-oplogTailer.Out():
 			if !ok {
 				return errors.Annotate(oplogTailer.Err(), "oplog tailer died")
@@ -303,6 +426,14 @@ func (t *logTailer) tailOplog() error {
 				}
 				continue
 			}
+			if endTime := t.params.EndTime; !endTime.IsZero() && !doc.Time.Before(endTime) {
+				// All the logs before the end time have been
+				// written.
+				return nil
+			}
+			if !t.matchesMessage(doc) {
+				continue
+			}
 
 			select {
 			case <-t.tomb.Dying():
@@ -314,9 +445,16 @@ func (t *logTailer) tailOplog() error {
 }
 
 func (t *logTailer) paramsToSelector(params *LogTailerParams, prefix string) bson.D {
+	timeSel := bson.M{"$gte": params.StartTime}
+	if !params.EndTime.IsZero() {
+		timeSel["$lt"] = params.EndTime
+	}
 	sel := bson.D{
 		{"e", t.envUUID},
-		{"t", bson.M{"$gte": params.StartTime}},
+		{"t", timeSel},
+	}
+	if params.AfterId != "" {
+		sel = append(sel, bson.DocElem{"_id", bson.M{"$gt": params.AfterId}})
 	}
 	if params.MinLevel > loggo.UNSPECIFIED {
 		sel = append(sel, bson.DocElem{"v", bson.M{"$gte": params.MinLevel}})
@@ -337,6 +475,9 @@ func (t *logTailer) paramsToSelector(params *LogTailerParams, prefix string) bso
 		sel = append(sel,
 			bson.DocElem{"m", bson.M{"$not": bson.RegEx{Pattern: makeModulePattern(params.ExcludeModule)}}})
 	}
+	if params.Location != "" {
+		sel = append(sel, bson.DocElem{"l", bson.RegEx{Pattern: makeLocationPattern(params.Location)}})
+	}
 
 	if prefix != "" {
 		for i, elem := range sel {
@@ -364,6 +505,15 @@ func makeModulePattern(modules []string) string {
 	return `^(` + strings.Join(patterns, "|") + `)(\..+)?$`
 }
 
+func makeLocationPattern(location string) string {
+	pattern := regexp.QuoteMeta(location)
+	if !strings.Contains(location, ":") {
+		// No line number was given, so match any line in the file.
+		pattern += `:[0-9]+`
+	}
+	return `^` + pattern + `$`
+}
+
 func newRecentIdTracker(maxLen int) *recentIdTracker {
 	return &recentIdTracker{
 		ids: deque.NewWithMaxLen(maxLen),
@@ -414,6 +564,7 @@ func (s *objectIdSet) Length() int {
 
 func logDocToRecord(doc *logDoc) *LogRecord {
 	return &LogRecord{
+		Id:       doc.Id,
 		Time:     doc.Time,
 		Entity:   doc.Entity,
 		Module:   doc.Module,
@@ -473,6 +473,167 @@ func (s *LogTailerSuite) TestIncludeExcludeModule(c *gc.C) {
 	s.checkLogTailerFiltering(params, writeLogs, assert)
 }
 
+func (s *LogTailerSuite) TestEndTime(c *gc.C) {
+	threshT := time.Now()
+	want := logTemplate{Message: "want"}
+	s.writeLogsT(c, threshT.Add(-5*time.Second), threshT.Add(-time.Millisecond), 5, want)
+	s.writeLogsT(c, threshT, threshT.Add(5*time.Second), 5, logTemplate{Message: "dont want"})
+
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		EndTime: threshT,
+		Oplog:   s.oplogColl,
+	})
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 5, want)
+
+	// The tailer stops once all the logs before the end time have
+	// been returned, rather than tailing the oplog.
+	select {
+	case log, ok := <-tailer.Logs():
+		c.Assert(ok, jc.IsFalse, gc.Commentf("unexpected log %#v", log))
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for tailer to stop")
+	}
+	c.Assert(tailer.Err(), jc.ErrorIsNil)
+}
+
+func (s *LogTailerSuite) TestEndTimeInFuture(c *gc.C) {
+	threshT := time.Now().Add(time.Hour)
+	want := logTemplate{Message: "want"}
+	s.writeLogs(c, 2, want)
+
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		EndTime: threshT,
+		Oplog:   s.oplogColl,
+	})
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 2, want)
+
+	// Logs written before the end time are read from the oplog.
+	s.writeLogs(c, 2, want)
+	s.assertTailer(c, tailer, 2, want)
+
+	// The tailer stops once a log recorded at the end time arrives.
+	s.writeLogsT(c, threshT, threshT, 1, logTemplate{Message: "dont want"})
+	select {
+	case log, ok := <-tailer.Logs():
+		c.Assert(ok, jc.IsFalse, gc.Commentf("unexpected log %#v", log))
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for tailer to stop")
+	}
+	c.Assert(tailer.Err(), jc.ErrorIsNil)
+}
+
+func (s *LogTailerSuite) TestAfterId(c *gc.C) {
+	s.writeLogs(c, 1, logTemplate{Message: "dont want"})
+	var doc struct {
+		Id bson.ObjectId `bson:"_id"`
+	}
+	err := s.logsColl.Find(nil).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Logs written later are returned, whatever their times.
+	threshT := time.Now()
+	want := logTemplate{Message: "want"}
+	s.writeLogsT(c, threshT.Add(-time.Hour), threshT.Add(-time.Hour), 2, want)
+
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		AfterId: doc.Id,
+		Oplog:   s.oplogColl,
+	})
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 2, want)
+
+	// Write more logs. These will be read from the oplog.
+	s.writeLogsT(c, threshT.Add(-time.Hour), threshT.Add(-time.Hour), 2, want)
+	s.assertTailer(c, tailer, 2, want)
+}
+
+func (s *LogTailerSuite) TestMessageRegex(c *gc.C) {
+	good := logTemplate{Message: "connection refused by 10.0.0.1"}
+	bad := logTemplate{Message: "all is well"}
+	writeLogs := func() {
+		s.writeLogs(c, 1, bad)
+		s.writeLogs(c, 1, good)
+		s.writeLogs(c, 1, bad)
+	}
+	params := &state.LogTailerParams{
+		MessageRegex: `refused by [0-9.]+$`,
+	}
+	assert := func(tailer state.LogTailer) {
+		s.assertTailer(c, tailer, 1, good)
+	}
+	s.checkLogTailerFiltering(params, writeLogs, assert)
+}
+
+func (s *LogTailerSuite) TestMessageRegexInitialLines(c *gc.C) {
+	good := logTemplate{Message: "connection refused"}
+	s.writeLogs(c, 3, good)
+	s.writeLogs(c, 5, logTemplate{Message: "all is well"})
+
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		MessageRegex: `refused$`,
+		InitialLines: 2,
+	})
+	defer tailer.Stop()
+
+	// The last 2 matching lines are returned, even though newer
+	// lines exist that don't match.
+	s.assertTailer(c, tailer, 2, good)
+}
+
+func (s *LogTailerSuite) TestMessageRegexInvalid(c *gc.C) {
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		MessageRegex: `(`,
+	})
+	defer tailer.Stop()
+
+	select {
+	case _, ok := <-tailer.Logs():
+		c.Assert(ok, jc.IsFalse)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for tailer to stop")
+	}
+	c.Assert(tailer.Err(), gc.ErrorMatches, "invalid message regex: .*")
+}
+
+func (s *LogTailerSuite) TestLocation(c *gc.C) {
+	line1 := logTemplate{Location: "machine.go:12"}
+	line2 := logTemplate{Location: "machine.go:123"}
+	other := logTemplate{Location: "unit.go:12"}
+	writeLogs := func() {
+		s.writeLogs(c, 1, line1)
+		s.writeLogs(c, 1, other)
+		s.writeLogs(c, 1, line2)
+	}
+	params := &state.LogTailerParams{
+		Location: "machine.go:12",
+	}
+	assert := func(tailer state.LogTailer) {
+		s.assertTailer(c, tailer, 1, line1)
+	}
+	s.checkLogTailerFiltering(params, writeLogs, assert)
+}
+
+func (s *LogTailerSuite) TestLocationFileOnly(c *gc.C) {
+	line1 := logTemplate{Location: "machine.go:12"}
+	line2 := logTemplate{Location: "machine.go:123"}
+	other := logTemplate{Location: "othermachine.go:12"}
+	writeLogs := func() {
+		s.writeLogs(c, 1, line1)
+		s.writeLogs(c, 1, other)
+		s.writeLogs(c, 1, line2)
+	}
+	params := &state.LogTailerParams{
+		Location: "machine.go",
+	}
+	assert := func(tailer state.LogTailer) {
+		s.assertTailer(c, tailer, 1, line1)
+		s.assertTailer(c, tailer, 1, line2)
+	}
+	s.checkLogTailerFiltering(params, writeLogs, assert)
+}
+
 func (s *LogTailerSuite) checkLogTailerFiltering(
 	params *state.LogTailerParams,
 	writeLogs func(),
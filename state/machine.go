@@ -142,6 +142,10 @@ type machineDoc struct {
 	// Placement is the placement directive that should be used when provisioning
 	// an instance for the machine.
 	Placement string `bson:",omitempty"`
+
+	// UpgradeSeries records the progress of a series upgrade of the
+	// machine, if one is in progress.
+	UpgradeSeries *upgradeSeriesDoc `bson:"upgradeseries,omitempty"`
 }
 
 func newMachine(st *State, doc *machineDoc) *Machine {
@@ -0,0 +1,666 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/state/description"
+	statestorage "github.com/juju/juju/state/storage"
+	"github.com/juju/juju/tools"
+)
+
+// Export returns a description of the environment and everything in
+// it, from which Import can recreate the environment in another state
+// server. Every entity in the environment must be alive; the history
+// of statuses, actions and metrics is not exported.
+func (st *State) Export() (*description.Environment, error) {
+	env, err := st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if env.Life() != Alive {
+		return nil, errors.Errorf("environment is no longer alive")
+	}
+	e := &exporter{st: st}
+	if err := e.readAll(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	out := &description.Environment{
+		Owner:       env.Owner().Canonical(),
+		Config:      cfg.AllAttrs(),
+		Constraints: e.constraints(environGlobalKey),
+		Annotations: e.annotations[environGlobalKey],
+		Sequences:   e.sequences,
+	}
+	steps := []func(*description.Environment) error{
+		e.users,
+		e.machines,
+		e.charms,
+		e.services,
+		e.relations,
+		e.storage,
+	}
+	for _, step := range steps {
+		if err := step(out); err != nil {
+			return nil, errors.Annotate(err, "cannot export environment")
+		}
+	}
+	return out, nil
+}
+
+// exporter holds the documents that describe parts of several
+// entities, keyed by global key, while an environment is exported.
+type exporter struct {
+	st          *State
+	statuses    map[string]statusDoc
+	cons        map[string]constraintsDoc
+	annotations map[string]map[string]string
+	settings    map[string]map[string]interface{}
+	ports       map[string][]portsDoc
+	sequences   map[string]int
+}
+
+func (e *exporter) readAll() error {
+	var statuses []struct {
+		DocID     string `bson:"_id"`
+		statusDoc `bson:",inline"`
+	}
+	if err := e.readCollection(statusesC, &statuses); err != nil {
+		return errors.Trace(err)
+	}
+	e.statuses = make(map[string]statusDoc)
+	for _, doc := range statuses {
+		e.statuses[e.st.localID(doc.DocID)] = doc.statusDoc
+	}
+
+	var cons []struct {
+		DocID          string `bson:"_id"`
+		constraintsDoc `bson:",inline"`
+	}
+	if err := e.readCollection(constraintsC, &cons); err != nil {
+		return errors.Trace(err)
+	}
+	e.cons = make(map[string]constraintsDoc)
+	for _, doc := range cons {
+		e.cons[e.st.localID(doc.DocID)] = doc.constraintsDoc
+	}
+
+	var annotations []annotatorDoc
+	if err := e.readCollection(annotationsC, &annotations); err != nil {
+		return errors.Trace(err)
+	}
+	e.annotations = make(map[string]map[string]string)
+	for _, doc := range annotations {
+		if len(doc.Annotations) > 0 {
+			e.annotations[doc.GlobalKey] = doc.Annotations
+		}
+	}
+
+	var settings []settingsDoc
+	if err := e.readCollection(settingsC, &settings); err != nil {
+		return errors.Trace(err)
+	}
+	e.settings = make(map[string]map[string]interface{})
+	for _, doc := range settings {
+		e.settings[e.st.localID(doc.DocID)] = copyMap(doc.Settings, unescapeReplacer.Replace)
+	}
+
+	var ports []portsDoc
+	if err := e.readCollection(openedPortsC, &ports); err != nil {
+		return errors.Trace(err)
+	}
+	e.ports = make(map[string][]portsDoc)
+	for _, doc := range ports {
+		e.ports[doc.MachineID] = append(e.ports[doc.MachineID], doc)
+	}
+
+	var sequences []sequenceDoc
+	if err := e.readCollection(sequenceC, &sequences); err != nil {
+		return errors.Trace(err)
+	}
+	e.sequences = make(map[string]int)
+	for _, doc := range sequences {
+		e.sequences[doc.Name] = doc.Counter
+	}
+	return nil
+}
+
+// readCollection reads all of the environment's documents in the named
+// collection.
+func (e *exporter) readCollection(name string, docs interface{}) error {
+	coll, closer := e.st.getCollection(name)
+	defer closer()
+	return errors.Annotatef(coll.Find(nil).All(docs), "cannot read %s", name)
+}
+
+// checkAlive returns an error if the described entity is not alive.
+func checkAlive(life Life, kind, id string) error {
+	if life != Alive {
+		return errors.Errorf("%s %q is %s", kind, id, life)
+	}
+	return nil
+}
+
+func (e *exporter) constraints(globalKey string) string {
+	doc, ok := e.cons[globalKey]
+	if !ok {
+		return ""
+	}
+	return doc.value().String()
+}
+
+func (e *exporter) status(globalKey string) description.Status {
+	doc := e.statuses[globalKey]
+	return description.Status{
+		Value:   string(doc.Status),
+		Message: doc.StatusInfo,
+		Data:    unescapeKeys(doc.StatusData),
+		Updated: time.Unix(0, doc.Updated).UTC(),
+	}
+}
+
+// optionalStatus returns the status with the given global key, or nil
+// if there is none.
+func (e *exporter) optionalStatus(globalKey string) *description.Status {
+	if _, ok := e.statuses[globalKey]; !ok {
+		return nil
+	}
+	status := e.status(globalKey)
+	return &status
+}
+
+func (e *exporter) users(out *description.Environment) error {
+	var docs []envUserDoc
+	if err := e.readCollection(envUsersC, &docs); err != nil {
+		return errors.Trace(err)
+	}
+	for _, doc := range docs {
+		out.Users = append(out.Users, description.User{
+			Name:        doc.UserName,
+			DisplayName: doc.DisplayName,
+			CreatedBy:   doc.CreatedBy,
+			DateCreated: doc.DateCreated.UTC(),
+			Access:      string(doc.Access),
+		})
+	}
+	return nil
+}
+
+func (e *exporter) machines(out *description.Environment) error {
+	var docs []machineDoc
+	if err := e.readCollection(machinesC, &docs); err != nil {
+		return errors.Trace(err)
+	}
+	var instances []instanceData
+	if err := e.readCollection(instanceDataC, &instances); err != nil {
+		return errors.Trace(err)
+	}
+	instancesByMachine := make(map[string]instanceData)
+	for _, doc := range instances {
+		instancesByMachine[doc.MachineId] = doc
+	}
+	// Parents are listed before their containers.
+	sort.Sort(machineDocsById(docs))
+	for _, doc := range docs {
+		if err := checkAlive(doc.Life, "machine", doc.Id); err != nil {
+			return errors.Trace(err)
+		}
+		globalKey := machineGlobalKey(doc.Id)
+		machine := description.Machine{
+			Id:                doc.Id,
+			Nonce:             doc.Nonce,
+			Series:            doc.Series,
+			ContainerType:     doc.ContainerType,
+			PasswordHash:      doc.PasswordHash,
+			Placement:         doc.Placement,
+			Tools:             exportTools(doc.Tools),
+			ProviderAddresses: exportAddresses(doc.Addresses),
+			MachineAddresses:  exportAddresses(doc.MachineAddresses),
+			Constraints:       e.constraints(globalKey),
+			Status:            e.status(globalKey),
+			Annotations:       e.annotations[globalKey],
+		}
+		for _, job := range doc.Jobs {
+			machine.Jobs = append(machine.Jobs, job.String())
+		}
+		if doc.PreferredPublicAddress.Value != "" {
+			machine.PreferredPublicAddress = exportAddress(doc.PreferredPublicAddress)
+		}
+		if doc.PreferredPrivateAddress.Value != "" {
+			machine.PreferredPrivateAddress = exportAddress(doc.PreferredPrivateAddress)
+		}
+		if doc.SupportedContainersKnown {
+			containers := []string{}
+			for _, containerType := range doc.SupportedContainers {
+				containers = append(containers, string(containerType))
+			}
+			machine.SupportedContainers = &containers
+		}
+		if inst, ok := instancesByMachine[doc.Id]; ok {
+			machine.Instance = &description.Instance{
+				Id:               string(inst.InstanceId),
+				Status:           inst.Status,
+				Arch:             inst.Arch,
+				Mem:              inst.Mem,
+				RootDisk:         inst.RootDisk,
+				CpuCores:         inst.CpuCores,
+				CpuPower:         inst.CpuPower,
+				Tags:             inst.Tags,
+				AvailabilityZone: inst.AvailZone,
+			}
+		}
+		for _, ports := range e.ports[doc.Id] {
+			opened := description.OpenedPorts{Network: ports.NetworkName}
+			for _, p := range ports.Ports {
+				opened.Ports = append(opened.Ports, description.PortRange{
+					Unit:     p.UnitName,
+					FromPort: p.FromPort,
+					ToPort:   p.ToPort,
+					Protocol: p.Protocol,
+				})
+			}
+			machine.OpenedPorts = append(machine.OpenedPorts, opened)
+		}
+		out.Machines = append(out.Machines, machine)
+	}
+	return nil
+}
+
+// machineDocsById sorts machine documents so that each machine comes
+// before its containers.
+type machineDocsById []machineDoc
+
+func (m machineDocsById) Len() int      { return len(m) }
+func (m machineDocsById) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+func (m machineDocsById) Less(i, j int) bool {
+	return len(m[i].Id) < len(m[j].Id) || len(m[i].Id) == len(m[j].Id) && m[i].Id < m[j].Id
+}
+
+func exportTools(t *tools.Tools) *description.Tools {
+	if t == nil {
+		return nil
+	}
+	return &description.Tools{
+		Version: t.Version.String(),
+		URL:     t.URL,
+		SHA256:  t.SHA256,
+		Size:    t.Size,
+	}
+}
+
+func exportAddress(addr address) *description.Address {
+	return &description.Address{
+		Value:       addr.Value,
+		Type:        addr.AddressType,
+		NetworkName: addr.NetworkName,
+		Scope:       addr.Scope,
+	}
+}
+
+func exportAddresses(addrs []address) []description.Address {
+	var out []description.Address
+	for _, addr := range addrs {
+		out = append(out, *exportAddress(addr))
+	}
+	return out
+}
+
+func (e *exporter) charms(out *description.Environment) error {
+	var docs []charmDoc
+	if err := e.readCollection(charmsC, &docs); err != nil {
+		return errors.Trace(err)
+	}
+	stor := statestorage.NewStorage(e.st.EnvironUUID(), e.st.MongoSession())
+	for _, doc := range docs {
+		if doc.Placeholder {
+			continue
+		}
+		if doc.PendingUpload {
+			return errors.Errorf("charm %q has not been uploaded", doc.URL)
+		}
+		archive, err := readCharmArchive(stor, doc.StoragePath)
+		if err != nil {
+			return errors.Annotatef(err, "cannot read charm %q", doc.URL)
+		}
+		out.Charms = append(out.Charms, description.Charm{
+			URL:     doc.URL.String(),
+			SHA256:  doc.BundleSha256,
+			Archive: base64.StdEncoding.EncodeToString(archive),
+		})
+	}
+	return nil
+}
+
+func readCharmArchive(stor statestorage.Storage, path string) ([]byte, error) {
+	r, _, err := stor.Get(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+func (e *exporter) services(out *description.Environment) error {
+	var docs []serviceDoc
+	if err := e.readCollection(servicesC, &docs); err != nil {
+		return errors.Trace(err)
+	}
+	var units []unitDoc
+	if err := e.readCollection(unitsC, &units); err != nil {
+		return errors.Trace(err)
+	}
+	unitsByService := make(map[string][]unitDoc)
+	for _, doc := range units {
+		unitsByService[doc.Service] = append(unitsByService[doc.Service], doc)
+	}
+	var storageCons []storageConstraintsDoc
+	if err := e.readCollection(storageConstraintsC, &storageCons); err != nil {
+		return errors.Trace(err)
+	}
+	storageConsByKey := make(map[string]map[string]StorageConstraints)
+	for _, doc := range storageCons {
+		storageConsByKey[e.st.localID(doc.DocID)] = doc.Constraints
+	}
+	var meterStatuses []meterStatusDoc
+	if err := e.readCollection(meterStatusC, &meterStatuses); err != nil {
+		return errors.Trace(err)
+	}
+	meterStatusByKey := make(map[string]meterStatusDoc)
+	for _, doc := range meterStatuses {
+		meterStatusByKey[e.st.localID(doc.DocID)] = doc
+	}
+
+	for _, doc := range docs {
+		if err := checkAlive(doc.Life, "service", doc.Name); err != nil {
+			return errors.Trace(err)
+		}
+		globalKey := serviceGlobalKey(doc.Name)
+		service := description.Service{
+			Name:               doc.Name,
+			Series:             doc.Series,
+			Subordinate:        doc.Subordinate,
+			CharmURL:           doc.CharmURL.String(),
+			ForceCharm:         doc.ForceCharm,
+			Exposed:            doc.Exposed,
+			MinUnits:           doc.MinUnits,
+			Owner:              doc.OwnerTag,
+			Settings:           e.settings[serviceSettingsKey(doc.Name, doc.CharmURL)],
+			LeadershipSettings: e.settings[leadershipSettingsKey(doc.Name)],
+			Constraints:        e.constraints(globalKey),
+			ActionLimits:       doc.ActionLimits,
+			Annotations:        e.annotations[globalKey],
+		}
+		if status, ok := e.statuses[globalKey]; ok && !status.NeverSet {
+			service.Status = e.optionalStatus(globalKey)
+		}
+		if len(doc.MetricCredentials) > 0 {
+			service.MetricCredentials = base64.StdEncoding.EncodeToString(doc.MetricCredentials)
+		}
+		for name, cons := range storageConsByKey[globalKey] {
+			if service.StorageConstraints == nil {
+				service.StorageConstraints = make(map[string]description.StorageConstraints)
+			}
+			service.StorageConstraints[name] = description.StorageConstraints{
+				Pool:  cons.Pool,
+				Size:  cons.Size,
+				Count: cons.Count,
+			}
+		}
+		for _, unitDoc := range unitsByService[doc.Name] {
+			if err := checkAlive(unitDoc.Life, "unit", unitDoc.Name); err != nil {
+				return errors.Trace(err)
+			}
+			// Settings are only exported for the service's charm.
+			if unitDoc.CharmURL != nil && unitDoc.CharmURL.String() != doc.CharmURL.String() {
+				return errors.Errorf("unit %q has not been upgraded to charm %q", unitDoc.Name, doc.CharmURL)
+			}
+			agentKey := unitAgentGlobalKey(unitDoc.Name)
+			meterStatus := meterStatusByKey[agentKey]
+			unit := description.Unit{
+				Name:            unitDoc.Name,
+				Machine:         unitDoc.MachineId,
+				Principal:       unitDoc.Principal,
+				Subordinates:    unitDoc.Subordinates,
+				PasswordHash:    unitDoc.PasswordHash,
+				Tools:           exportTools(unitDoc.Tools),
+				WorkloadVersion: unitDoc.WorkloadVersion,
+				Constraints:     e.constraints(agentKey),
+				WorkloadStatus:  e.status(unitGlobalKey(unitDoc.Name)),
+				AgentStatus:     e.status(agentKey),
+				MeterStatus: description.MeterStatus{
+					Code: meterStatus.Code,
+					Info: meterStatus.Info,
+				},
+				Annotations: e.annotations[unitGlobalKey(unitDoc.Name)],
+			}
+			if unitDoc.CharmURL != nil {
+				unit.CharmURL = unitDoc.CharmURL.String()
+			}
+			service.Units = append(service.Units, unit)
+		}
+		out.Services = append(out.Services, service)
+	}
+	return nil
+}
+
+func (e *exporter) relations(out *description.Environment) error {
+	var docs []relationDoc
+	if err := e.readCollection(relationsC, &docs); err != nil {
+		return errors.Trace(err)
+	}
+	var scopes []relationScopeDoc
+	if err := e.readCollection(relationScopesC, &scopes); err != nil {
+		return errors.Trace(err)
+	}
+	for _, doc := range docs {
+		if err := checkAlive(doc.Life, "relation", doc.Key); err != nil {
+			return errors.Trace(err)
+		}
+		relation := description.Relation{
+			Id:  doc.Id,
+			Key: doc.Key,
+		}
+		for _, ep := range doc.Endpoints {
+			relation.Endpoints = append(relation.Endpoints, description.Endpoint{
+				Service:   ep.ServiceName,
+				Name:      ep.Name,
+				Interface: ep.Interface,
+				Role:      string(ep.Role),
+				Scope:     string(ep.Scope),
+				Optional:  ep.Optional,
+				Limit:     ep.Limit,
+			})
+		}
+		// Scope keys are of the form r#<id>[#<container>]#<role>#<unit>.
+		prefix := relationScopePrefix(doc.Id)
+		for _, scope := range scopes {
+			if scope.Departing || !strings.HasPrefix(scope.Key, prefix) {
+				continue
+			}
+			unitName := scope.unitName()
+			serviceName, err := names.UnitService(unitName)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			for i := range relation.Endpoints {
+				ep := &relation.Endpoints[i]
+				if ep.Service != serviceName {
+					continue
+				}
+				if ep.UnitSettings == nil {
+					ep.UnitSettings = make(map[string]map[string]interface{})
+				}
+				settings := e.settings[scope.Key]
+				if settings == nil {
+					settings = make(map[string]interface{})
+				}
+				ep.UnitSettings[unitName] = settings
+			}
+		}
+		out.Relations = append(out.Relations, relation)
+	}
+	return nil
+}
+
+func (e *exporter) storage(out *description.Environment) error {
+	var instances []storageInstanceDoc
+	if err := e.readCollection(storageInstancesC, &instances); err != nil {
+		return errors.Trace(err)
+	}
+	var attachments []storageAttachmentDoc
+	if err := e.readCollection(storageAttachmentsC, &attachments); err != nil {
+		return errors.Trace(err)
+	}
+	for _, doc := range instances {
+		if err := checkAlive(doc.Life, "storage", doc.Id); err != nil {
+			return errors.Trace(err)
+		}
+		instance := description.StorageInstance{
+			Id:          doc.Id,
+			Kind:        string(storageKinds[doc.Kind]),
+			Owner:       doc.Owner,
+			StorageName: doc.StorageName,
+		}
+		if doc.CharmURL != nil {
+			instance.CharmURL = doc.CharmURL.String()
+		}
+		for _, att := range attachments {
+			if att.StorageInstance == doc.Id {
+				instance.Attachments = append(instance.Attachments, att.Unit)
+			}
+		}
+		out.Storage = append(out.Storage, instance)
+	}
+
+	var volumes []volumeDoc
+	if err := e.readCollection(volumesC, &volumes); err != nil {
+		return errors.Trace(err)
+	}
+	var volumeAttachments []volumeAttachmentDoc
+	if err := e.readCollection(volumeAttachmentsC, &volumeAttachments); err != nil {
+		return errors.Trace(err)
+	}
+	for _, doc := range volumes {
+		if err := checkAlive(doc.Life, "volume", doc.Name); err != nil {
+			return errors.Trace(err)
+		}
+		volume := description.Volume{
+			Id:      doc.Name,
+			Storage: doc.StorageId,
+			Binding: doc.Binding,
+			Status:  e.optionalStatus(volumeGlobalKey(doc.Name)),
+		}
+		if doc.Params != nil {
+			volume.Pool = doc.Params.Pool
+			volume.Size = doc.Params.Size
+		}
+		if doc.Info != nil {
+			volume.Info = &description.VolumeInfo{
+				VolumeId:   doc.Info.VolumeId,
+				HardwareId: doc.Info.HardwareId,
+				Size:       doc.Info.Size,
+				Pool:       doc.Info.Pool,
+				Persistent: doc.Info.Persistent,
+			}
+		}
+		for _, att := range volumeAttachments {
+			if att.Volume != doc.Name {
+				continue
+			}
+			attachment := description.VolumeAttachment{Machine: att.Machine}
+			if att.Params != nil {
+				attachment.ReadOnly = att.Params.ReadOnly
+			}
+			if att.Info != nil {
+				attachment.Info = &description.VolumeAttachmentInfo{
+					DeviceName: att.Info.DeviceName,
+					DeviceLink: att.Info.DeviceLink,
+					BusAddress: att.Info.BusAddress,
+					ReadOnly:   att.Info.ReadOnly,
+				}
+			}
+			volume.Attachments = append(volume.Attachments, attachment)
+		}
+		out.Volumes = append(out.Volumes, volume)
+	}
+
+	var filesystems []filesystemDoc
+	if err := e.readCollection(filesystemsC, &filesystems); err != nil {
+		return errors.Trace(err)
+	}
+	var filesystemAttachments []filesystemAttachmentDoc
+	if err := e.readCollection(filesystemAttachmentsC, &filesystemAttachments); err != nil {
+		return errors.Trace(err)
+	}
+	for _, doc := range filesystems {
+		if err := checkAlive(doc.Life, "filesystem", doc.FilesystemId); err != nil {
+			return errors.Trace(err)
+		}
+		filesystem := description.Filesystem{
+			Id:      doc.FilesystemId,
+			Storage: doc.StorageId,
+			Volume:  doc.VolumeId,
+			Binding: doc.Binding,
+			Status:  e.optionalStatus(filesystemGlobalKey(doc.FilesystemId)),
+		}
+		if doc.Params != nil {
+			filesystem.Pool = doc.Params.Pool
+			filesystem.Size = doc.Params.Size
+		}
+		if doc.Info != nil {
+			filesystem.Info = &description.FilesystemInfo{
+				FilesystemId: doc.Info.FilesystemId,
+				Size:         doc.Info.Size,
+				Pool:         doc.Info.Pool,
+			}
+		}
+		for _, att := range filesystemAttachments {
+			if att.Filesystem != doc.FilesystemId {
+				continue
+			}
+			attachment := description.FilesystemAttachment{Machine: att.Machine}
+			if att.Params != nil {
+				attachment.Location = att.Params.Location
+				attachment.ReadOnly = att.Params.ReadOnly
+			}
+			if att.Info != nil {
+				attachment.Info = &description.FilesystemAttachmentInfo{
+					MountPoint: att.Info.MountPoint,
+					ReadOnly:   att.Info.ReadOnly,
+				}
+			}
+			filesystem.Attachments = append(filesystem.Attachments, attachment)
+		}
+		out.Filesystems = append(out.Filesystems, filesystem)
+	}
+	return nil
+}
+
+// relationScopePrefix returns the prefix of the keys of the relation
+// scope documents, and of the unit settings, of the relation with the
+// given id.
+func relationScopePrefix(id int) string {
+	return "r#" + strconv.Itoa(id) + "#"
+}
+
+// storageKinds maps storage kinds to the charm storage types that
+// describe them.
+var storageKinds = map[StorageKind]charm.StorageType{
+	StorageKindBlock:      charm.StorageBlock,
+	StorageKindFilesystem: charm.StorageFilesystem,
+}
@@ -0,0 +1,952 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state/description"
+	statestorage "github.com/juju/juju/state/storage"
+	"github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
+)
+
+// controllerConfigAttrs holds the config attributes which every
+// environment hosted by a state server shares with the state server's
+// own environment. They match those that the EnvironmentManager API
+// requires of new hosted environments.
+var controllerConfigAttrs = []string{
+	"type",
+	"ca-cert",
+	"state-port",
+	"api-port",
+	"syslog-port",
+	"rsyslog-ca-cert",
+	"rsyslog-ca-key",
+}
+
+// Import creates a new environment, hosted by the state server, from
+// the given description, which will usually have been made by Export
+// on another state server. Machines keep their instance ids, nonces
+// and agent passwords, so that running agents can be pointed at the
+// new state server without redeploying anything. The values of the
+// environment config that are shared with the state server are taken
+// from this state server, not from the description.
+//
+// The environment's owner and any other local users must already
+// exist in the state server.
+func (st *State) Import(desc *description.Environment) (_ *Environment, _ *State, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot import environment")
+
+	ssEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	attrs, err := importConfigAttrs(ssEnv, desc.Config)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	cfg, err := config.New(config.NoDefaults, attrs)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	uuid, ok := cfg.UUID()
+	if !ok {
+		return nil, nil, errors.Errorf("environment uuid was not supplied")
+	}
+	if !names.IsValidUser(desc.Owner) {
+		return nil, nil, errors.NotValidf("owner %q", desc.Owner)
+	}
+	owner := names.NewUserTag(desc.Owner)
+	if owner.IsLocal() {
+		if _, err := st.User(owner); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	envTag := names.NewEnvironTag(uuid)
+	if _, err := st.GetEnvironment(envTag); err == nil {
+		return nil, nil, errors.AlreadyExistsf("environment %q", uuid)
+	} else if !errors.IsNotFound(err) {
+		return nil, nil, errors.Trace(err)
+	}
+
+	newSt, err := st.ForEnviron(envTag)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			newSt.Close()
+		}
+	}()
+	ops, err := newSt.envSetupOps(cfg, uuid, ssEnv.UUID(), owner)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	i := &importer{
+		st:    newSt,
+		desc:  desc,
+		owner: owner,
+		stor:  statestorage.NewStorage(uuid, newSt.MongoSession()),
+	}
+	defer func() {
+		if err != nil {
+			i.removeCharmArchives()
+		}
+	}()
+	i.index()
+	steps := []func() ([]txn.Op, error){
+		i.environOps,
+		i.userOps,
+		i.machineOps,
+		i.charmOps,
+		i.serviceOps,
+		i.relationOps,
+		i.storageOps,
+	}
+	for _, step := range steps {
+		stepOps, err := step()
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		ops = append(ops, stepOps...)
+	}
+	// The environment's constraints are created by the setup
+	// operations; replace them with those being imported.
+	cons, err := constraints.Parse(desc.Constraints)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	for n, op := range ops {
+		if op.C == constraintsC && op.Id == newSt.docID(environGlobalKey) {
+			ops[n] = createConstraintsOp(newSt, environGlobalKey, cons)
+		}
+	}
+
+	if err := newSt.runTransaction(ops); err == txn.ErrAborted {
+		return nil, nil, errors.Errorf("environment %q or some of its contents already exist", cfg.Name())
+	} else if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	env, err := newSt.Environment()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return env, newSt, nil
+}
+
+// importConfigAttrs returns the config attributes of the imported
+// environment: those described, but with the values shared with the
+// hosting state server replaced by its own.
+func importConfigAttrs(ssEnv *Environment, described map[string]interface{}) (map[string]interface{}, error) {
+	ssCfg, err := ssEnv.Config()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ssAttrs := ssCfg.AllAttrs()
+	attrs := make(map[string]interface{})
+	for key, value := range described {
+		attrs[key] = value
+	}
+	// The CA's private key is never kept in environment config, and
+	// a described one would belong to the other state server's CA.
+	delete(attrs, "ca-private-key")
+	for _, key := range controllerConfigAttrs {
+		if value, ok := ssAttrs[key]; ok {
+			attrs[key] = value
+		} else {
+			delete(attrs, key)
+		}
+	}
+	return attrs, nil
+}
+
+// importer creates the operations that recreate a described
+// environment.
+type importer struct {
+	st    *State
+	desc  *description.Environment
+	owner names.UserTag
+	stor  statestorage.Storage
+
+	// storedCharms holds the storage paths of the charm archives
+	// stored so far, so they can be removed if the import fails.
+	storedCharms []string
+
+	units       map[string]description.Unit
+	relationIds map[string][]int
+	storage     map[string][]string
+	volumes     map[string][]string
+	filesystems map[string][]string
+}
+
+// index records the relationships between the described entities
+// that are denormalised into their documents.
+func (i *importer) index() {
+	i.units = make(map[string]description.Unit)
+	for _, service := range i.desc.Services {
+		for _, unit := range service.Units {
+			i.units[unit.Name] = unit
+		}
+	}
+	i.relationIds = make(map[string][]int)
+	for _, relation := range i.desc.Relations {
+		for _, ep := range relation.Endpoints {
+			i.relationIds[ep.Service] = append(i.relationIds[ep.Service], relation.Id)
+		}
+	}
+	i.storage = make(map[string][]string)
+	for _, s := range i.desc.Storage {
+		for _, unit := range s.Attachments {
+			i.storage[unit] = append(i.storage[unit], s.Id)
+		}
+	}
+	i.volumes = make(map[string][]string)
+	for _, volume := range i.desc.Volumes {
+		for _, att := range volume.Attachments {
+			i.volumes[att.Machine] = append(i.volumes[att.Machine], volume.Id)
+		}
+	}
+	i.filesystems = make(map[string][]string)
+	for _, filesystem := range i.desc.Filesystems {
+		for _, att := range filesystem.Attachments {
+			i.filesystems[att.Machine] = append(i.filesystems[att.Machine], filesystem.Id)
+		}
+	}
+}
+
+func (i *importer) environOps() ([]txn.Op, error) {
+	var ops []txn.Op
+	env := names.NewEnvironTag(i.st.EnvironUUID())
+	ops = append(ops, i.annotationsOps(environGlobalKey, env, i.desc.Annotations)...)
+	for name, counter := range i.desc.Sequences {
+		ops = append(ops, txn.Op{
+			C:      sequenceC,
+			Id:     i.st.docID(name),
+			Assert: txn.DocMissing,
+			Insert: &sequenceDoc{
+				DocID:   i.st.docID(name),
+				Name:    name,
+				EnvUUID: i.st.EnvironUUID(),
+				Counter: counter,
+			},
+		})
+	}
+	return ops, nil
+}
+
+func (i *importer) annotationsOps(globalKey string, tag names.Tag, annotations map[string]string) []txn.Op {
+	if len(annotations) == 0 {
+		return nil
+	}
+	return []txn.Op{{
+		C:      annotationsC,
+		Id:     i.st.docID(globalKey),
+		Assert: txn.DocMissing,
+		Insert: &annotatorDoc{
+			GlobalKey:   globalKey,
+			Tag:         tag.String(),
+			Annotations: annotations,
+		},
+	}}
+}
+
+func (i *importer) userOps() ([]txn.Op, error) {
+	var ops []txn.Op
+	for _, user := range i.desc.Users {
+		if !names.IsValidUser(user.Name) {
+			return nil, errors.NotValidf("user %q", user.Name)
+		}
+		tag := names.NewUserTag(user.Name)
+		if tag.Canonical() == i.owner.Canonical() {
+			// The owner is added with the environment.
+			continue
+		}
+		if tag.IsLocal() {
+			if _, err := i.st.User(tag); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		access := EnvironmentAccess(user.Access)
+		if access != EnvironmentReadAccess && access != EnvironmentWriteAccess {
+			return nil, errors.NotValidf("access %q for user %q", user.Access, user.Name)
+		}
+		ops = append(ops, txn.Op{
+			C:      envUsersC,
+			Id:     envUserID(tag),
+			Assert: txn.DocMissing,
+			Insert: &envUserDoc{
+				ID:          envUserID(tag),
+				EnvUUID:     i.st.EnvironUUID(),
+				UserName:    tag.Canonical(),
+				DisplayName: user.DisplayName,
+				CreatedBy:   user.CreatedBy,
+				DateCreated: user.DateCreated,
+				Access:      access,
+			},
+		})
+	}
+	return ops, nil
+}
+
+// importStatus returns the status document for the described status.
+func (i *importer) importStatus(status description.Status) statusDoc {
+	return statusDoc{
+		EnvUUID:    i.st.EnvironUUID(),
+		Status:     Status(status.Value),
+		StatusInfo: status.Message,
+		StatusData: escapeKeys(status.Data),
+		Updated:    status.Updated.UnixNano(),
+	}
+}
+
+func importTools(t *description.Tools) (*tools.Tools, error) {
+	if t == nil {
+		return nil, nil
+	}
+	vers, err := version.ParseBinary(t.Version)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &tools.Tools{
+		Version: vers,
+		URL:     t.URL,
+		SHA256:  t.SHA256,
+		Size:    t.Size,
+	}, nil
+}
+
+func importAddress(addr description.Address) address {
+	return address{
+		Value:       addr.Value,
+		AddressType: addr.Type,
+		NetworkName: addr.NetworkName,
+		Scope:       addr.Scope,
+	}
+}
+
+func importAddresses(addrs []description.Address) []address {
+	var out []address
+	for _, addr := range addrs {
+		out = append(out, importAddress(addr))
+	}
+	return out
+}
+
+func importJob(name string) (MachineJob, error) {
+	for job, jobName := range jobNames {
+		if string(jobName) == name {
+			return job, nil
+		}
+	}
+	return 0, errors.NotValidf("machine job %q", name)
+}
+
+func (i *importer) machineOps() ([]txn.Op, error) {
+	containers := make(map[string][]string)
+	for _, m := range i.desc.Machines {
+		if parentId := ParentId(m.Id); parentId != "" {
+			containers[parentId] = append(containers[parentId], m.Id)
+		}
+	}
+	principals := make(map[string][]string)
+	for _, unit := range i.units {
+		if unit.Principal == "" && unit.Machine != "" {
+			principals[unit.Machine] = append(principals[unit.Machine], unit.Name)
+		}
+	}
+
+	var ops []txn.Op
+	for _, m := range i.desc.Machines {
+		if !names.IsValidMachine(m.Id) {
+			return nil, errors.NotValidf("machine id %q", m.Id)
+		}
+		mdoc := &machineDoc{
+			DocID:            i.st.docID(m.Id),
+			Id:               m.Id,
+			EnvUUID:          i.st.EnvironUUID(),
+			Nonce:            m.Nonce,
+			Series:           m.Series,
+			ContainerType:    m.ContainerType,
+			Principals:       principals[m.Id],
+			Life:             Alive,
+			PasswordHash:     m.PasswordHash,
+			Clean:            len(principals[m.Id]) == 0,
+			Volumes:          i.volumes[m.Id],
+			Filesystems:      i.filesystems[m.Id],
+			Addresses:        importAddresses(m.ProviderAddresses),
+			MachineAddresses: importAddresses(m.MachineAddresses),
+			Placement:        m.Placement,
+		}
+		for _, name := range m.Jobs {
+			job, err := importJob(name)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if job == JobManageEnviron {
+				return nil, errors.NotSupportedf("importing state server machine %q", m.Id)
+			}
+			mdoc.Jobs = append(mdoc.Jobs, job)
+		}
+		t, err := importTools(m.Tools)
+		if err != nil {
+			return nil, errors.Annotatef(err, "machine %q", m.Id)
+		}
+		mdoc.Tools = t
+		if m.PreferredPublicAddress != nil {
+			mdoc.PreferredPublicAddress = importAddress(*m.PreferredPublicAddress)
+		}
+		if m.PreferredPrivateAddress != nil {
+			mdoc.PreferredPrivateAddress = importAddress(*m.PreferredPrivateAddress)
+		}
+		if m.SupportedContainers != nil {
+			mdoc.SupportedContainersKnown = true
+			for _, containerType := range *m.SupportedContainers {
+				mdoc.SupportedContainers = append(mdoc.SupportedContainers, instance.ContainerType(containerType))
+			}
+		}
+		cons, err := constraints.Parse(m.Constraints)
+		if err != nil {
+			return nil, errors.Annotatef(err, "machine %q", m.Id)
+		}
+
+		globalKey := machineGlobalKey(m.Id)
+		ops = append(ops,
+			createConstraintsOp(i.st, globalKey, cons),
+			createStatusOp(i.st, globalKey, i.importStatus(m.Status)),
+			createRequestedNetworksOp(i.st, globalKey, nil),
+			createMachineBlockDevicesOp(m.Id),
+			i.st.insertNewContainerRefOp(m.Id, containers[m.Id]...),
+			txn.Op{
+				C:      machinesC,
+				Id:     mdoc.DocID,
+				Assert: txn.DocMissing,
+				Insert: mdoc,
+			},
+		)
+		if inst := m.Instance; inst != nil {
+			ops = append(ops, txn.Op{
+				C:      instanceDataC,
+				Id:     mdoc.DocID,
+				Assert: txn.DocMissing,
+				Insert: &instanceData{
+					DocID:      mdoc.DocID,
+					MachineId:  m.Id,
+					InstanceId: instance.Id(inst.Id),
+					EnvUUID:    i.st.EnvironUUID(),
+					Status:     inst.Status,
+					Arch:       inst.Arch,
+					Mem:        inst.Mem,
+					RootDisk:   inst.RootDisk,
+					CpuCores:   inst.CpuCores,
+					CpuPower:   inst.CpuPower,
+					Tags:       inst.Tags,
+					AvailZone:  inst.AvailabilityZone,
+				},
+			})
+		}
+		for _, opened := range m.OpenedPorts {
+			key := portsGlobalKey(m.Id, opened.Network)
+			doc := &portsDoc{
+				DocID:       i.st.docID(key),
+				EnvUUID:     i.st.EnvironUUID(),
+				MachineID:   m.Id,
+				NetworkName: opened.Network,
+			}
+			for _, p := range opened.Ports {
+				doc.Ports = append(doc.Ports, PortRange{
+					UnitName: p.Unit,
+					FromPort: p.FromPort,
+					ToPort:   p.ToPort,
+					Protocol: p.Protocol,
+				})
+			}
+			ops = append(ops, txn.Op{
+				C:      openedPortsC,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: doc,
+			})
+		}
+		ops = append(ops, i.annotationsOps(globalKey, names.NewMachineTag(m.Id), m.Annotations)...)
+	}
+	return ops, nil
+}
+
+// charmOps stores the described charm archives in the environment's
+// storage, and returns the operations that add the charms.
+func (i *importer) charmOps() ([]txn.Op, error) {
+	var ops []txn.Op
+	for _, c := range i.desc.Charms {
+		curl, err := charm.ParseURL(c.URL)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		data, err := base64.StdEncoding.DecodeString(c.Archive)
+		if err != nil {
+			return nil, errors.Annotatef(err, "charm %q", curl)
+		}
+		if sum := fmt.Sprintf("%x", sha256.Sum256(data)); sum != c.SHA256 {
+			return nil, errors.Errorf("charm %q archive has SHA256 %s, expected %s", curl, sum, c.SHA256)
+		}
+		ch, err := charm.ReadCharmArchiveBytes(data)
+		if err != nil {
+			return nil, errors.Annotatef(err, "charm %q", curl)
+		}
+		uuid, err := utils.NewUUID()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		storagePath := fmt.Sprintf("charms/%s-%s", curl, uuid)
+		if err := i.stor.Put(storagePath, bytes.NewReader(data), int64(len(data))); err != nil {
+			return nil, errors.Annotatef(err, "cannot store charm %q", curl)
+		}
+		i.storedCharms = append(i.storedCharms, storagePath)
+		charmOps, err := insertCharmOps(i.st, ch, curl, storagePath, c.SHA256)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, charmOps...)
+	}
+	return ops, nil
+}
+
+// removeCharmArchives removes any charm archives stored by charmOps.
+func (i *importer) removeCharmArchives() {
+	for _, storagePath := range i.storedCharms {
+		if err := i.stor.Remove(storagePath); err != nil {
+			logger.Warningf("cannot remove charm archive %q: %v", storagePath, err)
+		}
+	}
+}
+
+func (i *importer) serviceOps() ([]txn.Op, error) {
+	var ops []txn.Op
+	for _, s := range i.desc.Services {
+		if !names.IsValidService(s.Name) {
+			return nil, errors.NotValidf("service name %q", s.Name)
+		}
+		curl, err := charm.ParseURL(s.CharmURL)
+		if err != nil {
+			return nil, errors.Annotatef(err, "service %q", s.Name)
+		}
+		cons, err := constraints.Parse(s.Constraints)
+		if err != nil {
+			return nil, errors.Annotatef(err, "service %q", s.Name)
+		}
+		var metricCredentials []byte
+		if s.MetricCredentials != "" {
+			metricCredentials, err = base64.StdEncoding.DecodeString(s.MetricCredentials)
+			if err != nil {
+				return nil, errors.Annotatef(err, "service %q metric credentials", s.Name)
+			}
+		}
+		storageCons := make(map[string]StorageConstraints)
+		for name, sc := range s.StorageConstraints {
+			storageCons[name] = StorageConstraints{
+				Pool:  sc.Pool,
+				Size:  sc.Size,
+				Count: sc.Count,
+			}
+		}
+		var status statusDoc
+		if s.Status != nil {
+			status = i.importStatus(*s.Status)
+		} else {
+			status = statusDoc{
+				EnvUUID:    i.st.EnvironUUID(),
+				Status:     StatusUnknown,
+				StatusInfo: MessageWaitForAgentInit,
+				Updated:    nowToTheSecond().UnixNano(),
+				NeverSet:   true,
+			}
+		}
+		// The service and each of its units that has a charm hold
+		// a reference to the service's settings.
+		refCount := 1
+		for _, unit := range s.Units {
+			if unit.CharmURL != "" {
+				refCount++
+			}
+		}
+
+		globalKey := serviceGlobalKey(s.Name)
+		settingsKey := serviceSettingsKey(s.Name, curl)
+		sdoc := &serviceDoc{
+			DocID:             i.st.docID(s.Name),
+			Name:              s.Name,
+			EnvUUID:           i.st.EnvironUUID(),
+			Series:            s.Series,
+			Subordinate:       s.Subordinate,
+			CharmURL:          curl,
+			ForceCharm:        s.ForceCharm,
+			Life:              Alive,
+			UnitCount:         len(s.Units),
+			RelationCount:     len(i.relationIds[s.Name]),
+			Exposed:           s.Exposed,
+			MinUnits:          s.MinUnits,
+			OwnerTag:          s.Owner,
+			MetricCredentials: metricCredentials,
+			ActionLimits:      s.ActionLimits,
+		}
+		ops = append(ops,
+			createConstraintsOp(i.st, globalKey, cons),
+			createRequestedNetworksOp(i.st, globalKey, nil),
+			createStorageConstraintsOp(globalKey, storageCons),
+			createSettingsOp(settingsKey, s.Settings),
+			createSettingsOp(leadershipSettingsKey(s.Name), s.LeadershipSettings),
+			createStatusOp(i.st, globalKey, status),
+			txn.Op{
+				C:      settingsrefsC,
+				Id:     i.st.docID(settingsKey),
+				Assert: txn.DocMissing,
+				Insert: settingsRefsDoc{
+					RefCount: refCount,
+					EnvUUID:  i.st.EnvironUUID(),
+				},
+			},
+			txn.Op{
+				C:      servicesC,
+				Id:     sdoc.DocID,
+				Assert: txn.DocMissing,
+				Insert: sdoc,
+			},
+		)
+		if s.MinUnits > 0 {
+			ops = append(ops, txn.Op{
+				C:      minUnitsC,
+				Id:     i.st.docID(s.Name),
+				Assert: txn.DocMissing,
+				Insert: &minUnitsDoc{
+					ServiceName: s.Name,
+					EnvUUID:     i.st.EnvironUUID(),
+				},
+			})
+		}
+		ops = append(ops, i.annotationsOps(globalKey, names.NewServiceTag(s.Name), s.Annotations)...)
+
+		for _, unit := range s.Units {
+			unitOps, err := i.unitOps(s, unit)
+			if err != nil {
+				return nil, errors.Annotatef(err, "unit %q", unit.Name)
+			}
+			ops = append(ops, unitOps...)
+		}
+	}
+	return ops, nil
+}
+
+func (i *importer) unitOps(s description.Service, unit description.Unit) ([]txn.Op, error) {
+	if !names.IsValidUnit(unit.Name) {
+		return nil, errors.NotValidf("unit name %q", unit.Name)
+	}
+	udoc := &unitDoc{
+		DocID:                  i.st.docID(unit.Name),
+		Name:                   unit.Name,
+		EnvUUID:                i.st.EnvironUUID(),
+		Service:                s.Name,
+		Series:                 s.Series,
+		Principal:              unit.Principal,
+		Subordinates:           unit.Subordinates,
+		StorageAttachmentCount: len(i.storage[unit.Name]),
+		MachineId:              unit.Machine,
+		Life:                   Alive,
+		PasswordHash:           unit.PasswordHash,
+		WorkloadVersion:        unit.WorkloadVersion,
+	}
+	if unit.CharmURL != "" {
+		curl, err := charm.ParseURL(unit.CharmURL)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		udoc.CharmURL = curl
+	}
+	t, err := importTools(unit.Tools)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	udoc.Tools = t
+
+	globalKey := unitGlobalKey(unit.Name)
+	agentGlobalKey := unitAgentGlobalKey(unit.Name)
+	ops := []txn.Op{
+		createStatusOp(i.st, globalKey, i.importStatus(unit.WorkloadStatus)),
+		createStatusOp(i.st, agentGlobalKey, i.importStatus(unit.AgentStatus)),
+		createMeterStatusOp(i.st, agentGlobalKey, &meterStatusDoc{
+			Code: unit.MeterStatus.Code,
+			Info: unit.MeterStatus.Info,
+		}),
+		{
+			C:      unitsC,
+			Id:     udoc.DocID,
+			Assert: txn.DocMissing,
+			Insert: udoc,
+		},
+	}
+	if unit.Principal == "" {
+		cons, err := constraints.Parse(unit.Constraints)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, createConstraintsOp(i.st, agentGlobalKey, cons))
+	}
+	ops = append(ops, i.annotationsOps(globalKey, names.NewUnitTag(unit.Name), unit.Annotations)...)
+	return ops, nil
+}
+
+func (i *importer) relationOps() ([]txn.Op, error) {
+	var ops []txn.Op
+	for _, r := range i.desc.Relations {
+		doc := &relationDoc{
+			DocID:   i.st.docID(r.Key),
+			Key:     r.Key,
+			EnvUUID: i.st.EnvironUUID(),
+			Id:      r.Id,
+			Life:    Alive,
+		}
+		for _, ep := range r.Endpoints {
+			doc.Endpoints = append(doc.Endpoints, Endpoint{
+				ServiceName: ep.Service,
+				Relation: charm.Relation{
+					Name:      ep.Name,
+					Role:      charm.RelationRole(ep.Role),
+					Interface: ep.Interface,
+					Optional:  ep.Optional,
+					Limit:     ep.Limit,
+					Scope:     charm.RelationScope(ep.Scope),
+				},
+			})
+		}
+		if key := relationKey(doc.Endpoints); key != r.Key {
+			return nil, errors.Errorf("relation %d has key %q, expected %q", r.Id, r.Key, key)
+		}
+		for _, ep := range r.Endpoints {
+			for unitName, settings := range ep.UnitSettings {
+				unit, ok := i.units[unitName]
+				if !ok {
+					return nil, errors.NotFoundf("unit %q in relation %q", unitName, r.Key)
+				}
+				// See Relation.Unit and RelationUnit.key.
+				scope := []string{"r", fmt.Sprint(r.Id)}
+				if charm.RelationScope(ep.Scope) == charm.ScopeContainer {
+					container := unit.Principal
+					if container == "" {
+						container = unit.Name
+					}
+					scope = append(scope, container)
+				}
+				scopeKey := strings.Join(append(scope, ep.Role, unitName), "#")
+				ops = append(ops, createSettingsOp(scopeKey, settings), txn.Op{
+					C:      relationScopesC,
+					Id:     i.st.docID(scopeKey),
+					Assert: txn.DocMissing,
+					Insert: &relationScopeDoc{
+						DocID:   i.st.docID(scopeKey),
+						Key:     scopeKey,
+						EnvUUID: i.st.EnvironUUID(),
+					},
+				})
+				doc.UnitCount++
+			}
+		}
+		ops = append(ops, txn.Op{
+			C:      relationsC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		})
+	}
+	return ops, nil
+}
+
+func (i *importer) storageOps() ([]txn.Op, error) {
+	kinds := make(map[string]StorageKind)
+	for kind, storageType := range storageKinds {
+		kinds[string(storageType)] = kind
+	}
+
+	var ops []txn.Op
+	for _, s := range i.desc.Storage {
+		kind, ok := kinds[s.Kind]
+		if !ok {
+			return nil, errors.NotValidf("storage %q kind %q", s.Id, s.Kind)
+		}
+		doc := &storageInstanceDoc{
+			DocID:           i.st.docID(s.Id),
+			EnvUUID:         i.st.EnvironUUID(),
+			Id:              s.Id,
+			Kind:            kind,
+			Life:            Alive,
+			Owner:           s.Owner,
+			StorageName:     s.StorageName,
+			AttachmentCount: len(s.Attachments),
+		}
+		if s.CharmURL != "" {
+			curl, err := charm.ParseURL(s.CharmURL)
+			if err != nil {
+				return nil, errors.Annotatef(err, "storage %q", s.Id)
+			}
+			doc.CharmURL = curl
+		}
+		ops = append(ops, txn.Op{
+			C:      storageInstancesC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		})
+		for _, unit := range s.Attachments {
+			id := i.st.docID(storageAttachmentId(unit, s.Id))
+			ops = append(ops, txn.Op{
+				C:      storageAttachmentsC,
+				Id:     id,
+				Assert: txn.DocMissing,
+				Insert: &storageAttachmentDoc{
+					DocID:           id,
+					EnvUUID:         i.st.EnvironUUID(),
+					Unit:            unit,
+					StorageInstance: s.Id,
+					Life:            Alive,
+				},
+			})
+		}
+	}
+
+	pending := description.Status{Value: string(StatusPending), Updated: nowToTheSecond()}
+	for _, v := range i.desc.Volumes {
+		doc := &volumeDoc{
+			DocID:           i.st.docID(v.Id),
+			Name:            v.Id,
+			EnvUUID:         i.st.EnvironUUID(),
+			Life:            Alive,
+			StorageId:       v.Storage,
+			AttachmentCount: len(v.Attachments),
+			Binding:         v.Binding,
+		}
+		if v.Info != nil {
+			doc.Info = &VolumeInfo{
+				HardwareId: v.Info.HardwareId,
+				Size:       v.Info.Size,
+				Pool:       v.Info.Pool,
+				VolumeId:   v.Info.VolumeId,
+				Persistent: v.Info.Persistent,
+			}
+		} else {
+			doc.Params = &VolumeParams{Pool: v.Pool, Size: v.Size}
+		}
+		status := pending
+		if v.Status != nil {
+			status = *v.Status
+		}
+		ops = append(ops,
+			createStatusOp(i.st, volumeGlobalKey(v.Id), i.importStatus(status)),
+			txn.Op{
+				C:      volumesC,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: doc,
+			},
+		)
+		for _, att := range v.Attachments {
+			attDoc := &volumeAttachmentDoc{
+				DocID:   i.st.docID(volumeAttachmentId(att.Machine, v.Id)),
+				EnvUUID: i.st.EnvironUUID(),
+				Volume:  v.Id,
+				Machine: att.Machine,
+				Life:    Alive,
+			}
+			if att.Info != nil {
+				attDoc.Info = &VolumeAttachmentInfo{
+					DeviceName: att.Info.DeviceName,
+					DeviceLink: att.Info.DeviceLink,
+					BusAddress: att.Info.BusAddress,
+					ReadOnly:   att.Info.ReadOnly,
+				}
+			} else {
+				attDoc.Params = &VolumeAttachmentParams{ReadOnly: att.ReadOnly}
+			}
+			ops = append(ops, txn.Op{
+				C:      volumeAttachmentsC,
+				Id:     attDoc.DocID,
+				Assert: txn.DocMissing,
+				Insert: attDoc,
+			})
+		}
+	}
+
+	for _, f := range i.desc.Filesystems {
+		doc := &filesystemDoc{
+			DocID:           i.st.docID(f.Id),
+			FilesystemId:    f.Id,
+			EnvUUID:         i.st.EnvironUUID(),
+			Life:            Alive,
+			StorageId:       f.Storage,
+			VolumeId:        f.Volume,
+			AttachmentCount: len(f.Attachments),
+			Binding:         f.Binding,
+		}
+		if f.Info != nil {
+			doc.Info = &FilesystemInfo{
+				Size:         f.Info.Size,
+				Pool:         f.Info.Pool,
+				FilesystemId: f.Info.FilesystemId,
+			}
+		} else {
+			doc.Params = &FilesystemParams{Pool: f.Pool, Size: f.Size}
+		}
+		status := pending
+		if f.Status != nil {
+			status = *f.Status
+		}
+		ops = append(ops,
+			createStatusOp(i.st, filesystemGlobalKey(f.Id), i.importStatus(status)),
+			txn.Op{
+				C:      filesystemsC,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: doc,
+			},
+		)
+		for _, att := range f.Attachments {
+			attDoc := &filesystemAttachmentDoc{
+				DocID:      i.st.docID(filesystemAttachmentId(att.Machine, f.Id)),
+				EnvUUID:    i.st.EnvironUUID(),
+				Filesystem: f.Id,
+				Machine:    att.Machine,
+				Life:       Alive,
+			}
+			if att.Info != nil {
+				attDoc.Info = &FilesystemAttachmentInfo{
+					MountPoint: att.Info.MountPoint,
+					ReadOnly:   att.Info.ReadOnly,
+				}
+			} else {
+				attDoc.Params = &FilesystemAttachmentParams{
+					Location: att.Location,
+					ReadOnly: att.ReadOnly,
+				}
+			}
+			ops = append(ops, txn.Op{
+				C:      filesystemAttachmentsC,
+				Id:     attDoc.DocID,
+				Assert: txn.DocMissing,
+				Insert: attDoc,
+			})
+		}
+	}
+	return ops, nil
+}
@@ -0,0 +1,258 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/description"
+	"github.com/juju/juju/state/storage"
+	"github.com/juju/juju/testcharms"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type MigrationSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&MigrationSuite{})
+
+// addCharm adds the named testing charm to the environment, with its
+// archive in the environment's storage.
+func (s *MigrationSuite) addCharm(c *gc.C, name string) *state.Charm {
+	archive := testcharms.Repo.CharmArchive(c.MkDir(), name)
+	data, err := ioutil.ReadFile(archive.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL(fmt.Sprintf("local:quantal/%s-%d", name, archive.Revision()))
+	storagePath := "charms/" + curl.String()
+	stor := storage.NewStorage(s.State.EnvironUUID(), s.State.MongoSession())
+	err = stor.Put(storagePath, bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	sch, err := s.State.AddCharm(archive, curl, storagePath, fmt.Sprintf("%x", sha256.Sum256(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	return sch
+}
+
+// makeEnvironment populates the environment with a related pair of
+// services, each with a unit on a provisioned machine.
+func (s *MigrationSuite) makeEnvironment(c *gc.C) {
+	wordpress := s.Factory.MakeService(c, &factory.ServiceParams{
+		Charm: s.addCharm(c, "wordpress"),
+	})
+	mysql := s.Factory.MakeService(c, &factory.ServiceParams{
+		Charm: s.addCharm(c, "mysql"),
+	})
+	err := wordpress.SetAnnotations(map[string]string{"gui-x": "10"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress.SetConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress.UpdateConfigSettings(charm.Settings{"blog-title": "Migrated"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	wordpressUnit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: wordpress, SetCharmURL: true})
+	mysqlUnit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: mysql, SetCharmURL: true})
+	err = wordpressUnit.SetWorkloadVersion("4.2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, unit := range []*state.Unit{wordpressUnit, mysqlUnit} {
+		ru, err := rel.Unit(unit)
+		c.Assert(err, jc.ErrorIsNil)
+		err = ru.EnterScope(map[string]interface{}{"unit": unit.Name()})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+// importCopy imports the description as a new environment.
+func (s *MigrationSuite) importCopy(c *gc.C, desc *description.Environment) (*state.Environment, *state.State) {
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	desc.Config["uuid"] = uuid.String()
+	desc.Config["name"] = "imported"
+	env, st, err := s.State.Import(desc)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { st.Close() })
+	return env, st
+}
+
+func (s *MigrationSuite) TestExport(c *gc.C) {
+	s.makeEnvironment(c)
+
+	desc, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(desc.UUID(), gc.Equals, s.State.EnvironUUID())
+	c.Assert(desc.Owner, gc.Equals, s.Owner.Canonical())
+	c.Assert(desc.Machines, gc.HasLen, 2)
+	c.Assert(desc.Machines[0].Instance, gc.NotNil)
+	c.Assert(desc.Charms, gc.HasLen, 2)
+	c.Assert(desc.Services, gc.HasLen, 2)
+	c.Assert(desc.Relations, gc.HasLen, 1)
+	c.Assert(desc.Relations[0].Endpoints, gc.HasLen, 2)
+
+	wordpress := desc.Services[0]
+	c.Assert(wordpress.Name, gc.Equals, "wordpress")
+	c.Assert(wordpress.Annotations, jc.DeepEquals, map[string]string{"gui-x": "10"})
+	c.Assert(wordpress.Constraints, gc.Equals, "mem=4096M")
+	c.Assert(wordpress.Settings, jc.DeepEquals, map[string]interface{}{"blog-title": "Migrated"})
+	c.Assert(wordpress.Units, gc.HasLen, 1)
+	c.Assert(wordpress.Units[0].Machine, gc.Equals, desc.Machines[0].Id)
+	c.Assert(wordpress.Units[0].WorkloadVersion, gc.Equals, "4.2")
+}
+
+func (s *MigrationSuite) TestExportDyingMachine(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	err := machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `cannot export environment: machine "0" is dying`)
+}
+
+func (s *MigrationSuite) TestImport(c *gc.C) {
+	s.makeEnvironment(c)
+	desc, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	machineId := desc.Machines[0].Id
+	instanceId := desc.Machines[0].Instance.Id
+
+	env, st := s.importCopy(c, desc)
+	c.Assert(env.Name(), gc.Equals, "imported")
+	c.Assert(env.Owner(), gc.Equals, s.Owner)
+
+	machine, err := st.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := machine.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, instance.Id(instanceId))
+
+	service, err := st.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := service.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["blog-title"], gc.Equals, "Migrated")
+	ch, _, err := service.Charm()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ch.StoragePath(), gc.Not(gc.Equals), "")
+
+	rels, err := service.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 1)
+	unit, err := st.Unit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rels[0].Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	inScope, err := ru.InScope()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(inScope, jc.IsTrue)
+
+	// Exporting the imported environment describes it as before.
+	imported, err := st.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(imported.Config, jc.DeepEquals, desc.Config)
+	c.Assert(imported.Machines, jc.DeepEquals, desc.Machines)
+	c.Assert(imported.Charms, jc.DeepEquals, desc.Charms)
+	c.Assert(imported.Services, jc.DeepEquals, desc.Services)
+	c.Assert(imported.Relations, jc.DeepEquals, desc.Relations)
+	c.Assert(imported.Sequences, jc.DeepEquals, desc.Sequences)
+}
+
+func (s *MigrationSuite) TestImportSerialized(c *gc.C) {
+	s.makeEnvironment(c)
+	desc, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := description.Serialize(desc)
+	c.Assert(err, jc.ErrorIsNil)
+	desc, err = description.Deserialize(data)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, st := s.importCopy(c, desc)
+	units, err := st.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 2)
+}
+
+func (s *MigrationSuite) TestImportUsesStateServerConfig(c *gc.C) {
+	desc, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	// Describe an environment hosted by another state server.
+	desc.Config["ca-cert"] = testing.OtherCACert
+	desc.Config["ca-private-key"] = testing.OtherCAKey
+	desc.Config["state-port"] = 27017
+	desc.Config["api-port"] = 17777
+	desc.Config["syslog-port"] = 6515
+	desc.Config["rsyslog-ca-cert"] = testing.OtherCACert
+	desc.Config["rsyslog-ca-key"] = testing.OtherCAKey
+	data, err := description.Serialize(desc)
+	c.Assert(err, jc.ErrorIsNil)
+	desc, err = description.Deserialize(data)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, st := s.importCopy(c, desc)
+	cfg, err := st.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	ssCfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	caCert, _ := cfg.CACert()
+	ssCACert, _ := ssCfg.CACert()
+	c.Assert(caCert, gc.Equals, ssCACert)
+	_, ok := cfg.CAPrivateKey()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(cfg.StatePort(), gc.Equals, ssCfg.StatePort())
+	c.Assert(cfg.APIPort(), gc.Equals, ssCfg.APIPort())
+	c.Assert(cfg.SyslogPort(), gc.Equals, ssCfg.SyslogPort())
+	attrs, ssAttrs := cfg.AllAttrs(), ssCfg.AllAttrs()
+	c.Assert(attrs["rsyslog-ca-cert"], gc.Equals, ssAttrs["rsyslog-ca-cert"])
+	c.Assert(attrs["rsyslog-ca-key"], gc.Equals, ssAttrs["rsyslog-ca-key"])
+}
+
+func (s *MigrationSuite) TestImportExistingEnvironment(c *gc.C) {
+	desc, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, _, err = s.State.Import(desc)
+	c.Assert(err, gc.ErrorMatches, `cannot import environment: environment ".*" already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *MigrationSuite) TestImportUnknownOwner(c *gc.C) {
+	desc, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	desc.Owner = names.NewLocalUserTag("nobody").Canonical()
+
+	_, _, err = s.State.Import(desc)
+	c.Assert(err, gc.ErrorMatches, `cannot import environment: user "nobody" not found`)
+}
+
+func (s *MigrationSuite) TestImportBadCharmArchive(c *gc.C) {
+	s.addCharm(c, "dummy")
+	desc, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	desc.Charms[0].SHA256 = "0123"
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	desc.Config["uuid"] = uuid.String()
+
+	_, _, err = s.State.Import(desc)
+	c.Assert(err, gc.ErrorMatches, `cannot import environment: charm "local:quantal/dummy-[0-9]+" archive has SHA256 [0-9a-f]+, expected 0123`)
+	_, err = s.State.GetEnvironment(names.NewEnvironTag(uuid.String()))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+)
+
+// EndpointNetworkInfo describes a network interface of a unit's machine,
+// with the addresses it has in the space bound to one of the unit's
+// endpoints.
+type EndpointNetworkInfo struct {
+	// MACAddress and InterfaceName identify the interface. They are
+	// empty if the interface holding the addresses is not known.
+	MACAddress    string
+	InterfaceName string
+	Addresses     []InterfaceAddress
+}
+
+// InterfaceAddress is an address of a network interface, along with the
+// CIDR of the subnet containing it, which is empty if not known.
+type InterfaceAddress struct {
+	Address string
+	CIDR    string
+}
+
+// NetworkInfo returns the network interfaces of the unit's machine that
+// have addresses in the space the unit's named endpoint is bound to. If
+// the endpoint is not bound, or the machine has no addresses in the
+// bound space, the unit's private address is returned instead.
+func (u *Unit) NetworkInfo(endpoint string) ([]EndpointNetworkInfo, error) {
+	service, err := u.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := service.Endpoint(endpoint); err != nil {
+		return nil, errors.NotFoundf("endpoint %q", endpoint)
+	}
+	m, err := u.machine()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if spaceName := service.EndpointBinding(endpoint); spaceName != "" {
+		infos, err := m.spaceNetworkInfo(spaceName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(infos) > 0 {
+			return infos, nil
+		}
+	}
+	addr, err := m.PrivateAddress()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return []EndpointNetworkInfo{{
+		Addresses: []InterfaceAddress{{Address: addr.Value}},
+	}}, nil
+}
+
+// spaceNetworkInfo returns the machine's addresses in the named space,
+// grouped by the network interfaces they are allocated to.
+func (m *Machine) spaceNetworkInfo(spaceName string) ([]EndpointNetworkInfo, error) {
+	addrs, err := m.spaceAddresses(spaceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ifaces, err := m.NetworkInterfaces()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	interfaceNames := make(map[string]string, len(ifaces))
+	for _, iface := range ifaces {
+		interfaceNames[iface.MACAddress()] = iface.InterfaceName()
+	}
+	var infos []EndpointNetworkInfo
+	byMAC := make(map[string]int)
+	for _, addr := range addrs {
+		var macAddress string
+		ipAddr, err := ipAddress(m.st, addr.Value)
+		if err == nil && ipAddr.MachineId() == m.doc.Id {
+			macAddress = ipAddr.MACAddress()
+		} else if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		i, ok := byMAC[macAddress]
+		if !ok {
+			i = len(infos)
+			byMAC[macAddress] = i
+			infos = append(infos, EndpointNetworkInfo{
+				MACAddress:    macAddress,
+				InterfaceName: interfaceNames[macAddress],
+			})
+		}
+		infos[i].Addresses = append(infos[i].Addresses, InterfaceAddress{
+			Address: addr.Value,
+			CIDR:    addr.CIDR,
+		})
+	}
+	return infos, nil
+}
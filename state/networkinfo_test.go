@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type NetworkInfoSuite struct {
+	ConnSuite
+	wordpress *state.Service
+	unit      *state.Unit
+}
+
+var _ = gc.Suite(&NetworkInfoSuite{})
+
+func (s *NetworkInfoSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	for name, cidr := range map[string]string{
+		"internal": "10.0.0.0/24",
+		"public":   "192.168.1.0/24",
+	} {
+		_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: cidr})
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = s.State.AddSpace(name, []string{cidr}, false)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProviderAddresses(
+		network.NewAddress("192.168.1.5"),
+		network.NewAddress("10.0.0.5"),
+		network.NewAddress("10.0.0.6"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit = unit
+}
+
+func (s *NetworkInfoSuite) TestNetworkInfoBound(c *gc.C) {
+	err := s.wordpress.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := s.unit.NetworkInfo("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, []state.EndpointNetworkInfo{{
+		Addresses: []state.InterfaceAddress{
+			{Address: "10.0.0.5", CIDR: "10.0.0.0/24"},
+			{Address: "10.0.0.6", CIDR: "10.0.0.0/24"},
+		},
+	}})
+}
+
+func (s *NetworkInfoSuite) TestNetworkInfoUnbound(c *gc.C) {
+	info, err := s.unit.NetworkInfo("url")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, []state.EndpointNetworkInfo{{
+		Addresses: []state.InterfaceAddress{{Address: "192.168.1.5"}},
+	}})
+}
+
+func (s *NetworkInfoSuite) TestNetworkInfoUnknownEndpoint(c *gc.C) {
+	_, err := s.unit.NetworkInfo("foo")
+	c.Assert(err, gc.ErrorMatches, `endpoint "foo" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
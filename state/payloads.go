@@ -0,0 +1,289 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+const (
+	// PayloadStarting means the payload is starting up.
+	PayloadStarting = "starting"
+
+	// PayloadRunning means the payload is running. New payloads are
+	// registered as running unless told otherwise.
+	PayloadRunning = "running"
+
+	// PayloadStopping means the payload is shutting down.
+	PayloadStopping = "stopping"
+
+	// PayloadStopped means the payload is no longer running.
+	PayloadStopped = "stopped"
+)
+
+var validPayloadStatuses = []string{
+	PayloadStarting,
+	PayloadRunning,
+	PayloadStopping,
+	PayloadStopped,
+}
+
+// Payload describes a workload payload, such as a container or a
+// long-running process, that a unit's charm has registered with juju.
+type Payload struct {
+	// Class is the name of the kind of payload, as defined by the charm.
+	Class string
+
+	// Type is the technology used to run the payload, e.g. "docker".
+	Type string
+
+	// ID uniquely identifies the payload within its class for the unit.
+	ID string
+
+	// Status is one of the Payload* status values.
+	Status string
+
+	// Labels holds any free-form labels the charm attached.
+	Labels []string
+
+	// Unit is the name of the unit that registered the payload.
+	Unit string
+
+	// Machine is the id of the machine hosting the unit.
+	Machine string
+}
+
+// Validate returns an error if the payload is not valid.
+func (p Payload) Validate() error {
+	if p.Class == "" {
+		return errors.NewNotValid(nil, "missing payload class")
+	}
+	if p.Type == "" {
+		return errors.NewNotValid(nil, "missing payload type")
+	}
+	if p.ID == "" {
+		return errors.NewNotValid(nil, "missing payload id")
+	}
+	return validatePayloadStatus(p.Status)
+}
+
+func validatePayloadStatus(status string) error {
+	for _, valid := range validPayloadStatuses {
+		if status == valid {
+			return nil
+		}
+	}
+	return errors.NotValidf("payload status %q", status)
+}
+
+// payloadDoc records a payload registered by a unit.
+type payloadDoc struct {
+	DocID     string   `bson:"_id"`
+	EnvUUID   string   `bson:"env-uuid"`
+	UnitID    string   `bson:"unitid"`
+	MachineID string   `bson:"machineid"`
+	Class     string   `bson:"class"`
+	Type      string   `bson:"type"`
+	RawID     string   `bson:"rawid"`
+	Status    string   `bson:"status"`
+	Labels    []string `bson:"labels,omitempty"`
+}
+
+func (doc payloadDoc) payload() Payload {
+	return Payload{
+		Class:   doc.Class,
+		Type:    doc.Type,
+		ID:      doc.RawID,
+		Status:  doc.Status,
+		Labels:  doc.Labels,
+		Unit:    doc.UnitID,
+		Machine: doc.MachineID,
+	}
+}
+
+// payloadKey returns the key of the unit's payload with the given class
+// and id.
+func payloadKey(unitName, class, id string) string {
+	return fmt.Sprintf("u#%s#payload#%s#%s", unitName, class, id)
+}
+
+// RegisterPayload records that the unit is running the given payload.
+// Registering a payload with the same class and id as an existing one
+// replaces it. If the payload's status is empty, it is registered as
+// running.
+func (u *Unit) RegisterPayload(p Payload) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot register payload for unit %q", u)
+	if p.Status == "" {
+		p.Status = PayloadRunning
+	}
+	if err := p.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	machineId, err := u.AssignedMachineId()
+	if errors.IsNotAssigned(err) {
+		machineId = ""
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	docID := u.st.docID(payloadKey(u.doc.Name, p.Class, p.ID))
+	doc := &payloadDoc{
+		DocID:     docID,
+		EnvUUID:   u.st.EnvironUUID(),
+		UnitID:    u.doc.Name,
+		MachineID: machineId,
+		Class:     p.Class,
+		Type:      p.Type,
+		RawID:     p.ID,
+		Status:    p.Status,
+		Labels:    p.Labels,
+	}
+	payloads, closer := u.st.getCollection(payloadsC)
+	defer closer()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		count, err := payloads.FindId(docID).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if count == 0 {
+			return append(ops, txn.Op{
+				C:      payloadsC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+				Insert: doc,
+			}), nil
+		}
+		return append(ops, txn.Op{
+			C:      payloadsC,
+			Id:     docID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"machineid", doc.MachineID},
+				{"type", doc.Type},
+				{"status", doc.Status},
+				{"labels", doc.Labels},
+			}}},
+		}), nil
+	}
+	return u.st.run(buildTxn)
+}
+
+// UnregisterPayload removes the unit's payload with the given class and
+// id. It returns an error satisfying errors.IsNotFound if there is no
+// such payload.
+func (u *Unit) UnregisterPayload(class, id string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot unregister payload for unit %q", u)
+	docID := u.st.docID(payloadKey(u.doc.Name, class, id))
+	ops := []txn.Op{{
+		C:      payloadsC,
+		Id:     docID,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := u.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("payload %s/%s", class, id)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// SetPayloadStatus updates the status of the unit's payload with the
+// given class and id. It returns an error satisfying errors.IsNotFound if
+// there is no such payload.
+func (u *Unit) SetPayloadStatus(class, id, status string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set status of payload for unit %q", u)
+	if err := validatePayloadStatus(status); err != nil {
+		return errors.Trace(err)
+	}
+	docID := u.st.docID(payloadKey(u.doc.Name, class, id))
+	ops := []txn.Op{{
+		C:      payloadsC,
+		Id:     docID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"status", status}}}},
+	}}
+	if err := u.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("payload %s/%s", class, id)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// Payloads returns the payloads registered by the unit.
+func (u *Unit) Payloads() ([]Payload, error) {
+	payloads, err := u.st.payloads(bson.D{{"unitid", u.doc.Name}})
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get payloads for unit %q", u)
+	}
+	return payloads, nil
+}
+
+// AllPayloads returns the payloads registered by all units in the
+// environment.
+func (st *State) AllPayloads() ([]Payload, error) {
+	payloads, err := st.payloads(nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get payloads")
+	}
+	return payloads, nil
+}
+
+func (st *State) payloads(query bson.D) ([]Payload, error) {
+	coll, closer := st.getCollection(payloadsC)
+	defer closer()
+	var docs []payloadDoc
+	if err := coll.Find(query).Sort("unitid", "class", "rawid").All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	payloads := make([]Payload, len(docs))
+	for i, doc := range docs {
+		payloads[i] = doc.payload()
+	}
+	return payloads, nil
+}
+
+// removeUnitPayloads removes all payloads registered by the named unit.
+func (st *State) removeUnitPayloads(unitName string) error {
+	coll, closer := st.getCollection(payloadsC)
+	defer closer()
+	buildTxn := func(int) ([]txn.Op, error) {
+		var docs []payloadDoc
+		err := coll.Find(bson.D{{"unitid", unitName}}).Select(bson.D{{"_id", 1}}).All(&docs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(docs) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		ops := make([]txn.Op, len(docs))
+		for i, doc := range docs {
+			ops[i] = txn.Op{
+				C:      payloadsC,
+				Id:     doc.DocID,
+				Remove: true,
+			}
+		}
+		return ops, nil
+	}
+	return errors.Annotatef(st.run(buildTxn), "cannot remove payloads for unit %q", unitName)
+}
@@ -0,0 +1,178 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type PayloadsSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&PayloadsSuite{})
+
+func (s *PayloadsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit = unit
+}
+
+func (s *PayloadsSuite) TestRegisterPayload(c *gc.C) {
+	err := s.unit.RegisterPayload(state.Payload{
+		Class:  "webapp",
+		Type:   "docker",
+		ID:     "abc123",
+		Labels: []string{"frontend"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.RegisterPayload(state.Payload{
+		Class:  "cache",
+		Type:   "docker",
+		ID:     "def456",
+		Status: state.PayloadStarting,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	payloads, err := s.unit.Payloads()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payloads, jc.DeepEquals, []state.Payload{{
+		Class:   "cache",
+		Type:    "docker",
+		ID:      "def456",
+		Status:  "starting",
+		Unit:    "wordpress/0",
+		Machine: "0",
+	}, {
+		Class:   "webapp",
+		Type:    "docker",
+		ID:      "abc123",
+		Status:  "running",
+		Labels:  []string{"frontend"},
+		Unit:    "wordpress/0",
+		Machine: "0",
+	}})
+}
+
+func (s *PayloadsSuite) TestRegisterPayloadReplaces(c *gc.C) {
+	err := s.unit.RegisterPayload(state.Payload{Class: "webapp", Type: "docker", ID: "abc123"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.RegisterPayload(state.Payload{
+		Class:  "webapp",
+		Type:   "rkt",
+		ID:     "abc123",
+		Labels: []string{"v2"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	payloads, err := s.unit.Payloads()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payloads, gc.HasLen, 1)
+	c.Assert(payloads[0].Type, gc.Equals, "rkt")
+	c.Assert(payloads[0].Labels, jc.DeepEquals, []string{"v2"})
+}
+
+func (s *PayloadsSuite) TestRegisterPayloadInvalid(c *gc.C) {
+	err := s.unit.RegisterPayload(state.Payload{Type: "docker", ID: "abc123"})
+	c.Assert(err, gc.ErrorMatches, `cannot register payload for unit "wordpress/0": missing payload class`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotValid)
+
+	err = s.unit.RegisterPayload(state.Payload{Class: "webapp", Type: "docker", ID: "abc123", Status: "dancing"})
+	c.Assert(err, gc.ErrorMatches, `cannot register payload for unit "wordpress/0": payload status "dancing" not valid`)
+}
+
+func (s *PayloadsSuite) TestRegisterPayloadDeadUnit(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.RegisterPayload(state.Payload{Class: "webapp", Type: "docker", ID: "abc123"})
+	c.Assert(err, gc.ErrorMatches, `cannot register payload for unit "wordpress/0": not found or not alive`)
+}
+
+func (s *PayloadsSuite) TestSetPayloadStatus(c *gc.C) {
+	err := s.unit.RegisterPayload(state.Payload{Class: "webapp", Type: "docker", ID: "abc123"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.SetPayloadStatus("webapp", "abc123", state.PayloadStopping)
+	c.Assert(err, jc.ErrorIsNil)
+	payloads, err := s.unit.Payloads()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payloads[0].Status, gc.Equals, "stopping")
+
+	err = s.unit.SetPayloadStatus("webapp", "abc123", "dancing")
+	c.Assert(err, gc.ErrorMatches, `.*payload status "dancing" not valid`)
+
+	err = s.unit.SetPayloadStatus("webapp", "missing", state.PayloadStopped)
+	c.Assert(err, gc.ErrorMatches, `cannot set status of payload for unit "wordpress/0": payload webapp/missing not found`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *PayloadsSuite) TestUnregisterPayload(c *gc.C) {
+	err := s.unit.RegisterPayload(state.Payload{Class: "webapp", Type: "docker", ID: "abc123"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.UnregisterPayload("webapp", "abc123")
+	c.Assert(err, jc.ErrorIsNil)
+	payloads, err := s.unit.Payloads()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payloads, gc.HasLen, 0)
+
+	err = s.unit.UnregisterPayload("webapp", "abc123")
+	c.Assert(err, gc.ErrorMatches, `cannot unregister payload for unit "wordpress/0": payload webapp/abc123 not found`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *PayloadsSuite) TestAllPayloads(c *gc.C) {
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	other, err := mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.RegisterPayload(state.Payload{Class: "webapp", Type: "docker", ID: "abc123"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.RegisterPayload(state.Payload{Class: "db", Type: "docker", ID: "def456"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	payloads, err := s.State.AllPayloads()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payloads, jc.DeepEquals, []state.Payload{{
+		Class:  "db",
+		Type:   "docker",
+		ID:     "def456",
+		Status: "running",
+		Unit:   "mysql/0",
+	}, {
+		Class:   "webapp",
+		Type:    "docker",
+		ID:      "abc123",
+		Status:  "running",
+		Unit:    "wordpress/0",
+		Machine: "0",
+	}})
+}
+
+func (s *PayloadsSuite) TestRemoveUnitRemovesPayloads(c *gc.C) {
+	err := s.unit.RegisterPayload(state.Payload{Class: "webapp", Type: "docker", ID: "abc123"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+
+	payloads, err := s.State.AllPayloads()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payloads, gc.HasLen, 0)
+}
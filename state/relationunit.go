@@ -40,8 +40,27 @@ func (ru *RelationUnit) Endpoint() Endpoint {
 	return ru.endpoint
 }
 
-// PrivateAddress returns the private address of the unit.
+// PrivateAddress returns the private address of the unit. If the unit's
+// endpoint is bound to a space, an address of the unit's machine in that
+// space is used in preference.
 func (ru *RelationUnit) PrivateAddress() (network.Address, error) {
+	service, err := ru.unit.Service()
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	if spaceName := service.EndpointBinding(ru.endpoint.Name); spaceName != "" {
+		m, err := ru.unit.machine()
+		if err != nil {
+			return network.Address{}, errors.Trace(err)
+		}
+		addr, err := m.spaceAddress(spaceName)
+		if err == nil {
+			return addr, nil
+		} else if !errors.IsNotFound(err) {
+			return network.Address{}, errors.Trace(err)
+		}
+		logger.Warningf("unit %q has no address in space %q bound to endpoint %q", ru.unit, spaceName, ru.endpoint.Name)
+	}
 	return ru.unit.PrivateAddress()
 }
 
@@ -0,0 +1,228 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	statestorage "github.com/juju/juju/state/storage"
+)
+
+// Resource describes the current revision of a charm resource attached
+// to a service.
+type Resource struct {
+	// Service is the name of the service the resource is attached to.
+	Service string
+
+	// Name is the name of the resource, as declared in the charm
+	// metadata.
+	Name string
+
+	// Path is the file name the resource is given on units.
+	Path string
+
+	// Revision is incremented each time the resource is attached.
+	Revision int
+
+	// Fingerprint is the hex-encoded SHA-384 hash of the resource data.
+	Fingerprint string
+
+	// Size is the size of the resource data in bytes.
+	Size int64
+
+	// StoragePath is the path of the resource data in environment
+	// storage.
+	StoragePath string
+
+	// Username is the name of the user who attached the resource.
+	Username string
+
+	// Timestamp is when the resource was attached.
+	Timestamp time.Time
+}
+
+// resourceDoc records the current revision of a service's resource.
+type resourceDoc struct {
+	DocID       string    `bson:"_id"`
+	EnvUUID     string    `bson:"env-uuid"`
+	Service     string    `bson:"service"`
+	Name        string    `bson:"name"`
+	Path        string    `bson:"path"`
+	Revision    int       `bson:"revision"`
+	Fingerprint string    `bson:"fingerprint"`
+	Size        int64     `bson:"size"`
+	StoragePath string    `bson:"storagepath"`
+	Username    string    `bson:"username"`
+	Timestamp   time.Time `bson:"timestamp"`
+}
+
+func (doc resourceDoc) resource() Resource {
+	return Resource{
+		Service:     doc.Service,
+		Name:        doc.Name,
+		Path:        doc.Path,
+		Revision:    doc.Revision,
+		Fingerprint: doc.Fingerprint,
+		Size:        doc.Size,
+		StoragePath: doc.StoragePath,
+		Username:    doc.Username,
+		Timestamp:   doc.Timestamp,
+	}
+}
+
+// serviceResourceKey returns the key of the named resource of the
+// service.
+func serviceResourceKey(serviceName, name string) string {
+	return fmt.Sprintf("s#%s#resource#%s", serviceName, name)
+}
+
+// SetResource records a new revision of the service's resource, whose
+// data has already been written to res.StoragePath in environment
+// storage. The service's charm modified version is incremented, so that
+// its units run the upgrade-charm hook. The Service, Revision and
+// Timestamp fields of res are ignored; the recorded resource is
+// returned.
+func (s *Service) SetResource(res Resource) (_ Resource, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set resource %q of service %q", res.Name, s)
+	if res.Name == "" {
+		return Resource{}, errors.NewNotValid(nil, "missing resource name")
+	}
+	if res.StoragePath == "" {
+		return Resource{}, errors.NewNotValid(nil, "missing storage path")
+	}
+	docID := s.st.docID(serviceResourceKey(s.doc.Name, res.Name))
+	doc := resourceDoc{
+		DocID:       docID,
+		EnvUUID:     s.st.EnvironUUID(),
+		Service:     s.doc.Name,
+		Name:        res.Name,
+		Path:        res.Path,
+		Fingerprint: res.Fingerprint,
+		Size:        res.Size,
+		StoragePath: res.StoragePath,
+		Username:    res.Username,
+		Timestamp:   time.Now().UTC(),
+	}
+	resources, closer := s.st.getCollection(resourcesC)
+	defer closer()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if s.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		ops := []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$inc", bson.D{{"charmmodifiedversion", 1}}}},
+		}}
+		var existing resourceDoc
+		err := resources.FindId(docID).One(&existing)
+		if err == mgo.ErrNotFound {
+			doc.Revision = 1
+			return append(ops, txn.Op{
+				C:      resourcesC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		doc.Revision = existing.Revision + 1
+		return append(ops, txn.Op{
+			C:      resourcesC,
+			Id:     docID,
+			Assert: bson.D{{"revision", existing.Revision}},
+			Update: bson.D{{"$set", bson.D{
+				{"path", doc.Path},
+				{"revision", doc.Revision},
+				{"fingerprint", doc.Fingerprint},
+				{"size", doc.Size},
+				{"storagepath", doc.StoragePath},
+				{"username", doc.Username},
+				{"timestamp", doc.Timestamp},
+			}}},
+		}), nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return Resource{}, errors.Trace(err)
+	}
+	s.doc.CharmModifiedVersion++
+	return doc.resource(), nil
+}
+
+// Resource returns the current revision of the service's named
+// resource. It returns an error satisfying errors.IsNotFound if the
+// resource has not been attached.
+func (s *Service) Resource(name string) (Resource, error) {
+	resources, closer := s.st.getCollection(resourcesC)
+	defer closer()
+	var doc resourceDoc
+	err := resources.FindId(serviceResourceKey(s.doc.Name, name)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return Resource{}, errors.NotFoundf("resource %q of service %q", name, s)
+	} else if err != nil {
+		return Resource{}, errors.Annotatef(err, "cannot get resource %q of service %q", name, s)
+	}
+	return doc.resource(), nil
+}
+
+// Resources returns the resources attached to the service, ordered by
+// name.
+func (s *Service) Resources() ([]Resource, error) {
+	resources, closer := s.st.getCollection(resourcesC)
+	defer closer()
+	var docs []resourceDoc
+	err := resources.Find(bson.D{{"service", s.doc.Name}}).Sort("name").All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get resources of service %q", s)
+	}
+	result := make([]Resource, len(docs))
+	for i, doc := range docs {
+		result[i] = doc.resource()
+	}
+	return result, nil
+}
+
+// removeResourcesOps returns the operations that remove the resources
+// attached to the service, along with cleanups that remove their data
+// from environment storage once the resources are gone.
+func (s *Service) removeResourcesOps() ([]txn.Op, error) {
+	resources, closer := s.st.getCollection(resourcesC)
+	defer closer()
+	var docs []resourceDoc
+	if err := resources.Find(bson.D{{"service", s.doc.Name}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get resources of service %q", s)
+	}
+	var ops []txn.Op
+	for _, doc := range docs {
+		ops = append(ops, txn.Op{
+			C:      resourcesC,
+			Id:     doc.DocID,
+			Remove: true,
+		}, s.st.newCleanupOp(cleanupRemovedResourceData, doc.StoragePath))
+	}
+	return ops, nil
+}
+
+// cleanupRemovedResourceData removes the data of a removed resource from
+// environment storage.
+func (st *State) cleanupRemovedResourceData(storagePath string) error {
+	stor := statestorage.NewStorage(st.EnvironUUID(), st.MongoSession())
+	if err := stor.Remove(storagePath); err != nil && !errors.IsNotFound(err) {
+		return errors.Annotatef(err, "cannot remove resource data %q", storagePath)
+	}
+	return nil
+}
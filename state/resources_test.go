@@ -0,0 +1,164 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statestorage "github.com/juju/juju/state/storage"
+)
+
+type ResourcesSuite struct {
+	ConnSuite
+	wordpress *state.Service
+}
+
+var _ = gc.Suite(&ResourcesSuite{})
+
+func (s *ResourcesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *ResourcesSuite) TestSetResource(c *gc.C) {
+	c.Assert(s.wordpress.CharmModifiedVersion(), gc.Equals, 0)
+
+	res, err := s.wordpress.SetResource(state.Resource{
+		Name:        "software",
+		Path:        "wordpress.tgz",
+		Fingerprint: "abc",
+		Size:        3,
+		StoragePath: "resources/wordpress/software-1",
+		Username:    "bob",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Service, gc.Equals, "wordpress")
+	c.Assert(res.Revision, gc.Equals, 1)
+	c.Assert(res.Timestamp.IsZero(), jc.IsFalse)
+
+	res, err = s.wordpress.SetResource(state.Resource{
+		Name:        "software",
+		Path:        "wordpress.tgz",
+		Fingerprint: "def",
+		Size:        4,
+		StoragePath: "resources/wordpress/software-2",
+		Username:    "bob",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Revision, gc.Equals, 2)
+
+	err = s.wordpress.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.wordpress.CharmModifiedVersion(), gc.Equals, 2)
+
+	res, err = s.wordpress.Resource("software")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Timestamp.IsZero(), jc.IsFalse)
+	res.Timestamp = time.Time{}
+	c.Assert(res, jc.DeepEquals, state.Resource{
+		Service:     "wordpress",
+		Name:        "software",
+		Path:        "wordpress.tgz",
+		Revision:    2,
+		Fingerprint: "def",
+		Size:        4,
+		StoragePath: "resources/wordpress/software-2",
+		Username:    "bob",
+	})
+}
+
+func (s *ResourcesSuite) TestSetResourceInvalid(c *gc.C) {
+	_, err := s.wordpress.SetResource(state.Resource{StoragePath: "resources/wordpress/x"})
+	c.Assert(err, gc.ErrorMatches, `cannot set resource "" of service "wordpress": missing resource name`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ResourcesSuite) TestSetResourceDyingService(c *gc.C) {
+	_, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.wordpress.SetResource(state.Resource{
+		Name:        "software",
+		StoragePath: "resources/wordpress/software-1",
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot set resource "software" of service "wordpress": not found or not alive`)
+}
+
+func (s *ResourcesSuite) TestResourceNotFound(c *gc.C) {
+	_, err := s.wordpress.Resource("software")
+	c.Assert(err, gc.ErrorMatches, `resource "software" of service "wordpress" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ResourcesSuite) TestResources(c *gc.C) {
+	for _, name := range []string{"theme", "software"} {
+		_, err := s.wordpress.SetResource(state.Resource{
+			Name:        name,
+			Path:        name + ".tgz",
+			StoragePath: "resources/wordpress/" + name,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	resources, err := s.wordpress.Resources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 2)
+	c.Assert(resources[0].Name, gc.Equals, "software")
+	c.Assert(resources[1].Name, gc.Equals, "theme")
+}
+
+func (s *ResourcesSuite) TestRemoveServiceRemovesResources(c *gc.C) {
+	stor := statestorage.NewStorage(s.State.EnvironUUID(), s.State.MongoSession())
+	err := stor.Put("resources/wordpress/software", strings.NewReader("abc"), 3)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.wordpress.SetResource(state.Resource{
+		Name:        "software",
+		Path:        "wordpress.tgz",
+		StoragePath: "resources/wordpress/software",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, _, err = stor.Get("resources/wordpress/software")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	resources, err := wordpress.Resources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 0)
+}
+
+func (s *ResourcesSuite) TestReAddedServiceHasNoResources(c *gc.C) {
+	stor := statestorage.NewStorage(s.State.EnvironUUID(), s.State.MongoSession())
+	err := stor.Put("resources/wordpress/software", strings.NewReader("abc"), 3)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.wordpress.SetResource(state.Resource{
+		Name:        "software",
+		Path:        "wordpress.tgz",
+		StoragePath: "resources/wordpress/software",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The service is re-added before its cleanup runs.
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err = wordpress.Resource("software")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = stor.Get("resources/wordpress/software")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -47,6 +47,19 @@ type serviceDoc struct {
 	OwnerTag          string     `bson:"ownertag"`
 	TxnRevno          int64      `bson:"txn-revno"`
 	MetricCredentials []byte     `bson:"metric-credentials"`
+
+	// ActionLimits holds the maximum number of units that may run
+	// each limited action at once, by action name.
+	ActionLimits map[string]int `bson:"actionlimits,omitempty"`
+
+	// EndpointBindings holds the names of the spaces the service's
+	// relation endpoints are bound to, by endpoint name.
+	EndpointBindings map[string]string `bson:"endpointbindings,omitempty"`
+
+	// CharmModifiedVersion is incremented whenever a resource is
+	// attached to the service, prompting its units to run the
+	// upgrade-charm hook.
+	CharmModifiedVersion int `bson:"charmmodifiedversion"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -170,7 +183,11 @@ func (s *Service) destroyOps() ([]txn.Op, error) {
 	// removed, the service can also be removed.
 	if s.doc.UnitCount == 0 && s.doc.RelationCount == removeCount {
 		hasLastRefs := bson.D{{"life", Alive}, {"unitcount", 0}, {"relationcount", removeCount}}
-		return append(ops, s.removeOps(hasLastRefs)...), nil
+		removeOps, err := s.removeOps(hasLastRefs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, removeOps...), nil
 	}
 	// In all other cases, service removal will be handled as a consequence
 	// of the removal of the last unit or relation referencing it. If any
@@ -208,7 +225,21 @@ func (s *Service) destroyOps() ([]txn.Op, error) {
 
 // removeOps returns the operations required to remove the service. Supplied
 // asserts will be included in the operation on the service document.
-func (s *Service) removeOps(asserts bson.D) []txn.Op {
+func (s *Service) removeOps(asserts bson.D) ([]txn.Op, error) {
+	if s.doc.CharmModifiedVersion == 0 {
+		// No resources have been attached, so there's nothing to
+		// clean up; but make sure none are attached before the
+		// service is removed.
+		asserts = append(bson.D{{
+			"charmmodifiedversion", bson.D{{"$not", bson.D{{"$gt", 0}}}},
+		}}, asserts...)
+	} else {
+		// Attaching a resource increments the version, so this
+		// ensures none are attached after they are read below.
+		asserts = append(bson.D{{
+			"charmmodifiedversion", s.doc.CharmModifiedVersion,
+		}}, asserts...)
+	}
 	settingsDocID := s.st.docID(s.settingsKey())
 	ops := []txn.Op{
 		{
@@ -232,7 +263,21 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		removeLeadershipSettingsOp(s.Tag().Id()),
 		removeStatusOp(s.st, s.globalKey()),
 	}
-	return ops
+	for actionName := range s.doc.ActionLimits {
+		ops = append(ops, txn.Op{
+			C:      actionLimitCountsC,
+			Id:     s.st.docID(actionLimitCountID(s.doc.Name, actionName)),
+			Remove: true,
+		})
+	}
+	if s.doc.CharmModifiedVersion > 0 {
+		resourceOps, err := s.removeResourcesOps()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, resourceOps...)
+	}
+	return ops, nil
 }
 
 // IsExposed returns whether this service is exposed. The explicitly open
@@ -290,6 +335,12 @@ func (s *Service) CharmURL() (curl *charm.URL, force bool) {
 	return s.doc.CharmURL, s.doc.ForceCharm
 }
 
+// CharmModifiedVersion increases whenever the service's resources
+// change, without its charm URL changing.
+func (s *Service) CharmModifiedVersion() int {
+	return s.doc.CharmModifiedVersion
+}
+
 // Endpoints returns the service's currently available relation endpoints.
 func (s *Service) Endpoints() (eps []Endpoint, err error) {
 	ch, _, err := s.Charm()
@@ -504,6 +555,9 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 
 	// Build the transaction.
 	var ops []txn.Op
+	update := bson.D{{"$set", bson.D{{"charmurl", ch.URL()}, {"forcecharm", force}}}}
+	// Drop the bindings of any endpoints the new charm no longer has.
+	update = append(update, s.staleEndpointBindings(ch.Meta())...)
 	differentCharm := bson.D{{"charmurl", bson.D{{"$ne", ch.URL()}}}}
 	if oldSettings != nil {
 		// Old settings shouldn't change (when they exist).
@@ -519,7 +573,7 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 			C:      servicesC,
 			Id:     s.doc.DocID,
 			Assert: append(notDeadDoc, differentCharm...),
-			Update: bson.D{{"$set", bson.D{{"charmurl", ch.URL()}, {"forcecharm", force}}}},
+			Update: update,
 		},
 	}...)
 	// Add any extra peer relations that need creation.
@@ -867,7 +921,11 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 	}
 	if s.doc.Life == Dying && s.doc.RelationCount == 0 && s.doc.UnitCount == 1 {
 		hasLastRef := bson.D{{"life", Dying}, {"relationcount", 0}, {"unitcount", 1}}
-		return append(ops, s.removeOps(hasLastRef)...), nil
+		removeOps, err := s.removeOps(hasLastRef)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, removeOps...), nil
 	}
 	svcOp := txn.Op{
 		C:      servicesC,
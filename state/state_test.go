@@ -4507,6 +4507,32 @@ func (s *StateSuite) TestUnitsForInvalidId(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `"invalid-id" is not a valid machine id`)
 }
 
+func (s *StateSuite) TestResetCollections(c *gc.C) {
+	history := s.Session.DB("juju").C("statuseshistory")
+	err := history.Insert(bson.M{"_id": "foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	logs := s.Session.DB("logs").C("logs")
+	err = logs.DropCollection()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = state.ResetCollections(s.Session, []string{"juju.statuseshistory", "logs.logs"})
+	c.Assert(err, jc.ErrorIsNil)
+	count, err := history.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(count, gc.Equals, 0)
+	for _, coll := range []*mgo.Collection{history, logs} {
+		indexes, err := coll.Indexes()
+		c.Assert(err, jc.ErrorIsNil)
+		// Each has the _id index and at least one other.
+		c.Check(len(indexes) > 1, jc.IsTrue)
+	}
+
+	err = state.ResetCollections(s.Session, []string{"juju.unknown"})
+	c.Check(err, gc.ErrorMatches, `collection "juju.unknown" not found`)
+	err = state.ResetCollections(s.Session, []string{"admin.system.users"})
+	c.Check(err, gc.ErrorMatches, `collection name "admin.system.users" not valid`)
+}
+
 type SetAdminMongoPasswordSuite struct {
 	testing.BaseSuite
 }
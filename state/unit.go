@@ -99,6 +99,7 @@ type unitDoc struct {
 	Life                   Life
 	TxnRevno               int64 `bson:"txn-revno"`
 	PasswordHash           string
+	WorkloadVersion        string `bson:"workloadversion,omitempty"`
 
 	// TODO(mue) No longer actively used, only in upgrades.go.
 	// To be removed later.
@@ -228,6 +229,57 @@ func (u *Unit) SetAgentVersion(v version.Binary) (err error) {
 	return nil
 }
 
+// WorkloadVersion returns the version of the running workload set by
+// the unit's charm, or "" if it has not been set.
+func (u *Unit) WorkloadVersion() string {
+	return u.doc.WorkloadVersion
+}
+
+// SetWorkloadVersion records the version of the workload the unit's
+// charm is running, as reported by the charm itself. The unit document
+// is left untouched if it already records the version, so that charms
+// reporting it on every hook do not wake the unit's watchers.
+func (u *Unit) SetWorkloadVersion(version string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set workload version for unit %q", u)
+	db, closer := u.st.newDB()
+	defer closer()
+	units, closer := db.GetCollection(unitsC)
+	defer closer()
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if notDead, err := isNotDeadWithSession(units, u.doc.DocID); err != nil {
+				return nil, errors.Trace(err)
+			} else if !notDead {
+				return nil, ErrDead
+			}
+		}
+		sel := bson.D{{"_id", u.doc.DocID}, {"workloadversion", version}}
+		if version == "" {
+			// The field is omitted when empty.
+			sel = bson.D{{"_id", u.doc.DocID}, {"workloadversion", bson.D{{"$in", []interface{}{nil, ""}}}}}
+		}
+		if count, err := units.Find(sel).Count(); err != nil {
+			return nil, errors.Trace(err)
+		} else if count == 1 {
+			// Already set
+			return nil, jujutxn.ErrNoOperations
+		}
+		differentVersion := bson.D{{"workloadversion", bson.D{{"$ne", version}}}}
+		return []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: append(notDeadDoc, differentVersion...),
+			Update: bson.D{{"$set", bson.D{{"workloadversion", version}}}},
+		}}, nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		return err
+	}
+	u.doc.WorkloadVersion = version
+	return nil
+}
+
 // SetPassword sets the password for the machine's agent.
 func (u *Unit) SetPassword(password string) error {
 	if len(password) < utils.MinAgentPasswordLength {
@@ -1158,6 +1210,9 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	if u.doc.Principal != "" {
 		return nil, fmt.Errorf("unit is a subordinate")
 	}
+	if m.doc.UpgradeSeries != nil {
+		return nil, errUpgradingSeries(m)
+	}
 	if unused && !m.doc.Clean {
 		return nil, inUseErr
 	}
@@ -1206,7 +1261,8 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 			{{"machineid", m.Id()}},
 		}},
 	}...)
-	massert := isAliveDoc
+	massert := append(bson.D{}, isAliveDoc...)
+	massert = append(massert, noUpgradeSeriesDoc...)
 	if unused {
 		massert = append(massert, bson.D{{"clean", bson.D{{"$ne", false}}}}...)
 	}
@@ -1970,6 +2026,21 @@ type ActionSpecsByName map[string]charm.ActionSpec
 // this Unit, and returns its ID.  Note that the use of spec.InsertDefaults
 // mutates payload.
 func (u *Unit) AddAction(name string, payload map[string]interface{}) (*Action, error) {
+	return u.AddActionWithOptions(name, payload, ActionOptions{})
+}
+
+// AddOperationAction adds a new Action of type name and using arguments
+// payload to this Unit, as part of the operation with the given id.
+func (u *Unit) AddOperationAction(operation, name string, payload map[string]interface{}) (*Action, error) {
+	if operation == "" {
+		return nil, errors.New("no operation id given")
+	}
+	return u.AddActionWithOptions(name, payload, ActionOptions{Operation: operation})
+}
+
+// AddActionWithOptions adds a new Action of type name and using
+// arguments payload to this Unit, with the given options.
+func (u *Unit) AddActionWithOptions(name string, payload map[string]interface{}, opts ActionOptions) (*Action, error) {
 	if len(name) == 0 {
 		return nil, errors.New("no action name given")
 	}
@@ -1990,7 +2061,34 @@ func (u *Unit) AddAction(name string, payload map[string]interface{}) (*Action,
 	if err != nil {
 		return nil, err
 	}
-	return u.st.EnqueueAction(u.Tag(), name, payloadWithDefaults)
+	if opts.Timeout == 0 {
+		if opts.Timeout, err = actionSpecTimeout(spec); err != nil {
+			return nil, errors.Annotatef(err, "action %q", name)
+		}
+	}
+	return u.st.enqueueAction(u.Tag(), name, payloadWithDefaults, opts)
+}
+
+// actionSpecTimeout returns the default value of an action's "timeout"
+// parameter, as declared in the charm's actions.yaml, or zero if there
+// is none. Only string parameters are considered, so that charms using
+// "timeout" for a parameter of their own, such as a number of seconds,
+// are unaffected.
+func actionSpecTimeout(spec charm.ActionSpec) (time.Duration, error) {
+	properties, _ := spec.Params["properties"].(map[string]interface{})
+	param, _ := properties["timeout"].(map[string]interface{})
+	if param["type"] != "string" {
+		return 0, nil
+	}
+	s, ok := param["default"].(string)
+	if !ok {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(s)
+	if err != nil || timeout < 0 {
+		return 0, errors.Errorf("invalid timeout %q", s)
+	}
+	return timeout, nil
 }
 
 // ActionSpecs gets the ActionSpec map for the Unit's charm.
@@ -841,6 +841,45 @@ func (s *UnitSuite) TestTag(c *gc.C) {
 	c.Assert(s.unit.Tag().String(), gc.Equals, "unit-wordpress-0")
 }
 
+func (s *UnitSuite) TestSetWorkloadVersion(c *gc.C) {
+	c.Assert(s.unit.WorkloadVersion(), gc.Equals, "")
+
+	err := s.unit.SetWorkloadVersion("3.14")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.WorkloadVersion(), gc.Equals, "3.14")
+
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.WorkloadVersion(), gc.Equals, "3.14")
+}
+
+func (s *UnitSuite) TestSetWorkloadVersionUnchanged(c *gc.C) {
+	err := s.unit.SetWorkloadVersion("3.14")
+	c.Assert(err, jc.ErrorIsNil)
+	w := s.unit.Watch()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Setting the same version again leaves the unit alone.
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetWorkloadVersion("3.14")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	err = unit.SetWorkloadVersion("3.15")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *UnitSuite) TestSetWorkloadVersionDead(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetWorkloadVersion("3.14")
+	c.Assert(err, gc.ErrorMatches, `cannot set workload version for unit "wordpress/0": not found or dead`)
+}
+
 func (s *UnitSuite) TestSetPassword(c *gc.C) {
 	preventUnitDestroyRemove(c, s.unit)
 	testSetPassword(c, func() (state.Authenticator, error) {
@@ -98,7 +98,7 @@ func AddStateUsersAsEnvironUsers(st *State) error {
 
 		_, err := st.EnvironmentUser(uTag)
 		if err != nil && errors.IsNotFound(err) {
-			_, err = st.AddEnvironmentUser(uTag, uTag, "")
+			_, err = st.AddEnvironmentUser(uTag, uTag, "", EnvironmentWriteAccess)
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -2311,3 +2311,31 @@ func settingsDocNeedsMigration(doc bson.M) bool {
 	}
 	return true
 }
+
+// AddEnvUserAccess sets the access level of every environment user
+// that does not yet have one to write access, which matches the
+// access they had before access levels were introduced.
+func AddEnvUserAccess(st *State) error {
+	envUsers, closer := st.getRawCollection(envUsersC)
+	defer closer()
+
+	upgradesLogger.Debugf("adding access levels to environment users")
+	var docs []bson.M
+	err := envUsers.Find(bson.D{{"access", bson.D{{"$exists", false}}}}).Select(bson.D{{"_id", 1}}).All(&docs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var ops []txn.Op
+	for _, doc := range docs {
+		ops = append(ops, txn.Op{
+			C:      envUsersC,
+			Id:     doc["_id"],
+			Assert: bson.D{{"access", bson.D{{"$exists", false}}}},
+			Update: bson.D{{"$set", bson.D{{"access", EnvironmentWriteAccess}}}},
+		})
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	return errors.Trace(st.runRawTransaction(ops))
+}
@@ -1438,7 +1438,7 @@ func (s *upgradesSuite) setUpPortsMigration(c *gc.C) ([]*Machine, map[int][]*Uni
 	stateOwner, err := s.state.AddUser("bob", "notused", "notused", "bob")
 	c.Assert(err, jc.ErrorIsNil)
 	ownerTag := stateOwner.UserTag()
-	_, err = s.state.AddEnvironmentUser(ownerTag, ownerTag, "")
+	_, err = s.state.AddEnvironmentUser(ownerTag, ownerTag, "", EnvironmentWriteAccess)
 	c.Assert(err, jc.ErrorIsNil)
 
 	for i := range services {
@@ -1861,7 +1861,7 @@ func (s *upgradesSuite) setUpMeterStatusCreation(c *gc.C) []*Unit {
 	stateOwner, err := s.state.AddUser("bob", "notused", "notused", "bob")
 	c.Assert(err, jc.ErrorIsNil)
 	ownerTag := stateOwner.UserTag()
-	_, err = s.state.AddEnvironmentUser(ownerTag, ownerTag, "")
+	_, err = s.state.AddEnvironmentUser(ownerTag, ownerTag, "", EnvironmentWriteAccess)
 	c.Assert(err, jc.ErrorIsNil)
 
 	for i := 0; i < 3; i++ {
@@ -4435,3 +4435,36 @@ func (s *upgradesSuite) TestMigrateSettingsSchema(c *gc.C) {
 		c.Assert(docs, jc.DeepEquals, expected)
 	}
 }
+
+func (s *upgradesSuite) TestAddEnvUserAccess(c *gc.C) {
+	envUsers, closer := s.state.getRawCollection(envUsersC)
+	defer closer()
+
+	// Remove the access level from the environment owner, as it
+	// would be before the upgrade, and add a read-only user which
+	// should be left alone.
+	ownerId := s.state.docID(envUserID(s.owner))
+	err := envUsers.UpdateId(ownerId, bson.D{{"$unset", bson.D{{"access", 1}}}})
+	c.Assert(err, jc.ErrorIsNil)
+	readerTag := names.NewUserTag("reader@remote")
+	err = envUsers.Insert(bson.D{
+		{"_id", s.state.docID(envUserID(readerTag))},
+		{"env-uuid", s.state.EnvironUUID()},
+		{"user", readerTag.Canonical()},
+		{"access", "read"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Two rounds to check idempotency.
+	for i := 0; i < 2; i++ {
+		err = AddEnvUserAccess(s.state)
+		c.Assert(err, jc.ErrorIsNil)
+
+		owner, err := s.state.EnvironmentUser(s.owner)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(owner.Access(), gc.Equals, EnvironmentWriteAccess)
+		reader, err := s.state.EnvironmentUser(readerTag)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(reader.Access(), gc.Equals, EnvironmentReadAccess)
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// UpgradeSeriesStatus describes the progress of a machine series upgrade,
+// either for the machine as a whole or for one of its units.
+type UpgradeSeriesStatus string
+
+const (
+	// UpgradeSeriesNotStarted means no series upgrade is in progress.
+	UpgradeSeriesNotStarted UpgradeSeriesStatus = ""
+
+	// UpgradeSeriesPrepareStarted means the units are running their
+	// pre-series-upgrade hooks.
+	UpgradeSeriesPrepareStarted UpgradeSeriesStatus = "prepare started"
+
+	// UpgradeSeriesPrepareCompleted means the pre-series-upgrade hooks
+	// have run, and the machine's operating system can be upgraded.
+	UpgradeSeriesPrepareCompleted UpgradeSeriesStatus = "prepare completed"
+
+	// UpgradeSeriesCompleteStarted means the operating system has been
+	// upgraded, and the machine's agents and units are being updated
+	// to match.
+	UpgradeSeriesCompleteStarted UpgradeSeriesStatus = "complete started"
+
+	// UpgradeSeriesCompleted means the unit has run its
+	// post-series-upgrade hook.
+	UpgradeSeriesCompleted UpgradeSeriesStatus = "completed"
+)
+
+// upgradeSeriesDoc records the progress of a machine series upgrade. It
+// is stored in the machine document while the upgrade is in progress, and
+// blocks changes to the units and containers hosted by the machine.
+type upgradeSeriesDoc struct {
+	ToSeries string                         `bson:"to-series"`
+	Status   UpgradeSeriesStatus            `bson:"status"`
+	Units    map[string]UpgradeSeriesStatus `bson:"units"`
+}
+
+// noUpgradeSeriesDoc asserts that no series upgrade is in progress.
+var noUpgradeSeriesDoc = bson.D{{"upgradeseries", bson.D{{"$exists", false}}}}
+
+// errUpgradingSeries returns the error reported when changes are made to a
+// machine whose series is being upgraded.
+func errUpgradingSeries(m *Machine) error {
+	return errors.Errorf("machine %s is upgrading series", m)
+}
+
+// UpgradeSeriesStatus returns the status of the machine's series upgrade,
+// which is UpgradeSeriesNotStarted if no upgrade is in progress.
+func (m *Machine) UpgradeSeriesStatus() UpgradeSeriesStatus {
+	if m.doc.UpgradeSeries == nil {
+		return UpgradeSeriesNotStarted
+	}
+	return m.doc.UpgradeSeries.Status
+}
+
+// UpgradeSeriesTarget returns the series the machine is being upgraded
+// to, or "" if no upgrade is in progress.
+func (m *Machine) UpgradeSeriesTarget() string {
+	if m.doc.UpgradeSeries == nil {
+		return ""
+	}
+	return m.doc.UpgradeSeries.ToSeries
+}
+
+// UpgradeSeriesUnitStatus returns the status of the series upgrade for
+// the named unit hosted by the machine.
+func (m *Machine) UpgradeSeriesUnitStatus(unitName string) (UpgradeSeriesStatus, error) {
+	if m.doc.UpgradeSeries == nil {
+		return UpgradeSeriesNotStarted, nil
+	}
+	status, ok := m.doc.UpgradeSeries.Units[unitName]
+	if !ok {
+		return "", errors.NotFoundf("unit %q in series upgrade of machine %s", unitName, m)
+	}
+	return status, nil
+}
+
+// PrepareUpgradeSeries starts upgrading the machine to the given series.
+// Each unit hosted by the machine runs its pre-series-upgrade hook, and
+// no units or containers can be added to the machine until the upgrade
+// is complete.
+func (m *Machine) PrepareUpgradeSeries(series string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot prepare series upgrade of machine %s", m)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		if m.IsManager() {
+			return nil, errors.New("machine is a state server")
+		}
+		if m.doc.UpgradeSeries != nil {
+			return nil, errors.New("series upgrade already in progress")
+		}
+		if series == m.doc.Series {
+			return nil, errors.Errorf("machine is already running %s", series)
+		}
+		units, err := m.Units()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		doc := &upgradeSeriesDoc{
+			ToSeries: series,
+			Status:   UpgradeSeriesPrepareStarted,
+			Units:    make(map[string]UpgradeSeriesStatus, len(units)),
+		}
+		for _, unit := range units {
+			doc.Units[unit.Name()] = UpgradeSeriesPrepareStarted
+		}
+		if len(units) == 0 {
+			doc.Status = UpgradeSeriesPrepareCompleted
+		}
+		assert := append(bson.D{{"principals", m.doc.Principals}}, isAliveDoc...)
+		return []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: append(assert, noUpgradeSeriesDoc...),
+			Update: bson.D{{"$set", bson.D{{"upgradeseries", doc}}}},
+		}}, nil
+	}
+	return m.st.run(buildTxn)
+}
+
+// CompleteUpgradeSeries records that the machine's operating system has
+// been upgraded. The machine agent then updates the agent services and
+// the machine's series, after which the units run their
+// post-series-upgrade hooks.
+func (m *Machine) CompleteUpgradeSeries() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot complete series upgrade of machine %s", m)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		switch status := m.UpgradeSeriesStatus(); status {
+		case UpgradeSeriesPrepareCompleted:
+		case UpgradeSeriesNotStarted:
+			return nil, errors.New("series upgrade not prepared")
+		default:
+			return nil, errors.Errorf("series upgrade is %s", status)
+		}
+		return []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: bson.D{{"upgradeseries.status", UpgradeSeriesPrepareCompleted}},
+			Update: bson.D{{"$set", bson.D{{"upgradeseries.status", UpgradeSeriesCompleteStarted}}}},
+		}}, nil
+	}
+	return m.st.run(buildTxn)
+}
+
+// StartUnitUpgradeSeriesCompletion records that the machine agent has
+// updated its services for the new series. The machine and its units take
+// on the new series, and the units run their post-series-upgrade hooks.
+// If the machine hosts no units, the series upgrade is finished.
+func (m *Machine) StartUnitUpgradeSeriesCompletion() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot complete series upgrade of machine %s", m)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.UpgradeSeriesStatus() != UpgradeSeriesCompleteStarted {
+			return nil, errors.New("series upgrade completion not started")
+		}
+		doc := m.doc.UpgradeSeries
+		if doc.ToSeries == m.doc.Series {
+			return nil, jujutxn.ErrNoOperations
+		}
+		set := bson.D{{"series", doc.ToSeries}}
+		var ops []txn.Op
+		for unitName := range doc.Units {
+			set = append(set, bson.DocElem{"upgradeseries.units." + unitName, UpgradeSeriesCompleteStarted})
+			ops = append(ops, txn.Op{
+				C:      unitsC,
+				Id:     m.st.docID(unitName),
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"series", doc.ToSeries}}}},
+			})
+		}
+		update := bson.D{{"$set", set}}
+		if len(doc.Units) == 0 {
+			update = bson.D{
+				{"$set", bson.D{{"series", doc.ToSeries}}},
+				{"$unset", bson.D{{"upgradeseries", nil}}},
+			}
+		}
+		return append(ops, txn.Op{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: bson.D{{"upgradeseries.status", UpgradeSeriesCompleteStarted}},
+			Update: update,
+		}), nil
+	}
+	return m.st.run(buildTxn)
+}
+
+// SetUpgradeSeriesUnitStatus records the progress of the named unit through
+// the machine's series upgrade. Once every unit has run its
+// pre-series-upgrade hook, the machine is ready for its operating system
+// to be upgraded; once every unit has run its post-series-upgrade hook,
+// the series upgrade is finished.
+func (m *Machine) SetUpgradeSeriesUnitStatus(unitName string, status UpgradeSeriesStatus) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set series upgrade status of unit %q", unitName)
+	var from UpgradeSeriesStatus
+	switch status {
+	case UpgradeSeriesPrepareCompleted:
+		from = UpgradeSeriesPrepareStarted
+	case UpgradeSeriesCompleted:
+		from = UpgradeSeriesCompleteStarted
+	default:
+		return errors.NotValidf("status %q", status)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		current, err := m.UpgradeSeriesUnitStatus(unitName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if current == status {
+			return nil, jujutxn.ErrNoOperations
+		}
+		if current != from {
+			return nil, errors.Errorf("unit series upgrade is %s", current)
+		}
+		key := "upgradeseries.units." + unitName
+		// Assert the status of every unit, so that concurrent changes
+		// are seen before deciding whether all units are done.
+		assert := bson.D{{"upgradeseries.status", m.doc.UpgradeSeries.Status}}
+		for name, unitStatus := range m.doc.UpgradeSeries.Units {
+			assert = append(assert, bson.DocElem{"upgradeseries.units." + name, unitStatus})
+		}
+		update := bson.D{{"$set", bson.D{{key, status}}}}
+		if m.allUnitsUpgradeSeriesStatus(unitName, status) {
+			switch status {
+			case UpgradeSeriesPrepareCompleted:
+				update = bson.D{{"$set", bson.D{
+					{key, status},
+					{"upgradeseries.status", UpgradeSeriesPrepareCompleted},
+				}}}
+			case UpgradeSeriesCompleted:
+				update = bson.D{{"$unset", bson.D{{"upgradeseries", nil}}}}
+			}
+		}
+		return []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: assert,
+			Update: update,
+		}}, nil
+	}
+	return m.st.run(buildTxn)
+}
+
+// allUnitsUpgradeSeriesStatus reports whether all the units in the
+// machine's series upgrade other than the named one have the given status.
+func (m *Machine) allUnitsUpgradeSeriesStatus(unitName string, status UpgradeSeriesStatus) bool {
+	for name, unitStatus := range m.doc.UpgradeSeries.Units {
+		if name != unitName && unitStatus != status {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,152 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type UpgradeSeriesSuite struct {
+	ConnSuite
+	machine *state.Machine
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&UpgradeSeriesSuite{})
+
+func (s *UpgradeSeriesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.machine = s.Factory.MakeMachine(c, nil)
+	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{Machine: s.machine})
+}
+
+func (s *UpgradeSeriesSuite) assertStatus(c *gc.C, status state.UpgradeSeriesStatus) {
+	err := s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.UpgradeSeriesStatus(), gc.Equals, status)
+}
+
+func (s *UpgradeSeriesSuite) TestPrepareUpgradeSeries(c *gc.C) {
+	c.Assert(s.machine.UpgradeSeriesStatus(), gc.Equals, state.UpgradeSeriesNotStarted)
+
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, state.UpgradeSeriesPrepareStarted)
+	c.Assert(s.machine.UpgradeSeriesTarget(), gc.Equals, "trusty")
+	status, err := s.machine.UpgradeSeriesUnitStatus(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.UpgradeSeriesPrepareStarted)
+
+	_, err = s.machine.UpgradeSeriesUnitStatus("wordpress/42")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UpgradeSeriesSuite) TestPrepareUpgradeSeriesNoUnits(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	err := machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.UpgradeSeriesStatus(), gc.Equals, state.UpgradeSeriesPrepareCompleted)
+}
+
+func (s *UpgradeSeriesSuite) TestPrepareUpgradeSeriesErrors(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("quantal")
+	c.Assert(err, gc.ErrorMatches, `cannot prepare series upgrade of machine 0: machine is already running quantal`)
+
+	err = s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, gc.ErrorMatches, `cannot prepare series upgrade of machine 0: series upgrade already in progress`)
+
+	manager := s.Factory.MakeMachine(c, &factory.MachineParams{
+		Jobs: []state.MachineJob{state.JobManageEnviron},
+	})
+	err = manager.PrepareUpgradeSeries("trusty")
+	c.Assert(err, gc.ErrorMatches, `cannot prepare series upgrade of machine .*: machine is a state server`)
+}
+
+func (s *UpgradeSeriesSuite) TestUpgradeSeries(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The machine cannot be completed before the units are prepared.
+	err = s.machine.CompleteUpgradeSeries()
+	c.Assert(err, gc.ErrorMatches, `cannot complete series upgrade of machine 0: series upgrade is prepare started`)
+
+	err = s.machine.SetUpgradeSeriesUnitStatus(s.unit.Name(), state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, state.UpgradeSeriesPrepareCompleted)
+
+	err = s.machine.CompleteUpgradeSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, state.UpgradeSeriesCompleteStarted)
+
+	err = s.machine.StartUnitUpgradeSeriesCompletion()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, state.UpgradeSeriesCompleteStarted)
+	c.Assert(s.machine.Series(), gc.Equals, "trusty")
+	status, err := s.machine.UpgradeSeriesUnitStatus(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.UpgradeSeriesCompleteStarted)
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.Series(), gc.Equals, "trusty")
+
+	err = s.machine.SetUpgradeSeriesUnitStatus(s.unit.Name(), state.UpgradeSeriesCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, state.UpgradeSeriesNotStarted)
+	c.Assert(s.machine.UpgradeSeriesTarget(), gc.Equals, "")
+}
+
+func (s *UpgradeSeriesSuite) TestSetUpgradeSeriesUnitStatusWaitsForAllUnits(c *gc.C) {
+	other := s.Factory.MakeUnit(c, &factory.UnitParams{Machine: s.machine})
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetUpgradeSeriesUnitStatus(s.unit.Name(), state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, state.UpgradeSeriesPrepareStarted)
+
+	err = s.machine.SetUpgradeSeriesUnitStatus(other.Name(), state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, state.UpgradeSeriesPrepareCompleted)
+}
+
+func (s *UpgradeSeriesSuite) TestSetUpgradeSeriesUnitStatusInvalid(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetUpgradeSeriesUnitStatus(s.unit.Name(), state.UpgradeSeriesCompleteStarted)
+	c.Assert(err, gc.ErrorMatches, `cannot set series upgrade status of unit ".*": status "complete started" not valid`)
+
+	err = s.machine.SetUpgradeSeriesUnitStatus(s.unit.Name(), state.UpgradeSeriesCompleted)
+	c.Assert(err, gc.ErrorMatches, `cannot set series upgrade status of unit ".*": unit series upgrade is prepare started`)
+}
+
+func (s *UpgradeSeriesSuite) TestUpgradeSeriesBlocksMachineChanges(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+
+	service, err := s.unit.Service()
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit ".*" to machine 0: machine 0 is upgrading series`)
+
+	_, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.machine.Id(), instance.LXC)
+	c.Assert(err, gc.ErrorMatches, `.*machine 0 is upgrading series`)
+}
@@ -54,6 +54,7 @@ type EnvUserParams struct {
 	User        string
 	DisplayName string
 	CreatedBy   names.Tag
+	Access      state.EnvironmentAccess
 }
 
 // CharmParams defines the parameters for creating a charm.
@@ -162,7 +163,7 @@ func (factory *Factory) MakeUser(c *gc.C, params *UserParams) *state.User {
 		params.Name, params.DisplayName, params.Password, creatorUserTag.Name())
 	c.Assert(err, jc.ErrorIsNil)
 	if !params.NoEnvUser {
-		_, err := factory.st.AddEnvironmentUser(user.UserTag(), names.NewUserTag(user.CreatedBy()), params.DisplayName)
+		_, err := factory.st.AddEnvironmentUser(user.UserTag(), names.NewUserTag(user.CreatedBy()), params.DisplayName, state.EnvironmentWriteAccess)
 		c.Assert(err, jc.ErrorIsNil)
 	}
 	if params.Disabled {
@@ -192,8 +193,11 @@ func (factory *Factory) MakeEnvUser(c *gc.C, params *EnvUserParams) *state.Envir
 		c.Assert(err, jc.ErrorIsNil)
 		params.CreatedBy = env.Owner()
 	}
+	if params.Access == "" {
+		params.Access = state.EnvironmentWriteAccess
+	}
 	createdByUserTag := params.CreatedBy.(names.UserTag)
-	envUser, err := factory.st.AddEnvironmentUser(names.NewUserTag(params.User), createdByUserTag, params.DisplayName)
+	envUser, err := factory.st.AddEnvironmentUser(names.NewUserTag(params.User), createdByUserTag, params.DisplayName, params.Access)
 	c.Assert(err, jc.ErrorIsNil)
 	return envUser
 }
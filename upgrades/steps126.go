@@ -30,6 +30,13 @@ func stateStepsFor126() []Step {
 				return state.AddFilesystemStatus(context.State())
 			},
 		},
+		&upgradeStep{
+			description: "add access levels to environment users",
+			targets:     []Target{DatabaseMaster},
+			run: func(context Context) error {
+				return state.AddEnvUserAccess(context.State())
+			},
+		},
 		&upgradeStep{
 			description: "upgrade environment config",
 			targets:     []Target{DatabaseMaster},
@@ -25,6 +25,7 @@ func (s *steps126Suite) TestStateStepsFor126(c *gc.C) {
 	expected := []string{
 		"add the version field to all settings docs",
 		"add status to filesystem",
+		"add access levels to environment users",
 		"upgrade environment config",
 	}
 	assertStateSteps(c, version.MustParse("1.26.0"), expected)
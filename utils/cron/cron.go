@@ -0,0 +1,221 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cron parses cron-like schedule expressions, and computes
+// the times at which they fire.
+//
+// An expression has five space-separated fields: minute (0-59), hour
+// (0-23), day of month (1-31), month (1-12 or jan-dec) and day of week
+// (0-7 or sun-sat, where both 0 and 7 are Sunday). Each field may be
+// "*", a value, a range "a-b", or a comma-separated list of these, and
+// "*" or a range may be followed by a step "/n". As in cron, if both
+// the day of month and the day of week are restricted, a time matches
+// if either of them does.
+//
+// The descriptors @yearly (or @annually), @monthly, @weekly, @daily
+// (or @midnight) and @hourly may be used in place of the five fields.
+//
+// Schedules are evaluated in UTC.
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// maxSearch bounds how far ahead Next looks for a matching time, so
+// that schedules that can never fire, such as "0 0 30 2 *", do not
+// loop forever.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// field describes the values allowed in one field of an expression.
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: monthNames},
+	{name: "day of week", min: 0, max: 7, names: dayNames},
+}
+
+// Schedule holds a parsed schedule expression.
+type Schedule struct {
+	expr string
+
+	// Each set holds a bit for each value that matches the field.
+	minute, hour, dom, month, dow uint64
+
+	// domStar and dowStar record whether the day of month and day
+	// of week fields were unrestricted.
+	domStar, dowStar bool
+}
+
+// Parse parses a schedule expression.
+func Parse(expr string) (*Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if strings.HasPrefix(spec, "@") {
+		var ok bool
+		if spec, ok = descriptors[strings.ToLower(spec)]; !ok {
+			return nil, errors.Errorf("unknown schedule descriptor %q", expr)
+		}
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, errors.Errorf("expected %d fields in schedule %q, got %d", len(fields), expr, len(parts))
+	}
+	sets := make([]uint64, len(fields))
+	for i, part := range parts {
+		set, err := fields[i].parse(part)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid schedule %q", expr)
+		}
+		sets[i] = set
+	}
+	// Sunday may be given as either 0 or 7.
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+	return &Schedule{
+		expr:    expr,
+		minute:  sets[0],
+		hour:    sets[1],
+		dom:     sets[2],
+		month:   sets[3],
+		dow:     sets[4],
+		domStar: parts[2] == "*",
+		dowStar: parts[4] == "*",
+	}, nil
+}
+
+// String returns the expression from which the schedule was parsed.
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// Next returns the first time after t at which the schedule fires,
+// to the minute. It returns the zero time if the schedule never fires.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxSearch)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchDay returns whether the schedule fires on the day of t.
+func (s *Schedule) matchDay(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// parse returns the set of values matched by the given field of an
+// expression.
+func (f field) parse(spec string) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(spec, ",") {
+		min, max, step, err := f.parseItem(item)
+		if err != nil {
+			return 0, errors.Annotatef(err, "%s %q", f.name, item)
+		}
+		for v := min; v <= max; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// parseItem parses a single item of a comma-separated list.
+func (f field) parseItem(item string) (min, max, step int, err error) {
+	rangeSpec, stepSpec := item, ""
+	if i := strings.Index(item, "/"); i >= 0 {
+		rangeSpec, stepSpec = item[:i], item[i+1:]
+	}
+	step = 1
+	if stepSpec != "" {
+		if step, err = strconv.Atoi(stepSpec); err != nil || step < 1 {
+			return 0, 0, 0, errors.Errorf("invalid step %q", stepSpec)
+		}
+	}
+	switch {
+	case rangeSpec == "*":
+		return f.min, f.max, step, nil
+	case strings.Contains(rangeSpec, "-"):
+		bounds := strings.SplitN(rangeSpec, "-", 2)
+		if min, err = f.value(bounds[0]); err != nil {
+			return 0, 0, 0, err
+		}
+		if max, err = f.value(bounds[1]); err != nil {
+			return 0, 0, 0, err
+		}
+		if min > max {
+			return 0, 0, 0, errors.Errorf("range start %d is after end %d", min, max)
+		}
+		return min, max, step, nil
+	}
+	if stepSpec != "" {
+		return 0, 0, 0, errors.New("step given without range")
+	}
+	if min, err = f.value(rangeSpec); err != nil {
+		return 0, 0, 0, err
+	}
+	return min, min, 1, nil
+}
+
+// value parses a single value of the field, given as a number or,
+// for months and days of the week, a name.
+func (f field) value(spec string) (int, error) {
+	if v, ok := f.names[strings.ToLower(spec)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(spec)
+	if err != nil {
+		return 0, errors.Errorf("invalid value %q", spec)
+	}
+	if v < f.min || v > f.max {
+		return 0, errors.Errorf("value %d out of range %d-%d", v, f.min, f.max)
+	}
+	return v, nil
+}
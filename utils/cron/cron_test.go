@@ -0,0 +1,134 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cron_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/utils/cron"
+)
+
+type cronSuite struct{}
+
+var _ = gc.Suite(&cronSuite{})
+
+// start is a Thursday.
+var start = time.Date(2015, 10, 1, 12, 30, 15, 0, time.UTC)
+
+var nextTests = []struct {
+	expr string
+	next []string
+}{{
+	expr: "* * * * *",
+	next: []string{"2015-10-01 12:31", "2015-10-01 12:32"},
+}, {
+	expr: "*/15 * * * *",
+	next: []string{"2015-10-01 12:45", "2015-10-01 13:00", "2015-10-01 13:15"},
+}, {
+	expr: "0 3 * * *",
+	next: []string{"2015-10-02 03:00", "2015-10-03 03:00"},
+}, {
+	expr: "@daily",
+	next: []string{"2015-10-02 00:00", "2015-10-03 00:00"},
+}, {
+	expr: "@hourly",
+	next: []string{"2015-10-01 13:00", "2015-10-01 14:00"},
+}, {
+	expr: "30 2 * * sun",
+	next: []string{"2015-10-04 02:30", "2015-10-11 02:30"},
+}, {
+	expr: "30 2 * * 7",
+	next: []string{"2015-10-04 02:30", "2015-10-11 02:30"},
+}, {
+	expr: "0 0 1 */3 *",
+	next: []string{"2016-01-01 00:00", "2016-04-01 00:00"},
+}, {
+	expr: "0 9-17/4 * * mon-fri",
+	next: []string{
+		"2015-10-01 13:00", "2015-10-01 17:00",
+		"2015-10-02 09:00", "2015-10-02 13:00", "2015-10-02 17:00",
+		"2015-10-05 09:00",
+	},
+}, {
+	// Either the day of month or the day of week may match.
+	expr: "0 0 5 * mon",
+	next: []string{"2015-10-05 00:00", "2015-10-12 00:00"},
+}, {
+	expr: "0 0 29 feb *",
+	next: []string{"2016-02-29 00:00", "2020-02-29 00:00"},
+}, {
+	expr: "0 12 1,15 JAN,Jul *",
+	next: []string{"2016-01-01 12:00", "2016-01-15 12:00", "2016-07-01 12:00"},
+}}
+
+func (*cronSuite) TestNext(c *gc.C) {
+	for i, test := range nextTests {
+		c.Logf("test %d: %s", i, test.expr)
+		schedule, err := cron.Parse(test.expr)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(schedule.String(), gc.Equals, test.expr)
+		t := start
+		for _, expect := range test.next {
+			t = schedule.Next(t)
+			c.Check(t.Format("2006-01-02 15:04"), gc.Equals, expect)
+		}
+	}
+}
+
+func (*cronSuite) TestNextNever(c *gc.C) {
+	schedule, err := cron.Parse("0 0 30 2 *")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedule.Next(start).IsZero(), jc.IsTrue)
+}
+
+func (*cronSuite) TestNextLocalTime(c *gc.C) {
+	schedule, err := cron.Parse("0 12 * * *")
+	c.Assert(err, jc.ErrorIsNil)
+	// 12:30 in UTC+1 is 11:30 UTC.
+	t := time.Date(2015, 10, 1, 12, 30, 0, 0, time.FixedZone("UTC+1", 3600))
+	c.Assert(schedule.Next(t), gc.Equals, time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC))
+}
+
+var parseErrorTests = []struct {
+	expr string
+	err  string
+}{{
+	expr: "",
+	err:  `expected 5 fields in schedule "", got 0`,
+}, {
+	expr: "* * * *",
+	err:  `expected 5 fields in schedule "\* \* \* \*", got 4`,
+}, {
+	expr: "@fortnightly",
+	err:  `unknown schedule descriptor "@fortnightly"`,
+}, {
+	expr: "60 * * * *",
+	err:  `invalid schedule "60 \* \* \* \*": minute "60": value 60 out of range 0-59`,
+}, {
+	expr: "* 5-2 * * *",
+	err:  `invalid schedule .*: hour "5-2": range start 5 is after end 2`,
+}, {
+	expr: "* * 0 * *",
+	err:  `invalid schedule .*: day of month "0": value 0 out of range 1-31`,
+}, {
+	expr: "* * * foo *",
+	err:  `invalid schedule .*: month "foo": invalid value "foo"`,
+}, {
+	expr: "*/0 * * * *",
+	err:  `invalid schedule .*: minute "\*/0": invalid step "0"`,
+}, {
+	expr: "5/2 * * * *",
+	err:  `invalid schedule .*: minute "5/2": step given without range`,
+}}
+
+func (*cronSuite) TestParseErrors(c *gc.C) {
+	for i, test := range parseErrorTests {
+		c.Logf("test %d: %q", i, test.expr)
+		_, err := cron.Parse(test.expr)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionpruner
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+// DefaultPruneInterval is the default time between prunings of the
+// action history.
+const DefaultPruneInterval = time.Hour
+
+// New returns a worker which periodically wakes up to remove finished
+// actions from the environment's action history, according to the
+// max-action-results-age and max-action-results-count settings in the
+// environment's configuration. This worker is intended to run just
+// once per environment.
+func New(st *state.State, pruneInterval time.Duration) worker.Worker {
+	w := &pruneWorker{
+		st:            st,
+		pruneInterval: pruneInterval,
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+type pruneWorker struct {
+	st            *state.State
+	pruneInterval time.Duration
+}
+
+func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(w.pruneInterval):
+			if err := w.prune(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+func (w *pruneWorker) prune() error {
+	cfg, err := w.st.EnvironConfig()
+	if err != nil {
+		return errors.Annotate(err, "cannot read environment config")
+	}
+	var minTime time.Time
+	if maxAge := cfg.MaxActionResultsAge(); maxAge > 0 {
+		minTime = time.Now().Add(-maxAge)
+	}
+	return state.PruneActions(w.st, minTime, cfg.MaxActionResultsCount())
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionpruner_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/actionpruner"
+)
+
+func TestPackage(t *stdtesting.T) {
+	testing.MgoTestPackage(t)
+}
+
+var _ = gc.Suite(&suite{})
+
+type suite struct {
+	statetesting.StateSuite
+	unit   *state.Unit
+	pruner worker.Worker
+}
+
+func (s *suite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{
+		Service: s.Factory.MakeService(c, &factory.ServiceParams{
+			Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "dummy"}),
+		}),
+	})
+}
+
+func (s *suite) StartWorker(c *gc.C) {
+	// Speed up pruning interval for testing.
+	s.pruner = actionpruner.New(s.State, time.Millisecond)
+	s.AddCleanup(func(*gc.C) {
+		s.pruner.Kill()
+		c.Assert(s.pruner.Wait(), jc.ErrorIsNil)
+	})
+}
+
+func (s *suite) addActions(c *gc.C, count int, status state.ActionStatus) {
+	for i := 0; i < count; i++ {
+		action, err := s.unit.AddAction("snapshot", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		if status != state.ActionPending {
+			_, err = action.Finish(state.ActionResults{Status: status})
+			c.Assert(err, jc.ErrorIsNil)
+		}
+	}
+}
+
+func (s *suite) countActions(c *gc.C, status state.ActionStatus) int {
+	actions, err := s.State.FilterActions(state.ActionFilter{
+		Statuses: []state.ActionStatus{status},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return len(actions)
+}
+
+func (s *suite) TestPrunesExcessActions(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"max-action-results-count": 2,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.addActions(c, 5, state.ActionCompleted)
+	s.addActions(c, 1, state.ActionPending)
+
+	s.StartWorker(c)
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		if s.countActions(c, state.ActionCompleted) == 2 {
+			c.Assert(s.countActions(c, state.ActionPending), gc.Equals, 1)
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
+
+func (s *suite) TestKeepsRecentActions(c *gc.C) {
+	s.addActions(c, 5, state.ActionCompleted)
+
+	s.StartWorker(c)
+
+	time.Sleep(testing.ShortWait)
+	c.Assert(s.countActions(c, state.ActionCompleted), gc.Equals, 5)
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.actionscheduler")
+
+// DefaultCheckInterval is the default time between checks for action
+// schedules that are due to run. Schedules are given to the minute.
+const DefaultCheckInterval = time.Minute
+
+// now is replaced in tests.
+var now = time.Now
+
+// New returns a worker which periodically wakes up to enqueue the
+// actions of the environment's action schedules that are due to run.
+// This worker is intended to run just once per environment.
+func New(st *state.State, checkInterval time.Duration) worker.Worker {
+	w := &scheduleWorker{
+		st:            st,
+		checkInterval: checkInterval,
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+type scheduleWorker struct {
+	st            *state.State
+	checkInterval time.Duration
+}
+
+func (w *scheduleWorker) loop(stopCh <-chan struct{}) error {
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(w.checkInterval):
+			if err := w.runDue(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// runDue runs each schedule that is due. A schedule that cannot be run
+// does not prevent the others from running, so its error is logged
+// rather than returned.
+func (w *scheduleWorker) runDue() error {
+	t := now()
+	schedules, err := w.st.DueActionSchedules(t)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, schedule := range schedules {
+		actions, err := schedule.Run(t)
+		if errors.IsNotFound(err) {
+			logger.Infof("removed action schedule %q: %v", schedule.Id(), err)
+			continue
+		} else if err != nil {
+			logger.Errorf("%v", err)
+		}
+		for _, action := range actions {
+			logger.Debugf("enqueued action %q on %q for schedule %q", action.Id(), action.Receiver(), schedule.Id())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker/actionscheduler"
+)
+
+func TestPackage(t *stdtesting.T) {
+	testing.MgoTestPackage(t)
+}
+
+var _ = gc.Suite(&suite{})
+
+type suite struct {
+	statetesting.StateSuite
+	unit *state.Unit
+}
+
+func (s *suite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{
+		Service: s.Factory.MakeService(c, &factory.ServiceParams{
+			Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "dummy"}),
+		}),
+	})
+}
+
+func (s *suite) startWorker(c *gc.C, now time.Time) {
+	s.PatchValue(actionscheduler.Now, func() time.Time { return now })
+	// Speed up the check interval for testing.
+	w := actionscheduler.New(s.State, time.Millisecond)
+	s.AddCleanup(func(*gc.C) {
+		w.Kill()
+		c.Assert(w.Wait(), jc.ErrorIsNil)
+	})
+}
+
+func (s *suite) addSchedule(c *gc.C) *state.ActionSchedule {
+	schedule, err := s.State.AddActionSchedule(state.ActionScheduleArgs{
+		Receiver: s.unit.UnitTag(),
+		Name:     "snapshot",
+		Schedule: "@hourly",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return schedule
+}
+
+func (s *suite) TestEnqueuesDueActions(c *gc.C) {
+	schedule := s.addSchedule(c)
+	s.startWorker(c, schedule.NextRun().Add(2*time.Hour))
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		actions, err := s.unit.PendingActions()
+		c.Assert(err, jc.ErrorIsNil)
+		if len(actions) == 0 {
+			continue
+		}
+		c.Assert(actions, gc.HasLen, 1)
+		c.Assert(actions[0].Schedule(), gc.Equals, schedule.Id())
+		c.Assert(actions[0].MissedRuns(), gc.Equals, 2)
+
+		// The schedule is not run again until it is next due.
+		time.Sleep(testing.ShortWait)
+		actions, err = s.unit.PendingActions()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(actions, gc.HasLen, 1)
+		return
+	}
+	c.Fatal("scheduled action was not enqueued")
+}
+
+func (s *suite) TestIgnoresSchedulesNotDue(c *gc.C) {
+	schedule := s.addSchedule(c)
+	s.startWorker(c, schedule.NextRun().Add(-time.Minute))
+
+	time.Sleep(testing.ShortWait)
+	actions, err := s.unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 0)
+}
@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditpruner
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+// AuditPruneParams specifies how audit events should be pruned.
+type AuditPruneParams struct {
+	MaxEventAge     time.Duration
+	MaxEventsPerEnv int
+	PruneInterval   time.Duration
+}
+
+const DefaultMaxEventAge = 90 * 24 * time.Hour // 90 days
+const DefaultMaxEventsPerEnv = 1000000
+const DefaultPruneInterval = time.Hour
+
+// NewAuditPruneParams returns an AuditPruneParams initialised with
+// default values.
+func NewAuditPruneParams() *AuditPruneParams {
+	return &AuditPruneParams{
+		MaxEventAge:     DefaultMaxEventAge,
+		MaxEventsPerEnv: DefaultMaxEventsPerEnv,
+		PruneInterval:   DefaultPruneInterval,
+	}
+}
+
+// New returns a worker which periodically wakes up to remove old
+// audit events stored in MongoDB. This worker is intended to run
+// just once, on the MongoDB master.
+func New(st *state.State, params *AuditPruneParams) worker.Worker {
+	w := &pruneWorker{
+		st:     st,
+		params: params,
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+type pruneWorker struct {
+	st     *state.State
+	params *AuditPruneParams
+}
+
+func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
+	p := w.params
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(p.PruneInterval):
+			minEventTime := time.Now().Add(-p.MaxEventAge)
+			err := state.PruneAuditEvents(w.st, minEventTime, p.MaxEventsPerEnv)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
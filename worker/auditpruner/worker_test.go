@@ -0,0 +1,100 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditpruner_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/auditpruner"
+)
+
+func TestPackage(t *stdtesting.T) {
+	testing.MgoTestPackage(t)
+}
+
+var _ = gc.Suite(&suite{})
+
+type suite struct {
+	statetesting.StateSuite
+	pruner worker.Worker
+}
+
+func (s *suite) StartWorker(c *gc.C, maxEventAge time.Duration, maxEventsPerEnv int) {
+	params := &auditpruner.AuditPruneParams{
+		MaxEventAge:     maxEventAge,
+		MaxEventsPerEnv: maxEventsPerEnv,
+		PruneInterval:   time.Millisecond, // Speed up pruning interval for testing
+	}
+	s.pruner = auditpruner.New(s.State, params)
+	s.AddCleanup(func(*gc.C) {
+		s.pruner.Kill()
+		c.Assert(s.pruner.Wait(), jc.ErrorIsNil)
+	})
+}
+
+func (s *suite) addEvents(c *gc.C, t time.Time, method string, count int) {
+	for i := 0; i < count; i++ {
+		err := s.State.AddAuditEvent(audit.Event{
+			Time:   t,
+			User:   "user-admin",
+			Facade: "Client",
+			Method: method,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *suite) countEvents(c *gc.C, method string) int {
+	events, err := s.State.AuditEvents(state.AuditEventFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	count := 0
+	for _, event := range events {
+		if event.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *suite) TestPrunesOldEvents(c *gc.C) {
+	maxEventAge := 24 * time.Hour
+	now := time.Now()
+	s.addEvents(c, now.Add(-maxEventAge-time.Minute), "Prune", 5)
+	s.addEvents(c, now, "Keep", 5)
+
+	s.StartWorker(c, maxEventAge, 1000)
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		if s.countEvents(c, "Prune") == 0 {
+			c.Assert(s.countEvents(c, "Keep"), gc.Equals, 5)
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
+
+func (s *suite) TestPrunesExcessEvents(c *gc.C) {
+	now := time.Now()
+	s.addEvents(c, now.Add(-time.Hour), "Prune", 5)
+	s.addEvents(c, now, "Keep", 3)
+
+	s.StartWorker(c, 24*time.Hour, 3)
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		if s.countEvents(c, "Prune") == 0 {
+			c.Assert(s.countEvents(c, "Keep"), gc.Equals, 3)
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
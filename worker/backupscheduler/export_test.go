@@ -0,0 +1,10 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+var (
+	Now        = &now
+	NewBackups = &newBackups
+	NewDBInfo  = &newDBInfo
+)
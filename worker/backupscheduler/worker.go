@@ -0,0 +1,190 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/replicaset"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.backupscheduler")
+
+// DefaultCheckInterval is the default time between checks for whether
+// a scheduled backup is due.
+const DefaultCheckInterval = time.Minute
+
+// The following are replaced in tests.
+var (
+	now = time.Now
+
+	newBackups = func(st *state.State, cfg *config.Config, dataDir string) (backups.Backups, io.Closer, error) {
+		stor, err := backups.OpenStorage(st, cfg, dataDir)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		return backups.NewBackups(stor), stor, nil
+	}
+
+	newDBInfo = func(st *state.State) (*backups.DBInfo, error) {
+		session := st.MongoSession().Copy()
+		defer session.Close()
+
+		// Don't go if HA isn't ready.
+		if err := replicaset.WaitUntilReady(session, 60); err != nil {
+			return nil, errors.Annotate(err, "HA not ready")
+		}
+		return backups.NewDBInfo(st.MongoConnectionInfo(), session)
+	}
+)
+
+// New returns a worker which periodically wakes up to make a backup of
+// the environment's state, if one is due according to the environment's
+// backup-interval setting, and to remove the scheduled backups that are
+// no longer kept according to its retention settings. The outcome of
+// each attempt is recorded with State.SetBackupStatus.
+//
+// Backups are only made of the state server environment; in any other
+// environment, the worker does nothing. This worker is intended to run
+// just once per environment.
+func New(st *state.State, paths backups.Paths, machineID string, checkInterval time.Duration) worker.Worker {
+	w := &backupWorker{
+		st:            st,
+		paths:         paths,
+		machineID:     machineID,
+		checkInterval: checkInterval,
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+type backupWorker struct {
+	st            *state.State
+	paths         backups.Paths
+	machineID     string
+	checkInterval time.Duration
+
+	// lastFailed holds the time of the last failed attempt to make
+	// a backup, so that it is not retried until the next is due.
+	lastFailed time.Time
+}
+
+func (w *backupWorker) loop(stopCh <-chan struct{}) error {
+	if !w.st.IsStateServer() {
+		<-stopCh
+		return tomb.ErrDying
+	}
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(w.checkInterval):
+			if err := w.check(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// check makes a backup, if one is due, and then removes the expired
+// ones. A failure to make or remove backups is recorded in the backup
+// status rather than returned, so that it is not retried until the
+// next backup is due.
+func (w *backupWorker) check() error {
+	cfg, err := w.st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	interval := cfg.BackupInterval()
+	if interval == 0 {
+		return nil
+	}
+	t := now()
+	if t.Before(w.lastFailed.Add(interval)) {
+		return nil
+	}
+
+	b, closer, err := newBackups(w.st, cfg, w.paths.DataDir)
+	if err != nil {
+		return w.failed(t, errors.Annotate(err, "cannot open backup storage"))
+	}
+	defer closer.Close()
+	metas, err := b.List()
+	if err != nil {
+		return w.failed(t, errors.Annotate(err, "cannot list backups"))
+	}
+	var last time.Time
+	for _, meta := range metas {
+		if meta.Scheduled && meta.Started.After(last) {
+			last = meta.Started
+		}
+	}
+	if t.Before(last.Add(interval)) {
+		return nil
+	}
+
+	meta, err := w.create(b, cfg.BackupEncryptionKey(), cfg.BackupExclude())
+	if err != nil {
+		return w.failed(t, errors.Annotate(err, "cannot create backup"))
+	}
+	logger.Infof("created scheduled backup %q", meta.ID())
+
+	policy := backups.RetentionPolicy{
+		KeepLast:   cfg.BackupKeepLast(),
+		KeepDaily:  cfg.BackupKeepDaily(),
+		KeepWeekly: cfg.BackupKeepWeekly(),
+	}
+	for _, expired := range policy.Expired(append(metas, meta)) {
+		if err := b.Remove(expired.ID()); err != nil {
+			return w.failed(t, errors.Annotatef(err, "cannot remove expired backup %q", expired.ID()))
+		}
+		logger.Infof("removed expired backup %q", expired.ID())
+	}
+
+	message := fmt.Sprintf("last backup %q made at %s", meta.ID(), meta.Started.UTC().Format(time.RFC3339))
+	return errors.Trace(w.st.SetBackupStatus(state.StatusIdle, message, nil))
+}
+
+// create makes and stores a new scheduled backup, encrypted with the
+// given ASCII-armored key if it is not empty, and leaving out the
+// contents of the excluded databases and collections.
+func (w *backupWorker) create(b backups.Backups, encryptionKey string, excluded []string) (*backups.Metadata, error) {
+	var key *backups.Key
+	if encryptionKey != "" {
+		var err error
+		if key, err = backups.ParseKey([]byte(encryptionKey)); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	dbInfo, err := newDBInfo(w.st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta, err := backups.NewMetadataState(w.st, w.machineID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta.Scheduled = true
+	meta.Excluded = excluded
+	if err := b.Create(meta, &w.paths, dbInfo, key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return meta, nil
+}
+
+// failed logs the given error and records it in the backup status.
+func (w *backupWorker) failed(t time.Time, err error) error {
+	logger.Errorf("%v", err)
+	w.lastFailed = t
+	return errors.Trace(w.st.SetBackupStatus(state.StatusError, err.Error(), nil))
+}
@@ -0,0 +1,246 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"io"
+	"io/ioutil"
+	"strconv"
+	"sync"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+func TestPackage(t *stdtesting.T) {
+	testing.MgoTestPackage(t)
+}
+
+var _ = gc.Suite(&suite{})
+
+type suite struct {
+	statetesting.StateSuite
+	backups *fakeBackups
+}
+
+func (s *suite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.backups = &fakeBackups{}
+	s.PatchValue(backupscheduler.NewBackups, func(*state.State, *config.Config, string) (backups.Backups, io.Closer, error) {
+		return s.backups, ioutil.NopCloser(nil), nil
+	})
+	s.PatchValue(backupscheduler.NewDBInfo, func(*state.State) (*backups.DBInfo, error) {
+		return &backups.DBInfo{}, nil
+	})
+}
+
+func (s *suite) startWorker(c *gc.C) {
+	// Speed up the check interval for testing.
+	w := backupscheduler.New(s.State, backups.Paths{DataDir: "/var/lib/juju"}, "0", time.Millisecond)
+	s.AddCleanup(func(*gc.C) {
+		w.Kill()
+		c.Assert(w.Wait(), jc.ErrorIsNil)
+	})
+}
+
+func (s *suite) setInterval(c *gc.C, attrs map[string]interface{}) {
+	err := s.State.UpdateEnvironConfig(attrs, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *suite) waitForStatus(c *gc.C) state.StatusInfo {
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		info, err := s.State.BackupStatus()
+		if errors.IsNotFound(err) {
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		return info
+	}
+	c.Fatal("backup status was not set")
+	panic("unreachable")
+}
+
+func (s *suite) TestNoBackupsWithoutInterval(c *gc.C) {
+	s.startWorker(c)
+
+	time.Sleep(testing.ShortWait)
+	c.Assert(s.backups.createdCount(), gc.Equals, 0)
+	_, err := s.State.BackupStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *suite) TestCreatesAndPrunes(c *gc.C) {
+	s.setInterval(c, map[string]interface{}{
+		"backup-interval":  "1h",
+		"backup-keep-last": 2,
+	})
+	manual := s.backups.add("manual", time.Now().Add(-6*time.Hour), false)
+	s.backups.add("oldest", time.Now().Add(-5*time.Hour), true)
+	s.backups.add("older", time.Now().Add(-4*time.Hour), true)
+	s.backups.add("old", time.Now().Add(-3*time.Hour), true)
+
+	s.startWorker(c)
+
+	info := s.waitForStatus(c)
+	c.Assert(info.Status, gc.Equals, state.StatusIdle)
+	c.Assert(info.Message, gc.Matches, `last backup "new-0" made at .*`)
+
+	metas, err := s.backups.List()
+	c.Assert(err, jc.ErrorIsNil)
+	var ids []string
+	for _, meta := range metas {
+		ids = append(ids, meta.ID())
+	}
+	c.Assert(ids, jc.SameContents, []string{manual.ID(), "old", "new-0"})
+
+	created := s.backups.created[0]
+	c.Assert(created.Scheduled, jc.IsTrue)
+	c.Assert(created.Origin.Machine, gc.Equals, "0")
+	c.Assert(s.backups.paths.DataDir, gc.Equals, "/var/lib/juju")
+	c.Assert(s.backups.key, gc.IsNil)
+}
+
+func (s *suite) TestEncryptsWithEnvironmentKey(c *gc.C) {
+	s.setInterval(c, map[string]interface{}{
+		"backup-interval":       "1h",
+		"backup-encryption-key": testing.OpenPGPPublicKey,
+	})
+
+	s.startWorker(c)
+
+	info := s.waitForStatus(c)
+	c.Assert(info.Status, gc.Equals, state.StatusIdle)
+	c.Assert(s.backups.key, gc.NotNil)
+	c.Assert(s.backups.key.Fingerprint(), gc.Equals, testing.OpenPGPKeyFingerprint)
+}
+
+func (s *suite) TestExcludesEnvironmentExclusions(c *gc.C) {
+	s.setInterval(c, map[string]interface{}{
+		"backup-interval": "1h",
+		"backup-exclude":  "logs,juju.statuseshistory",
+	})
+
+	s.startWorker(c)
+
+	info := s.waitForStatus(c)
+	c.Assert(info.Status, gc.Equals, state.StatusIdle)
+	c.Assert(s.backups.created[0].Excluded, jc.DeepEquals, []string{"logs", "juju.statuseshistory"})
+}
+
+func (s *suite) TestNotDue(c *gc.C) {
+	s.setInterval(c, map[string]interface{}{"backup-interval": "1h"})
+	s.backups.add("recent", time.Now().Add(-30*time.Minute), true)
+	// Backups made by hand do not count toward the schedule.
+	s.backups.add("manual", time.Now().Add(-2*time.Hour), false)
+
+	s.startWorker(c)
+
+	time.Sleep(testing.ShortWait)
+	c.Assert(s.backups.createdCount(), gc.Equals, 0)
+}
+
+func (s *suite) TestDueLater(c *gc.C) {
+	s.setInterval(c, map[string]interface{}{"backup-interval": "1h"})
+	s.backups.add("recent", time.Now().Add(-30*time.Minute), true)
+	later := time.Now().Add(45 * time.Minute)
+	s.PatchValue(backupscheduler.Now, func() time.Time { return later })
+
+	s.startWorker(c)
+
+	info := s.waitForStatus(c)
+	c.Assert(info.Status, gc.Equals, state.StatusIdle)
+	c.Assert(s.backups.createdCount(), gc.Equals, 1)
+}
+
+func (s *suite) TestFailureReported(c *gc.C) {
+	s.setInterval(c, map[string]interface{}{"backup-interval": "1h"})
+	s.backups.createErr = errors.New("disk full")
+
+	s.startWorker(c)
+
+	info := s.waitForStatus(c)
+	c.Assert(info.Status, gc.Equals, state.StatusError)
+	c.Assert(info.Message, gc.Equals, "cannot create backup: disk full")
+
+	// The backup is not retried until the next is due.
+	time.Sleep(testing.ShortWait)
+	c.Assert(s.backups.attempts(), gc.Equals, 1)
+}
+
+type fakeBackups struct {
+	backups.Backups
+
+	mu        sync.Mutex
+	metas     []*backups.Metadata
+	created   []*backups.Metadata
+	paths     *backups.Paths
+	key       *backups.Key
+	tries     int
+	createErr error
+}
+
+func (b *fakeBackups) add(id string, started time.Time, scheduled bool) *backups.Metadata {
+	meta := backups.NewMetadata()
+	meta.SetID(id)
+	meta.Started = started.UTC()
+	meta.Scheduled = scheduled
+	b.metas = append(b.metas, meta)
+	return meta
+}
+
+func (b *fakeBackups) createdCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.created)
+}
+
+func (b *fakeBackups) attempts() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tries
+}
+
+func (b *fakeBackups) Create(meta *backups.Metadata, paths *backups.Paths, dbInfo *backups.DBInfo, key *backups.Key) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tries++
+	if b.createErr != nil {
+		return b.createErr
+	}
+	meta.SetID("new-" + strconv.Itoa(len(b.created)))
+	b.paths = paths
+	b.key = key
+	b.created = append(b.created, meta)
+	b.metas = append(b.metas, meta)
+	return nil
+}
+
+func (b *fakeBackups) List() ([]*backups.Metadata, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*backups.Metadata(nil), b.metas...), nil
+}
+
+func (b *fakeBackups) Remove(id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, meta := range b.metas {
+		if meta.ID() == id {
+			b.metas = append(b.metas[:i], b.metas[i+1:]...)
+			return nil
+		}
+	}
+	return errors.NotFoundf("backup %q", id)
+}
@@ -0,0 +1,10 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder
+
+var (
+	NewLogTailer = &newLogTailer
+	FormatSyslog = formatSyslog
+	FormatGELF   = formatGELF
+)
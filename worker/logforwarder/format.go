@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+// formatter converts a log record into the bytes to be written to a
+// log forwarding receiver, including any framing.
+type formatter func(*state.LogRecord) ([]byte, error)
+
+// newFormatter returns a formatter for the named log forwarding
+// format.
+func newFormatter(format, envUUID string) (formatter, error) {
+	switch format {
+	case config.LogForwardSyslog:
+		return func(r *state.LogRecord) ([]byte, error) {
+			return formatSyslog(r, envUUID), nil
+		}, nil
+	case config.LogForwardGELF:
+		return func(r *state.LogRecord) ([]byte, error) {
+			return formatGELF(r, envUUID)
+		}, nil
+	}
+	return nil, errors.NotValidf("log forwarding format %q", format)
+}
+
+// syslogSeverity returns the syslog severity corresponding to the
+// given log level.
+func syslogSeverity(level loggo.Level) int {
+	switch level {
+	case loggo.CRITICAL:
+		return 2
+	case loggo.ERROR:
+		return 3
+	case loggo.WARNING:
+		return 4
+	case loggo.INFO:
+		return 6
+	}
+	return 7
+}
+
+const (
+	// syslogFacility is the "user-level messages" syslog facility.
+	syslogFacility = 1
+
+	// syslogSDID identifies the structured data element holding
+	// Juju-specific fields. 28978 is Canonical's IANA private
+	// enterprise number.
+	syslogSDID = "juju@28978"
+)
+
+// syslogParamEscaper escapes structured data parameter values, as
+// described in RFC5424 section 6.3.3.
+var syslogParamEscaper = strings.NewReplacer(`"`, `\"`, `\`, `\\`, `]`, `\]`)
+
+// formatSyslog formats the record as an RFC5424 syslog message,
+// framed using octet counting as described in RFC6587.
+func formatSyslog(r *state.LogRecord, envUUID string) []byte {
+	hostname := r.Entity
+	if hostname == "" {
+		hostname = "-"
+	}
+	msg := fmt.Sprintf(`<%d>1 %s %s juju - - [%s env-uuid="%s" module="%s" location="%s"] %s`,
+		syslogFacility*8+syslogSeverity(r.Level),
+		r.Time.UTC().Format("2006-01-02T15:04:05.000000Z"),
+		hostname,
+		syslogSDID,
+		syslogParamEscaper.Replace(envUUID),
+		syslogParamEscaper.Replace(r.Module),
+		syslogParamEscaper.Replace(r.Location),
+		r.Message,
+	)
+	return []byte(fmt.Sprintf("%d %s", len(msg), msg))
+}
+
+// gelfMessage holds the fields of a GELF 1.1 message.
+type gelfMessage struct {
+	Version      string  `json:"version"`
+	Host         string  `json:"host"`
+	ShortMessage string  `json:"short_message"`
+	Timestamp    float64 `json:"timestamp"`
+	Level        int     `json:"level"`
+	EnvUUID      string  `json:"_env_uuid"`
+	Module       string  `json:"_module"`
+	Location     string  `json:"_location"`
+}
+
+// formatGELF formats the record as a GELF message, terminated by a
+// null byte as required for GELF over TCP.
+func formatGELF(r *state.LogRecord, envUUID string) ([]byte, error) {
+	msg := gelfMessage{
+		Version:      "1.1",
+		Host:         r.Entity,
+		ShortMessage: r.Message,
+		Timestamp:    float64(r.Time.UnixNano()) / 1e9,
+		Level:        syslogSeverity(r.Level),
+		EnvUUID:      envUUID,
+		Module:       r.Module,
+		Location:     r.Location,
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(data, 0), nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+type formatSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&formatSuite{})
+
+var testRecord = &state.LogRecord{
+	Time:     time.Date(2015, 10, 1, 12, 30, 0, 123000000, time.UTC),
+	Entity:   "machine-0",
+	Module:   "juju.worker.provisioner",
+	Location: "provisioner.go:42",
+	Level:    loggo.WARNING,
+	Message:  "cannot start instance",
+}
+
+func (s *formatSuite) TestFormatSyslog(c *gc.C) {
+	msg := `<12>1 2015-10-01T12:30:00.123000Z machine-0 juju - - ` +
+		`[juju@28978 env-uuid="env-uuid" module="juju.worker.provisioner" location="provisioner.go:42"] ` +
+		`cannot start instance`
+	out := logforwarder.FormatSyslog(testRecord, "env-uuid")
+	c.Assert(string(out), gc.Equals, "169 "+msg)
+	c.Assert(len(msg), gc.Equals, 169)
+}
+
+func (s *formatSuite) TestFormatSyslogEscapesParams(c *gc.C) {
+	record := *testRecord
+	record.Location = `weird"file]\.go:1`
+	out := logforwarder.FormatSyslog(&record, "env-uuid")
+	c.Assert(string(out), jc.Contains, `location="weird\"file\]\\.go:1"`)
+}
+
+func (s *formatSuite) TestFormatGELF(c *gc.C) {
+	out, err := logforwarder.FormatGELF(testRecord, "env-uuid")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out[len(out)-1], gc.Equals, byte(0))
+
+	var msg map[string]interface{}
+	err = json.Unmarshal(out[:len(out)-1], &msg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(msg, jc.DeepEquals, map[string]interface{}{
+		"version":       "1.1",
+		"host":          "machine-0",
+		"short_message": "cannot start instance",
+		"timestamp":     float64(testRecord.Time.UnixNano()) / 1e9,
+		"level":         float64(4),
+		"_env_uuid":     "env-uuid",
+		"_module":       "juju.worker.provisioner",
+		"_location":     "provisioner.go:42",
+	})
+}
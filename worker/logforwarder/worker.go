@@ -0,0 +1,280 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package logforwarder provides a worker which forwards an
+// environment's logs, as recorded in the database, to an external
+// syslog or GELF receiver.
+package logforwarder
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/cert"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.logforwarder")
+
+// State defines the state methods used by the log forwarder.
+type State interface {
+	state.LoggingState
+	EnvironConfig() (*config.Config, error)
+	WatchForEnvironConfigChanges() state.NotifyWatcher
+	LogForwardPosition(target string) (*state.LogForwardPosition, error)
+	SetLogForwardPosition(target string, pos *state.LogForwardPosition) error
+}
+
+var (
+	newLogTailer = state.NewLogTailer
+
+	// dialTimeout and writeTimeout bound the time spent connecting
+	// to, and writing a single record to, the receiver.
+	dialTimeout  = 30 * time.Second
+	writeTimeout = 30 * time.Second
+)
+
+// New returns a worker which forwards the environment's logs to the
+// receiver configured in the environment's log-forward-address
+// setting, restarting whenever the setting changes. The position up
+// to which logs have been forwarded to each receiver is recorded in
+// state, so that forwarding resumes where it left off when the worker
+// is restarted. This worker is intended to run just once per
+// environment, on the MongoDB master.
+func New(st State) worker.Worker {
+	w := &logForwarder{st: st}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+type logForwarder struct {
+	tomb tomb.Tomb
+	st   State
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *logForwarder) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *logForwarder) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *logForwarder) loop() error {
+	configWatcher := w.st.WatchForEnvironConfigChanges()
+	defer watcher.Stop(configWatcher, &w.tomb)
+
+	var current *target
+	var fwd *forwarder
+	var fwdDead <-chan struct{}
+	defer func() {
+		if fwd != nil {
+			fwd.Kill()
+			fwd.Wait()
+		}
+	}()
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-fwdDead:
+			return errors.Trace(fwd.Wait())
+		case _, ok := <-configWatcher.Changes():
+			if !ok {
+				return watcher.EnsureErr(configWatcher)
+			}
+			cfg, err := w.st.EnvironConfig()
+			if err != nil {
+				return errors.Annotate(err, "cannot read environment config")
+			}
+			next := targetFromConfig(cfg)
+			if sameTarget(current, next) {
+				continue
+			}
+			if fwd != nil {
+				logger.Infof("stopping log forwarding to %s", current)
+				fwd.Kill()
+				if err := fwd.Wait(); err != nil {
+					return errors.Trace(err)
+				}
+				fwd, fwdDead = nil, nil
+			}
+			current = next
+			if current != nil {
+				logger.Infof("starting log forwarding to %s", current)
+				fwd = newForwarder(w.st, *current)
+				fwdDead = fwd.tomb.Dead()
+			}
+		}
+	}
+}
+
+// target describes a receiver to which logs are forwarded.
+type target struct {
+	address string
+	format  string
+	tls     bool
+	caCert  string
+}
+
+// targetFromConfig returns the log forwarding target specified in
+// the given environment config, or nil if log forwarding is not
+// enabled.
+func targetFromConfig(cfg *config.Config) *target {
+	address := cfg.LogForwardAddress()
+	if address == "" {
+		return nil
+	}
+	caCert, _ := cfg.LogForwardCACert()
+	return &target{
+		address: address,
+		format:  cfg.LogForwardFormat(),
+		tls:     cfg.LogForwardTLS(),
+		caCert:  caCert,
+	}
+}
+
+func sameTarget(t0, t1 *target) bool {
+	if t0 == nil || t1 == nil {
+		return t0 == t1
+	}
+	return *t0 == *t1
+}
+
+// key returns the name under which the forwarding position for the
+// target is recorded. Changing only the TLS settings of a target does
+// not change its key, so forwarding continues where it left off.
+func (t target) key() string {
+	return fmt.Sprintf("%s://%s", t.format, t.address)
+}
+
+// String is part of the fmt.Stringer interface.
+func (t target) String() string {
+	if t.tls {
+		return fmt.Sprintf("%s+tls://%s", t.format, t.address)
+	}
+	return t.key()
+}
+
+// dial connects to the target.
+func dial(t target) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	if !t.tls {
+		return dialer.Dial("tcp", t.address)
+	}
+	host, _, err := net.SplitHostPort(t.address)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tlsConfig := &tls.Config{ServerName: host}
+	if t.caCert != "" {
+		caCert, err := cert.ParseCert(t.caCert)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot parse CA certificate")
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		tlsConfig.RootCAs.AddCert(caCert)
+	}
+	return tls.DialWithDialer(dialer, "tcp", t.address, tlsConfig)
+}
+
+// forwarder forwards log records to a single target.
+type forwarder struct {
+	tomb   tomb.Tomb
+	st     State
+	target target
+}
+
+func newForwarder(st State, t target) *forwarder {
+	f := &forwarder{
+		st:     st,
+		target: t,
+	}
+	go func() {
+		defer f.tomb.Done()
+		f.tomb.Kill(f.loop())
+	}()
+	return f
+}
+
+func (f *forwarder) Kill() {
+	f.tomb.Kill(nil)
+}
+
+func (f *forwarder) Wait() error {
+	return f.tomb.Wait()
+}
+
+func (f *forwarder) loop() error {
+	formatter, err := newFormatter(f.target.format, f.st.EnvironUUID())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	key := f.target.key()
+	pos, err := f.st.LogForwardPosition(key)
+	if errors.IsNotFound(err) {
+		// Nothing has been forwarded to this target before, so
+		// start with the logs written from now on, rather than
+		// sending the entire history.
+		pos = state.NewLogForwardPosition(time.Now())
+		if err := f.st.SetLogForwardPosition(key, pos); err != nil {
+			return errors.Trace(err)
+		}
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+
+	conn, err := dial(f.target)
+	if err != nil {
+		return errors.Annotatef(err, "cannot connect to %s", f.target)
+	}
+	defer conn.Close()
+
+	tailer := newLogTailer(f.st, &state.LogTailerParams{
+		AfterId: pos.Id,
+	})
+	defer tailer.Stop()
+
+	for {
+		select {
+		case <-f.tomb.Dying():
+			return tomb.ErrDying
+		case record, ok := <-tailer.Logs():
+			if !ok {
+				if err := tailer.Err(); err != nil {
+					return errors.Annotate(err, "log tailer died")
+				}
+				return errors.New("log tailer stopped unexpectedly")
+			}
+			data, err := formatter(record)
+			if err != nil {
+				return errors.Annotate(err, "cannot format log record")
+			}
+			if err := conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+				return errors.Trace(err)
+			}
+			if _, err := conn.Write(data); err != nil {
+				return errors.Annotatef(err, "cannot forward logs to %s", f.target)
+			}
+			pos.Advance(record)
+			if err := f.st.SetLogForwardPosition(key, pos); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
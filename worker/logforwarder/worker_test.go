@@ -0,0 +1,259 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder_test
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type workerSuite struct {
+	statetesting.StateSuite
+
+	mu       sync.Mutex
+	records  []*state.LogRecord
+	listener net.Listener
+	received chan string
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.records = nil
+	s.received = make(chan string, 100)
+	s.PatchValue(logforwarder.NewLogTailer, func(st state.LoggingState, params *state.LogTailerParams) state.LogTailer {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		records := make([]*state.LogRecord, len(s.records))
+		copy(records, s.records)
+		return newFakeLogTailer(records, params)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.listener = listener
+	s.AddCleanup(func(*gc.C) { listener.Close() })
+	go s.accept()
+}
+
+// accept reads octet-counted syslog messages from each connection
+// made to the test listener, and sends them on s.received.
+func (s *workerSuite) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			r := bufio.NewReader(conn)
+			for {
+				lenStr, err := r.ReadString(' ')
+				if err != nil {
+					return
+				}
+				n, err := strconv.Atoi(strings.TrimSuffix(lenStr, " "))
+				if err != nil {
+					return
+				}
+				msg := make([]byte, n)
+				if _, err := io.ReadFull(r, msg); err != nil {
+					return
+				}
+				s.received <- string(msg)
+			}
+		}()
+	}
+}
+
+func (s *workerSuite) addRecords(messages ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := time.Now().Add(time.Second)
+	if len(s.records) > 0 {
+		t = s.records[len(s.records)-1].Time
+	}
+	for _, message := range messages {
+		s.records = append(s.records, &state.LogRecord{
+			Id: newObjectId(t),
+			// Records are stored in the database with
+			// millisecond precision.
+			Time:     t.Truncate(time.Millisecond),
+			Entity:   "machine-0",
+			Module:   "juju.worker",
+			Location: "worker.go:1",
+			Level:    loggo.INFO,
+			Message:  message,
+		})
+	}
+}
+
+func (s *workerSuite) enableForwarding(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"log-forward-address": s.listener.Addr().String(),
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *workerSuite) startWorker(c *gc.C) worker.Worker {
+	w := logforwarder.New(s.State)
+	s.AddCleanup(func(*gc.C) { worker.Stop(w) })
+	return w
+}
+
+func (s *workerSuite) assertReceived(c *gc.C, messages ...string) {
+	for _, message := range messages {
+		select {
+		case msg := <-s.received:
+			c.Assert(msg, jc.HasSuffix, "] "+message)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for %q", message)
+		}
+	}
+}
+
+func (s *workerSuite) assertNothingReceived(c *gc.C) {
+	select {
+	case msg := <-s.received:
+		c.Fatalf("unexpected message %q", msg)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *workerSuite) TestNotForwardingWithoutAddress(c *gc.C) {
+	s.addRecords("one")
+	s.startWorker(c)
+	s.assertNothingReceived(c)
+}
+
+func (s *workerSuite) TestForwardsLogs(c *gc.C) {
+	s.enableForwarding(c)
+	s.addRecords("one", "two")
+	s.startWorker(c)
+	s.assertReceived(c, "one", "two")
+	s.assertNothingReceived(c)
+
+	target := "syslog://" + s.listener.Addr().String()
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		pos, err := s.State.LogForwardPosition(target)
+		c.Assert(err, jc.ErrorIsNil)
+		if pos.Id == s.records[1].Id {
+			return
+		}
+	}
+	c.Fatalf("position not recorded")
+}
+
+func (s *workerSuite) TestResumesFromPosition(c *gc.C) {
+	s.enableForwarding(c)
+	s.addRecords("one", "two")
+	w := s.startWorker(c)
+	s.assertReceived(c, "one", "two")
+	err := worker.Stop(w)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The new records share a timestamp with those already
+	// forwarded, but are not duplicates of them.
+	s.addRecords("three", "four")
+	s.startWorker(c)
+	s.assertReceived(c, "three", "four")
+	s.assertNothingReceived(c)
+}
+
+func (s *workerSuite) TestConnectionFailure(c *gc.C) {
+	s.listener.Close()
+	s.enableForwarding(c)
+	w := s.startWorker(c)
+	select {
+	case <-waitDone(w):
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("worker did not stop")
+	}
+	c.Assert(w.Wait(), gc.ErrorMatches, "cannot connect to syslog://.*")
+}
+
+func waitDone(w worker.Worker) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		w.Wait()
+		close(done)
+	}()
+	return done
+}
+
+// newObjectId returns a new object id, as generated for a log record
+// written at the given time.
+func newObjectId(t time.Time) bson.ObjectId {
+	id := []byte(string(bson.NewObjectId()))
+	binary.BigEndian.PutUint32(id[:4], uint32(t.Unix()))
+	return bson.ObjectId(id)
+}
+
+// fakeLogTailer is a state.LogTailer which returns those of a fixed
+// set of records that match its AfterId.
+type fakeLogTailer struct {
+	tomb tomb.Tomb
+	logs chan *state.LogRecord
+}
+
+func newFakeLogTailer(records []*state.LogRecord, params *state.LogTailerParams) state.LogTailer {
+	t := &fakeLogTailer{logs: make(chan *state.LogRecord)}
+	go func() {
+		defer t.tomb.Done()
+		defer close(t.logs)
+		for _, record := range records {
+			if record.Id <= params.AfterId {
+				continue
+			}
+			select {
+			case <-t.tomb.Dying():
+				return
+			case t.logs <- record:
+			}
+		}
+		<-t.tomb.Dying()
+	}()
+	return t
+}
+
+func (t *fakeLogTailer) Logs() <-chan *state.LogRecord {
+	return t.logs
+}
+
+func (t *fakeLogTailer) Dying() <-chan struct{} {
+	return t.tomb.Dying()
+}
+
+func (t *fakeLogTailer) Stop() error {
+	t.tomb.Kill(nil)
+	return t.tomb.Wait()
+}
+
+func (t *fakeLogTailer) Err() error {
+	return t.tomb.Err()
+}
@@ -3,4 +3,8 @@
 
 package machiner
 
-var InterfaceAddrs = &interfaceAddrs
+var (
+	InterfaceAddrs   = &interfaceAddrs
+	NewAgentServices = &newAgentServices
+	RemoveOldService = removeOldService
+)
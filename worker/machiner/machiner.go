@@ -24,13 +24,23 @@ type Machiner struct {
 	tag                    names.MachineTag
 	machine                Machine
 	ignoreAddressesOnStart bool
+	services               AgentServices
+
+	// upgradeSeriesStatus is the stage of the machine's series
+	// upgrade that the machiner last acted upon.
+	upgradeSeriesStatus params.UpgradeSeriesStatus
 }
 
 // NewMachiner returns a Worker that will wait for the identified machine
 // to become Dying and make it Dead; or until the machine becomes Dead by
 // other means.
 func NewMachiner(st MachineAccessor, agentConfig agent.Config, ignoreAddressesOnStart bool) worker.Worker {
-	mr := &Machiner{st: st, tag: agentConfig.Tag().(names.MachineTag), ignoreAddressesOnStart: ignoreAddressesOnStart}
+	mr := &Machiner{
+		st:                     st,
+		tag:                    agentConfig.Tag().(names.MachineTag),
+		ignoreAddressesOnStart: ignoreAddressesOnStart,
+		services:               newAgentServices(agentConfig),
+	}
 	return worker.NewNotifyWorker(mr)
 }
 
@@ -109,7 +119,7 @@ func (mr *Machiner) Handle(_ <-chan struct{}) error {
 	}
 	life := mr.machine.Life()
 	if life == params.Alive {
-		return nil
+		return mr.handleUpgradeSeries()
 	}
 	logger.Debugf("%q is now %s", mr.tag, life)
 	if err := mr.machine.SetStatus(params.StatusStopped, "", nil); err != nil {
@@ -134,6 +144,41 @@ func (mr *Machiner) Handle(_ <-chan struct{}) error {
 	return worker.ErrTerminateAgent
 }
 
+// handleUpgradeSeries acts on the progress of the machine's series
+// upgrade. Once the units have prepared for the upgrade, their agents
+// are stopped; once the operating system has been upgraded, the agent
+// services are rewritten for the new init system and the units are
+// left to complete the upgrade.
+func (mr *Machiner) handleUpgradeSeries() error {
+	status, series, err := mr.machine.UpgradeSeriesStatus()
+	if params.IsCodeNotImplemented(err) {
+		// The API server does not support series upgrades.
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "%s failed to get series upgrade status", mr.tag)
+	}
+	if status == mr.upgradeSeriesStatus {
+		return nil
+	}
+	switch status {
+	case params.UpgradeSeriesPrepareCompleted:
+		logger.Infof("%q prepared for upgrade to %s; stopping unit agents", mr.tag, series)
+		if err := mr.services.StopUnitAgents(); err != nil {
+			return errors.Annotate(err, "stopping unit agents")
+		}
+	case params.UpgradeSeriesCompleteStarted:
+		logger.Infof("%q completing upgrade to %s", mr.tag, series)
+		if err := mr.services.WriteAgentServices(series); err != nil {
+			return errors.Annotatef(err, "writing agent services for %s", series)
+		}
+		if err := mr.machine.StartUnitUpgradeSeriesCompletion(); err != nil {
+			return errors.Annotatef(err, "%s failed to complete series upgrade", mr.tag)
+		}
+	}
+	mr.upgradeSeriesStatus = status
+	return nil
+}
+
 func (mr *Machiner) TearDown() error {
 	// Nothing to do here.
 	return nil
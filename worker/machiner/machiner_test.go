@@ -6,6 +6,7 @@ package machiner_test
 import (
 	"io/ioutil"
 	"net"
+	"os"
 	"path/filepath"
 	stdtesting "testing"
 	"time"
@@ -21,6 +22,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/service/upstart"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker"
@@ -112,6 +114,67 @@ func (s *MachinerSuite) TestMachinerStorageAttached(c *gc.C) {
 	}})
 }
 
+func (s *MachinerSuite) TestMachinerUpgradeSeries(c *gc.C) {
+	services := &mockAgentServices{}
+	s.PatchValue(machiner.NewAgentServices, func(agent.Config) machiner.AgentServices {
+		return services
+	})
+	s.accessor.machine.upgradeSeriesStatus = []params.UpgradeSeriesStatus{
+		params.UpgradeSeriesPrepareStarted,
+		params.UpgradeSeriesPrepareCompleted,
+		params.UpgradeSeriesPrepareCompleted,
+		params.UpgradeSeriesCompleteStarted,
+	}
+
+	worker := machiner.NewMachiner(s.accessor, s.agentConfig, false)
+	for i := 0; i < 4; i++ {
+		s.accessor.machine.watcher.changes <- struct{}{}
+	}
+	worker.Kill()
+	c.Check(worker.Wait(), jc.ErrorIsNil)
+
+	// The unit agents are stopped once, when the units have
+	// prepared; the services are written once the machine's
+	// series upgrade is being completed.
+	services.CheckCalls(c, []gitjujutesting.StubCall{{
+		FuncName: "StopUnitAgents",
+	}, {
+		FuncName: "WriteAgentServices",
+		Args:     []interface{}{"xenial"},
+	}})
+	s.accessor.machine.CheckCallNames(c,
+		"SetMachineAddresses", "SetStatus", "Watch",
+		"Refresh", "Life", "UpgradeSeriesStatus",
+		"Refresh", "Life", "UpgradeSeriesStatus",
+		"Refresh", "Life", "UpgradeSeriesStatus",
+		"Refresh", "Life", "UpgradeSeriesStatus",
+		"StartUnitUpgradeSeriesCompletion",
+	)
+}
+
+func (s *MachinerSuite) TestRemoveOldService(c *gc.C) {
+	initDir := c.MkDir()
+	s.PatchValue(&upstart.InitDir, initDir)
+	confPath := filepath.Join(initDir, "jujud-unit-wordpress-0.conf")
+	err := ioutil.WriteFile(confPath, []byte("# upstart job"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Nothing is removed for a series that still uses upstart.
+	err = machiner.RemoveOldService("jujud-unit-wordpress-0", "trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(confPath)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machiner.RemoveOldService("jujud-unit-wordpress-0", "xenial")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(confPath)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+
+	// It is not an error for there to be nothing to remove.
+	err = machiner.RemoveOldService("jujud-unit-wordpress-0", "xenial")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 // worstCase is used for timeouts when timing out
 // will fail the test. Raising this value should
 // not affect the overall running time of the tests
@@ -38,6 +38,10 @@ type mockMachine struct {
 	gitjujutesting.Stub
 	watcher mockWatcher
 	life    params.Life
+
+	// upgradeSeriesStatus holds the series upgrade status
+	// to return from each call to UpgradeSeriesStatus.
+	upgradeSeriesStatus []params.UpgradeSeriesStatus
 }
 
 func (m *mockMachine) Refresh() error {
@@ -73,6 +77,34 @@ func (m *mockMachine) Watch() (watcher.NotifyWatcher, error) {
 	return &m.watcher, nil
 }
 
+func (m *mockMachine) UpgradeSeriesStatus() (params.UpgradeSeriesStatus, string, error) {
+	m.MethodCall(m, "UpgradeSeriesStatus")
+	var status params.UpgradeSeriesStatus
+	if len(m.upgradeSeriesStatus) > 0 {
+		status, m.upgradeSeriesStatus = m.upgradeSeriesStatus[0], m.upgradeSeriesStatus[1:]
+	}
+	return status, "xenial", m.NextErr()
+}
+
+func (m *mockMachine) StartUnitUpgradeSeriesCompletion() error {
+	m.MethodCall(m, "StartUnitUpgradeSeriesCompletion")
+	return m.NextErr()
+}
+
+type mockAgentServices struct {
+	gitjujutesting.Stub
+}
+
+func (s *mockAgentServices) StopUnitAgents() error {
+	s.MethodCall(s, "StopUnitAgents")
+	return s.NextErr()
+}
+
+func (s *mockAgentServices) WriteAgentServices(series string) error {
+	s.MethodCall(s, "WriteAgentServices", series)
+	return s.NextErr()
+}
+
 type mockMachineAccessor struct {
 	gitjujutesting.Stub
 	machine mockMachine
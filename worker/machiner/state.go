@@ -23,6 +23,8 @@ type Machine interface {
 	SetMachineAddresses(addresses []network.Address) error
 	SetStatus(status params.Status, info string, data map[string]interface{}) error
 	Watch() (watcher.NotifyWatcher, error)
+	UpgradeSeriesStatus() (params.UpgradeSeriesStatus, string, error)
+	StartUnitUpgradeSeriesCompletion() error
 }
 
 type APIMachineAccessor struct {
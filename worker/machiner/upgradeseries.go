@@ -0,0 +1,168 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machiner
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/shell"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/service/upstart"
+)
+
+// AgentServices manages the init system services of the agents running
+// on a machine whose series is being upgraded.
+type AgentServices interface {
+	// StopUnitAgents stops the services of the machine's unit agents.
+	StopUnitAgents() error
+
+	// WriteAgentServices writes the services of the machine's agents
+	// for the init system of the given series, removes those written
+	// for the init system the machine used before, and starts the unit
+	// agents.
+	WriteAgentServices(series string) error
+}
+
+var newAgentServices = func(config agent.Config) AgentServices {
+	return &agentServices{config}
+}
+
+// agentServices implements AgentServices using the service package.
+type agentServices struct {
+	config agent.Config
+}
+
+// unitAgents returns the names of the units deployed to the machine,
+// as recorded by their agent directories.
+func (s *agentServices) unitAgents() ([]string, error) {
+	fis, err := ioutil.ReadDir(filepath.Join(s.config.DataDir(), "agents"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var units []string
+	for _, fi := range fis {
+		tag, err := names.ParseUnitTag(fi.Name())
+		if err != nil {
+			continue
+		}
+		units = append(units, tag.Id())
+	}
+	return units, nil
+}
+
+func serviceName(tag names.Tag) string {
+	return "jujud-" + tag.String()
+}
+
+// StopUnitAgents is part of the AgentServices interface.
+func (s *agentServices) StopUnitAgents() error {
+	units, err := s.unitAgents()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, unitName := range units {
+		name := serviceName(names.NewUnitTag(unitName))
+		svc, err := service.DiscoverService(name, common.Conf{})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		logger.Infof("stopping %s for series upgrade", name)
+		if err := svc.Stop(); err != nil {
+			return errors.Annotatef(err, "stopping %s", name)
+		}
+	}
+	return nil
+}
+
+// WriteAgentServices is part of the AgentServices interface.
+func (s *agentServices) WriteAgentServices(series string) error {
+	renderer, err := shell.NewRenderer("")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	dataDir, logDir := s.config.DataDir(), s.config.LogDir()
+	containerType := s.config.Value(agent.ContainerType)
+
+	// The machine agent's service is written but not started; it is
+	// started by the new init system when the machine reboots.
+	machineTag := s.config.Tag()
+	info := service.NewMachineAgentInfo(machineTag.Id(), dataDir, logDir)
+	conf := service.AgentConf(info, renderer)
+	if _, err := writeService(serviceName(machineTag), conf, series); err != nil {
+		return errors.Trace(err)
+	}
+
+	units, err := s.unitAgents()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, unitName := range units {
+		info := service.NewUnitAgentInfo(unitName, dataDir, logDir)
+		conf := service.ContainerAgentConf(info, renderer, containerType)
+		svc, err := writeService(serviceName(names.NewUnitTag(unitName)), conf, series)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := svc.Start(); err != nil {
+			return errors.Annotatef(err, "starting %s", svc.Name())
+		}
+	}
+	return nil
+}
+
+// writeService installs the named service for the init system of the
+// given series, unless it is already installed, and removes it from the
+// init system the machine used before.
+func writeService(name string, conf common.Conf, series string) (service.Service, error) {
+	svc, err := service.NewService(name, conf, series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	exists, err := svc.Exists()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !exists {
+		logger.Infof("writing %s for %s", name, series)
+		if err := svc.Install(); err != nil {
+			return nil, errors.Annotatef(err, "writing %s", name)
+		}
+	}
+	if err := removeOldService(name, series); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return svc, nil
+}
+
+// removeOldService removes the named service's upstart configuration,
+// which is left behind when the machine moves to a series that uses
+// systemd. The service is not stopped: upstart is no longer running.
+func removeOldService(name, series string) error {
+	initSystem, err := service.VersionInitSystem(series)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if initSystem != service.InitSystemSystemd {
+		return nil
+	}
+	old := upstart.NewService(name, common.Conf{})
+	installed, err := old.Installed()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !installed {
+		return nil
+	}
+	logger.Infof("removing upstart configuration of %s", name)
+	if err := old.Remove(); err != nil {
+		return errors.Annotatef(err, "removing upstart configuration of %s", name)
+	}
+	return nil
+}
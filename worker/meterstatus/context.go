@@ -62,6 +62,11 @@ func (ctx *limitedContext) UnitName() string {
 // SetProcess implements runner.Context.
 func (ctx *limitedContext) SetProcess(process *os.Process) {}
 
+// CancelAction implements runner.Context.
+func (ctx *limitedContext) CancelAction() error {
+	return jujuc.ErrRestrictedContext
+}
+
 // ActionData implements runner.Context.
 func (ctx *limitedContext) ActionData() (*context.ActionData, error) {
 	return nil, jujuc.ErrRestrictedContext
@@ -70,6 +70,11 @@ func (ctx *hookContext) addJujuUnitsMetric() error {
 // SetProcess implements runner.Context.
 func (ctx *hookContext) SetProcess(process *os.Process) {}
 
+// CancelAction implements runner.Context.
+func (ctx *hookContext) CancelAction() error {
+	return jujuc.ErrRestrictedContext
+}
+
 // ActionData implements runner.Context.
 func (ctx *hookContext) ActionData() (*context.ActionData, error) {
 	return nil, jujuc.ErrRestrictedContext
@@ -0,0 +1,184 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package oplogcapture
+
+import (
+	"io"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/set"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.oplogcapture")
+
+// DefaultCheckInterval is the default time between checks for whether
+// the oplog is due to be captured.
+const DefaultCheckInterval = time.Minute
+
+// The following are replaced in tests.
+var (
+	now = time.Now
+
+	newBackups = func(st *state.State, cfg *config.Config, dataDir string) (backups.Backups, io.Closer, error) {
+		stor, err := backups.OpenStorage(st, cfg, dataDir)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		return backups.NewBackups(stor), stor, nil
+	}
+
+	captureOplog = backups.CaptureOplog
+)
+
+// New returns a worker which periodically wakes up to capture the
+// database operations made since the latest backup, if they are due to
+// be captured according to the environment's backup-oplog-interval
+// setting, and to remove those kept for backups that have since been
+// removed.
+//
+// The operations are only captured in the state server environment; in
+// any other environment, the worker does nothing. This worker is
+// intended to run just once per environment.
+func New(st *state.State, dataDir string, checkInterval time.Duration) worker.Worker {
+	w := &captureWorker{
+		st:            st,
+		dataDir:       dataDir,
+		checkInterval: checkInterval,
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+type captureWorker struct {
+	st            *state.State
+	dataDir       string
+	checkInterval time.Duration
+
+	// last holds the time of the last attempt to capture the oplog.
+	last time.Time
+}
+
+func (w *captureWorker) loop(stopCh <-chan struct{}) error {
+	if !w.st.IsStateServer() {
+		<-stopCh
+		return tomb.ErrDying
+	}
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(w.checkInterval):
+			if err := w.check(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// check captures the oplog, if it is due. A failure to capture it is
+// logged rather than returned, and the capture is retried when it is
+// next due.
+func (w *captureWorker) check() error {
+	cfg, err := w.st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	interval := cfg.BackupOplogInterval()
+	if interval == 0 {
+		return nil
+	}
+	t := now()
+	if t.Before(w.last.Add(interval)) {
+		return nil
+	}
+	w.last = t
+
+	if err := w.capture(cfg); err != nil {
+		logger.Errorf("cannot capture oplog: %v", err)
+	}
+	return nil
+}
+
+// capture captures the oplog entries made since the latest backup, and
+// removes those kept for backups that no longer exist.
+func (w *captureWorker) capture(cfg *config.Config) error {
+	b, closer, err := newBackups(w.st, cfg, w.dataDir)
+	if err != nil {
+		return errors.Annotate(err, "cannot open backup storage")
+	}
+	defer closer.Close()
+	metas, err := b.List()
+	if err != nil {
+		return errors.Annotate(err, "cannot list backups")
+	}
+	stor, err := backups.OpenOplogStorage(w.st, cfg, w.dataDir)
+	if err != nil {
+		return errors.Annotate(err, "cannot open oplog storage")
+	}
+	defer stor.Close()
+
+	if err := prune(stor, metas); err != nil {
+		return errors.Trace(err)
+	}
+
+	var latest *backups.Metadata
+	for _, meta := range metas {
+		if meta.Stored() == nil {
+			// The backup is still being made.
+			continue
+		}
+		if latest == nil || meta.Started.After(latest.Started) {
+			latest = meta
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+
+	var key *backups.Key
+	if encryptionKey := cfg.BackupEncryptionKey(); encryptionKey != "" {
+		if key, err = backups.ParseKey([]byte(encryptionKey)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	session := w.st.MongoSession().Copy()
+	defer session.Close()
+	seg, err := captureOplog(session, stor, latest, key)
+	if err != nil {
+		return errors.Annotatef(err, "backup %q", latest.ID())
+	}
+	if seg != nil {
+		logger.Debugf("captured %d oplog entries for backup %q", seg.Entries, latest.ID())
+	}
+	return nil
+}
+
+// prune removes the oplog entries kept for backups that no longer exist.
+func prune(stor *backups.OplogStorage, metas []*backups.Metadata) error {
+	ids, err := stor.BackupIDs()
+	if err != nil {
+		return errors.Annotate(err, "cannot list oplog segments")
+	}
+	existing := set.NewStrings()
+	for _, meta := range metas {
+		existing.Add(meta.ID())
+	}
+	for _, id := range ids {
+		if existing.Contains(id) {
+			continue
+		}
+		if err := stor.RemoveAll(id); err != nil {
+			return errors.Annotatef(err, "cannot remove oplog segments of backup %q", id)
+		}
+		logger.Infof("removed oplog segments of removed backup %q", id)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package oplogcapture_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/oplogcapture"
+)
+
+func TestPackage(t *stdtesting.T) {
+	testing.MgoTestPackage(t)
+}
+
+var _ = gc.Suite(&suite{})
+
+type suite struct {
+	statetesting.StateSuite
+	backups  *fakeBackups
+	captured chan capturedOplog
+}
+
+type capturedOplog struct {
+	id  string
+	key *backups.Key
+}
+
+func (s *suite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.backups = &fakeBackups{}
+	s.PatchValue(oplogcapture.NewBackups, func(*state.State, *config.Config, string) (backups.Backups, io.Closer, error) {
+		return s.backups, ioutil.NopCloser(nil), nil
+	})
+	s.captured = make(chan capturedOplog, 10)
+	s.PatchValue(oplogcapture.CaptureOplog, func(_ *mgo.Session, _ *backups.OplogStorage, meta *backups.Metadata, key *backups.Key) (*backups.OplogSegment, error) {
+		s.captured <- capturedOplog{meta.ID(), key}
+		return &backups.OplogSegment{BackupID: meta.ID()}, nil
+	})
+}
+
+func (s *suite) startWorker(c *gc.C) {
+	// Speed up the check interval for testing.
+	w := oplogcapture.New(s.State, "/var/lib/juju", time.Millisecond)
+	s.AddCleanup(func(*gc.C) {
+		w.Kill()
+		c.Assert(w.Wait(), jc.ErrorIsNil)
+	})
+}
+
+func (s *suite) setInterval(c *gc.C, attrs map[string]interface{}) {
+	err := s.State.UpdateEnvironConfig(attrs, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *suite) waitForCapture(c *gc.C) capturedOplog {
+	select {
+	case captured := <-s.captured:
+		return captured
+	case <-time.After(testing.LongWait):
+		c.Fatal("oplog was not captured")
+	}
+	panic("unreachable")
+}
+
+func (s *suite) TestNoCaptureWithoutInterval(c *gc.C) {
+	s.backups.add("backup", time.Now().Add(-time.Hour))
+	s.startWorker(c)
+
+	select {
+	case <-s.captured:
+		c.Fatal("oplog was captured")
+	case <-time.After(testing.ShortWait):
+	}
+}
+
+func (s *suite) TestCapturesForLatestBackup(c *gc.C) {
+	s.setInterval(c, map[string]interface{}{"backup-oplog-interval": "1h"})
+	s.backups.add("older", time.Now().Add(-2*time.Hour))
+	s.backups.add("latest", time.Now().Add(-time.Hour))
+	// A backup that is still being made has nothing stored yet.
+	s.backups.metas = append(s.backups.metas, backups.NewMetadata())
+
+	s.startWorker(c)
+
+	captured := s.waitForCapture(c)
+	c.Assert(captured.id, gc.Equals, "latest")
+	c.Assert(captured.key, gc.IsNil)
+
+	// The oplog is not captured again until the interval has passed.
+	select {
+	case <-s.captured:
+		c.Fatal("oplog was captured again")
+	case <-time.After(testing.ShortWait):
+	}
+}
+
+func (s *suite) TestCapturesWithEnvironmentKey(c *gc.C) {
+	s.setInterval(c, map[string]interface{}{
+		"backup-oplog-interval": "1h",
+		"backup-encryption-key": testing.OpenPGPPublicKey,
+	})
+	s.backups.add("latest", time.Now().Add(-time.Hour))
+
+	s.startWorker(c)
+
+	captured := s.waitForCapture(c)
+	c.Assert(captured.key, gc.NotNil)
+	c.Assert(captured.key.Fingerprint(), gc.Equals, testing.OpenPGPKeyFingerprint)
+}
+
+func (s *suite) TestCaptureRetried(c *gc.C) {
+	s.setInterval(c, map[string]interface{}{"backup-oplog-interval": "1h"})
+	s.backups.add("latest", time.Now().Add(-time.Hour))
+	s.PatchValue(oplogcapture.CaptureOplog, func(_ *mgo.Session, _ *backups.OplogStorage, meta *backups.Metadata, key *backups.Key) (*backups.OplogSegment, error) {
+		s.captured <- capturedOplog{meta.ID(), key}
+		return nil, errors.New("oplog entries since then are no longer available")
+	})
+	later := time.Now()
+	s.PatchValue(oplogcapture.Now, func() time.Time {
+		later = later.Add(time.Hour)
+		return later
+	})
+
+	s.startWorker(c)
+
+	s.waitForCapture(c)
+	s.waitForCapture(c)
+}
+
+func (s *suite) TestRemovesSegmentsOfRemovedBackups(c *gc.C) {
+	s.setInterval(c, map[string]interface{}{"backup-oplog-interval": "1h"})
+	s.backups.add("latest", time.Now().Add(-time.Hour))
+
+	stor := backups.NewOplogStorage(s.State, nil, "")
+	defer stor.Close()
+	data := "<oplog>"
+	for _, id := range []string{"latest", "removed"} {
+		err := stor.Add(&backups.OplogSegment{BackupID: id, Size: int64(len(data))}, bytes.NewBufferString(data))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	s.startWorker(c)
+	s.waitForCapture(c)
+
+	ids, err := stor.BackupIDs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, jc.DeepEquals, []string{"latest"})
+}
+
+type fakeBackups struct {
+	backups.Backups
+	metas []*backups.Metadata
+}
+
+func (b *fakeBackups) add(id string, started time.Time) {
+	meta := backups.NewMetadata()
+	meta.SetID(id)
+	meta.Started = started.UTC()
+	stored := started.Add(time.Minute).UTC()
+	meta.SetStored(&stored)
+	b.metas = append(b.metas, meta)
+}
+
+func (b *fakeBackups) List() ([]*backups.Metadata, error) {
+	return b.metas, nil
+}
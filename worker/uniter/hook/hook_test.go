@@ -50,6 +50,8 @@ var validateTests = []struct {
 	{hook.Info{Kind: hooks.StorageAttached}, `invalid storage ID ""`},
 	{hook.Info{Kind: hooks.StorageAttached, StorageId: "data/0"}, ""},
 	{hook.Info{Kind: hooks.StorageDetaching, StorageId: "data/0"}, ""},
+	{hook.Info{Kind: hook.PreSeriesUpgrade}, ""},
+	{hook.Info{Kind: hook.PostSeriesUpgrade}, ""},
 }
 
 func (s *InfoSuite) TestValidate(c *gc.C) {
@@ -63,6 +63,10 @@ func (opc *operationCallbacks) CommitHook(hi hook.Info) error {
 		return opc.u.storage.CommitHook(hi)
 	case hi.Kind == hooks.ConfigChanged:
 		opc.u.ranConfigChanged = true
+	case hi.Kind == hook.PreSeriesUpgrade:
+		return opc.u.unit.SetUpgradeSeriesStatus(params.UpgradeSeriesPrepareCompleted)
+	case hi.Kind == hook.PostSeriesUpgrade:
+		return opc.u.unit.SetUpgradeSeriesStatus(params.UpgradeSeriesCompleted)
 	}
 	return nil
 }
@@ -105,6 +109,21 @@ func (opc *operationCallbacks) FailAction(actionId, message string) error {
 	return err
 }
 
+// ActionCancelled is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) ActionCancelled(actionId string) (<-chan struct{}, func()) {
+	if opc.u.actionCancelled == nil {
+		// There is no remote state watcher, so the action
+		// cannot be cancelled.
+		return nil, func() {}
+	}
+	return opc.u.actionCancelled(actionId)
+}
+
+// GetCharmModifiedVersion is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) GetCharmModifiedVersion() (int, error) {
+	return opc.u.getServiceCharmModifiedVersion()
+}
+
 // GetArchiveInfo is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) GetArchiveInfo(charmURL *corecharm.URL) (charm.BundleInfo, error) {
 	ch, err := opc.u.st.Charm(charmURL)
@@ -23,6 +23,10 @@ type deploy struct {
 	revert   bool
 	resolved bool
 
+	// charmModifiedVersion is read in Prepare, before the charm is
+	// staged, so that later changes to the charm trigger another upgrade.
+	charmModifiedVersion int
+
 	callbacks Callbacks
 	deployer  charm.Deployer
 	abort     <-chan struct{}
@@ -61,6 +65,11 @@ func (d *deploy) Prepare(state State) (*State, error) {
 			return nil, errors.Trace(err)
 		}
 	}
+	charmModifiedVersion, err := d.callbacks.GetCharmModifiedVersion()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	d.charmModifiedVersion = charmModifiedVersion
 	info, err := d.callbacks.GetArchiveInfo(d.charmURL)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -123,12 +132,14 @@ func (d *deploy) checkAlreadyDone(state State) error {
 }
 
 func (d *deploy) getState(state State, step Step) *State {
-	return stateChange{
+	newState := stateChange{
 		Kind:     d.kind,
 		Step:     step,
 		CharmURL: d.charmURL,
 		Hook:     d.interruptedHook(state),
 	}.apply(state)
+	newState.CharmModifiedVersion = d.charmModifiedVersion
+	return newState
 }
 
 func (d *deploy) interruptedHook(state State) *hook.Info {
@@ -102,7 +102,8 @@ func (s *DeploySuite) TestNotifyDeployerError_ResolvedUpgrade(c *gc.C) {
 
 func (s *DeploySuite) testPrepareArchiveInfoError(c *gc.C, newDeploy newDeploy) {
 	callbacks := &DeployCallbacks{
-		MockGetArchiveInfo: &MockGetArchiveInfo{err: errors.New("pew")},
+		MockGetCharmModifiedVersion: &MockGetCharmModifiedVersion{},
+		MockGetArchiveInfo:          &MockGetArchiveInfo{err: errors.New("pew")},
 	}
 	deployer := &MockDeployer{
 		MockNotifyRevert:   &MockNoArgs{},
@@ -121,6 +122,22 @@ func (s *DeploySuite) testPrepareArchiveInfoError(c *gc.C, newDeploy newDeploy)
 	c.Check(callbacks.MockGetArchiveInfo.gotCharmURL, gc.DeepEquals, curl("cs:quantal/hive-23"))
 }
 
+func (s *DeploySuite) TestPrepareCharmModifiedVersionError(c *gc.C) {
+	callbacks := &DeployCallbacks{
+		MockGetCharmModifiedVersion: &MockGetCharmModifiedVersion{err: errors.New("zap")},
+	}
+	factory := operation.NewFactory(operation.FactoryParams{
+		Deployer:  &MockDeployer{},
+		Callbacks: callbacks,
+	})
+	op, err := factory.NewUpgrade(curl("cs:quantal/hive-23"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Prepare(operation.State{})
+	c.Check(newState, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, "zap")
+}
+
 func (s *DeploySuite) TestPrepareArchiveInfoError_Install(c *gc.C) {
 	s.testPrepareArchiveInfoError(c, (operation.Factory).NewInstall)
 }
@@ -139,7 +156,8 @@ func (s *DeploySuite) TestPrepareArchiveInfoError_ResolvedUpgrade(c *gc.C) {
 
 func (s *DeploySuite) testPrepareStageError(c *gc.C, newDeploy newDeploy) {
 	callbacks := &DeployCallbacks{
-		MockGetArchiveInfo: &MockGetArchiveInfo{info: &MockBundleInfo{}},
+		MockGetCharmModifiedVersion: &MockGetCharmModifiedVersion{},
+		MockGetArchiveInfo:          &MockGetArchiveInfo{info: &MockBundleInfo{}},
 	}
 	deployer := &MockDeployer{
 		MockNotifyRevert:   &MockNoArgs{},
@@ -180,8 +198,9 @@ func (s *DeploySuite) TestPrepareStageError_ResolvedUpgrade(c *gc.C) {
 
 func (s *DeploySuite) testPrepareSetCharmError(c *gc.C, newDeploy newDeploy) {
 	callbacks := &DeployCallbacks{
-		MockGetArchiveInfo:  &MockGetArchiveInfo{},
-		MockSetCurrentCharm: &MockSetCurrentCharm{err: errors.New("blargh")},
+		MockGetCharmModifiedVersion: &MockGetCharmModifiedVersion{},
+		MockGetArchiveInfo:          &MockGetArchiveInfo{},
+		MockSetCurrentCharm:         &MockSetCurrentCharm{err: errors.New("blargh")},
 	}
 	deployer := &MockDeployer{
 		MockNotifyRevert:   &MockNoArgs{},
@@ -335,6 +354,34 @@ func (s *DeploySuite) TestPrepareSuccess_Upgrade_PreserveNoHook(c *gc.C) {
 	}
 }
 
+func (s *DeploySuite) TestPrepareSuccess_RecordsCharmModifiedVersion(c *gc.C) {
+	callbacks := NewDeployCallbacks()
+	callbacks.MockGetCharmModifiedVersion.version = 3
+	factory := operation.NewFactory(operation.FactoryParams{
+		Deployer:  NewMockDeployer(),
+		Callbacks: callbacks,
+	})
+	op, err := factory.NewUpgrade(curl("cs:quantal/nyancat-4"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Prepare(operation.State{
+		Kind:                 operation.Continue,
+		Step:                 operation.Pending,
+		CharmModifiedVersion: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(newState, gc.DeepEquals, &operation.State{
+		Kind:                 operation.Upgrade,
+		Step:                 operation.Pending,
+		CharmURL:             curl("cs:quantal/nyancat-4"),
+		CharmModifiedVersion: 3,
+	})
+
+	newState, err = op.Commit(*newState)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(newState.CharmModifiedVersion, gc.Equals, 3)
+}
+
 func (s *DeploySuite) testExecuteConflictError(c *gc.C, newDeploy newDeploy) {
 	callbacks := NewDeployCallbacks()
 	deployer := &MockDeployer{
@@ -149,6 +149,16 @@ type Callbacks interface {
 	// RunActions operations.
 	FailAction(actionId, message string) error
 
+	// ActionCancelled returns a channel which is closed if the supplied
+	// action is cancelled while running, and a function which must be
+	// called once the action is no longer running. It's only used by
+	// RunAction operations.
+	ActionCancelled(actionId string) (<-chan struct{}, func())
+
+	// GetCharmModifiedVersion returns the service's charm modified version.
+	// It's only used by Deploy operations.
+	GetCharmModifiedVersion() (int, error)
+
 	// GetArchiveInfo is used to find out how to download a charm archive. It's
 	// only used by Deploy operations.
 	GetArchiveInfo(charmURL *corecharm.URL) (charm.BundleInfo, error)
@@ -20,6 +20,9 @@ type runAction struct {
 	name   string
 	runner runner.Runner
 
+	cancelled      <-chan struct{}
+	stopCancelling func()
+
 	RequiresMachineLock
 }
 
@@ -49,12 +52,18 @@ func (ra *runAction) Prepare(state State) (*State, error) {
 		// this should *really* never happen, but let's not panic
 		return nil, errors.Trace(err)
 	}
+	// Watch for cancellation before the action is marked as running,
+	// so that no cancellation can be missed.
+	cancelled, stopCancelling := ra.callbacks.ActionCancelled(ra.actionId)
 	err = rnr.Context().Prepare()
 	if err != nil {
+		stopCancelling()
 		return nil, errors.Trace(err)
 	}
 	ra.name = actionData.Name
 	ra.runner = rnr
+	ra.cancelled = cancelled
+	ra.stopCancelling = stopCancelling
 	return stateChange{
 		Kind:     RunAction,
 		Step:     Pending,
@@ -68,10 +77,24 @@ func (ra *runAction) Prepare(state State) (*State, error) {
 func (ra *runAction) Execute(state State) (*State, error) {
 	message := fmt.Sprintf("running action %s", ra.name)
 
+	defer ra.stopCancelling()
 	if err := ra.callbacks.SetExecutingStatus(message); err != nil {
 		return nil, err
 	}
 
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ra.cancelled:
+			logger.Infof("action %s cancelled", ra.actionId)
+			if err := ra.runner.Context().CancelAction(); err != nil {
+				logger.Errorf("cannot cancel action %s: %v", ra.actionId, err)
+			}
+		case <-done:
+		}
+	}()
+
 	err := ra.runner.RunAction(ra.name)
 	if err != nil {
 		// This indicates an actual error -- an action merely failing should
@@ -4,12 +4,15 @@
 package operation_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/runner"
@@ -74,6 +77,7 @@ func (s *RunActionSuite) TestPrepareErrorActionNotAvailable(c *gc.C) {
 	}
 	factory := operation.NewFactory(operation.FactoryParams{
 		RunnerFactory: runnerFactory,
+		Callbacks:     &RunActionCallbacks{},
 	})
 	op, err := factory.NewAction(someActionId)
 	c.Assert(err, jc.ErrorIsNil)
@@ -90,6 +94,7 @@ func (s *RunActionSuite) TestPrepareErrorOther(c *gc.C) {
 	}
 	factory := operation.NewFactory(operation.FactoryParams{
 		RunnerFactory: runnerFactory,
+		Callbacks:     &RunActionCallbacks{},
 	})
 	op, err := factory.NewAction(someActionId)
 	c.Assert(err, jc.ErrorIsNil)
@@ -111,6 +116,7 @@ func (s *RunActionSuite) TestPrepareCtxCalled(c *gc.C) {
 	}
 	factory := operation.NewFactory(operation.FactoryParams{
 		RunnerFactory: runnerFactory,
+		Callbacks:     &RunActionCallbacks{},
 	})
 	op, err := factory.NewAction(someActionId)
 	c.Assert(err, jc.ErrorIsNil)
@@ -133,6 +139,7 @@ func (s *RunActionSuite) TestPrepareCtxError(c *gc.C) {
 	}
 	factory := operation.NewFactory(operation.FactoryParams{
 		RunnerFactory: runnerFactory,
+		Callbacks:     &RunActionCallbacks{},
 	})
 	op, err := factory.NewAction(someActionId)
 	c.Assert(err, jc.ErrorIsNil)
@@ -147,6 +154,7 @@ func (s *RunActionSuite) TestPrepareSuccessCleanState(c *gc.C) {
 	runnerFactory := NewRunActionRunnerFactory(errors.New("should not call"))
 	factory := operation.NewFactory(operation.FactoryParams{
 		RunnerFactory: runnerFactory,
+		Callbacks:     &RunActionCallbacks{},
 	})
 	op, err := factory.NewAction(someActionId)
 	c.Assert(err, jc.ErrorIsNil)
@@ -165,6 +173,7 @@ func (s *RunActionSuite) TestPrepareSuccessDirtyState(c *gc.C) {
 	runnerFactory := NewRunActionRunnerFactory(errors.New("should not call"))
 	factory := operation.NewFactory(operation.FactoryParams{
 		RunnerFactory: runnerFactory,
+		Callbacks:     &RunActionCallbacks{},
 	})
 	op, err := factory.NewAction(someActionId)
 	c.Assert(err, jc.ErrorIsNil)
@@ -227,6 +236,36 @@ func (s *RunActionSuite) TestExecuteSuccess(c *gc.C) {
 	}
 }
 
+func (s *RunActionSuite) TestExecuteCancelled(c *gc.C) {
+	runnerFactory := NewRunActionRunnerFactory(nil)
+	mockRunner := runnerFactory.MockNewActionRunner.runner
+	mockContext := mockRunner.context.(*MockContext)
+	mockContext.actionCancelled = make(chan struct{})
+	callbacks := &RunActionCallbacks{}
+	mockRunner.MockRunAction.run = func() {
+		close(callbacks.cancelled)
+		select {
+		case <-mockContext.actionCancelled:
+		case <-time.After(coretesting.LongWait):
+			c.Errorf("timed out waiting for action to be cancelled")
+		}
+	}
+	factory := operation.NewFactory(operation.FactoryParams{
+		RunnerFactory: runnerFactory,
+		Callbacks:     callbacks,
+	})
+	op, err := factory.NewAction(someActionId)
+	c.Assert(err, jc.ErrorIsNil)
+	midState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(callbacks.released, jc.IsFalse)
+
+	_, err = op.Execute(*midState)
+	c.Assert(err, jc.ErrorIsNil)
+	mockContext.CheckCallNames(c, "Prepare", "CancelAction")
+	c.Assert(callbacks.released, jc.IsTrue)
+}
+
 func (s *RunActionSuite) TestCommit(c *gc.C) {
 	var stateChangeTests = []struct {
 		description string
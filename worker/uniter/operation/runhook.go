@@ -182,8 +182,9 @@ func (rh *runHook) afterHook(state State) (bool, error) {
 }
 
 // Commit updates relation state to include the fact of the hook's execution,
-// records the impact of start and collect-metrics hooks, and queues follow-up
-// config-changed hooks to directly follow install and upgrade-charm hooks.
+// records the impact of start, collect-metrics and series upgrade hooks,
+// and queues follow-up config-changed hooks to directly follow install and
+// upgrade-charm hooks.
 // Commit is part of the Operation interface.
 func (rh *runHook) Commit(state State) (*State, error) {
 	if err := rh.callbacks.CommitHook(rh.info); err != nil {
@@ -220,6 +221,10 @@ func (rh *runHook) Commit(state State) (*State, error) {
 		newState.Started = true
 	case hooks.Stop:
 		newState.Stopped = true
+	case hook.PreSeriesUpgrade:
+		newState.UpgradeSeriesStatus = params.UpgradeSeriesPrepareCompleted
+	case hook.PostSeriesUpgrade:
+		newState.UpgradeSeriesStatus = params.UpgradeSeriesCompleted
 	}
 
 	return newState, nil
@@ -10,6 +10,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/runner/context"
@@ -528,6 +529,40 @@ func (s *RunHookSuite) TestCommitSuccess_Start_Preserve(c *gc.C) {
 	}
 }
 
+func (s *RunHookSuite) TestCommitSuccess_SeriesUpgrade_SetStatus(c *gc.C) {
+	for i, newHook := range []newHook{
+		(operation.Factory).NewRunHook,
+		(operation.Factory).NewSkipHook,
+	} {
+		c.Logf("variant %d", i)
+		s.testCommitSuccess(c,
+			newHook,
+			hook.Info{Kind: hook.PreSeriesUpgrade},
+			operation.State{Started: true},
+			operation.State{
+				Started:             true,
+				Kind:                operation.Continue,
+				Step:                operation.Pending,
+				UpgradeSeriesStatus: params.UpgradeSeriesPrepareCompleted,
+			},
+		)
+		s.testCommitSuccess(c,
+			newHook,
+			hook.Info{Kind: hook.PostSeriesUpgrade},
+			operation.State{
+				Started:             true,
+				UpgradeSeriesStatus: params.UpgradeSeriesPrepareCompleted,
+			},
+			operation.State{
+				Started:             true,
+				Kind:                operation.Continue,
+				Step:                operation.Pending,
+				UpgradeSeriesStatus: params.UpgradeSeriesCompleted,
+			},
+		)
+	}
+}
+
 func (s *RunHookSuite) testQueueHook_BlankSlate(c *gc.C, cause hooks.Kind) {
 	for i, newHook := range []newHook{
 		(operation.Factory).NewRunHook,
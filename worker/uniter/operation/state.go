@@ -10,6 +10,7 @@ import (
 	"github.com/juju/utils"
 	"gopkg.in/juju/charm.v6-unstable"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/hook"
 )
 
@@ -93,6 +94,16 @@ type State struct {
 	// Charm describes the charm being deployed by an Install or Upgrade
 	// operation, and is otherwise blank.
 	CharmURL *charm.URL `yaml:"charm,omitempty"`
+
+	// CharmModifiedVersion records the service's charm modified version
+	// as of the most recent Install or Upgrade operation, so that changes
+	// made to the charm, such as attaching resources, while the uniter
+	// was not running still cause an upgrade.
+	CharmModifiedVersion int `yaml:"charm-modified-version,omitempty"`
+
+	// UpgradeSeriesStatus records the last stage of a series upgrade
+	// of the unit's machine for which the unit's hook has run.
+	UpgradeSeriesStatus params.UpgradeSeriesStatus `yaml:"upgrade-series-status,omitempty"`
 }
 
 // validate returns an error if the state violates expectations.
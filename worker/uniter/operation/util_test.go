@@ -39,13 +39,27 @@ func (mock *MockSetCurrentCharm) Call(charmURL *corecharm.URL) error {
 	return mock.err
 }
 
+type MockGetCharmModifiedVersion struct {
+	version int
+	err     error
+}
+
+func (mock *MockGetCharmModifiedVersion) Call() (int, error) {
+	return mock.version, mock.err
+}
+
 type DeployCallbacks struct {
 	operation.Callbacks
+	*MockGetCharmModifiedVersion
 	*MockGetArchiveInfo
 	*MockSetCurrentCharm
 	MockInitializeMetricsTimers *MockNoArgs
 }
 
+func (cb *DeployCallbacks) GetCharmModifiedVersion() (int, error) {
+	return cb.MockGetCharmModifiedVersion.Call()
+}
+
 func (cb *DeployCallbacks) GetArchiveInfo(charmURL *corecharm.URL) (charm.BundleInfo, error) {
 	return cb.MockGetArchiveInfo.Call(charmURL)
 }
@@ -124,12 +138,21 @@ type RunActionCallbacks struct {
 	operation.Callbacks
 	*MockFailAction
 	executingMessage string
+	cancelled        chan struct{}
+	released         bool
 }
 
 func (cb *RunActionCallbacks) FailAction(actionId, message string) error {
 	return cb.MockFailAction.Call(actionId, message)
 }
 
+func (cb *RunActionCallbacks) ActionCancelled(actionId string) (<-chan struct{}, func()) {
+	if cb.cancelled == nil {
+		cb.cancelled = make(chan struct{})
+	}
+	return cb.cancelled, func() { cb.released = true }
+}
+
 func (cb *RunActionCallbacks) SetExecutingStatus(message string) error {
 	cb.executingMessage = message
 	return nil
@@ -288,6 +311,7 @@ type MockContext struct {
 	runner.Context
 	testing.Stub
 	actionData      *context.ActionData
+	actionCancelled chan struct{}
 	setStatusCalled bool
 	status          jujuc.StatusInfo
 }
@@ -321,6 +345,14 @@ func (mock *MockContext) UnitStatus() (*jujuc.StatusInfo, error) {
 	return &mock.status, nil
 }
 
+func (mock *MockContext) CancelAction() error {
+	mock.MethodCall(mock, "CancelAction")
+	if mock.actionCancelled != nil {
+		close(mock.actionCancelled)
+	}
+	return mock.NextErr()
+}
+
 func (mock *MockContext) Prepare() error {
 	mock.MethodCall(mock, "Prepare")
 	return mock.NextErr()
@@ -328,11 +360,15 @@ func (mock *MockContext) Prepare() error {
 
 type MockRunAction struct {
 	gotName *string
+	run     func()
 	err     error
 }
 
 func (mock *MockRunAction) Call(actionName string) error {
 	mock.gotName = &actionName
+	if mock.run != nil {
+		mock.run()
+	}
 	return mock.err
 }
 
@@ -384,8 +420,9 @@ func (r *MockRunner) RunHook(hookName string) error {
 
 func NewDeployCallbacks() *DeployCallbacks {
 	return &DeployCallbacks{
-		MockGetArchiveInfo:  &MockGetArchiveInfo{info: &MockBundleInfo{}},
-		MockSetCurrentCharm: &MockSetCurrentCharm{},
+		MockGetCharmModifiedVersion: &MockGetCharmModifiedVersion{},
+		MockGetArchiveInfo:          &MockGetArchiveInfo{info: &MockBundleInfo{}},
+		MockSetCurrentCharm:         &MockSetCurrentCharm{},
 	}
 }
 
@@ -51,6 +51,7 @@ func (s *PathsSuite) TestWindows(c *gc.C) {
 			DeployerDir:     relAgent("state", "deployer"),
 			StorageDir:      relAgent("state", "storage"),
 			MetricsSpoolDir: relAgent("state", "spool", "metrics"),
+			ResourcesDir:    relAgent("resources"),
 		},
 	})
 }
@@ -79,6 +80,7 @@ func (s *PathsSuite) TestWorkerPathsWindows(c *gc.C) {
 			DeployerDir:     relAgent("state", "deployer"),
 			StorageDir:      relAgent("state", "storage"),
 			MetricsSpoolDir: relAgent("state", "spool", "metrics"),
+			ResourcesDir:    relAgent("resources"),
 		},
 	})
 }
@@ -106,6 +108,7 @@ func (s *PathsSuite) TestOther(c *gc.C) {
 			DeployerDir:     relAgent("state", "deployer"),
 			StorageDir:      relAgent("state", "storage"),
 			MetricsSpoolDir: relAgent("state", "spool", "metrics"),
+			ResourcesDir:    relAgent("resources"),
 		},
 	})
 }
@@ -134,6 +137,7 @@ func (s *PathsSuite) TestWorkerPaths(c *gc.C) {
 			DeployerDir:     relAgent("state", "deployer"),
 			StorageDir:      relAgent("state", "storage"),
 			MetricsSpoolDir: relAgent("state", "spool", "metrics"),
+			ResourcesDir:    relAgent("resources"),
 		},
 	})
 }
@@ -147,10 +151,12 @@ func (s *PathsSuite) TestContextInterface(c *gc.C) {
 		State: uniter.StatePaths{
 			CharmDir:        "/path/to/charm",
 			MetricsSpoolDir: "/path/to/spool/metrics",
+			ResourcesDir:    "/path/to/resources",
 		},
 	}
 	c.Assert(paths.GetToolsDir(), gc.Equals, "/path/to/tools")
 	c.Assert(paths.GetCharmDir(), gc.Equals, "/path/to/charm")
 	c.Assert(paths.GetJujucSocket(), gc.Equals, "/path/to/socket")
 	c.Assert(paths.GetMetricsSpoolDir(), gc.Equals, "/path/to/spool/metrics")
+	c.Assert(paths.GetResourcesDir(), gc.Equals, "/path/to/resources")
 }
@@ -88,12 +88,21 @@ func (w *mockStorageAttachmentWatcher) Changes() <-chan struct{} {
 
 type mockState struct {
 	unit                      mockUnit
+	actionStatus              map[string]string
 	relations                 map[names.RelationTag]*mockRelation
 	storageAttachment         map[params.StorageAttachmentId]params.StorageAttachment
 	relationUnitsWatchers     map[names.RelationTag]*mockRelationUnitsWatcher
 	storageAttachmentWatchers map[names.StorageTag]*mockStorageAttachmentWatcher
 }
 
+func (st *mockState) ActionStatus(tag names.ActionTag) (string, error) {
+	status, ok := st.actionStatus[tag.Id()]
+	if !ok {
+		return "", &params.Error{Code: params.CodeNotFound}
+	}
+	return status, nil
+}
+
 func (st *mockState) Relation(tag names.RelationTag) (remotestate.Relation, error) {
 	r, ok := st.relations[tag]
 	if !ok {
@@ -181,6 +190,9 @@ type mockUnit struct {
 	configSettingsWatcher mockNotifyWatcher
 	storageWatcher        mockStringsWatcher
 	actionWatcher         mockStringsWatcher
+	upgradeSeriesWatcher  mockNotifyWatcher
+	upgradeSeriesWatchErr error
+	upgradeSeriesStatus   params.UpgradeSeriesStatus
 }
 
 func (u *mockUnit) Life() params.Life {
@@ -223,11 +235,23 @@ func (u *mockUnit) WatchActionNotifications() (watcher.StringsWatcher, error) {
 	return &u.actionWatcher, nil
 }
 
+func (u *mockUnit) WatchUpgradeSeriesNotifications() (watcher.NotifyWatcher, error) {
+	if u.upgradeSeriesWatchErr != nil {
+		return nil, u.upgradeSeriesWatchErr
+	}
+	return &u.upgradeSeriesWatcher, nil
+}
+
+func (u *mockUnit) UpgradeSeriesStatus() (params.UpgradeSeriesStatus, string, error) {
+	return u.upgradeSeriesStatus, "", nil
+}
+
 type mockService struct {
 	tag                   names.ServiceTag
 	life                  params.Life
 	curl                  *charm.URL
 	forceUpgrade          bool
+	charmModifiedVersion  int
 	serviceWatcher        mockNotifyWatcher
 	leaderSettingsWatcher mockNotifyWatcher
 	relationsWatcher      mockStringsWatcher
@@ -237,6 +261,10 @@ func (s *mockService) CharmURL() (*charm.URL, bool, error) {
 	return s.curl, s.forceUpgrade, nil
 }
 
+func (s *mockService) CharmModifiedVersion() (int, error) {
+	return s.charmModifiedVersion, nil
+}
+
 func (s *mockService) Life() params.Life {
 	return s.life
 }
@@ -32,6 +32,12 @@ type Snapshot struct {
 	// should upgrade even in an error state.
 	ForceCharmUpgrade bool
 
+	// CharmModifiedVersion is incremented whenever the
+	// service's charm is modified in a way that requires
+	// the unit to run the upgrade-charm hook, such as
+	// when a resource is attached.
+	CharmModifiedVersion int
+
 	// ResolvedMode reports the method of resolving
 	// hook execution errors.
 	ResolvedMode params.ResolvedMode
@@ -55,6 +61,10 @@ type Snapshot struct {
 	// Actions is the list of pending actions to
 	// be peformed by this unit.
 	Actions []string
+
+	// UpgradeSeriesStatus is the progress of the unit
+	// through the series upgrade of its machine.
+	UpgradeSeriesStatus params.UpgradeSeriesStatus
 }
 
 type RelationSnapshot struct {
@@ -13,6 +13,7 @@ import (
 )
 
 type State interface {
+	ActionStatus(names.ActionTag) (string, error)
 	Relation(names.RelationTag) (Relation, error)
 	StorageAttachment(names.StorageTag, names.UnitTag) (params.StorageAttachment, error)
 	StorageAttachmentLife([]params.StorageAttachmentId) ([]params.LifeResult, error)
@@ -32,10 +33,13 @@ type Unit interface {
 	WatchConfigSettings() (watcher.NotifyWatcher, error)
 	WatchStorage() (watcher.StringsWatcher, error)
 	WatchActionNotifications() (watcher.StringsWatcher, error)
+	WatchUpgradeSeriesNotifications() (watcher.NotifyWatcher, error)
+	UpgradeSeriesStatus() (params.UpgradeSeriesStatus, string, error)
 }
 
 type Service interface {
 	CharmURL() (*charm.URL, bool, error)
+	CharmModifiedVersion() (int, error)
 	Life() params.Life
 	Refresh() error
 	Tag() names.ServiceTag
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
+	"github.com/juju/utils/set"
 	"launchpad.net/tomb"
 
 	apiwatcher "github.com/juju/juju/api/watcher"
@@ -35,6 +36,11 @@ type RemoteStateWatcher struct {
 	leadershipTracker         leadership.Tracker
 	updateStatusChannel       func() <-chan time.Time
 
+	// cancelledActions holds a channel for each action whose
+	// cancellation is being watched for; the channel is closed
+	// when the action is cancelled.
+	cancelledActions map[string]chan struct{}
+
 	tomb tomb.Tomb
 
 	out     chan struct{}
@@ -62,6 +68,7 @@ func NewWatcher(config WatcherConfig) (*RemoteStateWatcher, error) {
 		storageAttachmentChanges:  make(chan storageAttachmentChange),
 		leadershipTracker:         config.LeadershipTracker,
 		updateStatusChannel:       config.UpdateStatusChannel,
+		cancelledActions:          make(map[string]chan struct{}),
 		// Note: it is important that the out channel be buffered!
 		// The remote state watcher will perform a non-blocking send
 		// on the channel to wake up the observer. It is non-blocking
@@ -132,6 +139,24 @@ func (w *RemoteStateWatcher) Snapshot() Snapshot {
 	return snapshot
 }
 
+// ActionCancelled returns a channel which is closed when the action
+// with the specified id is cancelled while running, and a function
+// which must be called once the caller is no longer interested.
+func (w *RemoteStateWatcher) ActionCancelled(id string) (<-chan struct{}, func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	cancelled := make(chan struct{})
+	w.cancelledActions[id] = cancelled
+	release := func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.cancelledActions[id] == cancelled {
+			delete(w.cancelledActions, id)
+		}
+	}
+	return cancelled, release
+}
+
 func (w *RemoteStateWatcher) ClearResolvedMode() {
 	w.mu.Lock()
 	w.current.ResolvedMode = params.ResolvedNone
@@ -223,6 +248,21 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 	defer watcher.Stop(actionsw, &w.tomb)
 	requiredEvents++
 
+	var seenUpgradeSeriesChange bool
+	var upgradeSeriesChanges <-chan struct{}
+	upgradeSeriesw, err := w.unit.WatchUpgradeSeriesNotifications()
+	if errors.IsNotImplemented(err) {
+		// Older API servers do not support series upgrades, so
+		// the unit never takes part in one.
+		logger.Debugf("not watching series upgrades: %v", err)
+	} else if err != nil {
+		return err
+	} else {
+		defer watcher.Stop(upgradeSeriesw, &w.tomb)
+		upgradeSeriesChanges = upgradeSeriesw.Changes()
+		requiredEvents++
+	}
+
 	var seenLeadershipChange bool
 	// There's no watcher for this per se; we wait on a channel
 	// returned by the leadership tracker.
@@ -337,6 +377,16 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 			}
 			observedEvent(&seenActionsChange)
 
+		case _, ok := <-upgradeSeriesChanges:
+			logger.Debugf("got upgrade series change: ok=%t", ok)
+			if !ok {
+				return watcher.EnsureErr(upgradeSeriesw)
+			}
+			if err := w.upgradeSeriesChanged(); err != nil {
+				return err
+			}
+			observedEvent(&seenUpgradeSeriesChange)
+
 		case keys, ok := <-relationsw.Changes():
 			logger.Debugf("got relations change: ok=%t", ok)
 			if !ok {
@@ -430,9 +480,18 @@ func (w *RemoteStateWatcher) serviceChanged() error {
 	if err != nil {
 		return err
 	}
+	charmModifiedVersion, err := w.service.CharmModifiedVersion()
+	if errors.IsNotImplemented(err) {
+		// Older API servers do not support resources, so the
+		// charm is never modified in place.
+		charmModifiedVersion = 0
+	} else if err != nil {
+		return err
+	}
 	w.mu.Lock()
 	w.current.CharmURL = url
 	w.current.ForceCharmUpgrade = force
+	w.current.CharmModifiedVersion = charmModifiedVersion
 	w.mu.Unlock()
 	return nil
 }
@@ -451,6 +510,23 @@ func (w *RemoteStateWatcher) addressesChanged() error {
 	return nil
 }
 
+// upgradeSeriesChanged responds to changes in the series upgrade of
+// the unit's machine.
+func (w *RemoteStateWatcher) upgradeSeriesChanged() error {
+	status, _, err := w.unit.UpgradeSeriesStatus()
+	if params.IsCodeNotFound(err) {
+		// The unit was assigned to the machine after the
+		// series upgrade started, so it takes no part.
+		status = params.UpgradeSeriesNotStarted
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	w.mu.Lock()
+	w.current.UpgradeSeriesStatus = status
+	w.mu.Unlock()
+	return nil
+}
+
 func (w *RemoteStateWatcher) leaderSettingsChanged() error {
 	w.mu.Lock()
 	w.current.LeaderSettingsVersion++
@@ -560,7 +636,29 @@ func (w *RemoteStateWatcher) storageAttachmentChanged(change storageAttachmentCh
 func (w *RemoteStateWatcher) actionsChanged(actions []string) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	w.current.Actions = append(w.current.Actions, actions...)
+	known := make(set.Strings)
+	for _, id := range w.current.Actions {
+		known.Add(id)
+	}
+	for _, id := range actions {
+		if cancelled, ok := w.cancelledActions[id]; ok {
+			// The action is running; it is notified again
+			// when it is cancelled.
+			status, err := w.st.ActionStatus(names.NewActionTag(id))
+			if errors.IsNotImplemented(err) {
+				logger.Warningf("cannot check whether action %s was cancelled: %v", id, err)
+			} else if err != nil {
+				return errors.Annotatef(err, "getting status of action %s", id)
+			} else if status == params.ActionCancelling {
+				close(cancelled)
+				delete(w.cancelledActions, id)
+			}
+		}
+		if !known.Contains(id) {
+			known.Add(id)
+			w.current.Actions = append(w.current.Actions, id)
+		}
+	}
 	return nil
 }
 
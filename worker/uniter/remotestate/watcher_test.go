@@ -6,6 +6,7 @@ package remotestate_test
 import (
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -55,6 +56,7 @@ func (s *WatcherSuite) SetUpTest(c *gc.C) {
 			configSettingsWatcher: mockNotifyWatcher{changes: make(chan struct{}, 1)},
 			storageWatcher:        mockStringsWatcher{changes: make(chan []string, 1)},
 			actionWatcher:         mockStringsWatcher{changes: make(chan []string, 1)},
+			upgradeSeriesWatcher:  mockNotifyWatcher{changes: make(chan struct{}, 1)},
 		},
 		relations:                 make(map[names.RelationTag]*mockRelation),
 		storageAttachment:         make(map[params.StorageAttachmentId]params.StorageAttachment),
@@ -106,6 +108,7 @@ func (s *WatcherSuite) TestInitialSignal(c *gc.C) {
 	s.st.unit.configSettingsWatcher.changes <- struct{}{}
 	s.st.unit.storageWatcher.changes <- []string{}
 	s.st.unit.actionWatcher.changes <- []string{}
+	s.st.unit.upgradeSeriesWatcher.changes <- struct{}{}
 	s.st.unit.service.serviceWatcher.changes <- struct{}{}
 	s.st.unit.service.leaderSettingsWatcher.changes <- struct{}{}
 	s.st.unit.service.relationsWatcher.changes <- []string{}
@@ -113,12 +116,40 @@ func (s *WatcherSuite) TestInitialSignal(c *gc.C) {
 	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
 }
 
+func (s *WatcherSuite) TestInitialSignalUpgradeSeriesNotImplemented(c *gc.C) {
+	err := s.watcher.Stop()
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.unit.upgradeSeriesWatchErr = errors.NotImplementedf("WatchUpgradeSeriesNotifications")
+	s.watcher, err = remotestate.NewWatcher(remotestate.WatcherConfig{
+		State:               &s.st,
+		LeadershipTracker:   &s.leadership,
+		UnitTag:             s.st.unit.tag,
+		UpdateStatusChannel: func() <-chan time.Time { return s.clock.After(statusTickDuration) },
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// No upgrade series notification is needed before the
+	// remote state changes.
+	s.st.unit.unitWatcher.changes <- struct{}{}
+	s.st.unit.addressesWatcher.changes <- struct{}{}
+	s.st.unit.configSettingsWatcher.changes <- struct{}{}
+	s.st.unit.storageWatcher.changes <- []string{}
+	s.st.unit.actionWatcher.changes <- []string{}
+	s.st.unit.service.serviceWatcher.changes <- struct{}{}
+	s.st.unit.service.leaderSettingsWatcher.changes <- struct{}{}
+	s.st.unit.service.relationsWatcher.changes <- []string{}
+	s.leadership.claimTicket.ch <- struct{}{}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().UpgradeSeriesStatus, gc.Equals, params.UpgradeSeriesStatus(""))
+}
+
 func signalAll(st *mockState, l *mockLeadershipTracker) {
 	st.unit.unitWatcher.changes <- struct{}{}
 	st.unit.addressesWatcher.changes <- struct{}{}
 	st.unit.configSettingsWatcher.changes <- struct{}{}
 	st.unit.storageWatcher.changes <- []string{}
 	st.unit.actionWatcher.changes <- []string{}
+	st.unit.upgradeSeriesWatcher.changes <- struct{}{}
 	st.unit.service.serviceWatcher.changes <- struct{}{}
 	st.unit.service.leaderSettingsWatcher.changes <- struct{}{}
 	st.unit.service.relationsWatcher.changes <- []string{}
@@ -174,6 +205,11 @@ func (s *WatcherSuite) TestRemoteStateChanged(c *gc.C) {
 	assertOneChange()
 	c.Assert(s.watcher.Snapshot().ForceCharmUpgrade, jc.IsTrue)
 
+	s.st.unit.service.charmModifiedVersion = 1
+	s.st.unit.service.serviceWatcher.changes <- struct{}{}
+	assertOneChange()
+	c.Assert(s.watcher.Snapshot().CharmModifiedVersion, gc.Equals, 1)
+
 	s.st.unit.service.leaderSettingsWatcher.changes <- struct{}{}
 	assertOneChange()
 	c.Assert(s.watcher.Snapshot().LeaderSettingsVersion, gc.Equals, initial.LeaderSettingsVersion+1)
@@ -181,6 +217,11 @@ func (s *WatcherSuite) TestRemoteStateChanged(c *gc.C) {
 	s.st.unit.service.relationsWatcher.changes <- []string{}
 	assertOneChange()
 
+	s.st.unit.upgradeSeriesStatus = params.UpgradeSeriesPrepareStarted
+	s.st.unit.upgradeSeriesWatcher.changes <- struct{}{}
+	assertOneChange()
+	c.Assert(s.watcher.Snapshot().UpgradeSeriesStatus, gc.Equals, params.UpgradeSeriesPrepareStarted)
+
 	s.clock.Advance(statusTickDuration + 1)
 	assertOneChange()
 }
@@ -192,6 +233,34 @@ func (s *WatcherSuite) TestActionsReceived(c *gc.C) {
 	s.st.unit.actionWatcher.changes <- []string{"an-action"}
 	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
 	c.Assert(s.watcher.Snapshot().Actions, gc.DeepEquals, []string{"an-action"})
+
+	// Repeated notifications do not duplicate the action.
+	s.st.unit.actionWatcher.changes <- []string{"an-action"}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().Actions, gc.DeepEquals, []string{"an-action"})
+}
+
+func (s *WatcherSuite) TestActionCancelled(c *gc.C) {
+	id := "feedface-0123-4567-8901-2345deadbeef"
+	s.st.actionStatus = map[string]string{id: params.ActionRunning}
+	signalAll(&s.st, &s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+
+	cancelled, release := s.watcher.ActionCancelled(id)
+	defer release()
+
+	s.st.unit.actionWatcher.changes <- []string{id}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	select {
+	case <-cancelled:
+		c.Fatalf("unexpected cancellation")
+	default:
+	}
+
+	s.st.actionStatus[id] = params.ActionCancelling
+	s.st.unit.actionWatcher.changes <- []string{id}
+	assertNotifyEvent(c, cancelled, "waiting for action cancellation")
+	c.Assert(s.watcher.Snapshot().Actions, gc.DeepEquals, []string{id})
 }
 
 func (s *WatcherSuite) TestClearResolvedMode(c *gc.C) {
@@ -225,6 +225,27 @@ func (s *uniterResolver) nextOp(
 		return opFactory.NewUpgrade(remoteState.CharmURL)
 	}
 
+	// The charm is upgraded in place when it is modified without its
+	// URL changing, such as when a resource is attached.
+	if localState.CharmModifiedVersion != remoteState.CharmModifiedVersion {
+		logger.Debugf("upgrade from charm modified version %d to %d",
+			localState.CharmModifiedVersion, remoteState.CharmModifiedVersion)
+		return opFactory.NewUpgrade(remoteState.CharmURL)
+	}
+
+	// The series upgrade hooks run once for each stage of the
+	// machine's series upgrade.
+	switch remoteState.UpgradeSeriesStatus {
+	case params.UpgradeSeriesPrepareStarted:
+		if localState.UpgradeSeriesStatus != params.UpgradeSeriesPrepareCompleted {
+			return opFactory.NewRunHook(hook.Info{Kind: hook.PreSeriesUpgrade})
+		}
+	case params.UpgradeSeriesCompleteStarted:
+		if localState.UpgradeSeriesStatus != params.UpgradeSeriesCompleted {
+			return opFactory.NewRunHook(hook.Info{Kind: hook.PostSeriesUpgrade})
+		}
+	}
+
 	if localState.ConfigVersion != remoteState.ConfigVersion {
 		return opFactory.NewRunHook(hook.Info{Kind: hooks.ConfigChanged})
 	}
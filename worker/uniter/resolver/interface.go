@@ -7,6 +7,7 @@ import (
 	"github.com/juju/errors"
 	"gopkg.in/juju/charm.v6-unstable"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/remotestate"
 )
@@ -58,6 +59,10 @@ type LocalState struct {
 	// by the committing of deploy (install/upgrade) ops.
 	CharmURL *charm.URL
 
+	// CharmModifiedVersion is the version of the charm from
+	// remotestate.Snapshot for which an upgrade op has been committed.
+	CharmModifiedVersion int
+
 	// Conflicted indicates that the uniter is in a conflicted state,
 	// and needs either resolution or a forced upgrade to continue.
 	Conflicted bool
@@ -83,4 +88,9 @@ type LocalState struct {
 	// This is used to prevent us re running actions requested by the
 	// state server.
 	CompletedActions map[string]struct{}
+
+	// UpgradeSeriesStatus is the progress of the unit through the
+	// series upgrade of its machine, as recorded by the committing of
+	// pre-series-upgrade and post-series-upgrade hooks.
+	UpgradeSeriesStatus params.UpgradeSeriesStatus
 }
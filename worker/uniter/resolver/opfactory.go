@@ -9,6 +9,7 @@ import (
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/remotestate"
@@ -99,8 +100,10 @@ func trimCompletedActions(pendingActions []string, completedActions map[string]s
 }
 
 func (s *resolverOpFactory) wrapUpgradeOp(op operation.Operation, charmURL *charm.URL) operation.Operation {
+	charmModifiedVersion := s.RemoteState.CharmModifiedVersion
 	return onCommitWrapper{op, func() {
 		s.LocalState.CharmURL = charmURL
+		s.LocalState.CharmModifiedVersion = charmModifiedVersion
 		s.LocalState.Restart = true
 		s.LocalState.Conflicted = false
 	}}
@@ -118,6 +121,14 @@ func (s *resolverOpFactory) wrapHookOp(op operation.Operation, info hook.Info) o
 		op = onCommitWrapper{op, func() {
 			s.LocalState.LeaderSettingsVersion = v
 		}}
+	case hook.PreSeriesUpgrade:
+		op = onCommitWrapper{op, func() {
+			s.LocalState.UpgradeSeriesStatus = params.UpgradeSeriesPrepareCompleted
+		}}
+	case hook.PostSeriesUpgrade:
+		op = onCommitWrapper{op, func() {
+			s.LocalState.UpgradeSeriesStatus = params.UpgradeSeriesCompleted
+		}}
 	}
 	// No matter what has finished running, we reset the UpdateStatusVersion so that
 	// the update-status hook only fires after the next timer.
@@ -11,6 +11,7 @@ import (
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
@@ -126,6 +127,24 @@ func (s *ResolverOpFactorySuite) testLeaderSettingsChanged(
 	c.Assert(f.LocalState.UpdateStatusVersion, gc.Equals, 3)
 }
 
+func (s *ResolverOpFactorySuite) TestUpgradeSeriesHooks(c *gc.C) {
+	s.testUpgradeSeriesHook(c, hook.PreSeriesUpgrade, params.UpgradeSeriesPrepareCompleted)
+	s.testUpgradeSeriesHook(c, hook.PostSeriesUpgrade, params.UpgradeSeriesCompleted)
+}
+
+func (s *ResolverOpFactorySuite) testUpgradeSeriesHook(
+	c *gc.C, kind hooks.Kind, expect params.UpgradeSeriesStatus,
+) {
+	f := resolver.NewResolverOpFactory(s.opFactory)
+	op, err := f.NewRunHook(hook.Info{Kind: kind})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(f.LocalState.UpgradeSeriesStatus, gc.Equals, params.UpgradeSeriesNotStarted)
+
+	_, err = op.Commit(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(f.LocalState.UpgradeSeriesStatus, gc.Equals, expect)
+}
+
 func (s *ResolverOpFactorySuite) TestUpgrade(c *gc.C) {
 	s.testUpgrade(c, resolver.ResolverOpFactory.NewUpgrade)
 	s.testUpgrade(c, resolver.ResolverOpFactory.NewRevertUpgrade)
@@ -137,13 +156,16 @@ func (s *ResolverOpFactorySuite) testUpgrade(
 ) {
 	f := resolver.NewResolverOpFactory(s.opFactory)
 	f.LocalState.Conflicted = true
+	f.RemoteState.CharmModifiedVersion = 3
 	curl := charm.MustParseURL("cs:trusty/mysql")
 	op, err := meth(f, curl)
 	c.Assert(err, jc.ErrorIsNil)
+	f.RemoteState.CharmModifiedVersion = 4
 	_, err = op.Commit(operation.State{})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(f.LocalState.CharmURL, jc.DeepEquals, curl)
 	c.Assert(f.LocalState.Conflicted, jc.IsFalse)
+	c.Assert(f.LocalState.CharmModifiedVersion, gc.Equals, 3)
 }
 
 func (s *ResolverOpFactorySuite) TestNewUpgradeError(c *gc.C) {
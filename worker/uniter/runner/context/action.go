@@ -4,6 +4,8 @@
 package context
 
 import (
+	"time"
+
 	"github.com/juju/names"
 )
 
@@ -12,7 +14,10 @@ type ActionData struct {
 	Name           string
 	Tag            names.ActionTag
 	Params         map[string]interface{}
+	Timeout        time.Duration
 	Failed         bool
+	Cancelled      bool
+	TimedOut       bool
 	ResultsMessage string
 	ResultsMap     map[string]interface{}
 }
@@ -43,6 +43,10 @@ type Paths interface {
 	// GetMetricsSpoolDir returns the path to a metrics spool dir, used
 	// to store metrics recorded during a single hook run.
 	GetMetricsSpoolDir() string
+
+	// GetResourcesDir returns the path to the directory holding the
+	// files of the resources fetched by the charm.
+	GetResourcesDir() string
 }
 
 var logger = loggo.GetLogger("juju.worker.uniter.context")
@@ -142,6 +146,10 @@ type HookContext struct {
 	// like a juju-run command or a hook
 	process *os.Process
 
+	// actionTimer stops the running action's process when its timeout
+	// expires.
+	actionTimer *time.Timer
+
 	// rebootPriority tells us when the hook wants to reboot. If rebootPriority is jujuc.RebootNow
 	// the hook will be killed and requeued
 	rebootPriority jujuc.RebootPriority
@@ -152,6 +160,9 @@ type HookContext struct {
 	// storageId is the tag of the storage instance associated with the running hook.
 	storageTag names.StorageTag
 
+	// resourcesDir is the directory into which resources are downloaded.
+	resourcesDir string
+
 	// hasRunSetStatus is true if a call to the status-set was made during the
 	// invocation of a hook.
 	// This attribute is persisted to local uniter state at the end of the hook
@@ -201,8 +212,48 @@ func (ctx *HookContext) GetProcess() *os.Process {
 
 func (ctx *HookContext) SetProcess(process *os.Process) {
 	mutex.Lock()
-	defer mutex.Unlock()
 	ctx.process = process
+	cancelled := ctx.actionData != nil && ctx.actionData.Cancelled
+	if process != nil && ctx.actionTimer == nil && ctx.actionData != nil && ctx.actionData.Timeout > 0 {
+		ctx.actionTimer = time.AfterFunc(ctx.actionData.Timeout, ctx.timeoutAction)
+	}
+	mutex.Unlock()
+	if cancelled && process != nil {
+		// The action was cancelled before its process was started.
+		if err := process.Kill(); err != nil {
+			logger.Infof("kill returned: %s", err)
+		}
+	}
+}
+
+// timeoutAction stops the running action's process, and ensures that
+// the action is recorded as having timed out.
+func (ctx *HookContext) timeoutAction() {
+	mutex.Lock()
+	ctx.actionData.TimedOut = true
+	mutex.Unlock()
+	logger.Infof("action %q timed out after %v", ctx.actionData.Name, ctx.actionData.Timeout)
+	if err := ctx.killCharmHook(); err != nil && err != ErrNoProcess {
+		logger.Errorf("cannot stop timed out action %q: %v", ctx.actionData.Name, err)
+	}
+}
+
+// CancelAction implements the Context interface. It stops the running
+// action's process, and ensures that the action is recorded as
+// cancelled.
+func (ctx *HookContext) CancelAction() error {
+	if ctx.actionData == nil {
+		return errors.New("not running an action")
+	}
+	mutex.Lock()
+	ctx.actionData.Cancelled = true
+	mutex.Unlock()
+	err := ctx.killCharmHook()
+	if err == ErrNoProcess {
+		// The process will be killed as soon as it is started.
+		return nil
+	}
+	return err
 }
 
 func (ctx *HookContext) Id() string {
@@ -329,6 +380,12 @@ func (ctx *HookContext) PrivateAddress() (string, error) {
 	return ctx.privateAddress, nil
 }
 
+// NetworkInfo returns the network interfaces and addresses of the unit's
+// machine in the spaces bound to each of the named endpoints.
+func (ctx *HookContext) NetworkInfo(bindingNames []string) (map[string]params.NetworkInfoResult, error) {
+	return ctx.unit.NetworkInfo(bindingNames)
+}
+
 func (ctx *HookContext) AvailabilityZone() (string, error) {
 	if ctx.availabilityzone == "" {
 		return "", errors.NotFoundf("availability zone")
@@ -406,6 +463,35 @@ func (ctx *HookContext) ConfigSettings() (charm.Settings, error) {
 	return result, nil
 }
 
+// SetWorkloadVersion records the version of the workload run by the
+// unit, as reported by its charm.
+func (ctx *HookContext) SetWorkloadVersion(version string) error {
+	return ctx.unit.SetWorkloadVersion(version)
+}
+
+// RegisterPayload records that the unit is running the payload of the
+// given type, class and id.
+func (ctx *HookContext) RegisterPayload(ptype, class, id string, labels []string) error {
+	return ctx.unit.RegisterPayload(params.Payload{
+		Class:  class,
+		Type:   ptype,
+		ID:     id,
+		Labels: labels,
+	})
+}
+
+// UnregisterPayload records that the unit is no longer running the
+// payload with the given class and id.
+func (ctx *HookContext) UnregisterPayload(class, id string) error {
+	return ctx.unit.UnregisterPayload(class, id)
+}
+
+// SetPayloadStatus sets the status of the unit's payload with the given
+// class and id.
+func (ctx *HookContext) SetPayloadStatus(class, id, status string) error {
+	return ctx.unit.SetPayloadStatus(class, id, status)
+}
+
 // ActionName returns the name of the action.
 func (ctx *HookContext) ActionName() (string, error) {
 	if ctx.actionData == nil {
@@ -667,6 +753,13 @@ func (ctx *HookContext) finalizeAction(err, unhandledErr error) error {
 	if ctx.actionData.Failed {
 		status = params.ActionFailed
 	}
+	mutex.Lock()
+	if ctx.actionTimer != nil {
+		ctx.actionTimer.Stop()
+	}
+	cancelled := ctx.actionData.Cancelled
+	timedOut := ctx.actionData.TimedOut
+	mutex.Unlock()
 
 	// If we had an action error, we'll simply encapsulate it in the response
 	// and discard the error state.  Actions should not error the uniter.
@@ -678,6 +771,17 @@ func (ctx *HookContext) finalizeAction(err, unhandledErr error) error {
 		status = params.ActionFailed
 	}
 
+	// A cancelled action's process is killed, so any error is
+	// expected; record the cancellation along with whatever results
+	// the action had set.
+	if cancelled {
+		status = params.ActionCancelled
+		message = "action cancelled"
+	} else if timedOut {
+		status = params.ActionFailed
+		message = fmt.Sprintf("action timed out after %v", ctx.actionData.Timeout)
+	}
+
 	callErr := ctx.state.ActionFinish(tag, status, results, message)
 	if callErr != nil {
 		unhandledErr = errors.Wrap(unhandledErr, callErr)
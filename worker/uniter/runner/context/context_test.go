@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
@@ -330,6 +331,67 @@ func (s *InterfaceSuite) TestRequestRebootNowNoProcess(c *gc.C) {
 	c.Assert(priority, gc.Equals, jujuc.RebootNow)
 }
 
+func (s *InterfaceSuite) TestCancelActionNotAction(c *gc.C) {
+	ctx := context.HookContext{}
+	err := ctx.CancelAction()
+	c.Assert(err, gc.ErrorMatches, "not running an action")
+}
+
+func (s *InterfaceSuite) TestCancelAction(c *gc.C) {
+	hctx := context.GetStubActionContext(nil)
+	p := s.startProcess(c)
+	hctx.SetProcess(p)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.Wait()
+	}()
+	err := hctx.CancelAction()
+	c.Assert(err, jc.ErrorIsNil)
+	actionData, err := hctx.ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(actionData.Cancelled, jc.IsTrue)
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for process to be killed")
+	}
+}
+
+func (s *InterfaceSuite) TestCancelActionBeforeProcessStarted(c *gc.C) {
+	hctx := context.GetStubActionContext(nil)
+	err := hctx.CancelAction()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The process is killed as soon as it is recorded.
+	p := s.startProcess(c)
+	hctx.SetProcess(p)
+	_, err = p.Wait()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *InterfaceSuite) TestActionTimeout(c *gc.C) {
+	hctx := context.GetStubActionContext(nil)
+	actionData, err := hctx.ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	actionData.Timeout = coretesting.ShortWait
+
+	// The process is killed once the timeout expires.
+	p := s.startProcess(c)
+	hctx.SetProcess(p)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for process to be killed")
+	}
+	c.Check(actionData.TimedOut, jc.IsTrue)
+}
+
 func (s *InterfaceSuite) TestStorageAddConstraints(c *gc.C) {
 	expected := map[string][]params.StorageConstraints{
 		"data": []params.StorageConstraints{
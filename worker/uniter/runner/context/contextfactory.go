@@ -144,6 +144,7 @@ func (f *contextFactory) coreContext() (*HookContext, error) {
 		relationId:         -1,
 		pendingPorts:       make(map[PortRange]PortRangeInfo),
 		storage:            f.storage,
+		resourcesDir:       f.paths.GetResourcesDir(),
 	}
 	if err := f.updateContext(ctx); err != nil {
 		return nil, err
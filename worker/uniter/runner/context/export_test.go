@@ -53,6 +53,7 @@ func NewHookContext(
 		actionData:         actionData,
 		pendingPorts:       make(map[PortRange]PortRangeInfo),
 		assignedMachineTag: assignedMachineTag,
+		resourcesDir:       paths.GetResourcesDir(),
 	}
 	// Get and cache the addresses.
 	var err error
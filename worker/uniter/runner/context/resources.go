@@ -0,0 +1,60 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/resource"
+)
+
+// DownloadResource ensures the current revision of the named resource
+// is on disk, fetching it from the controller if the local copy is
+// missing or out of date, and returns the path to the file.
+func (ctx *HookContext) DownloadResource(name string) (string, error) {
+	res, err := ctx.unit.Resource(name)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	dir := filepath.Join(ctx.resourcesDir, res.Name)
+	path := filepath.Join(dir, res.Path)
+	if fingerprint, err := resource.FileFingerprint(path); err == nil && fingerprint == res.Fingerprint {
+		return path, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Annotate(err, "cannot create resource directory")
+	}
+	reader, err := ctx.unit.DownloadResource(name)
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot download resource %q", name)
+	}
+	defer reader.Close()
+
+	// Download into the same directory so the file can be renamed into
+	// place once it has been verified.
+	tempFile, err := ioutil.TempFile(dir, "download")
+	if err != nil {
+		return "", errors.Annotate(err, "cannot create temp file")
+	}
+	defer os.Remove(tempFile.Name())
+	fingerprint, _, err := resource.NewFingerprint(io.TeeReader(reader, tempFile))
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot download resource %q", name)
+	}
+	if fingerprint != res.Fingerprint {
+		return "", errors.Errorf("resource %q fingerprint mismatch: expected %s, got %s", name, res.Fingerprint, fingerprint)
+	}
+	if err := os.Rename(tempFile.Name(), path); err != nil {
+		return "", errors.Annotatef(err, "cannot save resource %q", name)
+	}
+	return path, nil
+}
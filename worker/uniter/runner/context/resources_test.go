@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context_test
+
+import (
+	"io/ioutil"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/resource"
+	"github.com/juju/juju/state"
+	statestorage "github.com/juju/juju/state/storage"
+)
+
+type ResourcesSuite struct {
+	HookContextSuite
+}
+
+var _ = gc.Suite(&ResourcesSuite{})
+
+func (s *ResourcesSuite) attach(c *gc.C, data string) {
+	fingerprint, size, err := resource.NewFingerprint(strings.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	storagePath := "resources/u/software-" + fingerprint[:8]
+	stor := statestorage.NewStorage(s.State.EnvironUUID(), s.State.MongoSession())
+	err = stor.Put(storagePath, strings.NewReader(data), size)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.service.SetResource(state.Resource{
+		Name:        "software",
+		Path:        "software.tgz",
+		Fingerprint: fingerprint,
+		Size:        size,
+		StoragePath: storagePath,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ResourcesSuite) assertDownload(c *gc.C, expect string) {
+	ctx := s.GetContext(c, -1, "")
+	path, err := ctx.DownloadResource("software")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(path, jc.HasSuffix, "/software/software.tgz")
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, expect)
+}
+
+func (s *ResourcesSuite) TestDownloadResource(c *gc.C) {
+	s.attach(c, "hello")
+	s.assertDownload(c, "hello")
+}
+
+func (s *ResourcesSuite) TestDownloadResourceNewRevision(c *gc.C) {
+	s.attach(c, "hello")
+	s.assertDownload(c, "hello")
+	s.attach(c, "goodbye")
+	s.assertDownload(c, "goodbye")
+}
+
+func (s *ResourcesSuite) TestDownloadResourceNotAttached(c *gc.C) {
+	ctx := s.GetContext(c, -1, "")
+	_, err := ctx.DownloadResource("software")
+	c.Assert(err, gc.ErrorMatches, `resource "software" of service "u" not found`)
+}
@@ -385,3 +385,7 @@ func (MockEnvPaths) GetJujucSocket() string {
 func (MockEnvPaths) GetMetricsSpoolDir() string {
 	return "path-to-metrics-spool-dir"
 }
+
+func (MockEnvPaths) GetResourcesDir() string {
+	return "path-to-resources-dir"
+}
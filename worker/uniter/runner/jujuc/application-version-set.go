@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// ApplicationVersionSetCommand implements the application-version-set
+// command.
+type ApplicationVersionSetCommand struct {
+	cmd.CommandBase
+	ctx     Context
+	version string
+}
+
+// NewApplicationVersionSetCommand makes a jujuc application-version-set
+// command.
+func NewApplicationVersionSetCommand(ctx Context) (cmd.Command, error) {
+	return &ApplicationVersionSetCommand{ctx: ctx}, nil
+}
+
+func (c *ApplicationVersionSetCommand) Info() *cmd.Info {
+	doc := `
+Sets the version of the workload run by the unit, as shown by juju
+status. The version of the service's leader unit is shown for the
+service as a whole. An empty version clears the unit's version.
+`
+	return &cmd.Info{
+		Name:    "application-version-set",
+		Args:    "<new-version>",
+		Purpose: "specify which version of the application is deployed",
+		Doc:     doc,
+	}
+}
+
+func (c *ApplicationVersionSetCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no version specified")
+	}
+	c.version = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *ApplicationVersionSetCommand) Run(ctx *cmd.Context) error {
+	return errors.Trace(c.ctx.SetWorkloadVersion(c.version))
+}
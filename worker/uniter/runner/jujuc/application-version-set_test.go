@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type ApplicationVersionSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&ApplicationVersionSetSuite{})
+
+func (s *ApplicationVersionSetSuite) createCommand(c *gc.C) (*Context, cmd.Command) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("application-version-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	return hctx, com
+}
+
+func (s *ApplicationVersionSetSuite) TestInit(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"4.2"}, ""},
+		{[]string{""}, ""},
+		{[]string{}, "no version specified"},
+		{[]string{"4.2", "extra"}, `unrecognized args: \["extra"\]`},
+	} {
+		c.Logf("test %d: %#v", i, t.args)
+		_, com := s.createCommand(c)
+		testing.TestInit(c, com, t.args, t.err)
+	}
+}
+
+func (s *ApplicationVersionSetSuite) TestSetVersion(c *gc.C) {
+	hctx, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"4.2"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(hctx.info.WorkloadVersion, gc.Equals, "4.2")
+	s.Stub.CheckCall(c, 0, "SetWorkloadVersion", "4.2")
+}
+
+func (s *ApplicationVersionSetSuite) TestSetVersionError(c *gc.C) {
+	s.Stub.SetErrors(errors.New("boom"))
+	hctx, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"4.2"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: boom\n")
+	c.Check(hctx.info.WorkloadVersion, gc.Equals, "")
+}
+
+func (s *ApplicationVersionSetSuite) TestHelp(c *gc.C) {
+	_, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--help"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, `usage: application-version-set <new-version>
+purpose: specify which version of the application is deployed
+
+Sets the version of the workload run by the unit, as shown by juju
+status. The version of the service's leader unit is shown for the
+service as a whole. An empty version clears the unit's version.
+`)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+}
@@ -50,6 +50,8 @@ type HookContext interface {
 	ContextLeadership
 	ContextMetrics
 	ContextStorage
+	ContextPayloads
+	ContextResources
 	ContextRelations
 }
 
@@ -104,6 +106,10 @@ type ContextUnit interface {
 
 	// Config returns the current service configuration of the executing unit.
 	ConfigSettings() (charm.Settings, error)
+
+	// SetWorkloadVersion records the version of the workload run by
+	// the executing unit.
+	SetWorkloadVersion(version string) error
 }
 
 // ContextStatus is the part of a hook context related to the unit's status.
@@ -156,6 +162,11 @@ type ContextNetworking interface {
 	// unit on its assigned machine. The result is sorted first by
 	// protocol, then by number.
 	OpenedPorts() []network.PortRange
+
+	// NetworkInfo returns the network interfaces and addresses of the
+	// unit's machine in the spaces bound to each of the named endpoints,
+	// by endpoint name.
+	NetworkInfo(bindingNames []string) (map[string]params.NetworkInfoResult, error)
 }
 
 // ContextLeadership is the part of a hook context related to the
@@ -202,6 +213,30 @@ type ContextStorage interface {
 	AddUnitStorage(map[string]params.StorageConstraints) error
 }
 
+// ContextPayloads is the part of a hook context related to payloads,
+// such as containers or long-running processes, run by the unit.
+type ContextPayloads interface {
+	// RegisterPayload records that the unit is running the payload of
+	// the given type, class and id, with the given labels.
+	RegisterPayload(ptype, class, id string, labels []string) error
+
+	// UnregisterPayload records that the unit is no longer running the
+	// payload with the given class and id.
+	UnregisterPayload(class, id string) error
+
+	// SetPayloadStatus sets the status of the unit's payload with the
+	// given class and id.
+	SetPayloadStatus(class, id, status string) error
+}
+
+// ContextResources is the part of a hook context related to the
+// resources attached to the unit's service.
+type ContextResources interface {
+	// DownloadResource ensures that the current revision of the named
+	// resource is on the unit, and returns the path of its file.
+	DownloadResource(name string) (string, error)
+}
+
 // ContextRelations exposes the relations associated with the unit.
 type ContextRelations interface {
 	// Relation returns the relation with the supplied id if it was found, and
@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// NetworkGetCommand implements the network-get command.
+type NetworkGetCommand struct {
+	cmd.CommandBase
+	ctx Context
+
+	bindingName    string
+	primaryAddress bool
+
+	out cmd.Output
+}
+
+func NewNetworkGetCommand(ctx Context) (cmd.Command, error) {
+	return &NetworkGetCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *NetworkGetCommand) Info() *cmd.Info {
+	doc := `
+network-get returns the network interfaces of the unit's machine that have
+addresses in the space the given relation endpoint is bound to, along with
+the addresses and the CIDRs of their subnets. If the endpoint is not bound
+to a space, the unit's private address is returned.
+
+If the --primary-address flag is passed, only the address the unit gives
+relations on the endpoint is printed.
+`
+	return &cmd.Info{
+		Name:    "network-get",
+		Args:    "<binding> [--primary-address]",
+		Purpose: "get network config",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *NetworkGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.BoolVar(&c.primaryAddress, "primary-address", false, "get the primary address for the binding")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *NetworkGetCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no arguments specified")
+	}
+	c.bindingName = args[0]
+	if c.bindingName == "" {
+		return errors.Errorf("no binding name specified")
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// networkInfo is the output format for a network interface.
+type networkInfo struct {
+	MACAddress    string             `yaml:"macaddress,omitempty" json:"macaddress,omitempty"`
+	InterfaceName string             `yaml:"interfacename,omitempty" json:"interfacename,omitempty"`
+	Addresses     []interfaceAddress `yaml:"addresses" json:"addresses"`
+}
+
+// interfaceAddress is the output format for a network interface address.
+type interfaceAddress struct {
+	Address string `yaml:"address" json:"address"`
+	CIDR    string `yaml:"cidr,omitempty" json:"cidr,omitempty"`
+}
+
+// Run is part of the cmd.Command interface.
+func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
+	results, err := c.ctx.NetworkInfo([]string{c.bindingName})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result, ok := results[c.bindingName]
+	if !ok {
+		return errors.NotFoundf("network info for %q", c.bindingName)
+	}
+	if result.Error != nil {
+		return errors.Trace(result.Error)
+	}
+
+	if c.primaryAddress {
+		address, err := primaryAddress(result.Info)
+		if err != nil {
+			return errors.Annotatef(err, "binding %q", c.bindingName)
+		}
+		return c.out.Write(ctx, address)
+	}
+	out := make([]networkInfo, len(result.Info))
+	for i, info := range result.Info {
+		out[i] = networkInfo{
+			MACAddress:    info.MACAddress,
+			InterfaceName: info.InterfaceName,
+			Addresses:     make([]interfaceAddress, len(info.Addresses)),
+		}
+		for j, addr := range info.Addresses {
+			out[i].Addresses[j] = interfaceAddress{
+				Address: addr.Address,
+				CIDR:    addr.CIDR,
+			}
+		}
+	}
+	return c.out.Write(ctx, out)
+}
+
+// primaryAddress returns the first address of the first interface.
+func primaryAddress(infos []params.NetworkInfo) (string, error) {
+	for _, info := range infos {
+		if len(info.Addresses) > 0 {
+			return info.Addresses[0].Address, nil
+		}
+	}
+	return "", errors.New("no addresses")
+}
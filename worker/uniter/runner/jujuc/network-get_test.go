@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type NetworkGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&NetworkGetSuite{})
+
+func (s *NetworkGetSuite) createCommand(c *gc.C) cmd.Command {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.NetworkInfoResults = map[string]params.NetworkInfoResult{
+		"db": {
+			Info: []params.NetworkInfo{{
+				MACAddress:    "aa:bb:cc:dd:ee:ff",
+				InterfaceName: "eth1",
+				Addresses: []params.InterfaceAddress{
+					{Address: "10.0.0.5", CIDR: "10.0.0.0/24"},
+					{Address: "10.0.0.6", CIDR: "10.0.0.0/24"},
+				},
+			}},
+		},
+		"url": {
+			Info: []params.NetworkInfo{{
+				Addresses: []params.InterfaceAddress{{Address: "192.168.1.5"}},
+			}},
+		},
+		"cache": {
+			Info: []params.NetworkInfo{},
+		},
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("network-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+func (s *NetworkGetSuite) TestNetworkGet(c *gc.C) {
+	for i, t := range []struct {
+		summary string
+		args    []string
+		code    int
+		out     string
+		err     string
+	}{{
+		summary: "no arguments",
+		code:    2,
+		err:     "error: no arguments specified\n",
+	}, {
+		summary: "too many arguments",
+		args:    []string{"db", "foo"},
+		code:    2,
+		err:     `error: unrecognized args: ["foo"]` + "\n",
+	}, {
+		summary: "bound endpoint",
+		args:    []string{"db"},
+		out: `
+- macaddress: aa:bb:cc:dd:ee:ff
+  interfacename: eth1
+  addresses:
+  - address: 10.0.0.5
+    cidr: 10.0.0.0/24
+  - address: 10.0.0.6
+    cidr: 10.0.0.0/24
+`[1:],
+	}, {
+		summary: "bound endpoint as json",
+		args:    []string{"db", "--format", "json"},
+		out: `[{"macaddress":"aa:bb:cc:dd:ee:ff","interfacename":"eth1",` +
+			`"addresses":[{"address":"10.0.0.5","cidr":"10.0.0.0/24"},` +
+			`{"address":"10.0.0.6","cidr":"10.0.0.0/24"}]}]` + "\n",
+	}, {
+		summary: "unbound endpoint",
+		args:    []string{"url"},
+		out: `
+- addresses:
+  - address: 192.168.1.5
+`[1:],
+	}, {
+		summary: "primary address",
+		args:    []string{"db", "--primary-address"},
+		out:     "10.0.0.5\n",
+	}, {
+		summary: "primary address with no addresses",
+		args:    []string{"cache", "--primary-address"},
+		code:    1,
+		err:     `error: binding "cache": no addresses` + "\n",
+	}, {
+		summary: "unknown endpoint",
+		args:    []string{"foo"},
+		code:    1,
+		err:     `error: endpoint "foo" not found` + "\n",
+	}} {
+		c.Logf("test %d: %s", i, t.summary)
+		com := s.createCommand(c)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, t.code)
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, t.err)
+	}
+}
+
+func (s *NetworkGetSuite) TestHelp(c *gc.C) {
+	com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--help"})
+	c.Assert(code, gc.Equals, 0)
+	expectedHelp := "" +
+		"usage: network-get [options] <binding> [--primary-address]\n" +
+		"purpose: get network config\n" +
+		"\n" +
+		"options:\n" +
+		"--format  (= smart)\n" +
+		"    specify output format (json|smart|yaml)\n" +
+		"-o, --output (= \"\")\n" +
+		"    specify an output file\n" +
+		"--primary-address  (= false)\n" +
+		"    get the primary address for the binding\n" +
+		"\n" +
+		"network-get returns the network interfaces of the unit's machine that have\n" +
+		"addresses in the space the given relation endpoint is bound to, along with\n" +
+		"the addresses and the CIDRs of their subnets. If the endpoint is not bound\n" +
+		"to a space, the unit's private address is returned.\n" +
+		"\n" +
+		"If the --primary-address flag is passed, only the address the unit gives\n" +
+		"relations on the endpoint is printed.\n"
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, expectedHelp)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+}
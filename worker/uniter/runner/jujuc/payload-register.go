@@ -0,0 +1,57 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// PayloadRegisterCommand implements the payload-register command.
+type PayloadRegisterCommand struct {
+	cmd.CommandBase
+	ctx Context
+
+	typ    string
+	class  string
+	id     string
+	labels []string
+}
+
+// NewPayloadRegisterCommand makes a jujuc payload-register command.
+func NewPayloadRegisterCommand(ctx Context) (cmd.Command, error) {
+	return &PayloadRegisterCommand{ctx: ctx}, nil
+}
+
+func (c *PayloadRegisterCommand) Info() *cmd.Info {
+	doc := `
+Registers a payload, such as a container or a long-running process, that
+the unit is running, so that it is shown by juju list-payloads.
+
+The type is the technology used to run the payload, e.g. "docker". The
+class is the name of the kind of payload, and the id identifies the
+payload within its class. Any further arguments are attached to the
+payload as labels. Registering a payload with the class and id of an
+existing payload replaces it.
+`
+	return &cmd.Info{
+		Name:    "payload-register",
+		Args:    "<type> <class> <id> [label...]",
+		Purpose: "register a charm payload with juju",
+		Doc:     doc,
+	}
+}
+
+func (c *PayloadRegisterCommand) Init(args []string) error {
+	if len(args) < 3 {
+		return errors.New("payload type, class and id required")
+	}
+	c.typ, c.class, c.id = args[0], args[1], args[2]
+	c.labels = args[3:]
+	return nil
+}
+
+func (c *PayloadRegisterCommand) Run(ctx *cmd.Context) error {
+	return errors.Trace(c.ctx.RegisterPayload(c.typ, c.class, c.id, c.labels))
+}
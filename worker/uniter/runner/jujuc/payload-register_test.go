@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type PayloadRegisterSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&PayloadRegisterSuite{})
+
+func (s *PayloadRegisterSuite) createCommand(c *gc.C) (*Context, cmd.Command) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("payload-register"))
+	c.Assert(err, jc.ErrorIsNil)
+	return hctx, com
+}
+
+func (s *PayloadRegisterSuite) TestInit(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"docker", "webapp", "abc123"}, ""},
+		{[]string{"docker", "webapp", "abc123", "frontend", "v2"}, ""},
+		{[]string{"docker", "webapp"}, "payload type, class and id required"},
+		{[]string{}, "payload type, class and id required"},
+	} {
+		c.Logf("test %d: %#v", i, t.args)
+		_, com := s.createCommand(c)
+		testing.TestInit(c, com, t.args, t.err)
+	}
+}
+
+func (s *PayloadRegisterSuite) TestRegister(c *gc.C) {
+	hctx, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"docker", "webapp", "abc123", "frontend"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(hctx.info.Payloads.Payloads, jc.DeepEquals, map[string]params.Payload{
+		"webapp/abc123": {
+			Class:  "webapp",
+			Type:   "docker",
+			ID:     "abc123",
+			Status: "running",
+			Labels: []string{"frontend"},
+		},
+	})
+	s.Stub.CheckCall(c, 0, "RegisterPayload", "docker", "webapp", "abc123", []string{"frontend"})
+}
+
+func (s *PayloadRegisterSuite) TestRegisterError(c *gc.C) {
+	s.Stub.SetErrors(errors.New("boom"))
+	hctx, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"docker", "webapp", "abc123"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: boom\n")
+	c.Check(hctx.info.Payloads.Payloads, gc.HasLen, 0)
+}
+
+func (s *PayloadRegisterSuite) TestHelp(c *gc.C) {
+	_, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--help"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, `usage: payload-register <type> <class> <id> [label...]
+purpose: register a charm payload with juju
+
+Registers a payload, such as a container or a long-running process, that
+the unit is running, so that it is shown by juju list-payloads.
+
+The type is the technology used to run the payload, e.g. "docker". The
+class is the name of the kind of payload, and the id identifies the
+payload within its class. Any further arguments are attached to the
+payload as labels. Registering a payload with the class and id of an
+existing payload replaces it.
+`)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+}
@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// validPayloadStatuses holds the statuses accepted by payload-status-set.
+var validPayloadStatuses = []string{"starting", "running", "stopping", "stopped"}
+
+// PayloadStatusSetCommand implements the payload-status-set command.
+type PayloadStatusSetCommand struct {
+	cmd.CommandBase
+	ctx Context
+
+	class  string
+	id     string
+	status string
+}
+
+// NewPayloadStatusSetCommand makes a jujuc payload-status-set command.
+func NewPayloadStatusSetCommand(ctx Context) (cmd.Command, error) {
+	return &PayloadStatusSetCommand{ctx: ctx}, nil
+}
+
+func (c *PayloadStatusSetCommand) Info() *cmd.Info {
+	doc := `
+Updates the status of a payload previously registered with
+payload-register. The status must be one of starting, running, stopping
+or stopped.
+`
+	return &cmd.Info{
+		Name:    "payload-status-set",
+		Args:    "<class> <id> <status>",
+		Purpose: "update the status of a payload",
+		Doc:     doc,
+	}
+}
+
+func (c *PayloadStatusSetCommand) Init(args []string) error {
+	if len(args) < 3 {
+		return errors.New("payload class, id and status required")
+	}
+	c.class, c.id, c.status = args[0], args[1], args[2]
+	valid := false
+	for _, status := range validPayloadStatuses {
+		if c.status == status {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return errors.Errorf("invalid status %q, expected one of %v", c.status, validPayloadStatuses)
+	}
+	return cmd.CheckEmpty(args[3:])
+}
+
+func (c *PayloadStatusSetCommand) Run(ctx *cmd.Context) error {
+	return errors.Trace(c.ctx.SetPayloadStatus(c.class, c.id, c.status))
+}
@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type PayloadStatusSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&PayloadStatusSetSuite{})
+
+func (s *PayloadStatusSetSuite) createCommand(c *gc.C) (*Context, cmd.Command) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.Payloads.Payloads = map[string]params.Payload{
+		"webapp/abc123": {Class: "webapp", Type: "docker", ID: "abc123", Status: "running"},
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("payload-status-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	return hctx, com
+}
+
+func (s *PayloadStatusSetSuite) TestInit(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"webapp", "abc123", "stopping"}, ""},
+		{[]string{"webapp", "abc123"}, "payload class, id and status required"},
+		{[]string{"webapp", "abc123", "dancing"}, `invalid status "dancing", expected one of \[starting running stopping stopped\]`},
+		{[]string{"webapp", "abc123", "stopped", "extra"}, `unrecognized args: \["extra"\]`},
+	} {
+		c.Logf("test %d: %#v", i, t.args)
+		_, com := s.createCommand(c)
+		testing.TestInit(c, com, t.args, t.err)
+	}
+}
+
+func (s *PayloadStatusSetSuite) TestSetStatus(c *gc.C) {
+	hctx, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"webapp", "abc123", "stopping"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(hctx.info.Payloads.Payloads["webapp/abc123"].Status, gc.Equals, "stopping")
+	s.Stub.CheckCall(c, 0, "SetPayloadStatus", "webapp", "abc123", "stopping")
+}
+
+func (s *PayloadStatusSetSuite) TestHelp(c *gc.C) {
+	_, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--help"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, `usage: payload-status-set <class> <id> <status>
+purpose: update the status of a payload
+
+Updates the status of a payload previously registered with
+payload-register. The status must be one of starting, running, stopping
+or stopped.
+`)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+}
@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// PayloadUnregisterCommand implements the payload-unregister command.
+type PayloadUnregisterCommand struct {
+	cmd.CommandBase
+	ctx Context
+
+	class string
+	id    string
+}
+
+// NewPayloadUnregisterCommand makes a jujuc payload-unregister command.
+func NewPayloadUnregisterCommand(ctx Context) (cmd.Command, error) {
+	return &PayloadUnregisterCommand{ctx: ctx}, nil
+}
+
+func (c *PayloadUnregisterCommand) Info() *cmd.Info {
+	doc := `
+Unregisters a payload previously registered with payload-register, for
+example when the container or process it describes has been removed.
+`
+	return &cmd.Info{
+		Name:    "payload-unregister",
+		Args:    "<class> <id>",
+		Purpose: "stop tracking a payload",
+		Doc:     doc,
+	}
+}
+
+func (c *PayloadUnregisterCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.New("payload class and id required")
+	}
+	c.class, c.id = args[0], args[1]
+	return cmd.CheckEmpty(args[2:])
+}
+
+func (c *PayloadUnregisterCommand) Run(ctx *cmd.Context) error {
+	return errors.Trace(c.ctx.UnregisterPayload(c.class, c.id))
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type PayloadUnregisterSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&PayloadUnregisterSuite{})
+
+func (s *PayloadUnregisterSuite) createCommand(c *gc.C) (*Context, cmd.Command) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.Payloads.Payloads = map[string]params.Payload{
+		"webapp/abc123": {Class: "webapp", Type: "docker", ID: "abc123", Status: "running"},
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("payload-unregister"))
+	c.Assert(err, jc.ErrorIsNil)
+	return hctx, com
+}
+
+func (s *PayloadUnregisterSuite) TestInit(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"webapp", "abc123"}, ""},
+		{[]string{"webapp"}, "payload class and id required"},
+		{[]string{"webapp", "abc123", "extra"}, `unrecognized args: \["extra"\]`},
+	} {
+		c.Logf("test %d: %#v", i, t.args)
+		_, com := s.createCommand(c)
+		testing.TestInit(c, com, t.args, t.err)
+	}
+}
+
+func (s *PayloadUnregisterSuite) TestUnregister(c *gc.C) {
+	hctx, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"webapp", "abc123"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(hctx.info.Payloads.Payloads, gc.HasLen, 0)
+	s.Stub.CheckCall(c, 0, "UnregisterPayload", "webapp", "abc123")
+}
+
+func (s *PayloadUnregisterSuite) TestUnregisterNotFound(c *gc.C) {
+	_, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"webapp", "def456"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: payload webapp/def456 not found\n")
+}
+
+func (s *PayloadUnregisterSuite) TestHelp(c *gc.C) {
+	_, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--help"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, `usage: payload-unregister <class> <id>
+purpose: stop tracking a payload
+
+Unregisters a payload previously registered with payload-register, for
+example when the container or process it describes has been removed.
+`)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+}
@@ -0,0 +1,57 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// ResourceGetCommand implements the resource-get command.
+type ResourceGetCommand struct {
+	cmd.CommandBase
+	ctx Context
+
+	name string
+}
+
+// NewResourceGetCommand makes a jujuc resource-get command.
+func NewResourceGetCommand(ctx Context) (cmd.Command, error) {
+	return &ResourceGetCommand{ctx: ctx}, nil
+}
+
+func (c *ResourceGetCommand) Info() *cmd.Info {
+	doc := `
+Downloads the current revision of a resource attached to the service with
+"juju attach", if it is not already on the unit, and prints the path of
+its file. The download is checked against the resource's fingerprint.
+
+The resource must be declared in the charm's metadata.
+`
+	return &cmd.Info{
+		Name:    "resource-get",
+		Args:    "<name>",
+		Purpose: "get the path of a resource file",
+		Doc:     doc,
+	}
+}
+
+func (c *ResourceGetCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("resource name required")
+	}
+	c.name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *ResourceGetCommand) Run(ctx *cmd.Context) error {
+	path, err := c.ctx.DownloadResource(c.name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintln(ctx.Stdout, path)
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type ResourceGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&ResourceGetSuite{})
+
+func (s *ResourceGetSuite) createCommand(c *gc.C) (*Context, cmd.Command) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.Resources.Paths = map[string]string{
+		"software": "/var/lib/juju/agents/unit-u-0/resources/software/software.tgz",
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("resource-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	return hctx, com
+}
+
+func (s *ResourceGetSuite) TestInit(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"software"}, ""},
+		{nil, "resource name required"},
+		{[]string{"software", "extra"}, `unrecognized args: \["extra"\]`},
+	} {
+		c.Logf("test %d: %#v", i, t.args)
+		_, com := s.createCommand(c)
+		testing.TestInit(c, com, t.args, t.err)
+	}
+}
+
+func (s *ResourceGetSuite) TestGet(c *gc.C) {
+	_, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"software"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "/var/lib/juju/agents/unit-u-0/resources/software/software.tgz\n")
+	s.Stub.CheckCall(c, 0, "DownloadResource", "software")
+}
+
+func (s *ResourceGetSuite) TestGetNotFound(c *gc.C) {
+	_, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"theme"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: resource \"theme\" not found\n")
+}
+
+func (s *ResourceGetSuite) TestHelp(c *gc.C) {
+	_, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--help"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, `usage: resource-get <name>
+purpose: get the path of a resource file
+
+Downloads the current revision of a resource attached to the service with
+"juju attach", if it is not already on the unit, and prints the path of
+its file. The download is checked against the resource's fingerprint.
+
+The resource must be declared in the charm's metadata.
+`)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+}
@@ -25,6 +25,9 @@ type RestrictedContext struct{}
 // ConfigSettings implements jujuc.Context.
 func (*RestrictedContext) ConfigSettings() (charm.Settings, error) { return nil, ErrRestrictedContext }
 
+// SetWorkloadVersion implements jujuc.Context.
+func (*RestrictedContext) SetWorkloadVersion(string) error { return ErrRestrictedContext }
+
 // UnitStatus implements jujuc.Context.
 func (*RestrictedContext) UnitStatus() (*StatusInfo, error) { return nil, ErrRestrictedContext }
 
@@ -64,6 +67,11 @@ func (*RestrictedContext) ClosePorts(protocol string, fromPort, toPort int) erro
 // OpenedPorts implements jujuc.Context.
 func (*RestrictedContext) OpenedPorts() []network.PortRange { return nil }
 
+// NetworkInfo implements jujuc.Context.
+func (*RestrictedContext) NetworkInfo(bindingNames []string) (map[string]params.NetworkInfoResult, error) {
+	return nil, ErrRestrictedContext
+}
+
 // IsLeader implements jujuc.Context.
 func (*RestrictedContext) IsLeader() (bool, error) { return false, ErrRestrictedContext }
 
@@ -96,6 +104,22 @@ func (*RestrictedContext) AddUnitStorage(map[string]params.StorageConstraints) e
 	return ErrRestrictedContext
 }
 
+// RegisterPayload implements jujuc.Context.
+func (*RestrictedContext) RegisterPayload(string, string, string, []string) error {
+	return ErrRestrictedContext
+}
+
+// UnregisterPayload implements jujuc.Context.
+func (*RestrictedContext) UnregisterPayload(string, string) error { return ErrRestrictedContext }
+
+// SetPayloadStatus implements jujuc.Context.
+func (*RestrictedContext) SetPayloadStatus(string, string, string) error {
+	return ErrRestrictedContext
+}
+
+// DownloadResource implements jujuc.Context.
+func (*RestrictedContext) DownloadResource(string) (string, error) { return "", ErrRestrictedContext }
+
 // Relation implements jujuc.Context.
 func (*RestrictedContext) Relation(id int) (ContextRelation, error) {
 	return nil, ErrRestrictedContext
@@ -34,23 +34,29 @@ type creator func(Context) (cmd.Command, error)
 
 // baseCommands maps Command names to creators.
 var baseCommands = map[string]creator{
-	"close-port" + cmdSuffix:    NewClosePortCommand,
-	"config-get" + cmdSuffix:    NewConfigGetCommand,
-	"juju-log" + cmdSuffix:      NewJujuLogCommand,
-	"open-port" + cmdSuffix:     NewOpenPortCommand,
-	"opened-ports" + cmdSuffix:  NewOpenedPortsCommand,
-	"relation-get" + cmdSuffix:  NewRelationGetCommand,
-	"action-get" + cmdSuffix:    NewActionGetCommand,
-	"action-set" + cmdSuffix:    NewActionSetCommand,
-	"action-fail" + cmdSuffix:   NewActionFailCommand,
-	"relation-ids" + cmdSuffix:  NewRelationIdsCommand,
-	"relation-list" + cmdSuffix: NewRelationListCommand,
-	"relation-set" + cmdSuffix:  NewRelationSetCommand,
-	"unit-get" + cmdSuffix:      NewUnitGetCommand,
-	"add-metric" + cmdSuffix:    NewAddMetricCommand,
-	"juju-reboot" + cmdSuffix:   NewJujuRebootCommand,
-	"status-get" + cmdSuffix:    NewStatusGetCommand,
-	"status-set" + cmdSuffix:    NewStatusSetCommand,
+	"close-port" + cmdSuffix:              NewClosePortCommand,
+	"config-get" + cmdSuffix:              NewConfigGetCommand,
+	"juju-log" + cmdSuffix:                NewJujuLogCommand,
+	"open-port" + cmdSuffix:               NewOpenPortCommand,
+	"opened-ports" + cmdSuffix:            NewOpenedPortsCommand,
+	"relation-get" + cmdSuffix:            NewRelationGetCommand,
+	"action-get" + cmdSuffix:              NewActionGetCommand,
+	"action-set" + cmdSuffix:              NewActionSetCommand,
+	"action-fail" + cmdSuffix:             NewActionFailCommand,
+	"relation-ids" + cmdSuffix:            NewRelationIdsCommand,
+	"relation-list" + cmdSuffix:           NewRelationListCommand,
+	"relation-set" + cmdSuffix:            NewRelationSetCommand,
+	"unit-get" + cmdSuffix:                NewUnitGetCommand,
+	"network-get" + cmdSuffix:             NewNetworkGetCommand,
+	"add-metric" + cmdSuffix:              NewAddMetricCommand,
+	"juju-reboot" + cmdSuffix:             NewJujuRebootCommand,
+	"status-get" + cmdSuffix:              NewStatusGetCommand,
+	"status-set" + cmdSuffix:              NewStatusSetCommand,
+	"application-version-set" + cmdSuffix: NewApplicationVersionSetCommand,
+	"payload-register" + cmdSuffix:        NewPayloadRegisterCommand,
+	"payload-unregister" + cmdSuffix:      NewPayloadUnregisterCommand,
+	"payload-status-set" + cmdSuffix:      NewPayloadStatusSetCommand,
+	"resource-get" + cmdSuffix:            NewResourceGetCommand,
 }
 
 var storageCommands = map[string]creator{
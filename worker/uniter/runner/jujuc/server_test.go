@@ -261,10 +261,16 @@ var newCommandTests = []struct {
 	{"relation-list", ""},
 	{"relation-set", ""},
 	{"unit-get", ""},
+	{"network-get", ""},
 	{"storage-add", ""},
 	{"storage-get", ""},
 	{"status-get", ""},
 	{"status-set", ""},
+	{"application-version-set", ""},
+	{"payload-register", ""},
+	{"payload-unregister", ""},
+	{"payload-status-set", ""},
+	{"resource-get", ""},
 	// The error message contains .exe on Windows
 	{"random", "unknown command: random(.exe)?"},
 }
@@ -18,6 +18,8 @@ type ContextInfo struct {
 	Leadership
 	Metrics
 	Storage
+	Payloads
+	Resources
 	Relations
 	RelationHook
 	ActionHook
@@ -56,6 +58,8 @@ type Context struct {
 	ContextLeader
 	ContextMetrics
 	ContextStorage
+	ContextPayloads
+	ContextResources
 	ContextRelations
 	ContextRelationHook
 	ContextActionHook
@@ -78,6 +82,10 @@ func NewContext(stub *testing.Stub, info *ContextInfo) *Context {
 	ctx.ContextMetrics.info = &info.Metrics
 	ctx.ContextStorage.stub = stub
 	ctx.ContextStorage.info = &info.Storage
+	ctx.ContextPayloads.stub = stub
+	ctx.ContextPayloads.info = &info.Payloads
+	ctx.ContextResources.stub = stub
+	ctx.ContextResources.info = &info.Resources
 	ctx.ContextRelations.stub = stub
 	ctx.ContextRelations.info = &info.Relations
 	ctx.ContextRelationHook.stub = stub
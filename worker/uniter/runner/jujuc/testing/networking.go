@@ -4,10 +4,13 @@
 package testing
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 )
 
@@ -16,6 +19,10 @@ type NetworkInterface struct {
 	PublicAddress  string
 	PrivateAddress string
 	Ports          []network.PortRange
+
+	// NetworkInfoResults holds the network information returned for
+	// each endpoint binding name.
+	NetworkInfoResults map[string]params.NetworkInfoResult
 }
 
 // CheckPorts checks the current ports.
@@ -100,3 +107,23 @@ func (c *ContextNetworking) OpenedPorts() []network.PortRange {
 
 	return c.info.Ports
 }
+
+// NetworkInfo implements jujuc.ContextNetworking.
+func (c *ContextNetworking) NetworkInfo(bindingNames []string) (map[string]params.NetworkInfoResult, error) {
+	c.stub.AddCall("NetworkInfo", bindingNames)
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	results := make(map[string]params.NetworkInfoResult, len(bindingNames))
+	for _, name := range bindingNames {
+		if result, ok := c.info.NetworkInfoResults[name]; ok {
+			results[name] = result
+		} else {
+			results[name] = params.NetworkInfoResult{
+				Error: &params.Error{Message: fmt.Sprintf("endpoint %q not found", name)},
+			}
+		}
+	}
+	return results, nil
+}
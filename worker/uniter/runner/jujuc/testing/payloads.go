@@ -0,0 +1,78 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Payloads holds the values for the hook sub-context.
+type Payloads struct {
+	// Payloads maps "<class>/<id>" to the registered payload.
+	Payloads map[string]params.Payload
+}
+
+func payloadKey(class, id string) string {
+	return class + "/" + id
+}
+
+// ContextPayloads is a test double for jujuc.ContextPayloads.
+type ContextPayloads struct {
+	contextBase
+	info *Payloads
+}
+
+// RegisterPayload implements jujuc.ContextPayloads.
+func (c *ContextPayloads) RegisterPayload(ptype, class, id string, labels []string) error {
+	c.stub.AddCall("RegisterPayload", ptype, class, id, labels)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if c.info.Payloads == nil {
+		c.info.Payloads = make(map[string]params.Payload)
+	}
+	c.info.Payloads[payloadKey(class, id)] = params.Payload{
+		Class:  class,
+		Type:   ptype,
+		ID:     id,
+		Status: "running",
+		Labels: labels,
+	}
+	return nil
+}
+
+// UnregisterPayload implements jujuc.ContextPayloads.
+func (c *ContextPayloads) UnregisterPayload(class, id string) error {
+	c.stub.AddCall("UnregisterPayload", class, id)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	key := payloadKey(class, id)
+	if _, ok := c.info.Payloads[key]; !ok {
+		return errors.NotFoundf("payload %s", key)
+	}
+	delete(c.info.Payloads, key)
+	return nil
+}
+
+// SetPayloadStatus implements jujuc.ContextPayloads.
+func (c *ContextPayloads) SetPayloadStatus(class, id, status string) error {
+	c.stub.AddCall("SetPayloadStatus", class, id, status)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	key := payloadKey(class, id)
+	payload, ok := c.info.Payloads[key]
+	if !ok {
+		return errors.NotFoundf("payload %s", key)
+	}
+	payload.Status = status
+	c.info.Payloads[key] = payload
+	return nil
+}
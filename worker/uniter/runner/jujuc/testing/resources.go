@@ -0,0 +1,35 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"github.com/juju/errors"
+)
+
+// Resources holds the values for the hook sub-context.
+type Resources struct {
+	// Paths maps resource names to the paths of their files on the
+	// unit.
+	Paths map[string]string
+}
+
+// ContextResources is a test double for jujuc.ContextResources.
+type ContextResources struct {
+	contextBase
+	info *Resources
+}
+
+// DownloadResource implements jujuc.ContextResources.
+func (c *ContextResources) DownloadResource(name string) (string, error) {
+	c.stub.AddCall("DownloadResource", name)
+	if err := c.stub.NextErr(); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	path, ok := c.info.Paths[name]
+	if !ok {
+		return "", errors.NotFoundf("resource %q", name)
+	}
+	return path, nil
+}
@@ -45,6 +45,7 @@ type Context interface {
 	HookVars(paths context.Paths) ([]string, error)
 	ActionData() (*context.ActionData, error)
 	SetProcess(process *os.Process)
+	CancelAction() error
 	HasExecutionSetUnitStatus() bool
 	ResetExecutionSetUnitStatus()
 
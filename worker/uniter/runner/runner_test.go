@@ -173,6 +173,10 @@ func (ctx *MockContext) SetProcess(process *os.Process) {
 	ctx.expectPid = process.Pid
 }
 
+func (ctx *MockContext) CancelAction() error {
+	return nil
+}
+
 func (ctx *MockContext) Prepare() error {
 	return nil
 }
@@ -23,6 +23,7 @@ type RealPaths struct {
 	charm        string
 	socket       string
 	metricsspool string
+	resources    string
 }
 
 func osDependentSockPath(c *gc.C) string {
@@ -39,6 +40,7 @@ func NewRealPaths(c *gc.C) RealPaths {
 		charm:        c.MkDir(),
 		socket:       osDependentSockPath(c),
 		metricsspool: c.MkDir(),
+		resources:    c.MkDir(),
 	}
 }
 
@@ -46,6 +48,10 @@ func (p RealPaths) GetMetricsSpoolDir() string {
 	return p.metricsspool
 }
 
+func (p RealPaths) GetResourcesDir() string {
+	return p.resources
+}
+
 func (p RealPaths) GetToolsDir() string {
 	return p.tools
 }
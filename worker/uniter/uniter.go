@@ -81,6 +81,10 @@ type Uniter struct {
 
 	ranConfigChanged bool
 
+	// actionCancelled is used to learn when a running action is
+	// cancelled. It is provided by the current remote state watcher.
+	actionCancelled func(actionId string) (<-chan struct{}, func())
+
 	// The execution observer is only used in tests at this stage. Should this
 	// need to be extended, perhaps a list of observers would be needed.
 	observer UniterExecutionObserver
@@ -173,6 +177,10 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 		}
 		charmURL = curl
 	}
+	// The charm modified version deployed by the last install or
+	// upgrade is recorded with the operation state, so modifications
+	// made while the uniter was not running still trigger an upgrade.
+	charmModifiedVersion := u.operationExecutor.State().CharmModifiedVersion
 
 	var (
 		watcher   *remotestate.RemoteStateWatcher
@@ -199,6 +207,7 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 		if err != nil {
 			return errors.Trace(err)
 		}
+		u.actionCancelled = watcher.ActionCancelled
 		// Stop the uniter if the watcher fails. The watcher may be
 		// stopped cleanly, so only kill the tomb if the error is
 		// non-nil.
@@ -266,7 +275,11 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 		case <-watcher.RemoteStateChanged():
 		}
 
-		localState := resolver.LocalState{CharmURL: charmURL}
+		localState := resolver.LocalState{
+			CharmURL:             charmURL,
+			CharmModifiedVersion: charmModifiedVersion,
+			UpgradeSeriesStatus:  u.operationExecutor.State().UpgradeSeriesStatus,
+		}
 		for err == nil {
 			err = resolver.Loop(resolver.LoopConfig{
 				Resolver:       uniterResolver,
@@ -292,6 +305,7 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 				err = u.terminate()
 			case resolver.ErrRestart:
 				charmURL = localState.CharmURL
+				charmModifiedVersion = localState.CharmModifiedVersion
 				// leave err assigned, causing loop to break
 			default:
 				// We need to set conflicted from here, because error
@@ -480,6 +494,19 @@ func (u *Uniter) getServiceCharmURL() (*corecharm.URL, error) {
 	return charmURL, err
 }
 
+func (u *Uniter) getServiceCharmModifiedVersion() (int, error) {
+	service, err := u.st.Service(u.unit.ServiceTag())
+	if err != nil {
+		return 0, err
+	}
+	version, err := service.CharmModifiedVersion()
+	if errors.IsNotImplemented(err) {
+		// Older API servers do not support resources.
+		return 0, nil
+	}
+	return version, err
+}
+
 func (u *Uniter) operationState() operation.State {
 	return u.operationExecutor.State()
 }